@@ -5,8 +5,11 @@ package admin
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/ava-labs/gecko/ids"
+
+	cjson "github.com/ava-labs/gecko/utils/json"
 )
 
 // GetChainAliasesArgs are the arguments for Admin.GetChainAliases API call
@@ -25,3 +28,45 @@ func (service *Admin) GetChainAliases(r *http.Request, args *GetChainAliasesArgs
 	reply.Aliases = service.chainManager.Aliases(ID)
 	return nil
 }
+
+// GetBootstrapProgressArgs are the arguments for calling GetBootstrapProgress
+type GetBootstrapProgressArgs struct {
+	Chain string `json:"chain"`
+}
+
+// GetBootstrapProgressReply are the results from calling GetBootstrapProgress
+type GetBootstrapProgressReply struct {
+	Bootstrapped bool `json:"bootstrapped"`
+
+	// The number of containers that were fetched, and how many of them were
+	// executed
+	Fetched  cjson.Uint64 `json:"fetched"`
+	Executed cjson.Uint64 `json:"executed"`
+
+	// The estimated number of seconds until the fetched containers are
+	// executed. Only known once they're being executed.
+	ETA      cjson.Uint64 `json:"eta"`
+	ETAKnown bool         `json:"etaKnown"`
+}
+
+// GetBootstrapProgress returns how far along bootstrapping a chain is
+func (service *Admin) GetBootstrapProgress(_ *http.Request, args *GetBootstrapProgressArgs, reply *GetBootstrapProgressReply) error {
+	service.log.Debug("Admin: GetBootstrapProgress called with Chain: %s", args.Chain)
+
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return err
+	}
+
+	status, err := service.chainManager.BootstrapProgress(chainID)
+	if err != nil {
+		return err
+	}
+
+	reply.Bootstrapped = status.Bootstrapped
+	reply.Fetched = cjson.Uint64(status.Fetched)
+	reply.Executed = cjson.Uint64(status.Executed)
+	reply.ETA = cjson.Uint64(status.ETA / time.Second)
+	reply.ETAKnown = status.ETAKnown
+	return nil
+}
@@ -0,0 +1,143 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package events
+
+import (
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// Size of the ws read buffer
+	readBufferSize = 1024
+
+	// Size of the ws write buffer
+	writeBufferSize = 1024
+
+	// Time allowed to write a message to the peer.
+	writeWait = 10 * time.Second
+
+	// Time allowed to read the next pong message from the peer.
+	pongWait = 60 * time.Second
+
+	// Send pings to peer with this period. Must be less than pongWait.
+	pingPeriod = (pongWait * 9) / 10
+
+	// Maximum message size allowed from peer. Subscriptions can list many
+	// addresses.
+	maxMessageSize = 64 * 1024 // bytes
+
+	// Maximum number of pending messages to send to a peer.
+	maxPendingMessages = 1024 // messages
+)
+
+// subscription is the message a peer sends to subscribe to, or unsubscribe
+// from, the events of one type from one dispatcher of one chain
+type subscription struct {
+	// ChainID is the ID or an alias of the chain
+	ChainID string `json:"chainID"`
+
+	// Dispatcher is either "decisions", the default, or "consensus"
+	Dispatcher string `json:"dispatcher"`
+
+	// EventType is "accepted", "rejected" or "issued"
+	EventType string `json:"eventType"`
+
+	// If Addresses isn't empty, only the decisions that create a UTXO owned by
+	// one of these addresses are sent. Addresses are in the format of the
+	// chain's API, which depends on its VM: X-6Y3kysjF9jnHnYkdS9yGAuoHyae2eNmeV
+	// on an AVM chain aliased X, but 6Y3kysjF9jnHnYkdS9yGAuoHyae2eNmeV on a
+	// spdagvm chain.
+	Addresses []string `json:"addresses"`
+
+	Unsubscribe bool `json:"unsubscribe"`
+}
+
+// subscriptionError is sent to a peer whose subscription failed
+type subscriptionError struct {
+	Subscription *subscription `json:"subscription"`
+	Error        string        `json:"error"`
+}
+
+// connection is a websocket connection that events are sent over
+type connection struct {
+	s *Server
+
+	// The websocket connection.
+	conn *websocket.Conn
+
+	// Buffered channel of outbound messages.
+	send chan interface{}
+
+	// The topics this connection is subscribed to. Protected by the server's
+	// lock.
+	topics map[topic]struct{}
+}
+
+// readPump reads the subscriptions of the peer.
+//
+// The server runs readPump in a per-connection goroutine, so there is at most
+// one reader on a connection.
+func (c *connection) readPump() {
+	defer func() {
+		c.s.removeConnection(c)
+		c.conn.Close()
+	}()
+
+	c.conn.SetReadLimit(maxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error { c.conn.SetReadDeadline(time.Now().Add(pongWait)); return nil })
+
+	for {
+		msg := &subscription{}
+		if err := c.conn.ReadJSON(msg); err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+				c.s.log.Debug("Unexpected close in websockets: %s", err)
+			}
+			break
+		}
+
+		var err error
+		if msg.Unsubscribe {
+			err = c.s.unsubscribe(c, msg)
+		} else {
+			err = c.s.subscribe(c, msg)
+		}
+		if err == nil {
+			continue
+		}
+		select {
+		case c.send <- &subscriptionError{Subscription: msg, Error: err.Error()}:
+		default:
+			c.s.log.Verbo("dropping subscription error due to too many pending messages")
+		}
+	}
+}
+
+// writePump writes the messages sent to the connection to the peer.
+//
+// The server runs writePump in a per-connection goroutine, so there is at most
+// one writer on a connection.
+func (c *connection) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+	for {
+		select {
+		case message := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteJSON(message); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
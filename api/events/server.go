@@ -0,0 +1,344 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package events
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+// The dispatchers events can be subscribed to
+const (
+	// Decisions are the txs of DAG chains and the blocks of linear chains
+	DecisionDispatcher = "decisions"
+
+	// Consensus containers are the vertices of DAG chains and the blocks of
+	// linear chains
+	ConsensusDispatcher = "consensus"
+)
+
+// The types of events that can be subscribed to
+const (
+	Accepted = "accepted"
+	Rejected = "rejected"
+	Issued   = "issued"
+)
+
+var (
+	errUnknownDispatcher   = errors.New("unknown dispatcher")
+	errUnknownEventType    = errors.New("unknown event type")
+	errAddressesNotDecoded = errors.New("the containers of this chain can't be filtered by address")
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  readBufferSize,
+	WriteBufferSize: writeBufferSize,
+	CheckOrigin:     func(*http.Request) bool { return true },
+}
+
+// ChainLookup resolves the aliases of chains
+type ChainLookup interface {
+	Lookup(alias string) (ids.ID, error)
+}
+
+// Decoder is implemented by VMs that can describe their decisions to the
+// subscribers of events.
+//
+// Addresses are formatted the way the VM's API formats them, so they differ
+// between VMs. For example, the AVM prefixes them with the alias of the chain
+// (X-6Y3kysjF9jnHnYkdS9yGAuoHyae2eNmeV), while the spdagvm doesn't
+// (6Y3kysjF9jnHnYkdS9yGAuoHyae2eNmeV).
+type Decoder interface {
+	// DecodeContainer returns a JSON-serializable description of [container],
+	// and the addresses of the UTXOs it creates
+	DecodeContainer(container []byte) (interface{}, []string, error)
+
+	// NormalizeAddress returns [addr], given in any form the VM's API accepts,
+	// in the form DecodeContainer returns addresses in
+	NormalizeAddress(addr string) (string, error)
+}
+
+// Event is the message that's sent to the subscribers of an event
+type Event struct {
+	ChainID     ids.ID          `json:"chainID"`
+	Dispatcher  string          `json:"dispatcher"`
+	EventType   string          `json:"eventType"`
+	ContainerID ids.ID          `json:"containerID"`
+	Container   formatting.CB58 `json:"container"`
+
+	// Body and Addresses are only set for the decisions of chains whose VM is
+	// a Decoder. Addresses are in the format of the chain's API.
+	Body      interface{} `json:"body,omitempty"`
+	Addresses []string    `json:"addresses,omitempty"`
+}
+
+// topic identifies the events of one type from one dispatcher of one chain
+type topic struct {
+	chainID    [32]byte
+	dispatcher string
+	eventType  string
+}
+
+// Server sends the events of the node's chains to the websocket connections
+// that subscribed to them
+type Server struct {
+	log    logging.Logger
+	lookup ChainLookup
+
+	lock     sync.Mutex
+	decoders map[[32]byte]Decoder
+
+	// Key: A topic
+	// Value: The subscribers of the topic, and the addresses each subscriber
+	//        filters the topic's events by. An empty filter matches every
+	//        event.
+	subscribers map[topic]map[*connection]map[string]struct{}
+}
+
+// NewServer returns a new events server. Chains are specified by the aliases
+// or IDs that [lookup] knows.
+func NewServer(log logging.Logger, lookup ChainLookup) *Server {
+	return &Server{
+		log:         log,
+		lookup:      lookup,
+		decoders:    make(map[[32]byte]Decoder),
+		subscribers: make(map[topic]map[*connection]map[string]struct{}),
+	}
+}
+
+// DecisionListener returns the handler to register with the decision
+// dispatcher
+func (s *Server) DecisionListener() interface{} {
+	return &listener{s: s, dispatcher: DecisionDispatcher}
+}
+
+// ConsensusListener returns the handler to register with the consensus
+// dispatcher
+func (s *Server) ConsensusListener() interface{} {
+	return &listener{s: s, dispatcher: ConsensusDispatcher}
+}
+
+// RegisterChain implements the chains.Registrant interface. The decisions of
+// chains whose VM is a Decoder are decoded before they're sent.
+func (s *Server) RegisterChain(ctx *snow.Context, vm interface{}) {
+	decoder, ok := vm.(Decoder)
+	if !ok {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.decoders[ctx.ChainID.Key()] = decoder
+}
+
+// ServeHTTP upgrades the request to a websocket connection, over which events
+// can be subscribed to
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	wsConn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		s.log.Debug("failed to upgrade to a websocket connection: %s", err)
+		return
+	}
+	conn := &connection{
+		s:      s,
+		conn:   wsConn,
+		send:   make(chan interface{}, maxPendingMessages),
+		topics: make(map[topic]struct{}),
+	}
+	go conn.writePump()
+	go conn.readPump()
+}
+
+// publish the event to the subscribers of its topic
+func (s *Server) publish(dispatcher, eventType string, chainID, containerID ids.ID, container []byte) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	subscribers, exists := s.subscribers[topic{
+		chainID:    chainID.Key(),
+		dispatcher: dispatcher,
+		eventType:  eventType,
+	}]
+	if !exists {
+		return
+	}
+
+	event := &Event{
+		ChainID:     chainID,
+		Dispatcher:  dispatcher,
+		EventType:   eventType,
+		ContainerID: containerID,
+		Container:   formatting.CB58{Bytes: container},
+	}
+	if decoder, ok := s.decoders[chainID.Key()]; ok && dispatcher == DecisionDispatcher {
+		body, addrs, err := decoder.DecodeContainer(container)
+		if err != nil {
+			s.log.Debug("couldn't decode container %s of chain %s: %s", containerID, chainID, err)
+		} else {
+			event.Body = body
+			event.Addresses = addrs
+		}
+	}
+
+	for conn, filter := range subscribers {
+		if !matches(filter, event.Addresses) {
+			continue
+		}
+		select {
+		case conn.send <- event:
+		default:
+			s.log.Verbo("dropping event to subscribed connection due to too many pending messages")
+		}
+	}
+}
+
+// matches returns true if [filter] is empty, or if it contains one of [addrs]
+func matches(filter map[string]struct{}, addrs []string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	for _, addr := range addrs {
+		if _, ok := filter[addr]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// subscribe [conn] to the events described by [sub]. If [sub] was already
+// subscribed to, its address filter is replaced.
+func (s *Server) subscribe(conn *connection, sub *subscription) error {
+	t, err := s.topic(sub)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.decoders[t.chainID]; len(sub.Addresses) > 0 && (!ok || t.dispatcher != DecisionDispatcher) {
+		return errAddressesNotDecoded
+	}
+
+	filter := make(map[string]struct{}, len(sub.Addresses))
+	for _, addr := range sub.Addresses {
+		normalized, err := s.decoders[t.chainID].NormalizeAddress(addr)
+		if err != nil {
+			return fmt.Errorf("invalid address %q: %w", addr, err)
+		}
+		filter[normalized] = struct{}{}
+	}
+
+	subscribers, exists := s.subscribers[t]
+	if !exists {
+		subscribers = make(map[*connection]map[string]struct{})
+		s.subscribers[t] = subscribers
+	}
+	subscribers[conn] = filter
+	conn.topics[t] = struct{}{}
+	return nil
+}
+
+// unsubscribe [conn] from the events described by [sub]
+func (s *Server) unsubscribe(conn *connection, sub *subscription) error {
+	t, err := s.topic(sub)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.remove(conn, t)
+	return nil
+}
+
+// removeConnection unsubscribes [conn] from every topic
+func (s *Server) removeConnection(conn *connection) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for t := range conn.topics {
+		s.remove(conn, t)
+	}
+}
+
+// remove [conn] from the subscribers of [t]. Assumes [s.lock] is held.
+func (s *Server) remove(conn *connection, t topic) {
+	delete(conn.topics, t)
+	subscribers, exists := s.subscribers[t]
+	if !exists {
+		return
+	}
+	delete(subscribers, conn)
+	if len(subscribers) == 0 {
+		delete(s.subscribers, t)
+	}
+}
+
+// topic returns the topic [sub] refers to
+func (s *Server) topic(sub *subscription) (topic, error) {
+	chainID, err := s.lookup.Lookup(sub.ChainID)
+	if err != nil {
+		chainID, err = ids.FromString(sub.ChainID)
+		if err != nil {
+			return topic{}, fmt.Errorf("unknown chain %q", sub.ChainID)
+		}
+	}
+
+	dispatcher := sub.Dispatcher
+	switch dispatcher {
+	case "":
+		dispatcher = DecisionDispatcher
+	case DecisionDispatcher, ConsensusDispatcher:
+	default:
+		return topic{}, fmt.Errorf("%w %q", errUnknownDispatcher, sub.Dispatcher)
+	}
+
+	switch sub.EventType {
+	case Accepted, Rejected, Issued:
+	default:
+		return topic{}, fmt.Errorf("%w %q", errUnknownEventType, sub.EventType)
+	}
+
+	return topic{
+		chainID:    chainID.Key(),
+		dispatcher: dispatcher,
+		eventType:  sub.EventType,
+	}, nil
+}
+
+// listener passes the events of a dispatcher to the server
+type listener struct {
+	s          *Server
+	dispatcher string
+}
+
+// Accept implements the triggers.Acceptor interface
+func (l *listener) Accept(chainID, containerID ids.ID, container []byte) error {
+	l.s.publish(l.dispatcher, Accepted, chainID, containerID, container)
+	return nil
+}
+
+// Reject implements the triggers.Rejector interface
+func (l *listener) Reject(chainID, containerID ids.ID, container []byte) error {
+	l.s.publish(l.dispatcher, Rejected, chainID, containerID, container)
+	return nil
+}
+
+// Issue implements the triggers.Issuer interface
+func (l *listener) Issue(chainID, containerID ids.ID, container []byte) error {
+	l.s.publish(l.dispatcher, Issued, chainID, containerID, container)
+	return nil
+}
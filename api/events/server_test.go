@@ -0,0 +1,222 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package events
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/triggers"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+var (
+	chainID      = ids.NewID([32]byte{1})
+	otherChainID = ids.NewID([32]byte{2})
+)
+
+var errEmptyAddress = errors.New("empty address")
+
+// testDecoder describes a container by its bytes, which are also the address
+// of the only UTXO it creates
+type testDecoder struct{}
+
+func (testDecoder) DecodeContainer(container []byte) (interface{}, []string, error) {
+	return string(container), []string{string(container)}, nil
+}
+
+// NormalizeAddress drops the optional "X-" prefix of an address
+func (testDecoder) NormalizeAddress(addr string) (string, error) {
+	addr = strings.TrimPrefix(addr, "X-")
+	if addr == "" {
+		return "", errEmptyAddress
+	}
+	return addr, nil
+}
+
+// receivedEvent is how a subscriber decodes the messages it receives
+type receivedEvent struct {
+	ChainID     ids.ID   `json:"chainID"`
+	Dispatcher  string   `json:"dispatcher"`
+	EventType   string   `json:"eventType"`
+	ContainerID ids.ID   `json:"containerID"`
+	Body        string   `json:"body"`
+	Addresses   []string `json:"addresses"`
+
+	Subscription subscription `json:"subscription"`
+	Error        string       `json:"error"`
+}
+
+func setup(t *testing.T) (*Server, *websocket.Conn, func()) {
+	aliaser := &ids.Aliaser{}
+	aliaser.Initialize()
+	if err := aliaser.Alias(chainID, "X"); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewServer(logging.NoLog{}, aliaser)
+	ctx := snow.DefaultContextTest()
+	ctx.ChainID = chainID
+	s.RegisterChain(ctx, testDecoder{})
+
+	httpServer := httptest.NewServer(s)
+	url := "ws" + strings.TrimPrefix(httpServer.URL, "http")
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		httpServer.Close()
+		t.Fatal(err)
+	}
+	return s, conn, func() {
+		conn.Close()
+		httpServer.Close()
+	}
+}
+
+// send [sub] to the server and wait for the server to process it. Returns the
+// error the server replied with, if any.
+func send(t *testing.T, conn *websocket.Conn, sub *subscription) string {
+	if err := conn.WriteJSON(sub); err != nil {
+		t.Fatal(err)
+	}
+	// Subscriptions are processed in order, so once the server replies to this
+	// invalid subscription, [sub] has been processed
+	if err := conn.WriteJSON(&subscription{ChainID: "X"}); err != nil {
+		t.Fatal(err)
+	}
+
+	msg := read(t, conn)
+	if msg.Subscription.ChainID == "X" && msg.Subscription.EventType == "" {
+		return ""
+	}
+	read(t, conn)
+	return msg.Error
+}
+
+func read(t *testing.T, conn *websocket.Conn) *receivedEvent {
+	if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	msg := &receivedEvent{}
+	if err := conn.ReadJSON(msg); err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+func TestSubscribe(t *testing.T) {
+	s, conn, cleanup := setup(t)
+	defer cleanup()
+
+	if err := send(t, conn, &subscription{ChainID: "X", EventType: Accepted}); err != "" {
+		t.Fatal(err)
+	}
+
+	// Events of other chains and of other types shouldn't be sent
+	decisions := s.DecisionListener()
+	containerID := ids.NewID([32]byte{3})
+	decisions.(triggers.Rejector).Reject(chainID, ids.NewID([32]byte{4}), []byte("rejected"))
+	decisions.(triggers.Acceptor).Accept(otherChainID, ids.NewID([32]byte{5}), []byte("otherChain"))
+	decisions.(triggers.Acceptor).Accept(chainID, containerID, []byte("accepted"))
+
+	msg := read(t, conn)
+	switch {
+	case !msg.ChainID.Equals(chainID):
+		t.Fatalf("Wrong chain ID. Expected: %s ; Returned: %s", chainID, msg.ChainID)
+	case !msg.ContainerID.Equals(containerID):
+		t.Fatalf("Wrong container ID. Expected: %s ; Returned: %s", containerID, msg.ContainerID)
+	case msg.Dispatcher != DecisionDispatcher:
+		t.Fatalf("Wrong dispatcher. Expected: %s ; Returned: %s", DecisionDispatcher, msg.Dispatcher)
+	case msg.EventType != Accepted:
+		t.Fatalf("Wrong event type. Expected: %s ; Returned: %s", Accepted, msg.EventType)
+	case msg.Body != "accepted":
+		t.Fatalf("Wrong body. Expected: %s ; Returned: %s", "accepted", msg.Body)
+	case len(msg.Addresses) != 1 || msg.Addresses[0] != "accepted":
+		t.Fatalf("Wrong addresses: %v", msg.Addresses)
+	}
+
+	// After unsubscribing, no events should be sent
+	if err := send(t, conn, &subscription{ChainID: "X", EventType: Accepted, Unsubscribe: true}); err != "" {
+		t.Fatal(err)
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, exists := s.subscribers[topic{chainID: chainID.Key(), dispatcher: DecisionDispatcher, eventType: Accepted}]; exists {
+		t.Fatalf("Should have removed the topic")
+	}
+}
+
+func TestSubscribeAddresses(t *testing.T) {
+	s, conn, cleanup := setup(t)
+	defer cleanup()
+
+	sub := &subscription{
+		ChainID:   chainID.String(),
+		EventType: Issued,
+		Addresses: []string{"X-mine"},
+	}
+	if err := send(t, conn, sub); err != "" {
+		t.Fatal(err)
+	}
+
+	decisions := s.DecisionListener().(triggers.Issuer)
+	decisions.Issue(chainID, ids.NewID([32]byte{3}), []byte("theirs"))
+	decisions.Issue(chainID, ids.NewID([32]byte{4}), []byte("mine"))
+
+	if msg := read(t, conn); msg.Body != "mine" {
+		t.Fatalf("Should only have sent the events of the subscribed addresses")
+	}
+
+	sub.Addresses = []string{"X-"}
+	if err := send(t, conn, sub); !strings.Contains(err, errEmptyAddress.Error()) {
+		t.Fatalf("Should have errored with %s but errored with %s", errEmptyAddress, err)
+	}
+
+	// Consensus containers aren't decoded, so they can't be filtered
+	sub.Dispatcher = ConsensusDispatcher
+	if err := send(t, conn, sub); !strings.Contains(err, errAddressesNotDecoded.Error()) {
+		t.Fatalf("Should have errored with %s but errored with %s", errAddressesNotDecoded, err)
+	}
+}
+
+func TestSubscribeConsensus(t *testing.T) {
+	s, conn, cleanup := setup(t)
+	defer cleanup()
+
+	if err := send(t, conn, &subscription{ChainID: "X", Dispatcher: ConsensusDispatcher, EventType: Accepted}); err != "" {
+		t.Fatal(err)
+	}
+
+	containerID := ids.NewID([32]byte{3})
+	s.DecisionListener().(triggers.Acceptor).Accept(chainID, ids.NewID([32]byte{4}), []byte("decision"))
+	s.ConsensusListener().(triggers.Acceptor).Accept(chainID, containerID, []byte("vertex"))
+
+	msg := read(t, conn)
+	switch {
+	case !msg.ContainerID.Equals(containerID):
+		t.Fatalf("Wrong container ID. Expected: %s ; Returned: %s", containerID, msg.ContainerID)
+	case msg.Dispatcher != ConsensusDispatcher:
+		t.Fatalf("Wrong dispatcher. Expected: %s ; Returned: %s", ConsensusDispatcher, msg.Dispatcher)
+	case msg.Body != "" || len(msg.Addresses) != 0:
+		t.Fatalf("Consensus containers shouldn't be decoded")
+	}
+}
+
+func TestSubscribeInvalid(t *testing.T) {
+	_, conn, cleanup := setup(t)
+	defer cleanup()
+
+	if err := send(t, conn, &subscription{ChainID: "Y", EventType: Accepted}); err == "" {
+		t.Fatalf("Should have failed because the chain doesn't exist")
+	}
+	if err := send(t, conn, &subscription{ChainID: "X", Dispatcher: "votes", EventType: Accepted}); !strings.Contains(err, errUnknownDispatcher.Error()) {
+		t.Fatalf("Should have errored with %s but errored with %s", errUnknownDispatcher, err)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/database/encdb"
 	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/database/versiondb"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/utils/formatting"
@@ -25,6 +26,9 @@ import (
 
 var (
 	errEmptyUsername = errors.New("username can't be the empty string")
+
+	usersPrefix = []byte("users")
+	bcsPrefix   = []byte("bcs")
 )
 
 // KeyValuePair ...
@@ -39,6 +43,12 @@ type UserDB struct {
 	Data []KeyValuePair `serialize:"true"`
 }
 
+// legacyUserDB is how the content of legacy users was exported
+type legacyUserDB struct {
+	legacyUser `serialize:"true"`
+	Data       []KeyValuePair `serialize:"true"`
+}
+
 // Keystore is the RPC interface for keystore management
 type Keystore struct {
 	lock sync.Mutex
@@ -51,6 +61,7 @@ type Keystore struct {
 	users map[string]*User
 
 	// Used to persist users and their data
+	db     database.Database
 	userDB database.Database
 	bcDB   database.Database
 	//           BaseDB
@@ -67,8 +78,9 @@ func (ks *Keystore) Initialize(log logging.Logger, db database.Database) {
 	ks.log = log
 	ks.codec = codec.NewDefault()
 	ks.users = make(map[string]*User)
-	ks.userDB = prefixdb.New([]byte("users"), db)
-	ks.bcDB = prefixdb.New([]byte("bcs"), db)
+	ks.db = db
+	ks.userDB = prefixdb.New(usersPrefix, db)
+	ks.bcDB = prefixdb.New(bcsPrefix, db)
 }
 
 // CreateHandler returns a new service object that can send requests to thisAPI.
@@ -94,8 +106,99 @@ func (ks *Keystore) getUser(username string) (*User, error) {
 		return nil, err
 	}
 
-	usr = &User{}
-	return usr, ks.codec.Unmarshal(usrBytes, usr)
+	return ks.parseUser(usrBytes)
+}
+
+// parseUser parses [usrBytes], which are in the current format or the legacy
+// one
+func (ks *Keystore) parseUser(usrBytes []byte) (*User, error) {
+	usr := &User{}
+	if err := ks.codec.Unmarshal(usrBytes, usr); err == nil {
+		return usr, nil
+	}
+	legacyUsr := legacyUser{}
+	if err := ks.codec.Unmarshal(usrBytes, &legacyUsr); err != nil {
+		return nil, err
+	}
+	return &User{
+		Password: legacyUsr.Password,
+		Salt:     legacyUsr.Salt,
+	}, nil
+}
+
+// unlock returns the user [username], and the key their data is encrypted
+// with. If the user's password was hashed with outdated parameters, it's
+// hashed again and the user's data is encrypted with the new key. Assumes
+// [ks.lock] is held.
+func (ks *Keystore) unlock(username, password string) (*User, []byte, error) {
+	usr, err := ks.getUser(username)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, ok := usr.unlock(password)
+	if !ok {
+		return nil, nil, fmt.Errorf("incorrect password for user '%s'", username)
+	}
+	if !usr.outdated() {
+		return usr, key, nil
+	}
+
+	ks.log.Info("upgrading the password hash of user '%s'", username)
+	return ks.rekey(username, key, password)
+}
+
+// rekey hashes [password] with the default parameters, and encrypts the data
+// of [username], which is encrypted with [key], with the key the new hash
+// yields. The user and their data are written atomically. Returns the updated
+// user and their new key. Assumes [ks.lock] is held.
+func (ks *Keystore) rekey(username string, key []byte, password string) (*User, []byte, error) {
+	usr := &User{}
+	newKey, err := usr.initialize(password)
+	if err != nil {
+		return nil, nil, err
+	}
+	usrBytes, err := ks.codec.Marshal(usr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The data is read from [ks.db], and the writes are buffered in [vdb]
+	// until they're committed together
+	vdb := versiondb.New(ks.db)
+	oldData, err := encdb.NewWithKey(key, userData(ks.db, username))
+	if err != nil {
+		return nil, nil, err
+	}
+	newData, err := encdb.NewWithKey(newKey, userData(vdb, username))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	it := oldData.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		if err := newData.Put(it.Key(), it.Value()); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := it.Error(); err != nil {
+		return nil, nil, err
+	}
+	if err := prefixdb.New(usersPrefix, vdb).Put([]byte(username), usrBytes); err != nil {
+		return nil, nil, err
+	}
+	if err := vdb.Commit(); err != nil {
+		return nil, nil, err
+	}
+
+	ks.users[username] = usr
+	return usr, newKey, nil
+}
+
+// userData returns the data of [username] in [db], which is the database of
+// the keystore
+func userData(db database.Database, username string) database.Database {
+	return prefixdb.New([]byte(username), prefixdb.New(bcsPrefix, db))
 }
 
 // CreateUserArgs are arguments for passing into CreateUser requests
@@ -184,15 +287,12 @@ func (ks *Keystore) ExportUser(_ *http.Request, args *ExportUserArgs, reply *Exp
 
 	ks.log.Verbo("ExportUser called for %s", args.Username)
 
-	usr, err := ks.getUser(args.Username)
+	usr, _, err := ks.unlock(args.Username, args.Password)
 	if err != nil {
 		return err
 	}
-	if !usr.CheckPassword(args.Password) {
-		return fmt.Errorf("incorrect password for %s", args.Username)
-	}
 
-	userDB := prefixdb.New([]byte(args.Username), ks.bcDB)
+	userDB := userData(ks.db, args.Username)
 
 	userData := UserDB{
 		User: *usr,
@@ -249,6 +349,19 @@ func (ks *Keystore) ImportUser(r *http.Request, args *ImportUserArgs, reply *Imp
 
 	userData := UserDB{}
 	if err := ks.codec.Unmarshal(cb58.Bytes, &userData); err != nil {
+		// The user may have been exported before the password's hash was
+		// parameterized. They'll be upgraded the first time they log in.
+		legacyUserData := legacyUserDB{}
+		if err := ks.codec.Unmarshal(cb58.Bytes, &legacyUserData); err != nil {
+			return err
+		}
+		userData.User = User{
+			Password: legacyUserData.Password,
+			Salt:     legacyUserData.Salt,
+		}
+		userData.Data = legacyUserData.Data
+	}
+	if err := userData.User.verifyKDF(); err != nil {
 		return err
 	}
 
@@ -275,6 +388,90 @@ func (ks *Keystore) ImportUser(r *http.Request, args *ImportUserArgs, reply *Imp
 	return batch.Write()
 }
 
+// ChangePasswordArgs are the arguments to ChangePassword
+type ChangePasswordArgs struct {
+	Username    string `json:"username"`
+	Password    string `json:"password"`
+	NewPassword string `json:"newPassword"`
+}
+
+// ChangePasswordReply is the reply from ChangePassword
+type ChangePasswordReply struct {
+	Success bool `json:"success"`
+}
+
+// ChangePassword changes the password of a user. All of the user's data is
+// encrypted again with the key of the new password.
+func (ks *Keystore) ChangePassword(_ *http.Request, args *ChangePasswordArgs, reply *ChangePasswordReply) error {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	ks.log.Verbo("ChangePassword called for %s", args.Username)
+
+	_, key, err := ks.unlock(args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+	if _, _, err := ks.rekey(args.Username, key, args.NewPassword); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// DeleteUserArgs are the arguments to DeleteUser
+type DeleteUserArgs struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// DeleteUserReply is the reply from DeleteUser
+type DeleteUserReply struct {
+	Success bool `json:"success"`
+}
+
+// DeleteUser deletes a user and all of their data
+func (ks *Keystore) DeleteUser(_ *http.Request, args *DeleteUserArgs, reply *DeleteUserReply) error {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	ks.log.Verbo("DeleteUser called for %s", args.Username)
+
+	usr, err := ks.getUser(args.Username)
+	if err != nil {
+		return err
+	}
+	if !usr.CheckPassword(args.Password) {
+		return fmt.Errorf("incorrect password for %s", args.Username)
+	}
+
+	// The data is read from [ks.db], and the deletions are buffered in [vdb]
+	// until they're committed together
+	vdb := versiondb.New(ks.db)
+	data := userData(vdb, args.Username)
+
+	it := userData(ks.db, args.Username).NewIterator()
+	defer it.Release()
+	for it.Next() {
+		if err := data.Delete(it.Key()); err != nil {
+			return err
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if err := prefixdb.New(usersPrefix, vdb).Delete([]byte(args.Username)); err != nil {
+		return err
+	}
+	if err := vdb.Commit(); err != nil {
+		return err
+	}
+
+	delete(ks.users, args.Username)
+	reply.Success = true
+	return nil
+}
+
 // NewBlockchainKeyStore ...
 func (ks *Keystore) NewBlockchainKeyStore(blockchainID ids.ID) *BlockchainKeystore {
 	return &BlockchainKeystore{
@@ -288,17 +485,14 @@ func (ks *Keystore) GetDatabase(bID ids.ID, username, password string) (database
 	ks.lock.Lock()
 	defer ks.lock.Unlock()
 
-	usr, err := ks.getUser(username)
+	_, key, err := ks.unlock(username, password)
 	if err != nil {
 		return nil, err
 	}
-	if !usr.CheckPassword(password) {
-		return nil, fmt.Errorf("incorrect password for user '%s'", username)
-	}
 
-	userDB := prefixdb.New([]byte(username), ks.bcDB)
+	userDB := userData(ks.db, username)
 	bcDB := prefixdb.NewNested(bID.Bytes(), userDB)
-	encDB, err := encdb.New([]byte(password), bcDB)
+	encDB, err := encdb.NewWithKey(key, bcDB)
 
 	if err != nil {
 		return nil, err
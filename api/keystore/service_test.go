@@ -7,8 +7,13 @@ import (
 	"bytes"
 	"testing"
 
+	"golang.org/x/crypto/argon2"
+
+	"github.com/ava-labs/gecko/database/encdb"
 	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/database/prefixdb"
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/formatting"
 	"github.com/ava-labs/gecko/utils/logging"
 )
 
@@ -200,3 +205,250 @@ func TestServiceExportImport(t *testing.T) {
 		}
 	}
 }
+
+func TestServiceImportUserKDFBounds(t *testing.T) {
+	tests := []struct {
+		name  string
+		kdf   KDFParams
+		valid bool
+	}{
+		{name: "default", kdf: DefaultKDFParams, valid: true},
+		{name: "legacy", kdf: KDFParams{}, valid: true},
+		{name: "lower bounds", kdf: KDFParams{Time: minKDFTime, Memory: minKDFMemory, Threads: minKDFThreads}, valid: true},
+		{name: "upper bounds", kdf: KDFParams{Time: maxKDFTime, Memory: maxKDFMemory, Threads: maxKDFThreads}, valid: true},
+		{name: "no time", kdf: KDFParams{Time: 0, Memory: 64 * 1024, Threads: 4}},
+		{name: "too much time", kdf: KDFParams{Time: maxKDFTime + 1, Memory: 64 * 1024, Threads: 4}},
+		{name: "too little memory", kdf: KDFParams{Time: 3, Memory: minKDFMemory - 1, Threads: 4}},
+		{name: "too much memory", kdf: KDFParams{Time: 3, Memory: 1<<32 - 1, Threads: 4}},
+		{name: "no threads", kdf: KDFParams{Time: 3, Memory: 64 * 1024, Threads: 0}},
+		{name: "too many threads", kdf: KDFParams{Time: 3, Memory: 64 * 1024, Threads: maxKDFThreads + 1}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ks := Keystore{}
+			ks.Initialize(logging.NoLog{}, memdb.New())
+
+			userData := UserDB{User: User{KDF: test.kdf}}
+			b, err := ks.codec.Marshal(&userData)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = ks.ImportUser(nil, &ImportUserArgs{
+				Username: "bob",
+				Password: "launch",
+				User:     formatting.CB58{Bytes: b}.String(),
+			}, &ImportUserReply{})
+			if test.valid && err != nil {
+				t.Fatal(err)
+			}
+			if !test.valid {
+				if err != errKDFParamsOutOfBounds {
+					t.Fatalf("Wrong error. Expected: %v ; Returned: %v", errKDFParamsOutOfBounds, err)
+				}
+				if _, err := ks.getUser("bob"); err == nil {
+					t.Fatalf("The user shouldn't have been imported")
+				}
+			}
+		})
+	}
+}
+
+func TestServiceChangePassword(t *testing.T) {
+	ks := Keystore{}
+	ks.Initialize(logging.NoLog{}, memdb.New())
+
+	if err := ks.CreateUser(nil, &CreateUserArgs{
+		Username: "bob",
+		Password: "launch",
+	}, &CreateUserReply{}); err != nil {
+		t.Fatal(err)
+	}
+
+	bID := ids.NewID([32]byte{1})
+	for _, chainID := range []ids.ID{ids.Empty, bID} {
+		db, err := ks.GetDatabase(chainID, "bob", "launch")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Put([]byte("hello"), chainID.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := ks.ChangePassword(nil, &ChangePasswordArgs{
+		Username:    "bob",
+		Password:    "wrong",
+		NewPassword: "land",
+	}, &ChangePasswordReply{}); err == nil {
+		t.Fatalf("Should have failed because the password is wrong")
+	}
+
+	reply := ChangePasswordReply{}
+	if err := ks.ChangePassword(nil, &ChangePasswordArgs{
+		Username:    "bob",
+		Password:    "launch",
+		NewPassword: "land",
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if !reply.Success {
+		t.Fatalf("Password should have been changed successfully")
+	}
+
+	if _, err := ks.GetDatabase(ids.Empty, "bob", "launch"); err == nil {
+		t.Fatalf("Shouldn't have accepted the old password")
+	}
+	for _, chainID := range []ids.ID{ids.Empty, bID} {
+		db, err := ks.GetDatabase(chainID, "bob", "land")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if val, err := db.Get([]byte("hello")); err != nil {
+			t.Fatal(err)
+		} else if !bytes.Equal(val, chainID.Bytes()) {
+			t.Fatalf("Should have read '%s' from the db", chainID)
+		}
+	}
+}
+
+func TestServiceDeleteUser(t *testing.T) {
+	ks := Keystore{}
+	ks.Initialize(logging.NoLog{}, memdb.New())
+
+	for _, username := range []string{"bob", "bobby"} {
+		if err := ks.CreateUser(nil, &CreateUserArgs{
+			Username: username,
+			Password: "launch",
+		}, &CreateUserReply{}); err != nil {
+			t.Fatal(err)
+		}
+		db, err := ks.GetDatabase(ids.Empty, username, "launch")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Put([]byte("hello"), []byte("world")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := ks.DeleteUser(nil, &DeleteUserArgs{
+		Username: "bob",
+		Password: "wrong",
+	}, &DeleteUserReply{}); err == nil {
+		t.Fatalf("Should have failed because the password is wrong")
+	}
+
+	reply := DeleteUserReply{}
+	if err := ks.DeleteUser(nil, &DeleteUserArgs{
+		Username: "bob",
+		Password: "launch",
+	}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if !reply.Success {
+		t.Fatalf("User should have been deleted successfully")
+	}
+
+	listReply := ListUsersReply{}
+	if err := ks.ListUsers(nil, &ListUsersArgs{}, &listReply); err != nil {
+		t.Fatal(err)
+	}
+	if len(listReply.Users) != 1 || listReply.Users[0] != "bobby" {
+		t.Fatalf("Only 'bobby' should be left, but the users are %v", listReply.Users)
+	}
+	if _, err := ks.GetDatabase(ids.Empty, "bob", "launch"); err == nil {
+		t.Fatalf("Should have failed because the user was deleted")
+	}
+
+	// The deleted user's data should be gone, even if the name is reused
+	if err := ks.CreateUser(nil, &CreateUserArgs{
+		Username: "bob",
+		Password: "launch",
+	}, &CreateUserReply{}); err != nil {
+		t.Fatal(err)
+	}
+	db, err := ks.GetDatabase(ids.Empty, "bob", "launch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if has, err := db.Has([]byte("hello")); err != nil {
+		t.Fatal(err)
+	} else if has {
+		t.Fatalf("The data of the deleted user should have been deleted")
+	}
+
+	// The other user's data should be untouched
+	db, err = ks.GetDatabase(ids.Empty, "bobby", "launch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val, err := db.Get([]byte("hello")); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(val, []byte("world")) {
+		t.Fatalf("Should have read '%s' from the db", "world")
+	}
+}
+
+func TestServiceMigrateLegacyUser(t *testing.T) {
+	db := memdb.New()
+	ks := Keystore{}
+	ks.Initialize(logging.NoLog{}, db)
+
+	// Write a user the way they used to be written
+	legacyUsr := legacyUser{}
+	copy(legacyUsr.Salt[:], "some salt")
+	copy(legacyUsr.Password[:], argon2.IDKey([]byte("launch"), legacyUsr.Salt[:], legacyTime, legacyMemory, legacyThreads, 32))
+	usrBytes, err := ks.codec.Marshal(&legacyUsr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ks.userDB.Put([]byte("bob"), usrBytes); err != nil {
+		t.Fatal(err)
+	}
+	legacyDB, err := encdb.New([]byte("launch"), prefixdb.NewNested(ids.Empty.Bytes(), userData(db, "bob")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := legacyDB.Put([]byte("hello"), []byte("world")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ks.GetDatabase(ids.Empty, "bob", "wrong"); err == nil {
+		t.Fatalf("Should have failed because the password is wrong")
+	}
+
+	// Logging in upgrades the user
+	bcDB, err := ks.GetDatabase(ids.Empty, "bob", "launch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val, err := bcDB.Get([]byte("hello")); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(val, []byte("world")) {
+		t.Fatalf("Should have read '%s' from the db", "world")
+	}
+	if _, err := legacyDB.Get([]byte("hello")); err == nil {
+		t.Fatalf("The data should have been encrypted with the new key")
+	}
+
+	// The upgrade should have been persisted
+	newKS := Keystore{}
+	newKS.Initialize(logging.NoLog{}, db)
+	usr, err := newKS.getUser("bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usr.KDF != DefaultKDFParams {
+		t.Fatalf("Wrong KDF parameters. Expected: %+v ; Returned: %+v", DefaultKDFParams, usr.KDF)
+	}
+	bcDB, err = newKS.GetDatabase(ids.Empty, "bob", "launch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val, err := bcDB.Get([]byte("hello")); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(val, []byte("world")) {
+		t.Fatalf("Should have read '%s' from the db", "world")
+	}
+}
@@ -4,32 +4,133 @@
 package keystore
 
 import (
-	"bytes"
 	"crypto/rand"
+	"crypto/subtle"
+	"errors"
 
 	"golang.org/x/crypto/argon2"
+
+	"github.com/ava-labs/gecko/utils/hashing"
+)
+
+// The parameters the passwords of legacy users were hashed with. The data of
+// legacy users is encrypted with the hash of their password.
+const (
+	legacyTime    = 1
+	legacyMemory  = 64 * 1024
+	legacyThreads = 4
 )
 
-// User describes a user of the keystore
+// The bounds of the parameters a user's password may be hashed with. Weaker
+// parameters would make the password easy to guess, and stronger ones would
+// let an imported user make every login take too much time or memory.
+const (
+	minKDFTime    = 1
+	maxKDFTime    = 16
+	minKDFMemory  = 16 * 1024  // In KiB
+	maxKDFMemory  = 256 * 1024 // In KiB
+	minKDFThreads = 1
+	maxKDFThreads = 16
+)
+
+var errKDFParamsOutOfBounds = errors.New("password hash parameters are out of bounds")
+
+// DefaultKDFParams are the parameters the passwords of new users are hashed
+// with. Users whose passwords were hashed with other parameters are upgraded
+// to these the next time they log in.
+var DefaultKDFParams = KDFParams{
+	Time:    3,
+	Memory:  64 * 1024,
+	Threads: 4,
+}
+
+// KDFParams are the argon2id parameters a user's password is hashed with
+type KDFParams struct {
+	Time    uint32 `serialize:"true"`
+	Memory  uint32 `serialize:"true"` // In KiB
+	Threads uint8  `serialize:"true"`
+}
+
+// User describes a user of the keystore. The hash of the user's password
+// yields both the hash that's stored, and the key the user's data is encrypted
+// with.
 type User struct {
+	Password [32]byte  `serialize:"true"` // The salted, hashed password
+	Salt     [16]byte  `serialize:"true"` // The salt
+	KDF      KDFParams `serialize:"true"` // Zero for legacy users
+}
+
+// legacyUser is how users were stored before their password's hash was
+// parameterized
+type legacyUser struct {
 	Password [32]byte `serialize:"true"` // The salted, hashed password
 	Salt     [16]byte `serialize:"true"` // The salt
 }
 
 // Initialize ...
 func (usr *User) Initialize(password string) error {
-	_, err := rand.Read(usr.Salt[:])
-	if err != nil {
-		return err
+	_, err := usr.initialize(password)
+	return err
+}
+
+// initialize the user with [password], hashed with the default parameters.
+// Returns the key the user's data is encrypted with.
+func (usr *User) initialize(password string) ([]byte, error) {
+	if _, err := rand.Read(usr.Salt[:]); err != nil {
+		return nil, err
 	}
-	// pw is the salted, hashed password
-	pw := argon2.IDKey([]byte(password), usr.Salt[:], 1, 64*1024, 4, 32)
-	copy(usr.Password[:], pw[:32])
-	return nil
+	usr.KDF = DefaultKDFParams
+	pw, key := usr.hash(password)
+	copy(usr.Password[:], pw)
+	return key, nil
 }
 
 // CheckPassword ...
 func (usr *User) CheckPassword(password string) bool {
-	pw := argon2.IDKey([]byte(password), usr.Salt[:], 1, 64*1024, 4, 32)
-	return bytes.Equal(pw, usr.Password[:])
+	_, ok := usr.unlock(password)
+	return ok
+}
+
+// unlock returns the key the user's data is encrypted with, and true, if
+// [password] is the user's password
+func (usr *User) unlock(password string) ([]byte, bool) {
+	pw, key := usr.hash(password)
+	if subtle.ConstantTimeCompare(pw, usr.Password[:]) != 1 {
+		return nil, false
+	}
+	return key, true
+}
+
+// hash returns the salted hash of [password], and the key the user's data is
+// encrypted with if [password] is the user's password
+func (usr *User) hash(password string) ([]byte, []byte) {
+	if usr.legacy() {
+		pw := argon2.IDKey([]byte(password), usr.Salt[:], legacyTime, legacyMemory, legacyThreads, 32)
+		return pw, hashing.ComputeHash256([]byte(password))
+	}
+	b := argon2.IDKey([]byte(password), usr.Salt[:], usr.KDF.Time, usr.KDF.Memory, usr.KDF.Threads, 64)
+	return b[:32], b[32:]
 }
+
+// verifyKDF returns an error if the user's password was hashed with parameters
+// outside of the bounds the keystore accepts
+func (usr *User) verifyKDF() error {
+	if usr.legacy() {
+		return nil
+	}
+	switch kdf := usr.KDF; {
+	case kdf.Time < minKDFTime || kdf.Time > maxKDFTime,
+		kdf.Memory < minKDFMemory || kdf.Memory > maxKDFMemory,
+		kdf.Threads < minKDFThreads || kdf.Threads > maxKDFThreads:
+		return errKDFParamsOutOfBounds
+	}
+	return nil
+}
+
+// legacy returns true if the user's password was hashed before the hash was
+// parameterized
+func (usr *User) legacy() bool { return usr.KDF == KDFParams{} }
+
+// outdated returns true if the user's password should be hashed again with
+// the default parameters
+func (usr *User) outdated() bool { return usr.KDF != DefaultKDFParams }
@@ -4,7 +4,12 @@
 package keystore
 
 import (
+	"bytes"
 	"testing"
+
+	"golang.org/x/crypto/argon2"
+
+	"github.com/ava-labs/gecko/utils/hashing"
 )
 
 func TestUser(t *testing.T) {
@@ -22,3 +27,37 @@ func TestUser(t *testing.T) {
 		t.Fatalf("Shouldn't have verified the password")
 	}
 }
+
+func TestLegacyUser(t *testing.T) {
+	usr := User{}
+	copy(usr.Salt[:], "some salt")
+	copy(usr.Password[:], argon2.IDKey([]byte("heytherepal"), usr.Salt[:], legacyTime, legacyMemory, legacyThreads, 32))
+
+	key, ok := usr.unlock("heytherepal")
+	if !ok {
+		t.Fatalf("Should have verified the password")
+	}
+	if !bytes.Equal(key, hashing.ComputeHash256([]byte("heytherepal"))) {
+		t.Fatalf("The data of legacy users should be encrypted with the hash of their password")
+	}
+	if usr.CheckPassword("heytherepal!") {
+		t.Fatalf("Shouldn't have verified the password")
+	}
+	if !usr.outdated() {
+		t.Fatalf("Legacy users should be upgraded")
+	}
+
+	if err := usr.Initialize("heytherepal"); err != nil {
+		t.Fatal(err)
+	}
+	if usr.outdated() {
+		t.Fatalf("New users shouldn't be upgraded")
+	}
+	newKey, ok := usr.unlock("heytherepal")
+	if !ok {
+		t.Fatalf("Should have verified the password")
+	}
+	if bytes.Equal(newKey, key) {
+		t.Fatalf("The key of new users should be derived from the password's salted hash")
+	}
+}
@@ -0,0 +1,105 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package signer
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/utils/formatting"
+)
+
+// Client is a snow.RemoteSigner that forwards the signing requests of the
+// node's chains to a signer listening on a unix socket, so that the keys never
+// enter the node's memory
+type Client struct{ path string }
+
+// NewClient returns a client of the signer listening on [path]
+func NewClient(path string) *Client { return &Client{path: path} }
+
+// GetSigner implements the snow.RemoteSigner interface. A session is opened
+// with the signer, and the user's credentials are checked once, when logging
+// in. The session is closed once the returned signer is garbage collected, or
+// by the signer once it's been idle for a while.
+func (c *Client) GetSigner(username, password string) (snow.Signer, error) {
+	conn, err := net.DialTimeout("unix", c.path, requestTimeout)
+	if err != nil {
+		return nil, err
+	}
+	s := &remoteSigner{
+		conn: conn,
+		enc:  json.NewEncoder(conn),
+		dec:  json.NewDecoder(conn),
+	}
+	if _, err := s.call(&Request{
+		Method:   LoginMethod,
+		Username: username,
+		Password: password,
+	}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	runtime.SetFinalizer(s, func(s *remoteSigner) { s.conn.Close() })
+	return s, nil
+}
+
+// remoteSigner signs on behalf of the user logged in to a session with the
+// signer
+type remoteSigner struct {
+	// Requests of a session are answered one at a time
+	lock sync.Mutex
+
+	conn net.Conn
+	enc  *json.Encoder
+	dec  *json.Decoder
+}
+
+// call sends [req] to the signer and returns its response
+func (s *remoteSigner) call(req *Request) (*Response, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := s.conn.SetDeadline(time.Now().Add(requestTimeout)); err != nil {
+		return nil, err
+	}
+	if err := s.enc.Encode(req); err != nil {
+		return nil, err
+	}
+	resp := &Response{}
+	if err := s.dec.Decode(resp); err != nil {
+		return nil, err
+	}
+	if resp.Error != "" {
+		return nil, errors.New(resp.Error)
+	}
+	return resp, nil
+}
+
+// Addresses implements the snow.Signer interface
+func (s *remoteSigner) Addresses() ([]ids.ShortID, error) {
+	resp, err := s.call(&Request{Method: AddressesMethod})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Addresses, nil
+}
+
+// SignHash implements the snow.Signer interface
+func (s *remoteSigner) SignHash(addr ids.ShortID, hash []byte) ([]byte, error) {
+	resp, err := s.call(&Request{
+		Method:  SignHashMethod,
+		Address: addr,
+		Hash:    formatting.CB58{Bytes: hash},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Signature.Bytes, nil
+}
@@ -0,0 +1,119 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package signer
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/ava-labs/gecko/api/keystore"
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+var (
+	errUnknownAddress = errors.New("no key controls the address")
+	errInvalidKey     = errors.New("stored key isn't a secp256k1 key")
+)
+
+// keysID is the ID the keys of a user are stored under in their keystore
+// database. The signer has no blockchains, so any fixed ID will do.
+var keysID = ids.Empty
+
+// Keychain is a snow.RemoteSigner that stores the keys of its users in [db],
+// encrypted with their passwords. It's the backend a Server is expected to be
+// run with.
+type Keychain struct {
+	// Serializes the creation of users
+	lock sync.Mutex
+
+	factory crypto.FactorySECP256K1R
+	ks      keystore.Keystore
+}
+
+// NewKeychain returns a keychain that stores its users in [db]
+func NewKeychain(log logging.Logger, db database.Database) *Keychain {
+	kc := &Keychain{}
+	kc.ks.Initialize(log, db)
+	return kc
+}
+
+// AddKey gives [username] control of [key]. The user is created with
+// [password] if they don't exist yet.
+func (kc *Keychain) AddKey(username, password string, key *crypto.PrivateKeySECP256K1R) error {
+	kc.lock.Lock()
+	defer kc.lock.Unlock()
+
+	db, err := kc.ks.GetDatabase(keysID, username, password)
+	if err != nil {
+		// The user may not exist yet. If they do, the password was wrong.
+		if createErr := kc.ks.CreateUser(nil, &keystore.CreateUserArgs{
+			Username: username,
+			Password: password,
+		}, &keystore.CreateUserReply{}); createErr != nil {
+			return err
+		}
+		if db, err = kc.ks.GetDatabase(keysID, username, password); err != nil {
+			return err
+		}
+	}
+	return db.Put(key.PublicKey().Address().Bytes(), key.Bytes())
+}
+
+// GetSigner implements the snow.RemoteSigner interface. The user's password is
+// checked once, when the signer is returned.
+func (kc *Keychain) GetSigner(username, password string) (snow.Signer, error) {
+	db, err := kc.ks.GetDatabase(keysID, username, password)
+	if err != nil {
+		return nil, err
+	}
+	return &keychainSigner{factory: &kc.factory, db: db}, nil
+}
+
+// keychainSigner signs with the keys of one user of a keychain
+type keychainSigner struct {
+	factory *crypto.FactorySECP256K1R
+
+	// Key: The address controlled by the key
+	// Value: The key, encrypted with the user's password
+	db database.Database
+}
+
+// Addresses implements the snow.Signer interface
+func (s *keychainSigner) Addresses() ([]ids.ShortID, error) {
+	it := s.db.NewIterator()
+	defer it.Release()
+
+	addrs := []ids.ShortID(nil)
+	for it.Next() {
+		addr, err := ids.ToShortID(it.Key())
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs, it.Error()
+}
+
+// SignHash implements the snow.Signer interface
+func (s *keychainSigner) SignHash(addr ids.ShortID, hash []byte) ([]byte, error) {
+	keyBytes, err := s.db.Get(addr.Bytes())
+	if err == database.ErrNotFound {
+		return nil, errUnknownAddress
+	} else if err != nil {
+		return nil, err
+	}
+	sk, err := s.factory.ToPrivateKey(keyBytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := sk.(*crypto.PrivateKeySECP256K1R)
+	if !ok {
+		return nil, errInvalidKey
+	}
+	return key.SignHash(hash)
+}
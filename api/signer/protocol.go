@@ -0,0 +1,54 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package signer
+
+import (
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/formatting"
+)
+
+// The signer speaks a small JSON protocol over a unix socket. Each connection is
+// a session: the node first logs in as a user, then sends requests on behalf of
+// that user and reads their responses one at a time.
+const (
+	// LoginMethod checks the credentials of a user, and makes them the user
+	// of the session. It must be the first request of a session.
+	LoginMethod = "login"
+
+	// AddressesMethod returns the addresses the user can sign for
+	AddressesMethod = "addresses"
+
+	// SignHashMethod signs a hash with the key that controls an address
+	SignHashMethod = "signHash"
+
+	// requestTimeout is how long a request has to complete, including
+	// connecting to the signer
+	requestTimeout = 10 * time.Second
+
+	// sessionTimeout is how long the signer keeps an idle session open
+	sessionTimeout = time.Minute
+)
+
+// Request is sent by the node to the signer
+type Request struct {
+	Method string `json:"method"`
+
+	// Username and Password are only set for LoginMethod
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+
+	// Address and Hash are only set for SignHashMethod
+	Address ids.ShortID     `json:"address"`
+	Hash    formatting.CB58 `json:"hash"`
+}
+
+// Response is sent by the signer to the node. If Error is non-empty, the
+// request failed and the other fields are unset.
+type Response struct {
+	Addresses []ids.ShortID   `json:"addresses,omitempty"`
+	Signature formatting.CB58 `json:"signature"`
+	Error     string          `json:"error,omitempty"`
+}
@@ -0,0 +1,129 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package signer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+var (
+	errUnknownMethod = errors.New("unknown method")
+	errInvalidHash   = fmt.Errorf("hash must be %d bytes", hashing.HashLen)
+	errNoAddress     = errors.New("no address provided")
+	errNotLoggedIn   = errors.New("the session must start with a login")
+	errLoggedIn      = errors.New("the session is already logged in")
+)
+
+// Server answers the requests of a Client with the signers of [backend]. It's
+// meant to run in its own process, which is the only one with access to the
+// keys.
+type Server struct {
+	log     logging.Logger
+	backend snow.RemoteSigner
+}
+
+// NewServer returns a server of the signers of [backend]
+func NewServer(log logging.Logger, backend snow.RemoteSigner) *Server {
+	return &Server{
+		log:     log,
+		backend: backend,
+	}
+}
+
+// Serve the connections accepted by [listener] until it's closed
+func (s *Server) Serve(listener net.Listener) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		go s.serveConn(conn)
+	}
+}
+
+// serveConn answers the requests of the session on [conn] until it's idle for
+// [sessionTimeout] or fails, and closes it
+func (s *Server) serveConn(conn net.Conn) {
+	defer conn.Close()
+
+	dec := json.NewDecoder(conn)
+	enc := json.NewEncoder(conn)
+
+	// The user's password is only checked on login, so every later request is
+	// answered by the signer of the logged in user
+	var signer snow.Signer
+	for {
+		if err := conn.SetDeadline(time.Now().Add(sessionTimeout)); err != nil {
+			s.log.Debug("couldn't set the deadline of a signer session: %s", err)
+			return
+		}
+		req := &Request{}
+		if err := dec.Decode(req); err != nil {
+			if err != io.EOF {
+				s.log.Debug("couldn't read a signer request: %s", err)
+			}
+			return
+		}
+
+		resp, err := s.handle(req, &signer)
+		if err != nil {
+			s.log.Debug("signer request %q failed: %s", req.Method, err)
+			resp = &Response{Error: err.Error()}
+		}
+		if err := enc.Encode(resp); err != nil {
+			s.log.Debug("couldn't write a signer response: %s", err)
+			return
+		}
+
+		// A session that couldn't log in is over
+		if signer == nil {
+			return
+		}
+	}
+}
+
+// handle returns the response to [req]. [signer] is the signer of the session,
+// which is set by a successful login.
+func (s *Server) handle(req *Request, signer *snow.Signer) (*Response, error) {
+	switch {
+	case req.Method == LoginMethod && *signer != nil:
+		return nil, errLoggedIn
+	case req.Method == LoginMethod:
+		sgnr, err := s.backend.GetSigner(req.Username, req.Password)
+		if err != nil {
+			return nil, err
+		}
+		*signer = sgnr
+		return &Response{}, nil
+	case *signer == nil:
+		return nil, errNotLoggedIn
+	case req.Method == AddressesMethod:
+		addrs, err := (*signer).Addresses()
+		if err != nil {
+			return nil, err
+		}
+		return &Response{Addresses: addrs}, nil
+	case req.Method != SignHashMethod:
+		return nil, fmt.Errorf("%w %q", errUnknownMethod, req.Method)
+	case req.Address.IsZero():
+		return nil, errNoAddress
+	case len(req.Hash.Bytes) != hashing.HashLen:
+		return nil, errInvalidHash
+	}
+	sig, err := (*signer).SignHash(req.Address, req.Hash.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	return &Response{Signature: formatting.CB58{Bytes: sig}}, nil
+}
@@ -0,0 +1,273 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package signer
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+// serve [backend] on a unix socket in a temporary directory, and return a
+// client of it. The returned function stops the server.
+func serve(t *testing.T, backend snow.RemoteSigner) (*Client, func()) {
+	dir, err := ioutil.TempDir("", "gecko-signer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "signer.sock")
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	go NewServer(logging.NoLog{}, backend).Serve(listener)
+	return NewClient(path), func() {
+		listener.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func newKey(t *testing.T) *crypto.PrivateKeySECP256K1R {
+	factory := crypto.FactorySECP256K1R{}
+	sk, err := factory.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sk.(*crypto.PrivateKeySECP256K1R)
+}
+
+func newKeychain() *Keychain { return NewKeychain(logging.NoLog{}, memdb.New()) }
+
+// countingBackend counts the logins to a keychain
+type countingBackend struct {
+	*Keychain
+	logins uint32
+}
+
+func (b *countingBackend) GetSigner(username, password string) (snow.Signer, error) {
+	atomic.AddUint32(&b.logins, 1)
+	return b.Keychain.GetSigner(username, password)
+}
+
+func TestSignerRoundTrip(t *testing.T) {
+	key0 := newKey(t)
+	key1 := newKey(t)
+	backend := newKeychain()
+	if err := backend.AddKey("bob", "launch", key0); err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.AddKey("bob", "launch", key1); err != nil {
+		t.Fatal(err)
+	}
+	client, stop := serve(t, backend)
+	defer stop()
+
+	signer, err := client.GetSigner("bob", "launch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addrs, err := signer.Addresses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(addrs) != 2 {
+		t.Fatalf("Wrong number of addresses. Expected: 2 ; Returned: %d", len(addrs))
+	}
+	addrSet := ids.ShortSet{}
+	addrSet.Add(addrs...)
+	if addr := key0.PublicKey().Address(); !addrSet.Contains(addr) {
+		t.Fatalf("Missing address %s", addr)
+	}
+	if addr := key1.PublicKey().Address(); !addrSet.Contains(addr) {
+		t.Fatalf("Missing address %s", addr)
+	}
+
+	hash := hashing.ComputeHash256([]byte{1, 2, 3})
+	sig, err := signer.SignHash(addrs[1], hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	factory := crypto.FactorySECP256K1R{}
+	pk, err := factory.RecoverHashPublicKey(hash, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr := pk.Address(); !addr.Equals(addrs[1]) {
+		t.Fatalf("Signed by the wrong key. Expected: %s ; Returned: %s", addrs[1], addr)
+	}
+}
+
+func TestSignerWrongPassword(t *testing.T) {
+	backend := newKeychain()
+	if err := backend.AddKey("bob", "launch", newKey(t)); err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.AddKey("bob", "wrong", newKey(t)); err == nil {
+		t.Fatalf("Shouldn't have added a key with the wrong password")
+	}
+	client, stop := serve(t, backend)
+	defer stop()
+
+	if _, err := client.GetSigner("bob", "wrong"); err == nil {
+		t.Fatalf("Should have failed because the password is wrong")
+	}
+	if _, err := client.GetSigner("alice", "launch"); err == nil {
+		t.Fatalf("Should have failed because the user doesn't exist")
+	}
+}
+
+func TestSignerUnknownAddress(t *testing.T) {
+	backend := newKeychain()
+	if err := backend.AddKey("bob", "launch", newKey(t)); err != nil {
+		t.Fatal(err)
+	}
+	client, stop := serve(t, backend)
+	defer stop()
+
+	signer, err := client.GetSigner("bob", "launch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := hashing.ComputeHash256([]byte{1, 2, 3})
+	if _, err := signer.SignHash(newKey(t).PublicKey().Address(), hash); err == nil {
+		t.Fatalf("Should have failed because the user doesn't control the address")
+	}
+	if _, err := signer.SignHash(ids.ShortID{}, hash); err == nil {
+		t.Fatalf("Should have failed because no address was provided")
+	}
+}
+
+func TestSignerInvalidHash(t *testing.T) {
+	key := newKey(t)
+	backend := newKeychain()
+	if err := backend.AddKey("bob", "launch", key); err != nil {
+		t.Fatal(err)
+	}
+	client, stop := serve(t, backend)
+	defer stop()
+
+	signer, err := client.GetSigner("bob", "launch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := signer.SignHash(key.PublicKey().Address(), []byte{1, 2, 3}); err == nil {
+		t.Fatalf("Should have refused to sign something that isn't a hash")
+	}
+}
+
+func TestServerUnknownMethod(t *testing.T) {
+	backend := newKeychain()
+	if err := backend.AddKey("bob", "launch", newKey(t)); err != nil {
+		t.Fatal(err)
+	}
+	client, stop := serve(t, backend)
+	defer stop()
+
+	signer, err := client.GetSigner("bob", "launch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := signer.(*remoteSigner).call(&Request{Method: "exportKey"}); err == nil {
+		t.Fatalf("Should have failed because the method doesn't exist")
+	}
+}
+
+func TestServerRequiresLogin(t *testing.T) {
+	client, stop := serve(t, newKeychain())
+	defer stop()
+
+	conn, err := net.Dial("unix", client.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	s := &remoteSigner{
+		conn: conn,
+		enc:  json.NewEncoder(conn),
+		dec:  json.NewDecoder(conn),
+	}
+	if _, err := s.Addresses(); err == nil {
+		t.Fatalf("Should have failed because the session isn't logged in")
+	}
+}
+
+func TestSignerLogsInOnce(t *testing.T) {
+	key := newKey(t)
+	backend := &countingBackend{Keychain: newKeychain()}
+	if err := backend.AddKey("bob", "launch", key); err != nil {
+		t.Fatal(err)
+	}
+	client, stop := serve(t, backend)
+	defer stop()
+
+	signer, err := client.GetSigner("bob", "launch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := byte(0); i < 3; i++ {
+		hash := hashing.ComputeHash256([]byte{i})
+		if _, err := signer.SignHash(key.PublicKey().Address(), hash); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := signer.Addresses(); err != nil {
+		t.Fatal(err)
+	}
+	if logins := atomic.LoadUint32(&backend.logins); logins != 1 {
+		t.Fatalf("Wrong number of logins. Expected: 1 ; Returned: %d", logins)
+	}
+}
+
+func TestKeychainPersists(t *testing.T) {
+	key := newKey(t)
+	db := memdb.New()
+	if err := NewKeychain(logging.NoLog{}, db).AddKey("bob", "launch", key); err != nil {
+		t.Fatal(err)
+	}
+
+	// A signer restarted on the same database still holds the key
+	backend := NewKeychain(logging.NoLog{}, db)
+	if _, err := backend.GetSigner("bob", "wrong"); err == nil {
+		t.Fatalf("Should have failed because the password is wrong")
+	}
+	signer, err := backend.GetSigner("bob", "launch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := hashing.ComputeHash256([]byte{1, 2, 3})
+	sig, err := signer.SignHash(key.PublicKey().Address(), hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	factory := crypto.FactorySECP256K1R{}
+	pk, err := factory.RecoverHashPublicKey(hash, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr := key.PublicKey().Address(); !pk.Address().Equals(addr) {
+		t.Fatalf("Signed by the wrong key. Expected: %s ; Returned: %s", addr, pk.Address())
+	}
+
+	// The key isn't stored in the clear
+	it := db.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		if bytes.Contains(it.Value(), key.Bytes()) {
+			t.Fatalf("The key was stored unencrypted")
+		}
+	}
+}
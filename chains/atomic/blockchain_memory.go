@@ -0,0 +1,26 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package atomic
+
+import (
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+)
+
+// BlockchainMemory is the shared memory, as seen by a single chain
+type BlockchainMemory struct {
+	blockchainID ids.ID
+	memory       *Memory
+}
+
+// GetDatabase returns, and locks, the database this chain shares with the chain
+// [id]
+func (bm *BlockchainMemory) GetDatabase(id ids.ID) database.Database {
+	return bm.memory.GetDatabase(bm.memory.sharedID(id, bm.blockchainID))
+}
+
+// ReleaseDatabase unlocks the database this chain shares with the chain [id]
+func (bm *BlockchainMemory) ReleaseDatabase(id ids.ID) {
+	bm.memory.ReleaseDatabase(bm.memory.sharedID(id, bm.blockchainID))
+}
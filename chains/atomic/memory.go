@@ -0,0 +1,105 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package atomic
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+type rcLock struct {
+	lock  sync.Mutex
+	count int
+}
+
+// Memory is the memory shared by the chains running on this node. Every pair
+// of chains has its own database, which both chains are able to read and
+// write. A chain must hold the database while modifying it, so that value
+// moved between two chains is never observed in both, or in neither, of them.
+type Memory struct {
+	lock  sync.Mutex
+	log   logging.Logger
+	locks map[[32]byte]*rcLock
+	db    database.Database
+}
+
+// Initialize the memory, which is persisted in [db]
+func (m *Memory) Initialize(log logging.Logger, db database.Database) {
+	m.log = log
+	m.locks = make(map[[32]byte]*rcLock)
+	m.db = db
+}
+
+// NewBlockchainMemory returns the view of this memory that the chain
+// [blockchainID] has
+func (m *Memory) NewBlockchainMemory(blockchainID ids.ID) *BlockchainMemory {
+	return &BlockchainMemory{
+		blockchainID: blockchainID,
+		memory:       m,
+	}
+}
+
+// GetDatabase returns, and locks, the database with ID [sharedID]. The
+// database must be released with ReleaseDatabase once it is no longer being
+// used.
+func (m *Memory) GetDatabase(sharedID ids.ID) database.Database {
+	lock := m.makeLock(sharedID)
+	lock.Lock()
+	return prefixdb.New(sharedID.Bytes(), m.db)
+}
+
+// ReleaseDatabase unlocks the database with ID [sharedID]
+func (m *Memory) ReleaseDatabase(sharedID ids.ID) {
+	lock := m.releaseLock(sharedID)
+	lock.Unlock()
+}
+
+func (m *Memory) makeLock(sharedID ids.ID) *sync.Mutex {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	key := sharedID.Key()
+	rc, exists := m.locks[key]
+	if !exists {
+		rc = &rcLock{}
+		m.locks[key] = rc
+	}
+	rc.count++
+	return &rc.lock
+}
+
+func (m *Memory) releaseLock(sharedID ids.ID) *sync.Mutex {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	key := sharedID.Key()
+	rc, exists := m.locks[key]
+	if !exists {
+		m.log.Error("released the shared database %s, which wasn't held", sharedID)
+		return &sync.Mutex{}
+	}
+	rc.count--
+	if rc.count == 0 {
+		delete(m.locks, key)
+	}
+	return &rc.lock
+}
+
+// sharedID returns the ID of the database shared by the chains [id1] and
+// [id2]. The ID doesn't depend on the order of the chains.
+func (m *Memory) sharedID(id1, id2 ids.ID) ids.ID {
+	if bytes.Compare(id1.Bytes(), id2.Bytes()) > 0 {
+		id1, id2 = id2, id1
+	}
+	pair := make([]byte, 0, 2*hashing.HashLen)
+	pair = append(pair, id1.Bytes()...)
+	pair = append(pair, id2.Bytes()...)
+	return ids.NewID(hashing.ComputeHash256Array(pair))
+}
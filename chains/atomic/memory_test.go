@@ -0,0 +1,101 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package atomic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+var (
+	blockchainID0 = ids.Empty.Prefix(0)
+	blockchainID1 = ids.Empty.Prefix(1)
+	blockchainID2 = ids.Empty.Prefix(2)
+)
+
+func TestMemorySharedID(t *testing.T) {
+	m := Memory{}
+	m.Initialize(logging.NoLog{}, memdb.New())
+
+	sharedID0 := m.sharedID(blockchainID0, blockchainID1)
+	sharedID1 := m.sharedID(blockchainID1, blockchainID0)
+	if !sharedID0.Equals(sharedID1) {
+		t.Fatalf("The shared ID shouldn't depend on the order of the chains")
+	}
+
+	sharedID2 := m.sharedID(blockchainID0, blockchainID2)
+	if sharedID0.Equals(sharedID2) {
+		t.Fatalf("Different pairs of chains should have different shared IDs")
+	}
+}
+
+func TestBlockchainMemorySharedDatabase(t *testing.T) {
+	m := Memory{}
+	m.Initialize(logging.NoLog{}, memdb.New())
+
+	bm0 := m.NewBlockchainMemory(blockchainID0)
+	bm1 := m.NewBlockchainMemory(blockchainID1)
+	bm2 := m.NewBlockchainMemory(blockchainID2)
+
+	db := bm0.GetDatabase(blockchainID1)
+	if err := db.Put([]byte{1}, []byte{2}); err != nil {
+		t.Fatal(err)
+	}
+	bm0.ReleaseDatabase(blockchainID1)
+
+	db = bm1.GetDatabase(blockchainID0)
+	if value, err := db.Get([]byte{1}); err != nil {
+		t.Fatal(err)
+	} else if len(value) != 1 || value[0] != 2 {
+		t.Fatalf("Both chains should see the same database")
+	}
+	bm1.ReleaseDatabase(blockchainID0)
+
+	db = bm2.GetDatabase(blockchainID0)
+	if has, err := db.Has([]byte{1}); err != nil {
+		t.Fatal(err)
+	} else if has {
+		t.Fatalf("Another pair of chains shouldn't see the database")
+	}
+	bm2.ReleaseDatabase(blockchainID0)
+}
+
+func TestBlockchainMemoryLocks(t *testing.T) {
+	m := Memory{}
+	m.Initialize(logging.NoLog{}, memdb.New())
+
+	bm0 := m.NewBlockchainMemory(blockchainID0)
+	bm1 := m.NewBlockchainMemory(blockchainID1)
+
+	bm0.GetDatabase(blockchainID1)
+
+	acquired := make(chan struct{})
+	go func() {
+		bm1.GetDatabase(blockchainID0)
+		close(acquired)
+		bm1.ReleaseDatabase(blockchainID0)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatalf("The database shouldn't be acquired while it is held")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Another pair of chains shouldn't be blocked by the held database
+	bm0.GetDatabase(blockchainID2)
+	bm0.ReleaseDatabase(blockchainID2)
+
+	bm0.ReleaseDatabase(blockchainID1)
+
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatalf("The database should be acquired once it is released")
+	}
+}
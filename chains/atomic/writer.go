@@ -0,0 +1,21 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package atomic
+
+import (
+	"github.com/ava-labs/gecko/database"
+)
+
+// WriteAll writes [baseBatch] and [batches] in a single, atomic write. Every
+// batch must be built on top of the same database as [baseBatch], as a chain's
+// database and the memory it shares with other chains are.
+func WriteAll(baseBatch database.Batch, batches ...database.Batch) error {
+	baseBatch = baseBatch.Inner()
+	for _, batch := range batches {
+		if err := batch.Inner().Replay(baseBatch); err != nil {
+			return err
+		}
+	}
+	return baseBatch.Write()
+}
@@ -0,0 +1,58 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package atomic
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/database/versiondb"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+func TestWriteAll(t *testing.T) {
+	baseDB := memdb.New()
+	chainDB := versiondb.New(prefixdb.New([]byte("chain"), baseDB))
+
+	m := Memory{}
+	m.Initialize(logging.NoLog{}, prefixdb.New([]byte("shared memory"), baseDB))
+	sm := m.NewBlockchainMemory(blockchainID0)
+	smDB := versiondb.New(sm.GetDatabase(blockchainID1))
+	defer sm.ReleaseDatabase(blockchainID1)
+
+	key := []byte("key")
+	chainValue := []byte("chain")
+	sharedValue := []byte("shared")
+	if err := chainDB.Put(key, chainValue); err != nil {
+		t.Fatal(err)
+	}
+	if err := smDB.Put(key, sharedValue); err != nil {
+		t.Fatal(err)
+	}
+
+	batch, err := chainDB.CommitBatch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	smBatch, err := smDB.CommitBatch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteAll(batch, smBatch); err != nil {
+		t.Fatal(err)
+	}
+
+	if value, err := chainDB.GetDatabase().Get(key); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(value, chainValue) {
+		t.Fatalf("Wrong value. Expected: 0x%x ; Returned: 0x%x", chainValue, value)
+	}
+	if value, err := smDB.GetDatabase().Get(key); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(value, sharedValue) {
+		t.Fatalf("Wrong value. Expected: 0x%x ; Returned: 0x%x", sharedValue, value)
+	}
+}
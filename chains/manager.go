@@ -4,11 +4,14 @@
 package chains
 
 import (
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/ava-labs/gecko/api"
 	"github.com/ava-labs/gecko/api/keystore"
+	"github.com/ava-labs/gecko/chains/atomic"
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/database/prefixdb"
 	"github.com/ava-labs/gecko/ids"
@@ -36,8 +39,15 @@ import (
 )
 
 const (
-	defaultChannelSize = 1000
-	requestTimeout     = 2 * time.Second
+	requestTimeout = 2 * time.Second
+)
+
+var (
+	errUnknownChain = errors.New("unknown chain")
+
+	// bootstrappedKey is put in a chain's database once the chain finished
+	// bootstrapping for the first time
+	bootstrappedKey = []byte("bootstrapped")
 )
 
 // Manager manages the chains running on this node.
@@ -57,6 +67,11 @@ type Manager interface {
 	// Create a chain now
 	ForceCreateChain(ChainParameters)
 
+	// Create the chains of a subnet that weren't created because this node
+	// wasn't validating the subnet, if it is now. Called whenever the
+	// subnet's validator set changes.
+	ValidatorsChanged(ids.ID)
+
 	// Add a registrant [r]. Every time a chain is
 	// created, [r].RegisterChain([new chain]) is called
 	AddRegistrant(Registrant)
@@ -73,6 +88,9 @@ type Manager interface {
 	// Add an alias to a chain
 	Alias(ids.ID, string) error
 
+	// Return how far along bootstrapping a chain is
+	BootstrapProgress(ids.ID) (common.ProgressStatus, error)
+
 	Shutdown()
 }
 
@@ -84,7 +102,14 @@ type ChainParameters struct {
 	VMAlias     string   // The ID of the vm this chain is running
 	FxAliases   []string // The IDs of the feature extensions this chain is running
 
-	CustomBeacons validators.Set // Should only be set if the default beacons can't be used.
+	// The chain is bootstrapped from [CustomBeacons], rather than from a sample
+	// of its validators, the first time this node syncs it. Should only be set
+	// if the validators can't be sampled until the chain is synced.
+	CustomBeacons validators.Set
+
+	// If true, the chain is always bootstrapped from [CustomBeacons], e.g.
+	// because the validators' stake isn't known when staking is disabled
+	AlwaysUseCustomBeacons bool
 }
 
 type manager struct {
@@ -102,6 +127,10 @@ type manager struct {
 	sender          sender.ExternalSender // Sends consensus messages to other validators
 	timeoutManager  *timeout.Manager      // Manages request timeouts when sending messages to other validators
 	consensusParams avacon.Parameters     // The consensus parameters (alpha, beta, etc.) for new chains
+	bootstrapAlpha  float64               // The fraction of stake that must agree on a container to bootstrap it
+	maxRetries      int                   // The number of times a chain samples the accepted frontier again when bootstrapping
+	bufferSize      int                   // The number of messages each chain buffers
+	maxPeerMessages int                   // The number of messages from each peer each chain buffers
 	validators      validators.Manager    // Validators validating on this chain
 	registrants     []Registrant          // Those notified when a chain is created
 	nodeID          ids.ShortID           // The ID of this node
@@ -109,9 +138,21 @@ type manager struct {
 	awaiter         Awaiter               // Waits for required connections before running bootstrapping
 	server          *api.Server           // Handles HTTP API calls
 	keystore        *keystore.Keystore
+	sharedMemory    *atomic.Memory
+	remoteSigner    snow.RemoteSigner // nil if keys are held in the keystore
 
 	unblocked     bool
 	blockedChains []ChainParameters
+
+	// Key: The ID of a subnet
+	// Value: The chains of the subnet, which are created once this node
+	//        validates the subnet
+	unvalidatedChains map[[32]byte][]ChainParameters
+
+	// Key: The ID of a chain
+	// Value: The progress of bootstrapping the chain
+	progressLock sync.RWMutex
+	progress     map[[32]byte]*common.Progress
 }
 
 // New returns a new Manager where:
@@ -129,12 +170,18 @@ func New(
 	router router.Router,
 	sender sender.ExternalSender,
 	consensusParams avacon.Parameters,
+	bootstrapAlpha float64,
+	maxRetries int,
+	bufferSize int,
+	maxPeerMessages int,
 	validators validators.Manager,
 	nodeID ids.ShortID,
 	networkID uint32,
 	awaiter Awaiter,
 	server *api.Server,
 	keystore *keystore.Keystore,
+	sharedMemory *atomic.Memory,
+	remoteSigner snow.RemoteSigner,
 ) Manager {
 	timeoutManager := timeout.Manager{}
 	timeoutManager.Initialize(requestTimeout)
@@ -153,12 +200,21 @@ func New(
 		sender:          sender,
 		timeoutManager:  &timeoutManager,
 		consensusParams: consensusParams,
+		bootstrapAlpha:  bootstrapAlpha,
+		maxRetries:      maxRetries,
+		bufferSize:      bufferSize,
+		maxPeerMessages: maxPeerMessages,
 		validators:      validators,
 		nodeID:          nodeID,
 		networkID:       networkID,
 		awaiter:         awaiter,
 		server:          server,
 		keystore:        keystore,
+		sharedMemory:    sharedMemory,
+		remoteSigner:    remoteSigner,
+		progress:        make(map[[32]byte]*common.Progress),
+
+		unvalidatedChains: make(map[[32]byte][]ChainParameters),
 	}
 	m.Initialize()
 	return m
@@ -192,6 +248,25 @@ func (m *manager) ForceCreateChain(chain ChainParameters) {
 		return
 	}
 
+	// The validators of this blockchain
+	validators, ok := m.validators.GetValidatorSet(chain.SubnetID)
+	defaultSubnet := chain.SubnetID.Equals(ids.Empty)
+	if !ok && defaultSubnet {
+		m.log.Error("couldn't get validator set of the default subnet")
+		return
+	}
+
+	// Every node runs the chains of the default subnet. Only the validators of
+	// any other subnet run its chains, so the chain is created once this node
+	// starts validating the subnet. A subnet that has never had validators
+	// doesn't have a validator set yet.
+	if !defaultSubnet && (!ok || !validators.Contains(m.nodeID)) {
+		m.log.Info("not validating subnet %s. Chain %s will be created once this node validates the subnet.", chain.SubnetID, chain.ID)
+		key := chain.SubnetID.Key()
+		m.unvalidatedChains[key] = append(m.unvalidatedChains[key], chain)
+		return
+	}
+
 	vmID, err := m.vmManager.Lookup(chain.VMAlias)
 	if err != nil {
 		m.log.Error("error while looking up VM: %s", err)
@@ -246,6 +321,8 @@ func (m *manager) ForceCreateChain(chain ChainParameters) {
 		NodeID:              m.nodeID,
 		HTTP:                m.server,
 		Keystore:            m.keystore.NewBlockchainKeyStore(chain.ID),
+		SharedMemory:        m.sharedMemory.NewBlockchainMemory(chain.ID),
+		RemoteSigner:        m.remoteSigner,
 		BCLookup:            m,
 	}
 	consensusParams := m.consensusParams
@@ -255,15 +332,8 @@ func (m *manager) ForceCreateChain(chain ChainParameters) {
 		consensusParams.Namespace = fmt.Sprintf("gecko_%s", ctx.ChainID)
 	}
 
-	// The validators of this blockchain
-	validators, ok := m.validators.GetValidatorSet(ids.Empty) // TODO: Change argument to chain.SubnetID
-	if !ok {
-		m.log.Error("couldn't get validator set of subnet with ID %s. The subnet may not exist", chain.SubnetID)
-		return
-	}
-
 	beacons := validators
-	if chain.CustomBeacons != nil {
+	if chain.CustomBeacons != nil && (chain.AlwaysUseCustomBeacons || !m.bootstrappedBefore(chain.ID)) {
 		beacons = chain.CustomBeacons
 	}
 
@@ -311,6 +381,23 @@ func (m *manager) ForceCreateChain(chain ChainParameters) {
 // Implements Manager.AddRegistrant
 func (m *manager) AddRegistrant(r Registrant) { m.registrants = append(m.registrants, r) }
 
+// Implements Manager.ValidatorsChanged
+func (m *manager) ValidatorsChanged(subnetID ids.ID) {
+	key := subnetID.Key()
+	unvalidated := m.unvalidatedChains[key]
+	if len(unvalidated) == 0 {
+		return
+	}
+	validators, ok := m.validators.GetValidatorSet(subnetID)
+	if !ok || !validators.Contains(m.nodeID) {
+		return
+	}
+	delete(m.unvalidatedChains, key)
+	for _, chain := range unvalidated {
+		m.ForceCreateChain(chain)
+	}
+}
+
 func (m *manager) unblockChains() {
 	m.unblocked = true
 	blocked := m.blockedChains
@@ -350,7 +437,7 @@ func (m *manager) createAvalancheChain(
 
 	// The channel through which a VM may send messages to the consensus engine
 	// VM uses this channel to notify engine that a block is ready to be made
-	msgChan := make(chan common.Message, defaultChannelSize)
+	msgChan := make(chan common.Message, m.bufferSize)
 
 	if err := vm.Initialize(ctx, vmDB, genesisData, msgChan, fxs); err != nil {
 		return err
@@ -376,14 +463,18 @@ func (m *manager) createAvalancheChain(
 		},
 	}
 
+	progress := &common.Progress{}
 	engine.Initialize(avaeng.Config{
 		BootstrapConfig: avaeng.BootstrapConfig{
 			Config: common.Config{
 				Context:    ctx,
 				Validators: validators,
 				Beacons:    beacons,
-				Alpha:      (beacons.Len() + 1) / 2,
+				SampleK:    consensusParams.K,
+				Alpha:      m.bootstrapAlpha,
+				MaxRetries: m.maxRetries,
 				Sender:     &sender,
+				Progress:   progress,
 			},
 			VtxBlocked: vtxBlocker,
 			TxBlocked:  txBlocker,
@@ -396,7 +487,20 @@ func (m *manager) createAvalancheChain(
 
 	// Asynchronously passes messages from the network to the consensus engine
 	handler := &handler.Handler{}
-	handler.Initialize(&engine, msgChan, defaultChannelSize)
+	err = handler.Initialize(
+		&engine,
+		validators,
+		msgChan,
+		m.bufferSize,
+		m.maxPeerMessages,
+		consensusParams.Namespace,
+		consensusParams.Metrics,
+	)
+	if err != nil {
+		return err
+	}
+
+	m.setProgress(ctx.ChainID, progress)
 
 	// Allows messages to be routed to the new chain
 	m.chainRouter.AddChain(handler)
@@ -443,7 +547,7 @@ func (m *manager) createSnowmanChain(
 
 	// The channel through which a VM may send messages to the consensus engine
 	// VM uses this channel to notify engine that a block is ready to be made
-	msgChan := make(chan common.Message, defaultChannelSize)
+	msgChan := make(chan common.Message, m.bufferSize)
 
 	// Initialize the VM
 	if err := vm.Initialize(ctx, vmDB, genesisData, msgChan, fxs); err != nil {
@@ -454,20 +558,31 @@ func (m *manager) createSnowmanChain(
 	sender := sender.Sender{}
 	sender.Initialize(ctx, m.sender, m.chainRouter, m.timeoutManager)
 
+	// Once the chain is bootstrapped, it's recorded so that the chain isn't
+	// bootstrapped from custom beacons again, and the other chains are created
+	bootstrapped := func() {
+		m.markBootstrapped(ctx, db)
+		m.unblockChains()
+	}
+
 	// The engine handles consensus
 	engine := smeng.Transitive{}
+	progress := &common.Progress{}
 	engine.Initialize(smeng.Config{
 		BootstrapConfig: smeng.BootstrapConfig{
 			Config: common.Config{
 				Context:    ctx,
 				Validators: validators,
 				Beacons:    beacons,
-				Alpha:      (beacons.Len() + 1) / 2,
+				SampleK:    consensusParams.K,
+				Alpha:      m.bootstrapAlpha,
+				MaxRetries: m.maxRetries,
 				Sender:     &sender,
+				Progress:   progress,
 			},
 			Blocked:      blocked,
 			VM:           vm,
-			Bootstrapped: m.unblockChains,
+			Bootstrapped: bootstrapped,
 		},
 		Params:    consensusParams,
 		Consensus: &smcon.Topological{},
@@ -475,7 +590,20 @@ func (m *manager) createSnowmanChain(
 
 	// Asynchronously passes messages from the network to the consensus engine
 	handler := &handler.Handler{}
-	handler.Initialize(&engine, msgChan, defaultChannelSize)
+	err = handler.Initialize(
+		&engine,
+		validators,
+		msgChan,
+		m.bufferSize,
+		m.maxPeerMessages,
+		consensusParams.Namespace,
+		consensusParams.Metrics,
+	)
+	if err != nil {
+		return err
+	}
+
+	m.setProgress(ctx.ChainID, progress)
 
 	// Allow incoming messages to be routed to the new chain
 	m.chainRouter.AddChain(handler)
@@ -497,6 +625,45 @@ func (m *manager) createSnowmanChain(
 	return nil
 }
 
+// bootstrappedBefore returns true if the chain [chainID] finished
+// bootstrapping before, e.g. before this node restarted
+func (m *manager) bootstrappedBefore(chainID ids.ID) bool {
+	db := prefixdb.New(chainID.Bytes(), m.db)
+	bootstrapped, err := db.Has(bootstrappedKey)
+	if err != nil {
+		m.log.Error("couldn't check whether chain %s bootstrapped before: %s", chainID, err)
+	}
+	return bootstrapped
+}
+
+// markBootstrapped records in the chain's database [db] that the chain
+// finished bootstrapping
+func (m *manager) markBootstrapped(ctx *snow.Context, db database.Database) {
+	if err := db.Put(bootstrappedKey, []byte{}); err != nil {
+		ctx.Log.Error("couldn't record that the chain finished bootstrapping: %s", err)
+	}
+}
+
+// setProgress sets the progress of bootstrapping the chain [chainID]
+func (m *manager) setProgress(chainID ids.ID, progress *common.Progress) {
+	m.progressLock.Lock()
+	defer m.progressLock.Unlock()
+
+	m.progress[chainID.Key()] = progress
+}
+
+// BootstrapProgress returns how far along bootstrapping the chain [chainID] is
+func (m *manager) BootstrapProgress(chainID ids.ID) (common.ProgressStatus, error) {
+	m.progressLock.RLock()
+	defer m.progressLock.RUnlock()
+
+	progress, ok := m.progress[chainID.Key()]
+	if !ok {
+		return common.ProgressStatus{}, errUnknownChain
+	}
+	return progress.Status(), nil
+}
+
 // Shutdown stops all the chains
 func (m *manager) Shutdown() { m.chainRouter.Shutdown() }
 
@@ -0,0 +1,103 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/validators"
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/vms"
+)
+
+var errNoVM = errors.New("no vm")
+
+// startRecorder is a VM manager that records the VMs chains are started with.
+// It doesn't know any VM, so chains stop being created once they look theirs
+// up.
+type startRecorder struct {
+	vms.Manager
+	started []string
+}
+
+func (r *startRecorder) Lookup(alias string) (ids.ID, error) {
+	r.started = append(r.started, alias)
+	return ids.ID{}, errNoVM
+}
+
+func newTestManager(nodeID ids.ShortID) (*manager, *startRecorder) {
+	recorder := &startRecorder{}
+	vdrs := validators.NewManager()
+	vdrs.PutValidatorSet(ids.Empty, validators.NewSet())
+	m := &manager{
+		log:               logging.NoLog{},
+		vmManager:         recorder,
+		validators:        vdrs,
+		nodeID:            nodeID,
+		unvalidatedChains: make(map[[32]byte][]ChainParameters),
+	}
+	m.Initialize()
+	return m, recorder
+}
+
+func TestCreateChainDefaultSubnet(t *testing.T) {
+	m, recorder := newTestManager(ids.NewShortID([20]byte{1}))
+
+	// Every node runs the chains of the default subnet
+	m.ForceCreateChain(ChainParameters{
+		ID:       ids.NewID([32]byte{1}),
+		SubnetID: ids.Empty,
+		VMAlias:  "default",
+	})
+	if len(recorder.started) != 1 || recorder.started[0] != "default" {
+		t.Fatalf("Should have started the chain")
+	}
+}
+
+func TestCreateChainNewSubnet(t *testing.T) {
+	nodeID := ids.NewShortID([20]byte{1})
+	m, recorder := newTestManager(nodeID)
+
+	// The subnet was just created, so it has never had a validator set
+	subnetID := ids.NewID([32]byte{2})
+	m.ForceCreateChain(ChainParameters{
+		ID:       ids.NewID([32]byte{1}),
+		SubnetID: subnetID,
+		VMAlias:  "subnet",
+	})
+	if len(recorder.started) != 0 {
+		t.Fatalf("Shouldn't have started the chain of a subnet this node doesn't validate")
+	}
+	m.ValidatorsChanged(subnetID)
+	if len(recorder.started) != 0 {
+		t.Fatalf("Shouldn't have started the chain of a subnet without validators")
+	}
+
+	// Another node starts validating the subnet
+	vdrs := validators.NewSet()
+	vdrs.Add(validators.NewValidator(ids.NewShortID([20]byte{2}), 1))
+	m.validators.PutValidatorSet(subnetID, vdrs)
+	m.ValidatorsChanged(subnetID)
+	if len(recorder.started) != 0 {
+		t.Fatalf("Shouldn't have started the chain of a subnet this node doesn't validate")
+	}
+
+	// This node starts validating the subnet
+	vdrs.Add(validators.NewValidator(nodeID, 1))
+	m.ValidatorsChanged(subnetID)
+	if len(recorder.started) != 1 || recorder.started[0] != "subnet" {
+		t.Fatalf("Should have started the chain once this node validates the subnet")
+	}
+	if len(m.unvalidatedChains) != 0 {
+		t.Fatalf("Shouldn't still be waiting to create the chain")
+	}
+
+	// The chain is only started once
+	m.ValidatorsChanged(subnetID)
+	if len(recorder.started) != 1 {
+		t.Fatalf("Should only have started the chain once")
+	}
+}
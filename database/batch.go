@@ -23,6 +23,12 @@ type Batch interface {
 
 	// Replay replays the batch contents.
 	Replay(w KeyValueWriter) error
+
+	// Inner returns the batch of the database this batch's database is built
+	// on top of, or the batch itself if its database isn't built on another
+	// database. Writing the inner batch writes this batch, along with any
+	// other batch with the same inner batch.
+	Inner() Batch
 }
 
 // Batcher wraps the NewBatch method of a backing data store.
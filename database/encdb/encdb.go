@@ -24,10 +24,15 @@ type Database struct {
 	db     database.Database
 }
 
-// New returns a new encrypted database
+// New returns a new encrypted database whose key is the hash of [password]
 func New(password []byte, db database.Database) (*Database, error) {
-	h := hashing.ComputeHash256(password)
-	aead, err := chacha20poly1305.NewX(h)
+	return NewWithKey(hashing.ComputeHash256(password), db)
+}
+
+// NewWithKey returns a new encrypted database whose key is [key], which must
+// be chacha20poly1305.KeySize bytes
+func NewWithKey(key []byte, db database.Database) (*Database, error) {
+	aead, err := chacha20poly1305.NewX(key)
 	if err != nil {
 		return nil, err
 	}
@@ -204,6 +209,9 @@ func (b *batch) Reset() {
 	b.Batch.Reset()
 }
 
+// Inner returns the batch of the database this database is encrypting
+func (b *batch) Inner() database.Batch { return b.Batch.Inner() }
+
 // Replay replays the batch contents.
 func (b *batch) Replay(w database.KeyValueWriter) error {
 	for _, keyvalue := range b.writes {
@@ -4,10 +4,14 @@
 package encdb
 
 import (
+	"bytes"
 	"testing"
 
+	"golang.org/x/crypto/chacha20poly1305"
+
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/utils/hashing"
 )
 
 func TestInterface(t *testing.T) {
@@ -22,3 +26,38 @@ func TestInterface(t *testing.T) {
 		test(t, db)
 	}
 }
+
+func TestNewWithKey(t *testing.T) {
+	pw := []byte("lol totally a secure password")
+	unencryptedDB := memdb.New()
+	db, err := New(pw, unencryptedDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Put([]byte("hello"), []byte("world")); err != nil {
+		t.Fatal(err)
+	}
+
+	// The password's database is keyed by the password's hash
+	keyedDB, err := NewWithKey(hashing.ComputeHash256(pw), unencryptedDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val, err := keyedDB.Get([]byte("hello")); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(val, []byte("world")) {
+		t.Fatalf("Wrong value. Expected: %s ; Returned: %s", "world", val)
+	}
+
+	wrongDB, err := NewWithKey(make([]byte, chacha20poly1305.KeySize), unencryptedDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wrongDB.Get([]byte("hello")); err == nil {
+		t.Fatalf("Shouldn't have decrypted the value with the wrong key")
+	}
+
+	if _, err := NewWithKey([]byte{1, 2, 3}, unencryptedDB); err == nil {
+		t.Fatalf("Should have failed because the key is too short")
+	}
+}
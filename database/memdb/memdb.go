@@ -194,6 +194,9 @@ func (b *batch) Reset() {
 	b.size = 0
 }
 
+// Inner implements the Batch interface
+func (b *batch) Inner() database.Batch { return b }
+
 // Replay implements the Batch interface
 func (b *batch) Replay(w database.KeyValueWriter) error {
 	for _, keyvalue := range b.writes {
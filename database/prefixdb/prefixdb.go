@@ -202,6 +202,9 @@ func (b *batch) Reset() {
 	b.Batch.Reset()
 }
 
+// Inner returns the batch of the database this database is prefixing
+func (b *batch) Inner() database.Batch { return b.Batch.Inner() }
+
 // Replay replays the batch contents.
 func (b *batch) Replay(w database.KeyValueWriter) error {
 	for _, keyvalue := range b.writes {
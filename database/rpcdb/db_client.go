@@ -0,0 +1,219 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcdb
+
+import (
+	"errors"
+	"net/rpc"
+
+	"github.com/ava-labs/gecko/database"
+)
+
+// DatabaseClient is a database that's accessed over net/rpc, through a
+// DatabaseServer
+type DatabaseClient struct{ client *rpc.Client }
+
+// NewClient returns a database that's accessed through [client]
+func NewClient(client *rpc.Client) *DatabaseClient { return &DatabaseClient{client: client} }
+
+// Has implements the Database interface
+func (db *DatabaseClient) Has(key []byte) (bool, error) {
+	reply := HasReply{}
+	err := db.call("DB.Has", &KeyArgs{Key: key}, &reply)
+	return reply.Has, err
+}
+
+// Get implements the Database interface
+func (db *DatabaseClient) Get(key []byte) ([]byte, error) {
+	reply := GetReply{}
+	if err := db.call("DB.Get", &KeyArgs{Key: key}, &reply); err != nil {
+		return nil, err
+	}
+	return reply.Value, nil
+}
+
+// Put implements the Database interface
+func (db *DatabaseClient) Put(key, value []byte) error {
+	return db.call("DB.Put", &PutArgs{Key: key, Value: value}, &struct{}{})
+}
+
+// Delete implements the Database interface
+func (db *DatabaseClient) Delete(key []byte) error {
+	return db.call("DB.Delete", &KeyArgs{Key: key}, &struct{}{})
+}
+
+// NewBatch implements the Database interface
+func (db *DatabaseClient) NewBatch() database.Batch { return &batch{db: db} }
+
+// NewIterator implements the Database interface
+func (db *DatabaseClient) NewIterator() database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+// NewIteratorWithStart implements the Database interface
+func (db *DatabaseClient) NewIteratorWithStart(start []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+// NewIteratorWithPrefix implements the Database interface
+func (db *DatabaseClient) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+// NewIteratorWithStartAndPrefix implements the Database interface
+func (db *DatabaseClient) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	reply := NewIteratorReply{}
+	if err := db.call("DB.NewIterator", &NewIteratorArgs{Start: start, Prefix: prefix}, &reply); err != nil {
+		return &iterator{db: db, exhausted: true, released: true, err: err}
+	}
+	return &iterator{db: db, id: reply.ID}
+}
+
+// Stat implements the Database interface
+func (db *DatabaseClient) Stat(property string) (string, error) {
+	reply := StatReply{}
+	err := db.call("DB.Stat", &StatArgs{Property: property}, &reply)
+	return reply.Stat, err
+}
+
+// Compact implements the Database interface
+func (db *DatabaseClient) Compact(start, limit []byte) error {
+	return db.call("DB.Compact", &CompactArgs{Start: start, Limit: limit}, &struct{}{})
+}
+
+// Close implements the Database interface
+func (db *DatabaseClient) Close() error {
+	return db.call("DB.Close", &struct{}{}, &struct{}{})
+}
+
+// call [method] on the server, and convert the errors the database returned
+// back to the database package's errors
+func (db *DatabaseClient) call(method string, args, reply interface{}) error {
+	return toDatabaseError(db.client.Call(method, args, reply))
+}
+
+// Errors are sent over net/rpc as strings, so the database package's errors
+// must be recovered from their messages. If the server is gone, the database
+// is considered closed.
+func toDatabaseError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case err == rpc.ErrShutdown:
+		return database.ErrClosed
+	case err.Error() == database.ErrClosed.Error():
+		return database.ErrClosed
+	case err.Error() == database.ErrNotFound.Error():
+		return database.ErrNotFound
+	default:
+		return err
+	}
+}
+
+type batch struct {
+	db   *DatabaseClient
+	ops  []BatchOp
+	size int
+}
+
+func (b *batch) Put(key, value []byte) error {
+	b.ops = append(b.ops, BatchOp{
+		Key:   copyBytes(key),
+		Value: copyBytes(value),
+	})
+	b.size += len(value)
+	return nil
+}
+
+func (b *batch) Delete(key []byte) error {
+	b.ops = append(b.ops, BatchOp{
+		Key:    copyBytes(key),
+		Delete: true,
+	})
+	b.size++
+	return nil
+}
+
+func (b *batch) ValueSize() int { return b.size }
+
+func (b *batch) Write() error {
+	return b.db.call("DB.WriteBatch", &WriteBatchArgs{Ops: b.ops}, &struct{}{})
+}
+
+func (b *batch) Reset() {
+	b.ops = b.ops[:0]
+	b.size = 0
+}
+
+func (b *batch) Inner() database.Batch { return b }
+
+func (b *batch) Replay(w database.KeyValueWriter) error {
+	for _, op := range b.ops {
+		if op.Delete {
+			if err := w.Delete(op.Key); err != nil {
+				return err
+			}
+		} else if err := w.Put(op.Key, op.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type iterator struct {
+	db *DatabaseClient
+	id uint64
+
+	// key/value pairs that were fetched from the server but not yet iterated
+	keys, values [][]byte
+	key, value   []byte
+
+	exhausted, released bool
+	err                 error
+}
+
+func (it *iterator) Next() bool {
+	if len(it.keys) == 0 && !it.exhausted {
+		reply := IteratorNextReply{}
+		if err := it.db.call("DB.IteratorNext", &IteratorArgs{ID: it.id}, &reply); err != nil {
+			it.exhausted = true
+			it.err = err
+		} else {
+			it.keys = reply.Keys
+			it.values = reply.Values
+			it.exhausted = reply.Exhausted
+			if reply.Err != "" {
+				it.err = toDatabaseError(errors.New(reply.Err))
+			}
+		}
+	}
+	if len(it.keys) == 0 {
+		it.key = nil
+		it.value = nil
+		return false
+	}
+	it.key, it.keys = it.keys[0], it.keys[1:]
+	it.value, it.values = it.values[0], it.values[1:]
+	return true
+}
+
+func (it *iterator) Error() error { return it.err }
+
+func (it *iterator) Key() []byte { return it.key }
+
+func (it *iterator) Value() []byte { return it.value }
+
+func (it *iterator) Release() {
+	if it.released {
+		return
+	}
+	it.released = true
+	it.db.call("DB.IteratorRelease", &IteratorArgs{ID: it.id}, &struct{}{})
+}
+
+func copyBytes(bytes []byte) []byte {
+	copiedBytes := make([]byte, len(bytes))
+	copy(copiedBytes, bytes)
+	return copiedBytes
+}
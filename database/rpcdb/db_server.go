@@ -0,0 +1,201 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcdb
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/ava-labs/gecko/database"
+)
+
+const (
+	// iteratorBatchSize is the maximum number of key/value pairs that are
+	// sent in response to a single IteratorNext call
+	iteratorBatchSize = 128
+)
+
+var (
+	errUnknownIterator = errors.New("unknown iterator")
+)
+
+// DatabaseServer exposes a database over net/rpc.
+// It must be registered with the name "DB".
+type DatabaseServer struct {
+	db database.Database
+
+	lock           sync.Mutex
+	nextIteratorID uint64
+	iterators      map[uint64]database.Iterator
+}
+
+// NewServer returns a server that exposes [db]
+func NewServer(db database.Database) *DatabaseServer {
+	return &DatabaseServer{
+		db:        db,
+		iterators: make(map[uint64]database.Iterator),
+	}
+}
+
+// KeyArgs are the arguments to calls that operate on a single key
+type KeyArgs struct{ Key []byte }
+
+// HasReply is the reply from Has
+type HasReply struct{ Has bool }
+
+// Has delegates the Has call to the database
+func (db *DatabaseServer) Has(args *KeyArgs, reply *HasReply) error {
+	has, err := db.db.Has(args.Key)
+	reply.Has = has
+	return err
+}
+
+// GetReply is the reply from Get
+type GetReply struct{ Value []byte }
+
+// Get delegates the Get call to the database
+func (db *DatabaseServer) Get(args *KeyArgs, reply *GetReply) error {
+	value, err := db.db.Get(args.Key)
+	reply.Value = value
+	return err
+}
+
+// PutArgs are the arguments to Put
+type PutArgs struct{ Key, Value []byte }
+
+// Put delegates the Put call to the database
+func (db *DatabaseServer) Put(args *PutArgs, _ *struct{}) error {
+	return db.db.Put(args.Key, args.Value)
+}
+
+// Delete delegates the Delete call to the database
+func (db *DatabaseServer) Delete(args *KeyArgs, _ *struct{}) error {
+	return db.db.Delete(args.Key)
+}
+
+// StatArgs are the arguments to Stat
+type StatArgs struct{ Property string }
+
+// StatReply is the reply from Stat
+type StatReply struct{ Stat string }
+
+// Stat delegates the Stat call to the database
+func (db *DatabaseServer) Stat(args *StatArgs, reply *StatReply) error {
+	stat, err := db.db.Stat(args.Property)
+	reply.Stat = stat
+	return err
+}
+
+// CompactArgs are the arguments to Compact
+type CompactArgs struct{ Start, Limit []byte }
+
+// Compact delegates the Compact call to the database
+func (db *DatabaseServer) Compact(args *CompactArgs, _ *struct{}) error {
+	return db.db.Compact(args.Start, args.Limit)
+}
+
+// Close delegates the Close call to the database
+func (db *DatabaseServer) Close(_ *struct{}, _ *struct{}) error {
+	return db.db.Close()
+}
+
+// BatchOp is a write made by a batch
+type BatchOp struct {
+	Key, Value []byte
+	Delete     bool
+}
+
+// WriteBatchArgs are the arguments to WriteBatch
+type WriteBatchArgs struct{ Ops []BatchOp }
+
+// WriteBatch atomically writes [args.Ops] to the database
+func (db *DatabaseServer) WriteBatch(args *WriteBatchArgs, _ *struct{}) error {
+	batch := db.db.NewBatch()
+	for _, op := range args.Ops {
+		if op.Delete {
+			if err := batch.Delete(op.Key); err != nil {
+				return err
+			}
+		} else if err := batch.Put(op.Key, op.Value); err != nil {
+			return err
+		}
+	}
+	return batch.Write()
+}
+
+// NewIteratorArgs are the arguments to NewIterator
+type NewIteratorArgs struct{ Start, Prefix []byte }
+
+// NewIteratorReply is the reply from NewIterator
+type NewIteratorReply struct{ ID uint64 }
+
+// NewIterator creates an iterator over the database, which is identified by
+// the ID in the reply
+func (db *DatabaseServer) NewIterator(args *NewIteratorArgs, reply *NewIteratorReply) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	reply.ID = db.nextIteratorID
+	db.nextIteratorID++
+	db.iterators[reply.ID] = db.db.NewIteratorWithStartAndPrefix(args.Start, args.Prefix)
+	return nil
+}
+
+// IteratorArgs are the arguments to calls that operate on an iterator
+type IteratorArgs struct{ ID uint64 }
+
+// IteratorNextReply is the reply from IteratorNext
+type IteratorNextReply struct {
+	Keys, Values [][]byte
+
+	// Exhausted is true if the iterator has no more key/value pairs. If so,
+	// [Err] is the error the iterator finished with, if any.
+	Exhausted bool
+	Err       string
+}
+
+// IteratorNext returns the next key/value pairs of an iterator
+func (db *DatabaseServer) IteratorNext(args *IteratorArgs, reply *IteratorNextReply) error {
+	it, err := db.iterator(args.ID)
+	if err != nil {
+		return err
+	}
+	for len(reply.Keys) < iteratorBatchSize {
+		if !it.Next() {
+			reply.Exhausted = true
+			if err := it.Error(); err != nil {
+				reply.Err = err.Error()
+			}
+			return nil
+		}
+		reply.Keys = append(reply.Keys, it.Key())
+		reply.Values = append(reply.Values, it.Value())
+	}
+	return nil
+}
+
+// IteratorRelease releases an iterator
+func (db *DatabaseServer) IteratorRelease(args *IteratorArgs, _ *struct{}) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	it, exists := db.iterators[args.ID]
+	if !exists {
+		return errUnknownIterator
+	}
+	delete(db.iterators, args.ID)
+	it.Release()
+	return nil
+}
+
+func (db *DatabaseServer) iterator(id uint64) (database.Iterator, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	it, exists := db.iterators[id]
+	if !exists {
+		return nil, errUnknownIterator
+	}
+	return it, nil
+}
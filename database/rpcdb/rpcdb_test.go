@@ -0,0 +1,64 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcdb
+
+import (
+	"net"
+	"net/rpc"
+	"testing"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/memdb"
+)
+
+func setupDB(t *testing.T) *DatabaseClient {
+	serverConn, clientConn := net.Pipe()
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("DB", NewServer(memdb.New())); err != nil {
+		t.Fatal(err)
+	}
+	go server.ServeConn(serverConn)
+
+	return NewClient(rpc.NewClient(clientConn))
+}
+
+func TestInterface(t *testing.T) {
+	for _, test := range database.Tests {
+		test(t, setupDB(t))
+	}
+}
+
+// Ensure iterators that span more than one batch of key/value pairs iterate
+// over every key in order
+func TestIteratorBatches(t *testing.T) {
+	db := setupDB(t)
+
+	numKeys := 2*iteratorBatchSize + 1
+	for i := 0; i < numKeys; i++ {
+		key := []byte{byte(i >> 8), byte(i)}
+		if err := db.Put(key, key); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	it := db.NewIterator()
+	defer it.Release()
+
+	for i := 0; i < numKeys; i++ {
+		if !it.Next() {
+			t.Fatalf("iterator stopped after %d keys", i)
+		}
+		key := []byte{byte(i >> 8), byte(i)}
+		if string(it.Key()) != string(key) || string(it.Value()) != string(key) {
+			t.Fatalf("Wrong key/value pair. Expected: 0x%x ; Returned: 0x%x/0x%x", key, it.Key(), it.Value())
+		}
+	}
+	if it.Next() {
+		t.Fatalf("iterator should have been exhausted")
+	}
+	if err := it.Error(); err != nil {
+		t.Fatal(err)
+	}
+}
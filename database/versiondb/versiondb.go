@@ -184,25 +184,58 @@ func (db *Database) Commit() error {
 	db.lock.Lock()
 	defer db.lock.Unlock()
 
-	if db.mem == nil {
-		return database.ErrClosed
-	}
-	if len(db.mem) == 0 {
+	if db.mem != nil && len(db.mem) == 0 {
 		return nil
 	}
 
+	batch, err := db.commitBatch()
+	if err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	db.mem = make(map[string]valueDelete, memdb.DefaultSize)
+	return nil
+}
+
+// CommitBatch returns a batch that, when written, commits all the operations
+// of this database to the underlying database. The operations aren't removed
+// from this database, so Abort should be called once the batch is written.
+func (db *Database) CommitBatch() (database.Batch, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	return db.commitBatch()
+}
+
+// commitBatch assumes the lock is held
+func (db *Database) commitBatch() (database.Batch, error) {
+	if db.mem == nil {
+		return nil, database.ErrClosed
+	}
+
 	batch := db.db.NewBatch()
 	for key, value := range db.mem {
 		if value.delete {
 			if err := batch.Delete([]byte(key)); err != nil {
-				return err
+				return nil, err
 			}
 		} else if err := batch.Put([]byte(key), value.value); err != nil {
-			return err
+			return nil, err
 		}
 	}
-	if err := batch.Write(); err != nil {
-		return err
+	return batch, nil
+}
+
+// Abort discards all the operations of this database that haven't been
+// committed
+func (db *Database) Abort() error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.mem == nil {
+		return database.ErrClosed
 	}
 
 	db.mem = make(map[string]valueDelete, memdb.DefaultSize)
@@ -275,6 +308,9 @@ func (b *batch) Reset() {
 	b.size = 0
 }
 
+// Inner implements the Database interface
+func (b *batch) Inner() database.Batch { return b }
+
 // Replay implements the Database interface
 func (b *batch) Replay(w database.KeyValueWriter) error {
 	for _, kv := range b.writes {
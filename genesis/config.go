@@ -0,0 +1,440 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package genesis
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/vms/avm"
+	"github.com/ava-labs/gecko/vms/evm"
+	"github.com/ava-labs/gecko/vms/nftfx"
+	"github.com/ava-labs/gecko/vms/platformvm"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+
+	cjson "github.com/ava-labs/gecko/utils/json"
+)
+
+const (
+	// Names of the chains created by a genesis built from a Config
+	avmChainName = "AVM"
+	evmChainName = "Athereum"
+
+	// DefaultEVMChainID is the EVM chain ID used when a Config doesn't
+	// specify one
+	DefaultEVMChainID = 43110
+
+	// Length, in bytes, of an EVM address
+	evmAddressLen = 20
+)
+
+var (
+	errNoValidators      = errors.New("at least one validator is required")
+	errNoStartTime       = errors.New("must be non-zero")
+	errZeroAmount        = errors.New("must be positive")
+	errDuplicateAddress  = errors.New("address is listed more than once")
+	errDuplicateNodeID   = errors.New("node ID is listed more than once")
+	errDuplicateEVMAddr  = errors.New("address is allocated more than once")
+	errEndBeforeStart    = errors.New("must be after startTime")
+	errNoInitialState    = errors.New("at least one initial state is required")
+	errEmptyField        = errors.New("must not be empty")
+	errBadEVMAddress     = fmt.Errorf("must be a hex encoded %d byte address", evmAddressLen)
+	errBadEVMBalance     = errors.New("must be a non-negative decimal or 0x prefixed hex integer")
+	errUnknownAliasedVM  = errors.New("only the avm and evm chains can be aliased")
+	errDuplicateAlias    = errors.New("alias is used more than once")
+	errReservedAlias     = errors.New("alias is reserved for the platform chain")
+	errNetworkIDMismatch = errors.New("networkID doesn't match the network ID of the genesis file")
+	errReservedNetworkID = errors.New("networkID is reserved for the public networks")
+	reservedNetworkIDs   = map[uint32]bool{MainnetID: true, TestnetID: true}
+	reservedChainAliases = map[string]bool{"P": true, "platform": true}
+)
+
+// Config describes the genesis state of a network. It is the format of the
+// file passed to the node with --genesis-file.
+type Config struct {
+	// ID of the network this genesis is for
+	NetworkID cjson.Uint32 `json:"networkID"`
+
+	// Unix time the network starts at. Every genesis validator starts
+	// validating at this time.
+	StartTime cjson.Uint64 `json:"startTime"`
+
+	// Initial balances on the platform chain
+	Accounts []Account `json:"accounts"`
+
+	// Initial validators of the default subnet
+	Validators []Validator `json:"validators"`
+
+	// Assets created on the AVM chain, keyed by their alias
+	Assets map[string]avm.AssetDefinition `json:"assets"`
+
+	// Chain ID and initial balances of the EVM chain. The balances are keyed by
+	// hex encoded address.
+	EVMChainID cjson.Uint64          `json:"evmChainID"`
+	EVMAlloc   map[string]EVMAccount `json:"evmAlloc"`
+
+	// Replaces the default aliases of a chain. Keyed by the alias of the VM
+	// the chain runs, which must be "avm" or "evm".
+	ChainAliases map[string][]string `json:"chainAliases"`
+
+	// Staking parameters of the platform chain at genesis. If omitted, they
+	// are platformvm.DefaultStakingParameters.
+	StakingParameters *platformvm.APIStakingParameters `json:"stakingParameters"`
+
+	// Keys that can change the staking parameters. If omitted, the staking
+	// parameters can't be changed.
+	Governance *platformvm.APIGovernance `json:"governance"`
+}
+
+// Account is an initial balance on the platform chain
+type Account struct {
+	Address string       `json:"address"`
+	Balance cjson.Uint64 `json:"balance"`
+}
+
+// Validator is a genesis validator of the default subnet
+type Validator struct {
+	NodeID      string       `json:"nodeID"`
+	Weight      cjson.Uint64 `json:"weight"`
+	EndTime     cjson.Uint64 `json:"endTime"`
+	Destination string       `json:"destination"`
+}
+
+// EVMAccount is an initial balance on the EVM chain
+type EVMAccount struct {
+	// Decimal or 0x prefixed hex encoded balance, in wei
+	Balance string `json:"balance"`
+}
+
+// ConfigFromFile reads and verifies the genesis config stored in [path]
+func ConfigFromFile(path string) (*Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseConfig(b)
+}
+
+// ParseConfig parses and verifies the JSON encoded genesis config [b].
+// Unknown keys are rejected, so that a typo doesn't silently change the
+// genesis.
+func ParseConfig(b []byte) (*Config, error) {
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.DisallowUnknownFields()
+
+	config := &Config{}
+	if err := decoder.Decode(config); err != nil {
+		return nil, fmt.Errorf("couldn't parse the genesis config: %w", err)
+	}
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// Verify returns an error, naming the offending key, if this config doesn't
+// describe a valid genesis
+func (c *Config) Verify() error {
+	if reservedNetworkIDs[uint32(c.NetworkID)] {
+		return fmt.Errorf("networkID: %w", errReservedNetworkID)
+	}
+	if c.StartTime == 0 {
+		return fmt.Errorf("startTime: %w", errNoStartTime)
+	}
+
+	addresses := ids.ShortSet{}
+	for i, account := range c.Accounts {
+		addr, err := ids.ShortFromString(account.Address)
+		switch {
+		case err != nil:
+			return fmt.Errorf("accounts[%d].address: %w", i, err)
+		case addresses.Contains(addr):
+			return fmt.Errorf("accounts[%d].address: %w", i, errDuplicateAddress)
+		case account.Balance == 0:
+			return fmt.Errorf("accounts[%d].balance: %w", i, errZeroAmount)
+		}
+		addresses.Add(addr)
+	}
+
+	if len(c.Validators) == 0 {
+		return fmt.Errorf("validators: %w", errNoValidators)
+	}
+	nodeIDs := ids.ShortSet{}
+	for i, validator := range c.Validators {
+		nodeID, err := ids.ShortFromString(validator.NodeID)
+		switch {
+		case err != nil:
+			return fmt.Errorf("validators[%d].nodeID: %w", i, err)
+		case nodeIDs.Contains(nodeID):
+			return fmt.Errorf("validators[%d].nodeID: %w", i, errDuplicateNodeID)
+		case validator.Weight == 0:
+			return fmt.Errorf("validators[%d].weight: %w", i, errZeroAmount)
+		case validator.EndTime <= c.StartTime:
+			return fmt.Errorf("validators[%d].endTime: %w", i, errEndBeforeStart)
+		}
+		if _, err := ids.ShortFromString(validator.Destination); err != nil {
+			return fmt.Errorf("validators[%d].destination: %w", i, err)
+		}
+		nodeIDs.Add(nodeID)
+	}
+
+	for alias, asset := range c.Assets {
+		switch {
+		case asset.Name == "":
+			return fmt.Errorf("assets[%s].name: %w", alias, errEmptyField)
+		case asset.Symbol == "":
+			return fmt.Errorf("assets[%s].symbol: %w", alias, errEmptyField)
+		case len(asset.InitialState) == 0:
+			return fmt.Errorf("assets[%s].initialState: %w", alias, errNoInitialState)
+		}
+		// Building the asset on its own attributes any error to this asset
+		args := avm.BuildGenesisArgs{GenesisData: map[string]avm.AssetDefinition{alias: asset}}
+		if err := (&avm.StaticService{}).BuildGenesis(nil, &args, &avm.BuildGenesisReply{}); err != nil {
+			return fmt.Errorf("assets[%s]: %w", alias, err)
+		}
+	}
+
+	evmAddresses := map[string]bool{}
+	for addr, account := range c.EVMAlloc {
+		parsedAddr, err := parseEVMAddress(addr)
+		if err != nil {
+			return fmt.Errorf("evmAlloc[%s]: %w", addr, err)
+		}
+		// The same address may be written with different cases or prefixes
+		key := string(parsedAddr)
+		if evmAddresses[key] {
+			return fmt.Errorf("evmAlloc[%s]: %w", addr, errDuplicateEVMAddr)
+		}
+		evmAddresses[key] = true
+		if _, err := parseEVMBalance(account.Balance); err != nil {
+			return fmt.Errorf("evmAlloc[%s].balance: %w", addr, err)
+		}
+	}
+
+	aliases := map[string]bool{}
+	for vmAlias, chainAliases := range c.ChainAliases {
+		if vmAlias != "avm" && vmAlias != "evm" {
+			return fmt.Errorf("chainAliases[%s]: %w", vmAlias, errUnknownAliasedVM)
+		}
+		for i, alias := range chainAliases {
+			switch {
+			case alias == "":
+				return fmt.Errorf("chainAliases[%s][%d]: %w", vmAlias, i, errEmptyField)
+			case reservedChainAliases[alias]:
+				return fmt.Errorf("chainAliases[%s][%d]: %w", vmAlias, i, errReservedAlias)
+			case aliases[alias]:
+				return fmt.Errorf("chainAliases[%s][%d]: %w", vmAlias, i, errDuplicateAlias)
+			}
+			aliases[alias] = true
+		}
+	}
+	return nil
+}
+
+// VerifyNetworkID returns an error if this config can't be used on the
+// network with ID [networkID]
+func (c *Config) VerifyNetworkID(networkID uint32) error {
+	if uint32(c.NetworkID) != networkID {
+		return fmt.Errorf("%w: %d != %d", errNetworkIDMismatch, networkID, c.NetworkID)
+	}
+	return nil
+}
+
+// FromConfig returns the genesis data of the Platform Chain described by
+// [config]. The genesis creates an AVM chain holding [config.Assets] and an
+// EVM chain holding [config.EVMAlloc].
+func FromConfig(config *Config) ([]byte, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+
+	avmArgs := avm.BuildGenesisArgs{GenesisData: config.Assets}
+	avmReply := avm.BuildGenesisReply{}
+	if err := (&avm.StaticService{}).BuildGenesis(nil, &avmArgs, &avmReply); err != nil {
+		return nil, fmt.Errorf("couldn't build the AVM genesis: %w", err)
+	}
+
+	evmGenesis, err := config.evmGenesis()
+	if err != nil {
+		return nil, fmt.Errorf("couldn't build the EVM genesis: %w", err)
+	}
+
+	accounts := make([]platformvm.APIAccount, len(config.Accounts))
+	for i, account := range config.Accounts {
+		addr, err := ids.ShortFromString(account.Address)
+		if err != nil {
+			return nil, err
+		}
+		accounts[i] = platformvm.APIAccount{
+			Address: addr,
+			Balance: account.Balance,
+		}
+	}
+
+	validators := make([]platformvm.APIDefaultSubnetValidator, len(config.Validators))
+	for i, validator := range config.Validators {
+		nodeID, err := ids.ShortFromString(validator.NodeID)
+		if err != nil {
+			return nil, err
+		}
+		destination, err := ids.ShortFromString(validator.Destination)
+		if err != nil {
+			return nil, err
+		}
+		weight := validator.Weight
+		validators[i] = platformvm.APIDefaultSubnetValidator{
+			APIValidator: platformvm.APIValidator{
+				StartTime: config.StartTime,
+				EndTime:   validator.EndTime,
+				Weight:    &weight,
+				ID:        nodeID,
+			},
+			Destination: destination,
+		}
+	}
+
+	platformArgs := platformvm.BuildGenesisArgs{
+		NetworkID:  config.NetworkID,
+		Accounts:   accounts,
+		Validators: validators,
+		Chains: []platformvm.APIChain{
+			// A Config can't be used for the public networks, so only new
+			// networks get the nft fx. The genesis of the local network is hard
+			// coded without it.
+			platformvm.APIChain{
+				GenesisData: avmReply.Bytes,
+				VMID:        avm.ID,
+				FxIDs:       []ids.ID{secp256k1fx.ID, nftfx.ID},
+				Name:        avmChainName,
+			},
+			platformvm.APIChain{
+				GenesisData: formatting.CB58{Bytes: evmGenesis},
+				VMID:        evm.ID,
+				FxIDs:       []ids.ID{},
+				Name:        evmChainName,
+			},
+		},
+		Time:              config.StartTime,
+		StakingParameters: config.StakingParameters,
+		Governance:        config.Governance,
+	}
+	platformReply := platformvm.BuildGenesisReply{}
+	if err := (&platformvm.StaticService{}).BuildGenesis(nil, &platformArgs, &platformReply); err != nil {
+		return nil, fmt.Errorf("couldn't build the platform genesis: %w", err)
+	}
+	return platformReply.Bytes.Bytes, nil
+}
+
+// ID returns the hash of [genesisBytes]. Nodes are on the same network only if
+// their genesis IDs match.
+func ID(genesisBytes []byte) ids.ID {
+	return ids.NewID(hashing.ComputeHash256Array(genesisBytes))
+}
+
+// evmChainConfig and evmGenesis mirror the JSON encoding of the EVM's genesis,
+// which is what the EVM expects as its genesis data
+type evmChainConfig struct {
+	ChainID             uint64 `json:"chainId"`
+	HomesteadBlock      uint64 `json:"homesteadBlock"`
+	DAOForkBlock        uint64 `json:"daoForkBlock"`
+	DAOForkSupport      bool   `json:"daoForkSupport"`
+	EIP150Block         uint64 `json:"eip150Block"`
+	EIP150Hash          string `json:"eip150Hash"`
+	EIP155Block         uint64 `json:"eip155Block"`
+	EIP158Block         uint64 `json:"eip158Block"`
+	ByzantiumBlock      uint64 `json:"byzantiumBlock"`
+	ConstantinopleBlock uint64 `json:"constantinopleBlock"`
+	PetersburgBlock     uint64 `json:"petersburgBlock"`
+}
+
+type evmGenesis struct {
+	Config     evmChainConfig        `json:"config"`
+	Nonce      string                `json:"nonce"`
+	Timestamp  string                `json:"timestamp"`
+	ExtraData  string                `json:"extraData"`
+	GasLimit   string                `json:"gasLimit"`
+	Difficulty string                `json:"difficulty"`
+	MixHash    string                `json:"mixHash"`
+	Coinbase   string                `json:"coinbase"`
+	Alloc      map[string]EVMAccount `json:"alloc"`
+	Number     string                `json:"number"`
+	GasUsed    string                `json:"gasUsed"`
+	ParentHash string                `json:"parentHash"`
+}
+
+// evmGenesis returns the genesis data of the EVM chain
+func (c *Config) evmGenesis() ([]byte, error) {
+	chainID := uint64(c.EVMChainID)
+	if chainID == 0 {
+		chainID = DefaultEVMChainID
+	}
+
+	alloc := make(map[string]EVMAccount, len(c.EVMAlloc))
+	for addrStr, account := range c.EVMAlloc {
+		addr, err := parseEVMAddress(addrStr)
+		if err != nil {
+			return nil, err
+		}
+		balance, err := parseEVMBalance(account.Balance)
+		if err != nil {
+			return nil, err
+		}
+		alloc[hex.EncodeToString(addr)] = EVMAccount{
+			Balance: "0x" + balance.Text(16),
+		}
+	}
+
+	zeroHash := "0x" + strings.Repeat("00", hashing.HashLen)
+	return json.Marshal(&evmGenesis{
+		Config: evmChainConfig{
+			ChainID:        chainID,
+			DAOForkSupport: true,
+			EIP150Hash:     "0x2086799aeebeae135c246c65021c82b4e15a2c451340993aacfd2751886514f0",
+		},
+		Nonce:      "0x0",
+		Timestamp:  "0x0",
+		ExtraData:  "0x00",
+		GasLimit:   "0x5f5e100",
+		Difficulty: "0x0",
+		MixHash:    zeroHash,
+		Coinbase:   "0x" + strings.Repeat("00", evmAddressLen),
+		Alloc:      alloc,
+		Number:     "0x0",
+		GasUsed:    "0x0",
+		ParentHash: zeroHash,
+	})
+}
+
+// parseEVMAddress parses a hex encoded EVM address, with or without the 0x
+// prefix
+func parseEVMAddress(addr string) ([]byte, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(strings.ToLower(addr), "0x"))
+	if err != nil || len(b) != evmAddressLen {
+		return nil, errBadEVMAddress
+	}
+	return b, nil
+}
+
+// parseEVMBalance parses a decimal or 0x prefixed hex encoded balance. Any
+// other prefix is rejected, so that a leading 0 isn't read as octal.
+func parseEVMBalance(balance string) (*big.Int, error) {
+	digits, base := balance, 10
+	if strings.HasPrefix(balance, "0x") || strings.HasPrefix(balance, "0X") {
+		digits, base = balance[2:], 16
+	}
+	b, ok := new(big.Int).SetString(digits, base)
+	if !ok || b.Sign() < 0 {
+		return nil, errBadEVMBalance
+	}
+	return b, nil
+}
@@ -0,0 +1,340 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package genesis
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/vms/avm"
+	"github.com/ava-labs/gecko/vms/evm"
+	"github.com/ava-labs/gecko/vms/nftfx"
+	"github.com/ava-labs/gecko/vms/platformvm"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+const testConfig = `{
+	"networkID": 54321,
+	"startTime": 1577836800,
+	"accounts": [
+		{"address": "6Y3kysjF9jnHnYkdS9yGAuoHyae2eNmeV", "balance": "20000000000000"}
+	],
+	"validators": [
+		{
+			"nodeID": "7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg",
+			"weight": 100,
+			"endTime": 1609459200,
+			"destination": "6Y3kysjF9jnHnYkdS9yGAuoHyae2eNmeV"
+		},
+		{
+			"nodeID": "MFrZFVCXPv5iCn6M9K6XduxGTYp891xXZ",
+			"weight": 200,
+			"endTime": 1609459200,
+			"destination": "6Y3kysjF9jnHnYkdS9yGAuoHyae2eNmeV"
+		}
+	],
+	"assets": {
+		"AVA": {
+			"name": "AVA",
+			"symbol": "AVA",
+			"denomination": 9,
+			"initialState": {
+				"fixedCap": [
+					{"amount": 100000, "address": "6Y3kysjF9jnHnYkdS9yGAuoHyae2eNmeV"}
+				]
+			}
+		}
+	},
+	"evmAlloc": {
+		"0x751a0b96e1042bee789452ecb20253fba40dbe85": {"balance": "1000000"}
+	},
+	"chainAliases": {
+		"avm": ["T", "tavm"]
+	}
+}`
+
+func testParsedConfig(t *testing.T) *Config {
+	config, err := ParseConfig([]byte(testConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return config
+}
+
+func TestConfigFromConfig(t *testing.T) {
+	config := testParsedConfig(t)
+
+	genesisBytes, err := FromConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	genesis := platformvm.Genesis{}
+	if err := platformvm.Codec.Unmarshal(genesisBytes, &genesis); err != nil {
+		t.Fatal(err)
+	}
+	if err := genesis.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(genesis.Accounts) != 1 {
+		t.Fatalf("Expected 1 account but got %d", len(genesis.Accounts))
+	}
+	if balance := genesis.Accounts[0].Balance; balance != 20000000000000 {
+		t.Fatalf("Wrong account balance. Expected: %d ; Returned: %d", 20000000000000, balance)
+	}
+	if genesis.Timestamp != 1577836800 {
+		t.Fatalf("Wrong timestamp. Expected: %d ; Returned: %d", 1577836800, genesis.Timestamp)
+	}
+	if numValidators := genesis.Validators.Len(); numValidators != 2 {
+		t.Fatalf("Expected 2 validators but got %d", numValidators)
+	}
+	if len(genesis.Chains) != 2 {
+		t.Fatalf("Expected 2 chains but got %d", len(genesis.Chains))
+	}
+	if !genesis.Chains[0].VMID.Equals(avm.ID) {
+		t.Fatalf("The first chain should run the AVM")
+	}
+	if !genesis.Chains[1].VMID.Equals(evm.ID) {
+		t.Fatalf("The second chain should run the EVM")
+	}
+	if fxIDs := genesis.Chains[0].FxIDs; len(fxIDs) != 2 || !fxIDs[1].Equals(nftfx.ID) {
+		t.Fatalf("Wrong AVM fxs. Expected: %s ; Returned: %s", []ids.ID{secp256k1fx.ID, nftfx.ID}, fxIDs)
+	}
+
+	evmGenesis := evmGenesis{}
+	if err := json.Unmarshal(genesis.Chains[1].GenesisData, &evmGenesis); err != nil {
+		t.Fatal(err)
+	}
+	if evmGenesis.Config.ChainID != DefaultEVMChainID {
+		t.Fatalf("Wrong EVM chain ID. Expected: %d ; Returned: %d", DefaultEVMChainID, evmGenesis.Config.ChainID)
+	}
+	account, exists := evmGenesis.Alloc["751a0b96e1042bee789452ecb20253fba40dbe85"]
+	if !exists {
+		t.Fatalf("EVM alloc is missing the configured address")
+	}
+	if account.Balance != "0xf4240" {
+		t.Fatalf("Wrong EVM balance. Expected: %s ; Returned: %s", "0xf4240", account.Balance)
+	}
+
+	otherGenesisBytes, err := FromConfig(testParsedConfig(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(genesisBytes, otherGenesisBytes) {
+		t.Fatalf("Building the same config twice should produce the same genesis")
+	}
+	if !ID(genesisBytes).Equals(ID(otherGenesisBytes)) {
+		t.Fatalf("The same genesis should have the same ID")
+	}
+}
+
+func TestConfigVerify(t *testing.T) {
+	tests := []struct {
+		key    string
+		modify func(*Config)
+	}{
+		{"networkID", func(c *Config) { c.NetworkID = 1 }},
+		{"startTime", func(c *Config) { c.StartTime = 0 }},
+		{"accounts[0].address", func(c *Config) { c.Accounts[0].Address = "not an address" }},
+		{"accounts[1].address", func(c *Config) { c.Accounts = append(c.Accounts, c.Accounts[0]) }},
+		{"accounts[0].balance", func(c *Config) { c.Accounts[0].Balance = 0 }},
+		{"validators", func(c *Config) { c.Validators = nil }},
+		{"validators[1].nodeID", func(c *Config) { c.Validators[1].NodeID = c.Validators[0].NodeID }},
+		{"validators[0].weight", func(c *Config) { c.Validators[0].Weight = 0 }},
+		{"validators[1].endTime", func(c *Config) { c.Validators[1].EndTime = c.StartTime }},
+		{"validators[0].destination", func(c *Config) { c.Validators[0].Destination = "" }},
+		{"assets[AVA].symbol", func(c *Config) {
+			asset := c.Assets["AVA"]
+			asset.Symbol = ""
+			c.Assets["AVA"] = asset
+		}},
+		{"assets[AVA]", func(c *Config) {
+			asset := c.Assets["AVA"]
+			asset.InitialState = map[string][]interface{}{"unknownCap": []interface{}{}}
+			c.Assets["AVA"] = asset
+		}},
+		{"evmAlloc[0x1234]", func(c *Config) { c.EVMAlloc["0x1234"] = EVMAccount{Balance: "1"} }},
+		{"evmAlloc[0x751A0B96E1042BEE789452ECB20253FBA40DBE85].balance", func(c *Config) {
+			c.EVMAlloc = map[string]EVMAccount{"0x751A0B96E1042BEE789452ECB20253FBA40DBE85": EVMAccount{Balance: "-1"}}
+		}},
+		{"chainAliases[timestamp]", func(c *Config) { c.ChainAliases["timestamp"] = []string{"ts"} }},
+		{"chainAliases[evm][0]", func(c *Config) { c.ChainAliases["evm"] = []string{"P"} }},
+		{"chainAliases[avm][1]", func(c *Config) { c.ChainAliases["avm"] = []string{"T", "T"} }},
+	}
+	for _, test := range tests {
+		t.Run(test.key, func(t *testing.T) {
+			config := testParsedConfig(t)
+			test.modify(config)
+
+			err := config.Verify()
+			if err == nil {
+				t.Fatalf("Should have errored due to an invalid %s", test.key)
+			}
+			if !strings.HasPrefix(err.Error(), test.key+":") {
+				t.Fatalf("Error should name the key %s. Returned: %s", test.key, err)
+			}
+			if _, err := FromConfig(config); err == nil {
+				t.Fatalf("Shouldn't have built a genesis from an invalid config")
+			}
+		})
+	}
+}
+
+func TestConfigStakingParameters(t *testing.T) {
+	genesisBytes, err := FromConfig(testParsedConfig(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesis := platformvm.Genesis{}
+	if err := platformvm.Codec.Unmarshal(genesisBytes, &genesis); err != nil {
+		t.Fatal(err)
+	}
+	if genesis.StakingParameters != platformvm.DefaultStakingParameters {
+		t.Fatalf("Omitted staking parameters should be the default staking parameters")
+	}
+	if genesis.Governance.Threshold != 0 {
+		t.Fatalf("Omitted governance shouldn't allow the staking parameters to change")
+	}
+
+	config := strings.Replace(testConfig, `"chainAliases"`, `"stakingParameters": {
+		"minimumStakeAmount": 5000,
+		"minimumStakingDuration": 3600,
+		"maximumStakingDuration": 86400,
+		"maxSupply": 1000000000000000000,
+		"mintingPeriod": 86400,
+		"minConsumptionRate": 100000,
+		"maxConsumptionRate": 200000,
+		"batchSize": 10,
+		"delta": 5
+	},
+	"governance": {
+		"controlKeys": ["6Y3kysjF9jnHnYkdS9yGAuoHyae2eNmeV"],
+		"threshold": 1
+	},
+	"chainAliases"`, 1)
+	parsedConfig, err := ParseConfig([]byte(config))
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesisBytes, err = FromConfig(parsedConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesis = platformvm.Genesis{}
+	if err := platformvm.Codec.Unmarshal(genesisBytes, &genesis); err != nil {
+		t.Fatal(err)
+	}
+	params := genesis.StakingParameters
+	if params.MinimumStakeAmount != 5000 || params.MaximumStakingDuration != 24*time.Hour || params.BatchSize != 10 || params.Delta != 5*time.Second {
+		t.Fatalf("Wrong staking parameters: %+v", params)
+	}
+	if genesis.Governance.Threshold != 1 || len(genesis.Governance.ControlKeys) != 1 {
+		t.Fatalf("Wrong governance: %+v", genesis.Governance)
+	}
+
+	// The minting period must be at least the maximum staking duration
+	config = strings.Replace(config, `"mintingPeriod": 86400`, `"mintingPeriod": 3600`, 1)
+	parsedConfig, err = ParseConfig([]byte(config))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FromConfig(parsedConfig); err == nil {
+		t.Fatalf("Should have errored due to invalid staking parameters")
+	}
+}
+
+func TestParseConfigUnknownKey(t *testing.T) {
+	config := strings.Replace(testConfig, `"startTime"`, `"startTme"`, 1)
+	if _, err := ParseConfig([]byte(config)); err == nil {
+		t.Fatalf("Should have errored due to an unknown key")
+	} else if !strings.Contains(err.Error(), "startTme") {
+		t.Fatalf("Error should name the unknown key. Returned: %s", err)
+	}
+}
+
+func TestConfigVerifyNetworkID(t *testing.T) {
+	config := testParsedConfig(t)
+	if err := config.VerifyNetworkID(54321); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.VerifyNetworkID(LocalID); err == nil {
+		t.Fatalf("Should have errored due to a mismatched network ID")
+	}
+}
+
+func TestAliasesCustom(t *testing.T) {
+	config := testParsedConfig(t)
+	genesisBytes, err := FromConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	generalAliases, chainAliases, _, err := Aliases(genesisBytes, config.ChainAliases)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	genesis := platformvm.Genesis{}
+	if err := platformvm.Codec.Unmarshal(genesisBytes, &genesis); err != nil {
+		t.Fatal(err)
+	}
+	if err := genesis.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	avmID := genesis.Chains[0].ID()
+	evmID := genesis.Chains[1].ID()
+
+	if aliases := chainAliases[avmID.Key()]; len(aliases) != 2 || aliases[0] != "T" || aliases[1] != "tavm" {
+		t.Fatalf("Wrong AVM chain aliases: %v", aliases)
+	}
+	if aliases := generalAliases["bc/"+avmID.String()]; len(aliases) != 4 || aliases[1] != "bc/T" {
+		t.Fatalf("Wrong AVM URL aliases: %v", aliases)
+	}
+	if aliases := chainAliases[evmID.Key()]; len(aliases) != 2 || aliases[0] != "C" {
+		t.Fatalf("The EVM chain should keep its default aliases: %v", aliases)
+	}
+
+	if _, _, _, err := Aliases([]byte{1, 2, 3}, nil); err == nil {
+		t.Fatalf("Should have errored due to an invalid genesis")
+	}
+}
+
+func TestParseEVMBalance(t *testing.T) {
+	tests := []struct {
+		balance string
+		valid   bool
+		value   int64
+	}{
+		{"100", true, 100},
+		{"0100", true, 100},
+		{"0x64", true, 100},
+		{"0X64", true, 100},
+		{"0", true, 0},
+		{"0o144", false, 0},
+		{"0b1100100", false, 0},
+		{"1_000", false, 0},
+		{"-1", false, 0},
+		{"0x-1", false, 0},
+		{"0x", false, 0},
+		{"", false, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.balance, func(t *testing.T) {
+			balance, err := parseEVMBalance(test.balance)
+			switch {
+			case !test.valid && err == nil:
+				t.Fatalf("Should have rejected %q", test.balance)
+			case test.valid && err != nil:
+				t.Fatal(err)
+			case test.valid && balance.Int64() != test.value:
+				t.Fatalf("Wrong balance. Expected: %d ; Returned: %s", test.value, balance)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ package genesis
 // TODO: Move this to a separate repo and leave only a byte array
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"regexp"
@@ -42,6 +43,8 @@ const (
 
 var (
 	validNetworkName = regexp.MustCompile(`network-[0-9]+`)
+
+	errAliasConflict = errors.New("alias is already used")
 )
 
 // Hard coded genesis constants
@@ -126,8 +129,13 @@ func NetworkID(networkName string) (uint32, error) {
 	return 0, fmt.Errorf("Failed to parse %s as a network name", networkName)
 }
 
-// Aliases returns the default aliases based on the network ID
-func Aliases(networkID uint32) (generalAliases map[string][]string, chainAliases map[[32]byte][]string, vmAliases map[[32]byte][]string) {
+// Aliases returns the default aliases of the network with genesis
+// [genesisBytes]. The chains in [customChainAliases], keyed by the alias of
+// the VM they run, are given those aliases instead of their default ones.
+// Returns an error if a custom alias is also used by another chain or VM,
+// including by the default aliases of the chains that weren't given custom
+// ones.
+func Aliases(genesisBytes []byte, customChainAliases map[string][]string) (generalAliases map[string][]string, chainAliases map[[32]byte][]string, vmAliases map[[32]byte][]string, err error) {
 	generalAliases = map[string][]string{
 		"vm/" + platformvm.ID.String():  []string{"vm/platform"},
 		"vm/" + avm.ID.String():         []string{"vm/avm"},
@@ -149,12 +157,27 @@ func Aliases(networkID uint32) (generalAliases map[string][]string, chainAliases
 		timestampvm.ID.Key(): []string{"timestamp"},
 	}
 
-	genesisBytes := Genesis(networkID)
-	genesis := &platformvm.Genesis{}                  // TODO let's not re-create genesis to do aliasing
-	platformvm.Codec.Unmarshal(genesisBytes, genesis) // TODO check for error
-	genesis.Initialize()
+	genesis := &platformvm.Genesis{} // TODO let's not re-create genesis to do aliasing
+	if err := platformvm.Codec.Unmarshal(genesisBytes, genesis); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := genesis.Initialize(); err != nil {
+		return nil, nil, nil, err
+	}
 
 	for _, chain := range genesis.Chains {
+		if aliases := vmAliases[chain.VMID.Key()]; len(aliases) > 0 {
+			if custom, exists := customChainAliases[aliases[0]]; exists {
+				urlAliases := []string(nil)
+				for _, alias := range custom {
+					urlAliases = append(urlAliases, alias, "bc/"+alias)
+				}
+				generalAliases["bc/"+chain.ID().String()] = urlAliases
+				chainAliases[chain.ID().Key()] = custom
+				continue
+			}
+		}
+
 		switch {
 		case avm.ID.Equals(chain.VMID):
 			generalAliases["bc/"+chain.ID().String()] = []string{"X", "avm", "bc/X", "bc/avm"}
@@ -173,16 +196,48 @@ func Aliases(networkID uint32) (generalAliases map[string][]string, chainAliases
 			chainAliases[chain.ID().Key()] = []string{"timestamp"}
 		}
 	}
+
+	if err := verifyAliases(generalAliases, chainAliases); err != nil {
+		return nil, nil, nil, err
+	}
 	return
 }
 
+// verifyAliases returns an error if an alias in [generalAliases] is given to
+// more than one name, or an alias in [chainAliases] to more than one chain
+func verifyAliases(generalAliases map[string][]string, chainAliases map[[32]byte][]string) error {
+	names := map[string]string{}
+	for name, aliases := range generalAliases {
+		for _, alias := range aliases {
+			if other, exists := names[alias]; exists {
+				return fmt.Errorf("%w: %q is an alias of both %s and %s", errAliasConflict, alias, other, name)
+			}
+			names[alias] = name
+		}
+	}
+
+	chains := map[string]ids.ID{}
+	for key, aliases := range chainAliases {
+		chainID := ids.NewID(key)
+		for _, alias := range aliases {
+			if other, exists := chains[alias]; exists {
+				return fmt.Errorf("%w: %q is an alias of both chain %s and chain %s", errAliasConflict, alias, other, chainID)
+			}
+			chains[alias] = chainID
+		}
+	}
+	return nil
+}
+
 // Genesis returns the genesis data of the Platform Chain.
 // Since the Platform Chain causes the creation of all other
 // chains, this function returns the genesis data of the entire network.
 // The ID of the new network is [networkID].
-func Genesis(networkID uint32) []byte {
+// Only the genesis of the local network is hard coded. The genesis of any
+// other network must be built from a Config with FromConfig.
+func Genesis(networkID uint32) ([]byte, error) {
 	if networkID != LocalID {
-		panic("unknown network ID provided")
+		return nil, fmt.Errorf("no genesis is known for network %s, one must be provided", NetworkName(networkID))
 	}
 
 	return []byte{
@@ -495,19 +550,61 @@ func Genesis(networkID uint32) []byte {
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
-		0x5d, 0xbb, 0x75, 0x80,
+		0x5d, 0xbb, 0x75, 0x80, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x27, 0x10, 0x00, 0x00, 0x4e, 0x94,
+		0x91, 0x4f, 0x00, 0x00, 0x00, 0x70, 0x09, 0xd3,
+		0x2d, 0xa3, 0x00, 0x00, 0x09, 0xfd, 0xf4, 0x2f,
+		0x6e, 0x48, 0x00, 0x00, 0x00, 0x70, 0x09, 0xd3,
+		0x2d, 0xa3, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x01, 0x86, 0xa0, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x01, 0xd4, 0xc0, 0x00, 0x00, 0x00, 0x1e,
+		0x00, 0x00, 0x00, 0x02, 0x54, 0x0b, 0xe4, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	}, nil
+}
+
+// VMGenesis returns the tx that creates the chain running [vmID] in the
+// genesis of the network with ID [networkID]
+func VMGenesis(networkID uint32, vmID ids.ID) (*platformvm.CreateChainTx, error) {
+	genesisBytes, err := Genesis(networkID)
+	if err != nil {
+		return nil, err
+	}
+	chain, err := GenesisChain(genesisBytes, vmID)
+	if err != nil {
+		return nil, fmt.Errorf("network %s doesn't create a chain running %s", NetworkName(networkID), vmID)
 	}
+	return chain, nil
 }
 
-// VMGenesis ...
-func VMGenesis(networkID uint32, vmID ids.ID) *platformvm.CreateChainTx {
-	genesisBytes := Genesis(networkID)
+// GenesisChain returns the tx that creates the chain running [vmID] in the
+// platform chain genesis [genesisBytes]
+func GenesisChain(genesisBytes []byte, vmID ids.ID) (*platformvm.CreateChainTx, error) {
 	genesis := platformvm.Genesis{}
-	platformvm.Codec.Unmarshal(genesisBytes, &genesis)
+	if err := platformvm.Codec.Unmarshal(genesisBytes, &genesis); err != nil {
+		return nil, err
+	}
+	if err := genesis.Initialize(); err != nil {
+		return nil, err
+	}
 	for _, chain := range genesis.Chains {
 		if chain.VMID.Equals(vmID) {
-			return chain
+			return chain, nil
 		}
 	}
-	return nil
+	return nil, fmt.Errorf("genesis doesn't create a chain running %s", vmID)
+}
+
+// AVA returns the ID of the AVM chain created by the platform chain genesis
+// [genesisBytes], and the ID of the AVA asset created by that chain's genesis
+func AVA(genesisBytes []byte) (avmID ids.ID, assetID ids.ID, err error) {
+	chain, err := GenesisChain(genesisBytes, avm.ID)
+	if err != nil {
+		return ids.ID{}, ids.ID{}, err
+	}
+	assetID, err = avm.GenesisAssetID(chain.GenesisData, "AVA")
+	if err != nil {
+		return ids.ID{}, ids.ID{}, err
+	}
+	return chain.ID(), assetID, nil
 }
@@ -4,11 +4,14 @@
 package genesis
 
 import (
+	"errors"
 	"testing"
 
+	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/vms/avm"
 	"github.com/ava-labs/gecko/vms/evm"
 	"github.com/ava-labs/gecko/vms/platformvm"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
 	"github.com/ava-labs/gecko/vms/spchainvm"
 	"github.com/ava-labs/gecko/vms/spdagvm"
 )
@@ -91,7 +94,14 @@ func TestNetworkID(t *testing.T) {
 }
 
 func TestAliases(t *testing.T) {
-	generalAliases, _, _ := Aliases(LocalID)
+	genesisBytes, err := Genesis(LocalID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	generalAliases, _, _, err := Aliases(genesisBytes, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
 	if _, exists := generalAliases["vm/"+platformvm.ID.String()]; !exists {
 		t.Fatalf("Should have a custom alias from the vm")
 	} else if _, exists := generalAliases["vm/"+avm.ID.String()]; !exists {
@@ -105,10 +115,158 @@ func TestAliases(t *testing.T) {
 	}
 }
 
+func TestAliasesConflict(t *testing.T) {
+	genesisBytes, err := Genesis(LocalID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		aliases map[string][]string
+		err     error
+	}{
+		{
+			name:    "new aliases",
+			aliases: map[string][]string{"avm": []string{"T", "tavm"}, "evm": []string{"E"}},
+		},
+		{
+			name:    "swapped default aliases",
+			aliases: map[string][]string{"avm": []string{"C"}, "evm": []string{"X"}},
+		},
+		{
+			name:    "default alias of the evm chain",
+			aliases: map[string][]string{"avm": []string{"C"}},
+			err:     errAliasConflict,
+		},
+		{
+			name:    "default alias of the avm chain",
+			aliases: map[string][]string{"evm": []string{"avm"}},
+			err:     errAliasConflict,
+		},
+		{
+			name:    "default alias of another vm's chain",
+			aliases: map[string][]string{"avm": []string{"timestamp"}},
+			err:     errAliasConflict,
+		},
+		{
+			name:    "alias of a vm",
+			aliases: map[string][]string{"evm": []string{"vm/avm"}},
+			err:     errAliasConflict,
+		},
+		{
+			name:    "same alias for two chains",
+			aliases: map[string][]string{"avm": []string{"T"}, "evm": []string{"T"}},
+			err:     errAliasConflict,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, _, _, err := Aliases(genesisBytes, test.aliases)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("Wrong error. Expected: %v ; Returned: %v", test.err, err)
+			}
+		})
+	}
+}
+
 func TestGenesis(t *testing.T) {
-	genesisBytes := Genesis(LocalID)
+	genesisBytes, err := Genesis(LocalID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesis := platformvm.Genesis{}
+	if err := platformvm.Codec.Unmarshal(genesisBytes, &genesis); err != nil {
+		t.Fatal(err)
+	}
+
+	// The local network's AVM must keep the fxs it was created with
+	avmChain, err := GenesisChain(genesisBytes, avm.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(avmChain.FxIDs) != 1 || !avmChain.FxIDs[0].Equals(secp256k1fx.ID) {
+		t.Fatalf("Wrong AVM fxs. Expected: %s ; Returned: %s", []ids.ID{secp256k1fx.ID}, avmChain.FxIDs)
+	}
+}
+
+func TestGenesisSupply(t *testing.T) {
+	genesisBytes, err := Genesis(LocalID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	avmID, assetID, err := AVA(genesisBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	avmChain, err := GenesisChain(genesisBytes, avm.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	allocated, err := avm.GenesisSupply(avmChain.GenesisData, assetID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if allocated == 0 {
+		t.Fatalf("Should have allocated AVA in the AVM genesis")
+	}
+
 	genesis := platformvm.Genesis{}
 	if err := platformvm.Codec.Unmarshal(genesisBytes, &genesis); err != nil {
 		t.Fatal(err)
 	}
+	if err := genesis.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	platformSupply, err := genesis.Supply(ids.Empty, assetID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The supply includes the AVA allocated by the AVM genesis
+	supply, err := genesis.Supply(avmID, assetID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if supply != platformSupply+allocated {
+		t.Fatalf("Wrong supply. Expected: %d ; Returned: %d", platformSupply+allocated, supply)
+	}
+}
+
+func TestGenesisUnknownNetwork(t *testing.T) {
+	if _, err := Genesis(MainnetID); err == nil {
+		t.Fatalf("Should have errored due to the genesis of the network not being known")
+	}
+}
+
+func TestAVA(t *testing.T) {
+	genesisBytes, err := Genesis(LocalID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	avmID, assetID, err := AVA(genesisBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	avmChain, err := VMGenesis(LocalID, avm.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !avmID.Equals(avmChain.ID()) {
+		t.Fatalf("Wrong AVM chain ID. Expected: %s ; Returned: %s", avmChain.ID(), avmID)
+	}
+
+	expectedAssetID, err := avm.GenesisAssetID(avmChain.GenesisData, "AVA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !assetID.Equals(expectedAssetID) {
+		t.Fatalf("Wrong AVA asset ID. Expected: %s ; Returned: %s", expectedAssetID, assetID)
+	}
+
+	if _, _, err := AVA([]byte{1, 2, 3}); err == nil {
+		t.Fatalf("Should have errored due to an invalid genesis")
+	}
 }
@@ -0,0 +1,178 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Every flag can be set from, in decreasing order of precedence:
+//  1. The command line. For example: --http-port=9650
+//  2. An environment variable named after the flag, in upper case, with
+//     dashes replaced by underscores and prefixed with AVA_. For example:
+//     AVA_HTTP_PORT=9650
+//  3. The JSON or YAML file given by --config-file, whose keys are the names
+//     of the flags. For example: {"http-port": 9650}
+//  4. The default value of the flag
+const (
+	envPrefix = "AVA_"
+
+	configFileKey = "config-file"
+)
+
+var (
+	errNestedConfigFile = fmt.Errorf("%s can't be set in a config file", configFileKey)
+
+	// sources maps the name of every flag that wasn't left to its default value
+	// to where its value was read from. It's set by applyOverrides.
+	sources = map[string]string{}
+)
+
+// envVar returns the name of the environment variable that sets flag [name]
+func envVar(name string) string {
+	return envPrefix + strings.ToUpper(strings.Replace(name, "-", "_", -1))
+}
+
+// source returns where the value of flag [name] was read from, so that an
+// invalid value can be reported against the key the user actually set
+func source(name string) string {
+	if src, exists := sources[name]; exists {
+		return src
+	}
+	return "--" + name
+}
+
+// applyOverrides sets every flag of [fs] that wasn't given on the command line
+// from the environment, or else from the config file [configFile], if
+// provided. Must be called after [fs] is parsed.
+func applyOverrides(fs *flag.FlagSet, configFile string) error {
+	sources = map[string]string{}
+	fs.Visit(func(f *flag.Flag) { sources[f.Name] = "--" + f.Name })
+
+	if _, onCommandLine := sources[configFileKey]; !onCommandLine {
+		if path, exists := os.LookupEnv(envVar(configFileKey)); exists {
+			sources[configFileKey] = envVar(configFileKey)
+			configFile = path
+		}
+	}
+
+	fileValues := map[string]string{}
+	if configFile != "" {
+		values, err := readConfigFile(configFile)
+		if err != nil {
+			return fmt.Errorf("couldn't read config file %s: %w", configFile, err)
+		}
+		fileValues = values
+	}
+
+	// Report unknown keys in a deterministic order
+	keys := make([]string, 0, len(fileValues))
+	for key := range fileValues {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		switch {
+		case key == configFileKey:
+			return fmt.Errorf("config file %s: %w", configFile, errNestedConfigFile)
+		case fs.Lookup(key) == nil:
+			return fmt.Errorf("config file %s: unknown key %q", configFile, key)
+		}
+	}
+
+	errs := []string(nil)
+	fs.VisitAll(func(f *flag.Flag) {
+		if _, onCommandLine := sources[f.Name]; onCommandLine {
+			return
+		}
+
+		src := ""
+		value, exists := os.LookupEnv(envVar(f.Name))
+		if exists {
+			src = envVar(f.Name)
+		} else if value, exists = fileValues[f.Name]; exists {
+			src = fmt.Sprintf("key %q in config file %s", f.Name, configFile)
+		} else {
+			return
+		}
+
+		sources[f.Name] = src
+		if err := fs.Set(f.Name, value); err != nil {
+			errs = append(errs, fmt.Sprintf("invalid value %q for %s: %s", value, src, err))
+		}
+	})
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+// readConfigFile returns the flag values in the config file at [path], keyed by
+// flag name. The format of the file is determined by its extension.
+func readConfigFile(path string) (map[string]string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := map[string]interface{}{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(b))
+		// Keep large numbers from being printed in scientific notation
+		decoder.UseNumber()
+		err = decoder.Decode(&raw)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, &raw)
+	default:
+		err = fmt.Errorf("unknown extension %q, expected .json, .yaml or .yml", ext)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(raw))
+	for key, value := range raw {
+		str, err := flagValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", key, err)
+		}
+		values[key] = str
+	}
+	return values, nil
+}
+
+// flagValue returns [value], as read from a config file, in the format the
+// flag package parses. Lists become comma separated values.
+func flagValue(value interface{}) (string, error) {
+	switch value := value.(type) {
+	case string:
+		return value, nil
+	case bool, int, int64, uint64, float64, json.Number:
+		return fmt.Sprint(value), nil
+	case []interface{}:
+		elems := make([]string, len(value))
+		for i, elem := range value {
+			str, err := flagValue(elem)
+			if err != nil {
+				return "", err
+			}
+			elems[i] = str
+		}
+		return strings.Join(elems, ","), nil
+	default:
+		return "", fmt.Errorf("unsupported value %v, expected a string, number, boolean or list", value)
+	}
+}
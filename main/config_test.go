@@ -0,0 +1,214 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestFlags returns a flag set with a flag of each type the node uses
+func newTestFlags() *flag.FlagSet {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	fs.String(configFileKey, "", "")
+	fs.Uint("http-port", 9650, "")
+	fs.Bool("staking-tls-enabled", true, "")
+	fs.String("log-level", "info", "")
+	fs.String("bootstrap-ips", "", "")
+	fs.Float64("bootstrap-stake-fraction", 0.5, "")
+	fs.Uint64("ava-tx-fee", 0, "")
+	return fs
+}
+
+func TestApplyOverrides(t *testing.T) {
+	tests := []struct {
+		name string
+
+		args []string
+		env  map[string]string
+
+		// If [fileExt] is empty, there is no config file
+		fileExt, file string
+		// If true, the config file is given by the environment rather than on
+		// the command line
+		fileFromEnv bool
+
+		// The expected values of the flags, if [err] is empty
+		values map[string]string
+		// The expected sources of the flags, if [err] is empty
+		sources map[string]string
+		// A substring of the expected error, if any
+		err string
+	}{
+		{
+			name:    "defaults",
+			values:  map[string]string{"http-port": "9650", "staking-tls-enabled": "true", "log-level": "info"},
+			sources: map[string]string{"http-port": "--http-port"},
+		},
+		{
+			name:    "json file",
+			fileExt: ".json",
+			file:    `{"http-port": 1234, "staking-tls-enabled": false, "log-level": "debug", "bootstrap-stake-fraction": 0.75}`,
+			values:  map[string]string{"http-port": "1234", "staking-tls-enabled": "false", "log-level": "debug", "bootstrap-stake-fraction": "0.75"},
+			sources: map[string]string{"http-port": `key "http-port" in config file`},
+		},
+		{
+			name:    "yaml file",
+			fileExt: ".yaml",
+			file:    "http-port: 1234\nstaking-tls-enabled: false\nbootstrap-ips:\n  - 127.0.0.1:9630\n  - 127.0.0.1:9631\n",
+			values:  map[string]string{"http-port": "1234", "staking-tls-enabled": "false", "bootstrap-ips": "127.0.0.1:9630,127.0.0.1:9631"},
+		},
+		{
+			name:    "large numbers aren't in scientific notation",
+			fileExt: ".json",
+			file:    `{"ava-tx-fee": 18446744073709551615}`,
+			values:  map[string]string{"ava-tx-fee": "18446744073709551615"},
+		},
+		{
+			name:    "command line beats file",
+			args:    []string{"--http-port=1", "--staking-tls-enabled=true"},
+			fileExt: ".json",
+			file:    `{"http-port": 1234, "staking-tls-enabled": false, "log-level": "debug"}`,
+			values:  map[string]string{"http-port": "1", "staking-tls-enabled": "true", "log-level": "debug"},
+			sources: map[string]string{"http-port": "--http-port", "log-level": `key "log-level" in config file`},
+		},
+		{
+			name:    "environment beats file",
+			env:     map[string]string{"AVA_HTTP_PORT": "5"},
+			fileExt: ".json",
+			file:    `{"http-port": 1234, "log-level": "debug"}`,
+			values:  map[string]string{"http-port": "5", "log-level": "debug"},
+			sources: map[string]string{"http-port": "AVA_HTTP_PORT"},
+		},
+		{
+			name:    "command line beats environment",
+			args:    []string{"--http-port=1"},
+			env:     map[string]string{"AVA_HTTP_PORT": "5", "AVA_LOG_LEVEL": "warn"},
+			values:  map[string]string{"http-port": "1", "log-level": "warn"},
+			sources: map[string]string{"http-port": "--http-port", "log-level": "AVA_LOG_LEVEL"},
+		},
+		{
+			name:        "config file from the environment",
+			fileExt:     ".json",
+			file:        `{"http-port": 1234}`,
+			fileFromEnv: true,
+			values:      map[string]string{"http-port": "1234"},
+		},
+		{
+			name:    "unknown key",
+			fileExt: ".json",
+			file:    `{"http-port": 1234, "http-prot": 1234}`,
+			err:     `unknown key "http-prot"`,
+		},
+		{
+			name:    "config file in config file",
+			fileExt: ".json",
+			file:    `{"config-file": "other.json"}`,
+			err:     errNestedConfigFile.Error(),
+		},
+		{
+			name:    "wrong type in file",
+			fileExt: ".json",
+			file:    `{"http-port": "ninety"}`,
+			err:     `invalid value "ninety" for key "http-port" in config file`,
+		},
+		{
+			name:    "negative number for unsigned flag",
+			fileExt: ".yaml",
+			file:    "http-port: -1\n",
+			err:     `invalid value "-1" for key "http-port" in config file`,
+		},
+		{
+			name:    "number for boolean flag",
+			fileExt: ".json",
+			file:    `{"staking-tls-enabled": 2}`,
+			err:     `invalid value "2" for key "staking-tls-enabled" in config file`,
+		},
+		{
+			name: "wrong type in environment",
+			env:  map[string]string{"AVA_HTTP_PORT": "ninety"},
+			err:  `invalid value "ninety" for AVA_HTTP_PORT`,
+		},
+		{
+			name:    "unsupported value",
+			fileExt: ".json",
+			file:    `{"http-port": {"port": 1234}}`,
+			err:     `key "http-port": unsupported value`,
+		},
+		{
+			name:    "malformed file",
+			fileExt: ".json",
+			file:    `{"http-port": `,
+			err:     "couldn't read config file",
+		},
+		{
+			name:    "unknown extension",
+			fileExt: ".toml",
+			file:    "http-port = 1234\n",
+			err:     `unknown extension ".toml"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "gecko-config")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			configFile := ""
+			if test.fileExt != "" {
+				configFile = filepath.Join(dir, "config"+test.fileExt)
+				if err := ioutil.WriteFile(configFile, []byte(test.file), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for key, value := range test.env {
+				os.Setenv(key, value)
+				defer os.Unsetenv(key)
+			}
+			if test.fileFromEnv {
+				os.Setenv(envVar(configFileKey), configFile)
+				defer os.Unsetenv(envVar(configFileKey))
+				configFile = ""
+			}
+
+			fs := newTestFlags()
+			if err := fs.Parse(test.args); err != nil {
+				t.Fatal(err)
+			}
+
+			err = applyOverrides(fs, configFile)
+			if test.err != "" {
+				if err == nil {
+					t.Fatalf("Should have failed with %q", test.err)
+				}
+				if !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("Wrong error. Expected: %q ; Returned: %q", test.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for name, expected := range test.values {
+				if value := fs.Lookup(name).Value.String(); value != expected {
+					t.Fatalf("Wrong value of %s. Expected: %s ; Returned: %s", name, expected, value)
+				}
+			}
+			for name, expected := range test.sources {
+				if src := source(name); !strings.HasPrefix(src, expected) {
+					t.Fatalf("Wrong source of %s. Expected: %s ; Returned: %s", name, expected, src)
+				}
+			}
+		})
+	}
+}
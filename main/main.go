@@ -4,6 +4,7 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"path"
 
@@ -16,12 +17,24 @@ import (
 // main is the primary entry point to Ava. This can either create a CLI to an
 //     existing node or create a new node.
 func main() {
+	parseFlags()
+
 	// Err is set based on the CLI arguments
 	if Err != nil {
 		fmt.Printf("parsing parameters returned with error %s\n", Err)
 		return
 	}
 
+	if dumpConfig {
+		config, err := json.MarshalIndent(&Config, "", "  ")
+		if err != nil {
+			fmt.Printf("couldn't marshal the config: %s\n", err)
+			return
+		}
+		fmt.Println(string(config))
+		return
+	}
+
 	config := Config.LoggingConfig
 	config.Directory = path.Join(config.Directory, "node")
 	factory := logging.NewFactory(config)
@@ -98,5 +111,7 @@ func main() {
 	defer node.MainNode.Shutdown()
 
 	log.Debug("Dispatching node handlers")
-	node.MainNode.Dispatch()
+	if err := node.MainNode.Dispatch(); err != nil {
+		log.Error("dispatching the node returned with: %s", err)
+	}
 }
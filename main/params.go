@@ -18,6 +18,7 @@ import (
 	"github.com/ava-labs/gecko/genesis"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/node"
+	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/snow/networking/router"
 	"github.com/ava-labs/gecko/utils"
 	"github.com/ava-labs/gecko/utils/formatting"
@@ -33,23 +34,45 @@ var (
 )
 
 var (
-	errBootstrapMismatch = errors.New("more bootstrap IDs provided than bootstrap IPs")
+	errBootstrapMismatch     = errors.New("more bootstrap IDs provided than bootstrap IPs")
+	errInvalidUptimeRequired = errors.New("the required uptime must be between 0 and 1")
+	errInvalidBootstrapStake = errors.New("the fraction of stake required to bootstrap must be greater than 0 and at most 1")
+	errNegativeRetries       = errors.New("the number of retries can't be negative")
+	errInvalidBufferSize     = errors.New("the number of buffered messages must be positive")
+
+	// If true, the effective config is printed rather than running the node
+	dumpConfig bool
 )
 
-// Parse the CLI arguments
-func init() {
+// parseFlags parses the CLI arguments, the environment and the config file
+// into Config, and sets Err if they're invalid. It's called by main rather than
+// at initialization, so that the package's tests don't parse their own flags.
+func parseFlags() {
 	errs := &wrappers.Errs{}
 	defer func() { Err = errs.Err }()
 
 	loggingConfig, err := logging.DefaultConfig()
 	errs.Add(err)
 
+	// Config:
+	configFile := flag.String(configFileKey, "", "JSON or YAML file setting any of these flags, keyed by flag name. Flags given on the command line take precedence over AVA_* environment variables, which take precedence over the config file")
+	flag.BoolVar(&dumpConfig, "dump-config", false, "Print the effective node configuration as JSON and exit")
+
 	// NetworkID:
 	networkName := flag.String("network-id", genesis.LocalName, "Network ID this node will connect to")
 
+	// Genesis:
+	genesisFile := flag.String("genesis-file", "", "JSON file describing the genesis state of the network. Required for any network other than local")
+
 	// Ava fees:
 	flag.Uint64Var(&Config.AvaTxFee, "ava-tx-fee", 0, "Ava transaction fee, in $nAva")
 
+	// Staking rewards:
+	flag.Float64Var(&Config.UptimeRequirement, "uptime-requirement", 0.6, "Fraction of its staking period a staker must be connected to this node for this node to prefer rewarding it")
+
+	// Transaction index:
+	flag.BoolVar(&Config.AVMTxIndexEnabled, "avm-tx-index-enabled", false, "If true, the AVM indexes the transactions that touch each address and asset. Only transactions accepted while the index is enabled are indexed")
+
 	// Assertions:
 	flag.BoolVar(&loggingConfig.Assertions, "assertions-enabled", true, "Turn on assertion execution")
 
@@ -60,6 +83,12 @@ func init() {
 	db := flag.Bool("db-enabled", true, "Turn on persistent storage")
 	dbDir := flag.String("db-dir", "db", "Database directory for Ava state")
 
+	// Plugins:
+	flag.StringVar(&Config.PluginDir, "plugin-dir", "", "Directory of VM plugins. Each plugin serves a VM that's aliased by the plugin's file name")
+
+	// Signer:
+	flag.StringVar(&Config.RemoteSignerPath, "remote-signer-path", "", "Unix socket of a signer that holds the keys of users. If empty, the keys are held in the keystore")
+
 	// IP:
 	consensusIP := flag.String("public-ip", "", "Public IP of this node")
 
@@ -72,6 +101,8 @@ func init() {
 	// Bootstrapping:
 	bootstrapIPs := flag.String("bootstrap-ips", "", "Comma separated list of bootstrap peer ips to connect to. Example: 127.0.0.1:9630,127.0.0.1:9631")
 	bootstrapIDs := flag.String("bootstrap-ids", "", "Comma separated list of bootstrap peer ids to connect to. Example: JR4dVmy6ffUGAKCBDkyCbeZbyHQBeDsET,8CrVPQZ4VSqgL8zTdvL14G8HqAfrBr4z")
+	flag.Float64Var(&Config.BootstrapStakeFraction, "bootstrap-stake-fraction", 0.5, "Fraction of the validators' total stake that must have accepted a container for this node to bootstrap it")
+	flag.IntVar(&Config.BootstrapRetries, "bootstrap-retries", common.DefaultBootstrapRetries, "Number of times the accepted frontier is sampled again when too little of the validators' stake responded to agree on it")
 
 	// Staking:
 	consensusPort := flag.Uint("staking-port", 9651, "Port of the consensus server")
@@ -79,6 +110,10 @@ func init() {
 	flag.StringVar(&Config.StakingKeyFile, "staking-tls-key-file", "", "TLS private key file for staking connections")
 	flag.StringVar(&Config.StakingCertFile, "staking-tls-cert-file", "", "TLS certificate file for staking connections")
 
+	// Networking:
+	flag.StringVar(&Config.NetworkTransport, "network-transport", node.GoTransport, fmt.Sprintf("The p2p network implementation to use. Should be one of {%s, %s}", node.GoTransport, node.SalticidaeTransport))
+	flag.IntVar(&Config.MaxPeers, "max-peers", 100, "Maximum number of peers to connect to. Validators and bootstrap peers are always connected to. If 0, there is no limit")
+
 	// Logging:
 	logsDir := flag.String("log-dir", "", "Logging directory for Ava")
 	logLevel := flag.String("log-level", "info", "The log level. Should be one of {verbo, debug, info, warn, error, fatal, off}")
@@ -90,12 +125,15 @@ func init() {
 	flag.IntVar(&Config.ConsensusParams.BetaRogue, "snow-rogue-commit-threshold", 30, "Beta value to use for rogue transactions")
 	flag.IntVar(&Config.ConsensusParams.Parents, "snow-avalanche-num-parents", 5, "Number of vertexes for reference from each new vertex")
 	flag.IntVar(&Config.ConsensusParams.BatchSize, "snow-avalanche-batch-size", 30, "Number of operations to batch in each new vertex")
+	flag.IntVar(&Config.ConsensusBufferSize, "snow-buffer-size", 1000, "Number of messages from peers each chain buffers for its consensus engine. Messages beyond that are dropped")
+	flag.IntVar(&Config.ConsensusMaxPeerMessages, "snow-max-peer-messages", 200, "Number of messages from each peer each chain buffers for its consensus engine. Messages beyond that are dropped, and the peer is penalized")
 
 	// Enable/Disable APIs:
 	flag.BoolVar(&Config.AdminAPIEnabled, "api-admin-enabled", true, "If true, this node exposes the Admin API")
 	flag.BoolVar(&Config.KeystoreAPIEnabled, "api-keystore-enabled", true, "If true, this node exposes the Keystore API")
 	flag.BoolVar(&Config.MetricsAPIEnabled, "api-metrics-enabled", true, "If true, this node exposes the Metrics API")
 	flag.BoolVar(&Config.IPCEnabled, "api-ipcs-enabled", false, "If true, IPCs can be opened")
+	flag.BoolVar(&Config.EventsAPIEnabled, "api-events-enabled", true, "If true, this node exposes the Events API, which streams the events of its chains over websockets")
 
 	// Throughput Server
 	throughputPort := flag.Uint("xput-server-port", 9652, "Port of the deprecated throughput test server")
@@ -103,17 +141,42 @@ func init() {
 
 	flag.Parse()
 
+	if err := applyOverrides(flag.CommandLine, *configFile); err != nil {
+		errs.Add(err)
+		return
+	}
+
 	networkID, err := genesis.NetworkID(*networkName)
-	errs.Add(err)
+	errs.Add(flagErr("network-id", err))
 
-	if networkID != genesis.LocalID {
-		errs.Add(fmt.Errorf("the only supported networkID is: %s", genesis.LocalName))
+	// Genesis:
+	if *genesisFile == "" {
+		genesisBytes, err := genesis.Genesis(networkID)
+		errs.Add(err)
+		Config.GenesisBytes = genesisBytes
+	} else if genesisConfig, err := genesis.ConfigFromFile(*genesisFile); err != nil {
+		errs.Add(flagErr("genesis-file", err))
+	} else {
+		// Unless it is given explicitly, the network ID is taken from the
+		// genesis file
+		if !flagSet("network-id") {
+			networkID = uint32(genesisConfig.NetworkID)
+		}
+		genesisBytes, err := genesis.FromConfig(genesisConfig)
+		errs.Add(genesisConfig.VerifyNetworkID(networkID), err)
+		Config.GenesisBytes = genesisBytes
+		Config.ChainAliases = genesisConfig.ChainAliases
 	}
 
 	Config.NetworkID = networkID
 
+	// Staking rewards:
+	if Config.UptimeRequirement < 0 || Config.UptimeRequirement > 1 {
+		errs.Add(flagErr("uptime-requirement", errInvalidUptimeRequired))
+	}
+
 	// DB:
-	if *db && err == nil {
+	if *db && err == nil && !dumpConfig {
 		// TODO: Add better params here
 		dbPath := path.Join(*dbDir, genesis.NetworkName(Config.NetworkID))
 		db, err := leveldb.New(dbPath, 0, 0, 0)
@@ -135,7 +198,7 @@ func init() {
 	}
 
 	if ip == nil {
-		errs.Add(fmt.Errorf("Invalid IP Address %s", *consensusIP))
+		errs.Add(flagErr("public-ip", fmt.Errorf("Invalid IP Address %s", *consensusIP)))
 	}
 	Config.StakingIP = utils.IPDesc{
 		IP:   ip,
@@ -143,10 +206,16 @@ func init() {
 	}
 
 	// Bootstrapping:
+	if Config.BootstrapStakeFraction <= 0 || Config.BootstrapStakeFraction > 1 {
+		errs.Add(flagErr("bootstrap-stake-fraction", errInvalidBootstrapStake))
+	}
+	if Config.BootstrapRetries < 0 {
+		errs.Add(flagErr("bootstrap-retries", errNegativeRetries))
+	}
 	for _, ip := range strings.Split(*bootstrapIPs, ",") {
 		if ip != "" {
 			addr, err := utils.ToIPDesc(ip)
-			errs.Add(err)
+			errs.Add(flagErr("bootstrap-ips", err))
 			Config.BootstrapPeers = append(Config.BootstrapPeers, &node.Peer{
 				IP: addr,
 			})
@@ -157,9 +226,9 @@ func init() {
 		cb58 := formatting.CB58{}
 		for _, id := range strings.Split(*bootstrapIDs, ",") {
 			if id != "" {
-				errs.Add(cb58.FromString(id))
+				errs.Add(flagErr("bootstrap-ids", cb58.FromString(id)))
 				cert, err := ids.ToShortID(cb58.Bytes)
-				errs.Add(err)
+				errs.Add(flagErr("bootstrap-ids", err))
 
 				if len(Config.BootstrapPeers) <= i {
 					errs.Add(errBootstrapMismatch)
@@ -186,14 +255,14 @@ func init() {
 		loggingConfig.Directory = *logsDir
 	}
 	logFileLevel, err := logging.ToLevel(*logLevel)
-	errs.Add(err)
+	errs.Add(flagErr("log-level", err))
 	loggingConfig.LogLevel = logFileLevel
 
 	if *logDisplayLevel == "" {
 		*logDisplayLevel = *logLevel
 	}
 	displayLevel, err := logging.ToLevel(*logDisplayLevel)
-	errs.Add(err)
+	errs.Add(flagErr("log-display-level", err))
 	loggingConfig.DisplayLevel = displayLevel
 
 	Config.LoggingConfig = loggingConfig
@@ -201,6 +270,34 @@ func init() {
 	// Throughput:
 	Config.ThroughputPort = uint16(*throughputPort)
 
+	// Consensus:
+	if Config.ConsensusBufferSize <= 0 {
+		errs.Add(flagErr("snow-buffer-size", errInvalidBufferSize))
+	}
+	if Config.ConsensusMaxPeerMessages <= 0 {
+		errs.Add(flagErr("snow-max-peer-messages", errInvalidBufferSize))
+	}
+
 	// Router used for consensus
 	Config.ConsensusRouter = &router.ChainRouter{}
 }
+
+// flagErr attributes [err], if any, to the key that set flag [name]
+func flagErr(name string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%s: %w", source(name), err)
+}
+
+// flagSet returns true if the flag [name] was set explicitly, rather than left
+// to its default value
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
@@ -0,0 +1,243 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils"
+)
+
+var (
+	TestBuilder Builder
+)
+
+func TestBuildGetVersion(t *testing.T) {
+	msg, err := TestBuilder.GetVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if op := msg.Op(); op != GetVersion {
+		t.Fatalf("expected op %s but got %s", GetVersion, op)
+	}
+
+	parsedMsg, err := TestBuilder.Parse(msg.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if op := parsedMsg.Op(); op != GetVersion {
+		t.Fatalf("expected op %s but got %s", GetVersion, op)
+	}
+}
+
+func TestBuildVersion(t *testing.T) {
+	networkID := uint32(12345)
+	myTime := uint64(1234567890)
+	ip := utils.IPDesc{
+		IP:   net.IPv4(1, 2, 3, 4),
+		Port: 5678,
+	}
+	myVersion := "xD"
+
+	msg, err := TestBuilder.Version(networkID, myTime, ip, myVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsedMsg, err := TestBuilder.Parse(msg.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if op := parsedMsg.Op(); op != Version {
+		t.Fatalf("expected op %s but got %s", Version, op)
+	}
+	if parsedNetworkID := parsedMsg.Get(NetworkID).(uint32); parsedNetworkID != networkID {
+		t.Fatalf("expected network ID %d but got %d", networkID, parsedNetworkID)
+	}
+	if parsedTime := parsedMsg.Get(MyTime).(uint64); parsedTime != myTime {
+		t.Fatalf("expected time %d but got %d", myTime, parsedTime)
+	}
+	if parsedIP := parsedMsg.Get(IP).(utils.IPDesc); !parsedIP.Equal(ip) {
+		t.Fatalf("expected ip %s but got %s", ip, parsedIP)
+	}
+	if parsedVersion := parsedMsg.Get(VersionStr).(string); parsedVersion != myVersion {
+		t.Fatalf("expected version %s but got %s", myVersion, parsedVersion)
+	}
+}
+
+func TestBuildPeerList(t *testing.T) {
+	ips := []utils.IPDesc{
+		{
+			IP:   net.IPv6loopback,
+			Port: 12345,
+		},
+		{
+			IP:   net.IPv6loopback,
+			Port: 54321,
+		},
+	}
+
+	msg, err := TestBuilder.PeerList(ips)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsedMsg, err := TestBuilder.Parse(msg.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if op := parsedMsg.Op(); op != PeerList {
+		t.Fatalf("expected op %s but got %s", PeerList, op)
+	}
+	parsedIPs := parsedMsg.Get(Peers).([]utils.IPDesc)
+	if len(parsedIPs) != len(ips) {
+		t.Fatalf("expected %d ips but got %d", len(ips), len(parsedIPs))
+	}
+	for i, ip := range ips {
+		if !parsedIPs[i].Equal(ip) {
+			t.Fatalf("expected ip %s but got %s", ip, parsedIPs[i])
+		}
+	}
+}
+
+func TestBuildPushQuery(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+	requestID := uint32(5)
+	containerID := ids.Empty.Prefix(1)
+	container := []byte{2}
+
+	msg, err := TestBuilder.PushQuery(chainID, requestID, containerID, container)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsedMsg, err := TestBuilder.Parse(msg.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if op := parsedMsg.Op(); op != PushQuery {
+		t.Fatalf("expected op %s but got %s", PushQuery, op)
+	}
+	if parsedChainID := parsedMsg.Get(ChainID).([]byte); !bytes.Equal(parsedChainID, chainID.Bytes()) {
+		t.Fatalf("wrong chainID")
+	}
+	if parsedRequestID := parsedMsg.Get(RequestID).(uint32); parsedRequestID != requestID {
+		t.Fatalf("expected request ID %d but got %d", requestID, parsedRequestID)
+	}
+	if parsedContainerID := parsedMsg.Get(ContainerID).([]byte); !bytes.Equal(parsedContainerID, containerID.Bytes()) {
+		t.Fatalf("wrong containerID")
+	}
+	if parsedContainer := parsedMsg.Get(ContainerBytes).([]byte); !bytes.Equal(parsedContainer, container) {
+		t.Fatalf("wrong container")
+	}
+}
+
+func TestBuildChits(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+	requestID := uint32(5)
+	containerID := ids.Empty.Prefix(1)
+	containerIDSet := ids.Set{}
+	containerIDSet.Add(containerID)
+
+	msg, err := TestBuilder.Chits(chainID, requestID, containerIDSet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsedMsg, err := TestBuilder.Parse(msg.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if op := parsedMsg.Op(); op != Chits {
+		t.Fatalf("expected op %s but got %s", Chits, op)
+	}
+	parsedContainerIDs := parsedMsg.Get(ContainerIDs).([][]byte)
+	if len(parsedContainerIDs) != 1 {
+		t.Fatalf("expected 1 container ID but got %d", len(parsedContainerIDs))
+	}
+	if !bytes.Equal(parsedContainerIDs[0], containerID.Bytes()) {
+		t.Fatalf("wrong containerID")
+	}
+}
+
+func TestBuildGetAncestors(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+	requestID := uint32(5)
+	containerID := ids.Empty.Prefix(1)
+
+	msg, err := TestBuilder.GetAncestors(chainID, requestID, containerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsedMsg, err := TestBuilder.Parse(msg.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if op := parsedMsg.Op(); op != GetAncestors {
+		t.Fatalf("expected op %s but got %s", GetAncestors, op)
+	}
+	if parsedChainID := parsedMsg.Get(ChainID).([]byte); !bytes.Equal(parsedChainID, chainID.Bytes()) {
+		t.Fatalf("wrong chainID")
+	}
+	if parsedRequestID := parsedMsg.Get(RequestID).(uint32); parsedRequestID != requestID {
+		t.Fatalf("expected request ID %d but got %d", requestID, parsedRequestID)
+	}
+	if parsedContainerID := parsedMsg.Get(ContainerID).([]byte); !bytes.Equal(parsedContainerID, containerID.Bytes()) {
+		t.Fatalf("wrong containerID")
+	}
+}
+
+func TestBuildMultiPut(t *testing.T) {
+	chainID := ids.Empty.Prefix(0)
+	requestID := uint32(5)
+	containers := [][]byte{{1}, {2, 3}}
+
+	msg, err := TestBuilder.MultiPut(chainID, requestID, containers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsedMsg, err := TestBuilder.Parse(msg.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if op := parsedMsg.Op(); op != MultiPut {
+		t.Fatalf("expected op %s but got %s", MultiPut, op)
+	}
+	if parsedChainID := parsedMsg.Get(ChainID).([]byte); !bytes.Equal(parsedChainID, chainID.Bytes()) {
+		t.Fatalf("wrong chainID")
+	}
+	if parsedRequestID := parsedMsg.Get(RequestID).(uint32); parsedRequestID != requestID {
+		t.Fatalf("expected request ID %d but got %d", requestID, parsedRequestID)
+	}
+	parsedContainers := parsedMsg.Get(MultiContainerBytes).([][]byte)
+	if len(parsedContainers) != len(containers) {
+		t.Fatalf("expected %d containers but got %d", len(containers), len(parsedContainers))
+	}
+	for i, container := range containers {
+		if !bytes.Equal(parsedContainers[i], container) {
+			t.Fatalf("wrong container")
+		}
+	}
+}
+
+func TestParseBadOp(t *testing.T) {
+	if _, err := TestBuilder.Parse([]byte{0xff}); err == nil {
+		t.Fatalf("should have failed to parse an unknown op")
+	}
+}
+
+func TestParseTrailingBytes(t *testing.T) {
+	msg, err := TestBuilder.Ping()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := TestBuilder.Parse(append(msg.Bytes(), 0)); err == nil {
+		t.Fatalf("should have failed to parse a message with trailing bytes")
+	}
+}
@@ -0,0 +1,82 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+var (
+	errBadLength    = errors.New("stream has unexpected length")
+	errMissingField = errors.New("message missing field")
+	errBadOp        = errors.New("input field has invalid operation")
+)
+
+// Codec defines the serialization and deserialization of network messages.
+// The first byte of a serialized message is its opcode.
+type Codec struct{}
+
+// Pack attempts to pack a map of fields into a message.
+func (Codec) Pack(op Op, fields map[Field]interface{}) (Msg, error) {
+	message, ok := Messages[op]
+	if !ok {
+		return nil, errBadOp
+	}
+
+	p := wrappers.Packer{MaxSize: math.MaxInt32}
+	p.PackByte(byte(op))
+	for _, field := range message {
+		data, ok := fields[field]
+		if !ok {
+			return nil, errMissingField
+		}
+		field.Packer()(&p, data)
+	}
+
+	if p.Errored() {
+		return nil, p.Err
+	}
+
+	return &msg{
+		op:     op,
+		fields: fields,
+		bytes:  p.Bytes,
+	}, nil
+}
+
+// Parse attempts to convert bytes into a message.
+func (Codec) Parse(b []byte) (Msg, error) {
+	p := wrappers.Packer{Bytes: b}
+	op := Op(p.UnpackByte())
+	if p.Errored() {
+		return nil, p.Err
+	}
+
+	message, ok := Messages[op]
+	if !ok {
+		return nil, errBadOp
+	}
+
+	fields := make(map[Field]interface{}, len(message))
+	for _, field := range message {
+		fields[field] = field.Unpacker()(&p)
+	}
+
+	if p.Errored() {
+		return nil, p.Err
+	}
+
+	if p.Offset != len(b) {
+		return nil, errBadLength
+	}
+
+	return &msg{
+		op:     op,
+		fields: fields,
+		bytes:  b,
+	}, nil
+}
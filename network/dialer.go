@@ -0,0 +1,34 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"net"
+	"time"
+
+	"github.com/ava-labs/gecko/utils"
+)
+
+// Dialer attempts to create a connection with the provided IP/port pair
+type Dialer interface {
+	Dial(utils.IPDesc) (net.Conn, error)
+}
+
+type dialer struct {
+	network string
+	timeout time.Duration
+}
+
+// NewDialer returns a new Dialer that calls `net.DialTimeout` with the provided
+// network and timeout.
+func NewDialer(network string, timeout time.Duration) Dialer {
+	return &dialer{
+		network: network,
+		timeout: timeout,
+	}
+}
+
+func (d *dialer) Dial(ip utils.IPDesc) (net.Conn, error) {
+	return net.DialTimeout(d.network, ip.String(), d.timeout)
+}
@@ -0,0 +1,132 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+type messageMetrics struct {
+	numSent, numFailed, numReceived prometheus.Counter
+}
+
+func (mm *messageMetrics) initialize(msgType Op, registerer prometheus.Registerer) error {
+	mm.numSent = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      fmt.Sprintf("network_%s_sent", msgType),
+			Help:      fmt.Sprintf("Number of %s messages sent", msgType),
+		})
+	mm.numFailed = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      fmt.Sprintf("network_%s_failed", msgType),
+			Help:      fmt.Sprintf("Number of %s messages that failed to be sent", msgType),
+		})
+	mm.numReceived = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      fmt.Sprintf("network_%s_received", msgType),
+			Help:      fmt.Sprintf("Number of %s messages received", msgType),
+		})
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(mm.numSent),
+		registerer.Register(mm.numFailed),
+		registerer.Register(mm.numReceived),
+	)
+	return errs.Err
+}
+
+type metrics struct {
+	numPeers prometheus.Gauge
+
+	getVersion, version,
+	getPeerlist, peerlist,
+	ping, pong,
+	getAcceptedFrontier, acceptedFrontier,
+	getAccepted, accepted,
+	getAncestors, multiPut,
+	get, put,
+	pushQuery, pullQuery, chits messageMetrics
+}
+
+func (m *metrics) initialize(registerer prometheus.Registerer) error {
+	m.numPeers = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "gecko",
+			Name:      "network_peers",
+			Help:      "Number of network peers",
+		})
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(m.numPeers),
+
+		m.getVersion.initialize(GetVersion, registerer),
+		m.version.initialize(Version, registerer),
+		m.getPeerlist.initialize(GetPeerList, registerer),
+		m.peerlist.initialize(PeerList, registerer),
+		m.ping.initialize(Ping, registerer),
+		m.pong.initialize(Pong, registerer),
+		m.getAcceptedFrontier.initialize(GetAcceptedFrontier, registerer),
+		m.acceptedFrontier.initialize(AcceptedFrontier, registerer),
+		m.getAccepted.initialize(GetAccepted, registerer),
+		m.accepted.initialize(Accepted, registerer),
+		m.getAncestors.initialize(GetAncestors, registerer),
+		m.multiPut.initialize(MultiPut, registerer),
+		m.get.initialize(Get, registerer),
+		m.put.initialize(Put, registerer),
+		m.pushQuery.initialize(PushQuery, registerer),
+		m.pullQuery.initialize(PullQuery, registerer),
+		m.chits.initialize(Chits, registerer),
+	)
+	return errs.Err
+}
+
+func (m *metrics) message(msgType Op) *messageMetrics {
+	switch msgType {
+	case GetVersion:
+		return &m.getVersion
+	case Version:
+		return &m.version
+	case GetPeerList:
+		return &m.getPeerlist
+	case PeerList:
+		return &m.peerlist
+	case Ping:
+		return &m.ping
+	case Pong:
+		return &m.pong
+	case GetAcceptedFrontier:
+		return &m.getAcceptedFrontier
+	case AcceptedFrontier:
+		return &m.acceptedFrontier
+	case GetAccepted:
+		return &m.getAccepted
+	case Accepted:
+		return &m.accepted
+	case GetAncestors:
+		return &m.getAncestors
+	case MultiPut:
+		return &m.multiPut
+	case Get:
+		return &m.get
+	case Put:
+		return &m.put
+	case PushQuery:
+		return &m.pushQuery
+	case PullQuery:
+		return &m.pullQuery
+	case Chits:
+		return &m.chits
+	default:
+		return nil
+	}
+}
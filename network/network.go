@@ -0,0 +1,1264 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/networking"
+	"github.com/ava-labs/gecko/snow/networking/router"
+	"github.com/ava-labs/gecko/snow/networking/sender"
+	"github.com/ava-labs/gecko/snow/triggers"
+	"github.com/ava-labs/gecko/snow/validators"
+	"github.com/ava-labs/gecko/utils"
+	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/utils/random"
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+const (
+	// CurrentVersion this avalanche instance is executing.
+	CurrentVersion = "avalanche/0.0.1"
+
+	// DefaultMaxMessageSize is the largest message, in bytes, that will be
+	// read from a peer.
+	DefaultMaxMessageSize uint32 = 1 << 21
+
+	defaultInitialReconnectDelay        = time.Second
+	defaultMaxReconnectDelay            = time.Minute
+	defaultSendQueueSize                = 1 << 10
+	defaultMaxClockDifference           = time.Minute
+	defaultPeerListGossipSpacing        = time.Minute
+	defaultPeerListGossipSize           = 100
+	defaultPeerListStakerGossipFraction = 2
+	defaultPingPongTimeout              = time.Minute
+	defaultPingFrequency                = 3 * defaultPingPongTimeout / 4
+	defaultMaxPeerFailures              = 10
+	defaultPeerExpiry                   = 7 * 24 * time.Hour
+	defaultMaxPersistedPeers            = 1000
+
+	// maxPeerListIPs is the number of IPs in a PeerList message that are
+	// connected to. The rest are ignored.
+	maxPeerListIPs = 256
+
+	// maxDiscoveredIPs is the number of IPs the network attempts to connect to
+	// at once, beyond which IPs learned from peers are ignored
+	maxDiscoveredIPs = 1024
+)
+
+var (
+	errTooManyPeers = errors.New("too many peers")
+)
+
+// Network defines the functionality of the networking library.
+type Network interface {
+	// All consensus messages can be sent through this interface. Thread safety
+	// must be managed internally in the network.
+	sender.ExternalSender
+
+	// The network must be able to broadcast accepted decisions to random peers.
+	// Thread safety must be managed internally in the network.
+	triggers.Acceptor
+
+	// Should only be called once, will run until either a fatal error occurs,
+	// or the network is closed. Returns nil if the network was closed.
+	Dispatch() error
+
+	// Attempt to connect to this IP. Thread safety must be managed internally
+	// to the network. The network will never stop attempting to connect to
+	// this IP.
+	Track(ip utils.IPDesc)
+
+	// Register a set of peers to wait for. Finish is called on [awaiting]
+	// once enough of the requested peers are connected. Thread safety must be
+	// managed internally in the network.
+	AwaitConnections(awaiting *networking.AwaitingConnections)
+
+	// Register a connector to notify of connections and disconnections. The
+	// connector is first notified of the peers this node is already connected
+	// to. Thread safety must be managed internally in the network.
+	RegisterConnector(connector networking.Connector)
+
+	// Returns the description of the nodes this network is currently
+	// connected to. Thread safety must be managed internally to the network.
+	Peers() []utils.IPDesc
+
+	// Close this network and all existing connections it has. Thread safety
+	// must be managed internally to the network. Calling close multiple times
+	// will return a nil error.
+	Close() error
+}
+
+type network struct {
+	// The metrics that this network tracks
+	metrics
+
+	log            logging.Logger
+	id             ids.ShortID
+	ip             utils.IPDesc
+	networkID      uint32
+	version        string
+	listener       net.Listener
+	dialer         Dialer
+	serverUpgrader Upgrader
+	clientUpgrader Upgrader
+	vdrs           validators.Set // set of current validators in the AVAnet
+	router         router.Router  // router must be thread safe
+
+	// if staking is disabled, every connected peer is treated as a validator
+	enableStaking bool
+
+	// peers that this node dialed, which are reconnected to after a restart.
+	// Must only be accessed with the stateLock not held.
+	peerDB peerDB
+
+	// if positive, at most this many peers are kept. Validators and tracked
+	// IPs are always connected to, if need be by disconnecting from another
+	// peer.
+	maxPeers int
+
+	clock    timer.Clock
+	b        Builder
+	executor timer.Executor
+
+	initialReconnectDelay        time.Duration
+	maxReconnectDelay            time.Duration
+	maxMessageSize               uint32
+	sendQueueSize                int
+	maxClockDifference           time.Duration
+	peerListGossipSpacing        time.Duration
+	peerListGossipSize           int
+	peerListStakerGossipFraction int
+	pingPongTimeout              time.Duration
+	pingFrequency                time.Duration
+	maxPeerFailures              uint32
+	peerExpiry                   time.Duration
+
+	gossiper *timer.Repeater
+	pinger   *timer.Repeater
+
+	stateLock sync.Mutex
+	closed    bool
+
+	// IPs that we are attempting to connect to
+	disconnectedIPs map[string]struct{}
+	// IPs of peers that we currently have a connection to
+	connectedIPs map[string]struct{}
+	// IPs that resulted in a connection to ourselves
+	myIPs map[string]struct{}
+	// IPs that were explicitly tracked, which are never given up on
+	pinnedIPs map[string]struct{}
+	// Key: IP ; Value: number of consecutive failed attempts to connect to it
+	failures map[string]uint32
+	// Key: IP ; Value: ID of the peer that was last connected to at the IP
+	ipIDs map[string]ids.ShortID
+	// peers that have been upgraded, but not necessarily finished the
+	// handshake
+	peers map[[20]byte]*peer
+
+	awaiting   []*networking.AwaitingConnections
+	connectors []networking.Connector
+}
+
+// NewDefaultNetwork returns a new Network implementation with the provided
+// parameters and some reasonable default values.
+func NewDefaultNetwork(
+	registerer prometheus.Registerer,
+	log logging.Logger,
+	id ids.ShortID,
+	ip utils.IPDesc,
+	networkID uint32,
+	version string,
+	listener net.Listener,
+	dialer Dialer,
+	serverUpgrader,
+	clientUpgrader Upgrader,
+	vdrs validators.Set,
+	router router.Router,
+	enableStaking bool,
+	db database.Database,
+	maxPeers int,
+) Network {
+	return NewNetwork(
+		registerer,
+		log,
+		id,
+		ip,
+		networkID,
+		version,
+		listener,
+		dialer,
+		serverUpgrader,
+		clientUpgrader,
+		vdrs,
+		router,
+		enableStaking,
+		db,
+		maxPeers,
+		defaultInitialReconnectDelay,
+		defaultMaxReconnectDelay,
+		DefaultMaxMessageSize,
+		defaultSendQueueSize,
+		defaultMaxClockDifference,
+		defaultPeerListGossipSpacing,
+		defaultPeerListGossipSize,
+		defaultPeerListStakerGossipFraction,
+		defaultPingPongTimeout,
+		defaultPingFrequency,
+		defaultMaxPeerFailures,
+		defaultPeerExpiry,
+	)
+}
+
+// NewNetwork returns a new Network implementation with the provided parameters.
+func NewNetwork(
+	registerer prometheus.Registerer,
+	log logging.Logger,
+	id ids.ShortID,
+	ip utils.IPDesc,
+	networkID uint32,
+	version string,
+	listener net.Listener,
+	dialer Dialer,
+	serverUpgrader,
+	clientUpgrader Upgrader,
+	vdrs validators.Set,
+	router router.Router,
+	enableStaking bool,
+	db database.Database,
+	maxPeers int,
+	initialReconnectDelay,
+	maxReconnectDelay time.Duration,
+	maxMessageSize uint32,
+	sendQueueSize int,
+	maxClockDifference time.Duration,
+	peerListGossipSpacing time.Duration,
+	peerListGossipSize int,
+	peerListStakerGossipFraction int,
+	pingPongTimeout time.Duration,
+	pingFrequency time.Duration,
+	maxPeerFailures uint32,
+	peerExpiry time.Duration,
+) Network {
+	net := &network{
+		log:                          log,
+		id:                           id,
+		ip:                           ip,
+		networkID:                    networkID,
+		version:                      version,
+		listener:                     listener,
+		dialer:                       dialer,
+		serverUpgrader:               serverUpgrader,
+		clientUpgrader:               clientUpgrader,
+		vdrs:                         vdrs,
+		router:                       router,
+		enableStaking:                enableStaking,
+		peerDB:                       peerDB{db: db, maxPeers: defaultMaxPersistedPeers},
+		maxPeers:                     maxPeers,
+		initialReconnectDelay:        initialReconnectDelay,
+		maxReconnectDelay:            maxReconnectDelay,
+		maxMessageSize:               maxMessageSize,
+		sendQueueSize:                sendQueueSize,
+		maxClockDifference:           maxClockDifference,
+		peerListGossipSpacing:        peerListGossipSpacing,
+		peerListGossipSize:           peerListGossipSize,
+		peerListStakerGossipFraction: peerListStakerGossipFraction,
+		pingPongTimeout:              pingPongTimeout,
+		pingFrequency:                pingFrequency,
+		maxPeerFailures:              maxPeerFailures,
+		peerExpiry:                   peerExpiry,
+		disconnectedIPs:              make(map[string]struct{}),
+		connectedIPs:                 make(map[string]struct{}),
+		myIPs:                        map[string]struct{}{ip.String(): struct{}{}},
+		pinnedIPs:                    make(map[string]struct{}),
+		failures:                     make(map[string]uint32),
+		ipIDs:                        make(map[string]ids.ShortID),
+		peers:                        make(map[[20]byte]*peer),
+	}
+	if err := net.initialize(registerer); err != nil {
+		log.Warn("initializing network metrics failed with: %s", err)
+	}
+
+	net.executor.Initialize()
+	go log.RecoverAndPanic(net.executor.Dispatch)
+
+	net.gossiper = timer.NewRepeater(net.gossip, peerListGossipSpacing)
+	go log.RecoverAndPanic(net.gossiper.Dispatch)
+
+	net.pinger = timer.NewRepeater(net.ping, pingFrequency)
+	go log.RecoverAndPanic(net.pinger.Dispatch)
+	return net
+}
+
+// GetAcceptedFrontier implements the Sender interface.
+// assumes the stateLock is not held.
+func (n *network) GetAcceptedFrontier(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32) {
+	msg, err := n.b.GetAcceptedFrontier(chainID, requestID)
+	n.log.AssertNoError(err)
+
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	for _, validatorID := range validatorIDs.List() {
+		vID := validatorID
+		if !n.send(msg, vID) {
+			n.getAcceptedFrontier.numFailed.Inc()
+			n.log.Debug("failed to send a GetAcceptedFrontier message to: %s", vID)
+			n.executor.Add(func() { n.router.GetAcceptedFrontierFailed(vID, chainID, requestID) })
+		} else {
+			n.getAcceptedFrontier.numSent.Inc()
+		}
+	}
+}
+
+// AcceptedFrontier implements the Sender interface.
+// assumes the stateLock is not held.
+func (n *network) AcceptedFrontier(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerIDs ids.Set) {
+	msg, err := n.b.AcceptedFrontier(chainID, requestID, containerIDs)
+	if err != nil {
+		n.log.Error("attempted to pack too large of an AcceptedFrontier message.\nNumber of containerIDs: %d",
+			containerIDs.Len())
+		return // Packing message failed
+	}
+
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	if !n.send(msg, validatorID) {
+		n.acceptedFrontier.numFailed.Inc()
+		n.log.Debug("failed to send an AcceptedFrontier message to: %s", validatorID)
+	} else {
+		n.acceptedFrontier.numSent.Inc()
+	}
+}
+
+// GetAccepted implements the Sender interface.
+// assumes the stateLock is not held.
+func (n *network) GetAccepted(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, containerIDs ids.Set) {
+	msg, err := n.b.GetAccepted(chainID, requestID, containerIDs)
+	if err != nil {
+		n.log.Error("attempted to pack too large of a GetAccepted message.\nNumber of containerIDs: %d",
+			containerIDs.Len())
+		for _, validatorID := range validatorIDs.List() {
+			vID := validatorID
+			n.executor.Add(func() { n.router.GetAcceptedFailed(vID, chainID, requestID) })
+		}
+		return
+	}
+
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	for _, validatorID := range validatorIDs.List() {
+		vID := validatorID
+		if !n.send(msg, vID) {
+			n.getAccepted.numFailed.Inc()
+			n.log.Debug("failed to send a GetAccepted message to: %s", vID)
+			n.executor.Add(func() { n.router.GetAcceptedFailed(vID, chainID, requestID) })
+		} else {
+			n.getAccepted.numSent.Inc()
+		}
+	}
+}
+
+// Accepted implements the Sender interface.
+// assumes the stateLock is not held.
+func (n *network) Accepted(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerIDs ids.Set) {
+	msg, err := n.b.Accepted(chainID, requestID, containerIDs)
+	if err != nil {
+		n.log.Error("attempted to pack too large of an Accepted message.\nNumber of containerIDs: %d",
+			containerIDs.Len())
+		return // Packing message failed
+	}
+
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	if !n.send(msg, validatorID) {
+		n.accepted.numFailed.Inc()
+		n.log.Debug("failed to send an Accepted message to: %s", validatorID)
+	} else {
+		n.accepted.numSent.Inc()
+	}
+}
+
+// GetAncestors implements the Sender interface.
+// assumes the stateLock is not held.
+func (n *network) GetAncestors(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID) {
+	msg, err := n.b.GetAncestors(chainID, requestID, containerID)
+	n.log.AssertNoError(err)
+
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	if !n.send(msg, validatorID) {
+		n.getAncestors.numFailed.Inc()
+		n.log.Debug("failed to send a GetAncestors message to: %s", validatorID)
+		n.executor.Add(func() { n.router.GetAncestorsFailed(validatorID, chainID, requestID) })
+	} else {
+		n.getAncestors.numSent.Inc()
+	}
+}
+
+// MultiPut implements the Sender interface.
+// assumes the stateLock is not held.
+func (n *network) MultiPut(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containers [][]byte) {
+	msg, err := n.b.MultiPut(chainID, requestID, containers)
+	if err != nil {
+		n.log.Error("failed to build MultiPut message because of %d containers", len(containers))
+		return
+	}
+
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	if !n.send(msg, validatorID) {
+		n.multiPut.numFailed.Inc()
+		n.log.Debug("failed to send a MultiPut message to: %s", validatorID)
+	} else {
+		n.multiPut.numSent.Inc()
+	}
+}
+
+// Get implements the Sender interface.
+// assumes the stateLock is not held.
+func (n *network) Get(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID) {
+	msg, err := n.b.Get(chainID, requestID, containerID)
+	n.log.AssertNoError(err)
+
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	if !n.send(msg, validatorID) {
+		n.get.numFailed.Inc()
+		n.log.Debug("failed to send a Get message to: %s", validatorID)
+		n.executor.Add(func() { n.router.GetFailed(validatorID, chainID, requestID, containerID) })
+	} else {
+		n.get.numSent.Inc()
+	}
+}
+
+// Put implements the Sender interface.
+// assumes the stateLock is not held.
+func (n *network) Put(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID, container []byte) {
+	msg, err := n.b.Put(chainID, requestID, containerID, container)
+	if err != nil {
+		n.log.Error("failed to build Put message because of container of size %d", len(container))
+		return
+	}
+
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	if !n.send(msg, validatorID) {
+		n.put.numFailed.Inc()
+		n.log.Debug("failed to send a Put message to: %s", validatorID)
+	} else {
+		n.put.numSent.Inc()
+	}
+}
+
+// PushQuery implements the Sender interface.
+// assumes the stateLock is not held.
+func (n *network) PushQuery(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, containerID ids.ID, container []byte) {
+	msg, err := n.b.PushQuery(chainID, requestID, containerID, container)
+	if err != nil {
+		n.log.Error("attempted to pack too large of a PushQuery message.\nContainer length: %d", len(container))
+		for _, validatorID := range validatorIDs.List() {
+			vID := validatorID
+			n.executor.Add(func() { n.router.QueryFailed(vID, chainID, requestID) })
+		}
+		return // Packing message failed
+	}
+
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	for _, validatorID := range validatorIDs.List() {
+		vID := validatorID
+		if !n.send(msg, vID) {
+			n.pushQuery.numFailed.Inc()
+			n.log.Debug("failed to send a PushQuery message to: %s", vID)
+			n.executor.Add(func() { n.router.QueryFailed(vID, chainID, requestID) })
+		} else {
+			n.pushQuery.numSent.Inc()
+		}
+	}
+}
+
+// PullQuery implements the Sender interface.
+// assumes the stateLock is not held.
+func (n *network) PullQuery(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, containerID ids.ID) {
+	msg, err := n.b.PullQuery(chainID, requestID, containerID)
+	n.log.AssertNoError(err)
+
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	for _, validatorID := range validatorIDs.List() {
+		vID := validatorID
+		if !n.send(msg, vID) {
+			n.pullQuery.numFailed.Inc()
+			n.log.Debug("failed to send a PullQuery message to: %s", vID)
+			n.executor.Add(func() { n.router.QueryFailed(vID, chainID, requestID) })
+		} else {
+			n.pullQuery.numSent.Inc()
+		}
+	}
+}
+
+// Chits implements the Sender interface.
+// assumes the stateLock is not held.
+func (n *network) Chits(validatorID ids.ShortID, chainID ids.ID, requestID uint32, votes ids.Set) {
+	msg, err := n.b.Chits(chainID, requestID, votes)
+	if err != nil {
+		n.log.Error("attempted to pack too large of a Chits message.\nChits length: %d", votes.Len())
+		return
+	}
+
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	if !n.send(msg, validatorID) {
+		n.chits.numFailed.Inc()
+		n.log.Debug("failed to send a Chits message to: %s", validatorID)
+	} else {
+		n.chits.numSent.Inc()
+	}
+}
+
+// Accept is called after every consensus decision.
+// assumes the stateLock is not held.
+func (n *network) Accept(chainID, containerID ids.ID, container []byte) error {
+	msg, err := n.b.Put(chainID, 0, containerID, container)
+	if err != nil {
+		return fmt.Errorf("attempted to pack too large of a Put message.\nContainer length: %d: %w", len(container), err)
+	}
+
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	n.log.Verbo("sending a Put message to non-validators."+
+		"\nChain: %s"+
+		"\nContainer ID: %s"+
+		"\nContainer:\n%s",
+		chainID,
+		containerID,
+		formatting.DumpBytes{Bytes: container},
+	)
+
+	for _, p := range n.peers {
+		if !p.connected || n.vdrs.Contains(p.id) {
+			continue
+		}
+		if p.send(msg) {
+			n.put.numSent.Inc()
+		} else {
+			n.put.numFailed.Inc()
+		}
+	}
+	return nil
+}
+
+// Dispatch starts accepting connections from other nodes attempting to connect
+// to this node, and starts connecting to the peers discovered before the node
+// was restarted.
+// assumes the stateLock is not held.
+func (n *network) Dispatch() error {
+	n.restorePeers()
+	for {
+		conn, err := n.listener.Accept()
+		if err != nil {
+			n.stateLock.Lock()
+			closed := n.closed
+			n.stateLock.Unlock()
+
+			if closed {
+				return nil
+			}
+			return err
+		}
+		go n.upgrade(&peer{
+			net:  n,
+			conn: conn,
+		}, n.serverUpgrader)
+	}
+}
+
+// Track registers the provided IP to be connected to.
+// assumes the stateLock is not held.
+func (n *network) Track(ip utils.IPDesc) {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	n.pinnedIPs[ip.String()] = struct{}{}
+	n.track(ip)
+}
+
+// AwaitConnections calls Finish on [awaiting] once enough of the requested
+// peers have finished the handshake.
+// assumes the stateLock is not held.
+func (n *network) AwaitConnections(awaiting *networking.AwaitingConnections) {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	awaiting.Add(n.id)
+	for _, p := range n.peers {
+		if p.connected {
+			awaiting.Add(p.id)
+		}
+	}
+	if awaiting.Ready() {
+		go awaiting.Finish()
+	} else {
+		n.awaiting = append(n.awaiting, awaiting)
+	}
+}
+
+// RegisterConnector notifies [connector] of the peers this node is connected
+// to, and of every connection and disconnection from now on.
+// assumes the stateLock is not held.
+func (n *network) RegisterConnector(connector networking.Connector) {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	for _, p := range n.peers {
+		if p.connected {
+			connector.Connected(p.id)
+		}
+	}
+	n.connectors = append(n.connectors, connector)
+}
+
+// Peers returns the IPs of the peers this node has finished the handshake
+// with.
+// assumes the stateLock is not held.
+func (n *network) Peers() []utils.IPDesc {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	ips := []utils.IPDesc(nil)
+	for _, p := range n.peers {
+		if p.connected && !p.ip.IsZero() {
+			ips = append(ips, p.ip)
+		}
+	}
+	return ips
+}
+
+// Close this network and all existing connections it has.
+// assumes the stateLock is not held.
+func (n *network) Close() error {
+	n.stateLock.Lock()
+	if n.closed {
+		n.stateLock.Unlock()
+		return nil
+	}
+	n.closed = true
+	err := n.listener.Close()
+
+	peersToClose := make([]*peer, 0, len(n.peers))
+	for _, p := range n.peers {
+		peersToClose = append(peersToClose, p)
+	}
+	n.stateLock.Unlock()
+
+	for _, p := range peersToClose {
+		p.Close() // Grabs the stateLock
+	}
+
+	n.gossiper.Stop()
+	n.pinger.Stop()
+	n.executor.Stop()
+	return err
+}
+
+// send the message to the peer with the provided ID. Returns true if the
+// message was queued to be sent.
+// assumes the stateLock is held.
+func (n *network) send(msg Msg, validatorID ids.ShortID) bool {
+	p, ok := n.peers[validatorID.Key()]
+	return ok && p.connected && p.send(msg)
+}
+
+// track registers the provided IP to be connected to. Returns true if the
+// network started connecting to the IP.
+// assumes the stateLock is held.
+func (n *network) track(ip utils.IPDesc) bool {
+	if n.closed {
+		return false
+	}
+
+	str := ip.String()
+	if _, ok := n.disconnectedIPs[str]; ok {
+		return false
+	}
+	if _, ok := n.connectedIPs[str]; ok {
+		return false
+	}
+	if _, ok := n.myIPs[str]; ok {
+		return false
+	}
+	n.disconnectedIPs[str] = struct{}{}
+
+	go n.log.RecoverAndPanic(func() { n.connectTo(ip) })
+	return true
+}
+
+// discovered registers the provided IP, which was learned from a peer, to be
+// connected to, unless the network is already attempting to connect to too
+// many IPs. If [claimedBy] isn't nil, it's the ID of the peer that claims to
+// listen on the IP. The IP isn't gossiped until this node has connected to
+// it.
+// assumes the stateLock is held.
+func (n *network) discovered(ip utils.IPDesc, claimedBy *ids.ShortID) {
+	if ip.IsZero() || ip.Equal(n.ip) {
+		return
+	}
+	if len(n.disconnectedIPs) >= maxDiscoveredIPs {
+		n.log.Verbo("ignoring %s as too many IPs are being connected to", ip)
+		return
+	}
+	if !n.track(ip) || claimedBy == nil {
+		return
+	}
+	str := ip.String()
+	if _, known := n.ipIDs[str]; !known {
+		n.ipIDs[str] = *claimedBy
+	}
+}
+
+// discardIP stops the network from connecting to the provided IP. The IP
+// should be removed from the peer database with forget once the stateLock is
+// released.
+// assumes the stateLock is held.
+func (n *network) discardIP(ip utils.IPDesc) {
+	str := ip.String()
+	delete(n.disconnectedIPs, str)
+	delete(n.connectedIPs, str)
+	delete(n.pinnedIPs, str)
+	delete(n.failures, str)
+	delete(n.ipIDs, str)
+}
+
+// forget removes the provided IP from the peer database.
+// assumes the stateLock is not held.
+func (n *network) forget(ip utils.IPDesc) {
+	if err := n.peerDB.remove(ip); err != nil {
+		n.log.Warn("failed to remove %s from the peer database: %s", ip, err)
+	}
+}
+
+// restorePeers starts connecting to the peers in the peer database. Peers that
+// haven't been connected to in too long, and peers that aren't validators and
+// have failed too many times, are forgotten. Validators are connected to
+// first.
+// assumes the stateLock is not held.
+func (n *network) restorePeers() {
+	peers, err := n.peerDB.peers()
+	if err != nil {
+		n.log.Error("failed to read the peer database: %s", err)
+		return
+	}
+
+	now := n.clock.Unix()
+	expiry := uint64(n.peerExpiry.Seconds())
+	restored := []*peerInfo(nil)
+	for _, info := range peers {
+		expired := info.lastSeen+expiry < now
+		failing := !n.vdrs.Contains(info.id) && n.maxPeerFailures > 0 && info.failures >= n.maxPeerFailures
+		if expired || failing {
+			n.log.Debug("forgetting %s at %s", info.id, info.ip)
+			n.forget(info.ip)
+			continue
+		}
+		restored = append(restored, info)
+	}
+
+	sort.Slice(restored, func(i, j int) bool {
+		iIsValidator := n.vdrs.Contains(restored[i].id)
+		jIsValidator := n.vdrs.Contains(restored[j].id)
+		if iIsValidator != jIsValidator {
+			return iIsValidator
+		}
+		return restored[i].lastSeen > restored[j].lastSeen
+	})
+
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	n.log.Info("restoring %d peer(s) from the peer database", len(restored))
+	for _, info := range restored {
+		str := info.ip.String()
+		n.failures[str] = info.failures
+		n.ipIDs[str] = info.id
+		n.track(info.ip)
+	}
+}
+
+// connectTo attempts to connect to the provided IP until either a connection
+// is established, the IP is no longer tracked, or the network is closed.
+// assumes the stateLock is not held.
+func (n *network) connectTo(ip utils.IPDesc) {
+	str := ip.String()
+
+	n.stateLock.Lock()
+	delay := n.reconnectDelay(ip)
+	n.stateLock.Unlock()
+
+	for {
+		n.stateLock.Lock()
+		_, isDisconnected := n.disconnectedIPs[str]
+		_, isConnected := n.connectedIPs[str]
+		_, isMyself := n.myIPs[str]
+		closed := n.closed
+		hasRoom := n.hasRoom(ip)
+		n.stateLock.Unlock()
+
+		if !isDisconnected || isConnected || isMyself || closed {
+			// If the IP was discovered by the peer connecting to us, we don't
+			// need to attempt to connect anymore
+
+			// If the IP was discovered to be our IP address, we don't need to
+			// attempt to connect anymore
+
+			// If the network was closed, we should stop attempting to connect
+			// to the peer
+			return
+		}
+
+		// Skipping an attempt because there are too many peers counts as a
+		// failure, so that IPs that aren't prioritized are eventually given up
+		// on
+		err := errTooManyPeers
+		if hasRoom {
+			err = n.attemptConnect(ip)
+			if err == nil {
+				return
+			}
+		}
+		n.log.Verbo("error attempting to connect to %s: %s. Reattempting in %s",
+			ip, err, delay)
+
+		n.stateLock.Lock()
+		giveUp := n.failed(ip)
+		n.stateLock.Unlock()
+
+		// Only failing to reach the peer counts against it in the peer
+		// database
+		if err != errTooManyPeers {
+			if giveUp {
+				n.forget(ip)
+			} else if err := n.peerDB.failed(ip); err != nil {
+				n.log.Warn("failed to store %s in the peer database: %s", ip, err)
+			}
+		}
+		if giveUp {
+			return
+		}
+
+		// Add some jitter so that peers don't all reconnect at the same time
+		time.Sleep(delay + time.Duration(rand.Int63n(int64(delay/2)+1)))
+		delay *= 2
+		if delay > n.maxReconnectDelay {
+			delay = n.maxReconnectDelay
+		}
+	}
+}
+
+// attemptConnect attempts to connect to the provided IP.
+// assumes the stateLock is not held.
+func (n *network) attemptConnect(ip utils.IPDesc) error {
+	n.log.Verbo("attempting to connect to %s", ip)
+
+	conn, err := n.dialer.Dial(ip)
+	if err != nil {
+		return err
+	}
+	return n.upgrade(&peer{
+		net:      n,
+		conn:     conn,
+		ip:       ip,
+		outbound: true,
+	}, n.clientUpgrader)
+}
+
+// upgrade the connection and register the peer.
+// assumes the stateLock is not held. Returns an error if the peer's connection
+// wasn't able to be upgraded.
+func (n *network) upgrade(p *peer, upgrader Upgrader) error {
+	if err := p.conn.SetDeadline(n.clock.Time().Add(n.pingPongTimeout)); err != nil {
+		_ = p.conn.Close()
+		n.log.Verbo("failed to set the deadline of the connection with %s", err)
+		return err
+	}
+
+	id, conn, err := upgrader.Upgrade(p.conn)
+	if err != nil {
+		_ = p.conn.Close()
+		n.log.Verbo("failed to upgrade connection with %s", err)
+		return err
+	}
+
+	p.sender = make(chan []byte, n.sendQueueSize)
+	p.id = id
+	p.conn = conn
+
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	if n.closed {
+		_ = p.conn.Close()
+		return nil
+	}
+
+	str := p.ip.String()
+	if id.Equals(n.id) {
+		if p.outbound {
+			// this IP is actually ourself so we should never try to connect to
+			// it again
+			delete(n.disconnectedIPs, str)
+			n.myIPs[str] = struct{}{}
+		}
+		_ = p.conn.Close()
+		return nil
+	}
+
+	key := id.Key()
+	if existing, ok := n.peers[key]; ok {
+		if existing.connected || !n.preferred(p) || n.preferred(existing) {
+			// We already have a connection to this peer, so there is no need
+			// to keep attempting to connect to this IP. A connection that
+			// finished the handshake is never replaced, so that dialing a
+			// connected peer to verify its IP doesn't interrupt it.
+			if p.outbound {
+				delete(n.disconnectedIPs, str)
+				if existing.ip.IsZero() {
+					// The peer connected to us, and dialing the IP it claims
+					// to listen on reached it, so the IP can be trusted
+					existing.ip = p.ip
+					delete(n.failures, str)
+					n.connectedIPs[str] = struct{}{}
+					n.ipIDs[str] = id
+				}
+			}
+			_ = p.conn.Close()
+			return nil
+		}
+
+		// Both nodes dialed each other at the same time. Only keep the
+		// connection that was dialed by the node with the smaller ID so that
+		// both nodes keep the same connection. If the nodes still end up
+		// keeping different connections, both are closed and redialed.
+		n.remove(existing)
+		if !existing.ip.IsZero() {
+			// The peer is still connected, so don't attempt to reconnect to
+			// the replaced connection's IP
+			delete(n.disconnectedIPs, existing.ip.String())
+		}
+		go existing.Close()
+	}
+
+	if !n.admit(p) {
+		n.log.Verbo("rejecting %s at %s as there are too many peers", id, p.ip)
+		_ = p.conn.Close()
+		return errTooManyPeers
+	}
+
+	n.peers[key] = p
+	if p.outbound {
+		delete(n.disconnectedIPs, str)
+		n.connectedIPs[str] = struct{}{}
+	}
+
+	p.Start()
+	return nil
+}
+
+// prioritized returns true if the peer [id], listening on [ip], should be
+// connected to even if there are already maxPeers peers.
+// assumes the stateLock is held.
+func (n *network) prioritized(id ids.ShortID, ip utils.IPDesc) bool {
+	if n.vdrs.Contains(id) {
+		return true
+	}
+	_, pinned := n.pinnedIPs[ip.String()]
+	return pinned
+}
+
+// hasRoom returns true if the network should attempt to connect to [ip].
+// assumes the stateLock is held.
+func (n *network) hasRoom(ip utils.IPDesc) bool {
+	if n.maxPeers <= 0 || len(n.peers) < n.maxPeers {
+		return true
+	}
+	id, ok := n.ipIDs[ip.String()]
+	if !ok {
+		id = ids.ShortEmpty
+	}
+	return n.prioritized(id, ip)
+}
+
+// admit returns true if the connection to [p] should be kept. If there are
+// already maxPeers peers, only prioritized peers are admitted, in which case
+// a peer that isn't prioritized is disconnected to make room, if there is one.
+// assumes the stateLock is held.
+func (n *network) admit(p *peer) bool {
+	if n.maxPeers <= 0 || len(n.peers) < n.maxPeers {
+		return true
+	}
+	if !n.prioritized(p.id, p.ip) {
+		return false
+	}
+	for _, existing := range n.peers {
+		if !n.prioritized(existing.id, existing.ip) {
+			n.log.Debug("disconnecting from %s to make room for %s", existing.id, p.id)
+			n.remove(existing)
+			go existing.Close()
+			break
+		}
+	}
+	return true
+}
+
+// reconnectDelay returns how long to wait after failing to connect to [ip],
+// based on how many times in a row connecting to it has failed before.
+// assumes the stateLock is held.
+func (n *network) reconnectDelay(ip utils.IPDesc) time.Duration {
+	delay := n.initialReconnectDelay
+	failures := n.failures[ip.String()]
+	for i := uint32(0); i < failures && delay < n.maxReconnectDelay; i++ {
+		delay *= 2
+	}
+	if delay > n.maxReconnectDelay {
+		delay = n.maxReconnectDelay
+	}
+	return delay
+}
+
+// failed records a failed attempt to connect to [ip]. Returns true if the
+// network should give up on connecting to [ip]. Pinned IPs, and the IPs of
+// validators, are never given up on.
+// assumes the stateLock is held.
+func (n *network) failed(ip utils.IPDesc) bool {
+	str := ip.String()
+	n.failures[str]++
+	failures := n.failures[str]
+	if n.maxPeerFailures == 0 || failures < n.maxPeerFailures {
+		return false
+	}
+	if _, pinned := n.pinnedIPs[str]; pinned {
+		return false
+	}
+	if id, ok := n.ipIDs[str]; ok && n.vdrs.Contains(id) {
+		return false
+	}
+
+	n.log.Debug("giving up on connecting to %s after %d attempts", ip, failures)
+	n.discardIP(ip)
+	return true
+}
+
+// preferred returns true if the peer's connection was dialed by the node with
+// the smaller ID.
+// assumes the stateLock is held.
+func (n *network) preferred(p *peer) bool {
+	return p.outbound == (bytes.Compare(n.id.Bytes(), p.id.Bytes()) < 0)
+}
+
+// connected is called after the peer finishes the handshake.
+// assumes the stateLock is held.
+func (n *network) connected(p *peer) {
+	p.connected = true
+
+	if !p.ip.IsZero() {
+		str := p.ip.String()
+		delete(n.disconnectedIPs, str)
+		delete(n.failures, str)
+		n.connectedIPs[str] = struct{}{}
+		n.ipIDs[str] = p.id
+	}
+
+	if !n.enableStaking {
+		n.vdrs.Add(validators.NewValidator(p.id, 1))
+	}
+
+	n.log.Debug("connected to %s at %s", p.id, p.ip)
+	n.numPeers.Set(float64(n.numConnected()))
+
+	for i := 0; i < len(n.awaiting); i++ {
+		awaiting := n.awaiting[i]
+		awaiting.Add(p.id)
+		if !awaiting.Ready() {
+			continue
+		}
+
+		newLen := len(n.awaiting) - 1
+		n.awaiting[i] = n.awaiting[newLen]
+		n.awaiting = n.awaiting[:newLen]
+
+		i--
+
+		go awaiting.Finish()
+	}
+
+	for _, connector := range n.connectors {
+		connector.Connected(p.id)
+	}
+
+	if ips := n.validatorIPs(); len(ips) > 0 {
+		p.PeerList(ips)
+	}
+}
+
+// disconnected is called after the peer's connection is closed.
+// assumes the stateLock is not held.
+func (n *network) disconnected(p *peer) {
+	n.stateLock.Lock()
+	// this peer may never have been registered or may have been replaced by
+	// another connection
+	if existing, ok := n.peers[p.id.Key()]; ok && existing == p {
+		n.remove(p)
+	}
+	ip := p.ip
+	persisted := p.connected && p.outbound && !ip.IsZero()
+	n.stateLock.Unlock()
+
+	if persisted {
+		if err := n.peerDB.seen(ip, n.clock.Unix()); err != nil {
+			n.log.Warn("failed to store %s in the peer database: %s", p.id, err)
+		}
+	}
+}
+
+// remove the peer from the set of peers, and attempt to reconnect to it.
+// assumes the stateLock is held.
+func (n *network) remove(p *peer) {
+	n.log.Debug("disconnected from %s at %s", p.id, p.ip)
+
+	delete(n.peers, p.id.Key())
+
+	if !p.ip.IsZero() {
+		delete(n.connectedIPs, p.ip.String())
+		n.track(p.ip)
+	}
+
+	if !p.connected {
+		return
+	}
+
+	if !n.enableStaking {
+		n.vdrs.Remove(p.id)
+	}
+
+	for _, awaiting := range n.awaiting {
+		awaiting.Remove(p.id)
+	}
+
+	for _, connector := range n.connectors {
+		connector.Disconnected(p.id)
+	}
+
+	n.numPeers.Set(float64(n.numConnected()))
+}
+
+// gossip the IPs of the validators to a sample of the connected peers.
+// assumes the stateLock is not held.
+func (n *network) gossip() {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	if n.closed {
+		return
+	}
+
+	ips := n.validatorIPs()
+	if len(ips) == 0 {
+		n.log.Debug("no IPs to gossip")
+		return
+	}
+
+	stakers := []*peer(nil)
+	nonStakers := []*peer(nil)
+	for _, p := range n.peers {
+		if !p.connected {
+			continue
+		}
+		if n.vdrs.Contains(p.id) {
+			stakers = append(stakers, p)
+		} else {
+			nonStakers = append(nonStakers, p)
+		}
+	}
+
+	numStakersToSend := (n.peerListGossipSize + n.peerListStakerGossipFraction - 1) / n.peerListStakerGossipFraction
+	if len(stakers) < numStakersToSend {
+		numStakersToSend = len(stakers)
+	}
+	numNonStakersToSend := n.peerListGossipSize - numStakersToSend
+	if len(nonStakers) < numNonStakersToSend {
+		numNonStakersToSend = len(nonStakers)
+	}
+
+	n.log.Verbo("gossiping %d ips to %d peer(s)", len(ips), numStakersToSend+numNonStakersToSend)
+
+	sampler := random.Uniform{N: len(stakers)}
+	for i := 0; i < numStakersToSend; i++ {
+		stakers[sampler.Sample()].PeerList(ips)
+	}
+	sampler.N = len(nonStakers)
+	sampler.Replace()
+	for i := 0; i < numNonStakersToSend; i++ {
+		nonStakers[sampler.Sample()].PeerList(ips)
+	}
+}
+
+// ping all the connected peers so that idle connections aren't timed out.
+// assumes the stateLock is not held.
+func (n *network) ping() {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	for _, p := range n.peers {
+		if p.connected {
+			p.Ping()
+		}
+	}
+}
+
+// validatorIPs returns the IPs of the connected validators.
+// assumes the stateLock is held.
+func (n *network) validatorIPs() []utils.IPDesc {
+	ips := []utils.IPDesc(nil)
+	for _, p := range n.peers {
+		if p.connected && !p.ip.IsZero() && n.vdrs.Contains(p.id) {
+			ips = append(ips, p.ip)
+		}
+	}
+	return ips
+}
+
+// numConnected returns the number of peers that finished the handshake.
+// assumes the stateLock is held.
+func (n *network) numConnected() int {
+	numConnected := 0
+	for _, p := range n.peers {
+		if p.connected {
+			numConnected++
+		}
+	}
+	return numConnected
+}
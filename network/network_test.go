@@ -0,0 +1,529 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/networking"
+	"github.com/ava-labs/gecko/snow/networking/handler"
+	"github.com/ava-labs/gecko/snow/networking/timeout"
+	"github.com/ava-labs/gecko/snow/validators"
+	"github.com/ava-labs/gecko/utils"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+const testTimeout = 10 * time.Second
+
+type testMsg struct {
+	op          Op
+	validatorID ids.ShortID
+	chainID     ids.ID
+	requestID   uint32
+	container   []byte
+	containers  [][]byte
+}
+
+// testRouter forwards every message it receives onto a channel
+type testRouter struct{ msgs chan testMsg }
+
+func newTestRouter() *testRouter { return &testRouter{msgs: make(chan testMsg, 100)} }
+
+func (r *testRouter) add(op Op, validatorID ids.ShortID, chainID ids.ID, requestID uint32, container []byte) {
+	r.msgs <- testMsg{
+		op:          op,
+		validatorID: validatorID,
+		chainID:     chainID,
+		requestID:   requestID,
+		container:   container,
+	}
+}
+
+func (r *testRouter) GetAcceptedFrontier(validatorID ids.ShortID, chainID ids.ID, requestID uint32) {
+	r.add(GetAcceptedFrontier, validatorID, chainID, requestID, nil)
+}
+func (r *testRouter) AcceptedFrontier(validatorID ids.ShortID, chainID ids.ID, requestID uint32, _ ids.Set) {
+	r.add(AcceptedFrontier, validatorID, chainID, requestID, nil)
+}
+func (r *testRouter) GetAccepted(validatorID ids.ShortID, chainID ids.ID, requestID uint32, _ ids.Set) {
+	r.add(GetAccepted, validatorID, chainID, requestID, nil)
+}
+func (r *testRouter) Accepted(validatorID ids.ShortID, chainID ids.ID, requestID uint32, _ ids.Set) {
+	r.add(Accepted, validatorID, chainID, requestID, nil)
+}
+func (r *testRouter) GetAncestors(validatorID ids.ShortID, chainID ids.ID, requestID uint32, _ ids.ID) {
+	r.add(GetAncestors, validatorID, chainID, requestID, nil)
+}
+func (r *testRouter) MultiPut(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containers [][]byte) {
+	r.msgs <- testMsg{
+		op:          MultiPut,
+		validatorID: validatorID,
+		chainID:     chainID,
+		requestID:   requestID,
+		containers:  containers,
+	}
+}
+func (r *testRouter) Get(validatorID ids.ShortID, chainID ids.ID, requestID uint32, _ ids.ID) {
+	r.add(Get, validatorID, chainID, requestID, nil)
+}
+func (r *testRouter) Put(validatorID ids.ShortID, chainID ids.ID, requestID uint32, _ ids.ID, container []byte) {
+	r.add(Put, validatorID, chainID, requestID, container)
+}
+func (r *testRouter) PushQuery(validatorID ids.ShortID, chainID ids.ID, requestID uint32, _ ids.ID, container []byte) {
+	r.add(PushQuery, validatorID, chainID, requestID, container)
+}
+func (r *testRouter) PullQuery(validatorID ids.ShortID, chainID ids.ID, requestID uint32, _ ids.ID) {
+	r.add(PullQuery, validatorID, chainID, requestID, nil)
+}
+func (r *testRouter) Chits(validatorID ids.ShortID, chainID ids.ID, requestID uint32, _ ids.Set) {
+	r.add(Chits, validatorID, chainID, requestID, nil)
+}
+func (r *testRouter) GetAcceptedFrontierFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32) {
+	r.add(GetAcceptedFrontier, validatorID, chainID, requestID, nil)
+}
+func (r *testRouter) GetAcceptedFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32) {
+	r.add(GetAccepted, validatorID, chainID, requestID, nil)
+}
+func (r *testRouter) GetAncestorsFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32) {
+	r.add(GetAncestors, validatorID, chainID, requestID, nil)
+}
+func (r *testRouter) GetFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32, _ ids.ID) {
+	r.add(Get, validatorID, chainID, requestID, nil)
+}
+func (r *testRouter) QueryFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32) {
+	r.add(PullQuery, validatorID, chainID, requestID, nil)
+}
+func (r *testRouter) AddChain(*handler.Handler)                   {}
+func (r *testRouter) RemoveChain(ids.ID)                          {}
+func (r *testRouter) Shutdown()                                   {}
+func (r *testRouter) Initialize(logging.Logger, *timeout.Manager) {}
+
+func (r *testRouter) expect(t *testing.T, op Op, validatorID ids.ShortID, requestID uint32) testMsg {
+	select {
+	case msg := <-r.msgs:
+		if msg.op != op {
+			t.Fatalf("expected op %s but got %s", op, msg.op)
+		}
+		if !msg.validatorID.Equals(validatorID) {
+			t.Fatalf("expected message from %s but got %s", validatorID, msg.validatorID)
+		}
+		if msg.requestID != requestID {
+			t.Fatalf("expected request ID %d but got %d", requestID, msg.requestID)
+		}
+		return msg
+	case <-time.After(testTimeout):
+		t.Fatalf("timed out waiting for a %s message", op)
+	}
+	return testMsg{}
+}
+
+type testNode struct {
+	id     ids.ShortID
+	ip     utils.IPDesc
+	router *testRouter
+	vdrs   validators.Set
+	net    Network
+}
+
+func newTLSConfig(t *testing.T) (ids.ShortID, *tls.Config) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(0),
+		Subject:               pkix.Name{Organization: []string{"Ava Labs"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		BasicConstraintsValid: true,
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := ids.ToShortID(hashing.PubkeyBytesToAddress(certBytes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id, TLSConfig(tls.Certificate{
+		Certificate: [][]byte{certBytes},
+		PrivateKey:  key,
+	})
+}
+
+func newTestNode(t *testing.T, networkID uint32) *testNode {
+	return newTestNodeWithDB(t, networkID, memdb.New(), 0)
+}
+
+func newTestNodeWithDB(t *testing.T, networkID uint32, db database.Database, maxPeers int) *testNode {
+	listener, ip := newTestListener(t)
+	return newTestNodeWithListener(t, networkID, listener, ip, db, maxPeers)
+}
+
+// newTestListener returns a listener on a random local port, and its IP
+func newTestListener(t *testing.T) (net.Listener, utils.IPDesc) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().(*net.TCPAddr)
+	return listener, utils.IPDesc{
+		IP:   addr.IP,
+		Port: uint16(addr.Port),
+	}
+}
+
+// newTestNodeWithListener returns a node that accepts connections on
+// [listener], and tells its peers that it listens on [ip]
+func newTestNodeWithListener(t *testing.T, networkID uint32, listener net.Listener, ip utils.IPDesc, db database.Database, maxPeers int) *testNode {
+	id, config := newTLSConfig(t)
+
+	node := &testNode{
+		id:     id,
+		ip:     ip,
+		router: newTestRouter(),
+		vdrs:   validators.NewSet(),
+	}
+	node.net = NewDefaultNetwork(
+		prometheus.NewRegistry(),
+		logging.NoLog{},
+		id,
+		ip,
+		networkID,
+		CurrentVersion,
+		listener,
+		NewDialer("tcp", time.Second),
+		NewTLSServerUpgrader(config),
+		NewTLSClientUpgrader(config),
+		node.vdrs,
+		node.router,
+		true,
+		db,
+		maxPeers,
+	)
+	go func() { _ = node.net.Dispatch() }()
+	return node
+}
+
+// awaitConnection blocks until [node] has finished the handshake with the
+// node with ID [peerID].
+func awaitConnection(t *testing.T, node *testNode, peerID ids.ShortID) {
+	done := make(chan struct{})
+	requested := ids.ShortSet{}
+	requested.Add(peerID)
+	node.net.AwaitConnections(&networking.AwaitingConnections{
+		Requested:   requested,
+		NumRequired: 1,
+		Finish:      func() { close(done) },
+	})
+
+	select {
+	case <-done:
+	case <-time.After(testTimeout):
+		t.Fatalf("timed out waiting for a connection to %s", peerID)
+	}
+}
+
+func TestNetworkConnectAndSend(t *testing.T) {
+	node0 := newTestNode(t, 12345)
+	defer node0.net.Close()
+	node1 := newTestNode(t, 12345)
+	defer node1.net.Close()
+
+	node0.net.Track(node1.ip)
+
+	awaitConnection(t, node0, node1.id)
+	awaitConnection(t, node1, node0.id)
+
+	chainID := ids.Empty.Prefix(0)
+	containerID := ids.Empty.Prefix(1)
+	container := []byte{1, 2, 3}
+
+	vdrs := ids.ShortSet{}
+	vdrs.Add(node1.id)
+	node0.net.PushQuery(vdrs, chainID, 1, containerID, container)
+	msg := node1.router.expect(t, PushQuery, node0.id, 1)
+	if !msg.chainID.Equals(chainID) {
+		t.Fatalf("wrong chainID")
+	}
+	if string(msg.container) != string(container) {
+		t.Fatalf("wrong container")
+	}
+
+	votes := ids.Set{}
+	votes.Add(containerID)
+	node1.net.Chits(node0.id, chainID, 1, votes)
+	node0.router.expect(t, Chits, node1.id, 1)
+
+	node1.net.Get(node0.id, chainID, 2, containerID)
+	node0.router.expect(t, Get, node1.id, 2)
+
+	node0.net.Put(node1.id, chainID, 2, containerID, container)
+	node1.router.expect(t, Put, node0.id, 2)
+
+	node1.net.GetAncestors(node0.id, chainID, 3, containerID)
+	node0.router.expect(t, GetAncestors, node1.id, 3)
+
+	node0.net.MultiPut(node1.id, chainID, 3, [][]byte{container, container})
+	msg = node1.router.expect(t, MultiPut, node0.id, 3)
+	if len(msg.containers) != 2 || string(msg.containers[1]) != string(container) {
+		t.Fatalf("wrong containers")
+	}
+
+	if peers := node0.net.Peers(); len(peers) != 1 || !peers[0].Equal(node1.ip) {
+		t.Fatalf("expected to be connected to %s but got %v", node1.ip, peers)
+	}
+	// node0 dialed node1, so node1 only knows node0's IP once it dialed it back
+	if peers := awaitPeers(t, node1, 1); len(peers) != 1 || !peers[0].Equal(node0.ip) {
+		t.Fatalf("expected to be connected to %s but got %v", node0.ip, peers)
+	}
+}
+
+func TestNetworkSimultaneousConnect(t *testing.T) {
+	node0 := newTestNode(t, 12345)
+	defer node0.net.Close()
+	node1 := newTestNode(t, 12345)
+	defer node1.net.Close()
+
+	node0.net.Track(node1.ip)
+	node1.net.Track(node0.ip)
+
+	awaitConnection(t, node0, node1.id)
+	awaitConnection(t, node1, node0.id)
+
+	chainID := ids.Empty.Prefix(0)
+
+	vdrs := ids.ShortSet{}
+	vdrs.Add(node1.id)
+
+	// One of the two connections will be dropped in favor of the other, so
+	// the first connection to finish the handshake may be replaced. If that
+	// happens, the send is reported as failed and should be retried.
+	timeout := time.After(testTimeout)
+	for requestID := uint32(0); ; requestID++ {
+		node0.net.GetAcceptedFrontier(vdrs, chainID, requestID)
+		select {
+		case <-node0.router.msgs:
+			time.Sleep(10 * time.Millisecond)
+			continue
+		case msg := <-node1.router.msgs:
+			if msg.op != GetAcceptedFrontier {
+				t.Fatalf("expected op %s but got %s", GetAcceptedFrontier, msg.op)
+			}
+			if !msg.validatorID.Equals(node0.id) {
+				t.Fatalf("expected message from %s but got %s", node0.id, msg.validatorID)
+			}
+			return
+		case <-timeout:
+			t.Fatalf("timed out waiting for a %s message", GetAcceptedFrontier)
+		}
+	}
+}
+
+func TestNetworkSendFailsToUnknownPeer(t *testing.T) {
+	node := newTestNode(t, 12345)
+	defer node.net.Close()
+
+	unknownID := ids.NewShortID([20]byte{1})
+	chainID := ids.Empty.Prefix(0)
+
+	node.net.Get(unknownID, chainID, 4, ids.Empty)
+	node.router.expect(t, Get, unknownID, 4)
+
+	node.net.GetAncestors(unknownID, chainID, 5, ids.Empty)
+	node.router.expect(t, GetAncestors, unknownID, 5)
+}
+
+func TestNetworkMismatchedNetworkID(t *testing.T) {
+	node0 := newTestNode(t, 12345)
+	defer node0.net.Close()
+	node1 := newTestNode(t, 54321)
+	defer node1.net.Close()
+
+	node0.net.Track(node1.ip)
+
+	// Give the nodes time to attempt the handshake
+	time.Sleep(time.Second)
+
+	if peers := node0.net.Peers(); len(peers) != 0 {
+		t.Fatalf("shouldn't have connected to a peer on a different network")
+	}
+}
+
+func TestNetworkCloseTwice(t *testing.T) {
+	node := newTestNode(t, 12345)
+	if err := node.net.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := node.net.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNetworkReconnectsAfterRestart(t *testing.T) {
+	db := memdb.New()
+
+	node0 := newTestNodeWithDB(t, 12345, db, 0)
+	node1 := newTestNode(t, 12345)
+	defer node1.net.Close()
+
+	node0.net.Track(node1.ip)
+	awaitConnection(t, node0, node1.id)
+	if err := node0.net.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The restarted node isn't told about node1, so it must have been
+	// remembered in the peer database
+	restarted := newTestNodeWithDB(t, 12345, db, 0)
+	defer restarted.net.Close()
+
+	awaitConnection(t, restarted, node1.id)
+}
+
+func TestNetworkVerifiesClaimedIP(t *testing.T) {
+	db := memdb.New()
+
+	node0 := newTestNodeWithDB(t, 12345, db, 0)
+	defer node0.net.Close()
+
+	// node1 claims to listen on an IP nothing listens on
+	closed, claimedIP := newTestListener(t)
+	if err := closed.Close(); err != nil {
+		t.Fatal(err)
+	}
+	listener, _ := newTestListener(t)
+	node1 := newTestNodeWithListener(t, 12345, listener, claimedIP, memdb.New(), 0)
+	defer node1.net.Close()
+
+	node1.net.Track(node0.ip)
+	awaitConnection(t, node0, node1.id)
+
+	// Dialing the claimed IP fails, so it's neither gossiped nor stored
+	time.Sleep(100 * time.Millisecond)
+	if peers := node0.net.Peers(); len(peers) != 0 {
+		t.Fatalf("shouldn't have trusted the IP %s claimed by %s", claimedIP, node1.id)
+	}
+	if peers, err := (&peerDB{db: db}).peers(); err != nil {
+		t.Fatal(err)
+	} else if len(peers) != 0 {
+		t.Fatalf("shouldn't have stored the peer that dialed this node")
+	}
+}
+
+// awaitPeers blocks until [node] has verified the IPs of [numPeers] peers
+func awaitPeers(t *testing.T, node *testNode, numPeers int) []utils.IPDesc {
+	deadline := time.Now().Add(testTimeout)
+	for {
+		peers := node.net.Peers()
+		if len(peers) >= numPeers {
+			return peers
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %d peer(s)", numPeers)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestNetworkMaxPeersPrefersValidators(t *testing.T) {
+	node0 := newTestNodeWithDB(t, 12345, memdb.New(), 1)
+	defer node0.net.Close()
+	node1 := newTestNode(t, 12345)
+	defer node1.net.Close()
+	node2 := newTestNode(t, 12345)
+	defer node2.net.Close()
+
+	node1.net.Track(node0.ip)
+	awaitConnection(t, node0, node1.id)
+
+	// node0 is full, but node2 is a validator, so node1 should be
+	// disconnected to make room for it
+	node0.vdrs.Add(validators.NewValidator(node2.id, 1))
+	node2.net.Track(node0.ip)
+	awaitConnection(t, node0, node2.id)
+
+	// node2 dialed node0, so its IP is only known once node0 dialed it back
+	if peers := awaitPeers(t, node0, 1); len(peers) != 1 || !peers[0].Equal(node2.ip) {
+		t.Fatalf("expected to only be connected to %s but got %v", node2.ip, peers)
+	}
+}
+
+// testConnector forwards the peers it's notified of onto channels
+type testConnector struct{ connected, disconnected chan ids.ShortID }
+
+func (c *testConnector) Connected(validatorID ids.ShortID)    { c.connected <- validatorID }
+func (c *testConnector) Disconnected(validatorID ids.ShortID) { c.disconnected <- validatorID }
+
+func expectPeer(t *testing.T, peers chan ids.ShortID, peerID ids.ShortID) {
+	select {
+	case id := <-peers:
+		if !id.Equals(peerID) {
+			t.Fatalf("expected %s but got %s", peerID, id)
+		}
+	case <-time.After(testTimeout):
+		t.Fatalf("timed out waiting for %s", peerID)
+	}
+}
+
+func TestNetworkRegisterConnector(t *testing.T) {
+	node0 := newTestNode(t, 12345)
+	defer node0.net.Close()
+	node1 := newTestNode(t, 12345)
+	node2 := newTestNode(t, 12345)
+	defer node2.net.Close()
+
+	node0.net.Track(node1.ip)
+	awaitConnection(t, node0, node1.id)
+
+	// The connector is told about the peers that are already connected
+	connector := &testConnector{
+		connected:    make(chan ids.ShortID, 10),
+		disconnected: make(chan ids.ShortID, 10),
+	}
+	node0.net.RegisterConnector(connector)
+	expectPeer(t, connector.connected, node1.id)
+
+	node0.net.Track(node2.ip)
+	expectPeer(t, connector.connected, node2.id)
+
+	if err := node1.net.Close(); err != nil {
+		t.Fatal(err)
+	}
+	expectPeer(t, connector.disconnected, node1.id)
+}
+
+func TestNetworkCapsDiscoveredIPs(t *testing.T) {
+	node := newTestNode(t, 12345)
+	defer node.net.Close()
+
+	n := node.net.(*network)
+	n.stateLock.Lock()
+	for i := 0; i < maxDiscoveredIPs+10; i++ {
+		n.discovered(utils.IPDesc{
+			IP:   net.IPv4(127, 0, byte(i>>8), byte(i)),
+			Port: 1,
+		}, nil)
+	}
+	numTracked := len(n.disconnectedIPs) + len(n.connectedIPs)
+	n.stateLock.Unlock()
+
+	if numTracked != maxDiscoveredIPs {
+		t.Fatalf("wrong number of tracked IPs. Expected: %d ; Returned: %d", maxDiscoveredIPs, numTracked)
+	}
+}
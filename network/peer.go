@@ -0,0 +1,578 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"net"
+	"sync"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils"
+	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+type peer struct {
+	net *network // network this peer is part of
+
+	// if the version message has been received and is valid. is only modified
+	// on the connection's reader routine with the network state lock held.
+	connected bool
+
+	// only close the peer once
+	once sync.Once
+
+	// if the close function has been called, is only modified when the lock is
+	// held.
+	closed bool
+
+	// queue of messages this connection is attempting to send the peer. Is
+	// closed when the connection is closed.
+	sender chan []byte
+
+	// protects the sender channel from being written to after it is closed
+	senderLock sync.Mutex
+
+	// ip may or may not be set when the peer is first started. If the peer
+	// connected to us, it's only set once dialing the IP the peer claims to
+	// listen on reaches the peer. is only modified with the network state lock
+	// held.
+	ip utils.IPDesc
+
+	// true if this peer was connected to by dialing its ip
+	outbound bool
+
+	// id should be set when the peer is first created.
+	id ids.ShortID
+
+	// the connection object that is used to read/write messages from
+	conn net.Conn
+}
+
+// assume the stateLock is held
+func (p *peer) Start() {
+	go p.net.log.RecoverAndPanic(p.ReadMessages)
+	go p.net.log.RecoverAndPanic(p.WriteMessages)
+
+	// Initially send the version to the peer
+	go p.Version()
+}
+
+// ReadMessages reads length prefixed messages from the connection until either
+// the connection is closed or an invalid message is received.
+func (p *peer) ReadMessages() {
+	defer p.Close()
+
+	lengthBytes := make([]byte, wrappers.IntLen)
+	for {
+		if err := p.conn.SetReadDeadline(p.net.clock.Time().Add(p.net.pingPongTimeout)); err != nil {
+			p.net.log.Verbo("error setting the read deadline for %s: %s", p.id, err)
+			return
+		}
+
+		if _, err := io.ReadFull(p.conn, lengthBytes); err != nil {
+			p.net.log.Verbo("error reading from %s: %s", p.id, err)
+			return
+		}
+
+		length := binary.BigEndian.Uint32(lengthBytes)
+		if length > p.net.maxMessageSize {
+			p.net.log.Debug("%s attempted to send a message of size %d, which exceeds the maximum of %d",
+				p.id,
+				length,
+				p.net.maxMessageSize)
+			return
+		}
+
+		msgBytes := make([]byte, length)
+		if _, err := io.ReadFull(p.conn, msgBytes); err != nil {
+			p.net.log.Verbo("error reading from %s: %s", p.id, err)
+			return
+		}
+
+		p.net.log.Verbo("parsing new message from %s:\n%s",
+			p.id,
+			formatting.DumpBytes{Bytes: msgBytes})
+
+		msg, err := p.net.b.Parse(msgBytes)
+		if err != nil {
+			p.net.log.Debug("failed to parse new message from %s:\n%s\n%s",
+				p.id,
+				formatting.DumpBytes{Bytes: msgBytes},
+				err)
+			return
+		}
+
+		p.handle(msg)
+	}
+}
+
+// WriteMessages writes the queued messages to the connection until either the
+// connection is closed or a write fails.
+func (p *peer) WriteMessages() {
+	defer p.Close()
+
+	for msg := range p.sender {
+		p.net.log.Verbo("sending new message to %s:\n%s",
+			p.id,
+			formatting.DumpBytes{Bytes: msg})
+
+		if len(msg) > math.MaxUint32 {
+			p.net.log.Debug("dropping a message to %s of length %d", p.id, len(msg))
+			continue
+		}
+
+		packer := wrappers.Packer{Bytes: make([]byte, wrappers.IntLen+len(msg))}
+		packer.PackInt(uint32(len(msg)))
+		packer.PackFixedBytes(msg)
+
+		if err := p.conn.SetWriteDeadline(p.net.clock.Time().Add(p.net.pingPongTimeout)); err != nil {
+			p.net.log.Verbo("error setting the write deadline for %s: %s", p.id, err)
+			return
+		}
+
+		if _, err := p.conn.Write(packer.Bytes); err != nil {
+			p.net.log.Verbo("error writing to %s: %s", p.id, err)
+			return
+		}
+	}
+}
+
+// send assumes that the stateLock is not held.
+func (p *peer) send(msg Msg) bool {
+	p.senderLock.Lock()
+	defer p.senderLock.Unlock()
+
+	if p.closed {
+		p.net.log.Debug("dropping message to %s due to a closed connection", p.id)
+		return false
+	}
+	select {
+	case p.sender <- msg.Bytes():
+		return true
+	default:
+		p.net.log.Debug("dropping message to %s due to a full send queue", p.id)
+		return false
+	}
+}
+
+// assumes the stateLock is not held
+func (p *peer) handle(msg Msg) {
+	op := msg.Op()
+	msgMetrics := p.net.message(op)
+	if msgMetrics == nil {
+		p.net.log.Debug("dropping an unknown message from %s with op %d", p.id, op)
+		return
+	}
+	msgMetrics.numReceived.Inc()
+
+	switch op {
+	case Version:
+		p.version(msg)
+		return
+	case GetVersion:
+		p.getVersion(msg)
+		return
+	case Ping:
+		p.ping(msg)
+		return
+	case Pong:
+		p.pong(msg)
+		return
+	}
+	if !p.isConnected() {
+		p.net.log.Debug("dropping message from %s because the connection hasn't been established yet", p.id)
+
+		// attempt to finish the handshake
+		p.GetVersion()
+		return
+	}
+
+	switch op {
+	case GetPeerList:
+		p.getPeerList(msg)
+	case PeerList:
+		p.peerList(msg)
+	case GetAcceptedFrontier:
+		p.getAcceptedFrontier(msg)
+	case AcceptedFrontier:
+		p.acceptedFrontier(msg)
+	case GetAccepted:
+		p.getAccepted(msg)
+	case Accepted:
+		p.accepted(msg)
+	case GetAncestors:
+		p.getAncestors(msg)
+	case MultiPut:
+		p.multiPut(msg)
+	case Get:
+		p.get(msg)
+	case Put:
+		p.put(msg)
+	case PushQuery:
+		p.pushQuery(msg)
+	case PullQuery:
+		p.pullQuery(msg)
+	case Chits:
+		p.chits(msg)
+	}
+}
+
+// assumes the stateLock is not held
+func (p *peer) isConnected() bool {
+	p.net.stateLock.Lock()
+	defer p.net.stateLock.Unlock()
+
+	return p.connected
+}
+
+// assumes the stateLock is not held
+func (p *peer) Close() { p.once.Do(p.close) }
+
+// assumes only called once
+func (p *peer) close() {
+	p.senderLock.Lock()
+	p.closed = true
+	close(p.sender)
+	p.senderLock.Unlock()
+
+	if err := p.conn.Close(); err != nil {
+		p.net.log.Debug("closing peer %s resulted in an error: %s", p.id, err)
+	}
+
+	p.net.disconnected(p)
+}
+
+// assumes the stateLock is not held
+func (p *peer) GetVersion() {
+	msg, err := p.net.b.GetVersion()
+	p.net.log.AssertNoError(err)
+	p.sendMessage(msg)
+}
+
+// assumes the stateLock is not held
+func (p *peer) Version() {
+	msg, err := p.net.b.Version(
+		p.net.networkID,
+		p.net.clock.Unix(),
+		p.net.ip,
+		p.net.version,
+	)
+	p.net.log.AssertNoError(err)
+	p.sendMessage(msg)
+}
+
+// assumes the stateLock is not held
+func (p *peer) GetPeerList() {
+	msg, err := p.net.b.GetPeerList()
+	p.net.log.AssertNoError(err)
+	p.sendMessage(msg)
+}
+
+// assumes the stateLock is not held
+func (p *peer) PeerList(peers []utils.IPDesc) {
+	msg, err := p.net.b.PeerList(peers)
+	if err != nil {
+		p.net.log.Warn("failed to send PeerList message due to %s", err)
+		return
+	}
+	p.sendMessage(msg)
+}
+
+// assumes the stateLock is not held
+func (p *peer) Ping() {
+	msg, err := p.net.b.Ping()
+	p.net.log.AssertNoError(err)
+	p.sendMessage(msg)
+}
+
+// assumes the stateLock is not held
+func (p *peer) Pong() {
+	msg, err := p.net.b.Pong()
+	p.net.log.AssertNoError(err)
+	p.sendMessage(msg)
+}
+
+// sendMessage queues the message and records whether it was sent
+func (p *peer) sendMessage(msg Msg) {
+	msgMetrics := p.net.message(msg.Op())
+	if p.send(msg) {
+		msgMetrics.numSent.Inc()
+	} else {
+		msgMetrics.numFailed.Inc()
+	}
+}
+
+// assumes the stateLock is not held
+func (p *peer) getVersion(_ Msg) { p.Version() }
+
+// assumes the stateLock is not held
+func (p *peer) version(msg Msg) {
+	if p.isConnected() {
+		p.net.log.Verbo("dropping duplicated version message from %s", p.id)
+		return
+	}
+
+	if networkID := msg.Get(NetworkID).(uint32); networkID != p.net.networkID {
+		p.net.log.Debug("peer's network ID doesn't match our networkID: Peer's = %d ; Ours = %d",
+			networkID,
+			p.net.networkID)
+
+		// By clearing the IP, we will not attempt to reconnect to this peer
+		p.discardIP()
+		p.Close()
+		return
+	}
+
+	myTime := float64(p.net.clock.Unix())
+	if peerTime := float64(msg.Get(MyTime).(uint64)); math.Abs(peerTime-myTime) > p.net.maxClockDifference.Seconds() {
+		p.net.log.Debug("peer's clock is too far out of sync with mine. Peer's = %d, Ours = %d (seconds)",
+			uint64(peerTime),
+			uint64(myTime))
+
+		p.Close()
+		return
+	}
+
+	if peerVersion := msg.Get(VersionStr).(string); !checkCompatibility(p.net.version, peerVersion) {
+		p.net.log.Debug("peer version, %s, is not compatible. Closing connection", peerVersion)
+
+		p.discardIP()
+		p.Close()
+		return
+	}
+
+	p.net.stateLock.Lock()
+	if p.net.closed {
+		p.net.stateLock.Unlock()
+		return
+	}
+
+	p.net.connected(p)
+	ip := p.ip
+
+	if claimedIP := msg.Get(IP).(utils.IPDesc); !p.outbound {
+		// The peer told us the IP it is listening on. It isn't gossiped until
+		// dialing it reaches the peer.
+		p.net.discovered(claimedIP, &p.id)
+	}
+	p.net.stateLock.Unlock()
+
+	// Only the IPs this node dialed are known to belong to the peer
+	if p.outbound {
+		if err := p.net.peerDB.connected(ip, p.id, p.net.clock.Unix(), p.net.vdrs.Contains); err != nil {
+			p.net.log.Warn("failed to store %s in the peer database: %s", p.id, err)
+		}
+	}
+}
+
+// assumes the stateLock is not held
+func (p *peer) getPeerList(_ Msg) {
+	p.net.stateLock.Lock()
+	peers := p.net.validatorIPs()
+	p.net.stateLock.Unlock()
+
+	if len(peers) > 0 {
+		p.PeerList(peers)
+	}
+}
+
+// assumes the stateLock is not held
+func (p *peer) peerList(msg Msg) {
+	ips := msg.Get(Peers).([]utils.IPDesc)
+	if len(ips) > maxPeerListIPs {
+		p.net.log.Debug("only connecting to %d of the %d IPs sent by %s", maxPeerListIPs, len(ips), p.id)
+		ips = ips[:maxPeerListIPs]
+	}
+
+	p.net.stateLock.Lock()
+	defer p.net.stateLock.Unlock()
+
+	for _, ip := range ips {
+		p.net.discovered(ip, nil)
+	}
+}
+
+// assumes the stateLock is not held
+func (p *peer) ping(_ Msg) { p.Pong() }
+
+// assumes the stateLock is not held
+func (p *peer) pong(_ Msg) {}
+
+// assumes the stateLock is not held
+func (p *peer) getAcceptedFrontier(msg Msg) {
+	chainID, err := ids.ToID(msg.Get(ChainID).([]byte))
+	p.net.log.AssertNoError(err)
+	requestID := msg.Get(RequestID).(uint32)
+
+	p.net.router.GetAcceptedFrontier(p.id, chainID, requestID)
+}
+
+// assumes the stateLock is not held
+func (p *peer) acceptedFrontier(msg Msg) {
+	chainID, err := ids.ToID(msg.Get(ChainID).([]byte))
+	p.net.log.AssertNoError(err)
+	requestID := msg.Get(RequestID).(uint32)
+
+	containerIDs := ids.Set{}
+	for _, containerIDBytes := range msg.Get(ContainerIDs).([][]byte) {
+		containerID, err := ids.ToID(containerIDBytes)
+		if err != nil {
+			p.net.log.Debug("error parsing ContainerID 0x%x: %s", containerIDBytes, err)
+			return
+		}
+		containerIDs.Add(containerID)
+	}
+
+	p.net.router.AcceptedFrontier(p.id, chainID, requestID, containerIDs)
+}
+
+// assumes the stateLock is not held
+func (p *peer) getAccepted(msg Msg) {
+	chainID, err := ids.ToID(msg.Get(ChainID).([]byte))
+	p.net.log.AssertNoError(err)
+	requestID := msg.Get(RequestID).(uint32)
+
+	containerIDs := ids.Set{}
+	for _, containerIDBytes := range msg.Get(ContainerIDs).([][]byte) {
+		containerID, err := ids.ToID(containerIDBytes)
+		if err != nil {
+			p.net.log.Debug("error parsing ContainerID 0x%x: %s", containerIDBytes, err)
+			return
+		}
+		containerIDs.Add(containerID)
+	}
+
+	p.net.router.GetAccepted(p.id, chainID, requestID, containerIDs)
+}
+
+// assumes the stateLock is not held
+func (p *peer) accepted(msg Msg) {
+	chainID, err := ids.ToID(msg.Get(ChainID).([]byte))
+	p.net.log.AssertNoError(err)
+	requestID := msg.Get(RequestID).(uint32)
+
+	containerIDs := ids.Set{}
+	for _, containerIDBytes := range msg.Get(ContainerIDs).([][]byte) {
+		containerID, err := ids.ToID(containerIDBytes)
+		if err != nil {
+			p.net.log.Debug("error parsing ContainerID 0x%x: %s", containerIDBytes, err)
+			return
+		}
+		containerIDs.Add(containerID)
+	}
+
+	p.net.router.Accepted(p.id, chainID, requestID, containerIDs)
+}
+
+// assumes the stateLock is not held
+func (p *peer) getAncestors(msg Msg) {
+	chainID, err := ids.ToID(msg.Get(ChainID).([]byte))
+	p.net.log.AssertNoError(err)
+	requestID := msg.Get(RequestID).(uint32)
+	containerID, err := ids.ToID(msg.Get(ContainerID).([]byte))
+	p.net.log.AssertNoError(err)
+
+	p.net.router.GetAncestors(p.id, chainID, requestID, containerID)
+}
+
+// assumes the stateLock is not held
+func (p *peer) multiPut(msg Msg) {
+	chainID, err := ids.ToID(msg.Get(ChainID).([]byte))
+	p.net.log.AssertNoError(err)
+	requestID := msg.Get(RequestID).(uint32)
+	containers := msg.Get(MultiContainerBytes).([][]byte)
+
+	p.net.router.MultiPut(p.id, chainID, requestID, containers)
+}
+
+// assumes the stateLock is not held
+func (p *peer) get(msg Msg) {
+	chainID, err := ids.ToID(msg.Get(ChainID).([]byte))
+	p.net.log.AssertNoError(err)
+	requestID := msg.Get(RequestID).(uint32)
+	containerID, err := ids.ToID(msg.Get(ContainerID).([]byte))
+	p.net.log.AssertNoError(err)
+
+	p.net.router.Get(p.id, chainID, requestID, containerID)
+}
+
+// assumes the stateLock is not held
+func (p *peer) put(msg Msg) {
+	chainID, err := ids.ToID(msg.Get(ChainID).([]byte))
+	p.net.log.AssertNoError(err)
+	requestID := msg.Get(RequestID).(uint32)
+	containerID, err := ids.ToID(msg.Get(ContainerID).([]byte))
+	p.net.log.AssertNoError(err)
+	container := msg.Get(ContainerBytes).([]byte)
+
+	p.net.router.Put(p.id, chainID, requestID, containerID, container)
+}
+
+// assumes the stateLock is not held
+func (p *peer) pushQuery(msg Msg) {
+	chainID, err := ids.ToID(msg.Get(ChainID).([]byte))
+	p.net.log.AssertNoError(err)
+	requestID := msg.Get(RequestID).(uint32)
+	containerID, err := ids.ToID(msg.Get(ContainerID).([]byte))
+	p.net.log.AssertNoError(err)
+	container := msg.Get(ContainerBytes).([]byte)
+
+	p.net.router.PushQuery(p.id, chainID, requestID, containerID, container)
+}
+
+// assumes the stateLock is not held
+func (p *peer) pullQuery(msg Msg) {
+	chainID, err := ids.ToID(msg.Get(ChainID).([]byte))
+	p.net.log.AssertNoError(err)
+	requestID := msg.Get(RequestID).(uint32)
+	containerID, err := ids.ToID(msg.Get(ContainerID).([]byte))
+	p.net.log.AssertNoError(err)
+
+	p.net.router.PullQuery(p.id, chainID, requestID, containerID)
+}
+
+// assumes the stateLock is not held
+func (p *peer) chits(msg Msg) {
+	chainID, err := ids.ToID(msg.Get(ChainID).([]byte))
+	p.net.log.AssertNoError(err)
+	requestID := msg.Get(RequestID).(uint32)
+
+	containerIDs := ids.Set{}
+	for _, containerIDBytes := range msg.Get(ContainerIDs).([][]byte) {
+		containerID, err := ids.ToID(containerIDBytes)
+		if err != nil {
+			p.net.log.Debug("error parsing ContainerID 0x%x: %s", containerIDBytes, err)
+			return
+		}
+		containerIDs.Add(containerID)
+	}
+
+	p.net.router.Chits(p.id, chainID, requestID, containerIDs)
+}
+
+// discardIP prevents the network from attempting to reconnect to this peer
+// assumes the stateLock is not held
+func (p *peer) discardIP() {
+	p.net.stateLock.Lock()
+	ip := p.ip
+	if !ip.IsZero() {
+		p.net.discardIP(ip)
+		p.ip = utils.IPDesc{}
+	}
+	p.net.stateLock.Unlock()
+
+	if !ip.IsZero() {
+		p.net.forget(ip)
+	}
+}
+
+// checkCompatibility Check to make sure that the peer and I speak the same language.
+func checkCompatibility(myVersion string, peerVersion string) bool {
+	// At the moment, we are all compatible.
+	return true
+}
@@ -0,0 +1,216 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+const (
+	// Length, in bytes, of a packed peerInfo: ip, port, id, lastSeen and
+	// failures
+	peerInfoLen = 16 + wrappers.ShortLen + hashing.AddrLen + wrappers.LongLen + wrappers.IntLen
+)
+
+var (
+	errBadPeerInfo = errors.New("unexpected peer info length")
+)
+
+// peerInfo is what the network remembers about a peer it discovered
+type peerInfo struct {
+	// IP the peer listens on
+	ip utils.IPDesc
+
+	// ID of the peer's certificate
+	id ids.ShortID
+
+	// Unix time the peer was last connected to
+	lastSeen uint64
+
+	// Number of consecutive failed attempts to connect to the peer
+	failures uint32
+}
+
+// peerDB persists the peers the network has connected to by dialing their IP,
+// keyed by that IP, so that a restarted node can rejoin the network without
+// being given bootstrap peers.
+//
+// If positive, at most [maxPeers] peers are stored. Once there are that many,
+// connecting to a new peer evicts the peer that was seen the longest time ago,
+// preferring peers that aren't validators.
+// peerDB is thread safe.
+type peerDB struct {
+	lock     sync.Mutex
+	db       database.Database
+	maxPeers int
+}
+
+// get returns the peer listening on [ip]. Returns false if the peer isn't
+// known.
+func (db *peerDB) get(ip utils.IPDesc) (*peerInfo, bool, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	return db.getInfo(ip)
+}
+
+func (db *peerDB) getInfo(ip utils.IPDesc) (*peerInfo, bool, error) {
+	b, err := db.db.Get([]byte(ip.String()))
+	if err == database.ErrNotFound {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	info, err := unpackPeerInfo(b)
+	return info, err == nil, err
+}
+
+// putInfo stores [info], overwriting the peer with the same IP
+func (db *peerDB) putInfo(info *peerInfo) error {
+	p := wrappers.Packer{Bytes: make([]byte, peerInfoLen)}
+	p.PackIP(info.ip)
+	p.PackFixedBytes(info.id.Bytes())
+	p.PackLong(info.lastSeen)
+	p.PackInt(info.failures)
+	if p.Errored() {
+		return p.Err
+	}
+	return db.db.Put([]byte(info.ip.String()), p.Bytes)
+}
+
+// remove forgets the peer listening on [ip]
+func (db *peerDB) remove(ip utils.IPDesc) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	return db.db.Delete([]byte(ip.String()))
+}
+
+// connected records that the peer [id], listening on [ip], finished the
+// handshake at [time] after this node dialed [ip]. If the database is full and
+// [ip] isn't known yet, another peer is evicted, where peers for which
+// [isValidator] returns true are only evicted if every peer is a validator.
+func (db *peerDB) connected(ip utils.IPDesc, id ids.ShortID, time uint64, isValidator func(ids.ShortID) bool) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if _, exists, err := db.getInfo(ip); err != nil {
+		return err
+	} else if !exists && db.maxPeers > 0 {
+		if err := db.makeRoom(isValidator); err != nil {
+			return err
+		}
+	}
+	return db.putInfo(&peerInfo{
+		ip:       ip,
+		id:       id,
+		lastSeen: time,
+	})
+}
+
+// makeRoom evicts peers until there is room for another peer.
+// assumes the lock is held.
+func (db *peerDB) makeRoom(isValidator func(ids.ShortID) bool) error {
+	peers, err := db.allPeers()
+	if err != nil {
+		return err
+	}
+	for len(peers) >= db.maxPeers {
+		evict := 0
+		for i, info := range peers {
+			iIsValidator := isValidator(info.id)
+			evictIsValidator := isValidator(peers[evict].id)
+			if iIsValidator != evictIsValidator {
+				if !iIsValidator {
+					evict = i
+				}
+				continue
+			}
+			if info.lastSeen < peers[evict].lastSeen {
+				evict = i
+			}
+		}
+		if err := db.db.Delete([]byte(peers[evict].ip.String())); err != nil {
+			return err
+		}
+		peers[evict] = peers[len(peers)-1]
+		peers = peers[:len(peers)-1]
+	}
+	return nil
+}
+
+// seen records that the peer listening on [ip] was connected at [time], if the
+// peer is known
+func (db *peerDB) seen(ip utils.IPDesc, time uint64) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	info, exists, err := db.getInfo(ip)
+	if err != nil || !exists {
+		return err
+	}
+	info.lastSeen = time
+	return db.putInfo(info)
+}
+
+// failed records a failed attempt to connect to the peer listening on [ip], if
+// the peer is known
+func (db *peerDB) failed(ip utils.IPDesc) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	info, exists, err := db.getInfo(ip)
+	if err != nil || !exists {
+		return err
+	}
+	info.failures++
+	return db.putInfo(info)
+}
+
+// peers returns every known peer
+func (db *peerDB) peers() ([]*peerInfo, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	return db.allPeers()
+}
+
+func (db *peerDB) allPeers() ([]*peerInfo, error) {
+	it := db.db.NewIterator()
+	defer it.Release()
+
+	peers := []*peerInfo(nil)
+	for it.Next() {
+		info, err := unpackPeerInfo(it.Value())
+		if err != nil {
+			return nil, err
+		}
+		peers = append(peers, info)
+	}
+	return peers, it.Error()
+}
+
+func unpackPeerInfo(b []byte) (*peerInfo, error) {
+	p := wrappers.Packer{Bytes: b}
+	info := &peerInfo{ip: p.UnpackIP()}
+	id, err := ids.ToShortID(p.UnpackFixedBytes(hashing.AddrLen))
+	if err != nil && !p.Errored() {
+		return nil, err
+	}
+	info.id = id
+	info.lastSeen = p.UnpackLong()
+	info.failures = p.UnpackInt()
+	if p.Offset != len(b) {
+		p.Add(errBadPeerInfo)
+	}
+	return info, p.Err
+}
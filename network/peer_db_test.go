@@ -0,0 +1,184 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"net"
+	"testing"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils"
+)
+
+func TestPeerDBConnected(t *testing.T) {
+	db := peerDB{db: memdb.New()}
+	ip := utils.IPDesc{
+		IP:   net.IPv4(1, 2, 3, 4),
+		Port: 9651,
+	}
+	id := ids.NewShortID([20]byte{1, 2, 3})
+
+	if _, exists, err := db.get(ip); err != nil {
+		t.Fatal(err)
+	} else if exists {
+		t.Fatalf("shouldn't have known about %s", ip)
+	}
+
+	if err := db.connected(ip, id, 12345, noValidators); err != nil {
+		t.Fatal(err)
+	}
+	info, exists, err := db.get(ip)
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case !exists:
+		t.Fatalf("should have known about %s", ip)
+	case !info.ip.Equal(ip):
+		t.Fatalf("wrong ip. Expected: %s ; Returned: %s", ip, info.ip)
+	case !info.id.Equals(id):
+		t.Fatalf("wrong id. Expected: %s ; Returned: %s", id, info.id)
+	case info.lastSeen != 12345:
+		t.Fatalf("wrong last seen time. Expected: %d ; Returned: %d", 12345, info.lastSeen)
+	case info.failures != 0:
+		t.Fatalf("wrong number of failures. Expected: %d ; Returned: %d", 0, info.failures)
+	}
+
+	if err := db.seen(ip, 23456); err != nil {
+		t.Fatal(err)
+	}
+	if info, _, err := db.get(ip); err != nil {
+		t.Fatal(err)
+	} else if info.lastSeen != 23456 {
+		t.Fatalf("wrong last seen time. Expected: %d ; Returned: %d", 23456, info.lastSeen)
+	} else if !info.id.Equals(id) {
+		t.Fatalf("seen shouldn't have changed the id")
+	}
+
+	if err := db.remove(ip); err != nil {
+		t.Fatal(err)
+	}
+	if _, exists, err := db.get(ip); err != nil {
+		t.Fatal(err)
+	} else if exists {
+		t.Fatalf("shouldn't have known about %s after removing it", ip)
+	}
+}
+
+func TestPeerDBFailures(t *testing.T) {
+	db := peerDB{db: memdb.New()}
+	ip := utils.IPDesc{
+		IP:   net.IPv4(1, 2, 3, 4),
+		Port: 9651,
+	}
+	id := ids.NewShortID([20]byte{1})
+
+	// Failing to connect to an unknown peer doesn't store it
+	if err := db.failed(ip); err != nil {
+		t.Fatal(err)
+	}
+	if _, exists, err := db.get(ip); err != nil {
+		t.Fatal(err)
+	} else if exists {
+		t.Fatalf("shouldn't have known about %s", ip)
+	}
+
+	if err := db.connected(ip, id, 1, noValidators); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := db.failed(ip); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if info, _, err := db.get(ip); err != nil {
+		t.Fatal(err)
+	} else if info.failures != 3 {
+		t.Fatalf("wrong number of failures. Expected: %d ; Returned: %d", 3, info.failures)
+	}
+
+	// Connecting should reset the failures
+	if err := db.connected(ip, id, 2, noValidators); err != nil {
+		t.Fatal(err)
+	}
+	if info, _, err := db.get(ip); err != nil {
+		t.Fatal(err)
+	} else if info.failures != 0 {
+		t.Fatalf("wrong number of failures. Expected: %d ; Returned: %d", 0, info.failures)
+	}
+}
+
+func TestPeerDBPeers(t *testing.T) {
+	db := peerDB{db: memdb.New()}
+	ip0 := utils.IPDesc{
+		IP:   net.IPv4(1, 2, 3, 4),
+		Port: 9651,
+	}
+	ip1 := utils.IPDesc{
+		IP:   net.IPv4(5, 6, 7, 8),
+		Port: 9651,
+	}
+
+	if err := db.connected(ip0, ids.NewShortID([20]byte{1}), 1, noValidators); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.connected(ip1, ids.NewShortID([20]byte{2}), 1, noValidators); err != nil {
+		t.Fatal(err)
+	}
+
+	peers, err := db.peers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(peers) != 2 {
+		t.Fatalf("expected 2 peers but got %d", len(peers))
+	}
+	found := map[string]bool{}
+	for _, info := range peers {
+		found[info.ip.String()] = true
+	}
+	if !found[ip0.String()] || !found[ip1.String()] {
+		t.Fatalf("expected peers %s and %s but got %v", ip0, ip1, found)
+	}
+}
+
+func TestPeerDBMaxPeers(t *testing.T) {
+	db := peerDB{db: memdb.New(), maxPeers: 2}
+	vdrID := ids.NewShortID([20]byte{1})
+	isValidator := func(id ids.ShortID) bool { return id.Equals(vdrID) }
+
+	ips := []utils.IPDesc(nil)
+	for i := 0; i < 3; i++ {
+		ips = append(ips, utils.IPDesc{
+			IP:   net.IPv4(1, 2, 3, byte(i)),
+			Port: 9651,
+		})
+	}
+
+	// The validator was seen the longest time ago, but the other peer is
+	// evicted to make room for the new one
+	if err := db.connected(ips[0], vdrID, 1, isValidator); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.connected(ips[1], ids.NewShortID([20]byte{2}), 2, isValidator); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.connected(ips[2], ids.NewShortID([20]byte{3}), 3, isValidator); err != nil {
+		t.Fatal(err)
+	}
+
+	peers, err := db.peers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := map[string]bool{}
+	for _, info := range peers {
+		found[info.ip.String()] = true
+	}
+	if len(peers) != 2 || !found[ips[0].String()] || !found[ips[2].String()] {
+		t.Fatalf("expected peers %s and %s but got %v", ips[0], ips[2], found)
+	}
+}
+
+func noValidators(ids.ShortID) bool { return false }
@@ -0,0 +1,121 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+// maxIPStrLen is the longest string representation of an IP, port pair that
+// will be accepted during an IP upgrade.
+const maxIPStrLen = 64
+
+var (
+	errNoCert    = errors.New("tls handshake finished with no peer certificate")
+	errIPTooLong = errors.New("peer's ip is too long")
+)
+
+// Upgrader upgrades a raw connection to an authenticated one, returning the ID
+// of the node on the other end of the connection.
+type Upgrader interface {
+	// Must be thread safe
+	Upgrade(net.Conn) (ids.ShortID, net.Conn, error)
+}
+
+type ipUpgrader struct{ ip utils.IPDesc }
+
+// NewIPUpgrader returns an upgrader that identifies peers by the hash of the IP
+// they claim to be listening on, matching how node IDs are derived when
+// staking is disabled. This provides no authentication and should only be
+// used when staking is disabled.
+func NewIPUpgrader(ip utils.IPDesc) Upgrader { return ipUpgrader{ip: ip} }
+
+func (u ipUpgrader) Upgrade(conn net.Conn) (ids.ShortID, net.Conn, error) {
+	p := wrappers.Packer{MaxSize: maxIPStrLen + wrappers.ShortLen}
+	p.PackStr(u.ip.String())
+	if p.Errored() {
+		return ids.ShortID{}, nil, p.Err
+	}
+	if _, err := conn.Write(p.Bytes); err != nil {
+		return ids.ShortID{}, nil, err
+	}
+
+	lengthBytes := make([]byte, wrappers.ShortLen)
+	if _, err := io.ReadFull(conn, lengthBytes); err != nil {
+		return ids.ShortID{}, nil, err
+	}
+	length := binary.BigEndian.Uint16(lengthBytes)
+	if length > maxIPStrLen {
+		return ids.ShortID{}, nil, errIPTooLong
+	}
+	ipBytes := make([]byte, length)
+	if _, err := io.ReadFull(conn, ipBytes); err != nil {
+		return ids.ShortID{}, nil, err
+	}
+
+	id := ids.NewShortID(hashing.ComputeHash160Array(ipBytes))
+	return id, conn, nil
+}
+
+type tlsServerUpgrader struct {
+	config *tls.Config
+}
+
+// NewTLSServerUpgrader returns an upgrader that performs the server side of a
+// TLS handshake and identifies the peer by its certificate.
+func NewTLSServerUpgrader(config *tls.Config) Upgrader {
+	return tlsServerUpgrader{config: config}
+}
+
+func (t tlsServerUpgrader) Upgrade(conn net.Conn) (ids.ShortID, net.Conn, error) {
+	return connToIDAndCert(tls.Server(conn, t.config))
+}
+
+type tlsClientUpgrader struct {
+	config *tls.Config
+}
+
+// NewTLSClientUpgrader returns an upgrader that performs the client side of a
+// TLS handshake and identifies the peer by its certificate.
+func NewTLSClientUpgrader(config *tls.Config) Upgrader {
+	return tlsClientUpgrader{config: config}
+}
+
+func (t tlsClientUpgrader) Upgrade(conn net.Conn) (ids.ShortID, net.Conn, error) {
+	return connToIDAndCert(tls.Client(conn, t.config))
+}
+
+func connToIDAndCert(conn *tls.Conn) (ids.ShortID, net.Conn, error) {
+	if err := conn.Handshake(); err != nil {
+		return ids.ShortID{}, nil, err
+	}
+
+	state := conn.ConnectionState()
+	if len(state.PeerCertificates) == 0 {
+		return ids.ShortID{}, nil, errNoCert
+	}
+	peerCert := state.PeerCertificates[0]
+	id, err := ids.ToShortID(hashing.PubkeyBytesToAddress(peerCert.Raw))
+	return id, conn, err
+}
+
+// TLSConfig returns the TLS configuration that the staking connections of a
+// node should use. Certificates are self-signed, so rather than verifying a
+// chain of trust, peers are identified by the hash of their certificate.
+func TLSConfig(cert tls.Certificate) *tls.Config {
+	return &tls.Config{
+		Certificates:       []tls.Certificate{cert},
+		ClientAuth:         tls.RequireAnyClientCert,
+		InsecureSkipVerify: true,
+	}
+}
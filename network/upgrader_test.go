@@ -0,0 +1,92 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"net"
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils"
+	"github.com/ava-labs/gecko/utils/hashing"
+)
+
+func upgradePair(t *testing.T, server, client Upgrader) (ids.ShortID, ids.ShortID) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	type result struct {
+		id  ids.ShortID
+		err error
+	}
+	serverResult := make(chan result, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			serverResult <- result{err: err}
+			return
+		}
+		defer conn.Close()
+
+		id, _, err := server.Upgrade(conn)
+		serverResult <- result{id: id, err: err}
+	}()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	clientID, _, err := client.Upgrade(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := <-serverResult
+	if res.err != nil {
+		t.Fatal(res.err)
+	}
+	return res.id, clientID
+}
+
+func TestIPUpgrader(t *testing.T) {
+	serverIP := utils.IPDesc{
+		IP:   net.IPv4(1, 2, 3, 4),
+		Port: 5,
+	}
+	clientIP := utils.IPDesc{
+		IP:   net.IPv4(6, 7, 8, 9),
+		Port: 10,
+	}
+
+	// the server learns the client's ID and the client learns the server's ID
+	clientID, serverID := upgradePair(t, NewIPUpgrader(serverIP), NewIPUpgrader(clientIP))
+
+	expectedServerID := ids.NewShortID(hashing.ComputeHash160Array([]byte(serverIP.String())))
+	if !serverID.Equals(expectedServerID) {
+		t.Fatalf("expected server ID %s but got %s", expectedServerID, serverID)
+	}
+	expectedClientID := ids.NewShortID(hashing.ComputeHash160Array([]byte(clientIP.String())))
+	if !clientID.Equals(expectedClientID) {
+		t.Fatalf("expected client ID %s but got %s", expectedClientID, clientID)
+	}
+}
+
+func TestTLSUpgrader(t *testing.T) {
+	serverID, serverConfig := newTLSConfig(t)
+	clientID, clientConfig := newTLSConfig(t)
+
+	upgradedClientID, upgradedServerID := upgradePair(t, NewTLSServerUpgrader(serverConfig), NewTLSClientUpgrader(clientConfig))
+
+	if !upgradedServerID.Equals(serverID) {
+		t.Fatalf("expected server ID %s but got %s", serverID, upgradedServerID)
+	}
+	if !upgradedClientID.Equals(clientID) {
+		t.Fatalf("expected client ID %s but got %s", clientID, upgradedClientID)
+	}
+}
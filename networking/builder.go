@@ -1,6 +1,9 @@
 // (c) 2019-2020, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
+//go:build cgo
+// +build cgo
+
 package networking
 
 import (
@@ -79,6 +82,24 @@ func (m Builder) Accepted(chainID ids.ID, requestID uint32, containerIDs ids.Set
 	})
 }
 
+// GetAncestors message
+func (m Builder) GetAncestors(chainID ids.ID, requestID uint32, containerID ids.ID) (Msg, error) {
+	return m.Pack(GetAncestors, map[Field]interface{}{
+		ChainID:     chainID.Bytes(),
+		RequestID:   requestID,
+		ContainerID: containerID.Bytes(),
+	})
+}
+
+// MultiPut message
+func (m Builder) MultiPut(chainID ids.ID, requestID uint32, containers [][]byte) (Msg, error) {
+	return m.Pack(MultiPut, map[Field]interface{}{
+		ChainID:             chainID.Bytes(),
+		RequestID:           requestID,
+		MultiContainerBytes: containers,
+	})
+}
+
 // Get message
 func (m Builder) Get(chainID ids.ID, requestID uint32, containerID ids.ID) (Msg, error) {
 	return m.Pack(Get, map[Field]interface{}{
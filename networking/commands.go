@@ -1,6 +1,9 @@
 // (c) 2019-2020, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
+//go:build cgo
+// +build cgo
+
 package networking
 
 import (
@@ -14,19 +17,20 @@ type Field uint32
 
 // Fields that may be packed. These values are not sent over the wire.
 const (
-	VersionStr     Field = iota // Used in handshake
-	NetworkID                   // Used in handshake
-	MyTime                      // Used in handshake
-	Peers                       // Used in handshake
-	ChainID                     // Used for dispatching
-	RequestID                   // Used for all messages
-	ContainerID                 // Used for querying
-	ContainerBytes              // Used for gossiping
-	ContainerIDs                // Used for querying
-	Bytes                       // Used as arbitrary data
-	TxID                        // Used for throughput tests
-	Tx                          // Used for throughput tests
-	Status                      // Used for throughput tests
+	VersionStr          Field = iota // Used in handshake
+	NetworkID                        // Used in handshake
+	MyTime                           // Used in handshake
+	Peers                            // Used in handshake
+	ChainID                          // Used for dispatching
+	RequestID                        // Used for all messages
+	ContainerID                      // Used for querying
+	ContainerBytes                   // Used for gossiping
+	ContainerIDs                     // Used for querying
+	Bytes                            // Used as arbitrary data
+	TxID                             // Used for throughput tests
+	Tx                               // Used for throughput tests
+	Status                           // Used for throughput tests
+	MultiContainerBytes              // Used in MultiPut
 )
 
 // Packer returns the packer function that can be used to pack this field.
@@ -58,6 +62,8 @@ func (f Field) Packer() func(*wrappers.Packer, interface{}) {
 		return wrappers.TryPackBytes
 	case Status:
 		return wrappers.TryPackInt
+	case MultiContainerBytes:
+		return wrappers.TryPack2DBytes
 	default:
 		return nil
 	}
@@ -92,6 +98,8 @@ func (f Field) Unpacker() func(*wrappers.Packer) interface{} {
 		return wrappers.TryUnpackBytes
 	case Status:
 		return wrappers.TryUnpackInt
+	case MultiContainerBytes:
+		return wrappers.TryUnpack2DBytes
 	default:
 		return nil
 	}
@@ -123,6 +131,8 @@ func (f Field) String() string {
 		return "Tx"
 	case Status:
 		return "Status"
+	case MultiContainerBytes:
+		return "MultiContainerBytes"
 	default:
 		return "Unknown Field"
 	}
@@ -154,6 +164,9 @@ const (
 	// Throughput test:
 	IssueTx
 	DecidedTx
+	// Bootstrapping:
+	GetAncestors
+	MultiPut
 )
 
 // Defines the messages that can be sent/received with this network
@@ -183,5 +196,8 @@ var (
 		// Throughput test:
 		IssueTx:   []Field{ChainID, Tx},
 		DecidedTx: []Field{TxID, Status},
+		// Bootstrapping:
+		GetAncestors: []Field{ChainID, RequestID, ContainerID},
+		MultiPut:     []Field{ChainID, RequestID, MultiContainerBytes},
 	}
 )
@@ -1,6 +1,9 @@
 // (c) 2019-2020, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
+//go:build cgo
+// +build cgo
+
 package networking
 
 // #include "salticidae/network.h"
@@ -104,6 +107,7 @@ type Handshake struct {
 
 	awaitingLock sync.Mutex
 	awaiting     []*networking.AwaitingConnections
+	connectors   []networking.Connector
 }
 
 // Initialize to the c networking library. This should only be done once during
@@ -163,6 +167,17 @@ func (nm *Handshake) AwaitConnections(awaiting *networking.AwaitingConnections)
 	}
 }
 
+// RegisterConnector ...
+func (nm *Handshake) RegisterConnector(connector networking.Connector) {
+	nm.awaitingLock.Lock()
+	defer nm.awaitingLock.Unlock()
+
+	for _, cert := range nm.connections.IDs().List() {
+		connector.Connected(cert)
+	}
+	nm.connectors = append(nm.connectors, connector)
+}
+
 func (nm *Handshake) gossipPeerList() {
 	stakers := []ids.ShortID{}
 	nonStakers := []ids.ShortID{}
@@ -328,6 +343,10 @@ func peerHandler(_conn *C.struct_peernetwork_conn_t, connected C.bool, _ unsafe.
 			awaiting.Remove(cert)
 		}
 
+		for _, connector := range HandshakeNet.connectors {
+			connector.Disconnected(cert)
+		}
+
 		return
 	}
 
@@ -487,6 +506,10 @@ func version(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.P
 
 		go awaiting.Finish()
 	}
+
+	for _, connector := range HandshakeNet.connectors {
+		connector.Connected(cert)
+	}
 }
 
 // getPeerList handles the recept of a getPeerList message
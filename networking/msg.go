@@ -1,6 +1,9 @@
 // (c) 2019-2020, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
+//go:build cgo
+// +build cgo
+
 package networking
 
 import (
@@ -1,6 +1,9 @@
 // (c) 2019-2020, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
+//go:build cgo
+// +build cgo
+
 package networking
 
 // #include "salticidae/network.h"
@@ -8,6 +11,8 @@ package networking
 // void acceptedFrontier(msg_t *, msgnetwork_conn_t *, void *);
 // void getAccepted(msg_t *, msgnetwork_conn_t *, void *);
 // void accepted(msg_t *, msgnetwork_conn_t *, void *);
+// void getAncestors(msg_t *, msgnetwork_conn_t *, void *);
+// void multiPut(msg_t *, msgnetwork_conn_t *, void *);
 // void get(msg_t *, msgnetwork_conn_t *, void *);
 // void put(msg_t *, msgnetwork_conn_t *, void *);
 // void pushQuery(msg_t *, msgnetwork_conn_t *, void *);
@@ -74,6 +79,8 @@ func (s *Voting) Initialize(log logging.Logger, vdrs validators.Set, peerNet sal
 	net.RegHandler(AcceptedFrontier, salticidae.MsgNetworkMsgCallback(C.acceptedFrontier), nil)
 	net.RegHandler(GetAccepted, salticidae.MsgNetworkMsgCallback(C.getAccepted), nil)
 	net.RegHandler(Accepted, salticidae.MsgNetworkMsgCallback(C.accepted), nil)
+	net.RegHandler(GetAncestors, salticidae.MsgNetworkMsgCallback(C.getAncestors), nil)
+	net.RegHandler(MultiPut, salticidae.MsgNetworkMsgCallback(C.multiPut), nil)
 	net.RegHandler(Get, salticidae.MsgNetworkMsgCallback(C.get), nil)
 	net.RegHandler(Put, salticidae.MsgNetworkMsgCallback(C.put), nil)
 	net.RegHandler(PushQuery, salticidae.MsgNetworkMsgCallback(C.pushQuery), nil)
@@ -253,6 +260,66 @@ func (s *Voting) Accepted(validatorID ids.ShortID, chainID ids.ID, requestID uin
 	s.numAcceptedSent.Inc()
 }
 
+// GetAncestors implements the Sender interface.
+func (s *Voting) GetAncestors(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID) {
+	addr, exists := s.conns.GetIP(validatorID)
+	if !exists {
+		s.log.Debug("Attempted to send a GetAncestors message to a disconnected validator: %s", validatorID)
+		s.executor.Add(func() { s.router.GetAncestorsFailed(validatorID, chainID, requestID) })
+		return // Validator is not connected
+	}
+
+	build := Builder{}
+	msg, err := build.GetAncestors(chainID, requestID, containerID)
+	s.log.AssertNoError(err)
+
+	s.log.Verbo("Sending a GetAncestors message."+
+		"\nValidator: %s"+
+		"\nDestination: %s"+
+		"\nChain: %s"+
+		"\nRequest ID: %d"+
+		"\nContainer ID: %s",
+		validatorID,
+		toIPDesc(addr),
+		chainID,
+		requestID,
+		containerID,
+	)
+	s.send(msg, addr)
+	s.numGetAncestorsSent.Inc()
+}
+
+// MultiPut implements the Sender interface.
+func (s *Voting) MultiPut(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containers [][]byte) {
+	addr, exists := s.conns.GetIP(validatorID)
+	if !exists {
+		s.log.Debug("Attempted to send a MultiPut message to a disconnected validator: %s", validatorID)
+		return // Validator is not connected
+	}
+
+	build := Builder{}
+	msg, err := build.MultiPut(chainID, requestID, containers)
+	if err != nil {
+		s.log.Error("Attempted to pack too large of a MultiPut message.\nNumber of containers: %d", len(containers))
+		return // Packing message failed
+	}
+
+	s.log.Verbo("Sending a MultiPut message."+
+		"\nValidator: %s"+
+		"\nDestination: %s"+
+		"\nChain: %s"+
+		"\nRequest ID: %d"+
+		"\nNumber of containers: %d",
+		validatorID,
+		toIPDesc(addr),
+		chainID,
+		requestID,
+		len(containers),
+	)
+	s.send(msg, addr)
+	s.numMultiPutSent.Inc()
+}
+
 // Get implements the Sender interface.
 func (s *Voting) Get(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID) {
 	addr, exists := s.conns.GetIP(validatorID)
@@ -526,6 +593,38 @@ func accepted(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.
 	VotingNet.router.Accepted(validatorID, chainID, requestID, containerIDs)
 }
 
+// getAncestors handles the receipt of a get ancestors message for a chain
+//export getAncestors
+func getAncestors(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.Pointer) {
+	VotingNet.numGetAncestorsReceived.Inc()
+
+	validatorID, chainID, requestID, msg, err := VotingNet.sanitize(_msg, _conn, GetAncestors)
+	if err != nil {
+		VotingNet.log.Error("Failed to sanitize message due to: %s", err)
+		return
+	}
+
+	containerID, _ := ids.ToID(msg.Get(ContainerID).([]byte))
+
+	VotingNet.router.GetAncestors(validatorID, chainID, requestID, containerID)
+}
+
+// multiPut handles the receipt of a multi put message
+//export multiPut
+func multiPut(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.Pointer) {
+	VotingNet.numMultiPutReceived.Inc()
+
+	validatorID, chainID, requestID, msg, err := VotingNet.sanitize(_msg, _conn, MultiPut)
+	if err != nil {
+		VotingNet.log.Error("Failed to sanitize message due to: %s", err)
+		return
+	}
+
+	containers := msg.Get(MultiContainerBytes).([][]byte)
+
+	VotingNet.router.MultiPut(validatorID, chainID, requestID, containers)
+}
+
 // get handles the recept of a get container message for a chain
 //export get
 func get(_msg *C.struct_msg_t, _conn *C.struct_msgnetwork_conn_t, _ unsafe.Pointer) {
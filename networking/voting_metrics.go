@@ -1,6 +1,9 @@
 // (c) 2019-2020, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
+//go:build cgo
+// +build cgo
+
 package networking
 
 import (
@@ -14,6 +17,8 @@ type votingMetrics struct {
 	numAcceptedFrontierSent, numAcceptedFrontierReceived,
 	numGetAcceptedSent, numGetAcceptedReceived,
 	numAcceptedSent, numAcceptedReceived,
+	numGetAncestorsSent, numGetAncestorsReceived,
+	numMultiPutSent, numMultiPutReceived,
 	numGetSent, numGetReceived,
 	numPutSent, numPutReceived,
 	numPushQuerySent, numPushQueryReceived,
@@ -70,6 +75,30 @@ func (vm *votingMetrics) Initialize(log logging.Logger, registerer prometheus.Re
 			Name:      "accepted_received",
 			Help:      "Number of accepted messages received",
 		})
+	vm.numGetAncestorsSent = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      "get_ancestors_sent",
+			Help:      "Number of get ancestors messages sent",
+		})
+	vm.numGetAncestorsReceived = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      "get_ancestors_received",
+			Help:      "Number of get ancestors messages received",
+		})
+	vm.numMultiPutSent = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      "multi_put_sent",
+			Help:      "Number of multi put messages sent",
+		})
+	vm.numMultiPutReceived = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      "multi_put_received",
+			Help:      "Number of multi put messages received",
+		})
 	vm.numGetSent = prometheus.NewCounter(
 		prometheus.CounterOpts{
 			Namespace: "gecko",
@@ -155,6 +184,18 @@ func (vm *votingMetrics) Initialize(log logging.Logger, registerer prometheus.Re
 	if err := registerer.Register(vm.numAcceptedReceived); err != nil {
 		log.Error("Failed to register accepted_received statistics due to %s", err)
 	}
+	if err := registerer.Register(vm.numGetAncestorsSent); err != nil {
+		log.Error("Failed to register get_ancestors_sent statistics due to %s", err)
+	}
+	if err := registerer.Register(vm.numGetAncestorsReceived); err != nil {
+		log.Error("Failed to register get_ancestors_received statistics due to %s", err)
+	}
+	if err := registerer.Register(vm.numMultiPutSent); err != nil {
+		log.Error("Failed to register multi_put_sent statistics due to %s", err)
+	}
+	if err := registerer.Register(vm.numMultiPutReceived); err != nil {
+		log.Error("Failed to register multi_put_received statistics due to %s", err)
+	}
 	if err := registerer.Register(vm.numGetSent); err != nil {
 		log.Error("Failed to register get_sent statistics due to %s", err)
 	}
@@ -13,17 +13,41 @@ import (
 	"github.com/ava-labs/gecko/utils/logging"
 )
 
+const (
+	// GoTransport is the pure go implementation of the p2p network
+	GoTransport = "go"
+
+	// SalticidaeTransport is the salticidae based implementation of the p2p
+	// network. Requires a cgo enabled build.
+	SalticidaeTransport = "salticidae"
+)
+
 // Config contains all of the configurations of an Ava node.
 type Config struct {
 	// protocol to use for opening the network interface
-	Nat nat.Interface
+	Nat nat.Interface `json:"-"`
 
 	// ID of the network this node should connect to
 	NetworkID uint32
 
+	// Genesis data of the Platform Chain, which defines the genesis state of
+	// the network
+	GenesisBytes []byte
+
+	// Aliases of the genesis chains, keyed by the alias of the VM they run.
+	// Chains that aren't listed keep their default aliases.
+	ChainAliases map[string][]string
+
 	// Transaction fee configuration
 	AvaTxFee uint64
 
+	// Fraction of its staking period a staker must be connected to this node
+	// for this node to prefer rewarding it
+	UptimeRequirement float64
+
+	// Transaction index configuration
+	AVMTxIndexEnabled bool
+
 	// Assertions configuration
 	EnableAssertions bool
 
@@ -31,7 +55,14 @@ type Config struct {
 	EnableCrypto bool
 
 	// Database to use for the node
-	DB database.Database
+	DB database.Database `json:"-"`
+
+	// Directory of the VM plugins to launch
+	PluginDir string
+
+	// Unix socket of the signer that holds the keys of users. If empty, keys
+	// are held in the keystore.
+	RemoteSignerPath string
 
 	// Staking configuration
 	StakingIP       utils.IPDesc
@@ -39,9 +70,21 @@ type Config struct {
 	StakingKeyFile  string
 	StakingCertFile string
 
+	// Networking configuration
+	NetworkTransport string
+	MaxPeers         int
+
 	// Bootstrapping configuration
 	BootstrapPeers []*Peer
 
+	// Fraction of the validators' total stake that must have accepted a
+	// container for this node to bootstrap it
+	BootstrapStakeFraction float64
+
+	// Number of times the accepted frontier is sampled again when too little
+	// of the validators' stake responded to agree on it
+	BootstrapRetries int
+
 	// HTTP configuration
 	HTTPPort      uint16
 	EnableHTTPS   bool
@@ -52,6 +95,7 @@ type Config struct {
 	AdminAPIEnabled    bool
 	KeystoreAPIEnabled bool
 	MetricsAPIEnabled  bool
+	EventsAPIEnabled   bool
 
 	// Logging configuration
 	LoggingConfig logging.Config
@@ -59,6 +103,11 @@ type Config struct {
 	// Consensus configuration
 	ConsensusParams avalanche.Parameters
 
+	// The number of messages from peers each chain buffers, in total and
+	// from each peer
+	ConsensusBufferSize      int
+	ConsensusMaxPeerMessages int
+
 	// Throughput configuration
 	ThroughputPort          uint16
 	ThroughputServerEnabled bool
@@ -67,5 +116,5 @@ type Config struct {
 	IPCEnabled bool
 
 	// Router that is used to handle incoming consensus messages
-	ConsensusRouter router.Router
+	ConsensusRouter router.Router `json:"-"`
 }
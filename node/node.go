@@ -3,34 +3,38 @@
 
 package node
 
-// #include "salticidae/network.h"
-// void onTerm(int sig, void *);
-// void errorHandler(SalticidaeCError *, bool, void *);
-import "C"
-
 import (
+	"crypto/tls"
 	"crypto/x509"
 	"encoding/pem"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
 	"sync"
-	"unsafe"
-
-	"github.com/ava-labs/salticidae-go"
+	"syscall"
+	"time"
 
 	"github.com/ava-labs/gecko/api"
 	"github.com/ava-labs/gecko/api/admin"
+	"github.com/ava-labs/gecko/api/events"
 	"github.com/ava-labs/gecko/api/ipcs"
 	"github.com/ava-labs/gecko/api/keystore"
 	"github.com/ava-labs/gecko/api/metrics"
+	"github.com/ava-labs/gecko/api/signer"
 	"github.com/ava-labs/gecko/chains"
+	"github.com/ava-labs/gecko/chains/atomic"
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/database/prefixdb"
 	"github.com/ava-labs/gecko/genesis"
 	"github.com/ava-labs/gecko/ids"
-	"github.com/ava-labs/gecko/networking"
-	"github.com/ava-labs/gecko/networking/xputtest"
+	"github.com/ava-labs/gecko/network"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/snow/networking"
 	"github.com/ava-labs/gecko/snow/triggers"
 	"github.com/ava-labs/gecko/snow/validators"
 	"github.com/ava-labs/gecko/utils/hashing"
@@ -38,13 +42,24 @@ import (
 	"github.com/ava-labs/gecko/vms"
 	"github.com/ava-labs/gecko/vms/avm"
 	"github.com/ava-labs/gecko/vms/evm"
+	"github.com/ava-labs/gecko/vms/nftfx"
 	"github.com/ava-labs/gecko/vms/platformvm"
+	"github.com/ava-labs/gecko/vms/rpcvm"
 	"github.com/ava-labs/gecko/vms/secp256k1fx"
 	"github.com/ava-labs/gecko/vms/spchainvm"
 	"github.com/ava-labs/gecko/vms/spdagvm"
 	"github.com/ava-labs/gecko/vms/timestampvm"
 )
 
+const (
+	dialTimeout = 10 * time.Second
+)
+
+var (
+	errUnknownTransport       = errors.New("unknown network transport")
+	errXputRequiresSalticidae = errors.New("the throughput server requires the salticidae network transport")
+)
+
 // MainNode is the reference for node callbacks
 var MainNode = Node{}
 
@@ -64,6 +79,9 @@ type Node struct {
 	// Handles calls to Keystore API
 	keystoreServer keystore.Keystore
 
+	// Memory that chains use to atomically transfer funds between each other
+	sharedMemory atomic.Memory
+
 	// Manages creation of blockchains and routing messages to them
 	chainManager chains.Manager
 
@@ -74,26 +92,15 @@ type Node struct {
 	DecisionDispatcher  *triggers.EventDispatcher
 	ConsensusDispatcher *triggers.EventDispatcher
 
-	// Event loop manager
-	EC salticidae.EventContext
-	// Network that manages validator peers
-	PeerNet salticidae.PeerNetwork
-	// Network that manages clients
-	ClientNet salticidae.MsgNetwork // TODO: Remove
+	// Net runs the networking stack
+	Net network.Network
 
-	// API that handles new connections
-	ValidatorAPI *networking.Handshake
-	// API that handles voting messages
-	ConsensusAPI *networking.Voting
+	// State that is only used by the salticidae network transport
+	salticidaeNode
 
 	// current validators of the network
 	vdrs validators.Manager
 
-	// APIs that handle client messages
-	// TODO: Remove
-	Issuer     *xputtest.Issuer
-	CClientAPI *xputtest.CClient
-
 	// Handles HTTP API calls
 	APIServer api.Server
 
@@ -107,67 +114,7 @@ type Node struct {
  ******************************************************************************
  */
 
-//export onTerm
-func onTerm(C.int, unsafe.Pointer) {
-	MainNode.Log.Debug("Terminate signal received")
-	MainNode.EC.Stop()
-}
-
-//export errorHandler
-func errorHandler(_err *C.struct_SalticidaeCError, fatal C.bool, _ unsafe.Pointer) {
-	err := (*salticidae.Error)(unsafe.Pointer(_err))
-	if fatal {
-		MainNode.Log.Fatal("Error during async call: %s", salticidae.StrError(err.GetCode()))
-		MainNode.EC.Stop()
-		return
-	}
-	MainNode.Log.Error("Error during async call: %s", salticidae.StrError(err.GetCode()))
-}
-
-func (n *Node) initNetlib() error {
-	// Create main event context
-	n.EC = salticidae.NewEventContext()
-
-	// Set up interrupt signal and terminate signal handlers
-	evInt := salticidae.NewSigEvent(n.EC, salticidae.SigEventCallback(C.onTerm), nil)
-	evInt.Add(salticidae.SIGINT)
-	evTerm := salticidae.NewSigEvent(n.EC, salticidae.SigEventCallback(C.onTerm), nil)
-	evTerm.Add(salticidae.SIGTERM)
-
-	// Create peer network config, may have tls enabled
-	peerConfig := salticidae.NewPeerNetworkConfig()
-	if n.Config.EnableStaking {
-		msgConfig := peerConfig.AsMsgNetworkConfig()
-		msgConfig.EnableTLS(true)
-		msgConfig.TLSKeyFile(n.Config.StakingKeyFile)
-		msgConfig.TLSCertFile(n.Config.StakingCertFile)
-	}
-
-	// Create the peer network
-	err := salticidae.NewError()
-	n.PeerNet = salticidae.NewPeerNetwork(n.EC, peerConfig, &err)
-	if code := err.GetCode(); code != 0 {
-		return errors.New(salticidae.StrError(code))
-	}
-	// Add peer network error handling
-	net := n.PeerNet.AsMsgNetwork()
-	net.RegErrorHandler(salticidae.MsgNetworkErrorCallback(C.errorHandler), nil)
-
-	if n.Config.ThroughputServerEnabled {
-		// Create the client network
-		msgConfig := salticidae.NewMsgNetworkConfig()
-		n.ClientNet = salticidae.NewMsgNetwork(n.EC, msgConfig, &err)
-		if code := err.GetCode(); code != 0 {
-			return errors.New(salticidae.StrError(code))
-		}
-		// Add client network error handling
-		n.ClientNet.RegErrorHandler(salticidae.MsgNetworkErrorCallback(C.errorHandler), nil)
-	}
-
-	return nil
-}
-
-func (n *Node) initValidatorNet() error {
+func (n *Node) initNetworking() error {
 	// Initialize validator manager and default subnet's validator set
 	defaultSubnetValidators := validators.NewSet()
 	if !n.Config.EnableStaking {
@@ -176,45 +123,73 @@ func (n *Node) initValidatorNet() error {
 	n.vdrs = validators.NewManager()
 	n.vdrs.PutValidatorSet(platformvm.DefaultSubnetID, defaultSubnetValidators)
 
-	cErr := salticidae.NewError()
-	serverIP := salticidae.NewNetAddrFromIPPortString(n.Config.StakingIP.String(), true, &cErr)
-	if code := cErr.GetCode(); code != 0 {
-		return errors.New(salticidae.StrError(code))
-	}
-
-	n.ValidatorAPI = &networking.HandshakeNet
-	n.ValidatorAPI.Initialize(
-		/*log=*/ n.Log,
-		/*validators=*/ defaultSubnetValidators,
-		/*myIP=*/ serverIP,
-		/*myID=*/ n.ID,
-		/*network=*/ n.PeerNet,
-		/*metrics=*/ n.Config.ConsensusParams.Metrics,
-		/*enableStaking=*/ n.Config.EnableStaking,
-		/*networkID=*/ n.Config.NetworkID,
-	)
-
-	return nil
+	switch n.Config.NetworkTransport {
+	case GoTransport:
+		if n.Config.ThroughputServerEnabled {
+			return errXputRequiresSalticidae
+		}
+		return n.initNetwork(defaultSubnetValidators)
+	case SalticidaeTransport:
+		return n.initSalticidaeNetwork(defaultSubnetValidators)
+	default:
+		return fmt.Errorf("%w: %s", errUnknownTransport, n.Config.NetworkTransport)
+	}
 }
 
-func (n *Node) initConsensusNet() {
-	vdrs, ok := n.vdrs.GetValidatorSet(platformvm.DefaultSubnetID)
-	n.Log.AssertTrue(ok, "should have initialize the validator set already")
+// initNetwork creates the pure go network this node uses to communicate with
+// other nodes
+func (n *Node) initNetwork(vdrs validators.Set) error {
+	listener, err := net.Listen("tcp", n.Config.StakingIP.PortString())
+	if err != nil {
+		return err
+	}
 
-	n.ConsensusAPI = &networking.VotingNet
-	n.ConsensusAPI.Initialize(n.Log, vdrs, n.PeerNet, n.ValidatorAPI.Connections(), n.chainManager.Router(), n.Config.ConsensusParams.Metrics)
+	dialer := network.NewDialer("tcp", dialTimeout)
+	var serverUpgrader, clientUpgrader network.Upgrader
+	if n.Config.EnableStaking {
+		cert, err := tls.LoadX509KeyPair(n.Config.StakingCertFile, n.Config.StakingKeyFile)
+		if err != nil {
+			return err
+		}
 
-	n.Log.AssertNoError(n.ConsensusDispatcher.Register("gossip", n.ConsensusAPI))
-}
+		tlsConfig := network.TLSConfig(cert)
 
-func (n *Node) initClients() {
-	n.Issuer = &xputtest.Issuer{}
-	n.Issuer.Initialize()
+		serverUpgrader = network.NewTLSServerUpgrader(tlsConfig)
+		clientUpgrader = network.NewTLSClientUpgrader(tlsConfig)
+	} else {
+		serverUpgrader = network.NewIPUpgrader(n.Config.StakingIP)
+		clientUpgrader = network.NewIPUpgrader(n.Config.StakingIP)
+	}
 
-	n.CClientAPI = &xputtest.CClientHandler
-	n.CClientAPI.Initialize(n.ClientNet, n.Issuer)
+	n.Net = network.NewDefaultNetwork(
+		n.Config.ConsensusParams.Metrics,
+		n.Log,
+		n.ID,
+		n.Config.StakingIP,
+		n.Config.NetworkID,
+		network.CurrentVersion,
+		listener,
+		dialer,
+		serverUpgrader,
+		clientUpgrader,
+		vdrs,
+		n.Config.ConsensusRouter,
+		n.Config.EnableStaking,
+		prefixdb.New([]byte("peers"), n.DB),
+		n.Config.MaxPeers,
+	)
 
-	n.chainManager.AddRegistrant(n.Issuer)
+	go n.Log.RecoverAndPanic(func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+		<-signals
+		n.Log.Debug("Terminate signal received")
+		if err := n.Net.Close(); err != nil {
+			n.Log.Debug("closing the network returned with: %s", err)
+		}
+	})
+	return nil
 }
 
 // StartConsensusServer starts the P2P server this node uses to communicate
@@ -222,45 +197,16 @@ func (n *Node) initClients() {
 func (n *Node) StartConsensusServer() error {
 	n.Log.Verbo("starting the consensus server")
 
-	n.PeerNet.AsMsgNetwork().Start()
-
-	err := salticidae.NewError()
-
-	// The IP this node listens on for P2P messaging
-	serverIP := salticidae.NewNetAddrFromIPPortString(n.Config.StakingIP.String(), true, &err)
-	if code := err.GetCode(); code != 0 {
-		return fmt.Errorf("failed to create ip addr: %s", salticidae.StrError(code))
-	}
-
-	// Listen for P2P messages
-	n.PeerNet.Listen(serverIP, &err)
-	if code := err.GetCode(); code != 0 {
-		return fmt.Errorf("failed to start consensus server: %s", salticidae.StrError(code))
-	}
-
-	// Start a server to handle throughput tests if configuration says to. Disabled by default.
-	if n.Config.ThroughputServerEnabled {
-		n.ClientNet.Start()
-
-		clientIP := salticidae.NewNetAddrFromIPPortString(fmt.Sprintf("127.0.0.1:%d", n.Config.ThroughputPort), true, &err)
-		if code := err.GetCode(); code != 0 {
-			return fmt.Errorf("failed to start xput server: %s", salticidae.StrError(code))
-		}
-
-		n.ClientNet.Listen(clientIP, &err)
-		if code := err.GetCode(); code != 0 {
-			return fmt.Errorf("failed to listen on xput server: %s", salticidae.StrError(code))
+	if n.Config.NetworkTransport == SalticidaeTransport {
+		if err := n.startSalticidaeServer(); err != nil {
+			return err
 		}
 	}
 
 	// Add bootstrap nodes to the peer network
 	for _, peer := range n.Config.BootstrapPeers {
 		if !peer.IP.Equal(n.Config.StakingIP) {
-			bootstrapIP := salticidae.NewNetAddrFromIPPortString(peer.IP.String(), true, &err)
-			if code := err.GetCode(); code != 0 {
-				return fmt.Errorf("failed to create bootstrap ip addr: %s", salticidae.StrError(code))
-			}
-			n.PeerNet.AddPeer(bootstrapIP)
+			n.Net.Track(peer.IP)
 		} else {
 			n.Log.Error("can't add self as a bootstrapper")
 		}
@@ -271,7 +217,7 @@ func (n *Node) StartConsensusServer() error {
 
 // Dispatch starts the node's servers.
 // Returns when the node exits.
-func (n *Node) Dispatch() { n.EC.Dispatch() }
+func (n *Node) Dispatch() error { return n.Net.Dispatch() }
 
 /*
  ******************************************************************************
@@ -315,13 +261,83 @@ func (n *Node) initNodeID() error {
 // The Platform VM is registered in initStaking because
 // its factory needs to reference n.chainManager, which is nil right now
 func (n *Node) initVMManager() {
+	_, avaAssetID := n.avaIDs()
+
 	n.vmManager = vms.NewManager(&n.APIServer, n.HTTPLog)
-	n.vmManager.RegisterVMFactory(avm.ID, &avm.Factory{})
+	n.vmManager.RegisterVMFactory(avm.ID, &avm.Factory{
+		AVA:      avaAssetID,
+		Platform: ids.Empty,
+		TxFee:    n.Config.AvaTxFee,
+		IndexTxs: n.Config.AVMTxIndexEnabled,
+	})
 	n.vmManager.RegisterVMFactory(evm.ID, &evm.Factory{})
 	n.vmManager.RegisterVMFactory(spdagvm.ID, &spdagvm.Factory{TxFee: n.Config.AvaTxFee})
-	n.vmManager.RegisterVMFactory(spchainvm.ID, &spchainvm.Factory{})
+	n.vmManager.RegisterVMFactory(spchainvm.ID, &spchainvm.Factory{TxFee: n.Config.AvaTxFee})
 	n.vmManager.RegisterVMFactory(secp256k1fx.ID, &secp256k1fx.Factory{})
+	n.vmManager.RegisterVMFactory(nftfx.ID, &nftfx.Factory{})
 	n.vmManager.RegisterVMFactory(timestampvm.ID, &timestampvm.Factory{})
+	n.initPlugins()
+}
+
+// Register the VM served by each plugin in the plugin directory. A plugin's VM
+// is aliased with the plugin's file name.
+func (n *Node) initPlugins() {
+	if n.Config.PluginDir == "" {
+		return
+	}
+	files, err := ioutil.ReadDir(n.Config.PluginDir)
+	if err != nil {
+		n.Log.Error("couldn't read the plugin directory: %s", err)
+		return
+	}
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		name := file.Name()
+		factory, err := rpcvm.NewFactory(filepath.Join(n.Config.PluginDir, name))
+		if err != nil {
+			n.Log.Error("couldn't load plugin %s: %s", name, err)
+			continue
+		}
+		vmID := pluginVMID(name)
+		if err := n.vmManager.RegisterVMFactory(vmID, factory); err != nil {
+			n.Log.Error("couldn't register plugin %s: %s", name, err)
+			continue
+		}
+		if err := n.vmManager.Alias(vmID, name); err != nil {
+			n.Log.Error("couldn't alias plugin %s: %s", name, err)
+			continue
+		}
+		n.Log.Info("registered %s VM %s from plugin %s", factory.Kind(), vmID, name)
+	}
+}
+
+// pluginVMID returns the ID of the VM served by the plugin named [name]. If the
+// name isn't an ID, the VM's ID is derived from the name the same way the IDs
+// of the built-in VMs are.
+func pluginVMID(name string) ids.ID {
+	if vmID, err := ids.FromString(name); err == nil {
+		return vmID
+	}
+	if len(name) > 32 {
+		return ids.NewID(hashing.ComputeHash256Array([]byte(name)))
+	}
+	vmID := [32]byte{}
+	copy(vmID[:], name)
+	return ids.NewID(vmID)
+}
+
+// avaIDs returns the ID of the AVM chain and the ID of the AVA asset created by
+// this node's genesis. If the genesis doesn't create them, empty IDs are
+// returned and AVA can't be transferred between the AVM and the platform chain.
+func (n *Node) avaIDs() (ids.ID, ids.ID) {
+	avmChainID, avaAssetID, err := genesis.AVA(n.Config.GenesisBytes)
+	if err != nil {
+		n.Log.Warn("AVA can't be transferred between the AVM and the platform chain: %s", err)
+		return ids.ID{}, ids.ID{}
+	}
+	return avmChainID, avaAssetID
 }
 
 // Create the EventDispatcher used for hooking events
@@ -334,6 +350,26 @@ func (n *Node) initEventDispatcher() {
 	n.ConsensusDispatcher.Initialize(n.Log)
 }
 
+// initEventsAPI initializes the Events API, which sends the events of the
+// dispatchers to websocket subscribers.
+// Assumes n.DecisionDispatcher, n.ConsensusDispatcher and n.chainManager
+// already initialized
+func (n *Node) initEventsAPI() error {
+	if !n.Config.EventsAPIEnabled {
+		return nil
+	}
+	n.Log.Info("initializing Events API")
+	server := events.NewServer(n.Log, n.chainManager)
+	if err := n.DecisionDispatcher.Register("events", server.DecisionListener()); err != nil {
+		return err
+	}
+	if err := n.ConsensusDispatcher.Register("events", server.ConsensusListener()); err != nil {
+		return err
+	}
+	n.chainManager.AddRegistrant(server)
+	return n.APIServer.AddRoute(&common.HTTPHandler{LockOptions: common.NoLock, Handler: server}, &sync.RWMutex{}, "events", "", n.HTTPLog)
+}
+
 // Initializes the Platform chain.
 // Its genesis data specifies the other chains that should
 // be created.
@@ -347,11 +383,16 @@ func (n *Node) initChains() {
 		vdrs.PutValidatorSet(platformvm.DefaultSubnetID, defaultSubnetValidators)
 	}
 
+	avmChainID, avaAssetID := n.avaIDs()
 	n.vmManager.RegisterVMFactory(
 		/*vmID=*/ platformvm.ID,
 		/*vmFactory=*/ &platformvm.Factory{
-			ChainManager: n.chainManager,
-			Validators:   vdrs,
+			ChainManager:      n.chainManager,
+			Validators:        vdrs,
+			AVM:               avmChainID,
+			AVA:               avaAssetID,
+			TxFee:             n.Config.AvaTxFee,
+			UptimeRequirement: n.Config.UptimeRequirement,
 		},
 	)
 
@@ -360,14 +401,17 @@ func (n *Node) initChains() {
 		beacons.Add(validators.NewValidator(peer.ID, 1))
 	}
 
-	genesisBytes := genesis.Genesis(n.Config.NetworkID)
+	n.Log.Info("genesis ID: %s", genesis.ID(n.Config.GenesisBytes))
 
-	// Create the Platform Chain
+	// Create the Platform Chain. Until it's synced, this node doesn't know the
+	// validators' stake, so it's first bootstrapped from the bootstrap peers.
 	n.chainManager.ForceCreateChain(chains.ChainParameters{
-		ID:            ids.Empty,
-		GenesisData:   genesisBytes, // Specifies other chains to create
-		VMAlias:       platformvm.ID.String(),
-		CustomBeacons: beacons,
+		ID:                     ids.Empty,
+		SubnetID:               platformvm.DefaultSubnetID,
+		GenesisData:            n.Config.GenesisBytes, // Specifies other chains to create
+		VMAlias:                platformvm.ID.String(),
+		CustomBeacons:          beacons,
+		AlwaysUseCustomBeacons: !n.Config.EnableStaking,
 	})
 }
 
@@ -401,17 +445,43 @@ func (n *Node) initChainManager() {
 		n.ConsensusDispatcher,
 		n.DB,
 		n.Config.ConsensusRouter,
-		&networking.VotingNet,
+		n.Net,
 		n.Config.ConsensusParams,
+		n.Config.BootstrapStakeFraction,
+		n.Config.BootstrapRetries,
+		n.Config.ConsensusBufferSize,
+		n.Config.ConsensusMaxPeerMessages,
 		n.vdrs,
 		n.ID,
 		n.Config.NetworkID,
-		n.ValidatorAPI,
+		n.Net,
 		&n.APIServer,
 		&n.keystoreServer,
+		&n.sharedMemory,
+		n.remoteSigner(),
 	)
 
 	n.chainManager.AddRegistrant(&n.APIServer)
+	n.chainManager.AddRegistrant(&connectorRegistrant{net: n.Net})
+
+	n.Log.AssertNoError(n.ConsensusDispatcher.Register("gossip", n.Net))
+}
+
+// remoteSigner returns the signer that holds the keys of users, or nil if the
+// keys are held in the keystore
+func (n *Node) remoteSigner() snow.RemoteSigner {
+	if n.Config.RemoteSignerPath == "" {
+		return nil
+	}
+	n.Log.Info("keys of users are held by the signer at %s", n.Config.RemoteSignerPath)
+	return signer.NewClient(n.Config.RemoteSignerPath)
+}
+
+// initSharedMemory initializes the memory chains share with each other
+func (n *Node) initSharedMemory() {
+	n.Log.Info("initializing SharedMemory")
+	sharedMemoryDB := prefixdb.New([]byte("shared memory"), n.DB)
+	n.sharedMemory.Initialize(n.Log, sharedMemoryDB)
 }
 
 // initWallet initializes the Wallet service
@@ -438,11 +508,11 @@ func (n *Node) initMetricsAPI() {
 }
 
 // initAdminAPI initializes the Admin API service
-// Assumes n.log, n.chainManager, and n.ValidatorAPI already initialized
+// Assumes n.log, n.chainManager, and n.Net already initialized
 func (n *Node) initAdminAPI() {
 	if n.Config.AdminAPIEnabled {
 		n.Log.Info("initializing Admin API")
-		service := admin.NewService(n.ID, n.Config.NetworkID, n.Log, n.chainManager, n.ValidatorAPI.Connections(), &n.APIServer)
+		service := admin.NewService(n.ID, n.Config.NetworkID, n.Log, n.chainManager, n.Net, &n.APIServer)
 		n.APIServer.AddRoute(service, &sync.RWMutex{}, "admin", "", n.HTTPLog)
 	}
 }
@@ -458,9 +528,12 @@ func (n *Node) initIPCAPI() {
 }
 
 // Give chains and VMs aliases as specified by the genesis information
-func (n *Node) initAliases() {
+func (n *Node) initAliases() error {
 	n.Log.Info("initializing aliases")
-	defaultAliases, chainAliases, vmAliases := genesis.Aliases(n.Config.NetworkID)
+	defaultAliases, chainAliases, vmAliases, err := genesis.Aliases(n.Config.GenesisBytes, n.Config.ChainAliases)
+	if err != nil {
+		return err
+	}
 	for chainIDKey, aliases := range chainAliases {
 		chainID := ids.NewID(chainIDKey)
 		for _, alias := range aliases {
@@ -476,6 +549,7 @@ func (n *Node) initAliases() {
 	for url, aliases := range defaultAliases {
 		n.APIServer.AddAliases(url, aliases...)
 	}
+	return nil
 }
 
 // Initialize this node
@@ -502,24 +576,32 @@ func (n *Node) Initialize(Config *Config, logger logging.Logger, logFactory logg
 	n.initMetricsAPI()  // Start the Metrics API
 
 	// Start node-to-node consensus server
-	if err = n.initNetlib(); err != nil { // Set up all networking
+	if err = n.initNetworking(); err != nil { // Set up all networking
 		return fmt.Errorf("problem initializing networking: %w", err)
 	}
-	n.initValidatorNet()    // Set up the validator handshake + authentication
+	n.initSharedMemory()    // Set up the shared memory
 	n.initVMManager()       // Set up the vm manager
 	n.initEventDispatcher() // Set up the event dipatcher
 	n.initChainManager()    // Set up the chain manager
-	n.initConsensusNet()    // Set up the main consensus network
 
 	// TODO: Remove once API is fully featured for throughput tests
 	if n.Config.ThroughputServerEnabled {
-		n.initClients() // Set up the client servers
+		if err = n.initClients(); err != nil { // Set up the client servers
+			return fmt.Errorf("problem initializing throughput clients: %w", err)
+		}
 	}
 
 	n.initAdminAPI() // Start the Admin API
 	n.initIPCAPI()   // Start the IPC API
-	n.initAliases()  // Set up aliases
-	n.initChains()   // Start the Platform chain
+
+	if err = n.initEventsAPI(); err != nil { // Start the Events API
+		return fmt.Errorf("problem initializing events API: %w", err)
+	}
+
+	if err = n.initAliases(); err != nil { // Set up aliases
+		return fmt.Errorf("problem initializing aliases: %w", err)
+	}
+	n.initChains() // Start the Platform chain
 
 	return nil
 }
@@ -527,7 +609,19 @@ func (n *Node) Initialize(Config *Config, logger logging.Logger, logFactory logg
 // Shutdown this node
 func (n *Node) Shutdown() {
 	n.Log.Info("shutting down the node")
-	n.ValidatorAPI.Shutdown()
-	n.ConsensusAPI.Shutdown()
+	if err := n.Net.Close(); err != nil {
+		n.Log.Debug("closing the network returned with: %s", err)
+	}
 	n.chainManager.Shutdown()
 }
+
+// connectorRegistrant registers the VMs of new chains that want to know when
+// peers connect and disconnect with the network
+type connectorRegistrant struct{ net network.Network }
+
+// RegisterChain implements the chains.Registrant interface
+func (r *connectorRegistrant) RegisterChain(_ *snow.Context, vm interface{}) {
+	if connector, ok := vm.(networking.Connector); ok {
+		r.net.RegisterConnector(connector)
+	}
+}
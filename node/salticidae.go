@@ -0,0 +1,251 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//go:build cgo
+// +build cgo
+
+package node
+
+// #include "salticidae/network.h"
+// void onTerm(int sig, void *);
+// void errorHandler(SalticidaeCError *, bool, void *);
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"unsafe"
+
+	"github.com/ava-labs/salticidae-go"
+
+	"github.com/ava-labs/gecko/networking"
+	"github.com/ava-labs/gecko/networking/xputtest"
+	"github.com/ava-labs/gecko/snow/validators"
+	"github.com/ava-labs/gecko/utils"
+
+	snownetworking "github.com/ava-labs/gecko/snow/networking"
+)
+
+// salticidaeNode contains the state of a node that uses the salticidae
+// transport
+type salticidaeNode struct {
+	// Event loop manager
+	EC salticidae.EventContext
+	// Network that manages validator peers
+	PeerNet salticidae.PeerNetwork
+	// Network that manages clients
+	ClientNet salticidae.MsgNetwork // TODO: Remove
+
+	// API that handles new connections
+	ValidatorAPI *networking.Handshake
+	// API that handles voting messages
+	ConsensusAPI *networking.Voting
+
+	// APIs that handle client messages
+	// TODO: Remove
+	Issuer     *xputtest.Issuer
+	CClientAPI *xputtest.CClient
+}
+
+//export onTerm
+func onTerm(C.int, unsafe.Pointer) {
+	MainNode.Log.Debug("Terminate signal received")
+	MainNode.EC.Stop()
+}
+
+//export errorHandler
+func errorHandler(_err *C.struct_SalticidaeCError, fatal C.bool, _ unsafe.Pointer) {
+	err := (*salticidae.Error)(unsafe.Pointer(_err))
+	if fatal {
+		MainNode.Log.Fatal("Error during async call: %s", salticidae.StrError(err.GetCode()))
+		MainNode.EC.Stop()
+		return
+	}
+	MainNode.Log.Error("Error during async call: %s", salticidae.StrError(err.GetCode()))
+}
+
+// initSalticidaeNetwork sets up the salticidae networking library and the
+// handshake and voting handlers that run on top of it.
+func (n *Node) initSalticidaeNetwork(vdrs validators.Set) error {
+	if err := n.initNetlib(); err != nil {
+		return err
+	}
+	if err := n.initValidatorNet(vdrs); err != nil {
+		return err
+	}
+	n.initConsensusNet(vdrs)
+
+	n.Net = &salticidaeNetwork{
+		Voting: n.ConsensusAPI,
+		node:   n,
+	}
+	return nil
+}
+
+func (n *Node) initNetlib() error {
+	// Create main event context
+	n.EC = salticidae.NewEventContext()
+
+	// Set up interrupt signal and terminate signal handlers
+	evInt := salticidae.NewSigEvent(n.EC, salticidae.SigEventCallback(C.onTerm), nil)
+	evInt.Add(salticidae.SIGINT)
+	evTerm := salticidae.NewSigEvent(n.EC, salticidae.SigEventCallback(C.onTerm), nil)
+	evTerm.Add(salticidae.SIGTERM)
+
+	// Create peer network config, may have tls enabled
+	peerConfig := salticidae.NewPeerNetworkConfig()
+	if n.Config.EnableStaking {
+		msgConfig := peerConfig.AsMsgNetworkConfig()
+		msgConfig.EnableTLS(true)
+		msgConfig.TLSKeyFile(n.Config.StakingKeyFile)
+		msgConfig.TLSCertFile(n.Config.StakingCertFile)
+	}
+
+	// Create the peer network
+	err := salticidae.NewError()
+	n.PeerNet = salticidae.NewPeerNetwork(n.EC, peerConfig, &err)
+	if code := err.GetCode(); code != 0 {
+		return errors.New(salticidae.StrError(code))
+	}
+	// Add peer network error handling
+	net := n.PeerNet.AsMsgNetwork()
+	net.RegErrorHandler(salticidae.MsgNetworkErrorCallback(C.errorHandler), nil)
+
+	if n.Config.ThroughputServerEnabled {
+		// Create the client network
+		msgConfig := salticidae.NewMsgNetworkConfig()
+		n.ClientNet = salticidae.NewMsgNetwork(n.EC, msgConfig, &err)
+		if code := err.GetCode(); code != 0 {
+			return errors.New(salticidae.StrError(code))
+		}
+		// Add client network error handling
+		n.ClientNet.RegErrorHandler(salticidae.MsgNetworkErrorCallback(C.errorHandler), nil)
+	}
+
+	return nil
+}
+
+func (n *Node) initValidatorNet(vdrs validators.Set) error {
+	cErr := salticidae.NewError()
+	serverIP := salticidae.NewNetAddrFromIPPortString(n.Config.StakingIP.String(), true, &cErr)
+	if code := cErr.GetCode(); code != 0 {
+		return errors.New(salticidae.StrError(code))
+	}
+
+	n.ValidatorAPI = &networking.HandshakeNet
+	n.ValidatorAPI.Initialize(
+		/*log=*/ n.Log,
+		/*validators=*/ vdrs,
+		/*myIP=*/ serverIP,
+		/*myID=*/ n.ID,
+		/*network=*/ n.PeerNet,
+		/*metrics=*/ n.Config.ConsensusParams.Metrics,
+		/*enableStaking=*/ n.Config.EnableStaking,
+		/*networkID=*/ n.Config.NetworkID,
+	)
+
+	return nil
+}
+
+func (n *Node) initConsensusNet(vdrs validators.Set) {
+	n.ConsensusAPI = &networking.VotingNet
+	n.ConsensusAPI.Initialize(n.Log, vdrs, n.PeerNet, n.ValidatorAPI.Connections(), n.Config.ConsensusRouter, n.Config.ConsensusParams.Metrics)
+}
+
+func (n *Node) initClients() error {
+	n.Issuer = &xputtest.Issuer{}
+	n.Issuer.Initialize()
+
+	n.CClientAPI = &xputtest.CClientHandler
+	n.CClientAPI.Initialize(n.ClientNet, n.Issuer)
+
+	n.chainManager.AddRegistrant(n.Issuer)
+	return nil
+}
+
+// startSalticidaeServer starts listening for P2P messages using salticidae
+func (n *Node) startSalticidaeServer() error {
+	n.PeerNet.AsMsgNetwork().Start()
+
+	err := salticidae.NewError()
+
+	// The IP this node listens on for P2P messaging
+	serverIP := salticidae.NewNetAddrFromIPPortString(n.Config.StakingIP.String(), true, &err)
+	if code := err.GetCode(); code != 0 {
+		return fmt.Errorf("failed to create ip addr: %s", salticidae.StrError(code))
+	}
+
+	// Listen for P2P messages
+	n.PeerNet.Listen(serverIP, &err)
+	if code := err.GetCode(); code != 0 {
+		return fmt.Errorf("failed to start consensus server: %s", salticidae.StrError(code))
+	}
+
+	// Start a server to handle throughput tests if configuration says to. Disabled by default.
+	if n.Config.ThroughputServerEnabled {
+		n.ClientNet.Start()
+
+		clientIP := salticidae.NewNetAddrFromIPPortString(fmt.Sprintf("127.0.0.1:%d", n.Config.ThroughputPort), true, &err)
+		if code := err.GetCode(); code != 0 {
+			return fmt.Errorf("failed to start xput server: %s", salticidae.StrError(code))
+		}
+
+		n.ClientNet.Listen(clientIP, &err)
+		if code := err.GetCode(); code != 0 {
+			return fmt.Errorf("failed to listen on xput server: %s", salticidae.StrError(code))
+		}
+	}
+	return nil
+}
+
+// salticidaeNetwork adapts the salticidae handshake and voting handlers to the
+// network.Network interface
+type salticidaeNetwork struct {
+	// sends consensus messages and gossips accepted containers
+	*networking.Voting
+
+	node *Node
+	once sync.Once
+}
+
+// Dispatch runs the salticidae event loop until the node is terminated
+func (s *salticidaeNetwork) Dispatch() error {
+	s.node.EC.Dispatch()
+	return nil
+}
+
+// Track adds the IP to the salticidae peer network
+func (s *salticidaeNetwork) Track(ip utils.IPDesc) {
+	err := salticidae.NewError()
+	addr := salticidae.NewNetAddrFromIPPortString(ip.String(), true, &err)
+	if code := err.GetCode(); code != 0 {
+		s.node.Log.Error("failed to create ip addr for %s: %s", ip, salticidae.StrError(code))
+		return
+	}
+	s.node.PeerNet.AddPeer(addr)
+}
+
+// AwaitConnections waits for the handshake to finish with the requested peers
+func (s *salticidaeNetwork) AwaitConnections(awaiting *snownetworking.AwaitingConnections) {
+	s.node.ValidatorAPI.AwaitConnections(awaiting)
+}
+
+// RegisterConnector notifies [connector] of connections and disconnections
+func (s *salticidaeNetwork) RegisterConnector(connector snownetworking.Connector) {
+	s.node.ValidatorAPI.RegisterConnector(connector)
+}
+
+// Peers returns the IPs of the currently connected peers
+func (s *salticidaeNetwork) Peers() []utils.IPDesc {
+	return s.node.ValidatorAPI.Connections().Peers()
+}
+
+// Close stops the handshake and voting handlers
+func (s *salticidaeNetwork) Close() error {
+	s.once.Do(func() {
+		s.node.ValidatorAPI.Shutdown()
+		s.node.ConsensusAPI.Shutdown()
+	})
+	return nil
+}
@@ -0,0 +1,26 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//go:build !cgo
+// +build !cgo
+
+package node
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/snow/validators"
+)
+
+var (
+	errSalticidaeDisabled = errors.New("the salticidae transport requires a cgo enabled build")
+)
+
+// salticidaeNode contains no state when cgo is disabled
+type salticidaeNode struct{}
+
+func (n *Node) initSalticidaeNetwork(validators.Set) error { return errSalticidaeDisabled }
+
+func (n *Node) initClients() error { return errSalticidaeDisabled }
+
+func (n *Node) startSalticidaeServer() error { return errSalticidaeDisabled }
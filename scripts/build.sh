@@ -18,3 +18,4 @@ else
 fi
 go build -o "$PREFIX/ava" "$GECKO_PATH/main/"*.go
 go build -o "$PREFIX/xputtest" "$GECKO_PATH/xputtest/"*.go
+go build -o "$PREFIX/signer" "$GECKO_PATH/signer/"*.go
@@ -0,0 +1,22 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+// Config contains all of the configurations of a signer.
+type Config struct {
+	// Unix socket the signer serves the node on
+	SocketPath string
+
+	// Database directory the encrypted keys of users are stored in
+	DBDir string
+
+	// If non-empty, a key is imported for this user instead of serving
+	ImportUsername string
+
+	LoggingConfig logging.Config
+}
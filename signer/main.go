@@ -0,0 +1,125 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"path"
+	"strings"
+	"syscall"
+
+	"github.com/ava-labs/gecko/api/signer"
+	"github.com/ava-labs/gecko/database/leveldb"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+var errMissingInput = errors.New("expected the password and the private key on separate lines")
+
+// main runs a signer: a process that holds the keys of the node's users, so
+// that they never enter the node's memory. It either serves the node on a unix
+// socket, or imports a key for a user.
+func main() {
+	if err != nil {
+		fmt.Printf("parsing parameters returned with error %s\n", err)
+		return
+	}
+
+	loggingConfig := config.LoggingConfig
+	loggingConfig.Directory = path.Join(loggingConfig.Directory, "signer")
+	factory := logging.NewFactory(loggingConfig)
+	defer factory.Close()
+
+	log, err := factory.Make()
+	if err != nil {
+		fmt.Printf("starting logger failed with: %s\n", err)
+		return
+	}
+	defer log.Stop()
+	defer log.StopOnPanic()
+
+	db, err := leveldb.New(config.DBDir, 0, 0, 0)
+	if err != nil {
+		log.Fatal("couldn't open the database at %s: %s", config.DBDir, err)
+		return
+	}
+	defer db.Close()
+
+	keychain := signer.NewKeychain(log, db)
+
+	if config.ImportUsername != "" {
+		if err := importKey(keychain, config.ImportUsername, os.Stdin); err != nil {
+			log.Fatal("couldn't import the key: %s", err)
+			return
+		}
+		log.Info("imported a key for user '%s'", config.ImportUsername)
+		return
+	}
+
+	// A socket left behind by a signer that didn't shut down cleanly would
+	// prevent listening
+	if err := os.Remove(config.SocketPath); err != nil && !os.IsNotExist(err) {
+		log.Fatal("couldn't remove the stale socket at %s: %s", config.SocketPath, err)
+		return
+	}
+	listener, err := net.Listen("unix", config.SocketPath)
+	if err != nil {
+		log.Fatal("couldn't listen on %s: %s", config.SocketPath, err)
+		return
+	}
+	// Only the user running the signer may connect to it
+	if err := os.Chmod(config.SocketPath, 0600); err != nil {
+		listener.Close()
+		log.Fatal("couldn't restrict access to %s: %s", config.SocketPath, err)
+		return
+	}
+
+	// Closing the listener stops serving and removes the socket
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-signals
+		log.Info("shutting down")
+		listener.Close()
+	}()
+
+	log.Info("serving keys on %s", config.SocketPath)
+	if err := signer.NewServer(log, keychain).Serve(listener); err != nil {
+		log.Debug("stopped serving: %s", err)
+	}
+}
+
+// importKey reads a password and a private key from [r], one per line, and
+// gives [username] control of the key
+func importKey(keychain *signer.Keychain, username string, r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	lines := []string(nil)
+	for len(lines) < 2 && scanner.Scan() {
+		lines = append(lines, strings.TrimSpace(scanner.Text()))
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if len(lines) < 2 {
+		return errMissingInput
+	}
+
+	keyStr := formatting.CB58{}
+	if err := keyStr.FromString(lines[1]); err != nil {
+		return fmt.Errorf("problem parsing the private key: %w", err)
+	}
+	factory := crypto.FactorySECP256K1R{}
+	sk, err := factory.ToPrivateKey(keyStr.Bytes)
+	if err != nil {
+		return fmt.Errorf("problem parsing the private key: %w", err)
+	}
+	return keychain.AddKey(username, lines[0], sk.(*crypto.PrivateKeySECP256K1R))
+}
@@ -0,0 +1,53 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"errors"
+	"flag"
+
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+var (
+	config Config
+	err    error
+)
+
+var errNoSocket = errors.New("a socket path must be provided")
+
+// Parse the CLI arguments
+func init() {
+	errs := &wrappers.Errs{}
+	defer func() { err = errs.Err }()
+
+	loggingConfig, err := logging.DefaultConfig()
+	errs.Add(err)
+
+	// Signer:
+	flag.StringVar(&config.SocketPath, "socket-path", "", "Unix socket to serve the node on. The node's remote-signer-path must be set to it")
+	flag.StringVar(&config.DBDir, "db-dir", "signer-db", "Database directory the keys of users are stored in, encrypted with their passwords")
+	flag.StringVar(&config.ImportUsername, "import-key", "", "If set, read the password of this user and a private key from stdin, one per line, and give the user control of the key instead of serving. The user is created if they don't exist")
+
+	// Logging:
+	logsDir := flag.String("log-dir", "", "Logging directory for the signer")
+	logLevel := flag.String("log-level", "info", "The log level. Should be one of {verbo, debug, info, warn, error, fatal, off}")
+
+	flag.Parse()
+
+	if config.SocketPath == "" && config.ImportUsername == "" {
+		errs.Add(errNoSocket)
+	}
+
+	// Logging:
+	if *logsDir != "" {
+		loggingConfig.Directory = *logsDir
+	}
+	level, err := logging.ToLevel(*logLevel)
+	errs.Add(err)
+	loggingConfig.LogLevel = level
+	loggingConfig.DisplayLevel = level
+	config.LoggingConfig = loggingConfig
+}
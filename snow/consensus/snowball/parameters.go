@@ -12,7 +12,7 @@ import (
 // Parameters required for snowball consensus
 type Parameters struct {
 	Namespace                         string
-	Metrics                           prometheus.Registerer
+	Metrics                           prometheus.Registerer `json:"-"`
 	K, Alpha, BetaVirtuous, BetaRogue int
 }
 
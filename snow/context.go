@@ -24,6 +24,29 @@ type Keystore interface {
 	GetDatabase(username, password string) (database.Database, error)
 }
 
+// Signer signs on behalf of a set of addresses, without revealing the keys
+// that control them
+type Signer interface {
+	// Addresses returns the addresses this signer can sign for
+	Addresses() ([]ids.ShortID, error)
+
+	// SignHash returns the recoverable signature of [hash] by the key that
+	// controls [addr]
+	SignHash(addr ids.ShortID, hash []byte) ([]byte, error)
+}
+
+// RemoteSigner gives access to the signers of users whose keys are held
+// outside of the node's keystore
+type RemoteSigner interface {
+	GetSigner(username, password string) (Signer, error)
+}
+
+// SharedMemory ...
+type SharedMemory interface {
+	GetDatabase(id ids.ID) database.Database
+	ReleaseDatabase(id ids.ID)
+}
+
 // AliasLookup ...
 type AliasLookup interface {
 	Lookup(alias string) (ids.ID, error)
@@ -44,6 +67,8 @@ type Context struct {
 	Lock                sync.RWMutex
 	HTTP                Callable
 	Keystore            Keystore
+	RemoteSigner        RemoteSigner // nil if keys are held in the keystore
+	SharedMemory        SharedMemory
 	BCLookup            AliasLookup
 }
 
@@ -4,13 +4,20 @@
 package avalanche
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/snow/consensus/avalanche"
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/snow/engine/common/queue"
 	"github.com/ava-labs/gecko/utils/formatting"
-	"github.com/prometheus/client_golang/prometheus"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+var (
+	errNoAcceptedFrontier = errors.New("vertices were fetched, but the accepted frontier they were fetched from is unknown")
 )
 
 // BootstrapConfig ...
@@ -30,13 +37,19 @@ type bootstrapper struct {
 	metrics
 	common.Bootstrapper
 
-	pending    ids.Set
+	// outstandingRequests tracks which validators were asked for which
+	// vertices in which requests
+	outstandingRequests common.Requests
+
 	finished   bool
 	onFinished func()
 }
 
 // Initialize this engine.
 func (b *bootstrapper) Initialize(config BootstrapConfig) {
+	if config.Progress == nil {
+		config.Progress = &common.Progress{}
+	}
 	b.BootstrapConfig = config
 
 	b.VtxBlocked.SetParser(&vtxParser{
@@ -73,44 +86,203 @@ func (b *bootstrapper) FilterAccepted(containerIDs ids.Set) ids.Set {
 	return acceptedVtxIDs
 }
 
+// ResumableFrontier ...
+func (b *bootstrapper) ResumableFrontier() (ids.Set, bool) {
+	acceptedFrontier, err := b.resumableFrontier()
+	if err != nil {
+		b.BootstrapConfig.Context.Log.Warn("Discarding bootstrapping progress due to %s", err)
+		errs := wrappers.Errs{}
+		errs.Add(
+			b.VtxBlocked.Clear(),
+			b.TxBlocked.Clear(),
+		)
+		if errs.Errored() {
+			b.BootstrapConfig.Context.Log.Error("Failed to discard bootstrapping progress due to %s", errs.Err)
+		}
+		return nil, false
+	}
+	b.updateProgress()
+	return acceptedFrontier, acceptedFrontier.Len() > 0
+}
+
+// resumableFrontier returns the accepted frontier that was being fetched, or
+// an error if the persisted progress is invalid
+func (b *bootstrapper) resumableFrontier() (ids.Set, error) {
+	if err := b.VtxBlocked.Verify(); err != nil {
+		return nil, err
+	}
+	if err := b.TxBlocked.Verify(); err != nil {
+		return nil, err
+	}
+
+	acceptedFrontier, err := b.VtxBlocked.Frontier()
+	if err != nil {
+		return nil, err
+	}
+	numFetched, err := b.VtxBlocked.NumJobs()
+	if err != nil {
+		return nil, err
+	}
+	if numFetched > 0 && acceptedFrontier.Len() == 0 {
+		return nil, errNoAcceptedFrontier
+	}
+
+	// Vertices and txs are accepted before their execution is committed, so
+	// the last vertex and tx that were executed must have been accepted
+	numExecuted, err := b.VtxBlocked.NumExecuted()
+	if err != nil {
+		return nil, err
+	}
+	if numExecuted > 0 {
+		vtxID, err := b.VtxBlocked.LastExecuted()
+		if err != nil {
+			return nil, err
+		}
+		if vtx, err := b.State.GetVertex(vtxID); err != nil || vtx.Status() != choices.Accepted {
+			return nil, fmt.Errorf("the last executed vertex %s isn't accepted", vtxID)
+		}
+	}
+	numExecuted, err = b.TxBlocked.NumExecuted()
+	if err != nil {
+		return nil, err
+	}
+	if numExecuted > 0 {
+		txID, err := b.TxBlocked.LastExecuted()
+		if err != nil {
+			return nil, err
+		}
+		if tx, err := b.VM.GetTx(txID); err != nil || tx.Status() != choices.Accepted {
+			return nil, fmt.Errorf("the last executed tx %s isn't accepted", txID)
+		}
+	}
+	return acceptedFrontier, nil
+}
+
 // ForceAccepted ...
 func (b *bootstrapper) ForceAccepted(acceptedContainerIDs ids.Set) {
-	for _, vtxID := range acceptedContainerIDs.List() {
-		b.fetch(vtxID)
+	if err := b.VtxBlocked.SetFrontier(acceptedContainerIDs); err != nil {
+		b.BootstrapConfig.Context.Log.Error("Failed to persist the accepted frontier due to %s", err)
 	}
 
-	if numPending := b.pending.Len(); numPending == 0 {
-		// TODO: This typically indicates bootstrapping has failed, so this
-		// should be handled appropriately
-		b.finish()
+	// Vertices that were requested before bootstrapping was interrupted still
+	// need to be fetched
+	missingIDs, err := b.VtxBlocked.MissingIDs()
+	if err != nil {
+		b.BootstrapConfig.Context.Log.Error("Failed to get the missing vertices due to %s", err)
 	}
+	toFetch := ids.Set{}
+	toFetch.Union(acceptedContainerIDs)
+	toFetch.Union(missingIDs)
+
+	for _, vtxID := range toFetch.List() {
+		// The ancestors of a vertex that was already queued were either queued
+		// or marked as missing when it was
+		if has, err := b.VtxBlocked.Has(vtxID); err == nil && has {
+			continue
+		}
+		b.fetch(vtxID)
+	}
+
+	// TODO: Having nothing to fetch typically indicates bootstrapping has
+	// failed, so this should be handled appropriately
+	b.checkpoint()
 }
 
-// Put ...
-func (b *bootstrapper) Put(vdr ids.ShortID, requestID uint32, vtxID ids.ID, vtxBytes []byte) {
-	b.BootstrapConfig.Context.Log.Verbo("Put called for vertexID %s", vtxID)
+// MultiPut handles the receipt of multiple vertices. Should be received in
+// response to a GetAncestors message to [vdr] with request ID [requestID].
+// The first vertex should be the requested vertex, followed by some of its
+// ancestors.
+func (b *bootstrapper) MultiPut(vdr ids.ShortID, requestID uint32, vtxs [][]byte) {
+	if lenVtxs := len(vtxs); lenVtxs > common.MaxContainersPerMultiPut {
+		b.BootstrapConfig.Context.Log.Debug("MultiPut(%s, %d) contains more than maximum number of vertices", vdr, requestID)
+		b.GetAncestorsFailed(vdr, requestID)
+		return
+	}
 
-	if !b.pending.Contains(vtxID) {
+	// Make sure this is in response to a request we made
+	wantedVtxID, ok := b.outstandingRequests.Remove(vdr, requestID)
+	if !ok {
+		b.BootstrapConfig.Context.Log.Debug("Received unexpected MultiPut from %s with request ID %d", vdr, requestID)
 		return
 	}
 
-	vtx, err := b.State.ParseVertex(vtxBytes)
-	if err != nil {
-		b.BootstrapConfig.Context.Log.Warn("ParseVertex failed due to %s for block:\n%s",
-			err,
-			formatting.DumpBytes{Bytes: vtxBytes})
-		b.GetFailed(vdr, requestID, vtxID)
+	if len(vtxs) == 0 {
+		b.BootstrapConfig.Context.Log.Debug("MultiPut(%s, %d) contains no vertices", vdr, requestID)
+		b.sendRequest(wantedVtxID)
 		return
 	}
 
-	b.addVertex(vtx)
+	// Parsing a vertex persists it, so only the requested vertex and the
+	// fetched ancestors of it are parsed. The headers of the fetched vertices
+	// are used to find the ancestors without persisting anything. Everything
+	// else in the response is discarded.
+	type header struct {
+		bytes     []byte
+		parentIDs []ids.ID
+	}
+	fetched := make(map[[32]byte]header, len(vtxs))
+	for _, vtxBytes := range vtxs {
+		vtxID, parentIDs, err := b.State.ParseVertexHeader(vtxBytes)
+		if err != nil {
+			b.BootstrapConfig.Context.Log.Debug("Failed to parse vertex from MultiPut(%s, %d) due to %s", vdr, requestID, err)
+			b.BootstrapConfig.Context.Log.Verbo("vertex: %s", formatting.DumpBytes{Bytes: vtxBytes})
+			continue
+		}
+		fetched[vtxID.Key()] = header{
+			bytes:     vtxBytes,
+			parentIDs: parentIDs,
+		}
+	}
+
+	var wantedVtx avalanche.Vertex
+	toParse := []ids.ID{wantedVtxID}
+	parsed := ids.Set{}
+	for len(toParse) > 0 {
+		newLen := len(toParse) - 1
+		vtxID := toParse[newLen]
+		toParse = toParse[:newLen]
+
+		vtxHeader, ok := fetched[vtxID.Key()]
+		if !ok || parsed.Contains(vtxID) {
+			continue
+		}
+		parsed.Add(vtxID)
+
+		vtx, err := b.State.ParseVertex(vtxHeader.bytes)
+		if err != nil {
+			b.BootstrapConfig.Context.Log.Debug("Failed to parse vertex from MultiPut(%s, %d) due to %s", vdr, requestID, err)
+			b.BootstrapConfig.Context.Log.Verbo("vertex: %s", formatting.DumpBytes{Bytes: vtxHeader.bytes})
+			continue
+		}
+		if vtxID.Equals(wantedVtxID) {
+			wantedVtx = vtx
+		}
+		toParse = append(toParse, vtxHeader.parentIDs...)
+	}
+
+	if wantedVtx == nil {
+		b.BootstrapConfig.Context.Log.Debug("MultiPut(%s, %d) didn't contain the requested vertex %s", vdr, requestID, wantedVtxID)
+		b.sendRequest(wantedVtxID)
+		return
+	}
+
+	b.process(wantedVtx)
+	b.checkpoint()
 }
 
-// GetFailed ...
-func (b *bootstrapper) GetFailed(_ ids.ShortID, _ uint32, vtxID ids.ID) { b.sendRequest(vtxID) }
+// GetAncestorsFailed is called when a GetAncestors message we sent fails
+func (b *bootstrapper) GetAncestorsFailed(vdr ids.ShortID, requestID uint32) {
+	vtxID, ok := b.outstandingRequests.Remove(vdr, requestID)
+	if !ok {
+		b.BootstrapConfig.Context.Log.Debug("GetAncestorsFailed(%s, %d) called but there was no outstanding request to this validator with this ID", vdr, requestID)
+		return
+	}
+	// Send another request for this
+	b.sendRequest(vtxID)
+}
 
 func (b *bootstrapper) fetch(vtxID ids.ID) {
-	if b.pending.Contains(vtxID) {
+	if b.outstandingRequests.Contains(vtxID) {
 		return
 	}
 
@@ -119,10 +291,15 @@ func (b *bootstrapper) fetch(vtxID ids.ID) {
 		b.sendRequest(vtxID)
 		return
 	}
-	b.addVertex(vtx)
+	b.process(vtx)
 }
 
+// sendRequest asks a validator for [vtxID] and its ancestors
 func (b *bootstrapper) sendRequest(vtxID ids.ID) {
+	if err := b.VtxBlocked.AddMissingID(vtxID); err != nil {
+		b.BootstrapConfig.Context.Log.Error("Failed to mark %s as missing due to %s", vtxID, err)
+	}
+
 	validators := b.BootstrapConfig.Validators.Sample(1)
 	if len(validators) == 0 {
 		b.BootstrapConfig.Context.Log.Error("Dropping request for %s as there are no validators", vtxID)
@@ -131,14 +308,17 @@ func (b *bootstrapper) sendRequest(vtxID ids.ID) {
 	validatorID := validators[0].ID()
 	b.RequestID++
 
-	b.pending.Add(vtxID)
-	b.BootstrapConfig.Sender.Get(validatorID, b.RequestID, vtxID)
+	b.outstandingRequests.Add(validatorID, b.RequestID, vtxID)
+	b.BootstrapConfig.Sender.GetAncestors(validatorID, b.RequestID, vtxID)
 
-	b.numPendingRequests.Set(float64(b.pending.Len()))
+	b.numPendingRequests.Set(float64(b.outstandingRequests.Len()))
 }
 
-func (b *bootstrapper) addVertex(vtx avalanche.Vertex) {
+// process [vtx] and its ancestors, until accepted vertices are reached. If an
+// ancestor isn't known, it's requested.
+func (b *bootstrapper) process(vtx avalanche.Vertex) {
 	vts := []avalanche.Vertex{vtx}
+	visited := ids.Set{}
 
 	for len(vts) > 0 {
 		newLen := len(vts) - 1
@@ -146,26 +326,36 @@ func (b *bootstrapper) addVertex(vtx avalanche.Vertex) {
 		vts = vts[:newLen]
 
 		vtxID := vtx.ID()
+		if visited.Contains(vtxID) {
+			continue
+		}
+		visited.Add(vtxID)
+
 		switch status := vtx.Status(); status {
 		case choices.Unknown:
-			b.sendRequest(vtxID)
+			if !b.outstandingRequests.Contains(vtxID) {
+				b.sendRequest(vtxID)
+			}
 		case choices.Processing:
-			b.pending.Remove(vtxID)
+			b.outstandingRequests.RemoveAny(vtxID)
+			if err := b.VtxBlocked.RemoveMissingID(vtxID); err != nil {
+				b.BootstrapConfig.Context.Log.Error("Failed to mark %s as fetched due to %s", vtxID, err)
+			}
 
 			if err := b.VtxBlocked.Push(&vertexJob{
 				numAccepted: b.numBootstrappedVtx,
 				numDropped:  b.numDroppedVtx,
 				vtx:         vtx,
-			}); err == nil {
-				b.numBlockedVtx.Inc()
+			}); err != nil && err != queue.ErrDuplicate {
+				b.BootstrapConfig.Context.Log.Error("Failed to queue vertex %s due to %s", vtxID, err)
 			}
 			for _, tx := range vtx.Txs() {
 				if err := b.TxBlocked.Push(&txJob{
 					numAccepted: b.numBootstrappedVtx,
 					numDropped:  b.numDroppedVtx,
 					tx:          tx,
-				}); err == nil {
-					b.numBlockedTx.Inc()
+				}); err != nil && err != queue.ErrDuplicate {
+					b.BootstrapConfig.Context.Log.Error("Failed to queue tx %s due to %s", tx.ID(), err)
 				}
 			}
 
@@ -178,32 +368,119 @@ func (b *bootstrapper) addVertex(vtx avalanche.Vertex) {
 			b.BootstrapConfig.Context.Log.Error("Bootstrapping wants to accept %s, however it was previously rejected", vtxID)
 		}
 	}
+}
+
+// checkpoint persists the progress of bootstrapping, so that it can be resumed
+// if it's interrupted. If there are no more vertices to fetch, bootstrapping
+// is finished.
+func (b *bootstrapper) checkpoint() {
+	b.commit()
+	b.updateProgress()
 
-	numPending := b.pending.Len()
+	numPending := b.outstandingRequests.Len()
 	b.numPendingRequests.Set(float64(numPending))
 	if numPending == 0 {
 		b.finish()
 	}
 }
 
+// commit the vertices and txs that were fetched and executed
+func (b *bootstrapper) commit() {
+	errs := wrappers.Errs{}
+	errs.Add(
+		b.VtxBlocked.Commit(),
+		b.TxBlocked.Commit(),
+	)
+	if errs.Errored() {
+		b.BootstrapConfig.Context.Log.Error("Failed to persist bootstrapping progress due to %s", errs.Err)
+	}
+}
+
+// updateProgress reports how many vertices and txs were fetched and executed
+func (b *bootstrapper) updateProgress() {
+	numFetchedVtx, err := b.VtxBlocked.NumJobs()
+	if err != nil {
+		b.BootstrapConfig.Context.Log.Error("Failed to get the number of fetched vertices due to %s", err)
+		return
+	}
+	numExecutedVtx, err := b.VtxBlocked.NumExecuted()
+	if err != nil {
+		b.BootstrapConfig.Context.Log.Error("Failed to get the number of executed vertices due to %s", err)
+		return
+	}
+	numFetchedTx, err := b.TxBlocked.NumJobs()
+	if err != nil {
+		b.BootstrapConfig.Context.Log.Error("Failed to get the number of fetched txs due to %s", err)
+		return
+	}
+	numExecutedTx, err := b.TxBlocked.NumExecuted()
+	if err != nil {
+		b.BootstrapConfig.Context.Log.Error("Failed to get the number of executed txs due to %s", err)
+		return
+	}
+
+	progress := b.BootstrapConfig.Progress
+	progress.Update(
+		uint64(numFetchedVtx)+uint64(numFetchedTx),
+		uint64(numExecutedVtx)+uint64(numExecutedTx),
+	)
+	status := progress.Status()
+
+	b.numFetchedVtx.Set(float64(numFetchedVtx))
+	b.numExecutedVtx.Set(float64(numExecutedVtx))
+	b.numBlockedVtx.Set(float64(numFetchedVtx - numExecutedVtx))
+	b.numFetchedTx.Set(float64(numFetchedTx))
+	b.numExecutedTx.Set(float64(numExecutedTx))
+	b.numBlockedTx.Set(float64(numFetchedTx - numExecutedTx))
+	if status.ETAKnown {
+		b.bootstrapETA.Set(status.ETA.Seconds())
+	} else {
+		b.bootstrapETA.Set(0)
+	}
+}
+
 func (b *bootstrapper) finish() {
 	if b.finished {
 		return
 	}
 
-	b.executeAll(b.TxBlocked, b.numBlockedTx)
-	b.executeAll(b.VtxBlocked, b.numBlockedVtx)
+	b.BootstrapConfig.Progress.StartExecuting()
+	b.executeAll(b.TxBlocked)
+	b.executeAll(b.VtxBlocked)
+
+	// Everything that was fetched was executed, so there's nothing to resume
+	errs := wrappers.Errs{}
+	errs.Add(
+		b.VtxBlocked.Clear(),
+		b.TxBlocked.Clear(),
+	)
+	if errs.Errored() {
+		b.BootstrapConfig.Context.Log.Error("Failed to clear bootstrapping progress due to %s", errs.Err)
+	}
+	if b.Bootstrapper.Resync() {
+		return
+	}
+	b.BootstrapConfig.Progress.Finish()
 
 	// Start consensus
 	b.onFinished()
 	b.finished = true
 }
 
-func (b *bootstrapper) executeAll(jobs *queue.Jobs, numBlocked prometheus.Gauge) {
+func (b *bootstrapper) executeAll(jobs *queue.Jobs) {
+	numExecuted := 0
 	for job, err := jobs.Pop(); err == nil; job, err = jobs.Pop() {
-		numBlocked.Dec()
 		if err := jobs.Execute(job); err != nil {
 			b.BootstrapConfig.Context.Log.Warn("Error executing: %s", err)
 		}
+
+		if numExecuted++; numExecuted%common.CheckpointFrequency == 0 {
+			b.commit()
+			b.updateProgress()
+
+			status := b.BootstrapConfig.Progress.Status()
+			b.BootstrapConfig.Context.Log.Info("Executed %d of %d vertices and txs. ETA: %s", status.Executed, status.Fetched, status.ETA)
+		}
 	}
+	b.updateProgress()
 }
@@ -58,7 +58,7 @@ func newConfig(t *testing.T) (BootstrapConfig, ids.ShortID, *common.SenderTest,
 	peerID := peer.ID()
 	peers.Add(peer)
 
-	handler.Initialize(engine, make(chan common.Message), 1)
+	handler.Initialize(engine, peers, make(chan common.Message), 1, 1, "", prometheus.NewRegistry())
 	timeouts.Initialize(0)
 	router.Initialize(ctx.Log, timeouts)
 
@@ -69,7 +69,7 @@ func newConfig(t *testing.T) (BootstrapConfig, ids.ShortID, *common.SenderTest,
 		Context:    ctx,
 		Validators: peers,
 		Beacons:    peers,
-		Alpha:      peers.Len()/2 + 1,
+		Alpha:      0.5,
 		Sender:     sender,
 	}
 	return BootstrapConfig{
@@ -133,7 +133,7 @@ func TestBootstrapperSingleFrontier(t *testing.T) {
 	}
 
 	vtxIDToReqID := map[[32]byte]uint32{}
-	sender.GetF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
 		if !vdr.Equals(peerID) {
 			t.Fatalf("Should have requested vertex from %s, requested from %s", peerID, vdr)
 		}
@@ -153,7 +153,7 @@ func TestBootstrapperSingleFrontier(t *testing.T) {
 	bs.ForceAccepted(acceptedIDs)
 
 	state.getVertex = nil
-	sender.GetF = nil
+	sender.GetAncestorsF = nil
 
 	if numReqs := len(vtxIDToReqID); numReqs != 3 {
 		t.Fatalf("Should have requested %d vertices, %d were requested", 3, numReqs)
@@ -202,11 +202,11 @@ func TestBootstrapperSingleFrontier(t *testing.T) {
 
 		switch {
 		case vtxID.Equals(vtxID0):
-			bs.Put(peerID, reqID, vtxID, vtxBytes0)
+			bs.MultiPut(peerID, reqID, [][]byte{vtxBytes0})
 		case vtxID.Equals(vtxID1):
-			bs.Put(peerID, reqID, vtxID, vtxBytes1)
+			bs.MultiPut(peerID, reqID, [][]byte{vtxBytes1})
 		case vtxID.Equals(vtxID2):
-			bs.Put(peerID, reqID, vtxID, vtxBytes2)
+			bs.MultiPut(peerID, reqID, [][]byte{vtxBytes2})
 		default:
 			t.Fatalf("Requested unknown vertex")
 		}
@@ -272,7 +272,7 @@ func TestBootstrapperUnknownByzantineResponse(t *testing.T) {
 	}
 
 	requestID := new(uint32)
-	sender.GetF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
 		if !vdr.Equals(peerID) {
 			t.Fatalf("Should have requested vertex from %s, requested from %s", peerID, vdr)
 		}
@@ -288,7 +288,6 @@ func TestBootstrapperUnknownByzantineResponse(t *testing.T) {
 	bs.ForceAccepted(acceptedIDs)
 
 	state.getVertex = nil
-	sender.GetF = nil
 
 	state.parseVertex = func(vtxBytes []byte) (avalanche.Vertex, error) {
 		switch {
@@ -316,8 +315,15 @@ func TestBootstrapperUnknownByzantineResponse(t *testing.T) {
 	finished := new(bool)
 	bs.onFinished = func() { *finished = true }
 
-	bs.Put(peerID, *requestID, vtxID1, vtxBytes1)
-	bs.Put(peerID, *requestID, vtxID0, vtxBytes0)
+	// A response without the requested vertex should cause it to be requested
+	// again
+	oldReqID := *requestID
+	bs.MultiPut(peerID, *requestID, [][]byte{vtxBytes1})
+	if oldReqID == *requestID {
+		t.Fatalf("Should have requested the vertex again")
+	}
+
+	bs.MultiPut(peerID, *requestID, [][]byte{vtxBytes0})
 
 	state.parseVertex = nil
 	state.edge = nil
@@ -334,6 +340,87 @@ func TestBootstrapperUnknownByzantineResponse(t *testing.T) {
 	}
 }
 
+func TestBootstrapperDiscardsUnrelatedVertices(t *testing.T) {
+	config, peerID, sender, state, _ := newConfig(t)
+
+	vtxID0 := ids.Empty.Prefix(0)
+	vtxID1 := ids.Empty.Prefix(1)
+
+	vtxBytes0 := []byte{0}
+	vtxBytes1 := []byte{1}
+
+	vtx0 := &Vtx{
+		id:     vtxID0,
+		height: 0,
+		status: choices.Processing,
+		bytes:  vtxBytes0,
+	}
+
+	bs := bootstrapper{}
+	bs.metrics.Initialize(config.Context.Log, fmt.Sprintf("gecko_%s", config.Context.ChainID), prometheus.NewRegistry())
+	bs.Initialize(config)
+
+	acceptedIDs := ids.Set{}
+	acceptedIDs.Add(vtxID0)
+
+	state.getVertex = func(vtxID ids.ID) (avalanche.Vertex, error) {
+		if vtxID.Equals(vtxID0) {
+			return nil, errUnknownVertex
+		}
+		t.Fatal(errUnknownVertex)
+		panic(errUnknownVertex)
+	}
+
+	requestID := new(uint32)
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
+		if !vtxID.Equals(vtxID0) {
+			t.Fatalf("Requested unknown vertex")
+		}
+		*requestID = reqID
+	}
+
+	bs.ForceAccepted(acceptedIDs)
+
+	state.parseVertexHeader = func(vtxBytes []byte) (ids.ID, []ids.ID, error) {
+		switch {
+		case bytes.Equal(vtxBytes, vtxBytes0):
+			return vtxID0, nil, nil
+		case bytes.Equal(vtxBytes, vtxBytes1):
+			return vtxID1, nil, nil
+		}
+		t.Fatal(errParsedUnknownVertex)
+		return ids.ID{}, nil, errParsedUnknownVertex
+	}
+	// The second vertex isn't an ancestor of the requested vertex, so it must
+	// not be parsed, as parsing it would persist it
+	state.parseVertex = func(vtxBytes []byte) (avalanche.Vertex, error) {
+		if bytes.Equal(vtxBytes, vtxBytes0) {
+			return vtx0, nil
+		}
+		t.Fatal(errParsedUnknownVertex)
+		return nil, errParsedUnknownVertex
+	}
+	state.getVertex = func(vtxID ids.ID) (avalanche.Vertex, error) {
+		if vtxID.Equals(vtxID0) {
+			return vtx0, nil
+		}
+		t.Fatal(errUnknownVertex)
+		panic(errUnknownVertex)
+	}
+
+	finished := new(bool)
+	bs.onFinished = func() { *finished = true }
+
+	bs.MultiPut(peerID, *requestID, [][]byte{vtxBytes0, vtxBytes1})
+
+	if !*finished {
+		t.Fatalf("Bootstrapping should have finished")
+	}
+	if vtx0.Status() != choices.Accepted {
+		t.Fatalf("Vertex should be accepted")
+	}
+}
+
 func TestBootstrapperVertexDependencies(t *testing.T) {
 	config, peerID, sender, state, _ := newConfig(t)
 
@@ -377,7 +464,7 @@ func TestBootstrapperVertexDependencies(t *testing.T) {
 	}
 
 	reqIDPtr := new(uint32)
-	sender.GetF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
 		if !vdr.Equals(peerID) {
 			t.Fatalf("Should have requested vertex from %s, requested from %s", peerID, vdr)
 		}
@@ -393,7 +480,7 @@ func TestBootstrapperVertexDependencies(t *testing.T) {
 	bs.ForceAccepted(acceptedIDs)
 
 	state.getVertex = nil
-	sender.GetF = nil
+	sender.GetAncestorsF = nil
 
 	state.parseVertex = func(vtxBytes []byte) (avalanche.Vertex, error) {
 		switch {
@@ -403,7 +490,7 @@ func TestBootstrapperVertexDependencies(t *testing.T) {
 		t.Fatal(errParsedUnknownVertex)
 		return nil, errParsedUnknownVertex
 	}
-	sender.GetF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
 		if !vdr.Equals(peerID) {
 			t.Fatalf("Should have requested vertex from %s, requested from %s", peerID, vdr)
 		}
@@ -416,10 +503,10 @@ func TestBootstrapperVertexDependencies(t *testing.T) {
 		*reqIDPtr = reqID
 	}
 
-	bs.Put(peerID, *reqIDPtr, vtxID1, vtxBytes1)
+	bs.MultiPut(peerID, *reqIDPtr, [][]byte{vtxBytes1})
 
 	state.parseVertex = nil
-	sender.GetF = nil
+	sender.GetAncestorsF = nil
 
 	if vtx0.Status() != choices.Unknown {
 		t.Fatalf("Vertex should be unknown")
@@ -463,7 +550,7 @@ func TestBootstrapperVertexDependencies(t *testing.T) {
 	finished := new(bool)
 	bs.onFinished = func() { *finished = true }
 
-	bs.Put(peerID, *reqIDPtr, vtxID0, vtxBytes0)
+	bs.MultiPut(peerID, *reqIDPtr, [][]byte{vtxBytes0})
 
 	state.parseVertex = nil
 	bs.onFinished = nil
@@ -551,7 +638,7 @@ func TestBootstrapperTxDependencies(t *testing.T) {
 	}
 
 	reqIDPtr := new(uint32)
-	sender.GetF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
 		if !vdr.Equals(peerID) {
 			t.Fatalf("Should have requested vertex from %s, requested from %s", peerID, vdr)
 		}
@@ -567,7 +654,7 @@ func TestBootstrapperTxDependencies(t *testing.T) {
 	bs.ForceAccepted(acceptedIDs)
 
 	state.getVertex = nil
-	sender.GetF = nil
+	sender.GetAncestorsF = nil
 
 	state.parseVertex = func(vtxBytes []byte) (avalanche.Vertex, error) {
 		switch {
@@ -577,7 +664,7 @@ func TestBootstrapperTxDependencies(t *testing.T) {
 		t.Fatal(errParsedUnknownVertex)
 		return nil, errParsedUnknownVertex
 	}
-	sender.GetF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
 		if !vdr.Equals(peerID) {
 			t.Fatalf("Should have requested vertex from %s, requested from %s", peerID, vdr)
 		}
@@ -590,10 +677,10 @@ func TestBootstrapperTxDependencies(t *testing.T) {
 		*reqIDPtr = reqID
 	}
 
-	bs.Put(peerID, *reqIDPtr, vtxID1, vtxBytes1)
+	bs.MultiPut(peerID, *reqIDPtr, [][]byte{vtxBytes1})
 
 	state.parseVertex = nil
-	sender.GetF = nil
+	sender.GetAncestorsF = nil
 
 	if tx0.Status() != choices.Processing {
 		t.Fatalf("Tx should be processing")
@@ -655,7 +742,7 @@ func TestBootstrapperTxDependencies(t *testing.T) {
 	finished := new(bool)
 	bs.onFinished = func() { *finished = true }
 
-	bs.Put(peerID, *reqIDPtr, vtxID0, vtxBytes0)
+	bs.MultiPut(peerID, *reqIDPtr, [][]byte{vtxBytes0})
 
 	state.parseVertex = nil
 	bs.onFinished = nil
@@ -747,7 +834,7 @@ func TestBootstrapperMissingTxDependency(t *testing.T) {
 	}
 
 	reqIDPtr := new(uint32)
-	sender.GetF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
 		if !vdr.Equals(peerID) {
 			t.Fatalf("Should have requested vertex from %s, requested from %s", peerID, vdr)
 		}
@@ -763,7 +850,7 @@ func TestBootstrapperMissingTxDependency(t *testing.T) {
 	bs.ForceAccepted(acceptedIDs)
 
 	state.getVertex = nil
-	sender.GetF = nil
+	sender.GetAncestorsF = nil
 
 	state.parseVertex = func(vtxBytes []byte) (avalanche.Vertex, error) {
 		switch {
@@ -773,7 +860,7 @@ func TestBootstrapperMissingTxDependency(t *testing.T) {
 		t.Fatal(errParsedUnknownVertex)
 		return nil, errParsedUnknownVertex
 	}
-	sender.GetF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
 		if !vdr.Equals(peerID) {
 			t.Fatalf("Should have requested vertex from %s, requested from %s", peerID, vdr)
 		}
@@ -786,10 +873,10 @@ func TestBootstrapperMissingTxDependency(t *testing.T) {
 		*reqIDPtr = reqID
 	}
 
-	bs.Put(peerID, *reqIDPtr, vtxID1, vtxBytes1)
+	bs.MultiPut(peerID, *reqIDPtr, [][]byte{vtxBytes1})
 
 	state.parseVertex = nil
-	sender.GetF = nil
+	sender.GetAncestorsF = nil
 
 	if tx0.Status() != choices.Unknown {
 		t.Fatalf("Tx should be unknown")
@@ -847,7 +934,7 @@ func TestBootstrapperMissingTxDependency(t *testing.T) {
 	finished := new(bool)
 	bs.onFinished = func() { *finished = true }
 
-	bs.Put(peerID, *reqIDPtr, vtxID0, vtxBytes0)
+	bs.MultiPut(peerID, *reqIDPtr, [][]byte{vtxBytes0})
 
 	state.parseVertex = nil
 	bs.onFinished = nil
@@ -870,6 +957,144 @@ func TestBootstrapperMissingTxDependency(t *testing.T) {
 	}
 }
 
+func TestBootstrapperResume(t *testing.T) {
+	config, peerID, sender, state, _ := newConfig(t)
+
+	db := memdb.New()
+	config.VtxBlocked, _ = queue.New(prefixdb.New([]byte("vtx"), db))
+	config.TxBlocked, _ = queue.New(prefixdb.New([]byte("tx"), db))
+
+	vtxID0 := ids.Empty.Prefix(0)
+	vtxID1 := ids.Empty.Prefix(1)
+
+	vtxBytes0 := []byte{0}
+	vtxBytes1 := []byte{1}
+
+	vtx0 := &Vtx{
+		id:     vtxID0,
+		height: 0,
+		status: choices.Unknown,
+		bytes:  vtxBytes0,
+	}
+	vtx1 := &Vtx{
+		parents: []avalanche.Vertex{vtx0},
+		id:      vtxID1,
+		height:  1,
+		status:  choices.Processing,
+		bytes:   vtxBytes1,
+	}
+
+	state.getVertex = func(vtxID ids.ID) (avalanche.Vertex, error) {
+		switch {
+		case vtxID.Equals(vtxID0), vtxID.Equals(vtxID1):
+			return nil, errUnknownVertex
+		default:
+			t.Fatal(errUnknownVertex)
+			panic(errUnknownVertex)
+		}
+	}
+	state.parseVertex = func(vtxBytes []byte) (avalanche.Vertex, error) {
+		switch {
+		case bytes.Equal(vtxBytes, vtxBytes0):
+			vtx0.status = choices.Processing
+			return vtx0, nil
+		case bytes.Equal(vtxBytes, vtxBytes1):
+			return vtx1, nil
+		}
+		t.Fatal(errParsedUnknownVertex)
+		return nil, errParsedUnknownVertex
+	}
+
+	requested := ids.ID{}
+	reqIDPtr := new(uint32)
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
+		requested = vtxID
+		*reqIDPtr = reqID
+	}
+
+	bs := bootstrapper{}
+	bs.metrics.Initialize(config.Context.Log, fmt.Sprintf("gecko_%s", config.Context.ChainID), prometheus.NewRegistry())
+	bs.Initialize(config)
+
+	acceptedIDs := ids.Set{}
+	acceptedIDs.Add(vtxID1)
+
+	bs.ForceAccepted(acceptedIDs)
+	bs.MultiPut(peerID, *reqIDPtr, [][]byte{vtxBytes1})
+
+	if !requested.Equals(vtxID0) {
+		t.Fatalf("Should have requested vertex %s", vtxID0)
+	}
+
+	// Bootstrapping is interrupted by the node restarting
+	config.VtxBlocked, _ = queue.New(prefixdb.New([]byte("vtx"), db))
+	config.TxBlocked, _ = queue.New(prefixdb.New([]byte("tx"), db))
+	requested = ids.ID{}
+
+	bs = bootstrapper{}
+	bs.metrics.Initialize(config.Context.Log, fmt.Sprintf("gecko_%s", config.Context.ChainID), prometheus.NewRegistry())
+	bs.Initialize(config)
+
+	finished := new(bool)
+	bs.onFinished = func() { *finished = true }
+
+	sender.GetAcceptedFrontierF = func(ids.ShortSet, uint32) {
+		t.Fatalf("Should have resumed from the persisted accepted frontier")
+	}
+
+	bs.Startup()
+
+	// Only the vertex that was missing should be requested again
+	if !requested.Equals(vtxID0) {
+		t.Fatalf("Should have requested vertex %s", vtxID0)
+	}
+
+	frontierRequested := new(bool)
+	sender.GetAcceptedFrontierF = func(_ ids.ShortSet, reqID uint32) {
+		*frontierRequested = true
+		*reqIDPtr = reqID
+	}
+
+	bs.MultiPut(peerID, *reqIDPtr, [][]byte{vtxBytes0})
+
+	// The network may have accepted more vertices since the resumed accepted
+	// frontier was agreed on, so the current one must be bootstrapped too
+	if *finished {
+		t.Fatalf("Bootstrapping shouldn't have finished before requesting the current accepted frontier")
+	}
+	if !*frontierRequested {
+		t.Fatalf("Should have requested the current accepted frontier")
+	}
+	if status := bs.BootstrapConfig.Progress.Status(); status.Bootstrapped || status.Fetched != 2 || status.Executed != 2 {
+		t.Fatalf("Wrong progress. Expected: not bootstrapped, 2 fetched, 2 executed ; Returned: %d fetched, %d executed", status.Fetched, status.Executed)
+	}
+
+	sender.GetAcceptedF = func(_ ids.ShortSet, reqID uint32, _ ids.Set) { *reqIDPtr = reqID }
+	state.getVertex = func(vtxID ids.ID) (avalanche.Vertex, error) {
+		if vtxID.Equals(vtxID1) {
+			return vtx1, nil
+		}
+		t.Fatal(errUnknownVertex)
+		panic(errUnknownVertex)
+	}
+
+	bs.AcceptedFrontier(peerID, *reqIDPtr, acceptedIDs)
+	bs.Accepted(peerID, *reqIDPtr, acceptedIDs)
+
+	if !*finished {
+		t.Fatalf("Bootstrapping should have finished")
+	}
+	if vtx0.Status() != choices.Accepted {
+		t.Fatalf("Vertex should be accepted")
+	}
+	if vtx1.Status() != choices.Accepted {
+		t.Fatalf("Vertex should be accepted")
+	}
+	if status := bs.BootstrapConfig.Progress.Status(); !status.Bootstrapped {
+		t.Fatalf("Progress should report that bootstrapping finished")
+	}
+}
+
 func TestBootstrapperAcceptedFrontier(t *testing.T) {
 	config, _, _, state, _ := newConfig(t)
 
@@ -14,6 +14,9 @@ type metrics struct {
 	numBootstrappedVtx, numDroppedVtx,
 	numBootstrappedTx, numDroppedTx prometheus.Counter
 
+	numFetchedVtx, numExecutedVtx,
+	numFetchedTx, numExecutedTx, bootstrapETA prometheus.Gauge
+
 	numPolls, numVtxRequests, numTxRequests, numPendingVtx prometheus.Gauge
 }
 
@@ -61,6 +64,36 @@ func (m *metrics) Initialize(log logging.Logger, namespace string, registerer pr
 			Name:      "av_bs_dropped_txs",
 			Help:      "Number of dropped txs",
 		})
+	m.numFetchedVtx = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "av_bs_fetched_vts",
+			Help:      "Number of vertices fetched while bootstrapping",
+		})
+	m.numExecutedVtx = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "av_bs_executed_vts",
+			Help:      "Number of fetched vertices executed while bootstrapping",
+		})
+	m.numFetchedTx = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "av_bs_fetched_txs",
+			Help:      "Number of txs fetched while bootstrapping",
+		})
+	m.numExecutedTx = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "av_bs_executed_txs",
+			Help:      "Number of fetched txs executed while bootstrapping",
+		})
+	m.bootstrapETA = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "av_bs_eta",
+			Help:      "Estimated number of seconds until the fetched vertices and txs are executed, or 0 if unknown",
+		})
 	m.numPolls = prometheus.NewGauge(
 		prometheus.GaugeOpts{
 			Namespace: namespace,
@@ -107,6 +140,21 @@ func (m *metrics) Initialize(log logging.Logger, namespace string, registerer pr
 	if err := registerer.Register(m.numDroppedTx); err != nil {
 		log.Error("Failed to register av_bs_dropped_txs statistics due to %s", err)
 	}
+	if err := registerer.Register(m.numFetchedVtx); err != nil {
+		log.Error("Failed to register av_bs_fetched_vts statistics due to %s", err)
+	}
+	if err := registerer.Register(m.numExecutedVtx); err != nil {
+		log.Error("Failed to register av_bs_executed_vts statistics due to %s", err)
+	}
+	if err := registerer.Register(m.numFetchedTx); err != nil {
+		log.Error("Failed to register av_bs_fetched_txs statistics due to %s", err)
+	}
+	if err := registerer.Register(m.numExecutedTx); err != nil {
+		log.Error("Failed to register av_bs_executed_txs statistics due to %s", err)
+	}
+	if err := registerer.Register(m.bootstrapETA); err != nil {
+		log.Error("Failed to register av_bs_eta statistics due to %s", err)
+	}
 	if err := registerer.Register(m.numPolls); err != nil {
 		log.Error("Failed to register av_polls statistics due to %s", err)
 	}
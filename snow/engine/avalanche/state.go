@@ -17,6 +17,11 @@ type State interface {
 	// Attempt to convert a stream of bytes into a vertex
 	ParseVertex(vertex []byte) (avalanche.Vertex, error)
 
+	// ParseVertexHeader returns the ID of a vertex and the IDs of its parents
+	// from the contents of the vertex. Unlike ParseVertex, nothing is parsed
+	// into, or persisted by, the VM.
+	ParseVertexHeader(vertex []byte) (vtxID ids.ID, parentIDs []ids.ID, err error)
+
 	// GetVertex attempts to load a vertex by hash from storage
 	GetVertex(vtxID ids.ID) (avalanche.Vertex, error)
 
@@ -17,6 +17,7 @@ import (
 	"github.com/ava-labs/gecko/snow/consensus/snowstorm"
 	"github.com/ava-labs/gecko/utils/hashing"
 	"github.com/ava-labs/gecko/utils/math"
+	"github.com/ava-labs/gecko/utils/wrappers"
 
 	avacon "github.com/ava-labs/gecko/snow/consensus/avalanche"
 	avaeng "github.com/ava-labs/gecko/snow/engine/avalanche"
@@ -80,6 +81,18 @@ func (s *Serializer) ParseVertex(b []byte) (avacon.Vertex, error) {
 	return uVtx, nil
 }
 
+// ParseVertexHeader implements the avalanche.State interface
+func (s *Serializer) ParseVertexHeader(b []byte) (ids.ID, []ids.ID, error) {
+	p := wrappers.Packer{Bytes: b}
+	chainID, _, parentIDs := unmarshalHeader(&p)
+	if p.Errored() {
+		return ids.ID{}, nil, p.Err
+	} else if !chainID.Equals(s.ctx.ChainID) {
+		return ids.ID{}, nil, errWrongChainID
+	}
+	return ids.NewID(hashing.ComputeHash256Array(b)), parentIDs, nil
+}
+
 // BuildVertex implements the avalanche.State interface
 func (s *Serializer) BuildVertex(parentSet ids.Set, txs []snowstorm.Tx) (avacon.Vertex, error) {
 	parentIDs := parentSet.List()
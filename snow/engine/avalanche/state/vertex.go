@@ -91,18 +91,7 @@ func (vtx *vertex) Marshal() ([]byte, error) {
 func (vtx *vertex) Unmarshal(b []byte, vm avalanche.DAGVM) error {
 	p := wrappers.Packer{Bytes: b}
 
-	if codecID := ID(p.UnpackInt()); codecID != CustomID {
-		p.Add(errBadCodec)
-	}
-
-	chainID, _ := ids.ToID(p.UnpackFixedBytes(hashing.HashLen))
-	height := p.UnpackLong()
-
-	parentIDs := []ids.ID(nil)
-	for i := p.UnpackInt(); i > 0 && !p.Errored(); i-- {
-		parentID, _ := ids.ToID(p.UnpackFixedBytes(hashing.HashLen))
-		parentIDs = append(parentIDs, parentID)
-	}
+	chainID, height, parentIDs := unmarshalHeader(&p)
 
 	txs := []snowstorm.Tx(nil)
 	for i := p.UnpackInt(); i > 0 && !p.Errored(); i-- {
@@ -130,6 +119,24 @@ func (vtx *vertex) Unmarshal(b []byte, vm avalanche.DAGVM) error {
 	return nil
 }
 
+// unmarshalHeader unpacks the fields of a vertex that precede its transactions.
+// The transactions are left in the packer.
+func unmarshalHeader(p *wrappers.Packer) (ids.ID, uint64, []ids.ID) {
+	if codecID := ID(p.UnpackInt()); codecID != CustomID {
+		p.Add(errBadCodec)
+	}
+
+	chainID, _ := ids.ToID(p.UnpackFixedBytes(hashing.HashLen))
+	height := p.UnpackLong()
+
+	parentIDs := []ids.ID(nil)
+	for i := p.UnpackInt(); i > 0 && !p.Errored(); i-- {
+		parentID, _ := ids.ToID(p.UnpackFixedBytes(hashing.HashLen))
+		parentIDs = append(parentIDs, parentID)
+	}
+	return chainID, height, parentIDs
+}
+
 type sortTxsData []snowstorm.Tx
 
 func (txs sortTxsData) Less(i, j int) bool {
@@ -23,9 +23,10 @@ type stateTest struct {
 
 	cantParseVertex, cantBuildVertex, cantGetVertex, cantEdge, cantSaveEdge bool
 
-	parseVertex func([]byte) (avalanche.Vertex, error)
-	buildVertex func(ids.Set, []snowstorm.Tx) (avalanche.Vertex, error)
-	getVertex   func(ids.ID) (avalanche.Vertex, error)
+	parseVertex       func([]byte) (avalanche.Vertex, error)
+	parseVertexHeader func([]byte) (ids.ID, []ids.ID, error)
+	buildVertex       func(ids.Set, []snowstorm.Tx) (avalanche.Vertex, error)
+	getVertex         func(ids.ID) (avalanche.Vertex, error)
 
 	edge     func() []ids.ID
 	saveEdge func([]ids.ID)
@@ -48,6 +49,29 @@ func (s *stateTest) ParseVertex(b []byte) (avalanche.Vertex, error) {
 	return nil, errParseVertex
 }
 
+// ParseVertexHeader falls back to parseVertex when parseVertexHeader isn't set,
+// as the test vertices aren't identified by the hash of their bytes
+func (s *stateTest) ParseVertexHeader(b []byte) (ids.ID, []ids.ID, error) {
+	if s.parseVertexHeader != nil {
+		return s.parseVertexHeader(b)
+	}
+	if s.parseVertex != nil {
+		vtx, err := s.parseVertex(b)
+		if err != nil {
+			return ids.ID{}, nil, err
+		}
+		parentIDs := []ids.ID(nil)
+		for _, parent := range vtx.Parents() {
+			parentIDs = append(parentIDs, parent.ID())
+		}
+		return vtx.ID(), parentIDs, nil
+	}
+	if s.cantParseVertex && s.t != nil {
+		s.t.Fatal(errParseVertex)
+	}
+	return ids.ID{}, nil, errParseVertex
+}
+
 func (s *stateTest) BuildVertex(set ids.Set, txs []snowstorm.Tx) (avalanche.Vertex, error) {
 	if s.buildVertex != nil {
 		return s.buildVertex(set, txs)
@@ -6,12 +6,14 @@ package avalanche
 import (
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/snow/consensus/avalanche"
 	"github.com/ava-labs/gecko/snow/consensus/snowstorm"
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/snow/events"
 	"github.com/ava-labs/gecko/utils/formatting"
 	"github.com/ava-labs/gecko/utils/random"
+	"github.com/ava-labs/gecko/utils/wrappers"
 )
 
 // Transitive implements the Engine interface by attempting to fetch all
@@ -71,6 +73,60 @@ func (t *Transitive) Shutdown() {
 // Context implements the Engine interface
 func (t *Transitive) Context() *snow.Context { return t.Config.Context }
 
+// GetAncestors implements the Engine interface
+func (t *Transitive) GetAncestors(vdr ids.ShortID, requestID uint32, vtxID ids.ID) {
+	vtx, err := t.Config.State.GetVertex(vtxID)
+	if err != nil { // Don't have the vertex. Drop this request.
+		t.Config.Context.Log.Verbo("couldn't get vertex %s. dropping GetAncestors(%s, %d, %s)", vtxID, vdr, requestID, vtxID)
+		return
+	}
+
+	// The vertex, followed by its ancestors in breadth-first order
+	ancestorsBytes := [][]byte{vtx.Bytes()}
+	ancestorsBytesLen := len(vtx.Bytes()) + wrappers.IntLen
+	queue := vtx.Parents()
+	visited := ids.Set{}
+	visited.Add(vtxID)
+	for len(queue) > 0 && len(ancestorsBytes) < common.MaxContainersPerMultiPut {
+		vtx, queue = queue[0], queue[1:]
+		vtxID := vtx.ID()
+		if visited.Contains(vtxID) || vtx.Status() == choices.Unknown {
+			continue
+		}
+		visited.Add(vtxID)
+
+		vtxBytes := vtx.Bytes()
+		// Include the length prefix of the vertex
+		newLen := ancestorsBytesLen + len(vtxBytes) + wrappers.IntLen
+		if newLen > common.MaxContainersLen {
+			break
+		}
+		ancestorsBytes = append(ancestorsBytes, vtxBytes)
+		ancestorsBytesLen = newLen
+		queue = append(queue, vtx.Parents()...)
+	}
+
+	t.Config.Sender.MultiPut(vdr, requestID, ancestorsBytes)
+}
+
+// MultiPut implements the Engine interface
+func (t *Transitive) MultiPut(vdr ids.ShortID, requestID uint32, vtxs [][]byte) {
+	if t.bootstrapped {
+		t.Config.Context.Log.Debug("Dropping MultiPut(%s, %d) as bootstrapping has finished", vdr, requestID)
+		return
+	}
+	t.bootstrapper.MultiPut(vdr, requestID, vtxs)
+}
+
+// GetAncestorsFailed implements the Engine interface
+func (t *Transitive) GetAncestorsFailed(vdr ids.ShortID, requestID uint32) {
+	if t.bootstrapped {
+		t.Config.Context.Log.Debug("Dropping GetAncestorsFailed(%s, %d) as bootstrapping has finished", vdr, requestID)
+		return
+	}
+	t.bootstrapper.GetAncestorsFailed(vdr, requestID)
+}
+
 // Get implements the Engine interface
 func (t *Transitive) Get(vdr ids.ShortID, requestID uint32, vtxID ids.ID) {
 	// If this engine has access to the requested vertex, provide it
@@ -84,7 +140,12 @@ func (t *Transitive) Put(vdr ids.ShortID, requestID uint32, vtxID ids.ID, vtxByt
 	t.Config.Context.Log.Verbo("Put called for vertexID %s", vtxID)
 
 	if !t.bootstrapped {
-		t.bootstrapper.Put(vdr, requestID, vtxID, vtxBytes)
+		// Request IDs aren't tied to message types, so this Put may be the
+		// response to an outstanding GetAncestors request. Its timeout was
+		// cancelled when this Put arrived, so the request must be failed here
+		// or bootstrapping would wait on it forever.
+		t.Config.Context.Log.Debug("Dropping Put for %s due to bootstrapping", vtxID)
+		t.bootstrapper.GetAncestorsFailed(vdr, requestID)
 		return
 	}
 
@@ -102,7 +163,10 @@ func (t *Transitive) Put(vdr ids.ShortID, requestID uint32, vtxID ids.ID, vtxByt
 // GetFailed implements the Engine interface
 func (t *Transitive) GetFailed(vdr ids.ShortID, requestID uint32, vtxID ids.ID) {
 	if !t.bootstrapped {
-		t.bootstrapper.GetFailed(vdr, requestID, vtxID)
+		// As with Put, this may correspond to an outstanding GetAncestors
+		// request
+		t.Config.Context.Log.Debug("Dropping GetFailed for %s due to bootstrapping", vtxID)
+		t.bootstrapper.GetAncestorsFailed(vdr, requestID)
 		return
 	}
 
@@ -2255,7 +2255,7 @@ func TestEngineBootstrappingIntoConsensus(t *testing.T) {
 		panic("Unknown vertex requested")
 	}
 
-	sender.GetF = func(inVdr ids.ShortID, reqID uint32, vtxID ids.ID) {
+	sender.GetAncestorsF = func(inVdr ids.ShortID, reqID uint32, vtxID ids.ID) {
 		if !vdrID.Equals(inVdr) {
 			t.Fatalf("Asking wrong validator for vertex")
 		}
@@ -2268,7 +2268,7 @@ func TestEngineBootstrappingIntoConsensus(t *testing.T) {
 	te.Accepted(vdrID, *requestID, acceptedFrontier)
 
 	st.getVertex = nil
-	sender.GetF = nil
+	sender.GetAncestorsF = nil
 
 	vm.ParseTxF = func(b []byte) (snowstorm.Tx, error) {
 		switch {
@@ -2298,7 +2298,7 @@ func TestEngineBootstrappingIntoConsensus(t *testing.T) {
 		panic("Unknown bytes provided")
 	}
 
-	te.Put(vdrID, *requestID, vtxID0, vtxBytes0)
+	te.MultiPut(vdrID, *requestID, [][]byte{vtxBytes0})
 
 	vm.ParseTxF = nil
 	st.parseVertex = nil
@@ -2363,3 +2363,80 @@ func TestEngineBootstrappingIntoConsensus(t *testing.T) {
 	sender.PushQueryF = nil
 	st.getVertex = nil
 }
+
+func TestEngineBootstrappingPutFailsAncestorsRequest(t *testing.T) {
+	config := DefaultConfig()
+
+	vdr := validators.GenerateRandomValidator(1)
+	vdrID := vdr.ID()
+
+	vals := validators.NewSet()
+	config.Validators = vals
+	config.Beacons = vals
+
+	vals.Add(vdr)
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+
+	sender.Default(true)
+
+	st := &stateTest{t: t}
+	config.State = st
+
+	st.Default(true)
+
+	vm := &VMTest{}
+	vm.T = t
+	config.VM = vm
+
+	vm.Default(true)
+
+	vtxID := GenerateID()
+
+	requestID := new(uint32)
+	sender.GetAcceptedFrontierF = func(_ ids.ShortSet, reqID uint32) { *requestID = reqID }
+
+	te := &Transitive{}
+	te.Initialize(config)
+	te.Startup()
+
+	acceptedFrontier := ids.Set{}
+	acceptedFrontier.Add(vtxID)
+
+	sender.GetAcceptedF = func(_ ids.ShortSet, reqID uint32, _ ids.Set) { *requestID = reqID }
+
+	te.AcceptedFrontier(vdrID, *requestID, acceptedFrontier)
+
+	st.getVertex = func(ids.ID) (avalanche.Vertex, error) { return nil, errMissing }
+
+	requested := new(int)
+	sender.GetAncestorsF = func(inVdr ids.ShortID, reqID uint32, inVtxID ids.ID) {
+		if !vtxID.Equals(inVtxID) {
+			t.Fatalf("Asking for wrong vertex")
+		}
+		*requested++
+		*requestID = reqID
+	}
+
+	te.Accepted(vdrID, *requestID, acceptedFrontier)
+
+	if *requested != 1 {
+		t.Fatalf("Should have requested the vertex's ancestors")
+	}
+
+	// A Put with the request ID of the GetAncestors request cancels the
+	// request's timeout, so the request must be sent again
+	te.Put(vdrID, *requestID, vtxID, []byte{0})
+
+	if *requested != 2 {
+		t.Fatalf("Should have requested the vertex's ancestors again after Put")
+	}
+
+	te.GetFailed(vdrID, *requestID, vtxID)
+
+	if *requested != 3 {
+		t.Fatalf("Should have requested the vertex's ancestors again after GetFailed")
+	}
+}
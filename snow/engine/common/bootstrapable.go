@@ -18,4 +18,10 @@ type Bootstrapable interface {
 
 	// Force the provided containers to be accepted.
 	ForceAccepted(acceptedContainerIDs ids.Set)
+
+	// Returns the accepted frontier that bootstrapping was fetching when it
+	// was interrupted, and true, if the progress that was persisted is valid
+	// and bootstrapping should be resumed from it. Invalid progress is
+	// discarded.
+	ResumableFrontier() (acceptedContainerIDs ids.Set, resume bool)
 }
@@ -5,17 +5,64 @@ package common
 
 import (
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/validators"
+)
+
+const (
+	// MaxContainersPerMultiPut is the maximum number of containers that can be
+	// sent in a MultiPut message
+	MaxContainersPerMultiPut = 2000
+
+	// MaxContainersLen is the maximum number of bytes of containers that can be
+	// sent in a MultiPut message. This leaves room, within the network's
+	// maximum message size, for the message's header and the length prefix of
+	// each container.
+	MaxContainersLen = 1 << 20
+
+	// CheckpointFrequency is the number of containers that are executed while
+	// bootstrapping between persisting how many containers were executed
+	CheckpointFrequency = 2500
+
+	// DefaultBootstrapRetries is the default number of times the accepted
+	// frontier is sampled again when too many validators failed to respond to
+	// agree on it
+	DefaultBootstrapRetries = 5
 )
 
 // Bootstrapper implements the Engine interface.
+//
+// The accepted frontier is requested from a sample of the beacons, weighted by
+// stake. Every beacon is then asked which of the containers in the frontier it
+// accepted, and the containers that at least [Alpha] of the beacons' stake
+// accepted are bootstrapped.
 type Bootstrapper struct {
 	Config
 
+	// The beacons that were asked for their accepted frontier in this attempt,
+	// and the ones that didn't respond yet
+	sampledAcceptedFrontier ids.ShortSet
 	pendingAcceptedFrontier ids.ShortSet
 	acceptedFrontier        ids.Set
 
 	pendingAccepted ids.ShortSet
-	accepted        ids.Bag
+
+	// Key: The ID of a beacon
+	// Value: The beacon's stake
+	weights map[[20]byte]uint64
+
+	// The total stake of the beacons, and how much of it responded
+	totalWeight, respondedWeight uint64
+
+	// Key: The ID of a container in the accepted frontier
+	// Value: The stake of the beacons that accepted the container
+	acceptedWeight map[[32]byte]uint64
+
+	// The number of times the accepted frontier was sampled again
+	retries int
+
+	// True if the accepted frontier being bootstrapped was persisted before
+	// this node restarted, rather than agreed on since
+	resumed bool
 
 	RequestID uint32
 }
@@ -23,20 +70,63 @@ type Bootstrapper struct {
 // Initialize implements the Engine interface.
 func (b *Bootstrapper) Initialize(config Config) {
 	b.Config = config
+}
 
-	for _, vdr := range b.Beacons.List() {
-		vdrID := vdr.ID()
-		b.pendingAcceptedFrontier.Add(vdrID)
-		b.pendingAccepted.Add(vdrID)
+// Startup implements the Engine interface.
+func (b *Bootstrapper) Startup() {
+	// The accepted frontier was already agreed on if bootstrapping was
+	// interrupted, e.g. by this node restarting
+	if acceptedFrontier, resume := b.Bootstrapable.ResumableFrontier(); resume {
+		b.Context.Log.Info("Resuming bootstrapping with %d containers in the accepted frontier", acceptedFrontier.Len())
+		b.resumed = true
+		b.Bootstrapable.ForceAccepted(acceptedFrontier)
+		return
 	}
 
-	b.accepted.SetThreshold(config.Alpha)
+	if b.otherBeacons().Len() == 0 {
+		b.Context.Log.Info("Bootstrapping skipped due to no provided bootstraps")
+		b.Bootstrapable.ForceAccepted(ids.Set{})
+		return
+	}
+
+	b.sampleAcceptedFrontier()
 }
 
-// Startup implements the Engine interface.
-func (b *Bootstrapper) Startup() {
+// Resync is called once the accepted frontier has been bootstrapped. If that
+// frontier was resumed, the network has likely accepted more containers since
+// it was agreed on, so the accepted frontier is requested again and the new
+// one is bootstrapped. Returns true if bootstrapping continues.
+func (b *Bootstrapper) Resync() bool {
+	if !b.resumed || b.otherBeacons().Len() == 0 {
+		return false
+	}
+	b.resumed = false
+
+	b.Context.Log.Info("Finished bootstrapping the resumed accepted frontier. Requesting the current accepted frontier")
+	b.sampleAcceptedFrontier()
+	return true
+}
+
+// sampleAcceptedFrontier requests the accepted frontier from a new sample of
+// the beacons
+func (b *Bootstrapper) sampleAcceptedFrontier() {
+	b.sampledAcceptedFrontier = ids.ShortSet{}
+	b.pendingAcceptedFrontier = ids.ShortSet{}
+	b.acceptedFrontier = ids.Set{}
+
+	beacons := b.otherBeacons()
+	sampleK := b.SampleK
+	if sampleK <= 0 {
+		sampleK = beacons.Len()
+	}
+	for _, vdr := range beacons.Sample(sampleK) {
+		b.sampledAcceptedFrontier.Add(vdr.ID())
+	}
+	b.pendingAcceptedFrontier.Union(b.sampledAcceptedFrontier)
+
+	// None of the beacons have any stake
 	if b.pendingAcceptedFrontier.Len() == 0 {
-		b.Context.Log.Info("Bootstrapping skipped due to no provided bootstraps")
+		b.Context.Log.Warn("Bootstrapping skipped due to the provided bootstraps having no stake")
 		b.Bootstrapable.ForceAccepted(ids.Set{})
 		return
 	}
@@ -48,6 +138,19 @@ func (b *Bootstrapper) Startup() {
 	b.Sender.GetAcceptedFrontier(vdrs, b.RequestID)
 }
 
+// otherBeacons returns the beacons other than this node. This node can't vouch
+// for the containers it's bootstrapping, so it's neither asked about them nor
+// is its stake counted.
+func (b *Bootstrapper) otherBeacons() validators.Set {
+	beacons := validators.NewSet()
+	for _, vdr := range b.Beacons.List() {
+		if !vdr.ID().Equals(b.Context.NodeID) {
+			beacons.Add(vdr)
+		}
+	}
+	return beacons
+}
+
 // GetAcceptedFrontier implements the Engine interface.
 func (b *Bootstrapper) GetAcceptedFrontier(validatorID ids.ShortID, requestID uint32) {
 	b.Sender.AcceptedFrontier(validatorID, requestID, b.Bootstrapable.CurrentAcceptedFrontier())
@@ -55,6 +158,29 @@ func (b *Bootstrapper) GetAcceptedFrontier(validatorID ids.ShortID, requestID ui
 
 // GetAcceptedFrontierFailed implements the Engine interface.
 func (b *Bootstrapper) GetAcceptedFrontierFailed(validatorID ids.ShortID, requestID uint32) {
+	if !b.pendingAcceptedFrontier.Contains(validatorID) {
+		b.Context.Log.Debug("Received a GetAcceptedFrontierFailed message from %s unexpectedly", validatorID)
+		return
+	}
+
+	// Ask a beacon that wasn't sampled yet in place of the one that failed
+	beacons := b.otherBeacons()
+	for _, vdr := range beacons.Sample(beacons.Len()) {
+		vdrID := vdr.ID()
+		if b.sampledAcceptedFrontier.Contains(vdrID) {
+			continue
+		}
+		b.Context.Log.Debug("Failed to get the accepted frontier from %s. Asking %s instead", validatorID, vdrID)
+
+		b.sampledAcceptedFrontier.Add(vdrID)
+		b.pendingAcceptedFrontier.Add(vdrID)
+
+		vdrs := ids.ShortSet{}
+		vdrs.Add(vdrID)
+		b.Sender.GetAcceptedFrontier(vdrs, b.RequestID)
+		break
+	}
+
 	b.AcceptedFrontier(validatorID, requestID, ids.Set{})
 }
 
@@ -69,12 +195,31 @@ func (b *Bootstrapper) AcceptedFrontier(validatorID ids.ShortID, requestID uint3
 	b.acceptedFrontier.Union(containerIDs)
 
 	if b.pendingAcceptedFrontier.Len() == 0 {
-		vdrs := ids.ShortSet{}
-		vdrs.Union(b.pendingAccepted)
+		b.requestAccepted()
+	}
+}
 
-		b.RequestID++
-		b.Sender.GetAccepted(vdrs, b.RequestID, b.acceptedFrontier)
+// requestAccepted asks every beacon which of the containers in the accepted
+// frontier it accepted
+func (b *Bootstrapper) requestAccepted() {
+	b.pendingAccepted = ids.ShortSet{}
+	b.weights = make(map[[20]byte]uint64)
+	b.totalWeight = 0
+	b.respondedWeight = 0
+	b.acceptedWeight = make(map[[32]byte]uint64)
+
+	for _, vdr := range b.otherBeacons().List() {
+		vdrID := vdr.ID()
+		b.pendingAccepted.Add(vdrID)
+		b.weights[vdrID.Key()] = vdr.Weight()
+		b.totalWeight += vdr.Weight()
 	}
+
+	vdrs := ids.ShortSet{}
+	vdrs.Union(b.pendingAccepted)
+
+	b.RequestID++
+	b.Sender.GetAccepted(vdrs, b.RequestID, b.acceptedFrontier)
 }
 
 // GetAccepted implements the Engine interface.
@@ -84,7 +229,13 @@ func (b *Bootstrapper) GetAccepted(validatorID ids.ShortID, requestID uint32, co
 
 // GetAcceptedFailed implements the Engine interface.
 func (b *Bootstrapper) GetAcceptedFailed(validatorID ids.ShortID, requestID uint32) {
-	b.Accepted(validatorID, requestID, ids.Set{})
+	if !b.pendingAccepted.Contains(validatorID) {
+		b.Context.Log.Debug("Received a GetAcceptedFailed message from %s unexpectedly", validatorID)
+		return
+	}
+	b.pendingAccepted.Remove(validatorID)
+
+	b.finishAccepted()
 }
 
 // Accepted implements the Engine interface.
@@ -95,16 +246,48 @@ func (b *Bootstrapper) Accepted(validatorID ids.ShortID, requestID uint32, conta
 	}
 	b.pendingAccepted.Remove(validatorID)
 
-	b.accepted.Add(containerIDs.List()...)
+	weight := b.weights[validatorID.Key()]
+	b.respondedWeight += weight
+	for _, containerID := range containerIDs.List() {
+		// Only the containers that were asked about count
+		if b.acceptedFrontier.Contains(containerID) {
+			b.acceptedWeight[containerID.Key()] += weight
+		}
+	}
 
-	if b.pendingAccepted.Len() == 0 {
-		accepted := b.accepted.Threshold()
-		if size := accepted.Len(); size == 0 && b.Config.Beacons.Len() > 0 {
-			b.Context.Log.Warn("Bootstrapping finished with no accepted frontier. This is likely a result of failing to be able to connect to the specified bootstraps, or no transactions have been issued on this network yet")
-		} else {
-			b.Context.Log.Info("Bootstrapping finished with %d vertices in the accepted frontier", size)
+	b.finishAccepted()
+}
+
+// finishAccepted bootstraps the containers that enough of the beacons' stake
+// accepted, once every beacon responded or failed to respond. If too much of
+// the beacons' stake failed to respond for the containers to be agreed on, the
+// accepted frontier is sampled again instead.
+func (b *Bootstrapper) finishAccepted() {
+	if b.pendingAccepted.Len() != 0 {
+		return
+	}
+
+	threshold := b.Alpha * float64(b.totalWeight)
+	if float64(b.respondedWeight) < threshold && b.retries < b.MaxRetries {
+		b.retries++
+		b.Context.Log.Info("Only %d of %d stake responded while bootstrapping. Sampling the accepted frontier again (attempt %d of %d)",
+			b.respondedWeight, b.totalWeight, b.retries, b.MaxRetries)
+		b.sampleAcceptedFrontier()
+		return
+	}
+
+	accepted := ids.Set{}
+	for key, weight := range b.acceptedWeight {
+		if float64(weight) >= threshold {
+			accepted.Add(ids.NewID(key))
 		}
+	}
 
-		b.Bootstrapable.ForceAccepted(accepted)
+	if size := accepted.Len(); size == 0 && b.Config.Beacons.Len() > 0 {
+		b.Context.Log.Warn("Bootstrapping finished with no accepted frontier. This is likely a result of failing to be able to connect to the specified bootstraps, or no transactions have been issued on this network yet")
+	} else {
+		b.Context.Log.Info("Bootstrapping finished with %d vertices in the accepted frontier", size)
 	}
+
+	b.Bootstrapable.ForceAccepted(accepted)
 }
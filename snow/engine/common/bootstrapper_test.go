@@ -0,0 +1,218 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/validators"
+)
+
+// newBootstrapperConfig returns a config with a beacon for each of [weights]
+func newBootstrapperConfig(t *testing.T, weights ...uint64) (Config, []ids.ShortID, *SenderTest, *BootstrapableTest) {
+	config := DefaultConfigTest()
+	config.Alpha = 0.5
+
+	vdrIDs := []ids.ShortID(nil)
+	for i, weight := range weights {
+		vdrID := ids.NewShortID([20]byte{byte(i + 1)})
+		config.Beacons.Add(validators.NewValidator(vdrID, weight))
+		vdrIDs = append(vdrIDs, vdrID)
+	}
+
+	sender := config.Sender.(*SenderTest)
+	sender.T = t
+	sender.Default(true)
+
+	bootstrapable := config.Bootstrapable.(*BootstrapableTest)
+	bootstrapable.T = t
+	bootstrapable.Default(true)
+	bootstrapable.ResumableFrontierF = func() (ids.Set, bool) { return nil, false }
+
+	return config, vdrIDs, sender, bootstrapable
+}
+
+func TestBootstrapperStakeWeightedAgreement(t *testing.T) {
+	config, vdrIDs, sender, bootstrapable := newBootstrapperConfig(t, 3, 1, 1)
+
+	bs := Bootstrapper{}
+	bs.Initialize(config)
+
+	asked := ids.ShortSet{}
+	sender.GetAcceptedFrontierF = func(vdrs ids.ShortSet, _ uint32) { asked.Union(vdrs) }
+	bs.Startup()
+
+	if asked.Len() != len(vdrIDs) {
+		t.Fatalf("Wrong number of beacons asked. Expected: %d ; Returned: %d", len(vdrIDs), asked.Len())
+	}
+
+	heavyID := ids.Empty.Prefix(0)
+	lightID := ids.Empty.Prefix(1)
+
+	askedAccepted := ids.ShortSet{}
+	sender.GetAcceptedF = func(vdrs ids.ShortSet, _ uint32, containerIDs ids.Set) {
+		askedAccepted.Union(vdrs)
+		if containerIDs.Len() != 2 {
+			t.Fatalf("Wrong number of containers asked about. Expected: %d ; Returned: %d", 2, containerIDs.Len())
+		}
+	}
+	bs.AcceptedFrontier(vdrIDs[0], bs.RequestID, ids.Set{heavyID.Key(): true})
+	bs.AcceptedFrontier(vdrIDs[1], bs.RequestID, ids.Set{lightID.Key(): true})
+	bs.AcceptedFrontier(vdrIDs[2], bs.RequestID, ids.Set{lightID.Key(): true})
+
+	if askedAccepted.Len() != len(vdrIDs) {
+		t.Fatalf("Wrong number of beacons asked. Expected: %d ; Returned: %d", len(vdrIDs), askedAccepted.Len())
+	}
+
+	// The beacon with the most stake is the only one that accepted [heavyID],
+	// and the other two accepted [lightID]
+	accepted := ids.Set(nil)
+	bootstrapable.ForceAcceptedF = func(containerIDs ids.Set) { accepted = containerIDs }
+	bs.Accepted(vdrIDs[0], bs.RequestID, ids.Set{heavyID.Key(): true})
+	bs.Accepted(vdrIDs[1], bs.RequestID, ids.Set{lightID.Key(): true})
+	bs.Accepted(vdrIDs[2], bs.RequestID, ids.Set{lightID.Key(): true})
+
+	switch {
+	case accepted == nil:
+		t.Fatalf("should have finished bootstrapping")
+	case accepted.Len() != 1:
+		t.Fatalf("Wrong number of accepted containers. Expected: %d ; Returned: %d", 1, accepted.Len())
+	case !accepted.Contains(heavyID):
+		t.Fatalf("should have accepted the container that most of the stake accepted")
+	}
+}
+
+func TestBootstrapperResampleOnTimeout(t *testing.T) {
+	config, vdrIDs, sender, bootstrapable := newBootstrapperConfig(t, 1, 1)
+	config.SampleK = 1
+
+	bs := Bootstrapper{}
+	bs.Initialize(config)
+
+	asked := ids.ShortSet{}
+	sender.GetAcceptedFrontierF = func(vdrs ids.ShortSet, _ uint32) { asked = vdrs }
+	bs.Startup()
+
+	if asked.Len() != 1 {
+		t.Fatalf("Wrong number of beacons asked. Expected: %d ; Returned: %d", 1, asked.Len())
+	}
+	failedID := asked.List()[0]
+	otherID := vdrIDs[0]
+	if otherID.Equals(failedID) {
+		otherID = vdrIDs[1]
+	}
+
+	// The beacon that wasn't sampled is asked in place of the one that failed
+	bs.GetAcceptedFrontierFailed(failedID, bs.RequestID)
+	if asked.Len() != 1 || !asked.Contains(otherID) {
+		t.Fatalf("should have asked the beacon that wasn't sampled yet")
+	}
+
+	containerID := ids.Empty.Prefix(0)
+	sender.GetAcceptedF = func(vdrs ids.ShortSet, _ uint32, containerIDs ids.Set) {
+		if vdrs.Len() != 2 {
+			t.Fatalf("should have asked every beacon")
+		}
+		if !containerIDs.Contains(containerID) {
+			t.Fatalf("should have asked about the accepted frontier")
+		}
+	}
+	bs.AcceptedFrontier(otherID, bs.RequestID, ids.Set{containerID.Key(): true})
+
+	accepted := ids.Set(nil)
+	bootstrapable.ForceAcceptedF = func(containerIDs ids.Set) { accepted = containerIDs }
+	bs.Accepted(vdrIDs[0], bs.RequestID, ids.Set{containerID.Key(): true})
+	bs.Accepted(vdrIDs[1], bs.RequestID, ids.Set{containerID.Key(): true})
+
+	if !accepted.Contains(containerID) {
+		t.Fatalf("should have accepted the container")
+	}
+}
+
+func TestBootstrapperRetryWithoutEnoughStake(t *testing.T) {
+	config, vdrIDs, sender, bootstrapable := newBootstrapperConfig(t, 1, 1, 1)
+
+	bs := Bootstrapper{}
+	bs.Initialize(config)
+
+	numAsked := 0
+	sender.GetAcceptedFrontierF = func(ids.ShortSet, uint32) { numAsked++ }
+	bs.Startup()
+
+	containerID := ids.Empty.Prefix(0)
+	sender.GetAcceptedF = func(ids.ShortSet, uint32, ids.Set) {}
+	for _, vdrID := range vdrIDs {
+		bs.AcceptedFrontier(vdrID, bs.RequestID, ids.Set{containerID.Key(): true})
+	}
+
+	// Only a third of the stake responds, so the accepted frontier is sampled
+	// again
+	bs.Accepted(vdrIDs[0], bs.RequestID, ids.Set{containerID.Key(): true})
+	bs.GetAcceptedFailed(vdrIDs[1], bs.RequestID)
+	bs.GetAcceptedFailed(vdrIDs[2], bs.RequestID)
+
+	if numAsked != 2 {
+		t.Fatalf("Wrong number of accepted frontier requests. Expected: %d ; Returned: %d", 2, numAsked)
+	}
+
+	for _, vdrID := range vdrIDs {
+		bs.AcceptedFrontier(vdrID, bs.RequestID, ids.Set{containerID.Key(): true})
+	}
+
+	accepted := ids.Set(nil)
+	bootstrapable.ForceAcceptedF = func(containerIDs ids.Set) { accepted = containerIDs }
+	bs.Accepted(vdrIDs[0], bs.RequestID, ids.Set{containerID.Key(): true})
+	bs.Accepted(vdrIDs[1], bs.RequestID, ids.Set{containerID.Key(): true})
+	bs.GetAcceptedFailed(vdrIDs[2], bs.RequestID)
+
+	if !accepted.Contains(containerID) {
+		t.Fatalf("should have accepted the container")
+	}
+}
+
+func TestBootstrapperExcludesSelf(t *testing.T) {
+	config, vdrIDs, sender, bootstrapable := newBootstrapperConfig(t, 1, 1)
+
+	// This node holds most of the stake, but can't vouch for what it's
+	// bootstrapping
+	config.Context.NodeID = ids.NewShortID([20]byte{0xff})
+	config.Beacons.Add(validators.NewValidator(config.Context.NodeID, 10))
+
+	bs := Bootstrapper{}
+	bs.Initialize(config)
+
+	asked := ids.ShortSet{}
+	sender.GetAcceptedFrontierF = func(vdrs ids.ShortSet, _ uint32) { asked.Union(vdrs) }
+	bs.Startup()
+
+	if asked.Contains(config.Context.NodeID) {
+		t.Fatalf("Shouldn't have asked this node for its accepted frontier")
+	}
+	if asked.Len() != len(vdrIDs) {
+		t.Fatalf("Wrong number of beacons asked. Expected: %d ; Returned: %d", len(vdrIDs), asked.Len())
+	}
+
+	containerID := ids.Empty.Prefix(0)
+	askedAccepted := ids.ShortSet{}
+	sender.GetAcceptedF = func(vdrs ids.ShortSet, _ uint32, _ ids.Set) { askedAccepted.Union(vdrs) }
+	for _, vdrID := range vdrIDs {
+		bs.AcceptedFrontier(vdrID, bs.RequestID, ids.Set{containerID.Key(): true})
+	}
+
+	if askedAccepted.Contains(config.Context.NodeID) {
+		t.Fatalf("Shouldn't have asked this node which containers it accepted")
+	}
+
+	// The other beacons hold all of the stake that counts
+	accepted := ids.Set(nil)
+	bootstrapable.ForceAcceptedF = func(containerIDs ids.Set) { accepted = containerIDs }
+	for _, vdrID := range vdrIDs {
+		bs.Accepted(vdrID, bs.RequestID, ids.Set{containerID.Key(): true})
+	}
+
+	if !accepted.Contains(containerID) {
+		t.Fatalf("Should have accepted the container")
+	}
+}
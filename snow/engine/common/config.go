@@ -15,7 +15,22 @@ type Config struct {
 	Validators validators.Set
 	Beacons    validators.Set
 
-	Alpha         int
+	// SampleK is the number of beacons, sampled by stake, that are asked for
+	// their accepted frontier while bootstrapping. If it isn't positive, every
+	// beacon is asked.
+	SampleK int
+
+	// Alpha is the fraction of the beacons' total stake that must have
+	// accepted a container for it to be bootstrapped
+	Alpha float64
+
+	// MaxRetries is the number of times the accepted frontier is sampled again
+	// when too little of the beacons' stake responded to agree on it
+	MaxRetries int
+
 	Sender        Sender
 	Bootstrapable Bootstrapable
+
+	// Progress is updated as the chain bootstraps. Optional.
+	Progress *Progress
 }
@@ -68,6 +68,19 @@ type AcceptedHandler interface {
 // FetchHandler defines how a consensus engine reacts to retrieval messages from
 // other validators
 type FetchHandler interface {
+	// GetAncestors notifies this consensus engine that the specified validator
+	// requested that this engine send the specified container and its
+	// ancestors, in a single MultiPut message.
+	GetAncestors(validatorID ids.ShortID, requestID uint32, containerID ids.ID)
+
+	// MultiPut notifies this consensus engine that the specified validator
+	// sent the containers it requested with GetAncestors. The first container
+	// should be the requested one, followed by some of its ancestors.
+	MultiPut(validatorID ids.ShortID, requestID uint32, containers [][]byte)
+
+	// Notify this engine that a GetAncestors request it issued has failed.
+	GetAncestorsFailed(validatorID ids.ShortID, requestID uint32)
+
 	// Get notifies this consensus engine that the specified validator requested
 	// that this engine send the specified container to it
 	Get(validatorID ids.ShortID, requestID uint32, containerID ids.ID)
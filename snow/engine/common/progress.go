@@ -0,0 +1,99 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+// Progress of bootstrapping a chain. It's updated by the bootstrapper, and can
+// be read concurrently, e.g. by an API.
+type Progress struct {
+	lock  sync.RWMutex
+	clock timer.Clock
+
+	bootstrapped      bool
+	fetched, executed uint64
+
+	// Whether the fetched containers are being executed, when this node
+	// started executing them, and how many of them were executed before then,
+	// e.g. before this node restarted
+	executing      bool
+	executionStart time.Time
+	executedBefore uint64
+}
+
+// ProgressStatus is a snapshot of the progress of bootstrapping a chain
+type ProgressStatus struct {
+	Bootstrapped bool
+
+	// The number of containers that were fetched, and how many of them were
+	// executed
+	Fetched, Executed uint64
+
+	// The estimated time until all the fetched containers are executed. Only
+	// known once the containers are being executed.
+	ETA      time.Duration
+	ETAKnown bool
+}
+
+// Update the number of containers that were fetched and executed
+func (p *Progress) Update(fetched, executed uint64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.fetched = fetched
+	p.executed = executed
+}
+
+// StartExecuting marks that the fetched containers are being executed
+func (p *Progress) StartExecuting() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.executing {
+		return
+	}
+	p.executing = true
+	p.executionStart = p.clock.Time()
+	p.executedBefore = p.executed
+}
+
+// Finish marks that the chain finished bootstrapping
+func (p *Progress) Finish() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.bootstrapped = true
+	p.executing = false
+}
+
+// Status returns the current progress
+func (p *Progress) Status() ProgressStatus {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	status := ProgressStatus{
+		Bootstrapped: p.bootstrapped,
+		Fetched:      p.fetched,
+		Executed:     p.executed,
+	}
+	if !p.executing || p.executed <= p.executedBefore {
+		return status
+	}
+
+	// Assume the remaining containers are executed at the same rate as the
+	// containers executed so far
+	elapsed := p.clock.Time().Sub(p.executionStart)
+	remaining := float64(p.fetched - p.executed)
+	if p.executed > p.fetched {
+		remaining = 0
+	}
+	status.ETA = time.Duration(float64(elapsed) * remaining / float64(p.executed-p.executedBefore))
+	status.ETAKnown = true
+	return status
+}
@@ -0,0 +1,56 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProgressETA(t *testing.T) {
+	p := Progress{}
+	start := time.Unix(1000, 0)
+	p.clock.Set(start)
+
+	// 10 containers were executed before this node restarted
+	p.Update(100, 10)
+	if status := p.Status(); status.ETAKnown {
+		t.Fatalf("The ETA shouldn't be known before the containers are executed")
+	}
+
+	p.StartExecuting()
+	if status := p.Status(); status.ETAKnown {
+		t.Fatalf("The ETA shouldn't be known before any containers are executed")
+	}
+
+	// 30 containers are executed in 15 seconds, so the remaining 60 should be
+	// executed in 30 seconds
+	p.clock.Set(start.Add(15 * time.Second))
+	p.Update(100, 40)
+
+	status := p.Status()
+	switch {
+	case !status.ETAKnown:
+		t.Fatalf("The ETA should be known")
+	case status.ETA != 30*time.Second:
+		t.Fatalf("Wrong ETA. Expected: %s ; Returned: %s", 30*time.Second, status.ETA)
+	case status.Fetched != 100:
+		t.Fatalf("Wrong number of fetched containers. Expected: %d ; Returned: %d", 100, status.Fetched)
+	case status.Executed != 40:
+		t.Fatalf("Wrong number of executed containers. Expected: %d ; Returned: %d", 40, status.Executed)
+	case status.Bootstrapped:
+		t.Fatalf("Shouldn't be bootstrapped yet")
+	}
+
+	p.Update(100, 100)
+	p.Finish()
+
+	status = p.Status()
+	switch {
+	case !status.Bootstrapped:
+		t.Fatalf("Should be bootstrapped")
+	case status.ETAKnown:
+		t.Fatalf("The ETA shouldn't be known after bootstrapping finished")
+	}
+}
@@ -12,12 +12,30 @@ import (
 	"github.com/ava-labs/gecko/utils/wrappers"
 )
 
+const (
+	// The maximum number of keys deleted by a single batch when the queue is
+	// cleared
+	maxClearBatchSize = 1024
+)
+
 var (
-	errEmpty     = errors.New("no available containers")
-	errDuplicate = errors.New("duplicated container")
+	// ErrDuplicate is returned when a job that was already pushed is pushed
+	// again
+	ErrDuplicate = errors.New("duplicated container")
+
+	errEmpty            = errors.New("no available containers")
+	errTooManyExecuted  = errors.New("more jobs were executed than were pushed")
+	errTooManyPending   = errors.New("more jobs are ready to execute than haven't been executed")
+	errNoLastExecuted   = errors.New("jobs were executed, but the last executed job is unknown")
+	errLastExecutedJobs = errors.New("no jobs were executed, but a last executed job is known")
 )
 
-// Jobs ...
+// Jobs is a queue of jobs that are executed once their dependencies have been
+// executed. Along with the jobs, the queue tracks how many jobs were pushed and
+// executed, the IDs of the containers that still need to be fetched, and the
+// accepted frontier the jobs were fetched from. None of this is written to the
+// underlying database until Commit is called, so committing at consistent
+// points allows the progress to be resumed after a restart.
 type Jobs struct {
 	parser Parser
 	baseDB database.Database
@@ -38,7 +56,18 @@ func New(db database.Database) (*Jobs, error) {
 	if _, err := jobs.HasNext(); err == nil {
 		return jobs, nil
 	}
-	return jobs, jobs.state.SetStackSize(jobs.db, 0)
+	return jobs, jobs.initialize()
+}
+
+// initialize the state of an empty queue
+func (j *Jobs) initialize() error {
+	errs := wrappers.Errs{}
+	errs.Add(
+		j.state.SetStackSize(j.db, 0),
+		j.state.SetNumJobs(j.db, 0),
+		j.state.SetNumExecuted(j.db, 0),
+	)
+	return errs.Err
 }
 
 // SetParser ...
@@ -77,12 +106,27 @@ func (j *Jobs) HasNext() (bool, error) {
 	return size > 0, err
 }
 
+// Has returns true if a job with ID [jobID] was pushed, whether or not it was
+// executed since
+func (j *Jobs) Has(jobID ids.ID) (bool, error) { return j.state.HasJob(j.db, jobID) }
+
 // Execute ...
 func (j *Jobs) Execute(job Job) error {
 	job.Execute()
 
 	jobID := job.ID()
 
+	executed, err := j.state.NumExecuted(j.db)
+	if err != nil {
+		return err
+	}
+	if err := j.state.SetNumExecuted(j.db, executed+1); err != nil {
+		return err
+	}
+	if err := j.state.SetLastExecuted(j.db, jobID); err != nil {
+		return err
+	}
+
 	blocking, _ := j.state.Blocking(j.db, jobID)
 	j.state.DeleteBlocking(j.db, jobID)
 
@@ -94,8 +138,7 @@ func (j *Jobs) Execute(job Job) error {
 		if job.MissingDependencies().Len() > 0 {
 			continue
 		}
-		j.state.DeleteJob(j.db, blockedID)
-		if err := j.push(job); err != nil {
+		if err := j.addToStack(job); err != nil {
 			return err
 		}
 	}
@@ -103,20 +146,133 @@ func (j *Jobs) Execute(job Job) error {
 	return nil
 }
 
+// NumJobs returns the number of jobs that were pushed
+func (j *Jobs) NumJobs() (uint32, error) { return j.state.NumJobs(j.db) }
+
+// NumExecuted returns the number of jobs that were executed
+func (j *Jobs) NumExecuted() (uint32, error) { return j.state.NumExecuted(j.db) }
+
+// LastExecuted returns the ID of the job that was executed last
+func (j *Jobs) LastExecuted() (ids.ID, error) { return j.state.LastExecuted(j.db) }
+
+// AddMissingID marks the container [containerID] as needing to be fetched
+func (j *Jobs) AddMissingID(containerID ids.ID) error {
+	return j.state.AddMissingID(j.db, containerID)
+}
+
+// RemoveMissingID marks the container [containerID] as no longer needing to
+// be fetched
+func (j *Jobs) RemoveMissingID(containerID ids.ID) error {
+	return j.state.DeleteMissingID(j.db, containerID)
+}
+
+// MissingIDs returns the IDs of the containers that need to be fetched
+func (j *Jobs) MissingIDs() (ids.Set, error) { return j.state.MissingIDs(j.db) }
+
+// SetFrontier sets the accepted frontier that the jobs are being fetched from
+func (j *Jobs) SetFrontier(containerIDs ids.Set) error {
+	return j.state.SetFrontier(j.db, containerIDs)
+}
+
+// Frontier returns the accepted frontier that the jobs are being fetched from.
+// Returns an empty set if no frontier was set.
+func (j *Jobs) Frontier() (ids.Set, error) {
+	containerIDs, err := j.state.Frontier(j.db)
+	if err == database.ErrNotFound {
+		return ids.Set{}, nil
+	}
+	return containerIDs, err
+}
+
+// Verify that the state of the queue is consistent
+func (j *Jobs) Verify() error {
+	size, err := j.state.StackSize(j.db)
+	if err != nil {
+		return err
+	}
+	pushed, err := j.state.NumJobs(j.db)
+	if err != nil {
+		return err
+	}
+	executed, err := j.state.NumExecuted(j.db)
+	if err != nil {
+		return err
+	}
+	hasLastExecuted, err := j.db.Has(lastExecuted)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case executed > pushed:
+		return errTooManyExecuted
+	case size > pushed-executed:
+		return errTooManyPending
+	case executed > 0 && !hasLastExecuted:
+		return errNoLastExecuted
+	case executed == 0 && hasLastExecuted:
+		return errLastExecutedJobs
+	default:
+		return nil
+	}
+}
+
 // Commit ...
 func (j *Jobs) Commit() error { return j.db.Commit() }
 
+// Clear removes all the jobs, and all the progress, from the queue. Any
+// changes that weren't committed are discarded.
+func (j *Jobs) Clear() error {
+	if err := j.db.Abort(); err != nil {
+		return err
+	}
+
+	iter := j.baseDB.NewIterator()
+	defer iter.Release()
+
+	batch := j.baseDB.NewBatch()
+	numDeleted := 0
+	for iter.Next() {
+		if err := batch.Delete(iter.Key()); err != nil {
+			return err
+		}
+		if numDeleted++; numDeleted%maxClearBatchSize == 0 {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+
+	if err := j.initialize(); err != nil {
+		return err
+	}
+	return j.db.Commit()
+}
+
 func (j *Jobs) push(job Job) error {
 	if has, err := j.state.HasJob(j.db, job.ID()); err != nil {
 		return err
 	} else if has {
-		return errDuplicate
+		return ErrDuplicate
 	}
 
 	if err := j.state.SetJob(j.db, job); err != nil {
 		return err
 	}
+	if err := j.incrementNumJobs(); err != nil {
+		return err
+	}
+	return j.addToStack(job)
+}
 
+func (j *Jobs) addToStack(job Job) error {
 	errs := wrappers.Errs{}
 
 	size, err := j.state.StackSize(j.db)
@@ -128,9 +284,18 @@ func (j *Jobs) push(job Job) error {
 }
 
 func (j *Jobs) block(job Job, deps ids.Set) error {
+	has, err := j.state.HasJob(j.db, job.ID())
+	if err != nil {
+		return err
+	}
 	if err := j.state.SetJob(j.db, job); err != nil {
 		return err
 	}
+	if !has {
+		if err := j.incrementNumJobs(); err != nil {
+			return err
+		}
+	}
 
 	jobID := job.ID()
 	for _, depID := range deps.List() {
@@ -143,3 +308,11 @@ func (j *Jobs) block(job Job, deps ids.Set) error {
 
 	return nil
 }
+
+func (j *Jobs) incrementNumJobs() error {
+	pushed, err := j.state.NumJobs(j.db)
+	if err != nil {
+		return err
+	}
+	return j.state.SetNumJobs(j.db, pushed+1)
+}
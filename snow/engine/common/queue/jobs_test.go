@@ -250,3 +250,234 @@ func TestDuplicatedPush(t *testing.T) {
 		t.Fatalf("Shouldn't have a container ready to pop")
 	}
 }
+
+func TestProgressPersisted(t *testing.T) {
+	parser := &TestParser{T: t}
+	db := memdb.New()
+
+	jobs, err := New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobs.SetParser(parser)
+
+	id0 := ids.Empty.Prefix(0)
+	job0 := &TestJob{
+		T: t,
+
+		IDF:                  func() ids.ID { return id0 },
+		MissingDependenciesF: func() ids.Set { return ids.Set{} },
+		ExecuteF:             func() {},
+		BytesF:               func() []byte { return []byte{0} },
+	}
+
+	id1 := ids.Empty.Prefix(1)
+	job1 := &TestJob{
+		T: t,
+
+		IDF:                  func() ids.ID { return id1 },
+		MissingDependenciesF: func() ids.Set { return ids.Set{id0.Key(): true} },
+		ExecuteF:             func() {},
+		BytesF:               func() []byte { return []byte{1} },
+	}
+
+	frontier := ids.Set{}
+	frontier.Add(id1)
+	missingID := ids.Empty.Prefix(2)
+
+	if err := jobs.SetFrontier(frontier); err != nil {
+		t.Fatal(err)
+	}
+	if err := jobs.Push(job1); err != nil {
+		t.Fatal(err)
+	}
+	if err := jobs.Push(job0); err != nil {
+		t.Fatal(err)
+	}
+	if err := jobs.AddMissingID(missingID); err != nil {
+		t.Fatal(err)
+	}
+
+	parser.ParseF = func(b []byte) (Job, error) {
+		switch {
+		case bytes.Equal(b, []byte{0}):
+			return job0, nil
+		case bytes.Equal(b, []byte{1}):
+			return job1, nil
+		}
+		t.Fatalf("Unknown job")
+		return nil, nil
+	}
+
+	job, err := jobs.Pop()
+	if err != nil {
+		t.Fatal(err)
+	}
+	job1.MissingDependenciesF = func() ids.Set { return ids.Set{} }
+	if err := jobs.Execute(job); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := jobs.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs, err = New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobs.SetParser(parser)
+
+	if err := jobs.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if numJobs, err := jobs.NumJobs(); err != nil {
+		t.Fatal(err)
+	} else if numJobs != 2 {
+		t.Fatalf("Wrong number of jobs. Expected: %d ; Returned: %d", 2, numJobs)
+	}
+	if numExecuted, err := jobs.NumExecuted(); err != nil {
+		t.Fatal(err)
+	} else if numExecuted != 1 {
+		t.Fatalf("Wrong number of executed jobs. Expected: %d ; Returned: %d", 1, numExecuted)
+	}
+	if lastExecuted, err := jobs.LastExecuted(); err != nil {
+		t.Fatal(err)
+	} else if !lastExecuted.Equals(id0) {
+		t.Fatalf("Wrong last executed job. Expected: %s ; Returned: %s", id0, lastExecuted)
+	}
+	if has, err := jobs.Has(id1); err != nil {
+		t.Fatal(err)
+	} else if !has {
+		t.Fatalf("Should have the pushed job")
+	}
+	if returnedFrontier, err := jobs.Frontier(); err != nil {
+		t.Fatal(err)
+	} else if !returnedFrontier.Equals(frontier) {
+		t.Fatalf("Wrong frontier. Expected: %s ; Returned: %s", frontier, returnedFrontier)
+	}
+	if missingIDs, err := jobs.MissingIDs(); err != nil {
+		t.Fatal(err)
+	} else if missingIDs.Len() != 1 || !missingIDs.Contains(missingID) {
+		t.Fatalf("Wrong missing IDs. Expected: [%s] ; Returned: %s", missingID, missingIDs)
+	}
+
+	if err := jobs.RemoveMissingID(missingID); err != nil {
+		t.Fatal(err)
+	}
+	if missingIDs, err := jobs.MissingIDs(); err != nil {
+		t.Fatal(err)
+	} else if missingIDs.Len() != 0 {
+		t.Fatalf("Shouldn't have any missing IDs")
+	}
+
+	if job, err := jobs.Pop(); err != nil {
+		t.Fatal(err)
+	} else if job != job1 {
+		t.Fatalf("Returned wrong job")
+	}
+}
+
+func TestClear(t *testing.T) {
+	parser := &TestParser{T: t}
+	db := memdb.New()
+
+	jobs, err := New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobs.SetParser(parser)
+
+	id := ids.Empty.Prefix(0)
+	job := &TestJob{
+		T: t,
+
+		IDF:                  func() ids.ID { return id },
+		MissingDependenciesF: func() ids.Set { return ids.Set{} },
+		ExecuteF:             func() {},
+		BytesF:               func() []byte { return []byte{0} },
+	}
+
+	frontier := ids.Set{}
+	frontier.Add(id)
+
+	if err := jobs.SetFrontier(frontier); err != nil {
+		t.Fatal(err)
+	}
+	if err := jobs.AddMissingID(id); err != nil {
+		t.Fatal(err)
+	}
+	if err := jobs.Push(job); err != nil {
+		t.Fatal(err)
+	}
+	if err := jobs.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := jobs.Clear(); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs, err = New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobs.SetParser(parser)
+
+	if err := jobs.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if hasNext, err := jobs.HasNext(); err != nil {
+		t.Fatal(err)
+	} else if hasNext {
+		t.Fatalf("Shouldn't have a container ready to pop")
+	}
+	if has, err := jobs.Has(id); err != nil {
+		t.Fatal(err)
+	} else if has {
+		t.Fatalf("Shouldn't have the cleared job")
+	}
+	if numJobs, err := jobs.NumJobs(); err != nil {
+		t.Fatal(err)
+	} else if numJobs != 0 {
+		t.Fatalf("Wrong number of jobs. Expected: %d ; Returned: %d", 0, numJobs)
+	}
+	if returnedFrontier, err := jobs.Frontier(); err != nil {
+		t.Fatal(err)
+	} else if returnedFrontier.Len() != 0 {
+		t.Fatalf("Shouldn't have a frontier")
+	}
+	if missingIDs, err := jobs.MissingIDs(); err != nil {
+		t.Fatal(err)
+	} else if missingIDs.Len() != 0 {
+		t.Fatalf("Shouldn't have any missing IDs")
+	}
+}
+
+func TestVerifyInconsistent(t *testing.T) {
+	db := memdb.New()
+
+	jobs, err := New(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := jobs.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	// More jobs were executed than were pushed
+	if err := jobs.state.SetNumExecuted(jobs.db, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := jobs.state.SetLastExecuted(jobs.db, ids.Empty); err != nil {
+		t.Fatal(err)
+	}
+	if err := jobs.Verify(); err == nil {
+		t.Fatalf("Should have failed verification due to executing more jobs than were pushed")
+	}
+}
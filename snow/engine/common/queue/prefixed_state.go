@@ -16,10 +16,20 @@ const (
 	stackID
 	jobID
 	blockingID
+	numJobsID
+	numExecutedID
+	lastExecutedID
+	missingID
+	frontierID
 )
 
 var (
-	stackSize = []byte{stackSizeID}
+	stackSize    = []byte{stackSizeID}
+	numJobs      = []byte{numJobsID}
+	numExecuted  = []byte{numExecutedID}
+	lastExecuted = []byte{lastExecutedID}
+	missing      = []byte{missingID}
+	frontier     = []byte{frontierID}
 )
 
 type prefixedState struct{ state }
@@ -121,3 +131,80 @@ func (ps *prefixedState) Blocking(db database.Database, id ids.ID) (ids.Set, err
 
 	return ps.state.IDs(db, p.Bytes)
 }
+
+func (ps *prefixedState) SetNumJobs(db database.Database, num uint32) error {
+	return ps.state.SetInt(db, numJobs, num)
+}
+
+func (ps *prefixedState) NumJobs(db database.Database) (uint32, error) {
+	return ps.state.Int(db, numJobs)
+}
+
+func (ps *prefixedState) SetNumExecuted(db database.Database, num uint32) error {
+	return ps.state.SetInt(db, numExecuted, num)
+}
+
+func (ps *prefixedState) NumExecuted(db database.Database) (uint32, error) {
+	return ps.state.Int(db, numExecuted)
+}
+
+func (ps *prefixedState) SetLastExecuted(db database.Database, id ids.ID) error {
+	return db.Put(lastExecuted, id.Bytes())
+}
+
+func (ps *prefixedState) DeleteLastExecuted(db database.Database) error {
+	return db.Delete(lastExecuted)
+}
+
+func (ps *prefixedState) LastExecuted(db database.Database) (ids.ID, error) {
+	bytes, err := db.Get(lastExecuted)
+	if err != nil {
+		return ids.ID{}, err
+	}
+	return ids.ToID(bytes)
+}
+
+func (ps *prefixedState) AddMissingID(db database.Database, id ids.ID) error {
+	p := wrappers.Packer{Bytes: make([]byte, 1+hashing.HashLen)}
+
+	p.PackByte(missingID)
+	p.PackFixedBytes(id.Bytes())
+
+	return db.Put(p.Bytes, nil)
+}
+
+func (ps *prefixedState) DeleteMissingID(db database.Database, id ids.ID) error {
+	p := wrappers.Packer{Bytes: make([]byte, 1+hashing.HashLen)}
+
+	p.PackByte(missingID)
+	p.PackFixedBytes(id.Bytes())
+
+	return db.Delete(p.Bytes)
+}
+
+func (ps *prefixedState) MissingIDs(db database.Database) (ids.Set, error) {
+	iter := db.NewIteratorWithPrefix(missing)
+	defer iter.Release()
+
+	missingIDs := ids.Set{}
+	for iter.Next() {
+		id, err := ids.ToID(iter.Key()[1:])
+		if err != nil {
+			return nil, err
+		}
+		missingIDs.Add(id)
+	}
+	return missingIDs, iter.Error()
+}
+
+func (ps *prefixedState) SetFrontier(db database.Database, containerIDs ids.Set) error {
+	return ps.state.SetIDs(db, frontier, containerIDs)
+}
+
+func (ps *prefixedState) DeleteFrontier(db database.Database) error {
+	return db.Delete(frontier)
+}
+
+func (ps *prefixedState) Frontier(db database.Database) (ids.Set, error) {
+	return ps.state.IDs(db, frontier)
+}
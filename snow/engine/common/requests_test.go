@@ -0,0 +1,126 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestRequests(t *testing.T) {
+	req := Requests{}
+
+	length := req.Len()
+	if length != 0 {
+		t.Fatalf("should have had no outstanding requests")
+	}
+
+	_, removed := req.Remove(ids.ShortEmpty, 0)
+	if removed {
+		t.Fatalf("shouldn't have removed the request")
+	}
+
+	removed = req.RemoveAny(ids.Empty)
+	if removed {
+		t.Fatalf("shouldn't have removed the request")
+	}
+
+	contains := req.Contains(ids.Empty)
+	if contains {
+		t.Fatalf("shouldn't contain this request")
+	}
+
+	req.Add(ids.ShortEmpty, 0, ids.Empty)
+
+	length = req.Len()
+	if length != 1 {
+		t.Fatalf("should have had one outstanding request")
+	}
+
+	_, removed = req.Remove(ids.ShortEmpty, 1)
+	if removed {
+		t.Fatalf("shouldn't have removed the request")
+	}
+
+	_, removed = req.Remove(ids.NewShortID([20]byte{1}), 0)
+	if removed {
+		t.Fatalf("shouldn't have removed the request")
+	}
+
+	contains = req.Contains(ids.Empty)
+	if !contains {
+		t.Fatalf("should contain this request")
+	}
+
+	length = req.Len()
+	if length != 1 {
+		t.Fatalf("should have had one outstanding request")
+	}
+
+	req.Add(ids.ShortEmpty, 10, ids.Empty.Prefix(0))
+
+	length = req.Len()
+	if length != 2 {
+		t.Fatalf("should have had two outstanding requests")
+	}
+
+	_, removed = req.Remove(ids.ShortEmpty, 1)
+	if removed {
+		t.Fatalf("shouldn't have removed the request")
+	}
+
+	_, removed = req.Remove(ids.NewShortID([20]byte{1}), 0)
+	if removed {
+		t.Fatalf("shouldn't have removed the request")
+	}
+
+	contains = req.Contains(ids.Empty)
+	if !contains {
+		t.Fatalf("should contain this request")
+	}
+
+	length = req.Len()
+	if length != 2 {
+		t.Fatalf("should have had two outstanding requests")
+	}
+
+	removedID, removed := req.Remove(ids.ShortEmpty, 0)
+	if !removedID.Equals(ids.Empty) {
+		t.Fatalf("should have removed the requested ID")
+	}
+	if !removed {
+		t.Fatalf("should have removed the request")
+	}
+
+	removedID, removed = req.Remove(ids.ShortEmpty, 10)
+	if !removedID.Equals(ids.Empty.Prefix(0)) {
+		t.Fatalf("should have removed the requested ID")
+	}
+	if !removed {
+		t.Fatalf("should have removed the request")
+	}
+
+	length = req.Len()
+	if length != 0 {
+		t.Fatalf("should have had no outstanding requests")
+	}
+
+	req.Add(ids.ShortEmpty, 0, ids.Empty)
+
+	length = req.Len()
+	if length != 1 {
+		t.Fatalf("should have had one outstanding request")
+	}
+
+	removed = req.RemoveAny(ids.Empty)
+	if !removed {
+		t.Fatalf("should have removed the request")
+	}
+
+	length = req.Len()
+	if length != 0 {
+		t.Fatalf("should have had no outstanding requests")
+	}
+}
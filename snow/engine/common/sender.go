@@ -44,6 +44,14 @@ type AcceptedSender interface {
 // FetchSender defines how a consensus engine sends retrieval messages to other
 // validators
 type FetchSender interface {
+	// Request that the specified validator send the specified container and
+	// its ancestors, in a single MultiPut message.
+	GetAncestors(validatorID ids.ShortID, requestID uint32, containerID ids.ID)
+
+	// Tell the specified validator about [containers], which should be a
+	// container and some of its ancestors, ordered from the container down.
+	MultiPut(validatorID ids.ShortID, requestID uint32, containers [][]byte)
+
 	// Request a container from a validator.
 	// Request that the specified validator send the specified container
 	// to this validator
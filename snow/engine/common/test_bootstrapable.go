@@ -15,11 +15,13 @@ type BootstrapableTest struct {
 
 	CantCurrentAcceptedFrontier,
 	CantFilterAccepted,
-	CantForceAccepted bool
+	CantForceAccepted,
+	CantResumableFrontier bool
 
 	CurrentAcceptedFrontierF func() (acceptedContainerIDs ids.Set)
 	FilterAcceptedF          func(containerIDs ids.Set) (acceptedContainerIDs ids.Set)
 	ForceAcceptedF           func(acceptedContainerIDs ids.Set)
+	ResumableFrontierF       func() (acceptedContainerIDs ids.Set, resume bool)
 }
 
 // Default sets the default on call handling
@@ -27,6 +29,7 @@ func (b *BootstrapableTest) Default(cant bool) {
 	b.CantCurrentAcceptedFrontier = cant
 	b.CantFilterAccepted = cant
 	b.CantForceAccepted = cant
+	b.CantResumableFrontier = cant
 }
 
 // CurrentAcceptedFrontier implements the Bootstrapable interface
@@ -59,3 +62,14 @@ func (b *BootstrapableTest) ForceAccepted(containerIDs ids.Set) {
 		b.T.Fatalf("Unexpectedly called ForceAccepted")
 	}
 }
+
+// ResumableFrontier implements the Bootstrapable interface
+func (b *BootstrapableTest) ResumableFrontier() (ids.Set, bool) {
+	if b.ResumableFrontierF != nil {
+		return b.ResumableFrontierF()
+	}
+	if b.CantResumableFrontier && b.T != nil {
+		b.T.Fatalf("Unexpectedly called ResumableFrontier")
+	}
+	return nil, false
+}
@@ -14,6 +14,7 @@ func DefaultConfigTest() Config {
 		Context:       snow.DefaultContextTest(),
 		Validators:    validators.NewSet(),
 		Beacons:       validators.NewSet(),
+		MaxRetries:    DefaultBootstrapRetries,
 		Sender:        &SenderTest{},
 		Bootstrapable: &BootstrapableTest{},
 	}
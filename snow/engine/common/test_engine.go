@@ -29,6 +29,10 @@ type EngineTest struct {
 	CantGetAcceptedFailed,
 	CantAccepted,
 
+	CantGetAncestors,
+	CantGetAncestorsFailed,
+	CantMultiPut,
+
 	CantGet,
 	CantGetFailed,
 	CantPut,
@@ -38,13 +42,14 @@ type EngineTest struct {
 	CantQueryFailed,
 	CantChits bool
 
-	StartupF, ShutdownF                                                                func()
-	ContextF                                                                           func() *snow.Context
-	NotifyF                                                                            func(Message)
-	GetF, GetFailedF, PullQueryF                                                       func(validatorID ids.ShortID, requestID uint32, containerID ids.ID)
-	PutF, PushQueryF                                                                   func(validatorID ids.ShortID, requestID uint32, containerID ids.ID, container []byte)
-	GetAcceptedFrontierF, GetAcceptedFrontierFailedF, GetAcceptedFailedF, QueryFailedF func(validatorID ids.ShortID, requestID uint32)
-	AcceptedFrontierF, GetAcceptedF, AcceptedF, ChitsF                                 func(validatorID ids.ShortID, requestID uint32, containerIDs ids.Set)
+	StartupF, ShutdownF                                                                                     func()
+	ContextF                                                                                                func() *snow.Context
+	NotifyF                                                                                                 func(Message)
+	GetAncestorsF, GetF, GetFailedF, PullQueryF                                                             func(validatorID ids.ShortID, requestID uint32, containerID ids.ID)
+	PutF, PushQueryF                                                                                        func(validatorID ids.ShortID, requestID uint32, containerID ids.ID, container []byte)
+	MultiPutF                                                                                               func(validatorID ids.ShortID, requestID uint32, containers [][]byte)
+	GetAcceptedFrontierF, GetAcceptedFrontierFailedF, GetAcceptedFailedF, GetAncestorsFailedF, QueryFailedF func(validatorID ids.ShortID, requestID uint32)
+	AcceptedFrontierF, GetAcceptedF, AcceptedF, ChitsF                                                      func(validatorID ids.ShortID, requestID uint32, containerIDs ids.Set)
 }
 
 // Default ...
@@ -64,6 +69,10 @@ func (e *EngineTest) Default(cant bool) {
 	e.CantGetAcceptedFailed = cant
 	e.CantAccepted = cant
 
+	e.CantGetAncestors = cant
+	e.CantGetAncestorsFailed = cant
+	e.CantMultiPut = cant
+
 	e.CantGet = cant
 	e.CantGetFailed = cant
 	e.CantPut = cant
@@ -166,6 +175,33 @@ func (e *EngineTest) Accepted(validatorID ids.ShortID, requestID uint32, contain
 	}
 }
 
+// GetAncestors ...
+func (e *EngineTest) GetAncestors(validatorID ids.ShortID, requestID uint32, containerID ids.ID) {
+	if e.GetAncestorsF != nil {
+		e.GetAncestorsF(validatorID, requestID, containerID)
+	} else if e.CantGetAncestors && e.T != nil {
+		e.T.Fatalf("Unexpectedly called GetAncestors")
+	}
+}
+
+// GetAncestorsFailed ...
+func (e *EngineTest) GetAncestorsFailed(validatorID ids.ShortID, requestID uint32) {
+	if e.GetAncestorsFailedF != nil {
+		e.GetAncestorsFailedF(validatorID, requestID)
+	} else if e.CantGetAncestorsFailed && e.T != nil {
+		e.T.Fatalf("Unexpectedly called GetAncestorsFailed")
+	}
+}
+
+// MultiPut ...
+func (e *EngineTest) MultiPut(validatorID ids.ShortID, requestID uint32, containers [][]byte) {
+	if e.MultiPutF != nil {
+		e.MultiPutF(validatorID, requestID, containers)
+	} else if e.CantMultiPut && e.T != nil {
+		e.T.Fatalf("Unexpectedly called MultiPut")
+	}
+}
+
 // Get ...
 func (e *EngineTest) Get(validatorID ids.ShortID, requestID uint32, containerID ids.ID) {
 	if e.GetF != nil {
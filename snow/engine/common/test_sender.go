@@ -15,6 +15,7 @@ type SenderTest struct {
 
 	CantGetAcceptedFrontier, CantAcceptedFrontier,
 	CantGetAccepted, CantAccepted,
+	CantGetAncestors, CantMultiPut,
 	CantGet, CantPut,
 	CantPullQuery, CantPushQuery, CantChits bool
 
@@ -22,6 +23,8 @@ type SenderTest struct {
 	AcceptedFrontierF    func(ids.ShortID, uint32, ids.Set)
 	GetAcceptedF         func(ids.ShortSet, uint32, ids.Set)
 	AcceptedF            func(ids.ShortID, uint32, ids.Set)
+	GetAncestorsF        func(ids.ShortID, uint32, ids.ID)
+	MultiPutF            func(ids.ShortID, uint32, [][]byte)
 	GetF                 func(ids.ShortID, uint32, ids.ID)
 	PutF                 func(ids.ShortID, uint32, ids.ID, []byte)
 	PushQueryF           func(ids.ShortSet, uint32, ids.ID, []byte)
@@ -35,6 +38,8 @@ func (s *SenderTest) Default(cant bool) {
 	s.CantAcceptedFrontier = cant
 	s.CantGetAccepted = cant
 	s.CantAccepted = cant
+	s.CantGetAncestors = cant
+	s.CantMultiPut = cant
 	s.CantGet = cant
 	s.CantPut = cant
 	s.CantPullQuery = cant
@@ -86,6 +91,28 @@ func (s *SenderTest) Accepted(validatorID ids.ShortID, requestID uint32, contain
 	}
 }
 
+// GetAncestors calls GetAncestorsF if it was initialized. If it wasn't
+// initialized and this function shouldn't be called and testing was
+// initialized, then testing will fail.
+func (s *SenderTest) GetAncestors(vdr ids.ShortID, requestID uint32, vtxID ids.ID) {
+	if s.GetAncestorsF != nil {
+		s.GetAncestorsF(vdr, requestID, vtxID)
+	} else if s.CantGetAncestors && s.T != nil {
+		s.T.Fatalf("Unexpectedly called GetAncestors")
+	}
+}
+
+// MultiPut calls MultiPutF if it was initialized. If it wasn't initialized and
+// this function shouldn't be called and testing was initialized, then testing
+// will fail.
+func (s *SenderTest) MultiPut(vdr ids.ShortID, requestID uint32, vtxs [][]byte) {
+	if s.MultiPutF != nil {
+		s.MultiPutF(vdr, requestID, vtxs)
+	} else if s.CantMultiPut && s.T != nil {
+		s.T.Fatalf("Unexpectedly called MultiPut")
+	}
+}
+
 // Get calls GetF if it was initialized. If it wasn't initialized and this
 // function shouldn't be called and testing was initialized, then testing will
 // fail.
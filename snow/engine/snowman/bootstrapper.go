@@ -4,13 +4,19 @@
 package snowman
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/snow/consensus/snowman"
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/snow/engine/common/queue"
 	"github.com/ava-labs/gecko/utils/formatting"
-	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	errNoAcceptedFrontier = errors.New("blocks were fetched, but the accepted frontier they were fetched from is unknown")
 )
 
 // BootstrapConfig ...
@@ -30,13 +36,19 @@ type bootstrapper struct {
 	metrics
 	common.Bootstrapper
 
-	pending    ids.Set
+	// outstandingRequests tracks which validators were asked for which blocks
+	// in which requests
+	outstandingRequests common.Requests
+
 	finished   bool
 	onFinished func()
 }
 
 // Initialize this engine.
 func (b *bootstrapper) Initialize(config BootstrapConfig) {
+	if config.Progress == nil {
+		config.Progress = &common.Progress{}
+	}
 	b.BootstrapConfig = config
 
 	b.Blocked.SetParser(&parser{
@@ -67,44 +79,147 @@ func (b *bootstrapper) FilterAccepted(containerIDs ids.Set) ids.Set {
 	return acceptedIDs
 }
 
+// ResumableFrontier ...
+func (b *bootstrapper) ResumableFrontier() (ids.Set, bool) {
+	acceptedFrontier, err := b.resumableFrontier()
+	if err != nil {
+		b.BootstrapConfig.Context.Log.Warn("Discarding bootstrapping progress due to %s", err)
+		if err := b.Blocked.Clear(); err != nil {
+			b.BootstrapConfig.Context.Log.Error("Failed to discard bootstrapping progress due to %s", err)
+		}
+		return nil, false
+	}
+	b.updateProgress()
+	return acceptedFrontier, acceptedFrontier.Len() > 0
+}
+
+// resumableFrontier returns the accepted frontier that was being fetched, or
+// an error if the persisted progress is invalid
+func (b *bootstrapper) resumableFrontier() (ids.Set, error) {
+	if err := b.Blocked.Verify(); err != nil {
+		return nil, err
+	}
+
+	acceptedFrontier, err := b.Blocked.Frontier()
+	if err != nil {
+		return nil, err
+	}
+	numFetched, err := b.Blocked.NumJobs()
+	if err != nil {
+		return nil, err
+	}
+	if numFetched > 0 && acceptedFrontier.Len() == 0 {
+		return nil, errNoAcceptedFrontier
+	}
+
+	// Blocks are accepted before their execution is committed, so the last
+	// block that was executed must have been accepted
+	numExecuted, err := b.Blocked.NumExecuted()
+	if err != nil {
+		return nil, err
+	}
+	if numExecuted > 0 {
+		blkID, err := b.Blocked.LastExecuted()
+		if err != nil {
+			return nil, err
+		}
+		if blk, err := b.VM.GetBlock(blkID); err != nil || blk.Status() != choices.Accepted {
+			return nil, fmt.Errorf("the last executed block %s isn't accepted", blkID)
+		}
+	}
+	return acceptedFrontier, nil
+}
+
 // ForceAccepted ...
 func (b *bootstrapper) ForceAccepted(acceptedContainerIDs ids.Set) {
-	for _, blkID := range acceptedContainerIDs.List() {
-		b.fetch(blkID)
+	if err := b.Blocked.SetFrontier(acceptedContainerIDs); err != nil {
+		b.BootstrapConfig.Context.Log.Error("Failed to persist the accepted frontier due to %s", err)
 	}
 
-	if numPending := b.pending.Len(); numPending == 0 {
-		// TODO: This typically indicates bootstrapping has failed, so this
-		// should be handled appropriately
-		b.finish()
+	// Blocks that were requested before bootstrapping was interrupted still
+	// need to be fetched
+	missingIDs, err := b.Blocked.MissingIDs()
+	if err != nil {
+		b.BootstrapConfig.Context.Log.Error("Failed to get the missing blocks due to %s", err)
+	}
+	toFetch := ids.Set{}
+	toFetch.Union(acceptedContainerIDs)
+	toFetch.Union(missingIDs)
+
+	for _, blkID := range toFetch.List() {
+		// The ancestors of a block that was already queued were either queued
+		// or marked as missing when it was
+		if has, err := b.Blocked.Has(blkID); err == nil && has {
+			continue
+		}
+		b.fetch(blkID)
 	}
+
+	// TODO: Having nothing to fetch typically indicates bootstrapping has
+	// failed, so this should be handled appropriately
+	b.checkpoint()
 }
 
-// Put ...
-func (b *bootstrapper) Put(vdr ids.ShortID, requestID uint32, blkID ids.ID, blkBytes []byte) {
-	b.BootstrapConfig.Context.Log.Verbo("Put called for blkID %s", blkID)
+// MultiPut handles the receipt of multiple blocks. Should be received in
+// response to a GetAncestors message to [vdr] with request ID [requestID].
+// The first block should be the requested block, followed by its ancestors.
+func (b *bootstrapper) MultiPut(vdr ids.ShortID, requestID uint32, blks [][]byte) {
+	if lenBlks := len(blks); lenBlks > common.MaxContainersPerMultiPut {
+		b.BootstrapConfig.Context.Log.Debug("MultiPut(%s, %d) contains more than maximum number of blocks", vdr, requestID)
+		b.GetAncestorsFailed(vdr, requestID)
+		return
+	}
 
-	if !b.pending.Contains(blkID) {
+	// Make sure this is in response to a request we made
+	wantedBlkID, ok := b.outstandingRequests.Remove(vdr, requestID)
+	if !ok {
+		b.BootstrapConfig.Context.Log.Debug("Received unexpected MultiPut from %s with request ID %d", vdr, requestID)
 		return
 	}
 
-	blk, err := b.VM.ParseBlock(blkBytes)
-	if err != nil {
-		b.BootstrapConfig.Context.Log.Warn("ParseBlock failed due to %s for block:\n%s",
-			err,
-			formatting.DumpBytes{Bytes: blkBytes})
-		b.GetFailed(vdr, requestID, blkID)
+	if len(blks) == 0 {
+		b.BootstrapConfig.Context.Log.Debug("MultiPut(%s, %d) contains no blocks", vdr, requestID)
+		b.sendRequest(wantedBlkID)
 		return
 	}
 
-	b.addBlock(blk)
+	// The blocks that were fetched, keyed by their IDs. Ancestors are looked
+	// up here first, as the VM may not know about blocks that were only parsed.
+	fetched := make(map[[32]byte]snowman.Block, len(blks))
+	for _, blkBytes := range blks {
+		blk, err := b.VM.ParseBlock(blkBytes)
+		if err != nil {
+			b.BootstrapConfig.Context.Log.Debug("Failed to parse block from MultiPut(%s, %d) due to %s", vdr, requestID, err)
+			b.BootstrapConfig.Context.Log.Verbo("block: %s", formatting.DumpBytes{Bytes: blkBytes})
+			break
+		}
+		fetched[blk.ID().Key()] = blk
+	}
+
+	wantedBlk, ok := fetched[wantedBlkID.Key()]
+	if !ok {
+		b.BootstrapConfig.Context.Log.Debug("MultiPut(%s, %d) didn't contain the requested block %s", vdr, requestID, wantedBlkID)
+		b.sendRequest(wantedBlkID)
+		return
+	}
+
+	b.process(wantedBlk, fetched)
+	b.checkpoint()
 }
 
-// GetFailed ...
-func (b *bootstrapper) GetFailed(_ ids.ShortID, _ uint32, blkID ids.ID) { b.sendRequest(blkID) }
+// GetAncestorsFailed is called when a GetAncestors message we sent fails
+func (b *bootstrapper) GetAncestorsFailed(vdr ids.ShortID, requestID uint32) {
+	blkID, ok := b.outstandingRequests.Remove(vdr, requestID)
+	if !ok {
+		b.BootstrapConfig.Context.Log.Debug("GetAncestorsFailed(%s, %d) called but there was no outstanding request to this validator with this ID", vdr, requestID)
+		return
+	}
+	// Send another request for this
+	b.sendRequest(blkID)
+}
 
 func (b *bootstrapper) fetch(blkID ids.ID) {
-	if b.pending.Contains(blkID) {
+	if b.outstandingRequests.Contains(blkID) {
 		return
 	}
 
@@ -113,10 +228,15 @@ func (b *bootstrapper) fetch(blkID ids.ID) {
 		b.sendRequest(blkID)
 		return
 	}
-	b.addBlock(blk)
+	b.process(blk, nil)
 }
 
+// sendRequest asks a validator for [blkID] and its ancestors
 func (b *bootstrapper) sendRequest(blkID ids.ID) {
+	if err := b.Blocked.AddMissingID(blkID); err != nil {
+		b.BootstrapConfig.Context.Log.Error("Failed to mark %s as missing due to %s", blkID, err)
+	}
+
 	validators := b.BootstrapConfig.Validators.Sample(1)
 	if len(validators) == 0 {
 		b.BootstrapConfig.Context.Log.Error("Dropping request for %s as there are no validators", blkID)
@@ -125,53 +245,112 @@ func (b *bootstrapper) sendRequest(blkID ids.ID) {
 	validatorID := validators[0].ID()
 	b.RequestID++
 
-	b.pending.Add(blkID)
-	b.BootstrapConfig.Sender.Get(validatorID, b.RequestID, blkID)
+	b.outstandingRequests.Add(validatorID, b.RequestID, blkID)
+	b.BootstrapConfig.Sender.GetAncestors(validatorID, b.RequestID, blkID)
 
-	b.numPendingRequests.Set(float64(b.pending.Len()))
+	b.numPendingRequests.Set(float64(b.outstandingRequests.Len()))
 }
 
-func (b *bootstrapper) addBlock(blk snowman.Block) {
+// process [blk] and its ancestors, until an accepted block is reached. The
+// ancestors are looked up in [fetched] first. If an ancestor isn't known, it's
+// requested.
+func (b *bootstrapper) process(blk snowman.Block, fetched map[[32]byte]snowman.Block) {
 	status := blk.Status()
 	blkID := blk.ID()
 	for status == choices.Processing {
-		b.pending.Remove(blkID)
+		b.outstandingRequests.RemoveAny(blkID)
+		if err := b.Blocked.RemoveMissingID(blkID); err != nil {
+			b.BootstrapConfig.Context.Log.Error("Failed to mark %s as fetched due to %s", blkID, err)
+		}
 
 		if err := b.Blocked.Push(&blockJob{
 			numAccepted: b.numBootstrapped,
 			numDropped:  b.numDropped,
 			blk:         blk,
-		}); err == nil {
-			b.numBlocked.Inc()
+		}); err != nil && err != queue.ErrDuplicate {
+			b.BootstrapConfig.Context.Log.Error("Failed to queue block %s due to %s", blkID, err)
 		}
 
-		blk = blk.Parent()
+		parent := blk.Parent()
+		if fetchedParent, ok := fetched[parent.ID().Key()]; ok {
+			parent = fetchedParent
+		}
+		blk = parent
 		status = blk.Status()
 		blkID = blk.ID()
 	}
 
-	switch status := blk.Status(); status {
+	switch status {
 	case choices.Unknown:
-		b.sendRequest(blkID)
+		if !b.outstandingRequests.Contains(blkID) {
+			b.sendRequest(blkID)
+		}
 	case choices.Accepted:
 		b.BootstrapConfig.Context.Log.Verbo("Bootstrapping confirmed %s", blkID)
 	case choices.Rejected:
 		b.BootstrapConfig.Context.Log.Error("Bootstrapping wants to accept %s, however it was previously rejected", blkID)
 	}
+}
+
+// checkpoint persists the progress of bootstrapping, so that it can be resumed
+// if it's interrupted. If there are no more blocks to fetch, bootstrapping is
+// finished.
+func (b *bootstrapper) checkpoint() {
+	if err := b.Blocked.Commit(); err != nil {
+		b.BootstrapConfig.Context.Log.Error("Failed to persist bootstrapping progress due to %s", err)
+	}
+	b.updateProgress()
 
-	numPending := b.pending.Len()
+	numPending := b.outstandingRequests.Len()
 	b.numPendingRequests.Set(float64(numPending))
 	if numPending == 0 {
 		b.finish()
 	}
 }
 
+// updateProgress reports how many blocks were fetched and executed
+func (b *bootstrapper) updateProgress() {
+	numFetched, err := b.Blocked.NumJobs()
+	if err != nil {
+		b.BootstrapConfig.Context.Log.Error("Failed to get the number of fetched blocks due to %s", err)
+		return
+	}
+	numExecuted, err := b.Blocked.NumExecuted()
+	if err != nil {
+		b.BootstrapConfig.Context.Log.Error("Failed to get the number of executed blocks due to %s", err)
+		return
+	}
+
+	progress := b.BootstrapConfig.Progress
+	progress.Update(uint64(numFetched), uint64(numExecuted))
+	status := progress.Status()
+
+	b.numFetched.Set(float64(numFetched))
+	b.numExecuted.Set(float64(numExecuted))
+	b.numBlocked.Set(float64(numFetched - numExecuted))
+	if status.ETAKnown {
+		b.bootstrapETA.Set(status.ETA.Seconds())
+	} else {
+		b.bootstrapETA.Set(0)
+	}
+}
+
 func (b *bootstrapper) finish() {
 	if b.finished {
 		return
 	}
 
-	b.executeAll(b.Blocked, b.numBlocked)
+	b.BootstrapConfig.Progress.StartExecuting()
+	b.executeAll()
+
+	// Everything that was fetched was executed, so there's nothing to resume
+	if err := b.Blocked.Clear(); err != nil {
+		b.BootstrapConfig.Context.Log.Error("Failed to clear bootstrapping progress due to %s", err)
+	}
+	if b.Bootstrapper.Resync() {
+		return
+	}
+	b.BootstrapConfig.Progress.Finish()
 
 	// Start consensus
 	b.onFinished()
@@ -182,11 +361,22 @@ func (b *bootstrapper) finish() {
 	}
 }
 
-func (b *bootstrapper) executeAll(jobs *queue.Jobs, numBlocked prometheus.Gauge) {
-	for job, err := jobs.Pop(); err == nil; job, err = jobs.Pop() {
-		numBlocked.Dec()
-		if err := jobs.Execute(job); err != nil {
+func (b *bootstrapper) executeAll() {
+	numExecuted := 0
+	for job, err := b.Blocked.Pop(); err == nil; job, err = b.Blocked.Pop() {
+		if err := b.Blocked.Execute(job); err != nil {
 			b.BootstrapConfig.Context.Log.Warn("Error executing: %s", err)
 		}
+
+		if numExecuted++; numExecuted%common.CheckpointFrequency == 0 {
+			if err := b.Blocked.Commit(); err != nil {
+				b.BootstrapConfig.Context.Log.Error("Failed to persist bootstrapping progress due to %s", err)
+			}
+			b.updateProgress()
+
+			status := b.BootstrapConfig.Progress.Status()
+			b.BootstrapConfig.Context.Log.Info("Executed %d of %d blocks. ETA: %s", status.Executed, status.Fetched, status.ETA)
+		}
 	}
+	b.updateProgress()
 }
@@ -52,7 +52,7 @@ func newConfig(t *testing.T) (BootstrapConfig, ids.ShortID, *common.SenderTest,
 	peerID := peer.ID()
 	peers.Add(peer)
 
-	handler.Initialize(engine, make(chan common.Message), 1)
+	handler.Initialize(engine, peers, make(chan common.Message), 1, 1, "", prometheus.NewRegistry())
 	timeouts.Initialize(0)
 	router.Initialize(ctx.Log, timeouts)
 
@@ -62,7 +62,7 @@ func newConfig(t *testing.T) (BootstrapConfig, ids.ShortID, *common.SenderTest,
 		Context:    ctx,
 		Validators: peers,
 		Beacons:    peers,
-		Alpha:      peers.Len()/2 + 1,
+		Alpha:      0.5,
 		Sender:     sender,
 	}
 	return BootstrapConfig{
@@ -113,7 +113,7 @@ func TestBootstrapperSingleFrontier(t *testing.T) {
 	}
 
 	reqID := new(uint32)
-	sender.GetF = func(vdr ids.ShortID, innerReqID uint32, blkID ids.ID) {
+	sender.GetAncestorsF = func(vdr ids.ShortID, innerReqID uint32, blkID ids.ID) {
 		if !vdr.Equals(peerID) {
 			t.Fatalf("Should have requested block from %s, requested from %s", peerID, vdr)
 		}
@@ -129,7 +129,7 @@ func TestBootstrapperSingleFrontier(t *testing.T) {
 	bs.ForceAccepted(acceptedIDs)
 
 	vm.GetBlockF = nil
-	sender.GetF = nil
+	sender.GetAncestorsF = nil
 
 	vm.ParseBlockF = func(blkBytes []byte) (snowman.Block, error) {
 		switch {
@@ -143,7 +143,7 @@ func TestBootstrapperSingleFrontier(t *testing.T) {
 	finished := new(bool)
 	bs.onFinished = func() { *finished = true }
 
-	bs.Put(peerID, *reqID, blkID1, blkBytes1)
+	bs.MultiPut(peerID, *reqID, [][]byte{blkBytes1})
 
 	vm.ParseBlockF = nil
 	bs.onFinished = nil
@@ -206,7 +206,7 @@ func TestBootstrapperUnknownByzantineResponse(t *testing.T) {
 	}
 
 	requestID := new(uint32)
-	sender.GetF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
 		if !vdr.Equals(peerID) {
 			t.Fatalf("Should have requested block from %s, requested from %s", peerID, vdr)
 		}
@@ -222,12 +222,13 @@ func TestBootstrapperUnknownByzantineResponse(t *testing.T) {
 	bs.ForceAccepted(acceptedIDs)
 
 	vm.GetBlockF = nil
-	sender.GetF = nil
 
 	vm.ParseBlockF = func(blkBytes []byte) (snowman.Block, error) {
 		switch {
 		case bytes.Equal(blkBytes, blkBytes1):
 			return blk1, nil
+		case bytes.Equal(blkBytes, blkBytes2):
+			return blk2, nil
 		}
 		t.Fatal(errUnknownBlock)
 		return nil, errUnknownBlock
@@ -236,8 +237,21 @@ func TestBootstrapperUnknownByzantineResponse(t *testing.T) {
 	finished := new(bool)
 	bs.onFinished = func() { *finished = true }
 
-	bs.Put(peerID, *requestID, blkID2, blkBytes2)
-	bs.Put(peerID, *requestID, blkID1, blkBytes1)
+	// A response to a request that wasn't made should be dropped
+	bs.MultiPut(peerID, *requestID+1, [][]byte{blkBytes1})
+	if *finished {
+		t.Fatalf("Bootstrapping should have dropped the unrequested response")
+	}
+
+	// A response without the requested block should cause it to be requested
+	// again
+	oldReqID := *requestID
+	bs.MultiPut(peerID, *requestID, [][]byte{blkBytes2})
+	if oldReqID == *requestID {
+		t.Fatalf("Should have requested the block again")
+	}
+
+	bs.MultiPut(peerID, *requestID, [][]byte{blkBytes1})
 
 	vm.ParseBlockF = nil
 
@@ -302,7 +316,7 @@ func TestBootstrapperDependency(t *testing.T) {
 	}
 
 	requestID := new(uint32)
-	sender.GetF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) {
 		if !vdr.Equals(peerID) {
 			t.Fatalf("Should have requested block from %s, requested from %s", peerID, vdr)
 		}
@@ -318,7 +332,7 @@ func TestBootstrapperDependency(t *testing.T) {
 	bs.ForceAccepted(acceptedIDs)
 
 	vm.GetBlockF = nil
-	sender.GetF = nil
+	sender.GetAncestorsF = nil
 
 	vm.ParseBlockF = func(blkBytes []byte) (snowman.Block, error) {
 		switch {
@@ -336,7 +350,7 @@ func TestBootstrapperDependency(t *testing.T) {
 	finished := new(bool)
 	bs.onFinished = func() { *finished = true }
 
-	bs.Put(peerID, *requestID, blkID1, blkBytes1)
+	bs.MultiPut(peerID, *requestID, [][]byte{blkBytes1})
 
 	if !*finished {
 		t.Fatalf("Bootstrapping should have finished")
@@ -349,6 +363,343 @@ func TestBootstrapperDependency(t *testing.T) {
 	}
 }
 
+func TestBootstrapperMultiPut(t *testing.T) {
+	config, peerID, sender, vm := newConfig(t)
+
+	blkID0 := ids.Empty.Prefix(0)
+	blkID1 := ids.Empty.Prefix(1)
+	blkID2 := ids.Empty.Prefix(2)
+	blkID3 := ids.Empty.Prefix(3)
+
+	blkBytes0 := []byte{0}
+	blkBytes1 := []byte{1}
+	blkBytes2 := []byte{2}
+	blkBytes3 := []byte{3}
+
+	blk0 := &Blk{
+		id:     blkID0,
+		height: 0,
+		status: choices.Accepted,
+		bytes:  blkBytes0,
+	}
+	blk1 := &Blk{
+		parent: blk0,
+		id:     blkID1,
+		height: 1,
+		status: choices.Unknown,
+		bytes:  blkBytes1,
+	}
+	blk2 := &Blk{
+		parent: blk1,
+		id:     blkID2,
+		height: 2,
+		status: choices.Unknown,
+		bytes:  blkBytes2,
+	}
+	blk3 := &Blk{
+		parent: blk2,
+		id:     blkID3,
+		height: 3,
+		status: choices.Unknown,
+		bytes:  blkBytes3,
+	}
+
+	bs := bootstrapper{}
+	bs.metrics.Initialize(config.Context.Log, fmt.Sprintf("gecko_%s", config.Context.ChainID), prometheus.NewRegistry())
+	bs.Initialize(config)
+
+	acceptedIDs := ids.Set{}
+	acceptedIDs.Add(blkID3)
+
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		switch {
+		case blkID.Equals(blkID3):
+			return nil, errUnknownBlock
+		default:
+			t.Fatal(errUnknownBlock)
+			panic(errUnknownBlock)
+		}
+	}
+
+	requested := ids.ID{}
+	requestID := new(uint32)
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, blkID ids.ID) {
+		if !vdr.Equals(peerID) {
+			t.Fatalf("Should have requested block from %s, requested from %s", peerID, vdr)
+		}
+		switch {
+		case blkID.Equals(blkID1), blkID.Equals(blkID3):
+		default:
+			t.Fatalf("Requested unknown block")
+		}
+
+		requested = blkID
+		*requestID = reqID
+	}
+
+	bs.ForceAccepted(acceptedIDs)
+
+	vm.GetBlockF = nil
+
+	if !requested.Equals(blkID3) {
+		t.Fatalf("Should have requested block %s", blkID3)
+	}
+
+	vm.ParseBlockF = func(blkBytes []byte) (snowman.Block, error) {
+		switch {
+		case bytes.Equal(blkBytes, blkBytes1):
+			blk1.status = choices.Processing
+			return blk1, nil
+		case bytes.Equal(blkBytes, blkBytes2):
+			blk2.status = choices.Processing
+			return blk2, nil
+		case bytes.Equal(blkBytes, blkBytes3):
+			blk3.status = choices.Processing
+			return blk3, nil
+		}
+		t.Fatal(errUnknownBlock)
+		return nil, errUnknownBlock
+	}
+
+	finished := new(bool)
+	bs.onFinished = func() { *finished = true }
+
+	// The response contains the requested block and one of its ancestors, so
+	// only the remaining ancestor should be requested
+	bs.MultiPut(peerID, *requestID, [][]byte{blkBytes3, blkBytes2})
+
+	if *finished {
+		t.Fatalf("Bootstrapping shouldn't have finished")
+	}
+	if !requested.Equals(blkID1) {
+		t.Fatalf("Should have requested block %s", blkID1)
+	}
+
+	bs.MultiPut(peerID, *requestID, [][]byte{blkBytes1})
+
+	if !*finished {
+		t.Fatalf("Bootstrapping should have finished")
+	}
+	for _, blk := range []*Blk{blk1, blk2, blk3} {
+		if blk.Status() != choices.Accepted {
+			t.Fatalf("Block %s should be accepted", blk.ID())
+		}
+	}
+}
+
+func TestBootstrapperResume(t *testing.T) {
+	config, peerID, sender, vm := newConfig(t)
+
+	db := memdb.New()
+	config.Blocked, _ = queue.New(db)
+
+	blkID0 := ids.Empty.Prefix(0)
+	blkID1 := ids.Empty.Prefix(1)
+	blkID2 := ids.Empty.Prefix(2)
+	blkID3 := ids.Empty.Prefix(3)
+
+	blkBytes0 := []byte{0}
+	blkBytes1 := []byte{1}
+	blkBytes2 := []byte{2}
+	blkBytes3 := []byte{3}
+
+	blk0 := &Blk{
+		id:     blkID0,
+		height: 0,
+		status: choices.Accepted,
+		bytes:  blkBytes0,
+	}
+	blk1 := &Blk{
+		parent: blk0,
+		id:     blkID1,
+		height: 1,
+		status: choices.Unknown,
+		bytes:  blkBytes1,
+	}
+	blk2 := &Blk{
+		parent: blk1,
+		id:     blkID2,
+		height: 2,
+		status: choices.Unknown,
+		bytes:  blkBytes2,
+	}
+	blk3 := &Blk{
+		parent: blk2,
+		id:     blkID3,
+		height: 3,
+		status: choices.Unknown,
+		bytes:  blkBytes3,
+	}
+
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		switch {
+		case blkID.Equals(blkID1), blkID.Equals(blkID3):
+			return nil, errUnknownBlock
+		default:
+			t.Fatal(errUnknownBlock)
+			panic(errUnknownBlock)
+		}
+	}
+	vm.ParseBlockF = func(blkBytes []byte) (snowman.Block, error) {
+		switch {
+		case bytes.Equal(blkBytes, blkBytes1):
+			blk1.status = choices.Processing
+			return blk1, nil
+		case bytes.Equal(blkBytes, blkBytes2):
+			blk2.status = choices.Processing
+			return blk2, nil
+		case bytes.Equal(blkBytes, blkBytes3):
+			blk3.status = choices.Processing
+			return blk3, nil
+		}
+		t.Fatal(errUnknownBlock)
+		return nil, errUnknownBlock
+	}
+
+	requested := ids.ID{}
+	requestID := new(uint32)
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, blkID ids.ID) {
+		requested = blkID
+		*requestID = reqID
+	}
+
+	bs := bootstrapper{}
+	bs.metrics.Initialize(config.Context.Log, fmt.Sprintf("gecko_%s", config.Context.ChainID), prometheus.NewRegistry())
+	bs.Initialize(config)
+
+	acceptedIDs := ids.Set{}
+	acceptedIDs.Add(blkID3)
+
+	bs.ForceAccepted(acceptedIDs)
+	bs.MultiPut(peerID, *requestID, [][]byte{blkBytes3, blkBytes2})
+
+	if !requested.Equals(blkID1) {
+		t.Fatalf("Should have requested block %s", blkID1)
+	}
+
+	// Bootstrapping is interrupted by the node restarting, which forgets about
+	// the blocks that were only parsed
+	blk2.status = choices.Unknown
+	blk3.status = choices.Unknown
+	config.Blocked, _ = queue.New(db)
+	requested = ids.ID{}
+
+	bs = bootstrapper{}
+	bs.metrics.Initialize(config.Context.Log, fmt.Sprintf("gecko_%s", config.Context.ChainID), prometheus.NewRegistry())
+	bs.Initialize(config)
+
+	finished := new(bool)
+	bs.onFinished = func() { *finished = true }
+
+	sender.GetAcceptedFrontierF = func(ids.ShortSet, uint32) {
+		t.Fatalf("Should have resumed from the persisted accepted frontier")
+	}
+
+	bs.Startup()
+
+	// Only the block that was missing should be requested again
+	if !requested.Equals(blkID1) {
+		t.Fatalf("Should have requested block %s", blkID1)
+	}
+	if status := bs.BootstrapConfig.Progress.Status(); status.Fetched != 2 || status.Executed != 0 {
+		t.Fatalf("Wrong progress. Expected: 2 fetched, 0 executed ; Returned: %d fetched, %d executed", status.Fetched, status.Executed)
+	}
+
+	frontierRequested := new(bool)
+	sender.GetAcceptedFrontierF = func(_ ids.ShortSet, reqID uint32) {
+		*frontierRequested = true
+		*requestID = reqID
+	}
+
+	bs.MultiPut(peerID, *requestID, [][]byte{blkBytes1})
+
+	// The network may have accepted more blocks since the resumed accepted
+	// frontier was agreed on, so the current one must be bootstrapped too
+	if *finished {
+		t.Fatalf("Bootstrapping shouldn't have finished before requesting the current accepted frontier")
+	}
+	if !*frontierRequested {
+		t.Fatalf("Should have requested the current accepted frontier")
+	}
+	if status := bs.BootstrapConfig.Progress.Status(); status.Bootstrapped || status.Fetched != 3 || status.Executed != 3 {
+		t.Fatalf("Wrong progress. Expected: not bootstrapped, 3 fetched, 3 executed ; Returned: %d fetched, %d executed", status.Fetched, status.Executed)
+	}
+
+	sender.GetAcceptedF = func(_ ids.ShortSet, reqID uint32, _ ids.Set) { *requestID = reqID }
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		if blkID.Equals(blkID3) {
+			return blk3, nil
+		}
+		t.Fatal(errUnknownBlock)
+		panic(errUnknownBlock)
+	}
+
+	bs.AcceptedFrontier(peerID, *requestID, acceptedIDs)
+	bs.Accepted(peerID, *requestID, acceptedIDs)
+
+	if !*finished {
+		t.Fatalf("Bootstrapping should have finished")
+	}
+	for _, blk := range []*Blk{blk1, blk2, blk3} {
+		if blk.Status() != choices.Accepted {
+			t.Fatalf("Block %s should be accepted", blk.ID())
+		}
+	}
+	if status := bs.BootstrapConfig.Progress.Status(); !status.Bootstrapped {
+		t.Fatalf("Progress should report that bootstrapping finished")
+	}
+
+	// There's nothing to resume after bootstrapping finished
+	if _, resume := bs.ResumableFrontier(); resume {
+		t.Fatalf("Shouldn't resume bootstrapping after it finished")
+	}
+}
+
+func TestBootstrapperDiscardInvalidProgress(t *testing.T) {
+	config, _, sender, _ := newConfig(t)
+
+	blk0 := &Blk{
+		id:     ids.Empty.Prefix(0),
+		height: 0,
+		status: choices.Accepted,
+		bytes:  []byte{0},
+	}
+	blk1 := &Blk{
+		parent: blk0,
+		id:     ids.Empty.Prefix(1),
+		height: 1,
+		status: choices.Processing,
+		bytes:  []byte{1},
+	}
+
+	// A block was fetched without persisting the accepted frontier it was
+	// fetched from
+	if err := config.Blocked.Push(&blockJob{blk: blk1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Blocked.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	bs := bootstrapper{}
+	bs.metrics.Initialize(config.Context.Log, fmt.Sprintf("gecko_%s", config.Context.ChainID), prometheus.NewRegistry())
+	bs.Initialize(config)
+
+	polled := new(bool)
+	sender.GetAcceptedFrontierF = func(ids.ShortSet, uint32) { *polled = true }
+
+	bs.Startup()
+
+	if !*polled {
+		t.Fatalf("Should have asked the beacons for their accepted frontier")
+	}
+	if numJobs, err := config.Blocked.NumJobs(); err != nil {
+		t.Fatal(err)
+	} else if numJobs != 0 {
+		t.Fatalf("Should have discarded the invalid progress")
+	}
+}
+
 func TestBootstrapperAcceptedFrontier(t *testing.T) {
 	config, _, _, vm := newConfig(t)
 
@@ -13,6 +13,8 @@ type metrics struct {
 	numPendingRequests, numBlocked prometheus.Gauge
 	numBootstrapped, numDropped    prometheus.Counter
 
+	numFetched, numExecuted, bootstrapETA prometheus.Gauge
+
 	numPolls, numBlkRequests, numBlockedBlk prometheus.Gauge
 }
 
@@ -42,6 +44,24 @@ func (m *metrics) Initialize(log logging.Logger, namespace string, registerer pr
 			Name:      "sm_bs_dropped",
 			Help:      "Number of dropped bootstrap blocks",
 		})
+	m.numFetched = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "sm_bs_fetched",
+			Help:      "Number of blocks fetched while bootstrapping",
+		})
+	m.numExecuted = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "sm_bs_executed",
+			Help:      "Number of fetched blocks executed while bootstrapping",
+		})
+	m.bootstrapETA = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "sm_bs_eta",
+			Help:      "Estimated number of seconds until the fetched blocks are executed, or 0 if unknown",
+		})
 	m.numPolls = prometheus.NewGauge(
 		prometheus.GaugeOpts{
 			Namespace: namespace,
@@ -73,6 +93,15 @@ func (m *metrics) Initialize(log logging.Logger, namespace string, registerer pr
 	if err := registerer.Register(m.numDropped); err != nil {
 		log.Error("Failed to register sm_bs_dropped statistics due to %s", err)
 	}
+	if err := registerer.Register(m.numFetched); err != nil {
+		log.Error("Failed to register sm_bs_fetched statistics due to %s", err)
+	}
+	if err := registerer.Register(m.numExecuted); err != nil {
+		log.Error("Failed to register sm_bs_executed statistics due to %s", err)
+	}
+	if err := registerer.Register(m.bootstrapETA); err != nil {
+		log.Error("Failed to register sm_bs_eta statistics due to %s", err)
+	}
 	if err := registerer.Register(m.numPolls); err != nil {
 		log.Error("Failed to register sm_polls statistics due to %s", err)
 	}
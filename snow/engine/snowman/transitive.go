@@ -11,6 +11,7 @@ import (
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/snow/events"
 	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/wrappers"
 )
 
 // Transitive implements the Engine interface by attempting to fetch all
@@ -60,6 +61,53 @@ func (t *Transitive) Shutdown() {
 // Context implements the Engine interface
 func (t *Transitive) Context() *snow.Context { return t.Config.Context }
 
+// GetAncestors implements the Engine interface
+func (t *Transitive) GetAncestors(vdr ids.ShortID, requestID uint32, blkID ids.ID) {
+	blk, err := t.Config.VM.GetBlock(blkID)
+	if err != nil { // Don't have the block. Drop this request.
+		t.Config.Context.Log.Verbo("couldn't get block %s. dropping GetAncestors(%s, %d, %s)", blkID, vdr, requestID, blkID)
+		return
+	}
+
+	// The block, followed by its parent, grandparent, etc.
+	ancestorsBytes := [][]byte{blk.Bytes()}
+	ancestorsBytesLen := len(blk.Bytes()) + wrappers.IntLen
+	for len(ancestorsBytes) < common.MaxContainersPerMultiPut {
+		blk = blk.Parent()
+		if blk == nil || blk.Status() == choices.Unknown {
+			break
+		}
+		blkBytes := blk.Bytes()
+		// Include the length prefix of the block
+		newLen := ancestorsBytesLen + len(blkBytes) + wrappers.IntLen
+		if newLen > common.MaxContainersLen {
+			break
+		}
+		ancestorsBytes = append(ancestorsBytes, blkBytes)
+		ancestorsBytesLen = newLen
+	}
+
+	t.Config.Sender.MultiPut(vdr, requestID, ancestorsBytes)
+}
+
+// MultiPut implements the Engine interface
+func (t *Transitive) MultiPut(vdr ids.ShortID, requestID uint32, blks [][]byte) {
+	if t.bootstrapped {
+		t.Config.Context.Log.Debug("Dropping MultiPut(%s, %d) as bootstrapping has finished", vdr, requestID)
+		return
+	}
+	t.bootstrapper.MultiPut(vdr, requestID, blks)
+}
+
+// GetAncestorsFailed implements the Engine interface
+func (t *Transitive) GetAncestorsFailed(vdr ids.ShortID, requestID uint32) {
+	if t.bootstrapped {
+		t.Config.Context.Log.Debug("Dropping GetAncestorsFailed(%s, %d) as bootstrapping has finished", vdr, requestID)
+		return
+	}
+	t.bootstrapper.GetAncestorsFailed(vdr, requestID)
+}
+
 // Get implements the Engine interface
 func (t *Transitive) Get(vdr ids.ShortID, requestID uint32, blkID ids.ID) {
 	if blk, err := t.Config.VM.GetBlock(blkID); err == nil {
@@ -72,7 +120,12 @@ func (t *Transitive) Put(vdr ids.ShortID, requestID uint32, blkID ids.ID, blkByt
 	t.Config.Context.Log.Verbo("Put called for blockID %s", blkID)
 
 	if !t.bootstrapped {
-		t.bootstrapper.Put(vdr, requestID, blkID, blkBytes)
+		// Request IDs aren't tied to message types, so this Put may be the
+		// response to an outstanding GetAncestors request. Its timeout was
+		// cancelled when this Put arrived, so the request must be failed here
+		// or bootstrapping would wait on it forever.
+		t.Config.Context.Log.Debug("Dropping Put for %s due to bootstrapping", blkID)
+		t.bootstrapper.GetAncestorsFailed(vdr, requestID)
 		return
 	}
 
@@ -91,7 +144,10 @@ func (t *Transitive) Put(vdr ids.ShortID, requestID uint32, blkID ids.ID, blkByt
 // GetFailed implements the Engine interface
 func (t *Transitive) GetFailed(vdr ids.ShortID, requestID uint32, blkID ids.ID) {
 	if !t.bootstrapped {
-		t.bootstrapper.GetFailed(vdr, requestID, blkID)
+		// As with Put, this may correspond to an outstanding GetAncestors
+		// request
+		t.Config.Context.Log.Debug("Dropping GetFailed for %s due to bootstrapping", blkID)
+		t.bootstrapper.GetAncestorsFailed(vdr, requestID)
 		return
 	}
 
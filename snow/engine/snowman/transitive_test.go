@@ -536,6 +536,60 @@ func TestEngineFetchBlock(t *testing.T) {
 	}
 }
 
+func TestEngineGetAncestors(t *testing.T) {
+	vdr, _, sender, vm, te, gBlk := setup(t)
+
+	sender.Default(false)
+
+	blk1 := &Blk{
+		parent: gBlk,
+		id:     GenerateID(),
+		status: choices.Accepted,
+		bytes:  []byte{1},
+	}
+	blk2 := &Blk{
+		parent: blk1,
+		id:     GenerateID(),
+		status: choices.Processing,
+		bytes:  []byte{2},
+	}
+
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		if blkID.Equals(blk2.ID()) {
+			return blk2, nil
+		}
+		t.Fatalf("Unknown block")
+		panic("Should have failed")
+	}
+
+	sent := new(bool)
+	sender.MultiPutF = func(inVdr ids.ShortID, requestID uint32, blks [][]byte) {
+		if !vdr.ID().Equals(inVdr) {
+			t.Fatalf("Wrong validator")
+		}
+		if requestID != 123 {
+			t.Fatalf("Wrong request id")
+		}
+		// The genesis block has no bytes
+		expected := [][]byte{blk2.Bytes(), blk1.Bytes(), gBlk.Bytes()}
+		if len(blks) != len(expected) {
+			t.Fatalf("Should have sent %d blocks, sent %d", len(expected), len(blks))
+		}
+		for i, blkBytes := range expected {
+			if !bytes.Equal(blks[i], blkBytes) {
+				t.Fatalf("Sent the wrong block at index %d", i)
+			}
+		}
+		*sent = true
+	}
+
+	te.GetAncestors(vdr.ID(), 123, blk2.ID())
+
+	if !*sent {
+		t.Fatalf("Should have sent the ancestors to the peer")
+	}
+}
+
 func TestEnginePushQuery(t *testing.T) {
 	vdr, _, sender, vm, te, gBlk := setup(t)
 
@@ -1076,3 +1130,68 @@ func TestEngineRetryFetch(t *testing.T) {
 		t.Fatalf("Should have requested the block again")
 	}
 }
+
+func TestEngineBootstrappingPutFailsAncestorsRequest(t *testing.T) {
+	config := DefaultConfig()
+
+	vdr := validators.GenerateRandomValidator(1)
+	vdrID := vdr.ID()
+
+	vals := validators.NewSet()
+	config.Validators = vals
+	config.Beacons = vals
+
+	vals.Add(vdr)
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+
+	sender.Default(true)
+	sender.CantGetAcceptedFrontier = false
+
+	vm := &VMTest{}
+	vm.T = t
+	config.VM = vm
+
+	vm.Default(true)
+
+	blkID := GenerateID()
+
+	te := &Transitive{}
+	te.Initialize(config)
+
+	vm.GetBlockF = func(ids.ID) (snowman.Block, error) { return nil, errUnknownBlock }
+
+	requested := new(int)
+	requestID := new(uint32)
+	sender.GetAncestorsF = func(inVdr ids.ShortID, reqID uint32, inBlkID ids.ID) {
+		if !blkID.Equals(inBlkID) {
+			t.Fatalf("Asking for wrong block")
+		}
+		*requested++
+		*requestID = reqID
+	}
+
+	acceptedIDs := ids.Set{}
+	acceptedIDs.Add(blkID)
+	te.ForceAccepted(acceptedIDs)
+
+	if *requested != 1 {
+		t.Fatalf("Should have requested the block's ancestors")
+	}
+
+	// A Put with the request ID of the GetAncestors request cancels the
+	// request's timeout, so the request must be sent again
+	te.Put(vdrID, *requestID, blkID, []byte{0})
+
+	if *requested != 2 {
+		t.Fatalf("Should have requested the block's ancestors again after Put")
+	}
+
+	te.GetFailed(vdrID, *requestID, blkID)
+
+	if *requested != 3 {
+		t.Fatalf("Should have requested the block's ancestors again after GetFailed")
+	}
+}
@@ -0,0 +1,17 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package networking
+
+import (
+	"github.com/ava-labs/gecko/ids"
+)
+
+// Connector is notified when this node finishes the handshake with a peer, and
+// when the connection to a peer closes. Its methods are called while the
+// network's locks are held, so they must return quickly and must not call
+// back into the network.
+type Connector interface {
+	Connected(validatorID ids.ShortID)
+	Disconnected(validatorID ids.ShortID)
+}
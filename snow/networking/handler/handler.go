@@ -6,27 +6,50 @@ package handler
 import (
 	"sync"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/snow/validators"
+	"github.com/ava-labs/gecko/utils/timer"
 )
 
 // Handler passes incoming messages from the network to the consensus engine
 // (Actually, it receives the incoming messages from a ChainRouter, but same difference)
 type Handler struct {
-	msgs    chan message
+	metrics
+
+	msgs    messageQueue
 	wg      sync.WaitGroup
+	closed  chan struct{}
+	clock   timer.Clock
 	engine  common.Engine
 	msgChan <-chan common.Message
 }
 
-// Initialize this consensus handler
-func (h *Handler) Initialize(engine common.Engine, msgChan <-chan common.Message, bufferSize int) {
-	h.msgs = make(chan message, bufferSize)
+// Initialize this consensus handler.
+// [validators] are the validators of the chain, whose stake determines how
+// often their messages are dispatched. At most [bufferSize] messages from
+// peers, and at most [maxPeerMessages] messages from each peer, are pending
+// at a time. Messages beyond that are dropped.
+func (h *Handler) Initialize(
+	engine common.Engine,
+	validators validators.Set,
+	msgChan <-chan common.Message,
+	bufferSize int,
+	maxPeerMessages int,
+	namespace string,
+	metrics prometheus.Registerer,
+) error {
+	h.msgs.initialize(validators, &h.metrics, bufferSize, maxPeerMessages)
 	h.engine = engine
 	h.msgChan = msgChan
+	h.closed = make(chan struct{})
 
 	h.wg.Add(1)
+
+	return h.metrics.Initialize(namespace, metrics)
 }
 
 // Context of this Handler
@@ -36,17 +59,34 @@ func (h *Handler) Context() *snow.Context { return h.engine.Context() }
 // and, when they arrive, sends them to the consensus engine
 func (h *Handler) Dispatch() {
 	defer h.wg.Done()
+	defer close(h.closed)
+
+	if h.msgChan != nil {
+		go h.forwardNotifications()
+	}
+
+	for {
+		msg := h.msgs.pop()
+
+		start := h.clock.Time()
+		if !h.dispatchMsg(msg) {
+			return
+		}
+		if !msg.internal() {
+			h.msgs.processed(msg.validatorID, h.clock.Time().Sub(start))
+		}
+	}
+}
 
+// forwardNotifications queues the messages the VM sends to the consensus
+// engine, until the handler shuts down
+func (h *Handler) forwardNotifications() {
 	for {
 		select {
-		case msg := <-h.msgs:
-			if !h.dispatchMsg(msg) {
-				return
-			}
 		case msg := <-h.msgChan:
-			if !h.dispatchMsg(message{messageType: notifyMsg, notification: msg}) {
-				return
-			}
+			h.Notify(msg)
+		case <-h.closed:
+			return
 		}
 	}
 }
@@ -75,6 +115,12 @@ func (h *Handler) dispatchMsg(msg message) bool {
 		h.engine.Accepted(msg.validatorID, msg.requestID, msg.containerIDs)
 	case getAcceptedFailedMsg:
 		h.engine.GetAcceptedFailed(msg.validatorID, msg.requestID)
+	case getAncestorsMsg:
+		h.engine.GetAncestors(msg.validatorID, msg.requestID, msg.containerID)
+	case multiPutMsg:
+		h.engine.MultiPut(msg.validatorID, msg.requestID, msg.containers)
+	case getAncestorsFailedMsg:
+		h.engine.GetAncestorsFailed(msg.validatorID, msg.requestID)
 	case getMsg:
 		h.engine.Get(msg.validatorID, msg.requestID, msg.containerID)
 	case getFailedMsg:
@@ -101,144 +147,180 @@ func (h *Handler) dispatchMsg(msg message) bool {
 // GetAcceptedFrontier passes a GetAcceptedFrontier message received from the
 // network to the consensus engine.
 func (h *Handler) GetAcceptedFrontier(validatorID ids.ShortID, requestID uint32) {
-	h.msgs <- message{
+	h.push(message{
 		messageType: getAcceptedFrontierMsg,
 		validatorID: validatorID,
 		requestID:   requestID,
-	}
+	})
 }
 
 // AcceptedFrontier passes a AcceptedFrontier message received from the network
 // to the consensus engine.
 func (h *Handler) AcceptedFrontier(validatorID ids.ShortID, requestID uint32, containerIDs ids.Set) {
-	h.msgs <- message{
+	h.push(message{
 		messageType:  acceptedFrontierMsg,
 		validatorID:  validatorID,
 		requestID:    requestID,
 		containerIDs: containerIDs,
-	}
+	})
 }
 
 // GetAcceptedFrontierFailed passes a GetAcceptedFrontierFailed message received
 // from the network to the consensus engine.
 func (h *Handler) GetAcceptedFrontierFailed(validatorID ids.ShortID, requestID uint32) {
-	h.msgs <- message{
+	h.push(message{
 		messageType: getAcceptedFrontierFailedMsg,
 		validatorID: validatorID,
 		requestID:   requestID,
-	}
+	})
 }
 
 // GetAccepted passes a GetAccepted message received from the
 // network to the consensus engine.
 func (h *Handler) GetAccepted(validatorID ids.ShortID, requestID uint32, containerIDs ids.Set) {
-	h.msgs <- message{
+	h.push(message{
 		messageType:  getAcceptedMsg,
 		validatorID:  validatorID,
 		requestID:    requestID,
 		containerIDs: containerIDs,
-	}
+	})
 }
 
 // Accepted passes a Accepted message received from the network to the consensus
 // engine.
 func (h *Handler) Accepted(validatorID ids.ShortID, requestID uint32, containerIDs ids.Set) {
-	h.msgs <- message{
+	h.push(message{
 		messageType:  acceptedMsg,
 		validatorID:  validatorID,
 		requestID:    requestID,
 		containerIDs: containerIDs,
-	}
+	})
 }
 
 // GetAcceptedFailed passes a GetAcceptedFailed message received from the
 // network to the consensus engine.
 func (h *Handler) GetAcceptedFailed(validatorID ids.ShortID, requestID uint32) {
-	h.msgs <- message{
+	h.push(message{
 		messageType: getAcceptedFailedMsg,
 		validatorID: validatorID,
 		requestID:   requestID,
-	}
+	})
+}
+
+// GetAncestors passes a GetAncestors message received from the network to the consensus engine.
+func (h *Handler) GetAncestors(validatorID ids.ShortID, requestID uint32, containerID ids.ID) {
+	h.push(message{
+		messageType: getAncestorsMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+		containerID: containerID,
+	})
+}
+
+// MultiPut passes a MultiPut message received from the network to the consensus engine.
+func (h *Handler) MultiPut(validatorID ids.ShortID, requestID uint32, containers [][]byte) {
+	h.push(message{
+		messageType: multiPutMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+		containers:  containers,
+	})
+}
+
+// GetAncestorsFailed passes a GetAncestorsFailed message to the consensus engine.
+func (h *Handler) GetAncestorsFailed(validatorID ids.ShortID, requestID uint32) {
+	h.push(message{
+		messageType: getAncestorsFailedMsg,
+		validatorID: validatorID,
+		requestID:   requestID,
+	})
 }
 
 // Get passes a Get message received from the network to the consensus engine.
 func (h *Handler) Get(validatorID ids.ShortID, requestID uint32, containerID ids.ID) {
-	h.msgs <- message{
+	h.push(message{
 		messageType: getMsg,
 		validatorID: validatorID,
 		requestID:   requestID,
 		containerID: containerID,
-	}
+	})
 }
 
 // Put passes a Put message received from the network to the consensus engine.
 func (h *Handler) Put(validatorID ids.ShortID, requestID uint32, containerID ids.ID, container []byte) {
-	h.msgs <- message{
+	h.push(message{
 		messageType: putMsg,
 		validatorID: validatorID,
 		requestID:   requestID,
 		containerID: containerID,
 		container:   container,
-	}
+	})
 }
 
 // GetFailed passes a GetFailed message to the consensus engine.
 func (h *Handler) GetFailed(validatorID ids.ShortID, requestID uint32, containerID ids.ID) {
-	h.msgs <- message{
+	h.push(message{
 		messageType: getFailedMsg,
 		validatorID: validatorID,
 		requestID:   requestID,
 		containerID: containerID,
-	}
+	})
 }
 
 // PushQuery passes a PushQuery message received from the network to the consensus engine.
 func (h *Handler) PushQuery(validatorID ids.ShortID, requestID uint32, blockID ids.ID, block []byte) {
-	h.msgs <- message{
+	h.push(message{
 		messageType: pushQueryMsg,
 		validatorID: validatorID,
 		requestID:   requestID,
 		containerID: blockID,
 		container:   block,
-	}
+	})
 }
 
 // PullQuery passes a PullQuery message received from the network to the consensus engine.
 func (h *Handler) PullQuery(validatorID ids.ShortID, requestID uint32, blockID ids.ID) {
-	h.msgs <- message{
+	h.push(message{
 		messageType: pullQueryMsg,
 		validatorID: validatorID,
 		requestID:   requestID,
 		containerID: blockID,
-	}
+	})
 }
 
 // Chits passes a Chits message received from the network to the consensus engine.
 func (h *Handler) Chits(validatorID ids.ShortID, requestID uint32, votes ids.Set) {
-	h.msgs <- message{
+	h.push(message{
 		messageType:  chitsMsg,
 		validatorID:  validatorID,
 		requestID:    requestID,
 		containerIDs: votes,
-	}
+	})
 }
 
 // QueryFailed passes a QueryFailed message received from the network to the consensus engine.
 func (h *Handler) QueryFailed(validatorID ids.ShortID, requestID uint32) {
-	h.msgs <- message{
+	h.push(message{
 		messageType: queryFailedMsg,
 		validatorID: validatorID,
 		requestID:   requestID,
-	}
+	})
 }
 
 // Shutdown shuts down the dispatcher
-func (h *Handler) Shutdown() { h.msgs <- message{messageType: shutdownMsg}; h.wg.Wait() }
+func (h *Handler) Shutdown() { h.push(message{messageType: shutdownMsg}); h.wg.Wait() }
 
 // Notify ...
 func (h *Handler) Notify(msg common.Message) {
-	h.msgs <- message{
+	h.push(message{
 		messageType:  notifyMsg,
 		notification: msg,
+	})
+}
+
+// push [msg] onto the queue of messages to dispatch
+func (h *Handler) push(msg message) {
+	if !h.msgs.push(msg) {
+		h.engine.Context().Log.Debug("Dropping message from %s due to too many pending messages:%s", msg.validatorID, msg)
 	}
 }
@@ -21,6 +21,9 @@ const (
 	getAcceptedMsg
 	acceptedMsg
 	getAcceptedFailedMsg
+	getAncestorsMsg
+	multiPutMsg
+	getAncestorsFailedMsg
 	getMsg
 	putMsg
 	getFailedMsg
@@ -39,6 +42,7 @@ type message struct {
 	containerID  ids.ID
 	container    []byte
 	containerIDs ids.Set
+	containers   [][]byte
 	notification common.Message
 }
 
@@ -49,12 +53,53 @@ func (m message) String() string {
 	sb.WriteString(fmt.Sprintf("\n    requestID: %d", m.requestID))
 	sb.WriteString(fmt.Sprintf("\n    containerID: %s", m.containerID.String()))
 	sb.WriteString(fmt.Sprintf("\n    containerIDs: %s", m.containerIDs.String()))
+	if m.messageType == multiPutMsg {
+		sb.WriteString(fmt.Sprintf("\n    numContainers: %d", len(m.containers)))
+	}
 	if m.messageType == notifyMsg {
 		sb.WriteString(fmt.Sprintf("\n    notification: %s", m.notification.String()))
 	}
 	return sb.String()
 }
 
+// internal returns true if the message was generated by this node, rather than
+// received from a peer
+func (m message) internal() bool {
+	switch m.messageType {
+	case getAcceptedFrontierFailedMsg, getAcceptedFailedMsg, getAncestorsFailedMsg,
+		getFailedMsg, queryFailedMsg, notifyMsg, shutdownMsg:
+		return true
+	default:
+		return false
+	}
+}
+
+// failure returns the message that tells the consensus engine that the
+// request this message responds to failed, and true, if this message is a
+// response
+func (m message) failure() (message, bool) {
+	failed := message{
+		validatorID: m.validatorID,
+		requestID:   m.requestID,
+	}
+	switch m.messageType {
+	case acceptedFrontierMsg:
+		failed.messageType = getAcceptedFrontierFailedMsg
+	case acceptedMsg:
+		failed.messageType = getAcceptedFailedMsg
+	case multiPutMsg:
+		failed.messageType = getAncestorsFailedMsg
+	case putMsg:
+		failed.messageType = getFailedMsg
+		failed.containerID = m.containerID
+	case chitsMsg:
+		failed.messageType = queryFailedMsg
+	default:
+		return message{}, false
+	}
+	return failed, true
+}
+
 func (t msgType) String() string {
 	switch t {
 	case nullMsg:
@@ -71,6 +116,12 @@ func (t msgType) String() string {
 		return "Accepted Message"
 	case getAcceptedFailedMsg:
 		return "Get Accepted Failed Message"
+	case getAncestorsMsg:
+		return "Get Ancestors Message"
+	case multiPutMsg:
+		return "MultiPut Message"
+	case getAncestorsFailedMsg:
+		return "Get Ancestors Failed Message"
 	case getMsg:
 		return "Get Message"
 	case putMsg:
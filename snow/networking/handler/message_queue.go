@@ -0,0 +1,242 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package handler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/validators"
+)
+
+// dropPenalty is the processing time a peer is charged for each of its
+// messages that's dropped because it has too many pending messages, so that
+// peers that flood this node are scheduled after the peers that don't
+const dropPenalty = 10 * time.Millisecond
+
+// messageQueue holds the messages received from peers until they're dispatched
+// to the consensus engine.
+//
+// Each peer has its own queue, which holds at most [maxPeerPending] messages.
+// Peers take turns in proportion to their stake: the next message dispatched
+// is the oldest message of the peer whose messages took the least time to
+// process, relative to the peer's stake. Peers that aren't validators are
+// treated as having the least possible stake.
+//
+// Messages this node generated itself, e.g. because a request timed out, are
+// dispatched before any message from a peer, and are never dropped.
+type messageQueue struct {
+	lock sync.Mutex
+	cond *sync.Cond
+
+	vdrs    validators.Set
+	metrics *metrics
+
+	// The maximum number of messages from peers that may be pending, in total
+	// and for each peer
+	maxPending, maxPeerPending int
+
+	internal []message
+
+	// Key: The ID of a peer
+	// Value: The messages from the peer, and how long they took to process
+	peers      map[[20]byte]*peerQueue
+	numPending int
+
+	// The virtual time of the peer whose message was dispatched last. A peer
+	// that had no pending messages can't be scheduled before this, so that
+	// peers can't build up credit while they're idle.
+	virtualTime float64
+}
+
+// peerQueue is the queue of messages from a peer
+type peerQueue struct {
+	msgs []message
+
+	// The time spent processing the peer's messages, divided by its stake
+	virtualTime float64
+}
+
+// initialize the queue
+func (q *messageQueue) initialize(vdrs validators.Set, metrics *metrics, maxPending, maxPeerPending int) {
+	q.cond = sync.NewCond(&q.lock)
+	q.vdrs = vdrs
+	q.metrics = metrics
+	q.maxPending = maxPending
+	q.maxPeerPending = maxPeerPending
+	q.peers = make(map[[20]byte]*peerQueue)
+}
+
+// push [msg] onto the queue. Returns false if the message was dropped.
+//
+// If the queue is full, the newest message of the peer with the most pending
+// messages relative to its stake is dropped to make room, unless that's the
+// peer that sent [msg], in which case [msg] is dropped. That way, peers that
+// flood this node can't crowd out the messages of the other peers.
+//
+// If a response is dropped, the engine is told that the request failed
+// instead, so that it doesn't wait for the response indefinitely. The router
+// only forwards responses to outstanding requests, so at most one failure is
+// reported for each request.
+func (q *messageQueue) push(msg message) bool {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if msg.internal() {
+		q.internal = append(q.internal, msg)
+		q.cond.Signal()
+		return true
+	}
+
+	key := msg.validatorID.Key()
+	peer, exists := q.peers[key]
+	if !exists {
+		peer = &peerQueue{}
+		q.peers[key] = peer
+	}
+
+	// Only a peer that exceeded its own cap, or whose message is evicted, is
+	// at fault
+	if len(peer.msgs) >= q.maxPeerPending {
+		q.penalize(peer, msg.validatorID)
+		q.drop(msg)
+		return false
+	}
+
+	if q.numPending >= q.maxPending {
+		victimID, victim := q.mostPending()
+		if victim == nil || q.pendingShare(victimID, len(victim.msgs)) <= q.pendingShare(msg.validatorID, len(peer.msgs)+1) {
+			if !exists {
+				delete(q.peers, key)
+			}
+			q.drop(msg)
+			return false
+		}
+
+		last := len(victim.msgs) - 1
+		evicted := victim.msgs[last]
+		victim.msgs[last] = message{}
+		victim.msgs = victim.msgs[:last]
+		q.numPending--
+		q.penalize(victim, victimID)
+		q.drop(evicted)
+	}
+
+	if len(peer.msgs) == 0 && peer.virtualTime < q.virtualTime {
+		peer.virtualTime = q.virtualTime
+	}
+	peer.msgs = append(peer.msgs, msg)
+	q.numPending++
+	q.metrics.numPending.Set(float64(q.numPending))
+	q.cond.Signal()
+	return true
+}
+
+// drop [msg]. If [msg] is a response, the engine is told that the request
+// failed instead. Assumes [q.lock] is held.
+func (q *messageQueue) drop(msg message) {
+	q.metrics.numDropped.Inc()
+	if failed, ok := msg.failure(); ok {
+		q.internal = append(q.internal, failed)
+		q.cond.Signal()
+	}
+}
+
+// penalize [peer], whose ID is [peerID], for having one of its messages
+// dropped. Assumes [q.lock] is held.
+func (q *messageQueue) penalize(peer *peerQueue, peerID ids.ShortID) {
+	q.metrics.penalty.Add(float64(dropPenalty))
+	q.charge(peer, peerID, dropPenalty)
+}
+
+// mostPending returns the peer with the most pending messages relative to its
+// stake, breaking ties by the peer's virtual time. Assumes [q.lock] is held.
+func (q *messageQueue) mostPending() (ids.ShortID, *peerQueue) {
+	var (
+		worstID ids.ShortID
+		worst   *peerQueue
+		share   float64
+	)
+	for key, peer := range q.peers {
+		if len(peer.msgs) == 0 {
+			continue
+		}
+		peerID := ids.NewShortID(key)
+		peerShare := q.pendingShare(peerID, len(peer.msgs))
+		if worst == nil || peerShare > share || (peerShare == share && peer.virtualTime > worst.virtualTime) {
+			worstID, worst, share = peerID, peer, peerShare
+		}
+	}
+	return worstID, worst
+}
+
+// pendingShare returns [numPending] divided by the stake of [peerID]
+func (q *messageQueue) pendingShare(peerID ids.ShortID, numPending int) float64 {
+	return float64(numPending) / float64(q.weight(peerID))
+}
+
+// pop the next message to dispatch off the queue. Blocks until there is one.
+func (q *messageQueue) pop() message {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	for len(q.internal) == 0 && q.numPending == 0 {
+		q.cond.Wait()
+	}
+
+	if len(q.internal) != 0 {
+		msg := q.internal[0]
+		q.internal[0] = message{}
+		q.internal = q.internal[1:]
+		return msg
+	}
+
+	var next *peerQueue
+	for _, peer := range q.peers {
+		if len(peer.msgs) != 0 && (next == nil || peer.virtualTime < next.virtualTime) {
+			next = peer
+		}
+	}
+
+	msg := next.msgs[0]
+	next.msgs[0] = message{}
+	next.msgs = next.msgs[1:]
+	q.numPending--
+	q.metrics.numPending.Set(float64(q.numPending))
+	q.virtualTime = next.virtualTime
+	return msg
+}
+
+// processed records that processing a message from [peerID] took [duration]
+func (q *messageQueue) processed(peerID ids.ShortID, duration time.Duration) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if peer, exists := q.peers[peerID.Key()]; exists {
+		q.charge(peer, peerID, duration)
+	}
+}
+
+// charge [peer], whose ID is [peerID], for [duration] of processing time, and
+// stop tracking the peer if it has no pending messages. A peer's virtual time
+// is only kept while it has pending messages, so that the number of peers
+// tracked is bounded by the number of pending messages, however many peers
+// send messages. Assumes [q.lock] is held.
+func (q *messageQueue) charge(peer *peerQueue, peerID ids.ShortID, duration time.Duration) {
+	if len(peer.msgs) == 0 {
+		delete(q.peers, peerID.Key())
+		return
+	}
+	peer.virtualTime += float64(duration) / float64(q.weight(peerID))
+}
+
+// weight returns the stake of [peerID], or 1 if it isn't a validator
+func (q *messageQueue) weight(peerID ids.ShortID) uint64 {
+	weight, _ := q.vdrs.GetWeight(peerID)
+	if weight == 0 {
+		weight = 1
+	}
+	return weight
+}
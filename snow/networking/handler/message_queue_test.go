@@ -0,0 +1,227 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/validators"
+)
+
+func newMessageQueueTest(t *testing.T, vdrs validators.Set, maxPending, maxPeerPending int) *messageQueue {
+	m := &metrics{}
+	if err := m.Initialize("", prometheus.NewRegistry()); err != nil {
+		t.Fatal(err)
+	}
+
+	q := &messageQueue{}
+	q.initialize(vdrs, m, maxPending, maxPeerPending)
+	return q
+}
+
+func TestMessageQueueStakeWeighted(t *testing.T) {
+	heavyID := ids.NewShortID([20]byte{1})
+	lightID := ids.NewShortID([20]byte{2})
+
+	vdrs := validators.NewSet()
+	vdrs.Add(validators.NewValidator(heavyID, 3))
+	vdrs.Add(validators.NewValidator(lightID, 1))
+
+	q := newMessageQueueTest(t, vdrs, 100, 100)
+	for i := 0; i < 8; i++ {
+		q.push(message{messageType: getMsg, validatorID: heavyID})
+		q.push(message{messageType: getMsg, validatorID: lightID})
+	}
+
+	// Every message takes as long to process, so the peer with three times
+	// the stake has three times as many of its messages dispatched
+	numHeavy := 0
+	for i := 0; i < 8; i++ {
+		msg := q.pop()
+		if msg.validatorID.Equals(heavyID) {
+			numHeavy++
+		}
+		q.processed(msg.validatorID, time.Millisecond)
+	}
+	if numHeavy != 6 {
+		t.Fatalf("Wrong number of dispatched messages. Expected: %d ; Returned: %d", 6, numHeavy)
+	}
+}
+
+func TestMessageQueueIdlePeerNoCredit(t *testing.T) {
+	busyID := ids.NewShortID([20]byte{1})
+	idleID := ids.NewShortID([20]byte{2})
+
+	vdrs := validators.NewSet()
+	vdrs.Add(validators.NewValidator(busyID, 1))
+	vdrs.Add(validators.NewValidator(idleID, 1))
+
+	q := newMessageQueueTest(t, vdrs, 100, 100)
+	for i := 0; i < 10; i++ {
+		q.push(message{messageType: getMsg, validatorID: busyID})
+		q.processed(q.pop().validatorID, time.Millisecond)
+	}
+
+	// The peer that was idle while the other peer's messages were processed
+	// doesn't get to go first, the peers take turns
+	for i := 0; i < 5; i++ {
+		q.push(message{messageType: getMsg, validatorID: busyID})
+		q.push(message{messageType: getMsg, validatorID: idleID})
+	}
+	numBusy := 0
+	for i := 0; i < 4; i++ {
+		msg := q.pop()
+		if msg.validatorID.Equals(busyID) {
+			numBusy++
+		}
+		q.processed(msg.validatorID, time.Millisecond)
+	}
+	if numBusy != 2 {
+		t.Fatalf("Wrong number of dispatched messages. Expected: %d ; Returned: %d", 2, numBusy)
+	}
+}
+
+func TestMessageQueueInternalFirst(t *testing.T) {
+	peerID := ids.NewShortID([20]byte{1})
+
+	q := newMessageQueueTest(t, validators.NewSet(), 100, 100)
+	q.push(message{messageType: getMsg, validatorID: peerID})
+	q.push(message{messageType: queryFailedMsg, validatorID: peerID})
+
+	if msg := q.pop(); msg.messageType != queryFailedMsg {
+		t.Fatalf("Wrong message dispatched. Expected: %s ; Returned: %s", queryFailedMsg, msg.messageType)
+	}
+	if msg := q.pop(); msg.messageType != getMsg {
+		t.Fatalf("Wrong message dispatched. Expected: %s ; Returned: %s", getMsg, msg.messageType)
+	}
+}
+
+func TestMessageQueuePeerCap(t *testing.T) {
+	floodID := ids.NewShortID([20]byte{1})
+	otherID := ids.NewShortID([20]byte{2})
+
+	q := newMessageQueueTest(t, validators.NewSet(), 3, 2)
+	if !q.push(message{messageType: getMsg, validatorID: floodID}) {
+		t.Fatalf("shouldn't have dropped the message")
+	}
+	if !q.push(message{messageType: getMsg, validatorID: floodID}) {
+		t.Fatalf("shouldn't have dropped the message")
+	}
+
+	// The peer has too many pending messages, so its response is dropped, and
+	// the request is reported as failed instead
+	if q.push(message{messageType: chitsMsg, validatorID: floodID, requestID: 5}) {
+		t.Fatalf("should have dropped the message")
+	}
+	msg := q.pop()
+	switch {
+	case msg.messageType != queryFailedMsg:
+		t.Fatalf("Wrong message dispatched. Expected: %s ; Returned: %s", queryFailedMsg, msg.messageType)
+	case !msg.validatorID.Equals(floodID):
+		t.Fatalf("Wrong validator. Expected: %s ; Returned: %s", floodID, msg.validatorID)
+	case msg.requestID != 5:
+		t.Fatalf("Wrong request ID. Expected: %d ; Returned: %d", 5, msg.requestID)
+	}
+
+	// Other peers can still send messages until the buffer is full
+	if !q.push(message{messageType: getMsg, validatorID: otherID}) {
+		t.Fatalf("shouldn't have dropped the message")
+	}
+	if q.push(message{messageType: getMsg, validatorID: otherID}) {
+		t.Fatalf("should have dropped the message")
+	}
+
+	// The peer that flooded this node was penalized, so the other peer goes
+	// first
+	if msg := q.pop(); !msg.validatorID.Equals(otherID) {
+		t.Fatalf("Wrong validator. Expected: %s ; Returned: %s", otherID, msg.validatorID)
+	}
+}
+
+func TestMessageQueueFullEvictsFlooders(t *testing.T) {
+	vdrID := ids.NewShortID([20]byte{1})
+
+	vdrs := validators.NewSet()
+	vdrs.Add(validators.NewValidator(vdrID, 10))
+
+	q := newMessageQueueTest(t, vdrs, 10, 5)
+
+	// Many peers that aren't validators fill the buffer
+	for i := 0; i < 20; i++ {
+		flooderID := ids.NewShortID([20]byte{2, byte(i)})
+		for j := 0; j < 5; j++ {
+			q.push(message{messageType: getMsg, validatorID: flooderID})
+		}
+	}
+
+	// The validator's messages still make it into the queue, at the expense
+	// of the flooders' messages
+	for i := 0; i < 5; i++ {
+		if !q.push(message{messageType: getMsg, validatorID: vdrID}) {
+			t.Fatalf("shouldn't have dropped the validator's message")
+		}
+	}
+	if q.numPending != 10 {
+		t.Fatalf("Wrong number of pending messages. Expected: %d ; Returned: %d", 10, q.numPending)
+	}
+
+	numVdr := 0
+	for i := 0; i < 10; i++ {
+		msg := q.pop()
+		if msg.validatorID.Equals(vdrID) {
+			numVdr++
+		}
+		q.processed(msg.validatorID, time.Millisecond)
+	}
+	if numVdr != 5 {
+		t.Fatalf("Wrong number of dispatched messages. Expected: %d ; Returned: %d", 5, numVdr)
+	}
+}
+
+func TestMessageQueueFullEvictedResponseFails(t *testing.T) {
+	floodID := ids.NewShortID([20]byte{1})
+	vdrID := ids.NewShortID([20]byte{2})
+
+	vdrs := validators.NewSet()
+	vdrs.Add(validators.NewValidator(vdrID, 10))
+
+	q := newMessageQueueTest(t, vdrs, 2, 2)
+	q.push(message{messageType: getMsg, validatorID: floodID})
+	q.push(message{messageType: chitsMsg, validatorID: floodID, requestID: 7})
+
+	// The flooder's newest message is evicted, and because it was a response,
+	// the request is reported as failed
+	if !q.push(message{messageType: getMsg, validatorID: vdrID}) {
+		t.Fatalf("shouldn't have dropped the validator's message")
+	}
+	msg := q.pop()
+	switch {
+	case msg.messageType != queryFailedMsg:
+		t.Fatalf("Wrong message dispatched. Expected: %s ; Returned: %s", queryFailedMsg, msg.messageType)
+	case !msg.validatorID.Equals(floodID):
+		t.Fatalf("Wrong validator. Expected: %s ; Returned: %s", floodID, msg.validatorID)
+	case msg.requestID != 7:
+		t.Fatalf("Wrong request ID. Expected: %d ; Returned: %d", 7, msg.requestID)
+	}
+}
+
+func TestMessageQueueForgetsIdlePeers(t *testing.T) {
+	q := newMessageQueueTest(t, validators.NewSet(), 100, 100)
+	for i := 0; i < 1000; i++ {
+		q.push(message{messageType: getMsg, validatorID: ids.NewShortID([20]byte{byte(i), byte(i >> 8)})})
+		if i%2 == 1 {
+			q.processed(q.pop().validatorID, time.Millisecond)
+			q.processed(q.pop().validatorID, time.Millisecond)
+		}
+	}
+
+	// Peers without pending messages aren't tracked
+	if numPeers := len(q.peers); numPeers != 0 {
+		t.Fatalf("Wrong number of tracked peers. Expected: %d ; Returned: %d", 0, numPeers)
+	}
+}
@@ -0,0 +1,45 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package handler
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+type metrics struct {
+	numPending          prometheus.Gauge
+	numDropped, penalty prometheus.Counter
+}
+
+// Initialize the metrics, and register them with [registerer]
+func (m *metrics) Initialize(namespace string, registerer prometheus.Registerer) error {
+	m.numPending = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "handler_pending",
+			Help:      "Number of messages from peers waiting to be dispatched to the consensus engine",
+		})
+	m.numDropped = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "handler_dropped",
+			Help:      "Number of messages from peers dropped because too many messages were pending",
+		})
+	m.penalty = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "handler_penalty",
+			Help:      "Time, in nanoseconds, that peers were charged as a penalty for sending messages that were dropped",
+		})
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(m.numPending),
+		registerer.Register(m.numDropped),
+		registerer.Register(m.penalty),
+	)
+	return errs.Err
+}
@@ -25,7 +25,8 @@ type ChainRouter struct {
 
 // Initialize the router
 // When this router receives an incoming message, it cancels the timeout in [timeouts]
-// associated with the request that caused the incoming message, if applicable
+// associated with the request that caused the incoming message, if applicable.
+// Responses to requests that aren't outstanding are dropped.
 func (sr *ChainRouter) Initialize(log logging.Logger, timeouts *timeout.Manager) {
 	sr.log = log
 	sr.chains = make(map[[32]byte]*handler.Handler)
@@ -76,7 +77,12 @@ func (sr *ChainRouter) AcceptedFrontier(validatorID ids.ShortID, chainID ids.ID,
 	sr.lock.RLock()
 	defer sr.lock.RUnlock()
 
-	sr.timeouts.Cancel(validatorID, chainID, requestID)
+	if !sr.timeouts.Cancel(validatorID, chainID, requestID) {
+		// Either this node didn't send the request, or it already gave up
+		// waiting for the response
+		sr.log.Debug("Dropping unrequested AcceptedFrontier from %s with requestID %d", validatorID, requestID)
+		return
+	}
 	if chain, exists := sr.chains[chainID.Key()]; exists {
 		chain.AcceptedFrontier(validatorID, requestID, containerIDs)
 	} else {
@@ -120,7 +126,12 @@ func (sr *ChainRouter) Accepted(validatorID ids.ShortID, chainID ids.ID, request
 	sr.lock.RLock()
 	defer sr.lock.RUnlock()
 
-	sr.timeouts.Cancel(validatorID, chainID, requestID)
+	if !sr.timeouts.Cancel(validatorID, chainID, requestID) {
+		// Either this node didn't send the request, or it already gave up
+		// waiting for the response
+		sr.log.Debug("Dropping unrequested Accepted from %s with requestID %d", validatorID, requestID)
+		return
+	}
 	if chain, exists := sr.chains[chainID.Key()]; exists {
 		chain.Accepted(validatorID, requestID, containerIDs)
 	} else {
@@ -143,6 +154,54 @@ func (sr *ChainRouter) GetAcceptedFailed(validatorID ids.ShortID, chainID ids.ID
 	}
 }
 
+// GetAncestors routes an incoming GetAncestors message from the validator with ID [validatorID]
+// to the consensus engine working on the chain with ID [chainID]
+func (sr *ChainRouter) GetAncestors(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID) {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	if chain, exists := sr.chains[chainID.Key()]; exists {
+		chain.GetAncestors(validatorID, requestID, containerID)
+	} else {
+		sr.log.Warn("Message referenced a chain, %s, this validator is not validating", chainID)
+	}
+}
+
+// MultiPut routes an incoming MultiPut message from the validator with ID [validatorID]
+// to the consensus engine working on the chain with ID [chainID]
+func (sr *ChainRouter) MultiPut(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containers [][]byte) {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	// This message came in response to a GetAncestors message from this node, and when we sent that
+	// GetAncestors message we set a timeout. Since we got a response, cancel the timeout.
+	if !sr.timeouts.Cancel(validatorID, chainID, requestID) {
+		// Either this node didn't send the request, or it already gave up
+		// waiting for the response
+		sr.log.Debug("Dropping unrequested MultiPut from %s with requestID %d", validatorID, requestID)
+		return
+	}
+	if chain, exists := sr.chains[chainID.Key()]; exists {
+		chain.MultiPut(validatorID, requestID, containers)
+	} else {
+		sr.log.Warn("Message referenced a chain, %s, this validator is not validating", chainID)
+	}
+}
+
+// GetAncestorsFailed routes an incoming GetAncestorsFailed message from the validator with ID [validatorID]
+// to the consensus engine working on the chain with ID [chainID]
+func (sr *ChainRouter) GetAncestorsFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32) {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	sr.timeouts.Cancel(validatorID, chainID, requestID)
+	if chain, exists := sr.chains[chainID.Key()]; exists {
+		chain.GetAncestorsFailed(validatorID, requestID)
+	} else {
+		sr.log.Warn("Message referenced a chain, %s, this validator is not validating", chainID)
+	}
+}
+
 // Get routes an incoming Get request from the validator with ID [validatorID]
 // to the consensus engine working on the chain with ID [chainID]
 func (sr *ChainRouter) Get(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID) {
@@ -164,7 +223,12 @@ func (sr *ChainRouter) Put(validatorID ids.ShortID, chainID ids.ID, requestID ui
 
 	// This message came in response to a Get message from this node, and when we sent that Get
 	// message we set a timeout. Since we got a response, cancel the timeout.
-	sr.timeouts.Cancel(validatorID, chainID, requestID)
+	if !sr.timeouts.Cancel(validatorID, chainID, requestID) {
+		// Either this node didn't send the request, or it already gave up
+		// waiting for the response
+		sr.log.Debug("Dropping unrequested Put from %s with requestID %d", validatorID, requestID)
+		return
+	}
 	if chain, exists := sr.chains[chainID.Key()]; exists {
 		chain.Put(validatorID, requestID, containerID, container)
 	} else {
@@ -219,7 +283,12 @@ func (sr *ChainRouter) Chits(validatorID ids.ShortID, chainID ids.ID, requestID
 	defer sr.lock.RUnlock()
 
 	// Cancel timeout we set when sent the message asking for these Chits
-	sr.timeouts.Cancel(validatorID, chainID, requestID)
+	if !sr.timeouts.Cancel(validatorID, chainID, requestID) {
+		// Either this node didn't send the request, or it already gave up
+		// waiting for the response
+		sr.log.Debug("Dropping unrequested Chits from %s with requestID %d", validatorID, requestID)
+		return
+	}
 	if chain, exists := sr.chains[chainID.Key()]; exists {
 		chain.Chits(validatorID, requestID, votes)
 	} else {
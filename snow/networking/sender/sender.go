@@ -31,10 +31,6 @@ func (s *Sender) Context() *snow.Context { return s.ctx }
 
 // GetAcceptedFrontier ...
 func (s *Sender) GetAcceptedFrontier(validatorIDs ids.ShortSet, requestID uint32) {
-	if validatorIDs.Contains(s.ctx.NodeID) {
-		validatorIDs.Remove(s.ctx.NodeID)
-		go s.router.GetAcceptedFrontier(s.ctx.NodeID, s.ctx.ChainID, requestID)
-	}
 	validatorList := validatorIDs.List()
 	for _, validatorID := range validatorList {
 		vID := validatorID
@@ -42,6 +38,10 @@ func (s *Sender) GetAcceptedFrontier(validatorIDs ids.ShortSet, requestID uint32
 			s.router.GetAcceptedFrontierFailed(vID, s.ctx.ChainID, requestID)
 		})
 	}
+	if validatorIDs.Contains(s.ctx.NodeID) {
+		validatorIDs.Remove(s.ctx.NodeID)
+		go s.router.GetAcceptedFrontier(s.ctx.NodeID, s.ctx.ChainID, requestID)
+	}
 	s.sender.GetAcceptedFrontier(validatorIDs, s.ctx.ChainID, requestID)
 }
 
@@ -56,10 +56,6 @@ func (s *Sender) AcceptedFrontier(validatorID ids.ShortID, requestID uint32, con
 
 // GetAccepted ...
 func (s *Sender) GetAccepted(validatorIDs ids.ShortSet, requestID uint32, containerIDs ids.Set) {
-	if validatorIDs.Contains(s.ctx.NodeID) {
-		validatorIDs.Remove(s.ctx.NodeID)
-		go s.router.GetAccepted(s.ctx.NodeID, s.ctx.ChainID, requestID, containerIDs)
-	}
 	validatorList := validatorIDs.List()
 	for _, validatorID := range validatorList {
 		vID := validatorID
@@ -67,6 +63,10 @@ func (s *Sender) GetAccepted(validatorIDs ids.ShortSet, requestID uint32, contai
 			s.router.GetAcceptedFailed(vID, s.ctx.ChainID, requestID)
 		})
 	}
+	if validatorIDs.Contains(s.ctx.NodeID) {
+		validatorIDs.Remove(s.ctx.NodeID)
+		go s.router.GetAccepted(s.ctx.NodeID, s.ctx.ChainID, requestID, containerIDs)
+	}
 	s.sender.GetAccepted(validatorIDs, s.ctx.ChainID, requestID, containerIDs)
 }
 
@@ -79,6 +79,29 @@ func (s *Sender) Accepted(validatorID ids.ShortID, requestID uint32, containerID
 	s.sender.Accepted(validatorID, s.ctx.ChainID, requestID, containerIDs)
 }
 
+// GetAncestors sends a GetAncestors message to the consensus engine running on
+// the specified chain to the specified validator. The GetAncestors message
+// signifies that this consensus engine would like the recipient to send this
+// consensus engine the specified container and its ancestors.
+func (s *Sender) GetAncestors(validatorID ids.ShortID, requestID uint32, containerID ids.ID) {
+	s.ctx.Log.Verbo("Sending GetAncestors to validator %s. RequestID: %d. ContainerID: %s", validatorID, requestID, containerID)
+	// Add a timeout -- if we don't get a response before the timeout expires,
+	// send this consensus engine a GetAncestorsFailed message
+	s.timeouts.Register(validatorID, s.ctx.ChainID, requestID, func() {
+		s.router.GetAncestorsFailed(validatorID, s.ctx.ChainID, requestID)
+	})
+	s.sender.GetAncestors(validatorID, s.ctx.ChainID, requestID, containerID)
+}
+
+// MultiPut sends a MultiPut message to the consensus engine running on the
+// specified chain on the specified validator. The MultiPut message gives the
+// recipient the contents of several containers, in response to a GetAncestors
+// message.
+func (s *Sender) MultiPut(validatorID ids.ShortID, requestID uint32, containers [][]byte) {
+	s.ctx.Log.Verbo("Sending MultiPut to validator %s. RequestID: %d. NumContainers: %d", validatorID, requestID, len(containers))
+	s.sender.MultiPut(validatorID, s.ctx.ChainID, requestID, containers)
+}
+
 // Get sends a Get message to the consensus engine running on the specified
 // chain to the specified validator. The Get message signifies that this
 // consensus engine would like the recipient to send this consensus engine the
@@ -108,6 +131,15 @@ func (s *Sender) Put(validatorID ids.ShortID, requestID uint32, containerID ids.
 // their preferred frontier given the existence of the specified container.
 func (s *Sender) PushQuery(validatorIDs ids.ShortSet, requestID uint32, containerID ids.ID, container []byte) {
 	s.ctx.Log.Verbo("Sending PushQuery to validators %v. RequestID: %d. ContainerID: %s", validatorIDs, requestID, containerID)
+	// The query is registered with every validator, including myself, because
+	// the router drops responses to queries that aren't outstanding
+	validatorList := validatorIDs.List() // Convert set to list for easier iteration
+	for _, validatorID := range validatorList {
+		vID := validatorID
+		s.timeouts.Register(validatorID, s.ctx.ChainID, requestID, func() {
+			s.router.QueryFailed(vID, s.ctx.ChainID, requestID)
+		})
+	}
 	// If one of the validators in [validatorIDs] is myself, send this message directly
 	// to my own router rather than sending it over the network
 	if validatorIDs.Contains(s.ctx.NodeID) { // One of the validators in [validatorIDs] was myself
@@ -117,13 +149,6 @@ func (s *Sender) PushQuery(validatorIDs ids.ShortSet, requestID uint32, containe
 		// If this were not a goroutine, then we would deadlock here when [handler].msgs is full
 		go s.router.PushQuery(s.ctx.NodeID, s.ctx.ChainID, requestID, containerID, container)
 	}
-	validatorList := validatorIDs.List() // Convert set to list for easier iteration
-	for _, validatorID := range validatorList {
-		vID := validatorID
-		s.timeouts.Register(validatorID, s.ctx.ChainID, requestID, func() {
-			s.router.QueryFailed(vID, s.ctx.ChainID, requestID)
-		})
-	}
 	s.sender.PushQuery(validatorIDs, s.ctx.ChainID, requestID, containerID, container)
 }
 
@@ -133,6 +158,15 @@ func (s *Sender) PushQuery(validatorIDs ids.ShortSet, requestID uint32, containe
 // their preferred frontier.
 func (s *Sender) PullQuery(validatorIDs ids.ShortSet, requestID uint32, containerID ids.ID) {
 	s.ctx.Log.Verbo("Sending PullQuery. RequestID: %d. ContainerID: %s", requestID, containerID)
+	// The query is registered with every validator, including myself, because
+	// the router drops responses to queries that aren't outstanding
+	validatorList := validatorIDs.List() // Convert set to list for easier iteration
+	for _, validatorID := range validatorList {
+		vID := validatorID
+		s.timeouts.Register(validatorID, s.ctx.ChainID, requestID, func() {
+			s.router.QueryFailed(vID, s.ctx.ChainID, requestID)
+		})
+	}
 	// If one of the validators in [validatorIDs] is myself, send this message directly
 	// to my own router rather than sending it over the network
 	if validatorIDs.Contains(s.ctx.NodeID) { // One of the validators in [validatorIDs] was myself
@@ -142,13 +176,6 @@ func (s *Sender) PullQuery(validatorIDs ids.ShortSet, requestID uint32, containe
 		// If this were not a goroutine, then we would deadlock when [handler].msgs is full
 		go s.router.PullQuery(s.ctx.NodeID, s.ctx.ChainID, requestID, containerID)
 	}
-	validatorList := validatorIDs.List() // Convert set to list for easier iteration
-	for _, validatorID := range validatorList {
-		vID := validatorID
-		s.timeouts.Register(validatorID, s.ctx.ChainID, requestID, func() {
-			s.router.QueryFailed(vID, s.ctx.ChainID, requestID)
-		})
-	}
 	s.sender.PullQuery(validatorIDs, s.ctx.ChainID, requestID, containerID)
 }
 
@@ -8,12 +8,15 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/snow/networking/handler"
 	"github.com/ava-labs/gecko/snow/networking/router"
 	"github.com/ava-labs/gecko/snow/networking/timeout"
+	"github.com/ava-labs/gecko/snow/validators"
 	"github.com/ava-labs/gecko/utils/logging"
 )
 
@@ -43,7 +46,7 @@ func TestTimeout(t *testing.T) {
 	}
 
 	handler := handler.Handler{}
-	handler.Initialize(&engine, nil, 1)
+	handler.Initialize(&engine, validators.NewSet(), nil, 1, 1, "", prometheus.NewRegistry())
 	go handler.Dispatch()
 
 	router.AddChain(&handler)
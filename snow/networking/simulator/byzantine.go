@@ -0,0 +1,44 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"math/rand"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+// Byzantine describes how a misbehaving node tampers with the messages it
+// sends. Tamper is called with every message the node sends and returns the
+// messages that are actually put on the wire. Returning no messages drops the
+// original message.
+type Byzantine interface {
+	Tamper(msg *Message) []*Message
+}
+
+// Mute is a node that never sends any messages. It behaves like a node that
+// has crashed.
+type Mute struct{}
+
+// Tamper implements the Byzantine interface
+func (Mute) Tamper(*Message) []*Message { return nil }
+
+// RandomVoter is a node that answers every query with a vote for a container
+// that doesn't exist. All other messages are sent unmodified.
+type RandomVoter struct{}
+
+// Tamper implements the Byzantine interface
+func (RandomVoter) Tamper(msg *Message) []*Message {
+	if msg.Op != Chits {
+		return []*Message{msg}
+	}
+
+	var vote [32]byte
+	rand.Read(vote[:])
+
+	tampered := *msg
+	tampered.ContainerIDs = ids.Set{}
+	tampered.ContainerIDs.Add(ids.NewID(vote))
+	return []*Message{&tampered}
+}
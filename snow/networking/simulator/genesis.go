@@ -0,0 +1,155 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/json"
+	"github.com/ava-labs/gecko/vms/avm"
+	"github.com/ava-labs/gecko/vms/nftfx"
+	"github.com/ava-labs/gecko/vms/platformvm"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+	"github.com/ava-labs/gecko/vms/timestampvm"
+)
+
+const (
+	// GenesisAddress holds the entire supply of the AVA asset on the AVM chain
+	// of the simulated network. It is controlled by GenesisKey.
+	GenesisAddress = "6Y3kysjF9jnHnYkdS9yGAuoHyae2eNmeV"
+
+	// GenesisKey is the CB58 encoded private key of GenesisAddress
+	GenesisKey = "ewoqjP7PxY4yr3iLTpLisriqt94hdyDFNgchSxGGztUrTXtNN"
+
+	// GenesisAmount is the amount of AVA held by GenesisAddress at genesis, on
+	// both the platform chain and the AVM chain
+	GenesisAmount = 45 * 1000 * 1000 * 1000 * 1000 * 1000
+
+	// Names of the chains created by the platform chain at genesis
+	avmChainName       = "AVM"
+	timestampChainName = "Timestamp"
+
+	// How long the genesis validators are staking for
+	stakingDuration = 365 * 24 * time.Hour
+)
+
+var (
+	errNoAVMChain = errors.New("genesis doesn't create an AVM chain")
+)
+
+// Genesis returns the genesis bytes of a platform chain on which every node in
+// [nodeIDs] is a default subnet validator of equal weight, and that creates an
+// AVM chain and a timestamp chain.
+func Genesis(networkID uint32, nodeIDs []ids.ShortID, startTime time.Time) ([]byte, error) {
+	avmArgs := avm.BuildGenesisArgs{
+		GenesisData: map[string]avm.AssetDefinition{
+			"AVA": avm.AssetDefinition{
+				Name:         "AVA",
+				Symbol:       "AVA",
+				Denomination: 9,
+				InitialState: map[string][]interface{}{
+					"fixedCap": []interface{}{
+						avm.Holder{
+							Amount:  GenesisAmount,
+							Address: GenesisAddress,
+						},
+					},
+				},
+			},
+		},
+	}
+	avmReply := avm.BuildGenesisReply{}
+	avmSS := avm.StaticService{}
+	if err := avmSS.BuildGenesis(nil, &avmArgs, &avmReply); err != nil {
+		return nil, err
+	}
+
+	genesisAddress := formatting.CB58{}
+	if err := genesisAddress.FromString(GenesisAddress); err != nil {
+		return nil, err
+	}
+	genesisAddressID, err := ids.ToShortID(genesisAddress.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	weight := json.Uint64(1)
+	validators := make([]platformvm.APIDefaultSubnetValidator, len(nodeIDs))
+	for i, nodeID := range nodeIDs {
+		validators[i] = platformvm.APIDefaultSubnetValidator{
+			APIValidator: platformvm.APIValidator{
+				StartTime: json.Uint64(startTime.Unix()),
+				EndTime:   json.Uint64(startTime.Add(stakingDuration).Unix()),
+				Weight:    &weight,
+				ID:        nodeID,
+			},
+			Destination: genesisAddressID,
+		}
+	}
+
+	platformArgs := platformvm.BuildGenesisArgs{
+		NetworkID: json.Uint32(networkID),
+		Accounts: []platformvm.APIAccount{
+			platformvm.APIAccount{
+				Address: genesisAddressID,
+				Balance: GenesisAmount,
+			},
+		},
+		Validators: validators,
+		Chains: []platformvm.APIChain{
+			platformvm.APIChain{
+				GenesisData: avmReply.Bytes,
+				VMID:        avm.ID,
+				FxIDs:       []ids.ID{secp256k1fx.ID, nftfx.ID},
+				Name:        avmChainName,
+			},
+			platformvm.APIChain{
+				GenesisData: formatting.CB58{Bytes: make([]byte, 32)},
+				VMID:        timestampvm.ID,
+				FxIDs:       []ids.ID{},
+				Name:        timestampChainName,
+			},
+		},
+		Time: json.Uint64(startTime.Unix()),
+	}
+	platformReply := platformvm.BuildGenesisReply{}
+	platformSS := platformvm.StaticService{}
+	if err := platformSS.BuildGenesis(nil, &platformArgs, &platformReply); err != nil {
+		return nil, err
+	}
+	return platformReply.Bytes.Bytes, nil
+}
+
+// GenesisChains returns the chains created by the platform chain with genesis
+// [genesisBytes]
+func GenesisChains(genesisBytes []byte) ([]*platformvm.CreateChainTx, error) {
+	genesis := platformvm.Genesis{}
+	if err := platformvm.Codec.Unmarshal(genesisBytes, &genesis); err != nil {
+		return nil, err
+	}
+	if err := genesis.Initialize(); err != nil {
+		return nil, err
+	}
+	return genesis.Chains, nil
+}
+
+// genesisAVA returns the ID of the AVM chain created by the platform chain with
+// genesis [genesisBytes], and the ID of the AVA asset it creates
+func genesisAVA(genesisBytes []byte) (ids.ID, ids.ID, error) {
+	chains, err := GenesisChains(genesisBytes)
+	if err != nil {
+		return ids.ID{}, ids.ID{}, err
+	}
+	for _, chain := range chains {
+		if !chain.VMID.Equals(avm.ID) {
+			continue
+		}
+		assetID, err := avm.GenesisAssetID(chain.GenesisData, "AVA")
+		return chain.ID(), assetID, err
+	}
+	return ids.ID{}, ids.ID{}, errNoAVMChain
+}
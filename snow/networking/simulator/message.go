@@ -0,0 +1,79 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+// Op is the type of a consensus message
+type Op byte
+
+// Consensus messages that can be sent between virtual nodes
+const (
+	GetAcceptedFrontier Op = iota
+	AcceptedFrontier
+	GetAccepted
+	Accepted
+	GetAncestors
+	MultiPut
+	Get
+	Put
+	PushQuery
+	PullQuery
+	Chits
+)
+
+func (op Op) String() string {
+	switch op {
+	case GetAcceptedFrontier:
+		return "get_accepted_frontier"
+	case AcceptedFrontier:
+		return "accepted_frontier"
+	case GetAccepted:
+		return "get_accepted"
+	case Accepted:
+		return "accepted"
+	case GetAncestors:
+		return "get_ancestors"
+	case MultiPut:
+		return "multi_put"
+	case Get:
+		return "get"
+	case Put:
+		return "put"
+	case PushQuery:
+		return "push_query"
+	case PullQuery:
+		return "pull_query"
+	case Chits:
+		return "chits"
+	default:
+		return "Unknown Op"
+	}
+}
+
+// Message is a consensus message in flight between two virtual nodes. Only
+// the fields relevant to the message's Op are populated.
+type Message struct {
+	Op        Op
+	From, To  ids.ShortID
+	ChainID   ids.ID
+	RequestID uint32
+
+	// Populated by GetAncestors, Get, Put, PushQuery and PullQuery
+	ContainerID ids.ID
+	// Populated by Put and PushQuery
+	Container []byte
+	// Populated by MultiPut
+	Containers [][]byte
+	// Populated by AcceptedFrontier, GetAccepted, Accepted and Chits
+	ContainerIDs ids.Set
+}
+
+func (m *Message) String() string {
+	return fmt.Sprintf("%s from %s to %s on chain %s with requestID %d", m.Op, m.From, m.To, m.ChainID, m.RequestID)
+}
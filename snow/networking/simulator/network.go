@@ -0,0 +1,366 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/chains"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/networking"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/vms/platformvm"
+
+	avacon "github.com/ava-labs/gecko/snow/consensus/avalanche"
+)
+
+var (
+	errNoNodes       = errors.New("the network must contain at least one node")
+	errBadDropRate   = errors.New("the drop rate must be in [0, 1)")
+	errUnknownNode   = errors.New("unknown node")
+	errAlreadyClosed = errors.New("the network has already been shut down")
+)
+
+// Config describes a simulated network
+type Config struct {
+	// Log is used by the network and every node in it. Defaults to no logging.
+	Log logging.Logger
+	// LogFactory creates the logs of the chains. Defaults to no logging.
+	LogFactory logging.Factory
+
+	// Number of virtual nodes in the network. Every node is a default subnet
+	// validator of equal weight.
+	NumNodes  int
+	NetworkID uint32
+
+	// Consensus parameters used by every chain on every node. The metrics
+	// registerer is ignored, as every node registers its metrics separately.
+	ConsensusParams avacon.Parameters
+
+	// Every message is delayed by Latency plus a uniformly random duration
+	// in [0, Jitter)
+	Latency, Jitter time.Duration
+	// Probability that a message is silently dropped
+	DropRate float64
+	// Seeds the randomness used for jitter and dropping messages
+	Seed int64
+}
+
+// Network is a set of virtual nodes that run the full chain manager and
+// consensus stack and that exchange consensus messages in memory.
+type Network struct {
+	log        logging.Logger
+	logFactory logging.Factory
+	networkID  uint32
+	genesis    []byte
+
+	// lock protects everything below. It is never held while a message is
+	// delivered, so engines may send messages while handling others.
+	lock       sync.Mutex
+	rng        *rand.Rand
+	latency    time.Duration
+	jitter     time.Duration
+	dropRate   float64
+	nodes      []*Node
+	nodeMap    map[[20]byte]*Node
+	partitions map[[20]byte]int
+	byzantine  map[[20]byte]Byzantine
+	started    bool
+
+	// chains that haven't been created on every node yet, keyed by chain ID
+	pending map[[32]byte][]*networking.AwaitingConnections
+
+	// closeLock is held for reading while a message is delivered and for
+	// writing while the network is being shut down
+	closeLock sync.RWMutex
+	closed    bool
+}
+
+// New returns a network of [config.NumNodes] virtual nodes. The network
+// doesn't run any chains until Start is called.
+func New(config Config) (*Network, error) {
+	switch {
+	case config.NumNodes <= 0:
+		return nil, errNoNodes
+	case config.DropRate < 0 || config.DropRate >= 1:
+		return nil, errBadDropRate
+	}
+	if config.Log == nil {
+		config.Log = logging.NoLog{}
+	}
+	if config.LogFactory == nil {
+		config.LogFactory = logging.NoFactory{}
+	}
+
+	net := &Network{
+		log:        config.Log,
+		logFactory: config.LogFactory,
+		networkID:  config.NetworkID,
+		rng:        rand.New(rand.NewSource(config.Seed)),
+		latency:    config.Latency,
+		jitter:     config.Jitter,
+		dropRate:   config.DropRate,
+		nodeMap:    make(map[[20]byte]*Node),
+		byzantine:  make(map[[20]byte]Byzantine),
+		pending:    make(map[[32]byte][]*networking.AwaitingConnections),
+	}
+
+	nodeIDs := make([]ids.ShortID, config.NumNodes)
+	for i := range nodeIDs {
+		nodeIDs[i] = ids.NewShortID(hashing.ComputeHash160Array([]byte(fmt.Sprintf("node %d", i))))
+	}
+
+	genesis, err := Genesis(config.NetworkID, nodeIDs, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("couldn't build the genesis: %w", err)
+	}
+	net.genesis = genesis
+
+	for _, nodeID := range nodeIDs {
+		node, err := newNode(net, nodeID, config.ConsensusParams)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't create node %s: %w", nodeID, err)
+		}
+		net.nodes = append(net.nodes, node)
+		net.nodeMap[nodeID.Key()] = node
+	}
+	return net, nil
+}
+
+// Start creates the platform chain on every node. The platform chain creates
+// the remaining chains once it has bootstrapped.
+func (net *Network) Start() error {
+	net.lock.Lock()
+	if net.started {
+		net.lock.Unlock()
+		return errors.New("the network has already been started")
+	}
+	net.started = true
+	nodes := net.nodes
+	net.lock.Unlock()
+
+	for _, node := range nodes {
+		node.chainManager.ForceCreateChain(chains.ChainParameters{
+			ID:          ids.Empty,
+			SubnetID:    platformvm.DefaultSubnetID,
+			GenesisData: net.genesis,
+			VMAlias:     platformvm.ID.String(),
+		})
+	}
+	return nil
+}
+
+// GenesisBytes returns the genesis of the platform chain
+func (net *Network) GenesisBytes() []byte { return net.genesis }
+
+// Nodes returns the nodes in this network
+func (net *Network) Nodes() []*Node {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	nodes := make([]*Node, len(net.nodes))
+	copy(nodes, net.nodes)
+	return nodes
+}
+
+// Node returns the node with ID [nodeID]
+func (net *Network) Node(nodeID ids.ShortID) (*Node, error) {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	node, exists := net.nodeMap[nodeID.Key()]
+	if !exists {
+		return nil, errUnknownNode
+	}
+	return node, nil
+}
+
+// Partition splits the network so that messages are only delivered between
+// nodes in the same group. Nodes that aren't in any group form a group of
+// their own. Partition replaces any existing partition.
+func (net *Network) Partition(groups ...[]ids.ShortID) {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	net.partitions = make(map[[20]byte]int)
+	for i, group := range groups {
+		for _, nodeID := range group {
+			net.partitions[nodeID.Key()] = i + 1
+		}
+	}
+}
+
+// Heal removes any partition
+func (net *Network) Heal() {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	net.partitions = nil
+}
+
+// SetByzantine makes the messages sent by [nodeID] pass through [behavior].
+// If [behavior] is nil, the node behaves correctly again.
+func (net *Network) SetByzantine(nodeID ids.ShortID, behavior Byzantine) error {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	if _, exists := net.nodeMap[nodeID.Key()]; !exists {
+		return errUnknownNode
+	}
+	if behavior == nil {
+		delete(net.byzantine, nodeID.Key())
+	} else {
+		net.byzantine[nodeID.Key()] = behavior
+	}
+	return nil
+}
+
+// SetLatency changes the delay of messages sent from now on
+func (net *Network) SetLatency(latency, jitter time.Duration) {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	net.latency = latency
+	net.jitter = jitter
+}
+
+// SetDropRate changes the probability that a message sent from now on is
+// dropped
+func (net *Network) SetDropRate(dropRate float64) error {
+	if dropRate < 0 || dropRate >= 1 {
+		return errBadDropRate
+	}
+
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	net.dropRate = dropRate
+	return nil
+}
+
+// Shutdown stops delivering messages and shuts down every chain on every node
+func (net *Network) Shutdown() error {
+	net.closeLock.Lock()
+	if net.closed {
+		net.closeLock.Unlock()
+		return errAlreadyClosed
+	}
+	net.closed = true
+	net.closeLock.Unlock()
+
+	for _, node := range net.Nodes() {
+		node.shutdown()
+	}
+	return nil
+}
+
+// registered is called once [chainID] has been created on a node. The
+// engines of a chain are only started after the chain has been created on
+// every node, so that no bootstrapping request is sent to a node that can't
+// answer it yet.
+func (net *Network) registered(chainID ids.ID, awaiting *networking.AwaitingConnections) {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	key := chainID.Key()
+	pending := append(net.pending[key], awaiting)
+	if len(pending) < len(net.nodes) {
+		net.pending[key] = pending
+		return
+	}
+	delete(net.pending, key)
+
+	net.log.Debug("chain %s has been created on every node", chainID)
+	for _, awaiting := range pending {
+		if awaiting != nil {
+			// Finish grabs the chain's context lock, which may be held by the
+			// caller
+			go awaiting.Finish()
+		}
+	}
+}
+
+// send puts [msg] on the wire
+func (net *Network) send(msg *Message) {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	msgs := []*Message{msg}
+	if behavior, exists := net.byzantine[msg.From.Key()]; exists {
+		msgs = behavior.Tamper(msg)
+	}
+
+	for _, msg := range msgs {
+		to, exists := net.nodeMap[msg.To.Key()]
+		switch {
+		case !exists:
+			net.log.Debug("dropping %s because the recipient doesn't exist", msg)
+			continue
+		case !net.connected(msg.From, msg.To):
+			net.log.Verbo("dropping %s because of a partition", msg)
+			continue
+		case net.rng.Float64() < net.dropRate:
+			net.log.Verbo("randomly dropping %s", msg)
+			continue
+		}
+
+		delay := net.latency
+		if net.jitter > 0 {
+			delay += time.Duration(net.rng.Int63n(int64(net.jitter)))
+		}
+
+		msg := msg
+		time.AfterFunc(delay, func() { net.deliver(to, msg) })
+	}
+}
+
+// connected returns true if messages can be sent from [from] to [to]
+// assumes the lock is held
+func (net *Network) connected(from, to ids.ShortID) bool {
+	return net.partitions == nil || net.partitions[from.Key()] == net.partitions[to.Key()]
+}
+
+// deliver passes [msg] to the router of [to]
+func (net *Network) deliver(to *Node, msg *Message) {
+	net.closeLock.RLock()
+	defer net.closeLock.RUnlock()
+
+	if net.closed {
+		return
+	}
+
+	net.log.Verbo("delivering %s", msg)
+	router := &to.router
+	switch msg.Op {
+	case GetAcceptedFrontier:
+		router.GetAcceptedFrontier(msg.From, msg.ChainID, msg.RequestID)
+	case AcceptedFrontier:
+		router.AcceptedFrontier(msg.From, msg.ChainID, msg.RequestID, msg.ContainerIDs)
+	case GetAccepted:
+		router.GetAccepted(msg.From, msg.ChainID, msg.RequestID, msg.ContainerIDs)
+	case Accepted:
+		router.Accepted(msg.From, msg.ChainID, msg.RequestID, msg.ContainerIDs)
+	case GetAncestors:
+		router.GetAncestors(msg.From, msg.ChainID, msg.RequestID, msg.ContainerID)
+	case MultiPut:
+		router.MultiPut(msg.From, msg.ChainID, msg.RequestID, msg.Containers)
+	case Get:
+		router.Get(msg.From, msg.ChainID, msg.RequestID, msg.ContainerID)
+	case Put:
+		router.Put(msg.From, msg.ChainID, msg.RequestID, msg.ContainerID, msg.Container)
+	case PushQuery:
+		router.PushQuery(msg.From, msg.ChainID, msg.RequestID, msg.ContainerID, msg.Container)
+	case PullQuery:
+		router.PullQuery(msg.From, msg.ChainID, msg.RequestID, msg.ContainerID)
+	case Chits:
+		router.Chits(msg.From, msg.ChainID, msg.RequestID, msg.ContainerIDs)
+	default:
+		net.log.Warn("dropping message with unknown op: %s", msg)
+	}
+}
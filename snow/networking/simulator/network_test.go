@@ -0,0 +1,364 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/api/keystore"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/consensus/snowball"
+	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/json"
+	"github.com/ava-labs/gecko/vms/avm"
+	"github.com/ava-labs/gecko/vms/timestampvm"
+
+	avacon "github.com/ava-labs/gecko/snow/consensus/avalanche"
+)
+
+const (
+	timeout      = 30 * time.Second
+	pollInterval = 10 * time.Millisecond
+)
+
+func defaultConfig(numNodes int) Config {
+	k := numNodes
+	if k > 10 {
+		k = 10
+	}
+	return Config{
+		NumNodes:  numNodes,
+		NetworkID: 12345,
+		ConsensusParams: avacon.Parameters{
+			Parameters: snowball.Parameters{
+				K:            k,
+				Alpha:        k/2 + 1,
+				BetaVirtuous: 5,
+				BetaRogue:    10,
+			},
+			Parents:   2,
+			BatchSize: 1,
+		},
+		Latency: time.Millisecond,
+		Jitter:  5 * time.Millisecond,
+		Seed:    1,
+	}
+}
+
+// startNetwork starts [net] and waits for every genesis chain to be created
+// on every node. It returns the IDs of the AVM and timestamp chains.
+func startNetwork(t *testing.T, net *Network) (ids.ID, ids.ID) {
+	genesisChains, err := GenesisChains(net.GenesisBytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	avmID, timestampID := ids.ID{}, ids.ID{}
+	for _, chain := range genesisChains {
+		switch {
+		case chain.VMID.Equals(avm.ID):
+			avmID = chain.ID()
+		case chain.VMID.Equals(timestampvm.ID):
+			timestampID = chain.ID()
+		}
+	}
+	if avmID.IsZero() || timestampID.IsZero() {
+		t.Fatalf("genesis doesn't contain the AVM and timestamp chains")
+	}
+
+	if err := net.Start(); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "every chain to be created on every node", func() bool {
+		for _, node := range net.Nodes() {
+			if !node.HasChain(ids.Empty) || !node.HasChain(avmID) || !node.HasChain(timestampID) {
+				return false
+			}
+		}
+		return true
+	})
+	eventually(t, "every chain to be bootstrapped on every node", func() bool {
+		for _, node := range net.Nodes() {
+			if !node.Bootstrapped(ids.Empty) || !node.Bootstrapped(avmID) || !node.Bootstrapped(timestampID) {
+				return false
+			}
+		}
+		return true
+	})
+	return avmID, timestampID
+}
+
+func eventually(t *testing.T, desc string, f func() bool) {
+	deadline := time.Now().Add(timeout)
+	for !f() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", desc)
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+func contains(list []ids.ID, id ids.ID) bool {
+	for _, elem := range list {
+		if elem.Equals(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// proposeBlock proposes a timestamp block containing [data] on [node] and
+// returns the ID of the block once [node] has accepted it. The proposal is
+// repeated in case the chain was still bootstrapping.
+func proposeBlock(t *testing.T, node *Node, chainID ids.ID, data byte) ids.ID {
+	dataBytes := [32]byte{data}
+	args := timestampvm.ProposeBlockArgs{
+		Data: formatting.CB58{Bytes: dataBytes[:]}.String(),
+	}
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		reply := timestampvm.ProposeBlockReply{}
+		if err := node.Call(chainID, "timestamp.proposeBlock", &args, &reply); err != nil {
+			t.Fatal(err)
+		}
+
+		retry := time.Now().Add(2 * time.Second)
+		for time.Now().Before(retry) {
+			block := timestampvm.GetBlockReply{}
+			if err := node.Call(chainID, "timestamp.getBlock", &timestampvm.GetBlockArgs{}, &block); err != nil {
+				t.Fatal(err)
+			}
+			if block.Data == args.Data {
+				blockID, err := ids.FromString(block.ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return blockID
+			}
+			time.Sleep(pollInterval)
+		}
+	}
+	t.Fatalf("block %d was never accepted by %s", data, node)
+	return ids.ID{}
+}
+
+// assertAgreement waits until every node in [nodes] has accepted the same
+// containers on [chainID]
+func assertAgreement(t *testing.T, nodes []*Node, chainID ids.ID) {
+	eventually(t, fmt.Sprintf("nodes to agree on chain %s", chainID), func() bool {
+		expected := nodes[0].Accepted(chainID)
+		for _, node := range nodes[1:] {
+			accepted := node.Accepted(chainID)
+			if len(accepted) != len(expected) {
+				return false
+			}
+			for i, containerID := range accepted {
+				if !containerID.Equals(expected[i]) {
+					return false
+				}
+			}
+		}
+		return true
+	})
+}
+
+// assertAccepted waits until every node in [nodes] has accepted [containerID]
+// on [chainID]
+func assertAccepted(t *testing.T, nodes []*Node, chainID, containerID ids.ID) {
+	eventually(t, fmt.Sprintf("%s to be accepted", containerID), func() bool {
+		for _, node := range nodes {
+			if !contains(node.Accepted(chainID), containerID) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func TestNetworkTimestampFinality(t *testing.T) {
+	net, err := New(defaultConfig(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer net.Shutdown()
+
+	_, timestampID := startNetwork(t, net)
+
+	nodes := net.Nodes()
+	for i := 0; i < 3; i++ {
+		blockID := proposeBlock(t, nodes[i], timestampID, byte(i+1))
+		assertAccepted(t, nodes, timestampID, blockID)
+	}
+	assertAgreement(t, nodes, timestampID)
+}
+
+func TestNetworkAVMFinality(t *testing.T) {
+	net, err := New(defaultConfig(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer net.Shutdown()
+
+	avmID, _ := startNetwork(t, net)
+
+	nodes := net.Nodes()
+	sender := nodes[0]
+
+	user := keystore.CreateUserArgs{
+		Username: "bob",
+		Password: "launch rocket to the moon",
+	}
+	if err := sender.Keystore().CreateUser(nil, &user, &keystore.CreateUserReply{}); err != nil {
+		t.Fatal(err)
+	}
+
+	key := formatting.CB58{}
+	if err := key.FromString(GenesisKey); err != nil {
+		t.Fatal(err)
+	}
+	importArgs := avm.ImportKeyArgs{
+		Username:   user.Username,
+		Password:   user.Password,
+		PrivateKey: key,
+	}
+	if err := sender.Call(avmID, "avm.importKey", &importArgs, &avm.ImportKeyReply{}); err != nil {
+		t.Fatal(err)
+	}
+
+	to := fmt.Sprintf("%s-%s", avmID, ids.ShortEmpty)
+
+	// Creating an asset is deterministic, so the transaction can be re-issued
+	// until the chain has finished bootstrapping
+	assetArgs := avm.CreateFixedCapAssetArgs{
+		Name:   "Simulated Asset",
+		Symbol: "SIM",
+		InitialHolders: []*avm.Holder{&avm.Holder{
+			Amount:  json.Uint64(500),
+			Address: to,
+		}},
+	}
+	assetReply := avm.CreateFixedCapAssetReply{}
+	eventually(t, "the asset to be created", func() bool {
+		if err := sender.Call(avmID, "avm.createFixedCapAsset", &assetArgs, &assetReply); err != nil {
+			t.Fatal(err)
+		}
+		return contains(sender.Accepted(avmID), assetReply.AssetID)
+	})
+	assertAccepted(t, nodes, avmID, assetReply.AssetID)
+
+	sendArgs := avm.SendArgs{
+		Username: user.Username,
+		Password: user.Password,
+		Amount:   1000,
+		AssetID:  "AVA",
+		To:       to,
+	}
+	sendReply := avm.SendReply{}
+	if err := sender.Call(avmID, "avm.send", &sendArgs, &sendReply); err != nil {
+		t.Fatal(err)
+	}
+	assertAccepted(t, nodes, avmID, sendReply.TxID)
+
+	for _, node := range nodes {
+		for assetID, expected := range map[string]json.Uint64{
+			"AVA":                       1000,
+			assetReply.AssetID.String(): 500,
+		} {
+			balance := avm.GetBalanceReply{}
+			if err := node.Call(avmID, "avm.getBalance", &avm.GetBalanceArgs{Address: to, AssetID: assetID}, &balance); err != nil {
+				t.Fatal(err)
+			}
+			if balance.Balance != expected {
+				t.Fatalf("%s reports a balance of %d of %s but it should be %d", node, balance.Balance, assetID, expected)
+			}
+		}
+	}
+}
+
+func TestNetworkPartition(t *testing.T) {
+	net, err := New(defaultConfig(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer net.Shutdown()
+
+	_, timestampID := startNetwork(t, net)
+
+	nodes := net.Nodes()
+	minority, majority := nodes[:2], nodes[2:]
+	net.Partition([]ids.ShortID{minority[0].ID, minority[1].ID})
+
+	first := proposeBlock(t, majority[0], timestampID, 1)
+	assertAccepted(t, majority, timestampID, first)
+	for _, node := range minority {
+		if contains(node.Accepted(timestampID), first) {
+			t.Fatalf("%s accepted a block while partitioned", node)
+		}
+	}
+
+	net.Heal()
+
+	second := proposeBlock(t, majority[0], timestampID, 2)
+	assertAccepted(t, nodes, timestampID, second)
+	assertAgreement(t, nodes, timestampID)
+}
+
+func TestNetworkByzantine(t *testing.T) {
+	config := defaultConfig(7)
+	config.DropRate = .01
+	net, err := New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer net.Shutdown()
+
+	nodes := net.Nodes()
+	if err := net.SetByzantine(nodes[5].ID, RandomVoter{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := net.SetByzantine(nodes[6].ID, Mute{}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, timestampID := startNetwork(t, net)
+
+	correct := nodes[:5]
+	for i := 0; i < 3; i++ {
+		blockID := proposeBlock(t, correct[i], timestampID, byte(i+1))
+		assertAccepted(t, correct, timestampID, blockID)
+	}
+	assertAgreement(t, correct, timestampID)
+}
+
+func TestNetworkManyNodes(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping large network in short mode")
+	}
+
+	net, err := New(defaultConfig(20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer net.Shutdown()
+
+	_, timestampID := startNetwork(t, net)
+
+	nodes := net.Nodes()
+	blockID := proposeBlock(t, nodes[0], timestampID, 1)
+	assertAccepted(t, nodes, timestampID, blockID)
+	assertAgreement(t, nodes, timestampID)
+}
+
+func TestNetworkConfigValidation(t *testing.T) {
+	if _, err := New(Config{}); err != errNoNodes {
+		t.Fatalf("expected %s but got %v", errNoNodes, err)
+	}
+	config := defaultConfig(1)
+	config.DropRate = 1
+	if _, err := New(config); err != errBadDropRate {
+		t.Fatalf("expected %s but got %v", errBadDropRate, err)
+	}
+}
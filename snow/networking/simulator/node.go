@@ -0,0 +1,317 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/gorilla/rpc/v2/json2"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/gecko/api"
+	"github.com/ava-labs/gecko/api/keystore"
+	"github.com/ava-labs/gecko/chains"
+	"github.com/ava-labs/gecko/chains/atomic"
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/snow/networking"
+	"github.com/ava-labs/gecko/snow/networking/router"
+	"github.com/ava-labs/gecko/snow/triggers"
+	"github.com/ava-labs/gecko/snow/validators"
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/utils/wrappers"
+	"github.com/ava-labs/gecko/vms"
+	"github.com/ava-labs/gecko/vms/avm"
+	"github.com/ava-labs/gecko/vms/nftfx"
+	"github.com/ava-labs/gecko/vms/platformvm"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+	"github.com/ava-labs/gecko/vms/spchainvm"
+	"github.com/ava-labs/gecko/vms/spdagvm"
+	"github.com/ava-labs/gecko/vms/timestampvm"
+
+	avacon "github.com/ava-labs/gecko/snow/consensus/avalanche"
+)
+
+var (
+	errUnknownChain   = errors.New("the chain doesn't exist on this node")
+	errNoHandler      = errors.New("the chain doesn't have an API handler")
+	errUnknownLocking = errors.New("the API handler has an unknown lock option")
+)
+
+// Node is a virtual node in a simulated network. Every node has its own
+// database, chain manager, router and validator set.
+type Node struct {
+	ID ids.ShortID
+
+	net    *Network
+	log    logging.Logger
+	router router.ChainRouter
+	server api.Server
+
+	keystore        keystore.Keystore
+	sharedMemory    atomic.Memory
+	decisionEvents  triggers.EventDispatcher
+	consensusEvents triggers.EventDispatcher
+	vdrs            validators.Manager
+	vmManager       vms.Manager
+	chainManager    chains.Manager
+
+	// lock protects awaiting and chains
+	lock sync.Mutex
+	// the connection request of the chain that is currently being created
+	awaiting *networking.AwaitingConnections
+	chains   map[[32]byte]*chain
+}
+
+// chain is a chain running on a node
+type chain struct {
+	ctx      *snow.Context
+	vm       interface{}
+	handler  *common.HTTPHandler
+	accepted []ids.ID
+}
+
+func newNode(net *Network, id ids.ShortID, consensusParams avacon.Parameters) (*Node, error) {
+	n := &Node{
+		ID:     id,
+		net:    net,
+		log:    net.log,
+		vdrs:   validators.NewManager(),
+		chains: make(map[[32]byte]*chain),
+	}
+
+	db := memdb.New()
+
+	n.decisionEvents.Initialize(n.log)
+	n.consensusEvents.Initialize(n.log)
+	if err := n.decisionEvents.Register("simulator", n); err != nil {
+		return nil, err
+	}
+
+	n.server.Initialize(n.log, net.logFactory, 0)
+	n.keystore.Initialize(n.log, prefixdb.New([]byte("keystore"), db))
+	n.sharedMemory.Initialize(n.log, prefixdb.New([]byte("shared memory"), db))
+
+	// The platform chain populates the default subnet from its genesis
+	n.vdrs.PutValidatorSet(platformvm.DefaultSubnetID, validators.NewSet())
+
+	// Every node registers its metrics separately
+	consensusParams.Metrics = prometheus.NewRegistry()
+
+	n.vmManager = vms.NewManager(&n.server, n.log)
+	n.chainManager = chains.New(
+		n.log,
+		net.logFactory,
+		n.vmManager,
+		&n.decisionEvents,
+		&n.consensusEvents,
+		db,
+		&n.router,
+		&sender{net: net, id: id},
+		consensusParams,
+		0.5, // Half of the stake must agree on the containers that are bootstrapped
+		common.DefaultBootstrapRetries,
+		1000, // Messages buffered by each chain
+		200,  // Messages from each peer buffered by each chain
+		n.vdrs,
+		id,
+		net.networkID,
+		n,
+		&n.server,
+		&n.keystore,
+		&n.sharedMemory,
+		nil,
+	)
+	n.chainManager.AddRegistrant(n)
+
+	avmChainID, avaAssetID, err := genesisAVA(net.genesis)
+	if err != nil {
+		return nil, err
+	}
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		n.vmManager.RegisterVMFactory(avm.ID, &avm.Factory{
+			AVA:      avaAssetID,
+			Platform: ids.Empty,
+		}),
+		n.vmManager.RegisterVMFactory(spdagvm.ID, &spdagvm.Factory{}),
+		n.vmManager.RegisterVMFactory(spchainvm.ID, &spchainvm.Factory{}),
+		n.vmManager.RegisterVMFactory(secp256k1fx.ID, &secp256k1fx.Factory{}),
+		n.vmManager.RegisterVMFactory(nftfx.ID, &nftfx.Factory{}),
+		n.vmManager.RegisterVMFactory(timestampvm.ID, &timestampvm.Factory{}),
+		n.vmManager.RegisterVMFactory(platformvm.ID, &platformvm.Factory{
+			ChainManager: n.chainManager,
+			Validators:   n.vdrs,
+			AVM:          avmChainID,
+			AVA:          avaAssetID,
+		}),
+	)
+	return n, errs.Err
+}
+
+// Keystore returns the keystore of this node
+func (n *Node) Keystore() *keystore.Keystore { return &n.keystore }
+
+// Validators returns the validators of the default subnet, according to this
+// node
+func (n *Node) Validators() validators.Set {
+	vdrs, _ := n.vdrs.GetValidatorSet(platformvm.DefaultSubnetID)
+	return vdrs
+}
+
+// Chains returns the IDs of the chains that have been created on this node
+func (n *Node) Chains() []ids.ID {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	chainIDs := []ids.ID(nil)
+	for _, chain := range n.chains {
+		if chain.ctx != nil {
+			chainIDs = append(chainIDs, chain.ctx.ChainID)
+		}
+	}
+	return chainIDs
+}
+
+// HasChain returns true if [chainID] has been created on this node
+func (n *Node) HasChain(chainID ids.ID) bool {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	chain, exists := n.chains[chainID.Key()]
+	return exists && chain.ctx != nil
+}
+
+// Bootstrapped returns true if this node finished bootstrapping [chainID]
+func (n *Node) Bootstrapped(chainID ids.ID) bool {
+	status, err := n.chainManager.BootstrapProgress(chainID)
+	return err == nil && status.Bootstrapped
+}
+
+// Accepted returns, in order, the IDs of the containers this node has
+// accepted on [chainID]. Containers accepted while bootstrapping aren't
+// included.
+func (n *Node) Accepted(chainID ids.ID) []ids.ID {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	chain, exists := n.chains[chainID.Key()]
+	if !exists {
+		return nil
+	}
+	accepted := make([]ids.ID, len(chain.accepted))
+	copy(accepted, chain.accepted)
+	return accepted
+}
+
+// Call makes the JSON RPC call [method] to the API of [chainID] on this node,
+// as if it was made over HTTP
+func (n *Node) Call(chainID ids.ID, method string, args, reply interface{}) error {
+	n.lock.Lock()
+	chain, exists := n.chains[chainID.Key()]
+	n.lock.Unlock()
+
+	switch {
+	case !exists || chain.ctx == nil:
+		return errUnknownChain
+	case chain.handler == nil:
+		return errNoHandler
+	}
+
+	body, err := json2.EncodeClientRequest(method, args)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", "*", bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	writer := httptest.NewRecorder()
+	switch chain.handler.LockOptions {
+	case common.WriteLock:
+		chain.ctx.Lock.Lock()
+		chain.handler.Handler.ServeHTTP(writer, req)
+		chain.ctx.Lock.Unlock()
+	case common.ReadLock:
+		chain.ctx.Lock.RLock()
+		chain.handler.Handler.ServeHTTP(writer, req)
+		chain.ctx.Lock.RUnlock()
+	case common.NoLock:
+		chain.handler.Handler.ServeHTTP(writer, req)
+	default:
+		return errUnknownLocking
+	}
+	return json2.DecodeClientResponse(writer.Body, reply)
+}
+
+// AwaitConnections implements the chains.Awaiter interface. Every node is
+// always connected to every other node, however the chain isn't started until
+// it has been created on every node.
+func (n *Node) AwaitConnections(awaiting *networking.AwaitingConnections) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.awaiting = awaiting
+}
+
+// RegisterChain implements the chains.Registrant interface
+// The chain manager calls AwaitConnections and then RegisterChain while
+// creating a chain, so the pending connection request belongs to this chain.
+func (n *Node) RegisterChain(ctx *snow.Context, vm interface{}) {
+	n.lock.Lock()
+	awaiting := n.awaiting
+	n.awaiting = nil
+
+	chain := n.chain(ctx.ChainID)
+	chain.ctx = ctx
+	chain.vm = vm
+	if vm, ok := vm.(common.VM); ok {
+		chain.handler = vm.CreateHandlers()[""]
+	}
+	n.lock.Unlock()
+
+	n.log.Debug("node %s created chain %s", n.ID, ctx.ChainID)
+	n.net.registered(ctx.ChainID, awaiting)
+}
+
+// Accept implements the triggers.Acceptor interface
+func (n *Node) Accept(chainID, containerID ids.ID, _ []byte) error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	chain := n.chain(chainID)
+	chain.accepted = append(chain.accepted, containerID)
+	return nil
+}
+
+// chain returns the chain with ID [chainID], creating it if it doesn't exist
+// yet
+// assumes the lock is held
+func (n *Node) chain(chainID ids.ID) *chain {
+	key := chainID.Key()
+	c, exists := n.chains[key]
+	if !exists {
+		c = &chain{}
+		n.chains[key] = c
+	}
+	return c
+}
+
+func (n *Node) shutdown() {
+	n.log.Debug("shutting down node %s", n.ID)
+	n.chainManager.Shutdown()
+}
+
+func (n *Node) String() string { return fmt.Sprintf("Node %s", n.ID) }
@@ -0,0 +1,144 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package simulator
+
+import (
+	"github.com/ava-labs/gecko/ids"
+)
+
+// sender implements the sender.ExternalSender interface by putting messages
+// on the simulated network
+type sender struct {
+	net *Network
+	id  ids.ShortID
+}
+
+// copySet returns a copy of [s], so that a message can't be modified by its
+// sender after it was sent
+func copySet(s ids.Set) ids.Set {
+	c := ids.Set{}
+	c.Union(s)
+	return c
+}
+
+func (s *sender) send(msg Message, validatorIDs ...ids.ShortID) {
+	for _, validatorID := range validatorIDs {
+		msg := msg
+		msg.From = s.id
+		msg.To = validatorID
+		msg.ContainerIDs = copySet(msg.ContainerIDs)
+		s.net.send(&msg)
+	}
+}
+
+// GetAcceptedFrontier implements the ExternalSender interface
+func (s *sender) GetAcceptedFrontier(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32) {
+	s.send(Message{
+		Op:        GetAcceptedFrontier,
+		ChainID:   chainID,
+		RequestID: requestID,
+	}, validatorIDs.List()...)
+}
+
+// AcceptedFrontier implements the ExternalSender interface
+func (s *sender) AcceptedFrontier(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerIDs ids.Set) {
+	s.send(Message{
+		Op:           AcceptedFrontier,
+		ChainID:      chainID,
+		RequestID:    requestID,
+		ContainerIDs: containerIDs,
+	}, validatorID)
+}
+
+// GetAccepted implements the ExternalSender interface
+func (s *sender) GetAccepted(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, containerIDs ids.Set) {
+	s.send(Message{
+		Op:           GetAccepted,
+		ChainID:      chainID,
+		RequestID:    requestID,
+		ContainerIDs: containerIDs,
+	}, validatorIDs.List()...)
+}
+
+// Accepted implements the ExternalSender interface
+func (s *sender) Accepted(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerIDs ids.Set) {
+	s.send(Message{
+		Op:           Accepted,
+		ChainID:      chainID,
+		RequestID:    requestID,
+		ContainerIDs: containerIDs,
+	}, validatorID)
+}
+
+// GetAncestors implements the ExternalSender interface
+func (s *sender) GetAncestors(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID) {
+	s.send(Message{
+		Op:          GetAncestors,
+		ChainID:     chainID,
+		RequestID:   requestID,
+		ContainerID: containerID,
+	}, validatorID)
+}
+
+// MultiPut implements the ExternalSender interface
+func (s *sender) MultiPut(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containers [][]byte) {
+	s.send(Message{
+		Op:         MultiPut,
+		ChainID:    chainID,
+		RequestID:  requestID,
+		Containers: containers,
+	}, validatorID)
+}
+
+// Get implements the ExternalSender interface
+func (s *sender) Get(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID) {
+	s.send(Message{
+		Op:          Get,
+		ChainID:     chainID,
+		RequestID:   requestID,
+		ContainerID: containerID,
+	}, validatorID)
+}
+
+// Put implements the ExternalSender interface
+func (s *sender) Put(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID, container []byte) {
+	s.send(Message{
+		Op:          Put,
+		ChainID:     chainID,
+		RequestID:   requestID,
+		ContainerID: containerID,
+		Container:   container,
+	}, validatorID)
+}
+
+// PushQuery implements the ExternalSender interface
+func (s *sender) PushQuery(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, containerID ids.ID, container []byte) {
+	s.send(Message{
+		Op:          PushQuery,
+		ChainID:     chainID,
+		RequestID:   requestID,
+		ContainerID: containerID,
+		Container:   container,
+	}, validatorIDs.List()...)
+}
+
+// PullQuery implements the ExternalSender interface
+func (s *sender) PullQuery(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, containerID ids.ID) {
+	s.send(Message{
+		Op:          PullQuery,
+		ChainID:     chainID,
+		RequestID:   requestID,
+		ContainerID: containerID,
+	}, validatorIDs.List()...)
+}
+
+// Chits implements the ExternalSender interface
+func (s *sender) Chits(validatorID ids.ShortID, chainID ids.ID, requestID uint32, votes ids.Set) {
+	s.send(Message{
+		Op:           Chits,
+		ChainID:      chainID,
+		RequestID:    requestID,
+		ContainerIDs: votes,
+	}, validatorID)
+}
@@ -33,9 +33,10 @@ func (m *Manager) Register(validatorID ids.ShortID, chainID ids.ID, requestID ui
 	m.tm.Put(createRequestID(validatorID, chainID, requestID), timeout)
 }
 
-// Cancel request timeout with the specified parameters.
-func (m *Manager) Cancel(validatorID ids.ShortID, chainID ids.ID, requestID uint32) {
-	m.tm.Remove(createRequestID(validatorID, chainID, requestID))
+// Cancel request timeout with the specified parameters. Returns true if the
+// request was outstanding.
+func (m *Manager) Cancel(validatorID ids.ShortID, chainID ids.ID, requestID uint32) bool {
+	return m.tm.Remove(createRequestID(validatorID, chainID, requestID))
 }
 
 func createRequestID(validatorID ids.ShortID, chainID ids.ID, requestID uint32) ids.ID {
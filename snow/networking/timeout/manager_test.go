@@ -36,7 +36,12 @@ func TestManagerCancel(t *testing.T) {
 
 	manager.Register(ids.NewShortID([20]byte{}), ids.NewID([32]byte{}), 0, func() { *fired = true })
 
-	manager.Cancel(ids.NewShortID([20]byte{}), ids.NewID([32]byte{}), 0)
+	if !manager.Cancel(ids.NewShortID([20]byte{}), ids.NewID([32]byte{}), 0) {
+		t.Fatalf("Should have reported that the request was outstanding")
+	}
+	if manager.Cancel(ids.NewShortID([20]byte{}), ids.NewID([32]byte{}), 0) {
+		t.Fatalf("Should have reported that the request was already cancelled")
+	}
 
 	manager.Register(ids.NewShortID([20]byte{}), ids.NewID([32]byte{}), 1, wg.Done)
 
@@ -31,6 +31,10 @@ type Set interface {
 	// currently in the set.
 	Contains(ids.ShortID) bool
 
+	// GetWeight returns the weight of the validator with the specified ID,
+	// and true if there is such a validator currently in the set.
+	GetWeight(ids.ShortID) (uint64, bool)
+
 	// Len returns the number of validators currently in the set.
 	Len() int
 
@@ -147,6 +151,22 @@ func (s *set) contains(vdrID ids.ShortID) bool {
 	return contains
 }
 
+// GetWeight implements the Set interface.
+func (s *set) GetWeight(vdrID ids.ShortID) (uint64, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.getWeight(vdrID)
+}
+
+func (s *set) getWeight(vdrID ids.ShortID) (uint64, bool) {
+	i, contains := s.vdrMap[vdrID.Key()]
+	if !contains {
+		return 0, false
+	}
+	return s.sampler.Weights[i], true
+}
+
 // Len implements the Set interface.
 func (s *set) Len() int {
 	s.lock.Lock()
@@ -126,6 +126,25 @@ func TestSamplerContains(t *testing.T) {
 	}
 }
 
+func TestSamplerGetWeight(t *testing.T) {
+	vdr := GenerateRandomValidator(5)
+
+	s := NewSet()
+	s.Add(vdr)
+
+	if weight, ok := s.GetWeight(vdr.ID()); !ok {
+		t.Fatalf("Should have contained validator")
+	} else if weight != 5 {
+		t.Fatalf("Wrong weight. Expected: %d ; Returned: %d", 5, weight)
+	}
+
+	s.Remove(vdr.ID())
+
+	if _, ok := s.GetWeight(vdr.ID()); ok {
+		t.Fatalf("Shouldn't have contained validator")
+	}
+}
+
 func TestSamplerString(t *testing.T) {
 	vdr0 := NewValidator(ids.ShortEmpty, 1)
 	vdr1 := NewValidator(
@@ -27,6 +27,15 @@ func (ipDesc IPDesc) Equal(otherIPDesc IPDesc) bool {
 		ipDesc.IP.Equal(otherIPDesc.IP)
 }
 
+// IsZero returns if the IP or port is zeroed out
+func (ipDesc IPDesc) IsZero() bool {
+	ip := ipDesc.IP
+	return ipDesc.Port == 0 ||
+		len(ip) == 0 ||
+		ip.Equal(net.IPv4zero) ||
+		ip.Equal(net.IPv6zero)
+}
+
 // PortString ...
 func (ipDesc IPDesc) PortString() string {
 	return fmt.Sprintf(":%d", ipDesc.Port)
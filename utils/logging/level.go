@@ -4,6 +4,7 @@
 package logging
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 )
@@ -82,3 +83,11 @@ func (l Level) String() string {
 		return "?????"
 	}
 }
+
+// MarshalJSON ...
+func (l Level) MarshalJSON() ([]byte, error) {
+	if l == Off {
+		return json.Marshal("off")
+	}
+	return json.Marshal(strings.ToLower(strings.TrimSpace(l.String())))
+}
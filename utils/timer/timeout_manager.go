@@ -50,12 +50,13 @@ func (tm *TimeoutManager) Put(id ids.ID, handler func()) {
 	tm.put(id, handler)
 }
 
-// Remove the item that no longer needs to be there.
-func (tm *TimeoutManager) Remove(id ids.ID) {
+// Remove the item that no longer needs to be there. Returns true if the item
+// was there.
+func (tm *TimeoutManager) Remove(id ids.ID) bool {
 	tm.lock.Lock()
 	defer tm.lock.Unlock()
 
-	tm.remove(id)
+	return tm.remove(id)
 }
 
 // Timeout registers a timeout
@@ -97,14 +98,15 @@ func (tm *TimeoutManager) put(id ids.ID, handler timeoutHandler) {
 	}
 }
 
-func (tm *TimeoutManager) remove(id ids.ID) {
+func (tm *TimeoutManager) remove(id ids.ID) bool {
 	key := id.Key()
 	e, exists := tm.timeoutMap[key]
 	if !exists {
-		return
+		return false
 	}
 	delete(tm.timeoutMap, key)
 	tm.timeoutList.Remove(e)
+	return true
 }
 
 // Returns true if the head was removed, false otherwise
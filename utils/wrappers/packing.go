@@ -252,6 +252,26 @@ func (p *Packer) UnpackFixedByteSlices(size int) [][]byte {
 	return bytes
 }
 
+// Pack2DByteSlice append a slice of byte slices, each with its own length
+// descriptor, to the byte array
+func (p *Packer) Pack2DByteSlice(byteSlices [][]byte) {
+	p.PackInt(uint32(len(byteSlices)))
+	for _, bytes := range byteSlices {
+		p.PackBytes(bytes)
+	}
+}
+
+// Unpack2DByteSlice unpack a slice of byte slices, each with its own length
+// descriptor, from the byte array
+func (p *Packer) Unpack2DByteSlice() [][]byte {
+	sliceSize := p.UnpackInt()
+	bytes := [][]byte(nil)
+	for i := uint32(0); i < sliceSize && !p.Errored(); i++ {
+		bytes = append(bytes, p.UnpackBytes())
+	}
+	return bytes
+}
+
 // PackStr append a string to the byte array
 func (p *Packer) PackStr(str string) {
 	strSize := len(str)
@@ -428,6 +448,20 @@ func TryUnpackBytes(packer *Packer) interface{} {
 	return packer.UnpackBytes()
 }
 
+// TryPack2DBytes attempts to pack the value as a list of byte slices
+func TryPack2DBytes(packer *Packer, valIntf interface{}) {
+	if val, ok := valIntf.([][]byte); ok {
+		packer.Pack2DByteSlice(val)
+	} else {
+		packer.Add(errBadType)
+	}
+}
+
+// TryUnpack2DBytes attempts to unpack the value as a list of byte slices
+func TryUnpack2DBytes(packer *Packer) interface{} {
+	return packer.Unpack2DByteSlice()
+}
+
 // TryPackStr attempts to pack the value as a string
 func TryPackStr(packer *Packer, valIntf interface{}) {
 	if val, ok := valIntf.(string); ok {
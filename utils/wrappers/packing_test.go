@@ -151,3 +151,40 @@ func TestPackBool(t *testing.T) {
 		t.Fatal("got back wrong values")
 	}
 }
+
+func TestPacker2DByteSlice(t *testing.T) {
+	p := Packer{MaxSize: 1024}
+	p.Pack2DByteSlice([][]byte{{0x01, 0x02}, {}, {0x03}})
+	if p.Errored() {
+		t.Fatal(p.Err)
+	}
+
+	expected := []byte{
+		0x00, 0x00, 0x00, 0x03,
+		0x00, 0x00, 0x00, 0x02, 0x01, 0x02,
+		0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x01, 0x03,
+	}
+	if !bytes.Equal(p.Bytes, expected) {
+		t.Fatalf("Packer.Pack2DByteSlice wrote:\n%v\nExpected:\n%v", p.Bytes, expected)
+	}
+
+	p2 := Packer{Bytes: p.Bytes}
+	byteSlices := p2.Unpack2DByteSlice()
+	if p2.Errored() {
+		t.Fatal(p2.Err)
+	}
+	if len(byteSlices) != 3 ||
+		!bytes.Equal(byteSlices[0], []byte{0x01, 0x02}) ||
+		len(byteSlices[1]) != 0 ||
+		!bytes.Equal(byteSlices[2], []byte{0x03}) {
+		t.Fatalf("Packer.Unpack2DByteSlice returned: %v", byteSlices)
+	}
+
+	// The length descriptor claims more slices than there are
+	p3 := Packer{Bytes: expected[:len(expected)-1]}
+	p3.Unpack2DByteSlice()
+	if !p3.Errored() {
+		t.Fatalf("should have errored due to the truncated byte array")
+	}
+}
@@ -86,8 +86,21 @@ func (t *BaseTx) UTXOs() []*UTXO {
 	return utxos
 }
 
-// SyntacticVerify that this transaction is well-formed.
-func (t *BaseTx) SyntacticVerify(ctx *snow.Context, c codec.Codec, _ int) error {
+// SyntacticVerify that this transaction is well-formed, and that it pays a fee
+// of [txFee] units of the asset [txFeeAssetID].
+func (t *BaseTx) SyntacticVerify(ctx *snow.Context, c codec.Codec, txFeeAssetID ids.ID, txFee uint64, _ int) error {
+	if err := t.verifyStructure(ctx, c); err != nil {
+		return err
+	}
+	if err := verifyBalance(txFeeAssetID, txFee, t.Ins, t.Outs); err != nil {
+		return err
+	}
+	return t.metadata.Verify()
+}
+
+// verifyStructure verifies that the fields of this transaction are
+// individually well-formed, without checking that the transaction is balanced.
+func (t *BaseTx) verifyStructure(ctx *snow.Context, c codec.Codec) error {
 	switch {
 	case t == nil:
 		return errNilTx
@@ -114,9 +127,15 @@ func (t *BaseTx) SyntacticVerify(ctx *snow.Context, c codec.Codec, _ int) error
 	if !isSortedAndUniqueTransferableInputs(t.Ins) {
 		return errInputsNotSortedUnique
 	}
+	return nil
+}
 
+// verifyBalance verifies that, for every asset, [ins] consume at least as much
+// as [outs] produce, and that they consume an additional [txFee] units of the
+// asset [txFeeAssetID]. Whatever is consumed but not produced is burnt.
+func verifyBalance(txFeeAssetID ids.ID, txFee uint64, ins []*TransferableInput, outs []*TransferableOutput) error {
 	consumedFunds := map[[32]byte]uint64{}
-	for _, in := range t.Ins {
+	for _, in := range ins {
 		assetID := in.AssetID()
 		amount := in.Input().Amount()
 
@@ -129,7 +148,7 @@ func (t *BaseTx) SyntacticVerify(ctx *snow.Context, c codec.Codec, _ int) error
 		}
 	}
 	producedFunds := map[[32]byte]uint64{}
-	for _, out := range t.Outs {
+	for _, out := range outs {
 		assetID := out.AssetID()
 		amount := out.Output().Amount()
 
@@ -142,7 +161,15 @@ func (t *BaseTx) SyntacticVerify(ctx *snow.Context, c codec.Codec, _ int) error
 		}
 	}
 
-	// TODO: Add the Tx fee to the producedFunds
+	if txFee > 0 {
+		var err error
+		assetIDKey := txFeeAssetID.Key()
+		producedFunds[assetIDKey], err = math.Add64(producedFunds[assetIDKey], txFee)
+
+		if err != nil {
+			return errOutputOverflow
+		}
+	}
 
 	for assetID, producedAssetAmount := range producedFunds {
 		consumedAssetAmount := consumedFunds[assetID]
@@ -150,12 +177,12 @@ func (t *BaseTx) SyntacticVerify(ctx *snow.Context, c codec.Codec, _ int) error
 			return errInsufficientFunds
 		}
 	}
-
-	return t.metadata.Verify()
+	return nil
 }
 
 // SemanticVerify that this transaction is valid to be spent.
 func (t *BaseTx) SemanticVerify(vm *VM, uTx *UniqueTx, creds []*Credential) error {
+	transfers := make(map[[32]byte]*transferTx)
 	for i, in := range t.Ins {
 		cred := creds[i]
 
@@ -178,7 +205,7 @@ func (t *BaseTx) SemanticVerify(vm *VM, uTx *UniqueTx, creds []*Credential) erro
 				return errIncompatibleFx
 			}
 
-			err = fx.VerifyTransfer(uTx, utxo.Out, in.In, cred.Cred)
+			err = fx.VerifyTransfer(t.transferTx(uTx, transfers, inAssetID), utxo.Out, in.In, cred.Cred)
 			if err == nil {
 				continue
 			}
@@ -215,9 +242,54 @@ func (t *BaseTx) SemanticVerify(vm *VM, uTx *UniqueTx, creds []*Credential) erro
 			return errIncompatibleFx
 		}
 
-		if err := fx.VerifyTransfer(uTx, utxo.Out, in.In, cred); err != nil {
+		if err := fx.VerifyTransfer(t.transferTx(uTx, transfers, inAssetID), utxo.Out, in.In, cred.Cred); err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+// transferTx returns the view of [uTx] that's passed to the fxs verifying the
+// transfers of [assetID]. The views are cached in [transfers], so that an
+// output can only be claimed once while the transaction is verified.
+func (t *BaseTx) transferTx(uTx *UniqueTx, transfers map[[32]byte]*transferTx, assetID ids.ID) *transferTx {
+	assetKey := assetID.Key()
+	if transfer, exists := transfers[assetKey]; exists {
+		return transfer
+	}
+	transfer := &transferTx{UniqueTx: uTx}
+	for _, out := range t.Outs {
+		if out.AssetID().Equals(assetID) {
+			transfer.outs = append(transfer.outs, out.Out)
+		}
+	}
+	transfer.claimed = make([]bool, len(transfer.outs))
+	transfers[assetKey] = transfer
+	return transfer
+}
+
+// transferTx is a transaction, as seen by an fx verifying the transfer of one
+// of its assets
+type transferTx struct {
+	*UniqueTx
+
+	outs    []interface{}
+	claimed []bool
+}
+
+// Outputs returns the outputs of the transaction of the transferred asset
+func (t *transferTx) Outputs() []interface{} { return t.outs }
+
+// Claim the output at [index]. Returns false if it was already claimed.
+func (t *transferTx) Claim(index int) bool {
+	if index < 0 || index >= len(t.claimed) || t.claimed[index] {
+		return false
+	}
+	t.claimed[index] = true
+	return true
+}
+
+// ExecuteWithSideEffects writes the state changes of this transaction to the
+// chain's database. Transactions that modify state outside of the chain
+// override this.
+func (t *BaseTx) ExecuteWithSideEffects(vm *VM) error { return vm.db.Commit() }
@@ -252,7 +252,7 @@ func TestBaseTxSyntacticVerify(t *testing.T) {
 	}
 	tx.Initialize([]byte{})
 
-	if err := tx.SyntacticVerify(ctx, c, 0); err != nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err != nil {
 		t.Fatal(err)
 	}
 }
@@ -269,7 +269,7 @@ func TestBaseTxSyntacticVerifyNil(t *testing.T) {
 	c.RegisterType(&secp256k1fx.Credential{})
 
 	tx := (*BaseTx)(nil)
-	if err := tx.SyntacticVerify(ctx, c, 0); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
 		t.Fatalf("Nil BaseTx should have errored")
 	}
 }
@@ -323,7 +323,7 @@ func TestBaseTxSyntacticVerifyWrongNetworkID(t *testing.T) {
 	}
 	tx.Initialize([]byte{})
 
-	if err := tx.SyntacticVerify(ctx, c, 0); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
 		t.Fatalf("Wrong networkID should have errored")
 	}
 }
@@ -377,7 +377,7 @@ func TestBaseTxSyntacticVerifyWrongChainID(t *testing.T) {
 	}
 	tx.Initialize([]byte{})
 
-	if err := tx.SyntacticVerify(ctx, c, 0); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
 		t.Fatalf("Wrong chain ID should have errored")
 	}
 }
@@ -422,7 +422,7 @@ func TestBaseTxSyntacticVerifyInvalidOutput(t *testing.T) {
 	}
 	tx.Initialize([]byte{})
 
-	if err := tx.SyntacticVerify(ctx, c, 0); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
 		t.Fatalf("Invalid output should have errored")
 	}
 }
@@ -486,7 +486,7 @@ func TestBaseTxSyntacticVerifyUnsortedOutputs(t *testing.T) {
 	}
 	tx.Initialize([]byte{})
 
-	if err := tx.SyntacticVerify(ctx, c, 0); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
 		t.Fatalf("Unsorted outputs should have errored")
 	}
 }
@@ -523,7 +523,7 @@ func TestBaseTxSyntacticVerifyInvalidInput(t *testing.T) {
 	}
 	tx.Initialize([]byte{})
 
-	if err := tx.SyntacticVerify(ctx, c, 0); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
 		t.Fatalf("Invalid input should have errored")
 	}
 }
@@ -595,7 +595,7 @@ func TestBaseTxSyntacticVerifyInputOverflow(t *testing.T) {
 	}
 	tx.Initialize([]byte{})
 
-	if err := tx.SyntacticVerify(ctx, c, 0); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
 		t.Fatalf("Input overflow should have errored")
 	}
 }
@@ -659,7 +659,7 @@ func TestBaseTxSyntacticVerifyOutputOverflow(t *testing.T) {
 	}
 	tx.Initialize([]byte{})
 
-	if err := tx.SyntacticVerify(ctx, c, 0); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
 		t.Fatalf("Output overflow should have errored")
 	}
 }
@@ -713,11 +713,74 @@ func TestBaseTxSyntacticVerifyInsufficientFunds(t *testing.T) {
 	}
 	tx.Initialize([]byte{})
 
-	if err := tx.SyntacticVerify(ctx, c, 0); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
 		t.Fatalf("Insufficient funds should have errored")
 	}
 }
 
+func TestBaseTxSyntacticVerifyFee(t *testing.T) {
+	c := codec.NewDefault()
+	c.RegisterType(&BaseTx{})
+	c.RegisterType(&CreateAssetTx{})
+	c.RegisterType(&OperationTx{})
+	c.RegisterType(&secp256k1fx.MintOutput{})
+	c.RegisterType(&secp256k1fx.TransferOutput{})
+	c.RegisterType(&secp256k1fx.MintInput{})
+	c.RegisterType(&secp256k1fx.TransferInput{})
+	c.RegisterType(&secp256k1fx.Credential{})
+
+	tx := &BaseTx{
+		NetID: networkID,
+		BCID:  chainID,
+		Outs: []*TransferableOutput{
+			&TransferableOutput{
+				Asset: Asset{ID: asset},
+				Out: &secp256k1fx.TransferOutput{
+					Amt: 12345,
+					OutputOwners: secp256k1fx.OutputOwners{
+						Threshold: 1,
+						Addrs:     []ids.ShortID{keys[0].PublicKey().Address()},
+					},
+				},
+			},
+		},
+		Ins: []*TransferableInput{
+			&TransferableInput{
+				UTXOID: UTXOID{
+					TxID: ids.NewID([32]byte{
+						0xff, 0xfe, 0xfd, 0xfc, 0xfb, 0xfa, 0xf9, 0xf8,
+						0xf7, 0xf6, 0xf5, 0xf4, 0xf3, 0xf2, 0xf1, 0xf0,
+						0xef, 0xee, 0xed, 0xec, 0xeb, 0xea, 0xe9, 0xe8,
+						0xe7, 0xe6, 0xe5, 0xe4, 0xe3, 0xe2, 0xe1, 0xe0,
+					}),
+					OutputIndex: 0,
+				},
+				Asset: Asset{ID: asset},
+				In: &secp256k1fx.TransferInput{
+					Amt: 12345 + 100,
+					Input: secp256k1fx.Input{
+						SigIndices: []uint32{2},
+					},
+				},
+			},
+		},
+	}
+	tx.Initialize([]byte{})
+
+	if err := tx.SyntacticVerify(ctx, c, asset, 100, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(ctx, c, asset, 101, 0); err != errInsufficientFunds {
+		t.Fatalf("Should have errored due to an underpaid fee")
+	}
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty.Prefix(0), 1, 0); err != errInsufficientFunds {
+		t.Fatalf("Should have errored due to the fee not being paid in the fee asset")
+	}
+	if err := tx.SyntacticVerify(ctx, c, asset, math.MaxUint64, 0); err != errOutputOverflow {
+		t.Fatalf("Should have errored due to the fee overflowing the outputs")
+	}
+}
+
 func TestBaseTxSyntacticVerifyUninitialized(t *testing.T) {
 	c := codec.NewDefault()
 	c.RegisterType(&BaseTx{})
@@ -766,7 +829,7 @@ func TestBaseTxSyntacticVerifyUninitialized(t *testing.T) {
 		},
 	}
 
-	if err := tx.SyntacticVerify(ctx, c, 0); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
 		t.Fatalf("Uninitialized tx should have errored")
 	}
 }
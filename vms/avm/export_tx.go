@@ -0,0 +1,100 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/database/versiondb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/vms/components/codec"
+	"github.com/ava-labs/gecko/vms/components/shared"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+var (
+	errNoExportOutputs   = errors.New("no export outputs")
+	errWrongExportOutput = errors.New("exported outputs must be secp256k1fx transfer outputs")
+	errNotAVA            = errors.New("only AVA can be transferred to or from the platform chain")
+)
+
+// ExportTx is a transaction that exports an asset to the platform chain.
+type ExportTx struct {
+	BaseTx `serialize:"true"`
+
+	ExportOuts []*TransferableOutput `serialize:"true"` // The outputs this transaction is sending to the platform chain
+}
+
+// SyntacticVerify that this transaction is well-formed.
+func (t *ExportTx) SyntacticVerify(ctx *snow.Context, c codec.Codec, txFeeAssetID ids.ID, txFee uint64, _ int) error {
+	switch {
+	case t == nil:
+		return errNilTx
+	case len(t.ExportOuts) == 0:
+		return errNoExportOutputs
+	}
+
+	if err := t.verifyStructure(ctx, c); err != nil {
+		return err
+	}
+
+	for _, out := range t.ExportOuts {
+		if err := out.Verify(); err != nil {
+			return err
+		}
+		if _, ok := out.Out.(*secp256k1fx.TransferOutput); !ok {
+			return errWrongExportOutput
+		}
+	}
+	if !isSortedTransferableOutputs(t.ExportOuts, c) {
+		return errOutputsNotSorted
+	}
+
+	outs := make([]*TransferableOutput, 0, len(t.Outs)+len(t.ExportOuts))
+	outs = append(outs, t.Outs...)
+	outs = append(outs, t.ExportOuts...)
+	if err := verifyBalance(txFeeAssetID, txFee, t.Ins, outs); err != nil {
+		return err
+	}
+	return t.metadata.Verify()
+}
+
+// SemanticVerify that this transaction is valid to be spent.
+func (t *ExportTx) SemanticVerify(vm *VM, uTx *UniqueTx, creds []*Credential) error {
+	for _, out := range t.ExportOuts {
+		if assetID := out.AssetID(); !assetID.Equals(vm.ava) {
+			return errNotAVA
+		}
+	}
+	return t.BaseTx.SemanticVerify(vm, uTx, creds)
+}
+
+// ExecuteWithSideEffects writes the state changes of this transaction to the
+// chain's database, and sends the exported outputs to the platform chain.
+//
+// Both are written in a single batch, so the exported funds are never
+// spendable on both chains, or on neither of them.
+func (t *ExportTx) ExecuteWithSideEffects(vm *VM) error {
+	smDB := vm.ctx.SharedMemory.GetDatabase(vm.platform)
+	defer vm.ctx.SharedMemory.ReleaseDatabase(vm.platform)
+
+	vsmDB := versiondb.New(smDB)
+	state := shared.NewState(vsmDB, vm.platform)
+
+	txID := t.ID()
+	for i, out := range t.ExportOuts {
+		utxo := &shared.UTXO{
+			TxID:        txID,
+			OutputIndex: uint32(len(t.Outs) + i),
+			AssetID:     out.AssetID(),
+			Out:         *out.Out.(*secp256k1fx.TransferOutput),
+		}
+		if err := state.FundUTXO(utxo); err != nil {
+			return err
+		}
+	}
+
+	return vm.commitWithSharedMemory(vsmDB)
+}
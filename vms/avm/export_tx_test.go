@@ -0,0 +1,384 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/gecko/chains/atomic"
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/vms/components/codec"
+	"github.com/ava-labs/gecko/vms/components/shared"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+var platformChainID = ids.Empty.Prefix(0)
+
+var errWriteFailed = errors.New("write failed")
+
+// failingDB is a database that, once [limitWrites] is set, fails to write any
+// batch after [writesLeft] more batches have been written, as a node crashing
+// would
+type failingDB struct {
+	*memdb.Database
+	limitWrites bool
+	writesLeft  int
+}
+
+func (db *failingDB) NewBatch() database.Batch {
+	return &failingBatch{Batch: db.Database.NewBatch(), db: db}
+}
+
+type failingBatch struct {
+	database.Batch
+	db *failingDB
+}
+
+func (b *failingBatch) Write() error {
+	if b.db.limitWrites {
+		if b.db.writesLeft == 0 {
+			return errWriteFailed
+		}
+		b.db.writesLeft--
+	}
+	return b.Batch.Write()
+}
+
+func (b *failingBatch) Inner() database.Batch { return b }
+
+// dbContents returns a copy of every key/value pair in [db]
+func dbContents(t *testing.T, db database.Database) map[string][]byte {
+	contents := make(map[string][]byte)
+	it := db.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		contents[string(it.Key())] = append([]byte(nil), it.Value()...)
+	}
+	if err := it.Error(); err != nil {
+		t.Fatal(err)
+	}
+	return contents
+}
+
+// AtomicVM returns a VM, whose AVA asset is the first asset of the test
+// genesis, that shares memory with the platform chain
+func AtomicVM(t *testing.T) (*VM, *atomic.Memory, *Tx) {
+	return atomicVMWithDB(t, memdb.New())
+}
+
+// atomicVMWithDB returns an AtomicVM whose database and shared memory are both
+// stored in [baseDB], as they are on a node
+func atomicVMWithDB(t *testing.T, baseDB database.Database) (*VM, *atomic.Memory, *Tx) {
+	genesisBytes := BuildGenesisTest(t)
+
+	sm := &atomic.Memory{}
+	sm.Initialize(logging.NoLog{}, prefixdb.New([]byte("shared memory"), baseDB))
+
+	atomicCtx := snow.DefaultContextTest()
+	atomicCtx.NetworkID = networkID
+	atomicCtx.ChainID = chainID
+	atomicCtx.SharedMemory = sm.NewBlockchainMemory(chainID)
+
+	atomicCtx.Lock.Lock()
+	defer atomicCtx.Lock.Unlock()
+
+	vm := &VM{}
+	err := vm.Initialize(
+		atomicCtx,
+		prefixdb.New(chainID.Bytes(), baseDB),
+		genesisBytes,
+		make(chan common.Message, 1),
+		[]*common.Fx{&common.Fx{
+			ID: ids.Empty,
+			Fx: &secp256k1fx.Fx{},
+		}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.batchTimeout = 0
+
+	genesisTx := GetFirstTxFromGenesisTest(genesisBytes, t)
+	vm.ava = genesisTx.ID()
+	vm.platform = platformChainID
+	return vm, sm, genesisTx
+}
+
+func TestExportTxSyntacticVerify(t *testing.T) {
+	c := codec.NewDefault()
+	c.RegisterType(&secp256k1fx.TransferInput{})
+	c.RegisterType(&TestTransferable{})
+	c.RegisterType(&secp256k1fx.TransferOutput{})
+
+	addr := keys[0].PublicKey().Address()
+	newTx := func() *ExportTx {
+		return &ExportTx{
+			BaseTx: BaseTx{
+				NetID: networkID,
+				BCID:  chainID,
+				Ins: []*TransferableInput{&TransferableInput{
+					UTXOID: UTXOID{TxID: asset},
+					Asset:  Asset{ID: asset},
+					In: &secp256k1fx.TransferInput{
+						Amt:   1000,
+						Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+					},
+				}},
+			},
+			ExportOuts: []*TransferableOutput{&TransferableOutput{
+				Asset: Asset{ID: asset},
+				Out: &secp256k1fx.TransferOutput{
+					Amt: 1000,
+					OutputOwners: secp256k1fx.OutputOwners{
+						Threshold: 1,
+						Addrs:     []ids.ShortID{addr},
+					},
+				},
+			}},
+		}
+	}
+
+	tx := newTx()
+	tx.Initialize([]byte{})
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	tx = newTx()
+	tx.ExportOuts = nil
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
+		t.Fatalf("Should have errored due to no export outputs")
+	}
+
+	tx = newTx()
+	tx.ExportOuts[0].Out = &TestTransferable{Val: 1000}
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
+		t.Fatalf("Should have errored due to exporting an output of another fx")
+	}
+
+	tx = newTx()
+	tx.ExportOuts[0].Out.(*secp256k1fx.TransferOutput).Amt = 1001
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
+		t.Fatalf("Should have errored due to exporting more than is consumed")
+	}
+}
+
+func TestIssueExportTx(t *testing.T) {
+	vm, sm, genesisTx := AtomicVM(t)
+
+	key := keys[0]
+	addr := key.PublicKey().Address()
+
+	tx := &Tx{UnsignedTx: &ExportTx{
+		BaseTx: BaseTx{
+			NetID: networkID,
+			BCID:  chainID,
+			Ins: []*TransferableInput{&TransferableInput{
+				UTXOID: UTXOID{
+					TxID:        genesisTx.ID(),
+					OutputIndex: 1,
+				},
+				Asset: Asset{ID: genesisTx.ID()},
+				In: &secp256k1fx.TransferInput{
+					Amt:   50000,
+					Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+				},
+			}},
+		},
+		ExportOuts: []*TransferableOutput{&TransferableOutput{
+			Asset: Asset{ID: genesisTx.ID()},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: 50000,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{addr},
+				},
+			},
+		}},
+	}}
+
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	service := Service{vm: vm}
+	txID, err := service.signAndIssue(tx, [][]signingKey{{key}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txs := vm.PendingTxs()
+	if len(txs) != 1 {
+		t.Fatalf("Should have returned %d tx(s)", 1)
+	}
+	if !txs[0].ID().Equals(txID) {
+		t.Fatalf("Wrong tx pending")
+	}
+	txs[0].Accept()
+
+	smDB := sm.NewBlockchainMemory(platformChainID).GetDatabase(chainID)
+	state := shared.NewState(smDB, platformChainID)
+	utxoIDs, err := state.Funds(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxoIDs) != 1 {
+		t.Fatalf("Should have exported %d UTXO(s) but exported %d", 1, len(utxoIDs))
+	}
+	utxo, err := state.UTXO(utxoIDs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !utxo.TxID.Equals(txID) {
+		t.Fatalf("Exported UTXO should have been produced by the export tx")
+	}
+	if utxo.Out.Amt != 50000 {
+		t.Fatalf("Wrong amount exported. Expected: %d ; Returned: %d", 50000, utxo.Out.Amt)
+	}
+
+	spent := UTXOID{TxID: genesisTx.ID(), OutputIndex: 1}
+	if _, err := vm.state.UTXO(spent.InputID()); err == nil {
+		t.Fatalf("The exported funds should have been spent")
+	}
+}
+
+func TestExportTxWriteFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		// The number of batches written before the node crashes
+		writes int
+		// Whether the accepted export is expected to have been written
+		written bool
+	}{
+		{name: "no write", writes: 0, written: false},
+		{name: "one write", writes: 1, written: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			baseDB := &failingDB{Database: memdb.New()}
+			vm, sm, genesisTx := atomicVMWithDB(t, baseDB)
+
+			key := keys[0]
+			addr := key.PublicKey().Address()
+
+			tx := &Tx{UnsignedTx: &ExportTx{
+				BaseTx: BaseTx{
+					NetID: networkID,
+					BCID:  chainID,
+					Ins: []*TransferableInput{&TransferableInput{
+						UTXOID: UTXOID{
+							TxID:        genesisTx.ID(),
+							OutputIndex: 1,
+						},
+						Asset: Asset{ID: genesisTx.ID()},
+						In: &secp256k1fx.TransferInput{
+							Amt:   50000,
+							Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+						},
+					}},
+				},
+				ExportOuts: []*TransferableOutput{&TransferableOutput{
+					Asset: Asset{ID: genesisTx.ID()},
+					Out: &secp256k1fx.TransferOutput{
+						Amt: 50000,
+						OutputOwners: secp256k1fx.OutputOwners{
+							Threshold: 1,
+							Addrs:     []ids.ShortID{addr},
+						},
+					},
+				}},
+			}}
+
+			vm.ctx.Lock.Lock()
+			defer vm.ctx.Lock.Unlock()
+
+			service := Service{vm: vm}
+			if _, err := service.signAndIssue(tx, [][]signingKey{{key}}); err != nil {
+				t.Fatal(err)
+			}
+
+			txs := vm.PendingTxs()
+			if len(txs) != 1 {
+				t.Fatalf("Should have returned %d tx(s)", 1)
+			}
+
+			before := dbContents(t, baseDB)
+			baseDB.limitWrites = true
+			baseDB.writesLeft = test.writes
+			txs[0].Accept()
+
+			// Read what was written, as the node would after restarting
+			baseDB.limitWrites = false
+			vm, sm, _ = atomicVMWithDB(t, baseDB)
+
+			if written := len(dbContents(t, baseDB)) != len(before); written != test.written {
+				t.Fatalf("Wrong written state. Expected: %v ; Returned: %v", test.written, written)
+			}
+
+			spent := UTXOID{TxID: genesisTx.ID(), OutputIndex: 1}
+			if _, err := vm.state.UTXO(spent.InputID()); (err == nil) == test.written {
+				t.Fatalf("The exported funds should have been spent if, and only if, the export was written")
+			}
+
+			smDB := sm.NewBlockchainMemory(platformChainID).GetDatabase(chainID)
+			defer sm.NewBlockchainMemory(platformChainID).ReleaseDatabase(chainID)
+			state := shared.NewState(smDB, platformChainID)
+			utxoIDs, err := state.Funds(addr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if exported := len(utxoIDs) == 1; exported != test.written {
+				t.Fatalf("The funds should have been exported if, and only if, the export was written")
+			}
+		})
+	}
+}
+
+func TestExportTxNotAVA(t *testing.T) {
+	vm, _, genesisTx := AtomicVM(t)
+	vm.ava = asset
+
+	key := keys[0]
+	tx := &Tx{UnsignedTx: &ExportTx{
+		BaseTx: BaseTx{
+			NetID: networkID,
+			BCID:  chainID,
+			Ins: []*TransferableInput{&TransferableInput{
+				UTXOID: UTXOID{
+					TxID:        genesisTx.ID(),
+					OutputIndex: 1,
+				},
+				Asset: Asset{ID: genesisTx.ID()},
+				In: &secp256k1fx.TransferInput{
+					Amt:   50000,
+					Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+				},
+			}},
+		},
+		ExportOuts: []*TransferableOutput{&TransferableOutput{
+			Asset: Asset{ID: genesisTx.ID()},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: 50000,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{key.PublicKey().Address()},
+				},
+			},
+		}},
+	}}
+
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	service := Service{vm: vm}
+	if _, err := service.signAndIssue(tx, [][]signingKey{{key}}); err == nil {
+		t.Fatalf("Should have errored due to exporting an asset other than AVA")
+	}
+}
@@ -13,7 +13,28 @@ var (
 )
 
 // Factory ...
-type Factory struct{}
+type Factory struct {
+	// ID of the AVA asset, the only asset that can be transferred to and from
+	// the platform chain
+	AVA ids.ID
+
+	// ID of the platform chain
+	Platform ids.ID
+
+	// Amount of AVA burnt by every transaction
+	TxFee uint64
+
+	// If true, the txs that touched each address and asset are indexed, so
+	// that the history of addresses and assets can be queried
+	IndexTxs bool
+}
 
 // New ...
-func (f *Factory) New() interface{} { return &VM{} }
+func (f *Factory) New() interface{} {
+	return &VM{
+		ava:      f.AVA,
+		platform: f.Platform,
+		txFee:    f.TxFee,
+		indexTxs: f.IndexTxs,
+	}
+}
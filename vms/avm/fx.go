@@ -47,3 +47,9 @@ type FxTransferable interface {
 type FxAddressable interface {
 	Addresses() [][]byte
 }
+
+// FxLockable is an output whose value can be locked until some time
+type FxLockable interface {
+	// Locked returns how much of the output's value can't be spent at [time]
+	Locked(time uint64) uint64
+}
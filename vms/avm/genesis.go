@@ -4,10 +4,19 @@
 package avm
 
 import (
+	"errors"
 	"sort"
 	"strings"
 
+	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/utils"
+	"github.com/ava-labs/gecko/utils/math"
+	"github.com/ava-labs/gecko/vms/components/codec"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+var (
+	errUnknownGenesisAsset = errors.New("genesis doesn't create the asset")
 )
 
 // Genesis ...
@@ -35,3 +44,89 @@ type GenesisAsset struct {
 	Alias         string `serialize:"true"`
 	CreateAssetTx `serialize:"true"`
 }
+
+// GenesisAssetID returns the ID of the asset with alias [alias] that is created
+// by the AVM genesis [genesisBytes]. Assumes the chain's only feature extension
+// is the secp256k1fx.
+func GenesisAssetID(genesisBytes []byte, alias string) (ids.ID, error) {
+	c := genesisCodec()
+	genesis := Genesis{}
+	if err := c.Unmarshal(genesisBytes, &genesis); err != nil {
+		return ids.ID{}, err
+	}
+
+	for _, genesisTx := range genesis.Txs {
+		if genesisTx.Alias != alias {
+			continue
+		}
+
+		tx := Tx{
+			UnsignedTx: &genesisTx.CreateAssetTx,
+		}
+		txBytes, err := c.Marshal(&tx)
+		if err != nil {
+			return ids.ID{}, err
+		}
+		tx.Initialize(txBytes)
+		return tx.ID(), nil
+	}
+	return ids.ID{}, errUnknownGenesisAsset
+}
+
+// GenesisSupply returns the amount of the asset with ID [assetID] that is
+// allocated by the AVM genesis [genesisBytes], or 0 if the genesis doesn't
+// create the asset. Assumes the chain's only feature extension is the
+// secp256k1fx.
+func GenesisSupply(genesisBytes []byte, assetID ids.ID) (uint64, error) {
+	c := genesisCodec()
+	genesis := Genesis{}
+	if err := c.Unmarshal(genesisBytes, &genesis); err != nil {
+		return 0, err
+	}
+
+	for _, genesisTx := range genesis.Txs {
+		tx := Tx{
+			UnsignedTx: &genesisTx.CreateAssetTx,
+		}
+		txBytes, err := c.Marshal(&tx)
+		if err != nil {
+			return 0, err
+		}
+		tx.Initialize(txBytes)
+		if !tx.ID().Equals(assetID) {
+			continue
+		}
+
+		supply := uint64(0)
+		for _, state := range genesisTx.States {
+			for _, out := range state.Outs {
+				newSupply, err := math.Add64(supply, amountOf(out))
+				if err != nil {
+					return 0, err
+				}
+				supply = newSupply
+			}
+		}
+		return supply, nil
+	}
+	return 0, nil
+}
+
+// genesisCodec returns a codec with the types of an AVM whose only feature
+// extension is the secp256k1fx, registered in the same order as the VM
+// registers them
+func genesisCodec() codec.Codec {
+	c := codec.NewDefault()
+	c.RegisterType(&BaseTx{})
+	c.RegisterType(&CreateAssetTx{})
+	c.RegisterType(&OperationTx{})
+	c.RegisterType(&secp256k1fx.MintOutput{})
+	c.RegisterType(&secp256k1fx.TransferOutput{})
+	c.RegisterType(&secp256k1fx.MintInput{})
+	c.RegisterType(&secp256k1fx.TransferInput{})
+	c.RegisterType(&secp256k1fx.Credential{})
+	c.RegisterType(&secp256k1fx.VestingOutput{})
+	c.RegisterType(&ImportTx{})
+	c.RegisterType(&ExportTx{})
+	return c
+}
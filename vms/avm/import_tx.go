@@ -0,0 +1,154 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/database/versiondb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/vms/components/codec"
+	"github.com/ava-labs/gecko/vms/components/shared"
+)
+
+var (
+	errNoImportInputs = errors.New("no import inputs")
+)
+
+// ImportTx is a transaction that imports an asset from the platform chain.
+type ImportTx struct {
+	BaseTx `serialize:"true"`
+
+	ImportIns []*TransferableInput `serialize:"true"` // The inputs this transaction is consuming from the platform chain
+}
+
+// InputUTXOs track which UTXOs this transaction is consuming. The imported
+// UTXOs are symbolic, as they aren't stored in this chain's state.
+func (t *ImportTx) InputUTXOs() []*UTXOID {
+	utxos := t.BaseTx.InputUTXOs()
+	for _, in := range t.ImportIns {
+		in.Symbol = true
+		utxos = append(utxos, &in.UTXOID)
+	}
+	return utxos
+}
+
+// AssetIDs returns the IDs of the assets this transaction depends on
+func (t *ImportTx) AssetIDs() ids.Set {
+	assets := t.BaseTx.AssetIDs()
+	for _, in := range t.ImportIns {
+		assets.Add(in.AssetID())
+	}
+	return assets
+}
+
+// SyntacticVerify that this transaction is well-formed.
+func (t *ImportTx) SyntacticVerify(ctx *snow.Context, c codec.Codec, txFeeAssetID ids.ID, txFee uint64, _ int) error {
+	switch {
+	case t == nil:
+		return errNilTx
+	case len(t.ImportIns) == 0:
+		return errNoImportInputs
+	}
+
+	if err := t.verifyStructure(ctx, c); err != nil {
+		return err
+	}
+
+	inputs := ids.Set{}
+	for _, in := range t.Ins {
+		inputs.Add(in.InputID())
+	}
+	for _, in := range t.ImportIns {
+		if err := in.Verify(); err != nil {
+			return err
+		}
+		inputID := in.InputID()
+		if inputs.Contains(inputID) {
+			return errDoubleSpend
+		}
+		inputs.Add(inputID)
+	}
+	if !isSortedAndUniqueTransferableInputs(t.ImportIns) {
+		return errInputsNotSortedUnique
+	}
+
+	ins := make([]*TransferableInput, 0, len(t.Ins)+len(t.ImportIns))
+	ins = append(ins, t.Ins...)
+	ins = append(ins, t.ImportIns...)
+	if err := verifyBalance(txFeeAssetID, txFee, ins, t.Outs); err != nil {
+		return err
+	}
+	return t.metadata.Verify()
+}
+
+// SemanticVerify that this transaction is valid to be spent.
+func (t *ImportTx) SemanticVerify(vm *VM, uTx *UniqueTx, creds []*Credential) error {
+	for _, in := range t.ImportIns {
+		if assetID := in.AssetID(); !assetID.Equals(vm.ava) {
+			return errNotAVA
+		}
+	}
+	if err := t.BaseTx.SemanticVerify(vm, uTx, creds); err != nil {
+		return err
+	}
+
+	smDB := vm.ctx.SharedMemory.GetDatabase(vm.platform)
+	defer vm.ctx.SharedMemory.ReleaseDatabase(vm.platform)
+
+	state := shared.NewState(smDB, vm.ctx.ChainID)
+
+	offset := len(t.Ins)
+	for i, in := range t.ImportIns {
+		cred := creds[i+offset]
+
+		fxIndex, err := vm.getFx(cred.Cred)
+		if err != nil {
+			return err
+		}
+		fx := vm.fxs[fxIndex].Fx
+
+		utxo, err := state.UTXO(in.InputID())
+		if err != nil {
+			return errMissingUTXO
+		}
+
+		inAssetID := in.AssetID()
+		if !utxo.AssetID.Equals(inAssetID) {
+			return errAssetIDMismatch
+		}
+
+		if !vm.verifyFxUsage(fxIndex, inAssetID) {
+			return errIncompatibleFx
+		}
+
+		if err := fx.VerifyTransfer(uTx, &utxo.Out, in.In, cred.Cred); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExecuteWithSideEffects removes the imported UTXOs from the memory shared with
+// the platform chain, and writes the state changes of this transaction to the
+// chain's database.
+//
+// Both are written in a single batch, so the imported funds are never
+// spendable on both chains, or on neither of them.
+func (t *ImportTx) ExecuteWithSideEffects(vm *VM) error {
+	smDB := vm.ctx.SharedMemory.GetDatabase(vm.platform)
+	defer vm.ctx.SharedMemory.ReleaseDatabase(vm.platform)
+
+	vsmDB := versiondb.New(smDB)
+	state := shared.NewState(vsmDB, vm.ctx.ChainID)
+
+	for _, in := range t.ImportIns {
+		if err := state.SpendUTXO(in.InputID()); err != nil {
+			return err
+		}
+	}
+
+	return vm.commitWithSharedMemory(vsmDB)
+}
@@ -0,0 +1,142 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/vms/components/shared"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+func TestIssueImportTx(t *testing.T) {
+	vm, sm, genesisTx := AtomicVM(t)
+
+	key := keys[0]
+	addr := key.PublicKey().Address()
+
+	// Export funds from the platform chain
+	exported := &shared.UTXO{
+		TxID:    ids.Empty.Prefix(1),
+		AssetID: genesisTx.ID(),
+		Out: secp256k1fx.TransferOutput{
+			Amt: 1000,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{addr},
+			},
+		},
+	}
+	platformMemory := sm.NewBlockchainMemory(platformChainID)
+	smDB := platformMemory.GetDatabase(chainID)
+	if err := shared.NewState(smDB, chainID).FundUTXO(exported); err != nil {
+		t.Fatal(err)
+	}
+	platformMemory.ReleaseDatabase(chainID)
+
+	newTx := func() *Tx {
+		return &Tx{UnsignedTx: &ImportTx{
+			BaseTx: BaseTx{
+				NetID: networkID,
+				BCID:  chainID,
+				Outs: []*TransferableOutput{&TransferableOutput{
+					Asset: Asset{ID: genesisTx.ID()},
+					Out: &secp256k1fx.TransferOutput{
+						Amt: 1000,
+						OutputOwners: secp256k1fx.OutputOwners{
+							Threshold: 1,
+							Addrs:     []ids.ShortID{addr},
+						},
+					},
+				}},
+			},
+			ImportIns: []*TransferableInput{&TransferableInput{
+				UTXOID: UTXOID{TxID: exported.TxID},
+				Asset:  Asset{ID: genesisTx.ID()},
+				In: &secp256k1fx.TransferInput{
+					Amt:   1000,
+					Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+				},
+			}},
+		}}
+	}
+
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	service := Service{vm: vm}
+
+	// Signed by a key that doesn't own the exported funds
+	if _, err := service.signAndIssue(newTx(), [][]signingKey{{keys[1]}}); err == nil {
+		t.Fatalf("Should have errored due to a wrong signature")
+	}
+
+	txID, err := service.signAndIssue(newTx(), [][]signingKey{{key}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mintedBefore, burnedBefore, err := vm.state.Supply(genesisTx.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txs := vm.PendingTxs()
+	if len(txs) != 1 {
+		t.Fatalf("Should have returned %d tx(s)", 1)
+	}
+	txs[0].Accept()
+
+	// The imported funds are minted on this chain
+	if minted, burned, err := vm.state.Supply(genesisTx.ID()); err != nil {
+		t.Fatal(err)
+	} else if minted != mintedBefore+1000 || burned != burnedBefore {
+		t.Fatalf("Wrong supply. Expected: %d minted, %d burned ; Returned: %d minted, %d burned",
+			mintedBefore+1000, burnedBefore, minted, burned)
+	}
+
+	imported := UTXOID{TxID: txID}
+	utxo, err := vm.state.UTXO(imported.InputID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out, ok := utxo.Out.(*secp256k1fx.TransferOutput); !ok || out.Amt != 1000 {
+		t.Fatalf("Wrong UTXO produced by the import tx")
+	}
+
+	smDB = platformMemory.GetDatabase(chainID)
+	defer platformMemory.ReleaseDatabase(chainID)
+	if _, err := shared.NewState(smDB, chainID).UTXO(exported.InputID()); err == nil {
+		t.Fatalf("The imported UTXO should have been removed from shared memory")
+	}
+}
+
+func TestImportTxMissingUTXO(t *testing.T) {
+	vm, _, genesisTx := AtomicVM(t)
+
+	key := keys[0]
+	tx := &Tx{UnsignedTx: &ImportTx{
+		BaseTx: BaseTx{
+			NetID: networkID,
+			BCID:  chainID,
+		},
+		ImportIns: []*TransferableInput{&TransferableInput{
+			UTXOID: UTXOID{TxID: ids.Empty.Prefix(1)},
+			Asset:  Asset{ID: genesisTx.ID()},
+			In: &secp256k1fx.TransferInput{
+				Amt:   1000,
+				Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+			},
+		}},
+	}}
+
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	service := Service{vm: vm}
+	if _, err := service.signAndIssue(tx, [][]signingKey{{key}}); err == nil {
+		t.Fatalf("Should have errored due to importing a UTXO that wasn't exported")
+	}
+}
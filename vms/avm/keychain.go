@@ -0,0 +1,96 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/vms/components/verify"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+// keychain is the set of addresses a user controls. The keys that control them
+// are never handled directly, they're only used through the user's signer.
+type keychain struct {
+	signer snow.Signer
+
+	// addrs[0] receives the change of the user's txs
+	addrs   []ids.ShortID
+	addrSet ids.ShortSet
+}
+
+// newKeychain returns the keychain of the addresses [signer] can sign for
+func newKeychain(signer snow.Signer) (*keychain, error) {
+	addrs, err := signer.Addresses()
+	if err != nil {
+		return nil, err
+	}
+	kc := &keychain{signer: signer}
+	for _, addr := range addrs {
+		if !kc.addrSet.Contains(addr) {
+			kc.addrs = append(kc.addrs, addr)
+			kc.addrSet.Add(addr)
+		}
+	}
+	return kc, nil
+}
+
+// Addresses returns the addresses of the keychain. It shouldn't be modified.
+func (kc *keychain) Addresses() ids.ShortSet { return kc.addrSet }
+
+// ChangeAddress returns the address that receives the change of the user's
+// txs. Assumes the keychain isn't empty.
+func (kc *keychain) ChangeAddress() ids.ShortID { return kc.addrs[0] }
+
+// Get returns the key that controls [addr], if the keychain has it
+func (kc *keychain) Get(addr ids.ShortID) (signingKey, bool) {
+	if !kc.addrSet.Contains(addr) {
+		return nil, false
+	}
+	return &signerKey{signer: kc.signer, addr: addr}, true
+}
+
+// Match attempts to match the addresses of [owners] up to their threshold
+func (kc *keychain) Match(owners *secp256k1fx.OutputOwners) ([]uint32, []signingKey, bool) {
+	sigs := []uint32{}
+	keys := []signingKey{}
+	for i := uint32(0); i < uint32(len(owners.Addrs)) && uint32(len(keys)) < owners.Threshold; i++ {
+		if key, exists := kc.Get(owners.Addrs[i]); exists {
+			sigs = append(sigs, i)
+			keys = append(keys, key)
+		}
+	}
+	return sigs, keys, uint32(len(keys)) == owners.Threshold
+}
+
+// Spend attempts to create an input that consumes [out] at [time], and
+// returns the keys that must sign it
+func (kc *keychain) Spend(out verify.Verifiable, time uint64) (verify.Verifiable, []signingKey, error) {
+	keys := []signingKey(nil)
+	in, err := secp256k1fx.Spend(out, time, func(owners *secp256k1fx.OutputOwners) ([]uint32, bool) {
+		sigIndices, matched, able := kc.Match(owners)
+		keys = matched
+		return sigIndices, able
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return in, keys, nil
+}
+
+// signingKey signs with the key that controls an address. Private keys are
+// signing keys, but the keys of a keychain are only reachable through its
+// signer.
+type signingKey interface {
+	SignHash(hash []byte) ([]byte, error)
+}
+
+// signerKey is the key that controls an address, as seen through a signer
+type signerKey struct {
+	signer snow.Signer
+	addr   ids.ShortID
+}
+
+// SignHash returns the signature of [hash] by the key
+func (k *signerKey) SignHash(hash []byte) ([]byte, error) { return k.signer.SignHash(k.addr, hash) }
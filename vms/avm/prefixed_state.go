@@ -4,7 +4,10 @@
 package avm
 
 import (
+	"math"
+
 	"github.com/ava-labs/gecko/cache"
+	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/utils/hashing"
@@ -16,10 +19,17 @@ const (
 	txStatusID
 	fundsID
 	dbInitializedID
+	addressTxsID
+	assetTxsID
+	mintedID
+	burnedID
+	supplyIndexedID
+	fundsIndexID
 )
 
 var (
 	dbInitialized = ids.Empty.Prefix(dbInitializedID)
+	supplyIndexed = ids.Empty.Prefix(supplyIndexedID)
 )
 
 // prefixedState wraps a state object. By prefixing the state, there will be no
@@ -28,6 +38,7 @@ type prefixedState struct {
 	state *state
 
 	tx, utxo, txStatus, funds cache.Cacher
+	addressTxs, assetTxs      cache.Cacher
 	uniqueTx                  cache.Deduplicator
 }
 
@@ -73,15 +84,145 @@ func (s *prefixedState) SetDBInitialized(status choices.Status) error {
 	return s.state.SetStatus(dbInitialized, status)
 }
 
-// Funds returns the mapping from the 32 byte representation of an address to a
-// list of utxo IDs that reference the address.
-func (s *prefixedState) Funds(id ids.ID) ([]ids.ID, error) {
-	return s.state.IDs(s.uniqueID(id, fundsID, s.funds))
+// SupplyIndexed returns the status of the supply indices. If the supply of the
+// genesis assets was never indexed, the status will be unknown.
+func (s *prefixedState) SupplyIndexed() (choices.Status, error) { return s.state.Status(supplyIndexed) }
+
+// SetSupplyIndexed saves the provided status of the supply indices.
+func (s *prefixedState) SetSupplyIndexed(status choices.Status) error {
+	return s.state.SetStatus(supplyIndexed, status)
+}
+
+// Funds returns the IDs of the utxos that reference the address whose 32 byte
+// representation is [addr]. Returns database.ErrNotFound if there are none.
+func (s *prefixedState) Funds(addr ids.ID) ([]ids.ID, error) {
+	utxoIDs, err := s.FundsPage(addr, ids.ID{}, math.MaxInt32)
+	if err == nil && len(utxoIDs) == 0 {
+		err = database.ErrNotFound
+	}
+	return utxoIDs, err
+}
+
+// FundsPage returns up to [limit] IDs of the utxos that reference the address
+// whose 32 byte representation is [addr]. The IDs are sorted, and start after
+// [start]. If [start] is empty, they start with the first ID.
+func (s *prefixedState) FundsPage(addr, start ids.ID, limit int) ([]ids.ID, error) {
+	if err := s.migrateFunds(addr); err != nil {
+		return nil, err
+	}
+	return s.state.IDsPage(s.uniqueID(addr, fundsIndexID, s.funds), start, limit)
+}
+
+// migrateFunds moves the IDs of the utxos that reference [addr] out of the
+// list they were kept in before each of them had its own key
+func (s *prefixedState) migrateFunds(addr ids.ID) error {
+	legacyID := addr.Prefix(fundsID)
+	utxoIDs, err := s.state.IDs(legacyID)
+	if err == database.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	indexID := s.uniqueID(addr, fundsIndexID, s.funds)
+	for _, utxoID := range utxoIDs {
+		if err := s.state.AddID(indexID, utxoID); err != nil {
+			return err
+		}
+	}
+	return s.state.SetIDs(legacyID, nil)
+}
+
+// AddressTxs returns the ID of the index of the txs that touched the address
+// whose 32 byte representation is [addr]
+func (s *prefixedState) AddressTxs(addr ids.ID) ids.ID {
+	return s.uniqueID(addr, addressTxsID, s.addressTxs)
+}
+
+// AssetTxs returns the ID of the index of the txs that touched [assetID]
+func (s *prefixedState) AssetTxs(assetID ids.ID) ids.ID {
+	return s.uniqueID(assetID, assetTxsID, s.assetTxs)
+}
+
+// Supply returns how much of [assetID] the accepted txs have minted, and how
+// much of it they have burnt
+func (s *prefixedState) Supply(assetID ids.ID) (uint64, uint64, error) {
+	minted, err := s.supply(assetID.Prefix(mintedID))
+	if err != nil {
+		return 0, 0, err
+	}
+	burned, err := s.supply(assetID.Prefix(burnedID))
+	return minted, burned, err
+}
+
+// AddSupply adds [minted] and [burned] to the amounts of [assetID] that have
+// been minted and burnt. The amounts saturate at the maximum uint64 rather than
+// overflow.
+func (s *prefixedState) AddSupply(assetID ids.ID, minted, burned uint64) error {
+	if err := s.addSupply(assetID.Prefix(mintedID), minted); err != nil {
+		return err
+	}
+	return s.addSupply(assetID.Prefix(burnedID), burned)
+}
+
+func (s *prefixedState) supply(id ids.ID) (uint64, error) {
+	amount, err := s.state.Uint64(id)
+	if err == database.ErrNotFound {
+		return 0, nil
+	}
+	return amount, err
+}
+
+func (s *prefixedState) addSupply(id ids.ID, amount uint64) error {
+	if amount == 0 {
+		return nil
+	}
+	total, err := s.supply(id)
+	if err != nil {
+		return err
+	}
+	return s.state.SetUint64(id, addSaturating(total, amount))
+}
+
+// NumIndexedTxs returns the number of txs in the index [index]
+func (s *prefixedState) NumIndexedTxs(index ids.ID) (uint64, error) {
+	numTxs, err := s.state.Uint64(index)
+	if err == database.ErrNotFound {
+		return 0, nil
+	}
+	return numTxs, err
+}
+
+// IndexTx appends [txID] to the index [index]
+func (s *prefixedState) IndexTx(index, txID ids.ID) error {
+	numTxs, err := s.NumIndexedTxs(index)
+	if err != nil {
+		return err
+	}
+	if err := s.state.SetID(index.Prefix(numTxs), txID); err != nil {
+		return err
+	}
+	return s.state.SetUint64(index, numTxs+1)
 }
 
-// SetFunds saves the mapping from address to utxo IDs to storage.
-func (s *prefixedState) SetFunds(id ids.ID, idSlice []ids.ID) error {
-	return s.state.SetIDs(s.uniqueID(id, fundsID, s.funds), idSlice)
+// IndexedTxs returns up to [limit] txs of the index [index], in the order they
+// were indexed, starting with the tx at position [start]
+func (s *prefixedState) IndexedTxs(index ids.ID, start, limit uint64) ([]ids.ID, error) {
+	numTxs, err := s.NumIndexedTxs(index)
+	if err != nil {
+		return nil, err
+	}
+
+	txIDs := []ids.ID{}
+	for i := start; i < numTxs && uint64(len(txIDs)) < limit; i++ {
+		txID, err := s.state.ID(index.Prefix(i))
+		if err != nil {
+			return nil, err
+		}
+		txIDs = append(txIDs, txID)
+	}
+	return txIDs, nil
 }
 
 func (s *prefixedState) uniqueID(id ids.ID, prefix uint64, cacher cache.Cacher) ids.ID {
@@ -114,11 +255,10 @@ func (s *prefixedState) SpendUTXO(utxoID ids.ID) error {
 func (s *prefixedState) removeUTXO(addrs [][]byte, utxoID ids.ID) error {
 	for _, addr := range addrs {
 		addrID := ids.NewID(hashing.ComputeHash256Array(addr))
-		utxos := ids.Set{}
-		funds, _ := s.Funds(addrID)
-		utxos.Add(funds...)
-		utxos.Remove(utxoID)
-		if err := s.SetFunds(addrID, utxos.List()); err != nil {
+		if err := s.migrateFunds(addrID); err != nil {
+			return err
+		}
+		if err := s.state.RemoveID(s.uniqueID(addrID, fundsIndexID, s.funds), utxoID); err != nil {
 			return err
 		}
 	}
@@ -143,11 +283,10 @@ func (s *prefixedState) FundUTXO(utxo *UTXO) error {
 func (s *prefixedState) addUTXO(addrs [][]byte, utxoID ids.ID) error {
 	for _, addr := range addrs {
 		addrID := ids.NewID(hashing.ComputeHash256Array(addr))
-		utxos := ids.Set{}
-		funds, _ := s.Funds(addrID)
-		utxos.Add(funds...)
-		utxos.Add(utxoID)
-		if err := s.SetFunds(addrID, utxos.List()); err != nil {
+		if err := s.migrateFunds(addrID); err != nil {
+			return err
+		}
+		if err := s.state.AddID(s.uniqueID(addrID, fundsIndexID, s.funds), utxoID); err != nil {
 			return err
 		}
 	}
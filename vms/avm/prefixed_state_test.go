@@ -6,6 +6,7 @@ package avm
 import (
 	"testing"
 
+	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/utils/crypto"
@@ -177,3 +178,118 @@ func TestPrefixedFundingAddresses(t *testing.T) {
 		t.Fatalf("Should have returned no utxoIDs")
 	}
 }
+
+func TestPrefixedTxIndex(t *testing.T) {
+	vm := GenesisVM(t)
+	state := vm.state
+
+	index := state.AddressTxs(ids.NewID([32]byte{1}))
+	otherIndex := state.AssetTxs(ids.NewID([32]byte{1}))
+
+	txIDs := []ids.ID{ids.NewID([32]byte{2}), ids.NewID([32]byte{3}), ids.NewID([32]byte{4})}
+	for _, txID := range txIDs {
+		if err := state.IndexTx(index, txID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if numTxs, err := state.NumIndexedTxs(index); err != nil {
+		t.Fatal(err)
+	} else if numTxs != 3 {
+		t.Fatalf("Wrong number of txs. Expected: 3 ; Returned: %d", numTxs)
+	}
+	if numTxs, err := state.NumIndexedTxs(otherIndex); err != nil {
+		t.Fatal(err)
+	} else if numTxs != 0 {
+		t.Fatalf("The indices of addresses and assets should be distinct")
+	}
+
+	page, err := state.IndexedTxs(index, 1, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 2 || !page[0].Equals(txIDs[1]) || !page[1].Equals(txIDs[2]) {
+		t.Fatalf("Wrong txs. Expected: %s ; Returned: %s", txIDs[1:], page)
+	}
+
+	page, err = state.IndexedTxs(index, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 1 || !page[0].Equals(txIDs[0]) {
+		t.Fatalf("Wrong txs. Expected: %s ; Returned: %s", txIDs[:1], page)
+	}
+}
+
+func TestPrefixedFundsPage(t *testing.T) {
+	vm := GenesisVM(t)
+	state := vm.state
+
+	addr := ids.NewID([32]byte{1})
+	utxoIDs := []ids.ID{ids.NewID([32]byte{4}), ids.NewID([32]byte{2}), ids.NewID([32]byte{3})}
+	// Indices written as a single unsorted list must still be paged through in
+	// order
+	if err := state.state.SetIDs(addr.Prefix(fundsID), utxoIDs); err != nil {
+		t.Fatal(err)
+	}
+
+	page, err := state.FundsPage(addr, ids.ID{}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 2 || !page[0].Equals(utxoIDs[1]) || !page[1].Equals(utxoIDs[2]) {
+		t.Fatalf("Wrong page. Expected: [%s %s] ; Returned: %s", utxoIDs[1], utxoIDs[2], page)
+	}
+
+	page, err = state.FundsPage(addr, page[1], 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 1 || !page[0].Equals(utxoIDs[0]) {
+		t.Fatalf("Wrong page. Expected: [%s] ; Returned: %s", utxoIDs[0], page)
+	}
+
+	if _, err := state.state.IDs(addr.Prefix(fundsID)); err != database.ErrNotFound {
+		t.Fatalf("The list of utxos should have been migrated")
+	}
+
+	if page, err := state.FundsPage(ids.NewID([32]byte{5}), ids.ID{}, 2); err != nil {
+		t.Fatal(err)
+	} else if len(page) != 0 {
+		t.Fatalf("An address without utxos should have an empty page")
+	}
+}
+
+func TestPrefixedSupply(t *testing.T) {
+	vm := GenesisVM(t)
+	state := vm.state
+
+	assetID := ids.NewID([32]byte{1})
+	if minted, burned, err := state.Supply(assetID); err != nil {
+		t.Fatal(err)
+	} else if minted != 0 || burned != 0 {
+		t.Fatalf("Nothing should have been minted or burnt yet")
+	}
+
+	if err := state.AddSupply(assetID, 10, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := state.AddSupply(assetID, 5, 3); err != nil {
+		t.Fatal(err)
+	}
+	if minted, burned, err := state.Supply(assetID); err != nil {
+		t.Fatal(err)
+	} else if minted != 15 || burned != 3 {
+		t.Fatalf("Wrong supply. Expected: 15 minted, 3 burned ; Returned: %d minted, %d burned", minted, burned)
+	}
+
+	// The minted amount saturates rather than overflows
+	if err := state.AddSupply(assetID, ^uint64(0), 0); err != nil {
+		t.Fatal(err)
+	}
+	if minted, burned, err := state.Supply(assetID); err != nil {
+		t.Fatal(err)
+	} else if minted != ^uint64(0) || burned != 3 {
+		t.Fatalf("Wrong supply. Expected: %d minted, 3 burned ; Returned: %d minted, %d burned", ^uint64(0), minted, burned)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"sort"
 
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/utils"
 	"github.com/ava-labs/gecko/utils/crypto"
@@ -18,7 +19,9 @@ import (
 	"github.com/ava-labs/gecko/utils/hashing"
 	"github.com/ava-labs/gecko/utils/json"
 	"github.com/ava-labs/gecko/utils/math"
+	"github.com/ava-labs/gecko/vms/components/shared"
 	"github.com/ava-labs/gecko/vms/components/verify"
+	"github.com/ava-labs/gecko/vms/nftfx"
 	"github.com/ava-labs/gecko/vms/secp256k1fx"
 )
 
@@ -31,12 +34,32 @@ var (
 	errSpendOverflow             = errors.New("spent amount overflows uint64")
 	errInvalidMintAmount         = errors.New("amount minted must be positive")
 	errAddressesCantMintAsset    = errors.New("provided addresses don't have the authority to mint the provided asset")
-	errCanOnlySignSingleInputTxs = errors.New("can only sign transactions with one input")
+	errCanOnlySignSingleInputTxs = errors.New("can only sign transactions with one input besides the inputs paying the tx fee")
+	errUnsignedFeeInputs         = errors.New("the inputs paying the tx fee must be signed")
 	errUnknownUTXO               = errors.New("unknown utxo")
 	errInvalidUTXO               = errors.New("invalid utxo")
 	errUnknownOutputType         = errors.New("unknown output type")
 	errUnneededAddress           = errors.New("address not required to sign")
 	errUnknownCredentialType     = errors.New("unknown credential type")
+	errUnknownAVA                = errors.New("this chain doesn't know the ID of the AVA asset")
+	errNoImportableFunds         = errors.New("no funds to import")
+	errTxIndexDisabled           = errors.New("this node doesn't index transactions")
+	errNoSpenders                = errors.New("no from addresses provided")
+	errNoRequiredKeys            = errors.New("user has no keys this transaction requires signatures from")
+	errMissingSignatures         = errors.New("transaction is missing signatures")
+	errLockedAndVesting          = errors.New("an output can't have both a locktime and a vesting schedule")
+	errInvalidVestingPeriod      = errors.New("vesting must end after it starts")
+	errCantMintNFT               = errors.New("user can't mint NFTs of the provided asset and group")
+	errRemoteSigner              = errors.New("the keys of users are held by a remote signer")
+	errNoNFT                     = errors.New("user doesn't own an NFT of the provided asset and group")
+)
+
+const (
+	// maxPageSize is the most txs that are returned in one page
+	maxPageSize = 1024
+
+	// maxUTXOsToFetch is the most utxos that are returned in one page
+	maxUTXOsToFetch = 1024
 )
 
 // Service defines the base service for the asset vm
@@ -56,6 +79,13 @@ type IssueTxReply struct {
 func (service *Service) IssueTx(r *http.Request, args *IssueTxArgs, reply *IssueTxReply) error {
 	service.vm.ctx.Log.Verbo("IssueTx called with %s", args.Tx)
 
+	// Transactions created by CreateSendTx have empty signatures until every
+	// signer has signed them
+	tx := Tx{}
+	if err := service.vm.codec.Unmarshal(args.Tx.Bytes, &tx); err == nil && missingSignatures(&tx) {
+		return errMissingSignatures
+	}
+
 	txID, err := service.vm.IssueTx(args.Tx.Bytes)
 	if err != nil {
 		return err
@@ -92,30 +122,185 @@ func (service *Service) GetTxStatus(r *http.Request, args *GetTxStatusArgs, repl
 	return nil
 }
 
+// GetTxArgs are arguments for passing into GetTx requests
+type GetTxArgs struct {
+	TxID ids.ID `json:"txID"`
+}
+
+// GetTxReply defines the GetTx replies returned from the API
+type GetTxReply struct {
+	Tx     formatting.CB58 `json:"tx"`
+	JSON   *Tx             `json:"json"`
+	Status choices.Status  `json:"status"`
+}
+
+// GetTx returns the specified transaction, both serialized and as JSON
+func (service *Service) GetTx(r *http.Request, args *GetTxArgs, reply *GetTxReply) error {
+	service.vm.ctx.Log.Verbo("GetTx called with %s", args.TxID)
+
+	if args.TxID.IsZero() {
+		return errNilTxID
+	}
+
+	tx := UniqueTx{
+		vm:   service.vm,
+		txID: args.TxID,
+	}
+	if status := tx.Status(); !status.Fetched() {
+		return errUnknownTx
+	}
+
+	reply.Tx.Bytes = tx.t.tx.Bytes()
+	reply.JSON = tx.t.tx
+	reply.Status = tx.Status()
+	return nil
+}
+
+// GetIndexedTxsReply defines the replies returned from the API when
+// paginating through the txs that touched an address or an asset
+type GetIndexedTxsReply struct {
+	// The txs, in the order they were accepted
+	TxIDs []ids.ID `json:"txIDs"`
+
+	// The cursor to pass in to get the next page
+	Cursor json.Uint64 `json:"cursor"`
+}
+
+// GetAddressTxsArgs are arguments for passing into GetAddressTxs requests
+type GetAddressTxsArgs struct {
+	Address string `json:"address"`
+
+	// The position of the first tx to return. 0 is the first tx that touched
+	// the address.
+	Cursor json.Uint64 `json:"cursor"`
+
+	// The maximum number of txs to return. If 0, or more than maxPageSize,
+	// maxPageSize txs are returned.
+	PageSize json.Uint64 `json:"pageSize"`
+}
+
+// GetAddressTxs returns the accepted txs that consumed or produced a UTXO
+// owned by the specified address
+func (service *Service) GetAddressTxs(r *http.Request, args *GetAddressTxsArgs, reply *GetIndexedTxsReply) error {
+	service.vm.ctx.Log.Verbo("GetAddressTxs called with address: %s cursor: %d pageSize: %d", args.Address, args.Cursor, args.PageSize)
+
+	address, err := service.vm.Parse(args.Address)
+	if err != nil {
+		return err
+	}
+	addrID := ids.NewID(hashing.ComputeHash256Array(address))
+	return service.indexedTxs(service.vm.state.AddressTxs(addrID), args.Cursor, args.PageSize, reply)
+}
+
+// GetAssetTxsArgs are arguments for passing into GetAssetTxs requests
+type GetAssetTxsArgs struct {
+	AssetID string `json:"assetID"`
+
+	// The position of the first tx to return. 0 is the first tx that touched
+	// the asset.
+	Cursor json.Uint64 `json:"cursor"`
+
+	// The maximum number of txs to return. If 0, or more than maxPageSize,
+	// maxPageSize txs are returned.
+	PageSize json.Uint64 `json:"pageSize"`
+}
+
+// GetAssetTxs returns the accepted txs that created, minted, consumed or
+// produced the specified asset
+func (service *Service) GetAssetTxs(r *http.Request, args *GetAssetTxsArgs, reply *GetIndexedTxsReply) error {
+	service.vm.ctx.Log.Verbo("GetAssetTxs called with assetID: %s cursor: %d pageSize: %d", args.AssetID, args.Cursor, args.PageSize)
+
+	assetID, err := service.vm.Lookup(args.AssetID)
+	if err != nil {
+		assetID, err = ids.FromString(args.AssetID)
+		if err != nil {
+			return err
+		}
+	}
+	return service.indexedTxs(service.vm.state.AssetTxs(assetID), args.Cursor, args.PageSize, reply)
+}
+
+func (service *Service) indexedTxs(index ids.ID, cursor, pageSize json.Uint64, reply *GetIndexedTxsReply) error {
+	if !service.vm.indexTxs {
+		return errTxIndexDisabled
+	}
+	if pageSize == 0 || pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	txIDs, err := service.vm.state.IndexedTxs(index, uint64(cursor), uint64(pageSize))
+	if err != nil {
+		return err
+	}
+	reply.TxIDs = txIDs
+	reply.Cursor = cursor + json.Uint64(len(txIDs))
+	return nil
+}
+
+// Index is a position in the utxos of a list of addresses
+type Index struct {
+	Address string `json:"address"`
+	UTXO    ids.ID `json:"utxo"`
+}
+
 // GetUTXOsArgs are arguments for passing into GetUTXOs requests
 type GetUTXOsArgs struct {
 	Addresses []string `json:"addresses"`
+
+	// The maximum number of utxos to return. If 0, or more than
+	// maxUTXOsToFetch, maxUTXOsToFetch utxos are returned.
+	Limit json.Uint32 `json:"limit"`
+
+	// The utxos are returned starting after this index. If empty, they start
+	// with the first utxo of the first address.
+	StartIndex Index `json:"startIndex"`
 }
 
 // GetUTXOsReply defines the GetUTXOs replies returned from the API
 type GetUTXOsReply struct {
-	UTXOs []formatting.CB58 `json:"utxos"`
+	NumFetched json.Uint64       `json:"numFetched"`
+	UTXOs      []formatting.CB58 `json:"utxos"`
+
+	// The index to pass in to get the next page. If fewer than the limit utxos
+	// were returned, there are no more utxos.
+	EndIndex Index `json:"endIndex"`
 }
 
-// GetUTXOs creates an empty account with the name passed in
+// GetUTXOs returns a page of the utxos that at least one of the provided
+// addresses is referenced in
 func (service *Service) GetUTXOs(r *http.Request, args *GetUTXOsArgs, reply *GetUTXOsReply) error {
 	service.vm.ctx.Log.Verbo("GetUTXOs called with %s", args.Addresses)
 
-	addrSet := ids.Set{}
-	for _, addr := range args.Addresses {
-		addrBytes, err := service.vm.Parse(addr)
+	addrs := []ids.ID{}
+	addrStrs := map[[32]byte]string{}
+	for _, addrStr := range args.Addresses {
+		addrBytes, err := service.vm.Parse(addrStr)
 		if err != nil {
 			return err
 		}
-		addrSet.Add(ids.NewID(hashing.ComputeHash256Array(addrBytes)))
+		addr := ids.NewID(hashing.ComputeHash256Array(addrBytes))
+		if _, ok := addrStrs[addr.Key()]; ok {
+			continue
+		}
+		addrs = append(addrs, addr)
+		addrStrs[addr.Key()] = addrStr
+	}
+
+	startAddr := ids.ID{}
+	if args.StartIndex.Address != "" {
+		addrBytes, err := service.vm.Parse(args.StartIndex.Address)
+		if err != nil {
+			return err
+		}
+		startAddr = ids.NewID(hashing.ComputeHash256Array(addrBytes))
+	}
+
+	limit := int(args.Limit)
+	if limit <= 0 || limit > maxUTXOsToFetch {
+		limit = maxUTXOsToFetch
 	}
 
-	utxos, err := service.vm.GetUTXOs(addrSet)
+	utxos, endAddr, endUTXO, err := service.vm.GetPaginatedUTXOs(addrs, startAddr, args.StartIndex.UTXO, limit)
 	if err != nil {
 		return err
 	}
@@ -128,6 +313,11 @@ func (service *Service) GetUTXOs(r *http.Request, args *GetUTXOsArgs, reply *Get
 		}
 		reply.UTXOs = append(reply.UTXOs, formatting.CB58{Bytes: b})
 	}
+	reply.NumFetched = json.Uint64(len(utxos))
+	if !endAddr.IsZero() {
+		reply.EndIndex.Address = addrStrs[endAddr.Key()]
+		reply.EndIndex.UTXO = endUTXO
+	}
 	return nil
 }
 
@@ -176,18 +366,74 @@ func (service *Service) GetAssetDescription(_ *http.Request, args *GetAssetDescr
 	return nil
 }
 
+// GetAssetSupplyArgs are arguments for passing into GetAssetSupply requests
+type GetAssetSupplyArgs struct {
+	AssetID string `json:"assetID"`
+}
+
+// GetAssetSupplyReply defines the GetAssetSupply replies returned from the API.
+// [Circulating] is [Minted] minus [Burned], or 0 if more was burnt than minted.
+type GetAssetSupplyReply struct {
+	Minted      json.Uint64 `json:"minted"`
+	Burned      json.Uint64 `json:"burned"`
+	Circulating json.Uint64 `json:"circulating"`
+}
+
+// GetAssetSupply returns how much of an asset the accepted txs have minted and
+// burnt. Funds imported from another chain are minted, and funds exported to
+// another chain are burnt. A node that accepted txs before it indexed the supply
+// only counts the genesis supply, and the txs accepted since it started
+// indexing.
+func (service *Service) GetAssetSupply(_ *http.Request, args *GetAssetSupplyArgs, reply *GetAssetSupplyReply) error {
+	service.vm.ctx.Log.Verbo("GetAssetSupply called with %s", args.AssetID)
+
+	assetID, err := service.vm.Lookup(args.AssetID)
+	if err != nil {
+		assetID, err = ids.FromString(args.AssetID)
+		if err != nil {
+			return err
+		}
+	}
+
+	tx := &UniqueTx{
+		vm:   service.vm,
+		txID: assetID,
+	}
+	if status := tx.Status(); status != choices.Accepted {
+		return errUnknownAssetID
+	}
+	if _, ok := tx.t.tx.UnsignedTx.(*CreateAssetTx); !ok {
+		return errTxNotCreateAsset
+	}
+
+	minted, burned, err := service.vm.state.Supply(assetID)
+	if err != nil {
+		return fmt.Errorf("problem retrieving the supply of the asset: %w", err)
+	}
+	reply.Minted = json.Uint64(minted)
+	reply.Burned = json.Uint64(burned)
+	if minted > burned {
+		reply.Circulating = json.Uint64(minted - burned)
+	}
+	return nil
+}
+
 // GetBalanceArgs are arguments for passing into GetBalance requests
 type GetBalanceArgs struct {
 	Address string `json:"address"`
 	AssetID string `json:"assetID"`
 }
 
-// GetBalanceReply defines the GetBalance replies returned from the API
+// GetBalanceReply defines the GetBalance replies returned from the API.
+// [Balance] is the sum of [Unlocked] and [Locked].
 type GetBalanceReply struct {
-	Balance json.Uint64 `json:"balance"`
+	Balance  json.Uint64 `json:"balance"`
+	Unlocked json.Uint64 `json:"unlocked"`
+	Locked   json.Uint64 `json:"locked"`
 }
 
-// GetBalance returns the amount of an asset that an address at least partially owns
+// GetBalance returns the amount of an asset that an address at least partially
+// owns, and how much of it is locked now
 func (service *Service) GetBalance(r *http.Request, args *GetBalanceArgs, reply *GetBalanceReply) error {
 	service.vm.ctx.Log.Verbo("GetBalance called with address: %s assetID: %s", args.Address, args.AssetID)
 
@@ -204,16 +450,21 @@ func (service *Service) GetBalance(r *http.Request, args *GetBalanceArgs, reply
 		}
 	}
 
-	addrSet := ids.Set{}
-	addrSet.Add(ids.NewID(hashing.ComputeHash256Array(address)))
-
-	utxos, err := service.vm.GetUTXOs(addrSet)
-	if err != nil {
-		return err
-	}
+	time := service.vm.clock.Unix()
 
-	for _, utxo := range utxos {
-		if utxo.AssetID().Equals(assetID) {
+	// Page through the address's utxos, so that they aren't all in memory at
+	// once
+	addrs := []ids.ID{ids.NewID(hashing.ComputeHash256Array(address))}
+	startAddr, startUTXO := ids.ID{}, ids.ID{}
+	for {
+		utxos, endAddr, endUTXO, err := service.vm.GetPaginatedUTXOs(addrs, startAddr, startUTXO, maxUTXOsToFetch)
+		if err != nil {
+			return err
+		}
+		for _, utxo := range utxos {
+			if !utxo.AssetID().Equals(assetID) {
+				continue
+			}
 			transferable, ok := utxo.Out.(FxTransferable)
 			if !ok {
 				continue
@@ -223,8 +474,38 @@ func (service *Service) GetBalance(r *http.Request, args *GetBalanceArgs, reply
 				return err
 			}
 			reply.Balance = json.Uint64(amt)
+
+			locked := uint64(0)
+			if lockable, ok := utxo.Out.(FxLockable); ok {
+				locked = lockable.Locked(time)
+			}
+			// Neither sum can overflow, as their total doesn't
+			reply.Locked += json.Uint64(locked)
+			reply.Unlocked += json.Uint64(transferable.Amount() - locked)
+		}
+		if len(utxos) < maxUTXOsToFetch {
+			return nil
 		}
+		startAddr, startUTXO = endAddr, endUTXO
 	}
+}
+
+// GetTxFeeArgs are arguments for passing into GetTxFee requests
+type GetTxFeeArgs struct{}
+
+// GetTxFeeReply defines the GetTxFee replies returned from the API
+type GetTxFeeReply struct {
+	TxFee   json.Uint64 `json:"txFee"`
+	AssetID ids.ID      `json:"assetID"`
+}
+
+// GetTxFee returns the amount of the asset [reply.AssetID] that every
+// transaction must burn
+func (service *Service) GetTxFee(_ *http.Request, _ *GetTxFeeArgs, reply *GetTxFeeReply) error {
+	service.vm.ctx.Log.Verbo("GetTxFee called")
+
+	reply.TxFee = json.Uint64(service.vm.txFee)
+	reply.AssetID = service.vm.ava
 	return nil
 }
 
@@ -238,10 +519,48 @@ type CreateFixedCapAssetArgs struct {
 	InitialHolders []*Holder `json:"initialHolders"`
 }
 
-// Holder describes how much an address owns of an asset
+// Holder describes how much an address owns of an asset, and when it can be
+// spent
 type Holder struct {
 	Amount  json.Uint64 `json:"amount"`
 	Address string      `json:"address"`
+	Lock
+}
+
+// Lock describes when the value of an output can be spent. If [VestingEnd] is
+// set, the value unlocks linearly from [VestingStart] to [VestingEnd].
+// Otherwise, it's locked until [Locktime]. Times are unix timestamps.
+type Lock struct {
+	Locktime     json.Uint64 `json:"locktime,omitempty"`
+	VestingStart json.Uint64 `json:"vestingStart,omitempty"`
+	VestingEnd   json.Uint64 `json:"vestingEnd,omitempty"`
+}
+
+// output returns an output that sends [amount] to [addr] under this lock
+func (l *Lock) output(amount uint64, addr ids.ShortID) (FxTransferable, error) {
+	owners := secp256k1fx.OutputOwners{
+		Threshold: 1,
+		Addrs:     []ids.ShortID{addr},
+	}
+	switch {
+	case l.VestingStart == 0 && l.VestingEnd == 0:
+		return &secp256k1fx.TransferOutput{
+			Amt:          amount,
+			Locktime:     uint64(l.Locktime),
+			OutputOwners: owners,
+		}, nil
+	case l.Locktime != 0:
+		return nil, errLockedAndVesting
+	case l.VestingStart >= l.VestingEnd:
+		return nil, errInvalidVestingPeriod
+	default:
+		return &secp256k1fx.VestingOutput{
+			Amt:          amount,
+			VestingStart: uint64(l.VestingStart),
+			VestingEnd:   uint64(l.VestingEnd),
+			OutputOwners: owners,
+		}, nil
+	}
 }
 
 // CreateFixedCapAssetReply defines the CreateFixedCapAsset replies returned from the API
@@ -261,6 +580,11 @@ func (service *Service) CreateFixedCapAsset(r *http.Request, args *CreateFixedCa
 		return errNoHolders
 	}
 
+	ins, outs, keys, err := service.payFee(args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+
 	initialState := &InitialState{
 		FxID: 0, // TODO: Should lookup secp256k1fx FxID
 		Outs: []verify.Verifiable{},
@@ -270,6 +594,8 @@ func (service *Service) CreateFixedCapAsset(r *http.Request, args *CreateFixedCa
 		BaseTx: BaseTx{
 			NetID: service.vm.ctx.NetworkID,
 			BCID:  service.vm.ctx.ChainID,
+			Outs:  outs,
+			Ins:   ins,
 		},
 		Name:         args.Name,
 		Symbol:       args.Symbol,
@@ -288,24 +614,17 @@ func (service *Service) CreateFixedCapAsset(r *http.Request, args *CreateFixedCa
 		if err != nil {
 			return err
 		}
-		initialState.Outs = append(initialState.Outs, &secp256k1fx.TransferOutput{
-			Amt: uint64(holder.Amount),
-			OutputOwners: secp256k1fx.OutputOwners{
-				Threshold: 1,
-				Addrs:     []ids.ShortID{addr},
-			},
-		})
+		out, err := holder.output(uint64(holder.Amount), addr)
+		if err != nil {
+			return err
+		}
+		initialState.Outs = append(initialState.Outs, out)
 	}
 	initialState.Sort(service.vm.codec)
 
-	b, err := service.vm.codec.Marshal(tx)
-	if err != nil {
-		return fmt.Errorf("problem creating transaction: %w", err)
-	}
-
-	assetID, err := service.vm.IssueTx(b)
+	assetID, err := service.signAndIssue(tx, keys)
 	if err != nil {
-		return fmt.Errorf("problem issuing transaction: %w", err)
+		return err
 	}
 
 	reply.AssetID = assetID
@@ -321,6 +640,9 @@ type CreateVariableCapAssetArgs struct {
 	Symbol       string   `json:"symbol"`
 	Denomination byte     `json:"denomination"`
 	MinterSets   []Owners `json:"minterSets"`
+
+	// InitialHolders are optionally given some of the asset when it's created
+	InitialHolders []*Holder `json:"initialHolders"`
 }
 
 // Owners describes who can perform an action
@@ -346,6 +668,11 @@ func (service *Service) CreateVariableCapAsset(r *http.Request, args *CreateVari
 		return errNoMinters
 	}
 
+	ins, outs, keys, err := service.payFee(args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+
 	initialState := &InitialState{
 		FxID: 0, // TODO: Should lookup secp256k1fx FxID
 		Outs: []verify.Verifiable{},
@@ -355,6 +682,8 @@ func (service *Service) CreateVariableCapAsset(r *http.Request, args *CreateVari
 		BaseTx: BaseTx{
 			NetID: service.vm.ctx.NetworkID,
 			BCID:  service.vm.ctx.ChainID,
+			Outs:  outs,
+			Ins:   ins,
 		},
 		Name:         args.Name,
 		Symbol:       args.Symbol,
@@ -384,16 +713,26 @@ func (service *Service) CreateVariableCapAsset(r *http.Request, args *CreateVari
 		ids.SortShortIDs(minter.Addrs)
 		initialState.Outs = append(initialState.Outs, minter)
 	}
-	initialState.Sort(service.vm.codec)
-
-	b, err := service.vm.codec.Marshal(tx)
-	if err != nil {
-		return fmt.Errorf("problem creating transaction: %w", err)
+	for _, holder := range args.InitialHolders {
+		address, err := service.vm.Parse(holder.Address)
+		if err != nil {
+			return err
+		}
+		addr, err := ids.ToShortID(address)
+		if err != nil {
+			return err
+		}
+		out, err := holder.output(uint64(holder.Amount), addr)
+		if err != nil {
+			return err
+		}
+		initialState.Outs = append(initialState.Outs, out)
 	}
+	initialState.Sort(service.vm.codec)
 
-	assetID, err := service.vm.IssueTx(b)
+	assetID, err := service.signAndIssue(tx, keys)
 	if err != nil {
-		return fmt.Errorf("problem issuing transaction: %w", err)
+		return err
 	}
 
 	reply.AssetID = assetID
@@ -416,6 +755,10 @@ type CreateAddressReply struct {
 func (service *Service) CreateAddress(r *http.Request, args *CreateAddressArgs, reply *CreateAddressReply) error {
 	service.vm.ctx.Log.Verbo("CreateAddress called for user '%s'", args.Username)
 
+	if service.vm.ctx.RemoteSigner != nil {
+		return errRemoteSigner
+	}
+
 	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
 	if err != nil {
 		return fmt.Errorf("problem retrieving user: %w", err)
@@ -462,6 +805,10 @@ type ExportKeyReply struct {
 func (service *Service) ExportKey(r *http.Request, args *ExportKeyArgs, reply *ExportKeyReply) error {
 	service.vm.ctx.Log.Verbo("ExportKey called for user '%s'", args.Username)
 
+	if service.vm.ctx.RemoteSigner != nil {
+		return errRemoteSigner
+	}
+
 	address, err := service.vm.Parse(args.Address)
 	if err != nil {
 		return fmt.Errorf("problem parsing address: %w", err)
@@ -500,6 +847,10 @@ type ImportKeyReply struct {
 func (service *Service) ImportKey(r *http.Request, args *ImportKeyArgs, reply *ImportKeyReply) error {
 	service.vm.ctx.Log.Verbo("ImportKey called for user '%s'", args.Username)
 
+	if service.vm.ctx.RemoteSigner != nil {
+		return errRemoteSigner
+	}
+
 	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
 	if err != nil {
 		return fmt.Errorf("problem retrieving data: %w", err)
@@ -536,6 +887,9 @@ type SendArgs struct {
 	Amount   json.Uint64 `json:"amount"`
 	AssetID  string      `json:"assetID"`
 	To       string      `json:"to"`
+
+	// Lock optionally locks the sent funds
+	Lock
 }
 
 // SendReply defines the Send replies returned from the API
@@ -568,38 +922,119 @@ func (service *Service) Send(r *http.Request, args *SendArgs, reply *SendReply)
 		return fmt.Errorf("problem parsing to address: %w", err)
 	}
 
-	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
+	kc, err := service.keychain(args.Username, args.Password)
 	if err != nil {
-		return fmt.Errorf("problem retrieving user: %w", err)
+		return err
 	}
 
-	user := userState{vm: service.vm}
+	amounts := map[[32]byte]uint64{
+		assetID.Key(): uint64(args.Amount),
+	}
+	amountsWithFee, err := service.withFee(amounts)
+	if err != nil {
+		return err
+	}
 
-	addresses, _ := user.Addresses(db)
+	toOut, err := args.output(uint64(args.Amount), to)
+	if err != nil {
+		return err
+	}
 
-	addrs := ids.Set{}
-	addrs.Add(addresses...)
-	utxos, err := service.vm.GetUTXOs(addrs)
+	amountsSpent, ins, relocks, keys, err := service.spend(kc, amountsWithFee)
+	if err != nil {
+		return err
+	}
+
+	outs := []*TransferableOutput{
+		&TransferableOutput{
+			Asset: Asset{
+				ID: assetID,
+			},
+			Out: toOut,
+		},
+	}
+	outs = append(outs, relocks...)
+	outs = append(outs, service.change(kc, amountsSpent, amountsWithFee)...)
+	sortTransferableOutputs(outs, service.vm.codec)
+
+	tx := Tx{
+		UnsignedTx: &BaseTx{
+			NetID: service.vm.ctx.NetworkID,
+			BCID:  service.vm.ctx.ChainID,
+			Outs:  outs,
+			Ins:   ins,
+		},
+	}
+
+	txID, err := service.signAndIssue(&tx, keys)
 	if err != nil {
-		return fmt.Errorf("problem retrieving user's UTXOs: %w", err)
+		return err
 	}
 
-	kc := secp256k1fx.NewKeychain()
-	for _, addr := range addresses {
-		sk, err := user.Key(db, addr)
+	reply.TxID = txID
+	return nil
+}
+
+// signer returns the signer of the user [username]. The user's keys are held
+// by the remote signer if the node has one, and in the keystore otherwise.
+func (service *Service) signer(username, password string) (snow.Signer, error) {
+	if remote := service.vm.ctx.RemoteSigner; remote != nil {
+		signer, err := remote.GetSigner(username, password)
 		if err != nil {
-			return fmt.Errorf("problem retrieving private key: %w", err)
+			return nil, fmt.Errorf("problem retrieving user: %w", err)
 		}
-		kc.Add(sk)
+		return signer, nil
+	}
+
+	db, err := service.vm.ctx.Keystore.GetDatabase(username, password)
+	if err != nil {
+		return nil, fmt.Errorf("problem retrieving user: %w", err)
+	}
+	return &keystoreSigner{
+		user: &userState{vm: service.vm},
+		db:   db,
+	}, nil
+}
+
+// keychain returns the addresses of the user [username]
+func (service *Service) keychain(username, password string) (*keychain, error) {
+	signer, err := service.signer(username, password)
+	if err != nil {
+		return nil, err
+	}
+	kc, err := newKeychain(signer)
+	if err != nil {
+		return nil, fmt.Errorf("problem retrieving addresses: %w", err)
+	}
+	return kc, nil
+}
+
+// spend returns inputs, that [kc] can sign, which consume at least
+// [amounts[assetID]] of every asset in [amounts]. Returns the amount of every
+// asset consumed by the inputs, the outputs that lock again what's still locked
+// of the consumed vesting outputs and the keys that must sign each input. The
+// amounts consumed don't include what's locked again. The inputs are sorted.
+func (service *Service) spend(kc *keychain, amounts map[[32]byte]uint64) (map[[32]byte]uint64, []*TransferableInput, []*TransferableOutput, [][]signingKey, error) {
+	utxos, err := service.keychainUTXOs(kc)
+	if err != nil {
+		return nil, nil, nil, nil, err
 	}
+	return service.spendUTXOs(kc, utxos, amounts)
+}
 
-	amountSpent := uint64(0)
+// spendUTXOs is spend, but the inputs only consume [utxos]
+func (service *Service) spendUTXOs(kc *keychain, utxos []*UTXO, amounts map[[32]byte]uint64) (map[[32]byte]uint64, []*TransferableInput, []*TransferableOutput, [][]signingKey, error) {
+	amountsSpent := make(map[[32]byte]uint64, len(amounts))
 	time := service.vm.clock.Unix()
 
 	ins := []*TransferableInput{}
-	keys := [][]*crypto.PrivateKeySECP256K1R{}
+	relocks := []*TransferableOutput{}
+	keys := [][]signingKey{}
 	for _, utxo := range utxos {
-		if !utxo.AssetID().Equals(assetID) {
+		assetID := utxo.AssetID()
+		assetKey := assetID.Key()
+		if amountsSpent[assetKey] >= amounts[assetKey] {
+			// Enough of this asset, or none of it, is needed
 			continue
 		}
 		inputIntf, signers, err := kc.Spend(utxo.Out, time)
@@ -610,11 +1045,16 @@ func (service *Service) Send(r *http.Request, args *SendArgs, reply *SendReply)
 		if !ok {
 			continue
 		}
-		spent, err := math.Add64(amountSpent, input.Amount())
+		amount := input.Amount()
+		if relock := relock(assetID, utxo.Out, time); relock != nil {
+			amount -= relock.Out.Amount()
+			relocks = append(relocks, relock)
+		}
+		spent, err := math.Add64(amountsSpent[assetKey], amount)
 		if err != nil {
-			return errSpendOverflow
+			return nil, nil, nil, nil, errSpendOverflow
 		}
-		amountSpent = spent
+		amountsSpent[assetKey] = spent
 
 		in := &TransferableInput{
 			UTXOID: utxo.UTXOID,
@@ -624,64 +1064,147 @@ func (service *Service) Send(r *http.Request, args *SendArgs, reply *SendReply)
 
 		ins = append(ins, in)
 		keys = append(keys, signers)
-
-		if amountSpent >= uint64(args.Amount) {
-			break
-		}
 	}
 
-	if amountSpent < uint64(args.Amount) {
-		return errInsufficientFunds
+	for assetKey, amount := range amounts {
+		if amountsSpent[assetKey] < amount {
+			return nil, nil, nil, nil, errInsufficientFunds
+		}
 	}
 
 	sortTransferableInputsWithSigners(ins, keys)
+	return amountsSpent, ins, relocks, keys, nil
+}
 
-	outs := []*TransferableOutput{
-		&TransferableOutput{
-			Asset: Asset{
-				ID: assetID,
+// relock returns the output that locks again the part of [out] that's still
+// locked at [time], if [out] is a vesting output. The new output keeps
+// unlocking at the same rate, until the same time.
+func relock(assetID ids.ID, out verify.Verifiable, time uint64) *TransferableOutput {
+	vesting, ok := out.(*secp256k1fx.VestingOutput)
+	if !ok {
+		return nil
+	}
+	locked := vesting.Locked(time)
+	if locked == 0 {
+		return nil
+	}
+	return &TransferableOutput{
+		Asset: Asset{
+			ID: assetID,
+		},
+		Out: &secp256k1fx.VestingOutput{
+			Amt:          locked,
+			VestingStart: time,
+			VestingEnd:   vesting.VestingEnd,
+			OutputOwners: vesting.OutputOwners,
+		},
+	}
+}
+
+// withFee returns [amounts], plus the tx fee in AVA
+func (service *Service) withFee(amounts map[[32]byte]uint64) (map[[32]byte]uint64, error) {
+	amountsWithFee := make(map[[32]byte]uint64, len(amounts)+1)
+	for assetKey, amount := range amounts {
+		amountsWithFee[assetKey] = amount
+	}
+	if service.vm.txFee == 0 {
+		return amountsWithFee, nil
+	}
+	if service.vm.ava.IsZero() {
+		return nil, errUnknownAVA
+	}
+
+	avaKey := service.vm.ava.Key()
+	amountWithFee, err := math.Add64(amountsWithFee[avaKey], service.vm.txFee)
+	if err != nil {
+		return nil, errSpendOverflow
+	}
+	amountsWithFee[avaKey] = amountWithFee
+	return amountsWithFee, nil
+}
+
+// change returns the outputs that send back to [kc] whatever was spent, as
+// described by [amountsSpent], beyond what was needed, as described by
+// [amounts]
+func (service *Service) change(kc *keychain, amountsSpent, amounts map[[32]byte]uint64) []*TransferableOutput {
+	changeAddr := kc.ChangeAddress()
+
+	outs := []*TransferableOutput{}
+	for assetKey, amountSpent := range amountsSpent {
+		if amountSpent <= amounts[assetKey] {
+			continue
+		}
+		outs = append(outs, &TransferableOutput{
+			Asset: Asset{
+				ID: ids.NewID(assetKey),
 			},
 			Out: &secp256k1fx.TransferOutput{
-				Amt:      uint64(args.Amount),
+				Amt:      amountSpent - amounts[assetKey],
 				Locktime: 0,
 				OutputOwners: secp256k1fx.OutputOwners{
 					Threshold: 1,
-					Addrs:     []ids.ShortID{to},
+					Addrs:     []ids.ShortID{changeAddr},
 				},
 			},
-		},
+		})
 	}
+	sortTransferableOutputs(outs, service.vm.codec)
+	return outs
+}
 
-	if amountSpent > uint64(args.Amount) {
-		changeAddr := kc.Keys[0].PublicKey().Address()
-		outs = append(outs,
-			&TransferableOutput{
-				Asset: Asset{
-					ID: assetID,
-				},
-				Out: &secp256k1fx.TransferOutput{
-					Amt:      amountSpent - uint64(args.Amount),
-					Locktime: 0,
-					OutputOwners: secp256k1fx.OutputOwners{
-						Threshold: 1,
-						Addrs:     []ids.ShortID{changeAddr},
-					},
-				},
-			},
-		)
+// payFee returns inputs, that the user [username] can sign, which consume the
+// tx fee, the outputs that send the change back to the user, or lock it again,
+// and the keys that must sign each input. If there is no tx fee, nothing is returned and the
+// user isn't looked up.
+func (service *Service) payFee(username, password string) ([]*TransferableInput, []*TransferableOutput, [][]signingKey, error) {
+	if service.vm.txFee == 0 {
+		return nil, nil, nil, nil
+	}
+
+	kc, err := service.keychain(username, password)
+	if err != nil {
+		return nil, nil, nil, err
 	}
 
+	amounts, err := service.withFee(nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	amountsSpent, ins, relocks, keys, err := service.spend(kc, amounts)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	outs := append(relocks, service.change(kc, amountsSpent, amounts)...)
 	sortTransferableOutputs(outs, service.vm.codec)
+	return ins, outs, keys, nil
+}
 
-	tx := Tx{
-		UnsignedTx: &BaseTx{
-			NetID: service.vm.ctx.NetworkID,
-			BCID:  service.vm.ctx.ChainID,
-			Outs:  outs,
-			Ins:   ins,
-		},
+// signAndIssue signs [tx] with [keys], where keys[i] are the keys that must
+// sign the i-th input the tx consumes, and issues it
+func (service *Service) signAndIssue(tx *Tx, keys [][]signingKey) (ids.ID, error) {
+	if err := service.sign(tx, keys); err != nil {
+		return ids.ID{}, err
+	}
+	return service.issue(tx)
+}
+
+// issue [tx], which must be signed
+func (service *Service) issue(tx *Tx) (ids.ID, error) {
+	b, err := service.vm.codec.Marshal(tx)
+	if err != nil {
+		return ids.ID{}, fmt.Errorf("problem creating transaction: %w", err)
+	}
+
+	txID, err := service.vm.IssueTx(b)
+	if err != nil {
+		return ids.ID{}, fmt.Errorf("problem issuing transaction: %w", err)
 	}
+	return txID, nil
+}
 
+// sign adds to [tx] a credential for each element of [keys], where keys[i] are
+// the keys that must sign the i-th input the tx consumes
+func (service *Service) sign(tx *Tx, keys [][]signingKey) error {
 	unsignedBytes, err := service.vm.codec.Marshal(&tx.UnsignedTx)
 	if err != nil {
 		return fmt.Errorf("problem creating transaction: %w", err)
@@ -702,24 +1225,244 @@ func (service *Service) Send(r *http.Request, args *SendArgs, reply *SendReply)
 		}
 		tx.Creds = append(tx.Creds, &Credential{Cred: cred})
 	}
+	return nil
+}
 
-	b, err := service.vm.codec.Marshal(tx)
+// ExportAVAArgs are arguments for passing into ExportAVA requests
+type ExportAVAArgs struct {
+	Username string      `json:"username"`
+	Password string      `json:"password"`
+	Amount   json.Uint64 `json:"amount"`
+	To       ids.ShortID `json:"to"`
+}
+
+// ExportAVAReply defines the ExportAVA replies returned from the API
+type ExportAVAReply struct {
+	TxID ids.ID `json:"txID"`
+}
+
+// ExportAVA sends [args.Amount] AVA from the user [args.Username] to the
+// platform chain address [args.To]. The funds must then be imported by the
+// platform chain before they can be spent there.
+func (service *Service) ExportAVA(_ *http.Request, args *ExportAVAArgs, reply *ExportAVAReply) error {
+	service.vm.ctx.Log.Verbo("ExportAVA called with username: %s", args.Username)
+
+	switch {
+	case args.Amount == 0:
+		return errInvalidAmount
+	case service.vm.ava.IsZero():
+		return errUnknownAVA
+	}
+
+	kc, err := service.keychain(args.Username, args.Password)
 	if err != nil {
-		return fmt.Errorf("problem creating transaction: %w", err)
+		return err
 	}
 
-	txID, err := service.vm.IssueTx(b)
+	amounts := map[[32]byte]uint64{
+		service.vm.ava.Key(): uint64(args.Amount),
+	}
+	amountsWithFee, err := service.withFee(amounts)
+	if err != nil {
+		return err
+	}
+
+	amountsSpent, ins, relocks, keys, err := service.spend(kc, amountsWithFee)
+	if err != nil {
+		return err
+	}
+
+	exportOuts := []*TransferableOutput{
+		&TransferableOutput{
+			Asset: Asset{
+				ID: service.vm.ava,
+			},
+			Out: &secp256k1fx.TransferOutput{
+				Amt:      uint64(args.Amount),
+				Locktime: 0,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{args.To},
+				},
+			},
+		},
+	}
+
+	outs := append(relocks, service.change(kc, amountsSpent, amountsWithFee)...)
+	sortTransferableOutputs(outs, service.vm.codec)
+
+	tx := Tx{
+		UnsignedTx: &ExportTx{
+			BaseTx: BaseTx{
+				NetID: service.vm.ctx.NetworkID,
+				BCID:  service.vm.ctx.ChainID,
+				Outs:  outs,
+				Ins:   ins,
+			},
+			ExportOuts: exportOuts,
+		},
+	}
+
+	txID, err := service.signAndIssue(&tx, keys)
+	if err != nil {
+		return err
+	}
+
+	reply.TxID = txID
+	return nil
+}
+
+// ImportAVAArgs are arguments for passing into ImportAVA requests
+type ImportAVAArgs struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	To       string `json:"to"`
+}
+
+// ImportAVAReply defines the ImportAVA replies returned from the API
+type ImportAVAReply struct {
+	TxID ids.ID `json:"txID"`
+}
+
+// ImportAVA sends all the AVA that the platform chain exported to the user
+// [args.Username] to the address [args.To]
+func (service *Service) ImportAVA(_ *http.Request, args *ImportAVAArgs, reply *ImportAVAReply) error {
+	service.vm.ctx.Log.Verbo("ImportAVA called with username: %s", args.Username)
+
+	if service.vm.ava.IsZero() {
+		return errUnknownAVA
+	}
+
+	toBytes, err := service.vm.Parse(args.To)
+	if err != nil {
+		return fmt.Errorf("problem parsing to address: %w", err)
+	}
+	to, err := ids.ToShortID(toBytes)
+	if err != nil {
+		return fmt.Errorf("problem parsing to address: %w", err)
+	}
+
+	kc, err := service.keychain(args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+
+	utxos, err := service.importableUTXOs(kc.Addresses())
+	if err != nil {
+		return fmt.Errorf("problem retrieving user's imported UTXOs: %w", err)
+	}
+
+	amount := uint64(0)
+	time := service.vm.clock.Unix()
+
+	ins := []*TransferableInput{}
+	keys := [][]signingKey{}
+	for _, utxo := range utxos {
+		if !utxo.AssetID.Equals(service.vm.ava) {
+			continue
+		}
+		inputIntf, signers, err := kc.Spend(&utxo.Out, time)
+		if err != nil {
+			continue
+		}
+		input, ok := inputIntf.(FxTransferable)
+		if !ok {
+			continue
+		}
+		imported, err := math.Add64(amount, input.Amount())
+		if err != nil {
+			return errSpendOverflow
+		}
+		amount = imported
+
+		ins = append(ins, &TransferableInput{
+			UTXOID: UTXOID{
+				TxID:        utxo.TxID,
+				OutputIndex: utxo.OutputIndex,
+			},
+			Asset: Asset{ID: service.vm.ava},
+			In:    input,
+		})
+		keys = append(keys, signers)
+	}
+
+	if len(ins) == 0 {
+		return errNoImportableFunds
+	}
+	// The tx fee is paid out of the imported funds
+	if amount <= service.vm.txFee {
+		return errInsufficientFunds
+	}
+
+	sortTransferableInputsWithSigners(ins, keys)
+
+	outs := []*TransferableOutput{
+		&TransferableOutput{
+			Asset: Asset{
+				ID: service.vm.ava,
+			},
+			Out: &secp256k1fx.TransferOutput{
+				Amt:      amount - service.vm.txFee,
+				Locktime: 0,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{to},
+				},
+			},
+		},
+	}
+
+	tx := Tx{
+		UnsignedTx: &ImportTx{
+			BaseTx: BaseTx{
+				NetID: service.vm.ctx.NetworkID,
+				BCID:  service.vm.ctx.ChainID,
+				Outs:  outs,
+			},
+			ImportIns: ins,
+		},
+	}
+
+	txID, err := service.signAndIssue(&tx, keys)
 	if err != nil {
-		return fmt.Errorf("problem issuing transaction: %w", err)
+		return err
 	}
 
 	reply.TxID = txID
 	return nil
 }
 
+// importableUTXOs returns the UTXOs the platform chain exported to this chain
+// that are owned by at least one of [addrs]
+func (service *Service) importableUTXOs(addrs ids.ShortSet) ([]*shared.UTXO, error) {
+	smDB := service.vm.ctx.SharedMemory.GetDatabase(service.vm.platform)
+	defer service.vm.ctx.SharedMemory.ReleaseDatabase(service.vm.platform)
+
+	state := shared.NewState(smDB, service.vm.ctx.ChainID)
+
+	utxoIDs := ids.Set{}
+	for _, addr := range addrs.List() {
+		addrUTXOIDs, err := state.Funds(addr)
+		if err != nil {
+			return nil, err
+		}
+		utxoIDs.Add(addrUTXOIDs...)
+	}
+
+	utxos := []*shared.UTXO{}
+	for _, utxoID := range utxoIDs.List() {
+		utxo, err := state.UTXO(utxoID)
+		if err != nil {
+			return nil, err
+		}
+		utxos = append(utxos, utxo)
+	}
+	return utxos, nil
+}
+
 type innerSortTransferableInputsWithSigners struct {
 	ins     []*TransferableInput
-	signers [][]*crypto.PrivateKeySECP256K1R
+	signers [][]signingKey
 }
 
 func (ins *innerSortTransferableInputsWithSigners) Less(i, j int) bool {
@@ -741,19 +1484,40 @@ func (ins *innerSortTransferableInputsWithSigners) Swap(i, j int) {
 	ins.signers[j], ins.signers[i] = ins.signers[i], ins.signers[j]
 }
 
-func sortTransferableInputsWithSigners(ins []*TransferableInput, signers [][]*crypto.PrivateKeySECP256K1R) {
+func sortTransferableInputsWithSigners(ins []*TransferableInput, signers [][]signingKey) {
 	sort.Sort(&innerSortTransferableInputsWithSigners{ins: ins, signers: signers})
 }
-func isSortedAndUniqueTransferableInputsWithSigners(ins []*TransferableInput, signers [][]*crypto.PrivateKeySECP256K1R) bool {
+func isSortedAndUniqueTransferableInputsWithSigners(ins []*TransferableInput, signers [][]signingKey) bool {
 	return utils.IsSortedAndUnique(&innerSortTransferableInputsWithSigners{ins: ins, signers: signers})
 }
 
-// CreateMintTxArgs are arguments for passing into CreateMintTx requests
+type innerSortOperableInputsWithSigners struct {
+	ins     []*OperableInput
+	signers [][]signingKey
+}
+
+func (ins *innerSortOperableInputsWithSigners) Less(i, j int) bool {
+	return innerSortOperableInputs(ins.ins).Less(i, j)
+}
+func (ins *innerSortOperableInputsWithSigners) Len() int { return len(ins.ins) }
+func (ins *innerSortOperableInputsWithSigners) Swap(i, j int) {
+	ins.ins[j], ins.ins[i] = ins.ins[i], ins.ins[j]
+	ins.signers[j], ins.signers[i] = ins.signers[i], ins.signers[j]
+}
+
+func sortOperableInputsWithSigners(ins []*OperableInput, signers [][]signingKey) {
+	sort.Sort(&innerSortOperableInputsWithSigners{ins: ins, signers: signers})
+}
+
+// CreateMintTxArgs are arguments for passing into CreateMintTx requests.
+// [Username] pays the tx fee, if there is one.
 type CreateMintTxArgs struct {
-	Amount  json.Uint64 `json:"amount"`
-	AssetID string      `json:"assetID"`
-	To      string      `json:"to"`
-	Minters []string    `json:"minters"`
+	Username string      `json:"username"`
+	Password string      `json:"password"`
+	Amount   json.Uint64 `json:"amount"`
+	AssetID  string      `json:"assetID"`
+	To       string      `json:"to"`
+	Minters  []string    `json:"minters"`
 }
 
 // CreateMintTxReply defines the CreateMintTx replies returned from the API
@@ -806,6 +1570,11 @@ func (service *Service) CreateMintTx(r *http.Request, args *CreateMintTxArgs, re
 		return fmt.Errorf("problem getting user's UTXOs: %w", err)
 	}
 
+	feeIns, feeOuts, feeKeys, err := service.payFee(args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+
 	for _, utxo := range utxos {
 		switch out := utxo.Out.(type) {
 		case *secp256k1fx.MintOutput:
@@ -828,6 +1597,8 @@ func (service *Service) CreateMintTx(r *http.Request, args *CreateMintTxArgs, re
 					BaseTx: BaseTx{
 						NetID: service.vm.ctx.NetworkID,
 						BCID:  service.vm.ctx.ChainID,
+						Outs:  feeOuts,
+						Ins:   feeIns,
 					},
 					Ops: []*Operation{
 						&Operation{
@@ -865,6 +1636,12 @@ func (service *Service) CreateMintTx(r *http.Request, args *CreateMintTxArgs, re
 				},
 			}
 
+			// The inputs paying the tx fee are signed now, as the minters only
+			// sign the mint input
+			if err := service.sign(&tx, feeKeys); err != nil {
+				return err
+			}
+
 			txBytes, err := service.vm.codec.Marshal(&tx)
 			if err != nil {
 				return fmt.Errorf("problem creating transaction: %w", err)
@@ -877,51 +1654,180 @@ func (service *Service) CreateMintTx(r *http.Request, args *CreateMintTxArgs, re
 	return errAddressesCantMintAsset
 }
 
-// SignMintTxArgs are arguments for passing into SignMintTx requests
-type SignMintTxArgs struct {
-	Username string          `json:"username"`
-	Password string          `json:"password"`
-	Minter   string          `json:"minter"`
-	Tx       formatting.CB58 `json:"tx"`
+// BurnArgs are arguments for passing into Burn requests
+type BurnArgs struct {
+	Username string      `json:"username"`
+	Password string      `json:"password"`
+	Amount   json.Uint64 `json:"amount"`
+	AssetID  string      `json:"assetID"`
 }
 
-// SignMintTxReply defines the SignMintTx replies returned from the API
-type SignMintTxReply struct {
-	Tx formatting.CB58 `json:"tx"`
+// BurnReply defines the Burn replies returned from the API
+type BurnReply struct {
+	TxID ids.ID `json:"txID"`
 }
 
-// SignMintTx returns the newly signed transaction
-func (service *Service) SignMintTx(r *http.Request, args *SignMintTxArgs, reply *SignMintTxReply) error {
-	service.vm.ctx.Log.Verbo("SignMintTx called")
+// Burn destroys [args.Amount] units of the asset [args.AssetID] that the user
+// [args.Username] owns. Only funds that aren't locked can be burnt.
+func (service *Service) Burn(_ *http.Request, args *BurnArgs, reply *BurnReply) error {
+	service.vm.ctx.Log.Verbo("Burn called with username: %s", args.Username)
 
-	minter, err := service.vm.Parse(args.Minter)
-	if err != nil {
-		return fmt.Errorf("problem parsing address '%s': %w", args.Minter, err)
+	if args.Amount == 0 {
+		return errInvalidAmount
 	}
 
-	db, err := service.vm.ctx.Keystore.GetDatabase(args.Username, args.Password)
+	assetID, err := service.vm.Lookup(args.AssetID)
 	if err != nil {
-		return fmt.Errorf("problem retrieving user: %w", err)
+		assetID, err = ids.FromString(args.AssetID)
+		if err != nil {
+			return fmt.Errorf("asset '%s' not found", args.AssetID)
+		}
 	}
 
-	user := userState{vm: service.vm}
-
-	addr := ids.NewID(hashing.ComputeHash256Array(minter))
-	sk, err := user.Key(db, addr)
+	kc, err := service.keychain(args.Username, args.Password)
 	if err != nil {
-		return fmt.Errorf("problem retriving private key: %w", err)
+		return err
 	}
-
+	utxos, err := service.keychainUTXOs(kc)
+	if err != nil {
+		return err
+	}
+
+	// The burn operation consumes transfer outputs of the asset. The UTXOs it
+	// doesn't consume can pay the tx fee.
+	time := service.vm.clock.Unix()
+	amount := uint64(args.Amount)
+	consumed := uint64(0)
+	ins := []*OperableInput{}
+	keys := [][]signingKey{}
+	unspent := []*UTXO{}
+	for _, utxo := range utxos {
+		out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+		if !ok || consumed >= amount || !utxo.AssetID().Equals(assetID) {
+			unspent = append(unspent, utxo)
+			continue
+		}
+		in, signers, err := kc.Spend(out, time)
+		if err != nil {
+			unspent = append(unspent, utxo)
+			continue
+		}
+		consumed, err = math.Add64(consumed, out.Amt)
+		if err != nil {
+			return errSpendOverflow
+		}
+		ins = append(ins, &OperableInput{
+			UTXOID: utxo.UTXOID,
+			In:     in,
+		})
+		keys = append(keys, signers)
+	}
+	if consumed < amount {
+		return errInsufficientFunds
+	}
+	sortOperableInputsWithSigners(ins, keys)
+
+	outs := []*OperableOutput{}
+	if consumed > amount {
+		outs = append(outs, &OperableOutput{
+			Out: &secp256k1fx.TransferOutput{
+				Amt: consumed - amount,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{kc.ChangeAddress()},
+				},
+			},
+		})
+	}
+
+	amountsWithFee, err := service.withFee(nil)
+	if err != nil {
+		return err
+	}
+	amountsSpent, feeIns, feeOuts, feeKeys, err := service.spendUTXOs(kc, unspent, amountsWithFee)
+	if err != nil {
+		return err
+	}
+	feeOuts = append(feeOuts, service.change(kc, amountsSpent, amountsWithFee)...)
+	sortTransferableOutputs(feeOuts, service.vm.codec)
+
+	tx := &Tx{UnsignedTx: &OperationTx{
+		BaseTx: BaseTx{
+			NetID: service.vm.ctx.NetworkID,
+			BCID:  service.vm.ctx.ChainID,
+			Outs:  feeOuts,
+			Ins:   feeIns,
+		},
+		Ops: []*Operation{
+			&Operation{
+				Asset: Asset{ID: assetID},
+				Ins:   ins,
+				Outs:  outs,
+			},
+		},
+	}}
+
+	txID, err := service.signAndIssue(tx, append(feeKeys, keys...))
+	if err != nil {
+		return err
+	}
+
+	reply.TxID = txID
+	return nil
+}
+
+// SignMintTxArgs are arguments for passing into SignMintTx requests
+type SignMintTxArgs struct {
+	Username string          `json:"username"`
+	Password string          `json:"password"`
+	Minter   string          `json:"minter"`
+	Tx       formatting.CB58 `json:"tx"`
+}
+
+// SignMintTxReply defines the SignMintTx replies returned from the API
+type SignMintTxReply struct {
+	Tx formatting.CB58 `json:"tx"`
+}
+
+// SignMintTx returns the newly signed transaction
+func (service *Service) SignMintTx(r *http.Request, args *SignMintTxArgs, reply *SignMintTxReply) error {
+	service.vm.ctx.Log.Verbo("SignMintTx called")
+
+	minter, err := service.vm.Parse(args.Minter)
+	if err != nil {
+		return fmt.Errorf("problem parsing address '%s': %w", args.Minter, err)
+	}
+
+	minterAddr, err := ids.ToShortID(minter)
+	if err != nil {
+		return fmt.Errorf("problem parsing address '%s': %w", args.Minter, err)
+	}
+
+	kc, err := service.keychain(args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+	sk, exists := kc.Get(minterAddr)
+	if !exists {
+		return errNoRequiredKeys
+	}
+
 	tx := Tx{}
 	if err := service.vm.codec.Unmarshal(args.Tx.Bytes, &tx); err != nil {
 		return fmt.Errorf("problem creating transaction: %w", err)
 	}
 
+	// The inputs paying the tx fee come before the mint input, and were signed
+	// when the tx was created
+	numFeeInputs := len(tx.Inputs())
 	inputUTXOs := tx.InputUTXOs()
-	if len(inputUTXOs) != 1 {
+	switch {
+	case len(inputUTXOs) != numFeeInputs+1:
 		return errCanOnlySignSingleInputTxs
+	case len(tx.Creds) < numFeeInputs:
+		return errUnsignedFeeInputs
 	}
-	inputUTXO := inputUTXOs[0]
+	inputUTXO := inputUTXOs[numFeeInputs]
 
 	inputTxID, utxoIndex := inputUTXO.InputSource()
 	utx := UniqueTx{
@@ -957,11 +1863,11 @@ func (service *Service) SignMintTx(r *http.Request, args *SignMintTxArgs, reply
 
 	}
 
-	if len(tx.Creds) == 0 {
+	if len(tx.Creds) == numFeeInputs {
 		tx.Creds = append(tx.Creds, &Credential{Cred: &secp256k1fx.Credential{}})
 	}
 
-	cred := tx.Creds[0]
+	cred := tx.Creds[numFeeInputs]
 	switch cred := cred.Cred.(type) {
 	case *secp256k1fx.Credential:
 		if len(cred.Sigs) != size {
@@ -973,7 +1879,7 @@ func (service *Service) SignMintTx(r *http.Request, args *SignMintTxArgs, reply
 			return fmt.Errorf("problem creating transaction: %w", err)
 		}
 
-		sig, err := sk.Sign(unsignedBytes)
+		sig, err := sk.SignHash(hashing.ComputeHash256(unsignedBytes))
 		if err != nil {
 			return fmt.Errorf("problem signing transaction: %w", err)
 		}
@@ -989,3 +1895,717 @@ func (service *Service) SignMintTx(r *http.Request, args *SignMintTxArgs, reply
 	reply.Tx.Bytes = txBytes
 	return nil
 }
+
+// CreateSendTxArgs are arguments for passing into CreateSendTx requests
+type CreateSendTxArgs struct {
+	From    []string    `json:"from"`
+	Amount  json.Uint64 `json:"amount"`
+	AssetID string      `json:"assetID"`
+	To      string      `json:"to"`
+}
+
+// CreateSendTxReply defines the CreateSendTx replies returned from the API
+type CreateSendTxReply struct {
+	Tx formatting.CB58 `json:"tx"`
+}
+
+// CreateSendTx returns a newly created unsigned transaction that sends
+// [args.Amount] of the asset [args.AssetID] to [args.To]. The transaction
+// spends UTXOs, possibly owned by several addresses, that the addresses in
+// [args.From] can spend together. The tx fee is paid out of the same UTXOs.
+// The change of each asset is sent back to the owners of the first UTXO of
+// that asset the transaction spends. The transaction's credentials have an
+// empty signature for each signature its inputs require, which is filled in by
+// SignTx.
+func (service *Service) CreateSendTx(r *http.Request, args *CreateSendTxArgs, reply *CreateSendTxReply) error {
+	service.vm.ctx.Log.Verbo("CreateSendTx called")
+
+	if args.Amount == 0 {
+		return errInvalidAmount
+	}
+	if len(args.From) == 0 {
+		return errNoSpenders
+	}
+
+	assetID, err := service.vm.Lookup(args.AssetID)
+	if err != nil {
+		assetID, err = ids.FromString(args.AssetID)
+		if err != nil {
+			return fmt.Errorf("asset '%s' not found", args.AssetID)
+		}
+	}
+
+	toBytes, err := service.vm.Parse(args.To)
+	if err != nil {
+		return fmt.Errorf("problem parsing to address '%s': %w", args.To, err)
+	}
+	to, err := ids.ToShortID(toBytes)
+	if err != nil {
+		return fmt.Errorf("problem parsing to address '%s': %w", args.To, err)
+	}
+
+	from := ids.ShortSet{}
+	for _, addrStr := range args.From {
+		addrBytes, err := service.vm.Parse(addrStr)
+		if err != nil {
+			return fmt.Errorf("problem parsing from address '%s': %w", addrStr, err)
+		}
+		addr, err := ids.ToShortID(addrBytes)
+		if err != nil {
+			return fmt.Errorf("problem parsing from address '%s': %w", addrStr, err)
+		}
+		from.Add(addr)
+	}
+
+	amounts := map[[32]byte]uint64{
+		assetID.Key(): uint64(args.Amount),
+	}
+	amountsWithFee, err := service.withFee(amounts)
+	if err != nil {
+		return err
+	}
+
+	amountsSpent, ins, relocks, owners, err := service.spendFrom(from, amountsWithFee)
+	if err != nil {
+		return err
+	}
+
+	outs := []*TransferableOutput{
+		&TransferableOutput{
+			Asset: Asset{
+				ID: assetID,
+			},
+			Out: &secp256k1fx.TransferOutput{
+				Amt:      uint64(args.Amount),
+				Locktime: 0,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{to},
+				},
+			},
+		},
+	}
+	outs = append(outs, relocks...)
+	for assetKey, amountSpent := range amountsSpent {
+		if amountSpent <= amountsWithFee[assetKey] {
+			continue
+		}
+		outs = append(outs, &TransferableOutput{
+			Asset: Asset{
+				ID: ids.NewID(assetKey),
+			},
+			Out: &secp256k1fx.TransferOutput{
+				Amt:          amountSpent - amountsWithFee[assetKey],
+				Locktime:     0,
+				OutputOwners: *owners[assetKey],
+			},
+		})
+	}
+	sortTransferableOutputs(outs, service.vm.codec)
+
+	tx := Tx{
+		UnsignedTx: &BaseTx{
+			NetID: service.vm.ctx.NetworkID,
+			BCID:  service.vm.ctx.ChainID,
+			Outs:  outs,
+			Ins:   ins,
+		},
+	}
+	for _, in := range ins {
+		numSigs := len(in.In.(*secp256k1fx.TransferInput).SigIndices)
+		tx.Creds = append(tx.Creds, &Credential{
+			Cred: &secp256k1fx.Credential{
+				Sigs: make([][crypto.SECP256K1RSigLen]byte, numSigs),
+			},
+		})
+	}
+
+	txBytes, err := service.vm.codec.Marshal(&tx)
+	if err != nil {
+		return fmt.Errorf("problem creating transaction: %w", err)
+	}
+	reply.Tx.Bytes = txBytes
+	return nil
+}
+
+// spendFrom returns inputs, that the addresses [from] can sign together, which
+// consume at least [amounts[assetID]] of every asset in [amounts]. Returns the
+// amount of every asset consumed by the inputs, the outputs that lock again
+// what's still locked of the consumed vesting outputs and the owners of the
+// first UTXO of every asset consumed. The amounts consumed don't include what's
+// locked again. The inputs are sorted.
+func (service *Service) spendFrom(from ids.ShortSet, amounts map[[32]byte]uint64) (map[[32]byte]uint64, []*TransferableInput, []*TransferableOutput, map[[32]byte]*secp256k1fx.OutputOwners, error) {
+	addrs := ids.Set{}
+	for _, addr := range from.List() {
+		addrs.Add(ids.NewID(hashing.ComputeHash256Array(addr.Bytes())))
+	}
+	utxos, err := service.vm.GetUTXOs(addrs)
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("problem retrieving UTXOs: %w", err)
+	}
+
+	amountsSpent := make(map[[32]byte]uint64, len(amounts))
+	owners := make(map[[32]byte]*secp256k1fx.OutputOwners, len(amounts))
+	time := service.vm.clock.Unix()
+
+	ins := []*TransferableInput{}
+	relocks := []*TransferableOutput{}
+	for _, utxo := range utxos {
+		assetID := utxo.AssetID()
+		assetKey := assetID.Key()
+		if amountsSpent[assetKey] >= amounts[assetKey] {
+			// Enough of this asset, or none of it, is needed
+			continue
+		}
+		var (
+			spenders *secp256k1fx.OutputOwners
+			amount   uint64
+		)
+		switch utxoOut := utxo.Out.(type) {
+		case *secp256k1fx.TransferOutput:
+			if time < utxoOut.Locktime {
+				continue
+			}
+			spenders, amount = &utxoOut.OutputOwners, utxoOut.Amt
+		case *secp256k1fx.VestingOutput:
+			if utxoOut.Unlocked(time) == 0 {
+				continue
+			}
+			spenders, amount = &utxoOut.OutputOwners, utxoOut.Amt
+		default:
+			continue
+		}
+		sigs := []uint32{}
+		for i := uint32(0); i < uint32(len(spenders.Addrs)) && uint32(len(sigs)) < spenders.Threshold; i++ {
+			if from.Contains(spenders.Addrs[i]) {
+				sigs = append(sigs, i)
+			}
+		}
+		if uint32(len(sigs)) != spenders.Threshold {
+			continue
+		}
+
+		spendable := amount
+		if relock := relock(assetID, utxo.Out, time); relock != nil {
+			spendable -= relock.Out.Amount()
+			relocks = append(relocks, relock)
+		}
+		spent, err := math.Add64(amountsSpent[assetKey], spendable)
+		if err != nil {
+			return nil, nil, nil, nil, errSpendOverflow
+		}
+		amountsSpent[assetKey] = spent
+		if _, exists := owners[assetKey]; !exists {
+			owners[assetKey] = spenders
+		}
+
+		ins = append(ins, &TransferableInput{
+			UTXOID: utxo.UTXOID,
+			Asset:  Asset{ID: assetID},
+			In: &secp256k1fx.TransferInput{
+				Amt: amount,
+				Input: secp256k1fx.Input{
+					SigIndices: sigs,
+				},
+			},
+		})
+	}
+
+	for assetKey, amount := range amounts {
+		if amountsSpent[assetKey] < amount {
+			return nil, nil, nil, nil, errInsufficientFunds
+		}
+	}
+
+	sortTransferableInputs(ins)
+	return amountsSpent, ins, relocks, owners, nil
+}
+
+// SignTxArgs are arguments for passing into SignTx requests
+type SignTxArgs struct {
+	Username string          `json:"username"`
+	Password string          `json:"password"`
+	Tx       formatting.CB58 `json:"tx"`
+}
+
+// SignTxReply defines the SignTx replies returned from the API
+type SignTxReply struct {
+	Tx formatting.CB58 `json:"tx"`
+}
+
+// SignTx adds to [args.Tx] the signatures of the user [args.Username]. Every
+// signature an input requires from an address the user controls is put in the
+// slot of the input's credential that corresponds to that address. The other
+// signatures are left as they are, so a transaction can be passed from signer
+// to signer until it's fully signed, and then issued with IssueTx.
+func (service *Service) SignTx(r *http.Request, args *SignTxArgs, reply *SignTxReply) error {
+	service.vm.ctx.Log.Verbo("SignTx called with username: %s", args.Username)
+
+	kc, err := service.keychain(args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+
+	tx := Tx{}
+	if err := service.vm.codec.Unmarshal(args.Tx.Bytes, &tx); err != nil {
+		return fmt.Errorf("problem parsing transaction: %w", err)
+	}
+
+	unsignedBytes, err := service.vm.codec.Marshal(&tx.UnsignedTx)
+	if err != nil {
+		return fmt.Errorf("problem creating transaction: %w", err)
+	}
+	hash := hashing.ComputeHash256(unsignedBytes)
+
+	utxoIDs, ins := txInputs(tx.UnsignedTx)
+	for len(tx.Creds) < len(ins) {
+		tx.Creds = append(tx.Creds, &Credential{Cred: &secp256k1fx.Credential{}})
+	}
+
+	numSigned := 0
+	for i, in := range ins {
+		var sigIndices []uint32
+		switch in := in.(type) {
+		case *secp256k1fx.TransferInput:
+			sigIndices = in.SigIndices
+		case *secp256k1fx.MintInput:
+			sigIndices = in.SigIndices
+		default:
+			continue
+		}
+
+		utxoID := utxoIDs[i]
+		if utxoID.Symbolic() {
+			// UTXOs imported from another chain aren't stored in this chain
+			continue
+		}
+		utxo, err := service.utxo(utxoID)
+		if err != nil {
+			return err
+		}
+		var owners *secp256k1fx.OutputOwners
+		switch out := utxo.Out.(type) {
+		case *secp256k1fx.TransferOutput:
+			owners = &out.OutputOwners
+		case *secp256k1fx.MintOutput:
+			owners = &out.OutputOwners
+		case *secp256k1fx.VestingOutput:
+			owners = &out.OutputOwners
+		default:
+			return errUnknownOutputType
+		}
+
+		cred, ok := tx.Creds[i].Cred.(*secp256k1fx.Credential)
+		if !ok {
+			return errUnknownCredentialType
+		}
+		if len(cred.Sigs) != len(sigIndices) {
+			cred.Sigs = make([][crypto.SECP256K1RSigLen]byte, len(sigIndices))
+		}
+
+		for j, sigIndex := range sigIndices {
+			if sigIndex >= uint32(len(owners.Addrs)) {
+				return errInvalidUTXO
+			}
+			key, exists := kc.Get(owners.Addrs[sigIndex])
+			if !exists {
+				continue
+			}
+			sig, err := key.SignHash(hash)
+			if err != nil {
+				return fmt.Errorf("problem signing transaction: %w", err)
+			}
+			copy(cred.Sigs[j][:], sig)
+			numSigned++
+		}
+	}
+	if numSigned == 0 {
+		return errNoRequiredKeys
+	}
+
+	txBytes, err := service.vm.codec.Marshal(&tx)
+	if err != nil {
+		return fmt.Errorf("problem creating transaction: %w", err)
+	}
+	reply.Tx.Bytes = txBytes
+	return nil
+}
+
+// utxo returns the UTXO [utxoID] refers to, which may have been produced by a
+// transaction that's still processing
+func (service *Service) utxo(utxoID *UTXOID) (*UTXO, error) {
+	if utxo, err := service.vm.state.UTXO(utxoID.InputID()); err == nil {
+		return utxo, nil
+	}
+
+	inputTxID, utxoIndex := utxoID.InputSource()
+	utx := UniqueTx{
+		vm:   service.vm,
+		txID: inputTxID,
+	}
+	if !utx.Status().Fetched() {
+		return nil, errUnknownUTXO
+	}
+	utxos := utx.UTXOs()
+	if uint32(len(utxos)) <= utxoIndex {
+		return nil, errInvalidUTXO
+	}
+	return utxos[int(utxoIndex)], nil
+}
+
+// txInputs returns the UTXOs [tx] consumes and the inputs that consume them,
+// in the order of the tx's credentials
+func txInputs(tx UnsignedTx) ([]*UTXOID, []verify.Verifiable) {
+	ins := []verify.Verifiable{}
+	for _, in := range tx.Inputs() {
+		ins = append(ins, in.In)
+	}
+	switch tx := tx.(type) {
+	case *OperationTx:
+		for _, op := range tx.Ops {
+			for _, in := range op.Ins {
+				ins = append(ins, in.In)
+			}
+		}
+	case *ImportTx:
+		for _, in := range tx.ImportIns {
+			ins = append(ins, in.In)
+		}
+	}
+	return tx.InputUTXOs(), ins
+}
+
+// missingSignatures returns true if a credential of [tx] has a signature that
+// hasn't been filled in yet
+func missingSignatures(tx *Tx) bool {
+	for _, cred := range tx.Creds {
+		cred, ok := cred.Cred.(*secp256k1fx.Credential)
+		if !ok {
+			continue
+		}
+		for _, sig := range cred.Sigs {
+			if sig == [crypto.SECP256K1RSigLen]byte{} {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CreateNFTAssetArgs are arguments for passing into CreateNFTAsset requests
+type CreateNFTAssetArgs struct {
+	Username   string   `json:"username"`
+	Password   string   `json:"password"`
+	Name       string   `json:"name"`
+	Symbol     string   `json:"symbol"`
+	MinterSets []Owners `json:"minterSets"`
+}
+
+// CreateNFTAssetReply defines the CreateNFTAsset replies returned from the API
+type CreateNFTAssetReply struct {
+	AssetID ids.ID `json:"assetID"`
+}
+
+// CreateNFTAsset returns the ID of a newly created asset of NFTs. The NFTs of
+// the i-th minter set are of the group i.
+func (service *Service) CreateNFTAsset(r *http.Request, args *CreateNFTAssetArgs, reply *CreateNFTAssetReply) error {
+	service.vm.ctx.Log.Verbo("CreateNFTAsset called with name: %s symbol: %s number of minters: %d",
+		args.Name,
+		args.Symbol,
+		len(args.MinterSets),
+	)
+
+	if len(args.MinterSets) == 0 {
+		return errNoMinters
+	}
+
+	fxIndex, err := service.vm.fxIndex(nftfx.ID)
+	if err != nil {
+		return err
+	}
+
+	ins, outs, keys, err := service.payFee(args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+
+	initialState := &InitialState{
+		FxID: uint32(fxIndex),
+		Outs: []verify.Verifiable{},
+	}
+
+	tx := &Tx{UnsignedTx: &CreateAssetTx{
+		BaseTx: BaseTx{
+			NetID: service.vm.ctx.NetworkID,
+			BCID:  service.vm.ctx.ChainID,
+			Outs:  outs,
+			Ins:   ins,
+		},
+		Name:   args.Name,
+		Symbol: args.Symbol,
+		States: []*InitialState{
+			initialState,
+		},
+	}}
+
+	for i, owner := range args.MinterSets {
+		minter := &nftfx.MintOutput{
+			GroupID: uint32(i),
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: uint32(owner.Threshold),
+			},
+		}
+		for _, address := range owner.Minters {
+			addrBytes, err := service.vm.Parse(address)
+			if err != nil {
+				return err
+			}
+			addr, err := ids.ToShortID(addrBytes)
+			if err != nil {
+				return err
+			}
+			minter.Addrs = append(minter.Addrs, addr)
+		}
+		ids.SortShortIDs(minter.Addrs)
+		initialState.Outs = append(initialState.Outs, minter)
+	}
+	initialState.Sort(service.vm.codec)
+
+	assetID, err := service.signAndIssue(tx, keys)
+	if err != nil {
+		return err
+	}
+
+	reply.AssetID = assetID
+	return nil
+}
+
+// MintNFTArgs are arguments for passing into MintNFT requests
+type MintNFTArgs struct {
+	Username string          `json:"username"`
+	Password string          `json:"password"`
+	AssetID  string          `json:"assetID"`
+	GroupID  json.Uint32     `json:"groupID"`
+	Payload  formatting.CB58 `json:"payload"`
+	To       string          `json:"to"`
+}
+
+// MintNFTReply defines the MintNFT replies returned from the API
+type MintNFTReply struct {
+	TxID ids.ID `json:"txID"`
+}
+
+// MintNFT mints an NFT of the group [args.GroupID] of the asset [args.AssetID],
+// with the payload [args.Payload], and sends it to [args.To]. The user must be
+// able to sign for the group's minters on their own.
+func (service *Service) MintNFT(r *http.Request, args *MintNFTArgs, reply *MintNFTReply) error {
+	service.vm.ctx.Log.Verbo("MintNFT called with username: %s", args.Username)
+
+	if len(args.Payload.Bytes) > nftfx.MaxPayloadSize {
+		return fmt.Errorf("payload can't be larger than %d bytes", nftfx.MaxPayloadSize)
+	}
+
+	assetID, to, err := service.parseNFTArgs(args.AssetID, args.To)
+	if err != nil {
+		return err
+	}
+
+	kc, err := service.keychain(args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+	utxos, err := service.keychainUTXOs(kc)
+	if err != nil {
+		return err
+	}
+
+	for _, utxo := range utxos {
+		out, ok := utxo.Out.(*nftfx.MintOutput)
+		if !ok || !utxo.AssetID().Equals(assetID) || out.GroupID != uint32(args.GroupID) {
+			continue
+		}
+		sigIndices, keys, able := kc.Match(&out.OutputOwners)
+		if !able {
+			continue
+		}
+
+		outs := []*OperableOutput{
+			&OperableOutput{
+				Out: &nftfx.MintOutput{
+					GroupID:      out.GroupID,
+					OutputOwners: out.OutputOwners,
+				},
+			},
+			&OperableOutput{
+				Out: &nftfx.TransferOutput{
+					GroupID: out.GroupID,
+					Payload: args.Payload.Bytes,
+					OutputOwners: secp256k1fx.OutputOwners{
+						Threshold: 1,
+						Addrs:     []ids.ShortID{to},
+					},
+				},
+			},
+		}
+		txID, err := service.issueNFTOperation(args.Username, args.Password, &Operation{
+			Asset: Asset{ID: assetID},
+			Ins: []*OperableInput{
+				&OperableInput{
+					UTXOID: utxo.UTXOID,
+					In: &nftfx.MintInput{
+						Input: secp256k1fx.Input{SigIndices: sigIndices},
+					},
+				},
+			},
+			Outs: outs,
+		}, keys)
+		if err != nil {
+			return err
+		}
+
+		reply.TxID = txID
+		return nil
+	}
+	return errCantMintNFT
+}
+
+// SendNFTArgs are arguments for passing into SendNFT requests
+type SendNFTArgs struct {
+	Username string      `json:"username"`
+	Password string      `json:"password"`
+	AssetID  string      `json:"assetID"`
+	GroupID  json.Uint32 `json:"groupID"`
+	To       string      `json:"to"`
+}
+
+// SendNFTReply defines the SendNFT replies returned from the API
+type SendNFTReply struct {
+	TxID ids.ID `json:"txID"`
+}
+
+// SendNFT sends an NFT of the group [args.GroupID] of the asset [args.AssetID]
+// that the user owns to [args.To]
+func (service *Service) SendNFT(r *http.Request, args *SendNFTArgs, reply *SendNFTReply) error {
+	service.vm.ctx.Log.Verbo("SendNFT called with username: %s", args.Username)
+
+	assetID, to, err := service.parseNFTArgs(args.AssetID, args.To)
+	if err != nil {
+		return err
+	}
+
+	kc, err := service.keychain(args.Username, args.Password)
+	if err != nil {
+		return err
+	}
+	utxos, err := service.keychainUTXOs(kc)
+	if err != nil {
+		return err
+	}
+
+	for _, utxo := range utxos {
+		out, ok := utxo.Out.(*nftfx.TransferOutput)
+		if !ok || !utxo.AssetID().Equals(assetID) || out.GroupID != uint32(args.GroupID) {
+			continue
+		}
+		sigIndices, keys, able := kc.Match(&out.OutputOwners)
+		if !able {
+			continue
+		}
+
+		txID, err := service.issueNFTOperation(args.Username, args.Password, &Operation{
+			Asset: Asset{ID: assetID},
+			Ins: []*OperableInput{
+				&OperableInput{
+					UTXOID: utxo.UTXOID,
+					In: &nftfx.TransferInput{
+						Input: secp256k1fx.Input{SigIndices: sigIndices},
+					},
+				},
+			},
+			Outs: []*OperableOutput{
+				&OperableOutput{
+					Out: &nftfx.TransferOutput{
+						GroupID: out.GroupID,
+						Payload: out.Payload,
+						OutputOwners: secp256k1fx.OutputOwners{
+							Threshold: 1,
+							Addrs:     []ids.ShortID{to},
+						},
+					},
+				},
+			},
+		}, keys)
+		if err != nil {
+			return err
+		}
+
+		reply.TxID = txID
+		return nil
+	}
+	return errNoNFT
+}
+
+// parseNFTArgs returns the asset [assetStr] refers to and the address [toStr]
+func (service *Service) parseNFTArgs(assetStr, toStr string) (ids.ID, ids.ShortID, error) {
+	assetID, err := service.vm.Lookup(assetStr)
+	if err != nil {
+		assetID, err = ids.FromString(assetStr)
+		if err != nil {
+			return ids.ID{}, ids.ShortID{}, fmt.Errorf("asset '%s' not found", assetStr)
+		}
+	}
+
+	toBytes, err := service.vm.Parse(toStr)
+	if err != nil {
+		return ids.ID{}, ids.ShortID{}, fmt.Errorf("problem parsing to address '%s': %w", toStr, err)
+	}
+	to, err := ids.ToShortID(toBytes)
+	if err != nil {
+		return ids.ID{}, ids.ShortID{}, fmt.Errorf("problem parsing to address '%s': %w", toStr, err)
+	}
+	return assetID, to, nil
+}
+
+// keychainUTXOs returns the UTXOs that reference the addresses of [kc]
+func (service *Service) keychainUTXOs(kc *keychain) ([]*UTXO, error) {
+	addrs := ids.Set{}
+	for _, addr := range kc.Addresses().List() {
+		addrs.Add(ids.NewID(hashing.ComputeHash256Array(addr.Bytes())))
+	}
+	utxos, err := service.vm.GetUTXOs(addrs)
+	if err != nil {
+		return nil, fmt.Errorf("problem retrieving user's UTXOs: %w", err)
+	}
+	return utxos, nil
+}
+
+// issueNFTOperation issues a tx that performs [op], whose input [keys] sign.
+// The user [username] pays the tx fee, if there is one.
+func (service *Service) issueNFTOperation(username, password string, op *Operation, keys []signingKey) (ids.ID, error) {
+	feeIns, feeOuts, feeKeys, err := service.payFee(username, password)
+	if err != nil {
+		return ids.ID{}, err
+	}
+	sortOperableOutputs(op.Outs, service.vm.codec)
+
+	tx := &Tx{UnsignedTx: &OperationTx{
+		BaseTx: BaseTx{
+			NetID: service.vm.ctx.NetworkID,
+			BCID:  service.vm.ctx.ChainID,
+			Outs:  feeOuts,
+			Ins:   feeIns,
+		},
+		Ops: []*Operation{op},
+	}}
+
+	// The operation's input comes after the inputs paying the tx fee. It's
+	// signed the same way, but its credential is the nft fx's.
+	if err := service.sign(tx, append(feeKeys, keys)); err != nil {
+		return ids.ID{}, err
+	}
+	opCred := tx.Creds[len(tx.Creds)-1]
+	opCred.Cred = &nftfx.Credential{
+		Credential: *opCred.Cred.(*secp256k1fx.Credential),
+	}
+	return service.issue(tx)
+}
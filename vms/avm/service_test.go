@@ -4,12 +4,24 @@
 package avm
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"testing"
+	"time"
 
+	"github.com/ava-labs/gecko/api/signer"
+	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/database/memdb"
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/vms/nftfx"
 	"github.com/ava-labs/gecko/vms/secp256k1fx"
+
+	cjson "github.com/ava-labs/gecko/utils/json"
 )
 
 func TestGetAssetDescription(t *testing.T) {
@@ -186,3 +198,732 @@ func TestCreateVariableCapAsset(t *testing.T) {
 		t.Fatalf("Wrong assetID returned from CreateFixedCapAsset %s", reply.AssetID)
 	}
 }
+
+func TestGetTxFee(t *testing.T) {
+	vm, _, genesisTx := AtomicVM(t)
+	defer func() {
+		vm.ctx.Lock.Lock()
+		vm.Shutdown()
+		vm.ctx.Lock.Unlock()
+	}()
+	vm.txFee = 10
+
+	s := Service{vm: vm}
+
+	reply := GetTxFeeReply{}
+	if err := s.GetTxFee(nil, &GetTxFeeArgs{}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if reply.TxFee != 10 {
+		t.Fatalf("Wrong tx fee returned. Expected: %d ; Returned: %d", 10, reply.TxFee)
+	}
+	if !reply.AssetID.Equals(genesisTx.ID()) {
+		t.Fatalf("Wrong tx fee asset returned. Expected: %s ; Returned: %s", genesisTx.ID(), reply.AssetID)
+	}
+}
+
+func TestGetAddressTxs(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm := &VM{indexTxs: true}
+	err := vm.Initialize(
+		ctx,
+		memdb.New(),
+		genesisBytes,
+		make(chan common.Message, 1),
+		[]*common.Fx{&common.Fx{
+			ID: ids.Empty,
+			Fx: &secp256k1fx.Fx{},
+		}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer vm.Shutdown()
+	vm.batchTimeout = 0
+
+	genesisTx := GetFirstTxFromGenesisTest(genesisBytes, t)
+	key := keys[0]
+	to := keys[2].PublicKey().Address()
+
+	s := Service{vm: vm}
+	newTx := &Tx{UnsignedTx: &BaseTx{
+		NetID: networkID,
+		BCID:  chainID,
+		Ins: []*TransferableInput{&TransferableInput{
+			UTXOID: UTXOID{
+				TxID:        genesisTx.ID(),
+				OutputIndex: 1,
+			},
+			Asset: Asset{ID: genesisTx.ID()},
+			In: &secp256k1fx.TransferInput{
+				Amt:   50000,
+				Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+			},
+		}},
+		Outs: []*TransferableOutput{&TransferableOutput{
+			Asset: Asset{ID: genesisTx.ID()},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: 50000,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{to},
+				},
+			},
+		}},
+	}}
+	txID, err := s.signAndIssue(newTx, [][]signingKey{{key}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	txs := vm.PendingTxs()
+	if len(txs) != 1 {
+		t.Fatalf("Should have issued the tx")
+	}
+	txs[0].Accept()
+
+	// The indices are committed along with the tx
+	vm.db.Abort()
+
+	// The sender spent a UTXO in [newTx]
+	fromReply := &GetIndexedTxsReply{}
+	if err := s.GetAddressTxs(nil, &GetAddressTxsArgs{Address: vm.Format(key.PublicKey().Address().Bytes())}, fromReply); err != nil {
+		t.Fatal(err)
+	}
+	if numTxs := len(fromReply.TxIDs); numTxs == 0 || !fromReply.TxIDs[numTxs-1].Equals(txID) {
+		t.Fatalf("Should have indexed %s for the sender", txID)
+	}
+
+	// The receiver was a minter of a genesis asset, and received [newTx]
+	args := &GetAddressTxsArgs{
+		Address:  vm.Format(to.Bytes()),
+		PageSize: 1,
+	}
+	reply := &GetIndexedTxsReply{}
+	if err := s.GetAddressTxs(nil, args, reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.TxIDs) != 1 || reply.Cursor != 1 {
+		t.Fatalf("Should have returned the genesis tx in the first page")
+	}
+
+	args.Cursor = reply.Cursor
+	if err := s.GetAddressTxs(nil, args, reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.TxIDs) != 1 || !reply.TxIDs[0].Equals(txID) || reply.Cursor != 2 {
+		t.Fatalf("Should have returned %s in the second page", txID)
+	}
+
+	args.Cursor = reply.Cursor
+	if err := s.GetAddressTxs(nil, args, reply); err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.TxIDs) != 0 || reply.Cursor != 2 {
+		t.Fatalf("Should have returned an empty last page")
+	}
+
+	// The sender's spent UTXO was created by the genesis tx
+	assetReply := &GetIndexedTxsReply{}
+	if err := s.GetAssetTxs(nil, &GetAssetTxsArgs{AssetID: genesisTx.ID().String()}, assetReply); err != nil {
+		t.Fatal(err)
+	}
+	if len(assetReply.TxIDs) != 2 || !assetReply.TxIDs[0].Equals(genesisTx.ID()) || !assetReply.TxIDs[1].Equals(txID) {
+		t.Fatalf("Wrong txs returned. Expected: [%s %s] ; Returned: %s", genesisTx.ID(), txID, assetReply.TxIDs)
+	}
+
+	vm.indexTxs = false
+	if err := s.GetAddressTxs(nil, args, reply); err != errTxIndexDisabled {
+		t.Fatalf("Should have errored with %s but errored with %v", errTxIndexDisabled, err)
+	}
+}
+
+func TestGetTx(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		ctx.Lock.Unlock()
+	}()
+
+	genesisTx := GetFirstTxFromGenesisTest(BuildGenesisTest(t), t)
+
+	s := Service{vm: vm}
+	reply := GetTxReply{}
+	if err := s.GetTx(nil, &GetTxArgs{TxID: genesisTx.ID()}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(reply.Tx.Bytes, genesisTx.Bytes()) {
+		t.Fatalf("Wrong tx bytes returned")
+	}
+	if !reply.JSON.ID().Equals(genesisTx.ID()) {
+		t.Fatalf("Wrong tx returned. Expected: %s ; Returned: %s", genesisTx.ID(), reply.JSON.ID())
+	}
+	if reply.Status != choices.Accepted {
+		t.Fatalf("Wrong status. Expected: %s ; Returned: %s", choices.Accepted, reply.Status)
+	}
+	if _, err := json.Marshal(&reply); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.GetTx(nil, &GetTxArgs{TxID: ids.Empty}, &reply); err != errUnknownTx {
+		t.Fatalf("Should have errored with %s but errored with %v", errUnknownTx, err)
+	}
+}
+
+func TestGetUTXOsPagination(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		ctx.Lock.Unlock()
+	}()
+
+	s := Service{vm: vm}
+	addr := vm.Format(keys[0].PublicKey().Address().Bytes())
+	args := &GetUTXOsArgs{
+		Addresses: []string{addr},
+		Limit:     3,
+	}
+
+	// The address is referenced by 7 genesis utxos
+	fetched := map[string]struct{}{}
+	for _, expected := range []int{3, 3, 1} {
+		reply := &GetUTXOsReply{}
+		if err := s.GetUTXOs(nil, args, reply); err != nil {
+			t.Fatal(err)
+		}
+		if int(reply.NumFetched) != expected || len(reply.UTXOs) != expected {
+			t.Fatalf("Wrong number of utxos. Expected: %d ; Returned: %d", expected, reply.NumFetched)
+		}
+		if reply.EndIndex.Address != addr {
+			t.Fatalf("Wrong end address. Expected: %s ; Returned: %s", addr, reply.EndIndex.Address)
+		}
+		for _, utxo := range reply.UTXOs {
+			fetched[utxo.String()] = struct{}{}
+		}
+		args.StartIndex = reply.EndIndex
+	}
+	if len(fetched) != 7 {
+		t.Fatalf("Should have fetched 7 distinct utxos, but fetched %d", len(fetched))
+	}
+
+	args.StartIndex.Address = vm.Format(keys[1].PublicKey().Address().Bytes())
+	if err := s.GetUTXOs(nil, args, &GetUTXOsReply{}); err != errUnknownStartAddress {
+		t.Fatalf("Should have errored with %s but errored with %v", errUnknownStartAddress, err)
+	}
+}
+
+// testKeystore gives every user their own in-memory database
+type testKeystore map[string]database.Database
+
+func (ks testKeystore) GetDatabase(username, _ string) (database.Database, error) {
+	db, exists := ks[username]
+	if !exists {
+		db = memdb.New()
+		ks[username] = db
+	}
+	return db, nil
+}
+
+func TestMultisigSend(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		ctx.Keystore = nil
+		ctx.Lock.Unlock()
+	}()
+	ctx.Keystore = testKeystore{}
+
+	genesisTx := GetFirstTxFromGenesisTest(BuildGenesisTest(t), t)
+	assetID := genesisTx.ID()
+
+	// A UTXO that any 2 of keys[0], keys[1] and keys[2] can spend
+	owners := []ids.ShortID{
+		keys[0].PublicKey().Address(),
+		keys[1].PublicKey().Address(),
+		keys[2].PublicKey().Address(),
+	}
+	ids.SortShortIDs(owners)
+	utxo := &UTXO{
+		UTXOID: UTXOID{TxID: ids.NewID([32]byte{1})},
+		Asset:  Asset{ID: assetID},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: 1000,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 2,
+				Addrs:     owners,
+			},
+		},
+	}
+	if err := vm.state.FundUTXO(utxo); err != nil {
+		t.Fatal(err)
+	}
+
+	s := Service{vm: vm}
+	createReply := CreateSendTxReply{}
+	err := s.CreateSendTx(nil, &CreateSendTxArgs{
+		From: []string{
+			vm.Format(keys[1].PublicKey().Address().Bytes()),
+			vm.Format(keys[2].PublicKey().Address().Bytes()),
+		},
+		Amount:  600,
+		AssetID: assetID.String(),
+		To:      vm.Format(keys[0].PublicKey().Address().Bytes()),
+	}, &createReply)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issueReply := IssueTxReply{}
+	if err := s.IssueTx(nil, &IssueTxArgs{Tx: createReply.Tx}, &issueReply); err != errMissingSignatures {
+		t.Fatalf("Should have errored with %s but errored with %v", errMissingSignatures, err)
+	}
+
+	for i, key := range keys[:3] {
+		err := s.ImportKey(nil, &ImportKeyArgs{
+			Username:   fmt.Sprintf("user%d", i),
+			PrivateKey: formatting.CB58{Bytes: key.Bytes()},
+		}, &ImportKeyReply{})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// keys[0] owns the UTXO, but the tx was built to be signed by the others
+	if err := s.SignTx(nil, &SignTxArgs{Username: "user0", Tx: createReply.Tx}, &SignTxReply{}); err != errNoRequiredKeys {
+		t.Fatalf("Should have errored with %s but errored with %v", errNoRequiredKeys, err)
+	}
+
+	signReply := SignTxReply{}
+	if err := s.SignTx(nil, &SignTxArgs{Username: "user1", Tx: createReply.Tx}, &signReply); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.IssueTx(nil, &IssueTxArgs{Tx: signReply.Tx}, &issueReply); err != errMissingSignatures {
+		t.Fatalf("Should have errored with %s but errored with %v", errMissingSignatures, err)
+	}
+
+	if err := s.SignTx(nil, &SignTxArgs{Username: "user2", Tx: signReply.Tx}, &signReply); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.IssueTx(nil, &IssueTxArgs{Tx: signReply.Tx}, &issueReply); err != nil {
+		t.Fatal(err)
+	}
+
+	tx := Tx{}
+	if err := vm.codec.Unmarshal(signReply.Tx.Bytes, &tx); err != nil {
+		t.Fatal(err)
+	}
+	outs := tx.UnsignedTx.Outputs()
+	if len(outs) != 2 {
+		t.Fatalf("Wrong number of outputs. Expected: 2 ; Returned: %d", len(outs))
+	}
+	for _, out := range outs {
+		out := out.Out.(*secp256k1fx.TransferOutput)
+		switch out.Amt {
+		case 600:
+			if len(out.Addrs) != 1 || !out.Addrs[0].Equals(keys[0].PublicKey().Address()) {
+				t.Fatalf("Should have sent the funds to keys[0]")
+			}
+		case 400:
+			if !out.OutputOwners.Equals(&utxo.Out.(*secp256k1fx.TransferOutput).OutputOwners) {
+				t.Fatalf("Should have sent the change back to the owners of the spent UTXO")
+			}
+		default:
+			t.Fatalf("Unexpected output amount %d", out.Amt)
+		}
+	}
+}
+
+func TestSendVesting(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		ctx.Keystore = nil
+		ctx.Lock.Unlock()
+	}()
+	ctx.Keystore = testKeystore{}
+
+	now := time.Unix(1000000, 0)
+	vm.clock.Set(now)
+
+	genesisTx := GetFirstTxFromGenesisTest(BuildGenesisTest(t), t)
+	assetID := genesisTx.ID()
+
+	// Half of the vesting output is unlocked
+	addr := keys[1].PublicKey().Address()
+	vesting := &secp256k1fx.VestingOutput{
+		Amt:          1000,
+		VestingStart: uint64(now.Unix()) - 50,
+		VestingEnd:   uint64(now.Unix()) + 50,
+		OutputOwners: secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs:     []ids.ShortID{addr},
+		},
+	}
+	utxo := &UTXO{
+		UTXOID: UTXOID{TxID: ids.NewID([32]byte{1})},
+		Asset:  Asset{ID: assetID},
+		Out:    vesting,
+	}
+	if err := vm.state.FundUTXO(utxo); err != nil {
+		t.Fatal(err)
+	}
+
+	s := Service{vm: vm}
+	balanceReply := GetBalanceReply{}
+	err := s.GetBalance(nil, &GetBalanceArgs{
+		Address: vm.Format(addr.Bytes()),
+		AssetID: assetID.String(),
+	}, &balanceReply)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balanceReply.Balance != 1000 || balanceReply.Unlocked != 500 || balanceReply.Locked != 500 {
+		t.Fatalf("Wrong balance. Expected: 1000 (500 unlocked) ; Returned: %d (%d unlocked)", balanceReply.Balance, balanceReply.Unlocked)
+	}
+
+	err = s.ImportKey(nil, &ImportKeyArgs{
+		Username:   "user",
+		PrivateKey: formatting.CB58{Bytes: keys[1].Bytes()},
+	}, &ImportKeyReply{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sendArgs := &SendArgs{
+		Username: "user",
+		Amount:   501,
+		AssetID:  assetID.String(),
+		To:       vm.Format(keys[2].PublicKey().Address().Bytes()),
+	}
+	if err := s.Send(nil, sendArgs, &SendReply{}); err != errInsufficientFunds {
+		t.Fatalf("Should have errored with %s but errored with %v", errInsufficientFunds, err)
+	}
+
+	sendArgs.Amount = 300
+	sendArgs.Locktime = cjson.Uint64(now.Unix() + 100)
+	sendReply := SendReply{}
+	if err := s.Send(nil, sendArgs, &sendReply); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := vm.state.Tx(sendReply.TxID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outs := tx.UnsignedTx.Outputs()
+	if len(outs) != 3 {
+		t.Fatalf("Wrong number of outputs. Expected: 3 ; Returned: %d", len(outs))
+	}
+	for _, out := range outs {
+		switch out := out.Out.(type) {
+		case *secp256k1fx.TransferOutput:
+			switch out.Amt {
+			case 300:
+				if out.Locktime != uint64(sendArgs.Locktime) {
+					t.Fatalf("Should have locked the sent funds")
+				}
+			case 200:
+				if out.Locktime != 0 {
+					t.Fatalf("Shouldn't have locked the change")
+				}
+			default:
+				t.Fatalf("Unexpected output amount %d", out.Amt)
+			}
+		case *secp256k1fx.VestingOutput:
+			if out.Amt != 500 || !vesting.Relocks(out, uint64(now.Unix())) {
+				t.Fatalf("Should have locked again what's still locked of the vesting output")
+			}
+		default:
+			t.Fatalf("Unexpected output type %T", out)
+		}
+	}
+}
+
+func TestNFT(t *testing.T) {
+	genesisBytes := BuildGenesisTest(t)
+
+	ctx.Lock.Lock()
+	defer func() {
+		ctx.Keystore = nil
+		ctx.Lock.Unlock()
+	}()
+	ctx.Keystore = testKeystore{}
+
+	vm := &VM{}
+	err := vm.Initialize(
+		ctx,
+		memdb.New(),
+		genesisBytes,
+		make(chan common.Message, 1),
+		[]*common.Fx{
+			&common.Fx{
+				ID: ids.Empty,
+				Fx: &secp256k1fx.Fx{},
+			},
+			&common.Fx{
+				ID: nftfx.ID,
+				Fx: &nftfx.Fx{},
+			},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.batchTimeout = 0
+	defer vm.Shutdown()
+
+	s := Service{vm: vm}
+	for i, key := range keys[:2] {
+		err := s.ImportKey(nil, &ImportKeyArgs{
+			Username:   fmt.Sprintf("user%d", i),
+			PrivateKey: formatting.CB58{Bytes: key.Bytes()},
+		}, &ImportKeyReply{})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	accept := func() {
+		txs := vm.PendingTxs()
+		if len(txs) != 1 {
+			t.Fatalf("Should have issued the tx")
+		}
+		txs[0].Accept()
+	}
+
+	createReply := CreateNFTAssetReply{}
+	err = s.CreateNFTAsset(nil, &CreateNFTAssetArgs{
+		Username: "user0",
+		Name:     "collectibles",
+		Symbol:   "COL",
+		MinterSets: []Owners{
+			Owners{
+				Threshold: 1,
+				Minters:   []string{vm.Format(keys[0].PublicKey().Address().Bytes())},
+			},
+			Owners{
+				Threshold: 1,
+				Minters:   []string{vm.Format(keys[1].PublicKey().Address().Bytes())},
+			},
+		},
+	}, &createReply)
+	if err != nil {
+		t.Fatal(err)
+	}
+	accept()
+	assetID := createReply.AssetID.String()
+
+	// keys[0] can only mint the NFTs of group 0
+	mintArgs := &MintNFTArgs{
+		Username: "user0",
+		AssetID:  assetID,
+		GroupID:  1,
+		Payload:  formatting.CB58{Bytes: []byte{'h', 'i'}},
+		To:       vm.Format(keys[1].PublicKey().Address().Bytes()),
+	}
+	if err := s.MintNFT(nil, mintArgs, &MintNFTReply{}); err != errCantMintNFT {
+		t.Fatalf("Should have errored with %s but errored with %v", errCantMintNFT, err)
+	}
+	mintArgs.GroupID = 0
+	if err := s.MintNFT(nil, mintArgs, &MintNFTReply{}); err != nil {
+		t.Fatal(err)
+	}
+	accept()
+
+	sendArgs := &SendNFTArgs{
+		Username: "user1",
+		AssetID:  assetID,
+		GroupID:  0,
+		To:       vm.Format(keys[2].PublicKey().Address().Bytes()),
+	}
+	sendReply := SendNFTReply{}
+	if err := s.SendNFT(nil, sendArgs, &sendReply); err != nil {
+		t.Fatal(err)
+	}
+	accept()
+
+	tx, err := vm.state.Tx(sendReply.TxID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opTx, ok := tx.UnsignedTx.(*OperationTx)
+	if !ok || len(opTx.Ops) != 1 || len(opTx.Ops[0].Outs) != 1 {
+		t.Fatalf("Should have sent the NFT with an operation")
+	}
+	out, ok := opTx.Ops[0].Outs[0].Out.(*nftfx.TransferOutput)
+	switch {
+	case !ok:
+		t.Fatalf("Should have created an NFT")
+	case out.GroupID != 0 || !bytes.Equal(out.Payload, mintArgs.Payload.Bytes):
+		t.Fatalf("Should have kept the group and the payload of the NFT")
+	case len(out.Addrs) != 1 || !out.Addrs[0].Equals(keys[2].PublicKey().Address()):
+		t.Fatalf("Should have sent the NFT to keys[2]")
+	}
+
+	// keys[1] doesn't own the NFT anymore
+	if err := s.SendNFT(nil, sendArgs, &SendNFTReply{}); err != errNoNFT {
+		t.Fatalf("Should have errored with %s but errored with %v", errNoNFT, err)
+	}
+}
+
+func TestBurn(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		ctx.Keystore = nil
+		ctx.Lock.Unlock()
+	}()
+	ctx.Keystore = testKeystore{}
+
+	genesisTx := GetFirstTxFromGenesisTest(BuildGenesisTest(t), t)
+	assetID := genesisTx.ID()
+	addr := vm.Format(keys[0].PublicKey().Address().Bytes())
+
+	s := Service{vm: vm}
+	err := s.ImportKey(nil, &ImportKeyArgs{
+		Username:   "user",
+		PrivateKey: formatting.CB58{Bytes: keys[0].Bytes()},
+	}, &ImportKeyReply{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	accept := func() {
+		txs := vm.PendingTxs()
+		if len(txs) != 1 {
+			t.Fatalf("Should have issued the tx")
+		}
+		txs[0].Accept()
+	}
+	checkSupply := func(assetID string, minted, burned uint64) {
+		reply := GetAssetSupplyReply{}
+		if err := s.GetAssetSupply(nil, &GetAssetSupplyArgs{AssetID: assetID}, &reply); err != nil {
+			t.Fatal(err)
+		}
+		if uint64(reply.Minted) != minted || uint64(reply.Burned) != burned || uint64(reply.Circulating) != minted-burned {
+			t.Fatalf("Wrong supply. Expected: %d minted, %d burned ; Returned: %d minted, %d burned, %d circulating",
+				minted, burned, reply.Minted, reply.Burned, reply.Circulating)
+		}
+	}
+
+	checkSupply(assetID.String(), 300000, 0)
+
+	burnArgs := &BurnArgs{
+		Username: "user",
+		Amount:   120000,
+		AssetID:  assetID.String(),
+	}
+	if err := s.Burn(nil, burnArgs, &BurnReply{}); err != nil {
+		t.Fatal(err)
+	}
+	accept()
+	checkSupply(assetID.String(), 300000, 120000)
+
+	balanceReply := GetBalanceReply{}
+	if err := s.GetBalance(nil, &GetBalanceArgs{Address: addr, AssetID: assetID.String()}, &balanceReply); err != nil {
+		t.Fatal(err)
+	}
+	if balanceReply.Balance != 180000 {
+		t.Fatalf("Wrong balance. Expected: 180000 ; Returned: %d", balanceReply.Balance)
+	}
+
+	burnArgs.Amount = 180001
+	if err := s.Burn(nil, burnArgs, &BurnReply{}); err != errInsufficientFunds {
+		t.Fatalf("Should have errored with %s but errored with %v", errInsufficientFunds, err)
+	}
+
+	// Minting adds to the supply of a variable cap asset
+	checkSupply("asset3", 0, 0)
+	mintReply := CreateMintTxReply{}
+	err = s.CreateMintTx(nil, &CreateMintTxArgs{
+		Amount:  500,
+		AssetID: "asset3",
+		To:      addr,
+		Minters: []string{addr},
+	}, &mintReply)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signReply := SignTxReply{}
+	if err := s.SignTx(nil, &SignTxArgs{Username: "user", Tx: mintReply.Tx}, &signReply); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.IssueTx(nil, &IssueTxArgs{Tx: signReply.Tx}, &IssueTxReply{}); err != nil {
+		t.Fatal(err)
+	}
+	accept()
+	checkSupply("asset3", 500, 0)
+
+	if err := s.GetAssetSupply(nil, &GetAssetSupplyArgs{AssetID: ids.Empty.String()}, &GetAssetSupplyReply{}); err != errUnknownAssetID {
+		t.Fatalf("Should have errored with %s but errored with %v", errUnknownAssetID, err)
+	}
+}
+
+func TestRemoteSigner(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		ctx.RemoteSigner = nil
+		ctx.Lock.Unlock()
+	}()
+
+	// The user's keys are only held by the remote signer
+	remote := signer.NewKeychain(logging.NoLog{}, memdb.New())
+	if err := remote.AddKey("user", "launch", keys[0]); err != nil {
+		t.Fatal(err)
+	}
+	ctx.RemoteSigner = remote
+
+	genesisTx := GetFirstTxFromGenesisTest(BuildGenesisTest(t), t)
+	assetID := genesisTx.ID()
+	to := vm.Format(keys[1].PublicKey().Address().Bytes())
+
+	s := Service{vm: vm}
+	err := s.ImportKey(nil, &ImportKeyArgs{
+		Username:   "user",
+		Password:   "launch",
+		PrivateKey: formatting.CB58{Bytes: keys[1].Bytes()},
+	}, &ImportKeyReply{})
+	if err != errRemoteSigner {
+		t.Fatalf("Should have errored with %s but errored with %v", errRemoteSigner, err)
+	}
+
+	sendArgs := &SendArgs{
+		Username: "user",
+		Password: "wrong",
+		Amount:   1000,
+		AssetID:  assetID.String(),
+		To:       to,
+	}
+	if err := s.Send(nil, sendArgs, &SendReply{}); err == nil {
+		t.Fatalf("Should have failed because the password is wrong")
+	}
+
+	sendArgs.Password = "launch"
+	if err := s.Send(nil, sendArgs, &SendReply{}); err != nil {
+		t.Fatal(err)
+	}
+	txs := vm.PendingTxs()
+	if len(txs) != 1 {
+		t.Fatalf("Should have issued the tx")
+	}
+	if err := txs[0].Verify(); err != nil {
+		t.Fatal(err)
+	}
+	txs[0].Accept()
+
+	balanceReply := GetBalanceReply{}
+	if err := s.GetBalance(nil, &GetBalanceArgs{Address: to, AssetID: assetID.String()}, &balanceReply); err != nil {
+		t.Fatal(err)
+	}
+	if balanceReply.Balance != 1000 {
+		t.Fatalf("Wrong balance. Expected: 1000 ; Returned: %d", balanceReply.Balance)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/ava-labs/gecko/cache"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/utils/hashing"
 )
 
 var (
@@ -174,3 +175,111 @@ func (s *state) SetIDs(id ids.ID, idSlice []ids.ID) error {
 
 	return s.vm.db.Put(id.Bytes(), bytes)
 }
+
+// Uint64 returns a uint64 from storage
+func (s *state) Uint64(id ids.ID) (uint64, error) {
+	if valIntf, found := s.c.Get(id); found {
+		if val, ok := valIntf.(uint64); ok {
+			return val, nil
+		}
+		return 0, errCacheTypeMismatch
+	}
+
+	bytes, err := s.vm.db.Get(id.Bytes())
+	if err != nil {
+		return 0, err
+	}
+
+	var val uint64
+	if err := s.vm.codec.Unmarshal(bytes, &val); err != nil {
+		return 0, err
+	}
+
+	s.c.Put(id, val)
+	return val, nil
+}
+
+// SetUint64 saves a uint64 in storage
+func (s *state) SetUint64(id ids.ID, val uint64) error {
+	s.c.Put(id, val)
+
+	bytes, err := s.vm.codec.Marshal(val)
+	if err != nil {
+		return err
+	}
+	return s.vm.db.Put(id.Bytes(), bytes)
+}
+
+// ID returns an ID from storage
+func (s *state) ID(id ids.ID) (ids.ID, error) {
+	if idIntf, found := s.c.Get(id); found {
+		if val, ok := idIntf.(ids.ID); ok {
+			return val, nil
+		}
+		return ids.ID{}, errCacheTypeMismatch
+	}
+
+	bytes, err := s.vm.db.Get(id.Bytes())
+	if err != nil {
+		return ids.ID{}, err
+	}
+
+	val, err := ids.ToID(bytes)
+	if err != nil {
+		return ids.ID{}, err
+	}
+
+	s.c.Put(id, val)
+	return val, nil
+}
+
+// SetID saves an ID in storage
+func (s *state) SetID(id ids.ID, val ids.ID) error {
+	s.c.Put(id, val)
+	return s.vm.db.Put(id.Bytes(), val.Bytes())
+}
+
+// AddID adds [val] to the set of IDs kept under [id]. Each ID of the set is
+// kept under its own key, so the set can be paged through without loading all
+// of it.
+func (s *state) AddID(id ids.ID, val ids.ID) error {
+	return s.vm.db.Put(setKey(id, val), nil)
+}
+
+// RemoveID removes [val] from the set of IDs kept under [id]
+func (s *state) RemoveID(id ids.ID, val ids.ID) error {
+	return s.vm.db.Delete(setKey(id, val))
+}
+
+// IDsPage returns up to [limit] IDs of the set kept under [id], in order. The
+// IDs start after [start], or with the first ID of the set if [start] is
+// empty.
+func (s *state) IDsPage(id ids.ID, start ids.ID, limit int) ([]ids.ID, error) {
+	prefix := id.Bytes()
+	startKey := prefix
+	if !start.IsZero() {
+		startKey = setKey(id, start)
+	}
+
+	it := s.vm.db.NewIteratorWithStartAndPrefix(startKey, prefix)
+	defer it.Release()
+
+	idSlice := []ids.ID(nil)
+	for len(idSlice) < limit && it.Next() {
+		val, err := ids.ToID(it.Key()[len(prefix):])
+		if err != nil {
+			return nil, err
+		}
+		if val.Equals(start) {
+			continue
+		}
+		idSlice = append(idSlice, val)
+	}
+	return idSlice, it.Error()
+}
+
+func setKey(id ids.ID, val ids.ID) []byte {
+	key := make([]byte, 0, 2*hashing.HashLen)
+	key = append(key, id.Bytes()...)
+	return append(key, val.Bytes()...)
+}
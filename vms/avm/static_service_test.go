@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
 )
 
 func TestBuildGenesis(t *testing.T) {
@@ -96,3 +97,72 @@ func TestBuildGenesis(t *testing.T) {
 		)
 	}
 }
+
+func TestBuildGenesisVesting(t *testing.T) {
+	ss := StaticService{}
+
+	args := BuildGenesisArgs{GenesisData: map[string]AssetDefinition{
+		"asset1": AssetDefinition{
+			Name:   "myFixedCapAsset",
+			Symbol: "MFCA",
+			InitialState: map[string][]interface{}{
+				"fixedCap": []interface{}{
+					Holder{
+						Amount:  100000,
+						Address: "A9bTQjfYGBFK3JPRJqF2eh3JYL7cHocvy",
+						Lock: Lock{
+							VestingStart: 100,
+							VestingEnd:   200,
+						},
+					},
+					Holder{
+						Amount:  100000,
+						Address: "6mxBGnjGDCKgkVe7yfrmvMA7xE7qCv3vv",
+						Lock: Lock{
+							Locktime: 300,
+						},
+					},
+				},
+			},
+		},
+	}}
+	reply := BuildGenesisReply{}
+	if err := ss.BuildGenesis(nil, &args, &reply); err != nil {
+		t.Fatal(err)
+	}
+
+	g := Genesis{}
+	if err := genesisCodec().Unmarshal(reply.Bytes.Bytes, &g); err != nil {
+		t.Fatal(err)
+	}
+	outs := g.Txs[0].States[0].Outs
+	if len(outs) != 2 {
+		t.Fatalf("Wrong number of outputs. Expected: 2 ; Returned: %d", len(outs))
+	}
+	for _, out := range outs {
+		switch out := out.(type) {
+		case *secp256k1fx.VestingOutput:
+			if out.VestingStart != 100 || out.VestingEnd != 200 {
+				t.Fatalf("Wrong vesting schedule")
+			}
+		case *secp256k1fx.TransferOutput:
+			if out.Locktime != 300 {
+				t.Fatalf("Wrong locktime. Expected: 300 ; Returned: %d", out.Locktime)
+			}
+		default:
+			t.Fatalf("Unexpected output type %T", out)
+		}
+	}
+
+	args.GenesisData["asset1"].InitialState["fixedCap"][0] = Holder{
+		Amount:  100000,
+		Address: "A9bTQjfYGBFK3JPRJqF2eh3JYL7cHocvy",
+		Lock: Lock{
+			Locktime:   300,
+			VestingEnd: 200,
+		},
+	}
+	if err := ss.BuildGenesis(nil, &args, &reply); err != errLockedAndVesting {
+		t.Fatalf("Should have errored with %s but errored with %v", errLockedAndVesting, err)
+	}
+}
@@ -30,8 +30,9 @@ type UnsignedTx interface {
 	AssetIDs() ids.Set
 	InputUTXOs() []*UTXOID
 	UTXOs() []*UTXO
-	SyntacticVerify(ctx *snow.Context, c codec.Codec, numFxs int) error
+	SyntacticVerify(ctx *snow.Context, c codec.Codec, txFeeAssetID ids.ID, txFee uint64, numFxs int) error
 	SemanticVerify(vm *VM, uTx *UniqueTx, creds []*Credential) error
+	ExecuteWithSideEffects(vm *VM) error
 }
 
 // Tx is the core operation that can be performed. The tx uses the UTXO model.
@@ -50,13 +51,13 @@ type Tx struct {
 func (t *Tx) Credentials() []*Credential { return t.Creds }
 
 // SyntacticVerify verifies that this transaction is well-formed.
-func (t *Tx) SyntacticVerify(ctx *snow.Context, c codec.Codec, numFxs int) error {
+func (t *Tx) SyntacticVerify(ctx *snow.Context, c codec.Codec, txFeeAssetID ids.ID, txFee uint64, numFxs int) error {
 	switch {
 	case t == nil || t.UnsignedTx == nil:
 		return errNilTx
 	}
 
-	if err := t.UnsignedTx.SyntacticVerify(ctx, c, numFxs); err != nil {
+	if err := t.UnsignedTx.SyntacticVerify(ctx, c, txFeeAssetID, txFee, numFxs); err != nil {
 		return err
 	}
 
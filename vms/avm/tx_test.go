@@ -15,7 +15,7 @@ import (
 func TestTxNil(t *testing.T) {
 	c := codec.NewDefault()
 	tx := (*Tx)(nil)
-	if err := tx.SyntacticVerify(ctx, c, 1); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 1); err == nil {
 		t.Fatalf("Should have errored due to nil tx")
 	}
 }
@@ -27,7 +27,7 @@ func TestTxEmpty(t *testing.T) {
 	c.RegisterType(&OperationTx{})
 
 	tx := &Tx{}
-	if err := tx.SyntacticVerify(ctx, c, 1); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 1); err == nil {
 		t.Fatalf("Should have errored due to nil tx")
 	}
 }
@@ -81,7 +81,7 @@ func TestTxInvalidCredential(t *testing.T) {
 	}
 	tx.Initialize(b)
 
-	if err := tx.SyntacticVerify(ctx, c, 1); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 1); err == nil {
 		t.Fatalf("Tx should have failed due to an invalid credential")
 	}
 }
@@ -155,7 +155,7 @@ func TestTxInvalidUnsignedTx(t *testing.T) {
 	}
 	tx.Initialize(b)
 
-	if err := tx.SyntacticVerify(ctx, c, 1); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 1); err == nil {
 		t.Fatalf("Tx should have failed due to an invalid unsigned tx")
 	}
 }
@@ -227,7 +227,7 @@ func TestTxInvalidNumberOfCredentials(t *testing.T) {
 	}
 	tx.Initialize(b)
 
-	if err := tx.SyntacticVerify(ctx, c, 1); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 1); err == nil {
 		t.Fatalf("Tx should have failed due to an invalid unsigned tx")
 	}
 }
@@ -98,8 +98,20 @@ func (tx *UniqueTx) Accept() {
 		return
 	}
 
+	// The owners of the spent utxos must be looked up before they're removed
+	var indices []ids.ID
+	if tx.vm.indexTxs {
+		indices = tx.vm.txIndices(tx.t.tx)
+	}
+
 	// Remove spent utxos
-	for _, utxoID := range tx.InputIDs().List() {
+	for _, utxo := range tx.InputUTXOs() {
+		if utxo.Symbolic() {
+			// If the UTXO isn't in this chain's state, then there is nothing
+			// to remove
+			continue
+		}
+		utxoID := utxo.InputID()
 		if err := tx.vm.state.SpendUTXO(utxoID); err != nil {
 			tx.vm.ctx.Log.Error("Failed to spend utxo %s due to %s", utxoID, err)
 			return
@@ -109,7 +121,7 @@ func (tx *UniqueTx) Accept() {
 	// Add new utxos
 	for _, utxo := range tx.UTXOs() {
 		if err := tx.vm.state.FundUTXO(utxo); err != nil {
-			tx.vm.ctx.Log.Error("Failed to fund utxo %s due to %s", utxoID, err)
+			tx.vm.ctx.Log.Error("Failed to fund utxo %s due to %s", utxo.InputID(), err)
 			return
 		}
 	}
@@ -117,7 +129,15 @@ func (tx *UniqueTx) Accept() {
 	txID := tx.ID()
 	tx.vm.ctx.Log.Verbo("Accepting Tx: %s", txID)
 
-	if err := tx.vm.db.Commit(); err != nil {
+	// The indices are only informational, so failing to update them doesn't
+	// stop the tx from being accepted. They're updated before the accept is
+	// committed, so that they're committed with it.
+	tx.vm.indexTx(txID, indices)
+	if err := tx.vm.indexSupply(tx.t.tx); err != nil {
+		tx.vm.ctx.Log.Error("Failed to index the supply changes of tx %s due to %s", tx.txID, err)
+	}
+
+	if err := tx.t.tx.ExecuteWithSideEffects(tx.vm); err != nil {
 		tx.vm.ctx.Log.Error("Failed to commit accept %s due to %s", tx.txID, err)
 	}
 
@@ -160,6 +180,9 @@ func (tx *UniqueTx) Dependencies() []snowstorm.Tx {
 
 	txIDs := ids.Set{}
 	for _, in := range tx.InputUTXOs() {
+		if in.Symbolic() {
+			continue
+		}
 		txID, _ := in.InputSource()
 		if !txIDs.Contains(txID) {
 			txIDs.Add(txID)
@@ -247,7 +270,7 @@ func (tx *UniqueTx) SyntacticVerify() error {
 	}
 
 	tx.t.verifiedTx = true
-	tx.t.validity = tx.t.tx.SyntacticVerify(tx.vm.ctx, tx.vm.codec, len(tx.vm.fxs))
+	tx.t.validity = tx.t.tx.SyntacticVerify(tx.vm.ctx, tx.vm.codec, tx.vm.ava, tx.vm.txFee, len(tx.vm.fxs))
 	return tx.t.validity
 }
 
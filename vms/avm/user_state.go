@@ -51,3 +51,34 @@ func (s *userState) Key(db database.Database, address ids.ID) (*crypto.PrivateKe
 	}
 	return sk.(*crypto.PrivateKeySECP256K1R), nil
 }
+
+// keystoreSigner signs with the keys the user holds in the keystore
+type keystoreSigner struct {
+	user *userState
+	db   database.Database
+}
+
+// Addresses implements the snow.Signer interface
+func (s *keystoreSigner) Addresses() ([]ids.ShortID, error) {
+	// A user that hasn't stored any keys has no addresses
+	addrHashes, _ := s.user.Addresses(s.db)
+
+	addrs := make([]ids.ShortID, len(addrHashes))
+	for i, addrHash := range addrHashes {
+		sk, err := s.user.Key(s.db, addrHash)
+		if err != nil {
+			return nil, err
+		}
+		addrs[i] = sk.PublicKey().Address()
+	}
+	return addrs, nil
+}
+
+// SignHash implements the snow.Signer interface
+func (s *keystoreSigner) SignHash(addr ids.ShortID, hash []byte) ([]byte, error) {
+	sk, err := s.user.Key(s.db, ids.NewID(hashing.ComputeHash256Array(addr.Bytes())))
+	if err != nil {
+		return nil, err
+	}
+	return sk.SignHash(hash)
+}
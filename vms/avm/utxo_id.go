@@ -20,6 +20,10 @@ type UTXOID struct {
 	TxID        ids.ID `serialize:"true"`
 	OutputIndex uint32 `serialize:"true"`
 
+	// Symbol is true if the UTXO isn't stored in this chain's state, such as a
+	// UTXO imported from another chain
+	Symbol bool
+
 	// Cached:
 	id ids.ID
 }
@@ -35,6 +39,9 @@ func (utxo *UTXOID) InputID() ids.ID {
 	return utxo.id
 }
 
+// Symbolic returns true if the UTXO isn't stored in this chain's state
+func (utxo *UTXOID) Symbolic() bool { return utxo.Symbol }
+
 // Verify implements the verify.Verifiable interface
 func (utxo *UTXOID) Verify() error {
 	switch {
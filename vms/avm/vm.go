@@ -13,6 +13,7 @@ import (
 	"github.com/gorilla/rpc/v2"
 
 	"github.com/ava-labs/gecko/cache"
+	"github.com/ava-labs/gecko/chains/atomic"
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/database/versiondb"
 	"github.com/ava-labs/gecko/ids"
@@ -21,6 +22,8 @@ import (
 	"github.com/ava-labs/gecko/snow/consensus/snowstorm"
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/math"
 	"github.com/ava-labs/gecko/utils/timer"
 	"github.com/ava-labs/gecko/utils/wrappers"
 	"github.com/ava-labs/gecko/vms/components/codec"
@@ -43,6 +46,7 @@ var (
 	errGenesisAssetMustHaveState = errors.New("genesis asset must have non-empty state")
 	errInvalidAddress            = errors.New("invalid address")
 	errWrongBlockchainID         = errors.New("wrong blockchain ID")
+	errUnknownStartAddress       = errors.New("the start index's address isn't one of the addresses")
 )
 
 // VM implements the avalanche.DAGVM interface
@@ -52,6 +56,18 @@ type VM struct {
 	// Contains information of where this VM is executing
 	ctx *snow.Context
 
+	// ID of the AVA asset
+	ava ids.ID
+
+	// ID of the platform chain, which AVA can be exported to and imported from
+	platform ids.ID
+
+	// Amount of AVA burnt by every transaction
+	txFee uint64
+
+	// If true, the txs that touched each address and asset are indexed
+	indexTxs bool
+
 	// Used to check local time
 	clock timer.Clock
 
@@ -133,6 +149,9 @@ func (vm *VM) Initialize(
 		txStatus: &cache.LRU{Size: idCacheSize},
 		funds:    &cache.LRU{Size: idCacheSize},
 
+		addressTxs: &cache.LRU{Size: idCacheSize},
+		assetTxs:   &cache.LRU{Size: idCacheSize},
+
 		uniqueTx: &cache.EvictableLRU{Size: txCacheSize},
 	}
 
@@ -166,6 +185,16 @@ func (vm *VM) Initialize(
 
 	vm.codec = c
 
+	// These types are registered after the feature extension types, so that the
+	// type IDs of the genesis are unchanged
+	errs.Add(
+		c.RegisterType(&ImportTx{}),
+		c.RegisterType(&ExportTx{}),
+	)
+	if errs.Errored() {
+		return errs.Err
+	}
+
 	if err := vm.initAliases(genesisBytes); err != nil {
 		return err
 	}
@@ -176,6 +205,12 @@ func (vm *VM) Initialize(
 		}
 	}
 
+	if supplyStatus, err := vm.state.SupplyIndexed(); err != nil || supplyStatus == choices.Unknown {
+		if err := vm.initSupply(genesisBytes); err != nil {
+			return err
+		}
+	}
+
 	vm.timer = timer.NewTimer(func() {
 		ctx.Lock.Lock()
 		defer ctx.Lock.Unlock()
@@ -284,6 +319,107 @@ func (vm *VM) GetUTXOs(addrs ids.Set) ([]*UTXO, error) {
 	return utxos, nil
 }
 
+// GetPaginatedUTXOs returns up to [limit] utxos that at least one of the
+// provided addresses is referenced in. The addresses are the 32 byte
+// representations of the addresses. The utxos of each address are returned in
+// the order of their IDs, and the addresses are paged through in the order
+// they're provided in. Paging starts after the utxo [startUTXO] of the address
+// [startAddr], or at the first utxo of the first address if [startAddr] is
+// empty. Returns the address and utxo the next page should start after.
+//
+// A utxo referenced by several of the addresses is only returned once per
+// page.
+func (vm *VM) GetPaginatedUTXOs(addrs []ids.ID, startAddr, startUTXO ids.ID, limit int) ([]*UTXO, ids.ID, ids.ID, error) {
+	first := 0
+	if !startAddr.IsZero() {
+		for first < len(addrs) && !addrs[first].Equals(startAddr) {
+			first++
+		}
+		if first == len(addrs) {
+			return nil, ids.ID{}, ids.ID{}, errUnknownStartAddress
+		}
+	}
+
+	utxos := []*UTXO{}
+	seen := ids.Set{}
+	lastAddr, lastUTXO := startAddr, startUTXO
+	for _, addr := range addrs[first:] {
+		start := ids.ID{}
+		if addr.Equals(startAddr) {
+			start = startUTXO
+		}
+		for len(utxos) < limit {
+			utxoIDs, err := vm.state.FundsPage(addr, start, limit-len(utxos))
+			if err != nil {
+				return nil, ids.ID{}, ids.ID{}, err
+			}
+			if len(utxoIDs) == 0 {
+				break
+			}
+			for _, utxoID := range utxoIDs {
+				start = utxoID
+				lastAddr, lastUTXO = addr, utxoID
+				if seen.Contains(utxoID) {
+					continue
+				}
+				seen.Add(utxoID)
+
+				utxo, err := vm.state.UTXO(utxoID)
+				if err != nil {
+					return nil, ids.ID{}, ids.ID{}, err
+				}
+				utxos = append(utxos, utxo)
+			}
+		}
+		if len(utxos) == limit {
+			break
+		}
+	}
+	return utxos, lastAddr, lastUTXO, nil
+}
+
+// DecodeContainer implements the events.Decoder interface. The tx is described
+// by its JSON representation, and the addresses are the owners of the UTXOs it
+// creates.
+func (vm *VM) DecodeContainer(container []byte) (interface{}, []string, error) {
+	tx := &Tx{}
+	if err := vm.codec.Unmarshal(container, tx); err != nil {
+		return nil, nil, err
+	}
+	if tx.UnsignedTx == nil {
+		return nil, nil, errNilTx
+	}
+	tx.Initialize(container)
+
+	addrs := []string{}
+	seen := map[string]struct{}{}
+	for _, utxo := range tx.UTXOs() {
+		addressable, ok := utxo.Out.(FxAddressable)
+		if !ok {
+			continue
+		}
+		for _, addr := range addressable.Addresses() {
+			addrStr := vm.Format(addr)
+			if _, ok := seen[addrStr]; ok {
+				continue
+			}
+			seen[addrStr] = struct{}{}
+			addrs = append(addrs, addrStr)
+		}
+	}
+	return tx, addrs, nil
+}
+
+// NormalizeAddress implements the events.Decoder interface. The address may be
+// prefixed with any alias of the chain, or with its ID.
+func (vm *VM) NormalizeAddress(addr string) (string, error) {
+	b, err := vm.Parse(addr)
+	if err != nil {
+		return "", err
+	}
+	return vm.Format(b), nil
+}
+
 /*
  ******************************************************************************
  *********************************** Fx API ***********************************
@@ -379,6 +515,9 @@ func (vm *VM) initState(genesisBytes []byte) error {
 		if err := vm.state.SetStatus(txID, choices.Accepted); err != nil {
 			return err
 		}
+		if vm.indexTxs {
+			vm.indexTx(txID, vm.txIndices(&tx))
+		}
 		for _, utxo := range tx.UTXOs() {
 			if err := vm.state.FundUTXO(utxo); err != nil {
 				return err
@@ -389,6 +528,34 @@ func (vm *VM) initState(genesisBytes []byte) error {
 	return vm.state.SetDBInitialized(choices.Processing)
 }
 
+// initSupply indexes the supply of the genesis assets. A database that was
+// initialized before the supply was indexed is backfilled with the genesis
+// supply here, but not with the txs it accepted since then, so its burnt amounts
+// may exceed its minted amounts.
+func (vm *VM) initSupply(genesisBytes []byte) error {
+	genesis := Genesis{}
+	if err := vm.codec.Unmarshal(genesisBytes, &genesis); err != nil {
+		return err
+	}
+
+	for _, genesisTx := range genesis.Txs {
+		tx := Tx{
+			UnsignedTx: &genesisTx.CreateAssetTx,
+		}
+		txBytes, err := vm.codec.Marshal(&tx)
+		if err != nil {
+			return err
+		}
+		tx.Initialize(txBytes)
+
+		if err := vm.indexSupply(&tx); err != nil {
+			return err
+		}
+	}
+
+	return vm.state.SetSupplyIndexed(choices.Accepted)
+}
+
 func (vm *VM) parseTx(b []byte) (*UniqueTx, error) {
 	rawTx := &Tx{}
 	err := vm.codec.Unmarshal(b, rawTx)
@@ -428,6 +595,168 @@ func (vm *VM) issueTx(tx snowstorm.Tx) {
 	}
 }
 
+// txIndices returns the indices of the addresses and assets [tx] touched. Must
+// be called before the UTXOs [tx] consumes are spent. The owners of UTXOs that
+// can't be read are left out, rather than failing the acceptance of [tx].
+func (vm *VM) txIndices(tx *Tx) []ids.ID {
+	addrs := ids.Set{}
+	assets := tx.AssetIDs()
+	if _, ok := tx.UnsignedTx.(*CreateAssetTx); ok {
+		assets.Add(tx.ID())
+	}
+
+	addAddrs := func(out interface{}) {
+		if addressable, ok := out.(FxAddressable); ok {
+			for _, addr := range addressable.Addresses() {
+				addrs.Add(ids.NewID(hashing.ComputeHash256Array(addr)))
+			}
+		}
+	}
+	for _, utxoID := range tx.InputUTXOs() {
+		if utxoID.Symbolic() {
+			// UTXOs that aren't in this chain's state don't have known owners
+			continue
+		}
+		utxo, err := vm.state.UTXO(utxoID.InputID())
+		if err != nil {
+			vm.ctx.Log.Error("Failed to look up the owners of utxo %s due to %s", utxoID.InputID(), err)
+			continue
+		}
+		addAddrs(utxo.Out)
+	}
+	for _, utxo := range tx.UTXOs() {
+		assets.Add(utxo.AssetID())
+		addAddrs(utxo.Out)
+	}
+
+	indices := make([]ids.ID, 0, addrs.Len()+assets.Len())
+	for _, addr := range addrs.List() {
+		indices = append(indices, vm.state.AddressTxs(addr))
+	}
+	for _, assetID := range assets.List() {
+		indices = append(indices, vm.state.AssetTxs(assetID))
+	}
+	return indices
+}
+
+// indexTx appends [txID] to [indices]. Failures are only logged, so that
+// indexing can't fail the acceptance of a tx.
+func (vm *VM) indexTx(txID ids.ID, indices []ids.ID) {
+	for _, index := range indices {
+		if err := vm.state.IndexTx(index, txID); err != nil {
+			vm.ctx.Log.Error("Failed to index tx %s due to %s", txID, err)
+		}
+	}
+}
+
+// indexSupply adds the value [tx] minted and burnt to the supply indices of the
+// assets it touched. Value is minted by the initial states of new assets, by
+// operations that produce more than they consume, and by imports from another
+// chain. Whatever the tx consumes, but doesn't produce, is burnt, including the
+// value it exports to another chain.
+//
+// The totals saturate rather than overflow, so that indexing the supply can't
+// fail due to the amounts in a tx that consensus accepted.
+func (vm *VM) indexSupply(tx *Tx) error {
+	consumed := map[[32]byte]uint64{}
+	produced := map[[32]byte]uint64{}
+	minted := map[[32]byte]uint64{}
+	assets := ids.Set{}
+	add := func(amounts map[[32]byte]uint64, assetID ids.ID, amount uint64) {
+		assets.Add(assetID)
+		amounts[assetID.Key()] = addSaturating(amounts[assetID.Key()], amount)
+	}
+
+	var ins []*TransferableInput
+	switch utx := tx.UnsignedTx.(type) {
+	case *BaseTx:
+		ins = utx.Ins
+	case *CreateAssetTx:
+		ins = utx.Ins
+		for _, state := range utx.States {
+			for _, out := range state.Outs {
+				add(minted, tx.ID(), amountOf(out))
+			}
+		}
+	case *OperationTx:
+		ins = utx.Ins
+		for _, op := range utx.Ops {
+			opConsumed, opProduced := map[[32]byte]uint64{}, map[[32]byte]uint64{}
+			for _, in := range op.Ins {
+				add(opConsumed, op.AssetID(), amountOf(in.In))
+			}
+			for _, out := range op.Outs {
+				add(opProduced, op.AssetID(), amountOf(out.Out))
+			}
+			assetKey := op.AssetID().Key()
+			if opProduced[assetKey] > opConsumed[assetKey] {
+				add(minted, op.AssetID(), opProduced[assetKey]-opConsumed[assetKey])
+			}
+			add(consumed, op.AssetID(), opConsumed[assetKey])
+		}
+	case *ImportTx:
+		ins = utx.Ins
+		for _, in := range utx.ImportIns {
+			add(minted, in.AssetID(), amountOf(in.In))
+		}
+	case *ExportTx:
+		ins = utx.Ins
+	}
+	for _, in := range ins {
+		add(consumed, in.AssetID(), amountOf(in.In))
+	}
+	for _, utxo := range tx.UTXOs() {
+		add(produced, utxo.AssetID(), amountOf(utxo.Out))
+	}
+
+	for _, assetID := range assets.List() {
+		assetKey := assetID.Key()
+		available := addSaturating(consumed[assetKey], minted[assetKey])
+		burned := uint64(0)
+		if available > produced[assetKey] {
+			burned = available - produced[assetKey]
+		}
+		if err := vm.state.AddSupply(assetID, minted[assetKey], burned); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addSaturating returns [a] + [b], or the maximum uint64 if the sum overflows
+func addSaturating(a, b uint64) uint64 {
+	if sum, err := math.Add64(a, b); err == nil {
+		return sum
+	}
+	return ^uint64(0)
+}
+
+// amountOf returns the amount of the asset [val] holds, which is 0 if [val]
+// isn't fungible
+func amountOf(val interface{}) uint64 {
+	if transferable, ok := val.(FxTransferable); ok {
+		return transferable.Amount()
+	}
+	return 0
+}
+
+// commitWithSharedMemory writes the chain's database and [smDB], a view of the
+// memory shared with another chain, in a single batch
+func (vm *VM) commitWithSharedMemory(smDB *versiondb.Database) error {
+	batch, err := vm.db.CommitBatch()
+	if err != nil {
+		return err
+	}
+	smBatch, err := smDB.CommitBatch()
+	if err != nil {
+		return err
+	}
+	if err := atomic.WriteAll(batch, smBatch); err != nil {
+		return err
+	}
+	return vm.db.Abort()
+}
+
 func (vm *VM) getFx(val interface{}) (int, error) {
 	valType := reflect.TypeOf(val)
 	fx, exists := vm.typeToFxIndex[valType]
@@ -437,6 +766,16 @@ func (vm *VM) getFx(val interface{}) (int, error) {
 	return fx, nil
 }
 
+// fxIndex returns the index of the fx [fxID] among the fxs of this chain
+func (vm *VM) fxIndex(fxID ids.ID) (int, error) {
+	for i, fx := range vm.fxs {
+		if fx.ID.Equals(fxID) {
+			return i, nil
+		}
+	}
+	return 0, errUnknownFx
+}
+
 func (vm *VM) verifyFxUsage(fxID int, assetID ids.ID) bool {
 	tx := &UniqueTx{
 		vm:   vm,
@@ -5,6 +5,8 @@ package avm
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
 	"testing"
 
 	"github.com/ava-labs/gecko/database/memdb"
@@ -547,3 +549,127 @@ func TestGenesisGetUTXOs(t *testing.T) {
 		t.Fatalf("Wrong number of utxos (%d) returned", len(utxos))
 	}
 }
+
+func TestIssueTxFee(t *testing.T) {
+	vm, _, genesisTx := AtomicVM(t)
+	vm.txFee = 10
+
+	vm.ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.ctx.Lock.Unlock()
+	}()
+
+	key := keys[0]
+	newTx := func(amount uint64) *Tx {
+		return &Tx{UnsignedTx: &BaseTx{
+			NetID: networkID,
+			BCID:  chainID,
+			Ins: []*TransferableInput{&TransferableInput{
+				UTXOID: UTXOID{
+					TxID:        genesisTx.ID(),
+					OutputIndex: 1,
+				},
+				Asset: Asset{ID: genesisTx.ID()},
+				In: &secp256k1fx.TransferInput{
+					Amt:   50000,
+					Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+				},
+			}},
+			Outs: []*TransferableOutput{&TransferableOutput{
+				Asset: Asset{ID: genesisTx.ID()},
+				Out: &secp256k1fx.TransferOutput{
+					Amt: amount,
+					OutputOwners: secp256k1fx.OutputOwners{
+						Threshold: 1,
+						Addrs:     []ids.ShortID{key.PublicKey().Address()},
+					},
+				},
+			}},
+		}}
+	}
+
+	service := Service{vm: vm}
+	if _, err := service.signAndIssue(newTx(50000), [][]signingKey{{key}}); err == nil {
+		t.Fatalf("Should have errored due to not paying the tx fee")
+	}
+	if _, err := service.signAndIssue(newTx(50000-10), [][]signingKey{{key}}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDecodeContainer(t *testing.T) {
+	vm := GenesisVM(t)
+	ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		ctx.Lock.Unlock()
+	}()
+
+	addr1 := keys[1].PublicKey().Address()
+	addr2 := keys[2].PublicKey().Address()
+	output := func(addrs ...ids.ShortID) *TransferableOutput {
+		return &TransferableOutput{
+			Asset: Asset{ID: asset},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: 1,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     addrs,
+				},
+			},
+		}
+	}
+	tx := &Tx{UnsignedTx: &BaseTx{
+		NetID: networkID,
+		BCID:  chainID,
+		Outs: []*TransferableOutput{
+			output(addr1),
+			output(addr1, addr2),
+		},
+	}}
+	b, err := vm.codec.Marshal(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, addrs, err := vm.DecodeContainer(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decodedTx, ok := body.(*Tx)
+	if !ok {
+		t.Fatalf("Should have described the tx")
+	}
+	if !bytes.Equal(decodedTx.Bytes(), b) {
+		t.Fatalf("Wrong tx. Expected: 0x%x ; Returned: 0x%x", b, decodedTx.Bytes())
+	}
+	if _, err := json.Marshal(body); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedAddrs := []string{vm.Format(addr1.Bytes()), vm.Format(addr2.Bytes())}
+	if len(addrs) != len(expectedAddrs) {
+		t.Fatalf("Wrong addresses. Expected: %v ; Returned: %v", expectedAddrs, addrs)
+	}
+	for i, addr := range addrs {
+		if addr != expectedAddrs[i] {
+			t.Fatalf("Wrong addresses. Expected: %v ; Returned: %v", expectedAddrs, addrs)
+		}
+	}
+
+	if _, _, err := vm.DecodeContainer([]byte{1, 2, 3}); err == nil {
+		t.Fatalf("Should have failed to decode an invalid tx")
+	}
+
+	// Subscribers may prefix addresses with the chain's ID rather than its alias
+	byID := fmt.Sprintf("%s%s%s", chainID, addressSep, formatting.CB58{Bytes: addr1.Bytes()})
+	if normalized, err := vm.NormalizeAddress(byID); err != nil {
+		t.Fatal(err)
+	} else if normalized != expectedAddrs[0] {
+		t.Fatalf("Wrong address. Expected: %s ; Returned: %s", expectedAddrs[0], normalized)
+	}
+	if _, err := vm.NormalizeAddress(addr1.String()); err == nil {
+		t.Fatalf("Should have required the chain prefix")
+	}
+}
@@ -0,0 +1,111 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package shared
+
+import (
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/vms/components/codec"
+)
+
+var (
+	utxoPrefix  = []byte("utxo")
+	fundsPrefix = []byte("funds")
+)
+
+// State is the set of UTXOs that were exported to a chain, and that the chain
+// hasn't imported yet. It is kept in a database that the chain shares with the
+// exporting chain, which must be held while the state is used.
+type State struct {
+	codec codec.Codec
+
+	// UTXO ID --> UTXO
+	utxoDB database.Database
+
+	// Address + UTXO ID --> nothing
+	fundsDB database.Database
+}
+
+// NewState returns the UTXOs in the shared database [db] that were exported to
+// the chain [chainID]
+func NewState(db database.Database, chainID ids.ID) *State {
+	// The prefix isn't compressed into [db]'s, so that the keys are the same
+	// whether or not [db] is wrapped, for example, by a versiondb.
+	chainDB := prefixdb.NewNested(chainID.Bytes(), db)
+	return &State{
+		codec:   codec.NewDefault(),
+		utxoDB:  prefixdb.New(utxoPrefix, chainDB),
+		fundsDB: prefixdb.New(fundsPrefix, chainDB),
+	}
+}
+
+// UTXO returns the UTXO with ID [id]
+func (s *State) UTXO(id ids.ID) (*UTXO, error) {
+	b, err := s.utxoDB.Get(id.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	utxo := &UTXO{}
+	if err := s.codec.Unmarshal(b, utxo); err != nil {
+		return nil, err
+	}
+	return utxo, nil
+}
+
+// FundUTXO adds [utxo] to the state, and indexes it by the addresses that
+// control it
+func (s *State) FundUTXO(utxo *UTXO) error {
+	b, err := s.codec.Marshal(utxo)
+	if err != nil {
+		return err
+	}
+	utxoID := utxo.InputID()
+	if err := s.utxoDB.Put(utxoID.Bytes(), b); err != nil {
+		return err
+	}
+	for _, addr := range utxo.Out.Addrs {
+		if err := s.fundsDB.Put(fundsKey(addr, utxoID), nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SpendUTXO removes the UTXO with ID [id] from the state
+func (s *State) SpendUTXO(id ids.ID) error {
+	utxo, err := s.UTXO(id)
+	if err != nil {
+		return err
+	}
+	for _, addr := range utxo.Out.Addrs {
+		if err := s.fundsDB.Delete(fundsKey(addr, id)); err != nil {
+			return err
+		}
+	}
+	return s.utxoDB.Delete(id.Bytes())
+}
+
+// Funds returns the IDs of the UTXOs that [addr] is one of the owners of
+func (s *State) Funds(addr ids.ShortID) ([]ids.ID, error) {
+	it := s.fundsDB.NewIteratorWithPrefix(addr.Bytes())
+	defer it.Release()
+
+	utxoIDs := []ids.ID(nil)
+	for it.Next() {
+		utxoID, err := ids.ToID(it.Key()[hashing.AddrLen:])
+		if err != nil {
+			return nil, err
+		}
+		utxoIDs = append(utxoIDs, utxoID)
+	}
+	return utxoIDs, it.Error()
+}
+
+func fundsKey(addr ids.ShortID, utxoID ids.ID) []byte {
+	key := make([]byte, 0, hashing.AddrLen+hashing.HashLen)
+	key = append(key, addr.Bytes()...)
+	return append(key, utxoID.Bytes()...)
+}
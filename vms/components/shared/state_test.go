@@ -0,0 +1,121 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package shared
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/database/versiondb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+func TestStateFundSpend(t *testing.T) {
+	db := memdb.New()
+	chainID := ids.Empty.Prefix(0)
+	state := NewState(db, chainID)
+
+	addr0 := ids.NewShortID([20]byte{1})
+	addr1 := ids.NewShortID([20]byte{2})
+	utxo := &UTXO{
+		TxID:        ids.Empty.Prefix(1),
+		OutputIndex: 1,
+		AssetID:     ids.Empty.Prefix(2),
+		Out: secp256k1fx.TransferOutput{
+			Amt: 12345,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{addr0},
+			},
+		},
+	}
+	utxoID := utxo.InputID()
+
+	if _, err := state.UTXO(utxoID); err == nil {
+		t.Fatalf("Shouldn't have found an unfunded UTXO")
+	}
+	if err := state.FundUTXO(utxo); err != nil {
+		t.Fatal(err)
+	}
+
+	fetched, err := state.UTXO(utxoID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fetched.InputID().Equals(utxoID) {
+		t.Fatalf("Wrong UTXO returned")
+	}
+	if fetched.Out.Amt != 12345 {
+		t.Fatalf("Wrong amount. Expected: %d ; Returned: %d", 12345, fetched.Out.Amt)
+	}
+
+	if funds, err := state.Funds(addr0); err != nil {
+		t.Fatal(err)
+	} else if len(funds) != 1 || !funds[0].Equals(utxoID) {
+		t.Fatalf("Wrong funds for the owner of the UTXO: %v", funds)
+	}
+	if funds, err := state.Funds(addr1); err != nil {
+		t.Fatal(err)
+	} else if len(funds) != 0 {
+		t.Fatalf("An address that doesn't own the UTXO shouldn't have funds: %v", funds)
+	}
+
+	otherState := NewState(db, ids.Empty.Prefix(3))
+	if _, err := otherState.UTXO(utxoID); err == nil {
+		t.Fatalf("UTXOs exported to one chain shouldn't be visible to another")
+	}
+
+	if err := state.SpendUTXO(utxoID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := state.UTXO(utxoID); err == nil {
+		t.Fatalf("Shouldn't have found a spent UTXO")
+	}
+	if funds, err := state.Funds(addr0); err != nil {
+		t.Fatal(err)
+	} else if len(funds) != 0 {
+		t.Fatalf("A spent UTXO shouldn't be indexed: %v", funds)
+	}
+	if err := state.SpendUTXO(utxoID); err == nil {
+		t.Fatalf("Shouldn't have spent a UTXO twice")
+	}
+}
+
+func TestStateWrappedDatabase(t *testing.T) {
+	db := prefixdb.New([]byte{1}, memdb.New())
+	chainID := ids.Empty.Prefix(0)
+
+	addr := ids.NewShortID([20]byte{1})
+	utxo := &UTXO{
+		TxID:    ids.Empty.Prefix(1),
+		AssetID: ids.Empty.Prefix(2),
+		Out: secp256k1fx.TransferOutput{
+			Amt: 12345,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{addr},
+			},
+		},
+	}
+
+	vdb := versiondb.New(db)
+	if err := NewState(vdb, chainID).FundUTXO(utxo); err != nil {
+		t.Fatal(err)
+	}
+	if err := vdb.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	state := NewState(db, chainID)
+	if _, err := state.UTXO(utxo.InputID()); err != nil {
+		t.Fatalf("A UTXO funded through a wrapped database should be visible through the database: %s", err)
+	}
+	if funds, err := state.Funds(addr); err != nil {
+		t.Fatal(err)
+	} else if len(funds) != 1 {
+		t.Fatalf("Wrong funds: %v", funds)
+	}
+}
@@ -0,0 +1,51 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package shared
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+var (
+	errNilUTXO    = errors.New("nil utxo is not valid")
+	errNilTxID    = errors.New("nil tx ID is not valid")
+	errNilAssetID = errors.New("nil asset ID is not valid")
+)
+
+// UTXO is an output that was exported from one chain to another. Until the
+// destination chain imports it, it is kept in the memory the two chains share.
+type UTXO struct {
+	// ID of the transaction that exported this UTXO
+	TxID ids.ID `serialize:"true"`
+
+	// Index of this UTXO in the outputs of the exporting transaction
+	OutputIndex uint32 `serialize:"true"`
+
+	// ID of the asset this UTXO holds
+	AssetID ids.ID `serialize:"true"`
+
+	// The exported output
+	Out secp256k1fx.TransferOutput `serialize:"true"`
+}
+
+// InputID returns the unique ID of this UTXO. It matches the ID the AVM gives
+// to the UTXO with the same source.
+func (utxo *UTXO) InputID() ids.ID { return utxo.TxID.Prefix(uint64(utxo.OutputIndex)) }
+
+// Verify implements the verify.Verifiable interface
+func (utxo *UTXO) Verify() error {
+	switch {
+	case utxo == nil:
+		return errNilUTXO
+	case utxo.TxID.IsZero():
+		return errNilTxID
+	case utxo.AssetID.IsZero():
+		return errNilAssetID
+	default:
+		return utxo.Out.Verify()
+	}
+}
@@ -0,0 +1,15 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nftfx
+
+import (
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+// Credential holds the signatures that authorize consuming an output of this
+// fx. It's a distinct type from the secp256k1fx credential, so that the VM can
+// tell which fx a credential belongs to.
+type Credential struct {
+	secp256k1fx.Credential `serialize:"true"`
+}
@@ -0,0 +1,165 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nftfx
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/ava-labs/gecko/vms/components/verify"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+var (
+	errWrongTxType         = errors.New("wrong tx type")
+	errWrongUTXOType       = errors.New("wrong utxo type")
+	errWrongOutputType     = errors.New("wrong output type")
+	errWrongInputType      = errors.New("wrong input type")
+	errWrongCredentialType = errors.New("wrong credential type")
+
+	errWrongNumberOfOutputs     = errors.New("wrong number of outputs for an operation")
+	errWrongNumberOfInputs      = errors.New("wrong number of inputs for an operation")
+	errWrongNumberOfCredentials = errors.New("wrong number of credentials for an operation")
+
+	errWrongMintCreated     = errors.New("wrong mint output created from the operation")
+	errWrongGroupID         = errors.New("output has a different group ID than expected")
+	errWrongPayload         = errors.New("output has a different payload than expected")
+	errCantTransfer         = errors.New("nfts can only be transferred by operations")
+	errUnknownOperationType = errors.New("unknown operation type")
+)
+
+// Fx is the feature extension of non-fungible tokens. An NFT asset has mint
+// outputs, each of which can mint the NFTs of one group. NFTs are unique, and
+// keep their group ID and payload for as long as they exist. Both minting and
+// transferring NFTs are operations, as NFTs have no amounts that a transfer
+// could be balanced by.
+//
+// Signatures are verified the way secp256k1fx verifies them.
+type Fx struct{ secp256k1fx.Fx }
+
+// Initialize ...
+func (fx *Fx) Initialize(vmIntf interface{}) error {
+	if err := fx.InitializeVM(vmIntf); err != nil {
+		return err
+	}
+
+	c := vmIntf.(secp256k1fx.VM).Codec()
+	c.RegisterType(&MintOutput{})
+	c.RegisterType(&TransferOutput{})
+	c.RegisterType(&MintInput{})
+	c.RegisterType(&TransferInput{})
+	c.RegisterType(&Credential{})
+	return nil
+}
+
+// VerifyOperation verifies either the minting of NFTs or the transfer of one.
+//
+// A mint operation consumes a MintOutput with a MintInput. It must create the
+// same MintOutput again, and NFTs of the output's group.
+//
+// A transfer operation consumes a TransferOutput with a TransferInput. It must
+// create one TransferOutput with the same group ID and payload.
+func (fx *Fx) VerifyOperation(txIntf interface{}, utxosIntf, insIntf, credsIntf, outsIntf []interface{}) error {
+	tx, ok := txIntf.(secp256k1fx.Tx)
+	if !ok {
+		return errWrongTxType
+	}
+
+	if len(utxosIntf) != 1 || len(insIntf) != 1 {
+		return errWrongNumberOfInputs
+	}
+	if len(credsIntf) != 1 {
+		return errWrongNumberOfCredentials
+	}
+	cred, ok := credsIntf[0].(*Credential)
+	if !ok {
+		return errWrongCredentialType
+	}
+
+	outs := make([]verify.Verifiable, len(outsIntf))
+	for i, outIntf := range outsIntf {
+		out, ok := outIntf.(verify.Verifiable)
+		if !ok {
+			return errWrongOutputType
+		}
+		outs[i] = out
+	}
+
+	switch in := insIntf[0].(type) {
+	case *MintInput:
+		utxo, ok := utxosIntf[0].(*MintOutput)
+		if !ok {
+			return errWrongUTXOType
+		}
+		return fx.verifyMint(tx, utxo, in, cred, outs)
+	case *TransferInput:
+		utxo, ok := utxosIntf[0].(*TransferOutput)
+		if !ok {
+			return errWrongUTXOType
+		}
+		return fx.verifyTransfer(tx, utxo, in, cred, outs)
+	default:
+		return errUnknownOperationType
+	}
+}
+
+func (fx *Fx) verifyMint(tx secp256k1fx.Tx, utxo *MintOutput, in *MintInput, cred *Credential, outs []verify.Verifiable) error {
+	if err := verify.All(utxo, in, cred); err != nil {
+		return err
+	}
+	if len(outs) < 2 {
+		return errWrongNumberOfOutputs
+	}
+
+	numMints := 0
+	for _, outIntf := range outs {
+		if err := outIntf.Verify(); err != nil {
+			return err
+		}
+		switch out := outIntf.(type) {
+		case *MintOutput:
+			if out.GroupID != utxo.GroupID || !out.OutputOwners.Equals(&utxo.OutputOwners) {
+				return errWrongMintCreated
+			}
+			numMints++
+		case *TransferOutput:
+			if out.GroupID != utxo.GroupID {
+				return errWrongGroupID
+			}
+		default:
+			return errWrongOutputType
+		}
+	}
+	if numMints != 1 {
+		return errWrongMintCreated
+	}
+
+	return fx.VerifyCredentials(tx, &utxo.OutputOwners, &in.Input, &cred.Credential)
+}
+
+func (fx *Fx) verifyTransfer(tx secp256k1fx.Tx, utxo *TransferOutput, in *TransferInput, cred *Credential, outs []verify.Verifiable) error {
+	if err := verify.All(utxo, in, cred); err != nil {
+		return err
+	}
+	if len(outs) != 1 {
+		return errWrongNumberOfOutputs
+	}
+	out, ok := outs[0].(*TransferOutput)
+	if !ok {
+		return errWrongOutputType
+	}
+	switch err := out.Verify(); {
+	case err != nil:
+		return err
+	case out.GroupID != utxo.GroupID:
+		return errWrongGroupID
+	case !bytes.Equal(out.Payload, utxo.Payload):
+		return errWrongPayload
+	}
+
+	return fx.VerifyCredentials(tx, &utxo.OutputOwners, &in.Input, &cred.Credential)
+}
+
+// VerifyTransfer always fails, as NFTs can only be transferred by operations
+func (fx *Fx) VerifyTransfer(_, _, _, _ interface{}) error { return errCantTransfer }
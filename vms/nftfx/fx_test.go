@@ -0,0 +1,372 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nftfx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/timer"
+	"github.com/ava-labs/gecko/vms/components/codec"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+var (
+	txBytes  = []byte{0, 1, 2, 3, 4, 5}
+	sigBytes = [crypto.SECP256K1RSigLen]byte{
+		0x0e, 0x33, 0x4e, 0xbc, 0x67, 0xa7, 0x3f, 0xe8,
+		0x24, 0x33, 0xac, 0xa3, 0x47, 0x88, 0xa6, 0x3d,
+		0x58, 0xe5, 0x8e, 0xf0, 0x3a, 0xd5, 0x84, 0xf1,
+		0xbc, 0xa3, 0xb2, 0xd2, 0x5d, 0x51, 0xd6, 0x9b,
+		0x0f, 0x28, 0x5d, 0xcd, 0x3f, 0x71, 0x17, 0x0a,
+		0xf9, 0xbf, 0x2d, 0xb1, 0x10, 0x26, 0x5c, 0xe9,
+		0xdc, 0xc3, 0x9d, 0x7a, 0x01, 0x50, 0x9d, 0xe8,
+		0x35, 0xbd, 0xcb, 0x29, 0x3a, 0xd1, 0x49, 0x32,
+		0x00,
+	}
+	addrBytes = [hashing.AddrLen]byte{
+		0x01, 0x5c, 0xce, 0x6c, 0x55, 0xd6, 0xb5, 0x09,
+		0x84, 0x5c, 0x8c, 0x4e, 0x30, 0xbe, 0xd9, 0x8d,
+		0x39, 0x1a, 0xe7, 0xf0,
+	}
+)
+
+type testVM struct{ clock timer.Clock }
+
+func (vm *testVM) Codec() codec.Codec { return codec.NewDefault() }
+
+func (vm *testVM) Clock() *timer.Clock { return &vm.clock }
+
+type testTx struct{ bytes []byte }
+
+func (tx *testTx) UnsignedBytes() []byte { return tx.bytes }
+
+func newTestFx(t *testing.T) *Fx {
+	vm := testVM{}
+	vm.clock.Set(time.Date(2019, time.January, 19, 16, 25, 17, 3, time.UTC))
+	fx := &Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	return fx
+}
+
+func testOwners() secp256k1fx.OutputOwners {
+	return secp256k1fx.OutputOwners{
+		Threshold: 1,
+		Addrs: []ids.ShortID{
+			ids.NewShortID(addrBytes),
+		},
+	}
+}
+
+func testInput() secp256k1fx.Input {
+	return secp256k1fx.Input{SigIndices: []uint32{0}}
+}
+
+func testCredential() *Credential {
+	return &Credential{Credential: secp256k1fx.Credential{
+		Sigs: [][crypto.SECP256K1RSigLen]byte{
+			sigBytes,
+		},
+	}}
+}
+
+func TestFxInitialize(t *testing.T) {
+	newTestFx(t)
+}
+
+func TestFxInitializeInvalid(t *testing.T) {
+	fx := Fx{}
+	if err := fx.Initialize(nil); err == nil {
+		t.Fatalf("Should have returned an error")
+	}
+}
+
+func TestFxVerifyMintOperation(t *testing.T) {
+	fx := newTestFx(t)
+	tx := &testTx{bytes: txBytes}
+	utxo := &MintOutput{
+		GroupID:      1,
+		OutputOwners: testOwners(),
+	}
+	in := &MintInput{Input: testInput()}
+	outs := []interface{}{
+		&MintOutput{
+			GroupID:      1,
+			OutputOwners: testOwners(),
+		},
+		&TransferOutput{
+			GroupID:      1,
+			Payload:      []byte{'h', 'i'},
+			OutputOwners: secp256k1fx.OutputOwners{},
+		},
+	}
+
+	err := fx.VerifyOperation(tx, []interface{}{utxo}, []interface{}{in}, []interface{}{testCredential()}, outs)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFxVerifyMintOperationWrongGroupID(t *testing.T) {
+	fx := newTestFx(t)
+	tx := &testTx{bytes: txBytes}
+	utxo := &MintOutput{
+		GroupID:      1,
+		OutputOwners: testOwners(),
+	}
+	in := &MintInput{Input: testInput()}
+	outs := []interface{}{
+		&MintOutput{
+			GroupID:      1,
+			OutputOwners: testOwners(),
+		},
+		&TransferOutput{
+			GroupID: 2,
+			Payload: []byte{'h', 'i'},
+		},
+	}
+
+	err := fx.VerifyOperation(tx, []interface{}{utxo}, []interface{}{in}, []interface{}{testCredential()}, outs)
+	if err != errWrongGroupID {
+		t.Fatalf("Should have errored with %s but errored with %v", errWrongGroupID, err)
+	}
+}
+
+func TestFxVerifyMintOperationNoMintCreated(t *testing.T) {
+	fx := newTestFx(t)
+	tx := &testTx{bytes: txBytes}
+	utxo := &MintOutput{
+		GroupID:      1,
+		OutputOwners: testOwners(),
+	}
+	in := &MintInput{Input: testInput()}
+	outs := []interface{}{
+		&TransferOutput{
+			GroupID: 1,
+			Payload: []byte{'h', 'i'},
+		},
+		&TransferOutput{
+			GroupID: 1,
+			Payload: []byte{'y', 'o'},
+		},
+	}
+
+	err := fx.VerifyOperation(tx, []interface{}{utxo}, []interface{}{in}, []interface{}{testCredential()}, outs)
+	if err != errWrongMintCreated {
+		t.Fatalf("Should have errored with %s but errored with %v", errWrongMintCreated, err)
+	}
+}
+
+func TestFxVerifyMintOperationChangedMinters(t *testing.T) {
+	fx := newTestFx(t)
+	tx := &testTx{bytes: txBytes}
+	utxo := &MintOutput{
+		GroupID:      1,
+		OutputOwners: testOwners(),
+	}
+	in := &MintInput{Input: testInput()}
+	outs := []interface{}{
+		&MintOutput{
+			GroupID:      1,
+			OutputOwners: secp256k1fx.OutputOwners{},
+		},
+		&TransferOutput{
+			GroupID: 1,
+			Payload: []byte{'h', 'i'},
+		},
+	}
+
+	err := fx.VerifyOperation(tx, []interface{}{utxo}, []interface{}{in}, []interface{}{testCredential()}, outs)
+	if err != errWrongMintCreated {
+		t.Fatalf("Should have errored with %s but errored with %v", errWrongMintCreated, err)
+	}
+}
+
+func TestFxVerifyMintOperationWrongCredential(t *testing.T) {
+	fx := newTestFx(t)
+	tx := &testTx{bytes: txBytes}
+	utxo := &MintOutput{
+		GroupID:      1,
+		OutputOwners: testOwners(),
+	}
+	in := &MintInput{Input: testInput()}
+	cred := &testCredential().Credential
+	outs := []interface{}{
+		&MintOutput{
+			GroupID:      1,
+			OutputOwners: testOwners(),
+		},
+		&TransferOutput{
+			GroupID: 1,
+			Payload: []byte{'h', 'i'},
+		},
+	}
+
+	err := fx.VerifyOperation(tx, []interface{}{utxo}, []interface{}{in}, []interface{}{cred}, outs)
+	if err != errWrongCredentialType {
+		t.Fatalf("Should have errored with %s but errored with %v", errWrongCredentialType, err)
+	}
+}
+
+func TestFxVerifyMintOperationWrongSignature(t *testing.T) {
+	fx := newTestFx(t)
+	tx := &testTx{bytes: []byte{1}}
+	utxo := &MintOutput{
+		GroupID:      1,
+		OutputOwners: testOwners(),
+	}
+	in := &MintInput{Input: testInput()}
+	outs := []interface{}{
+		&MintOutput{
+			GroupID:      1,
+			OutputOwners: testOwners(),
+		},
+		&TransferOutput{
+			GroupID: 1,
+			Payload: []byte{'h', 'i'},
+		},
+	}
+
+	err := fx.VerifyOperation(tx, []interface{}{utxo}, []interface{}{in}, []interface{}{testCredential()}, outs)
+	if err == nil {
+		t.Fatalf("Should have failed verification due to the tx not being signed by the minter")
+	}
+}
+
+func TestFxVerifyTransferOperation(t *testing.T) {
+	fx := newTestFx(t)
+	tx := &testTx{bytes: txBytes}
+	utxo := &TransferOutput{
+		GroupID:      1,
+		Payload:      []byte{'h', 'i'},
+		OutputOwners: testOwners(),
+	}
+	in := &TransferInput{Input: testInput()}
+	outs := []interface{}{
+		&TransferOutput{
+			GroupID: 1,
+			Payload: []byte{'h', 'i'},
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{ids.ShortEmpty},
+			},
+		},
+	}
+
+	err := fx.VerifyOperation(tx, []interface{}{utxo}, []interface{}{in}, []interface{}{testCredential()}, outs)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFxVerifyTransferOperationWrongPayload(t *testing.T) {
+	fx := newTestFx(t)
+	tx := &testTx{bytes: txBytes}
+	utxo := &TransferOutput{
+		GroupID:      1,
+		Payload:      []byte{'h', 'i'},
+		OutputOwners: testOwners(),
+	}
+	in := &TransferInput{Input: testInput()}
+	outs := []interface{}{
+		&TransferOutput{
+			GroupID: 1,
+			Payload: []byte{'y', 'o'},
+		},
+	}
+
+	err := fx.VerifyOperation(tx, []interface{}{utxo}, []interface{}{in}, []interface{}{testCredential()}, outs)
+	if err != errWrongPayload {
+		t.Fatalf("Should have errored with %s but errored with %v", errWrongPayload, err)
+	}
+}
+
+func TestFxVerifyTransferOperationWrongGroupID(t *testing.T) {
+	fx := newTestFx(t)
+	tx := &testTx{bytes: txBytes}
+	utxo := &TransferOutput{
+		GroupID:      1,
+		Payload:      []byte{'h', 'i'},
+		OutputOwners: testOwners(),
+	}
+	in := &TransferInput{Input: testInput()}
+	outs := []interface{}{
+		&TransferOutput{
+			GroupID: 2,
+			Payload: []byte{'h', 'i'},
+		},
+	}
+
+	err := fx.VerifyOperation(tx, []interface{}{utxo}, []interface{}{in}, []interface{}{testCredential()}, outs)
+	if err != errWrongGroupID {
+		t.Fatalf("Should have errored with %s but errored with %v", errWrongGroupID, err)
+	}
+}
+
+func TestFxVerifyTransferOperationDuplicated(t *testing.T) {
+	fx := newTestFx(t)
+	tx := &testTx{bytes: txBytes}
+	utxo := &TransferOutput{
+		GroupID:      1,
+		Payload:      []byte{'h', 'i'},
+		OutputOwners: testOwners(),
+	}
+	in := &TransferInput{Input: testInput()}
+	outs := []interface{}{
+		&TransferOutput{
+			GroupID: 1,
+			Payload: []byte{'h', 'i'},
+		},
+		&TransferOutput{
+			GroupID: 1,
+			Payload: []byte{'h', 'i'},
+		},
+	}
+
+	err := fx.VerifyOperation(tx, []interface{}{utxo}, []interface{}{in}, []interface{}{testCredential()}, outs)
+	if err != errWrongNumberOfOutputs {
+		t.Fatalf("Should have errored with %s but errored with %v", errWrongNumberOfOutputs, err)
+	}
+}
+
+func TestFxVerifyOperationWrongUTXOType(t *testing.T) {
+	fx := newTestFx(t)
+	tx := &testTx{bytes: txBytes}
+	utxo := &MintOutput{
+		GroupID:      1,
+		OutputOwners: testOwners(),
+	}
+	in := &TransferInput{Input: testInput()}
+	outs := []interface{}{
+		&TransferOutput{
+			GroupID: 1,
+			Payload: []byte{'h', 'i'},
+		},
+	}
+
+	err := fx.VerifyOperation(tx, []interface{}{utxo}, []interface{}{in}, []interface{}{testCredential()}, outs)
+	if err != errWrongUTXOType {
+		t.Fatalf("Should have errored with %s but errored with %v", errWrongUTXOType, err)
+	}
+}
+
+func TestFxVerifyTransfer(t *testing.T) {
+	fx := newTestFx(t)
+	tx := &testTx{bytes: txBytes}
+	utxo := &TransferOutput{
+		GroupID:      1,
+		Payload:      []byte{'h', 'i'},
+		OutputOwners: testOwners(),
+	}
+	in := &TransferInput{Input: testInput()}
+
+	if err := fx.VerifyTransfer(tx, utxo, in, testCredential()); err != errCantTransfer {
+		t.Fatalf("Should have errored with %s but errored with %v", errCantTransfer, err)
+	}
+}
@@ -0,0 +1,15 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nftfx
+
+import (
+	"testing"
+)
+
+func TestMintOutputVerifyNil(t *testing.T) {
+	out := (*MintOutput)(nil)
+	if err := out.Verify(); err == nil {
+		t.Fatalf("MintOutput.Verify should have returned an error due to an nil output")
+	}
+}
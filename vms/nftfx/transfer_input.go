@@ -0,0 +1,13 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nftfx
+
+import (
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+// TransferInput consumes a TransferOutput
+type TransferInput struct {
+	secp256k1fx.Input `serialize:"true"`
+}
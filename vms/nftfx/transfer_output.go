@@ -0,0 +1,40 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nftfx
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+const (
+	// MaxPayloadSize is the maximum size of the payload of an NFT
+	MaxPayloadSize = 1 << 10
+)
+
+var (
+	errNilOutput       = errors.New("nil output")
+	errPayloadTooLarge = errors.New("payload too large")
+)
+
+// TransferOutput is an NFT of the group [GroupID] that's owned by its owners.
+// [Payload] is the content of the NFT, which never changes.
+type TransferOutput struct {
+	GroupID                  uint32 `serialize:"true"`
+	Payload                  []byte `serialize:"true"`
+	secp256k1fx.OutputOwners `serialize:"true"`
+}
+
+// Verify ...
+func (out *TransferOutput) Verify() error {
+	switch {
+	case out == nil:
+		return errNilOutput
+	case len(out.Payload) > MaxPayloadSize:
+		return errPayloadTooLarge
+	default:
+		return out.OutputOwners.Verify()
+	}
+}
@@ -0,0 +1,37 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package nftfx
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+func TestTransferOutputVerifyNil(t *testing.T) {
+	out := (*TransferOutput)(nil)
+	if err := out.Verify(); err == nil {
+		t.Fatalf("TransferOutput.Verify should have returned an error due to an nil output")
+	}
+}
+
+func TestTransferOutputVerifyPayloadTooLarge(t *testing.T) {
+	out := &TransferOutput{
+		Payload:      make([]byte, MaxPayloadSize+1),
+		OutputOwners: secp256k1fx.OutputOwners{},
+	}
+	if err := out.Verify(); err != errPayloadTooLarge {
+		t.Fatalf("Should have errored with %s but errored with %v", errPayloadTooLarge, err)
+	}
+}
+
+func TestTransferOutputVerifyMaxPayload(t *testing.T) {
+	out := &TransferOutput{
+		Payload:      make([]byte, MaxPayloadSize),
+		OutputOwners: secp256k1fx.OutputOwners{},
+	}
+	if err := out.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -11,11 +11,6 @@ import (
 
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/utils/math"
-	"github.com/ava-labs/gecko/utils/units"
-)
-
-var (
-	txFee = uint64(0) * units.MicroAva // The transaction fee
 )
 
 var (
@@ -40,8 +35,8 @@ type Account struct {
 }
 
 // Remove generates a new account state from removing [amount + txFee] from [a]'s balance.
-// [nonce] is [a]'s next unused nonce
-func (a Account) Remove(amount, nonce uint64) (Account, error) {
+// [txFee] is burnt. [nonce] is [a]'s next unused nonce
+func (a Account) Remove(amount, txFee, nonce uint64) (Account, error) {
 	// Ensure account is in a valid state
 	if err := a.Verify(); err != nil {
 		return Account{}, err
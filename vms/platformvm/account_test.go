@@ -29,7 +29,7 @@ func TestAccountRemoveMaxNonce(t *testing.T) {
 		Balance: defaultBalance,
 	}
 
-	_, err := account.Remove(defaultBalance-txFee, account.Nonce)
+	_, err := account.Remove(defaultBalance-defaultTxFee, defaultTxFee, account.Nonce)
 	if err == nil {
 		t.Fatal("should have failed because account is out of nonces")
 	}
@@ -42,7 +42,7 @@ func TestAccountRemoveWrongNonce(t *testing.T) {
 		Balance: defaultBalance,
 	}
 
-	_, err := account.Remove(defaultBalance-txFee, account.Nonce)
+	_, err := account.Remove(defaultBalance-defaultTxFee, defaultTxFee, account.Nonce)
 	if err == nil {
 		t.Fatal("should have failed because nonce in argument is wrong")
 	}
@@ -55,7 +55,7 @@ func TestAccountRemoveLockFunds(t *testing.T) {
 		Balance: defaultBalance,
 	}
 
-	_, err := account.Remove(defaultBalance-txFee-1, account.Nonce+1)
+	_, err := account.Remove(defaultBalance-defaultTxFee-1, defaultTxFee, account.Nonce+1)
 	if err == nil {
 		t.Fatal("should have failed because funds would be locked")
 	}
@@ -68,24 +68,20 @@ func TestAccountRemoveInvalid(t *testing.T) {
 		Balance: defaultBalance,
 	}
 
-	_, err := account.Remove(defaultBalance-txFee, account.Nonce+1)
+	_, err := account.Remove(defaultBalance-defaultTxFee, defaultTxFee, account.Nonce+1)
 	if err == nil {
 		t.Fatal("should have failed because account is invalid (ID is empty)")
 	}
 }
 
 func TestRemoveOverflow(t *testing.T) {
-	// this test is only meaningful if txFee is non-zero
-	if txFee == 0 {
-		return
-	}
 	account := Account{
 		Address: defaultKey.PublicKey().Address(),
 		Nonce:   defaultNonce,
 		Balance: math.MaxUint64,
 	}
 
-	_, err := account.Remove(account.Balance, account.Nonce+1)
+	_, err := account.Remove(account.Balance, defaultTxFee, account.Nonce+1)
 	if err == nil {
 		t.Fatal("should have failed because amount to remove plus tx fee overflows")
 	}
@@ -99,7 +95,7 @@ func TestRemoveAllFunds(t *testing.T) {
 		Balance: defaultBalance,
 	}
 
-	account, err := account.Remove(defaultBalance-txFee, account.Nonce+1)
+	account, err := account.Remove(defaultBalance-defaultTxFee, defaultTxFee, account.Nonce+1)
 	if err != nil {
 		t.Fatal(err)
 	}
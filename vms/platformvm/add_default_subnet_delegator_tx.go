@@ -68,16 +68,10 @@ func (tx *addDefaultSubnetDelegatorTx) SyntacticVerify() error {
 		return errWrongNetworkID
 	case tx.NodeID.IsZero():
 		return errInvalidID
-	case tx.Wght < MinimumStakeAmount: // Ensure validator is staking at least the minimum amount
+	case tx.Wght == 0: // Ensure delegator is staking something
 		return errWeightTooSmall
-	}
-
-	// Ensure staking length is not too short or long
-	stakingDuration := tx.Duration()
-	if stakingDuration < MinimumStakingDuration {
+	case tx.End <= tx.Start: // Ensure delegator stakes for some time
 		return errStakeTooShort
-	} else if stakingDuration > MaximumStakingDuration {
-		return errStakeTooLong
 	}
 
 	unsignedIntf := interface{}(&tx.UnsignedAddDefaultSubnetDelegatorTx)
@@ -115,6 +109,15 @@ func (tx *addDefaultSubnetDelegatorTx) SemanticVerify(db database.Database) (*ve
 			validatorStartTime)
 	}
 
+	// Ensure the delegator stakes enough, for neither too short nor too long
+	params, err := tx.vm.getStakingParameters(db)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if err := params.verifyStake(tx.Wght, tx.Duration()); err != nil {
+		return nil, nil, nil, nil, err
+	}
+
 	// Get the account that is paying the transaction fee and, if the proposal is to add a validator
 	// to the default subnet, providing the staked $AVA.
 	// The ID of this account is the address associated with the public key that signed this tx
@@ -126,7 +129,7 @@ func (tx *addDefaultSubnetDelegatorTx) SemanticVerify(db database.Database) (*ve
 
 	// The account if this block's proposal is committed and the validator is added
 	// to the pending validator set. (Increase the account's nonce; decrease its balance.)
-	newAccount, err := account.Remove(0, tx.Nonce) // Remove also removes the fee
+	newAccount, err := account.Remove(0, tx.vm.txFee, tx.Nonce) // Remove also removes the fee
 	if err != nil {
 		return nil, nil, nil, nil, err
 	}
@@ -173,6 +176,9 @@ func (tx *addDefaultSubnetDelegatorTx) SemanticVerify(db database.Database) (*ve
 	if err := tx.vm.putAccount(onCommitDB, newAccount); err != nil {
 		return nil, nil, nil, nil, err
 	}
+	if err := tx.vm.burn(onCommitDB, tx.vm.txFee); err != nil {
+		return nil, nil, nil, nil, err
+	}
 
 	// If this proposal is aborted, chain state doesn't change
 	onAbortDB := versiondb.New(db)
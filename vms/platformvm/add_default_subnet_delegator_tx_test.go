@@ -79,7 +79,7 @@ func TestAddDefaultSubnetDelegatorTxSyntacticVerify(t *testing.T) {
 	// Case 5: Not enough weight
 	tx, err = vm.newAddDefaultSubnetDelegatorTx(
 		defaultNonce+1,
-		MinimumStakeAmount-1,
+		DefaultStakingParameters.MinimumStakeAmount-1,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateEndTime.Unix()),
 		defaultKey.PublicKey().Address(),
@@ -90,7 +90,7 @@ func TestAddDefaultSubnetDelegatorTxSyntacticVerify(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if err := tx.SyntacticVerify(); err == nil {
+	if _, _, _, _, err := tx.SemanticVerify(vm.DB); err == nil {
 		t.Fatal("should have errored because of not enough weight")
 	}
 
@@ -99,7 +99,7 @@ func TestAddDefaultSubnetDelegatorTxSyntacticVerify(t *testing.T) {
 		defaultNonce+1,
 		defaultStakeAmount,
 		uint64(defaultValidateStartTime.Unix()),
-		uint64(defaultValidateStartTime.Add(MinimumStakingDuration).Unix())-1,
+		uint64(defaultValidateStartTime.Add(DefaultStakingParameters.MinimumStakingDuration).Unix())-1,
 		defaultKey.PublicKey().Address(),
 		defaultKey.PublicKey().Address(),
 		testNetworkID,
@@ -108,7 +108,7 @@ func TestAddDefaultSubnetDelegatorTxSyntacticVerify(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	err = tx.SyntacticVerify()
+	_, _, _, _, err = tx.SemanticVerify(vm.DB)
 	if err == nil {
 		t.Fatal("should have errored because validation length too short")
 	}
@@ -118,7 +118,7 @@ func TestAddDefaultSubnetDelegatorTxSyntacticVerify(t *testing.T) {
 		defaultNonce+1,
 		defaultStakeAmount,
 		uint64(defaultValidateStartTime.Unix()),
-		uint64(defaultValidateStartTime.Add(MaximumStakingDuration).Unix())+1,
+		uint64(defaultValidateStartTime.Add(DefaultStakingParameters.MaximumStakingDuration).Unix())+1,
 		defaultKey.PublicKey().Address(),
 		defaultKey.PublicKey().Address(),
 		testNetworkID,
@@ -127,7 +127,7 @@ func TestAddDefaultSubnetDelegatorTxSyntacticVerify(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	err = tx.SyntacticVerify()
+	_, _, _, _, err = tx.SemanticVerify(vm.DB)
 	if err == nil {
 		t.Fatal("should have errored because validation length too long")
 	}
@@ -206,7 +206,7 @@ func TestAddDefaultSubnetDelegatorTxSemanticVerify(t *testing.T) {
 
 	// starts validating default subnet 10 seconds after genesis
 	DSStartTime := defaultGenesisTime.Add(10 * time.Second)
-	DSEndTime := DSStartTime.Add(5 * MinimumStakingDuration)
+	DSEndTime := DSStartTime.Add(5 * DefaultStakingParameters.MinimumStakingDuration)
 
 	addDSTx, err := vm.newAddDefaultSubnetValidatorTx(
 		defaultNonce+1,                   // nonce
@@ -328,7 +328,7 @@ func TestAddDefaultSubnetDelegatorTxSemanticVerify(t *testing.T) {
 		defaultNonce+1,              // nonce
 		defaultStakeAmount,          // weight
 		uint64(newTimestamp.Unix()), // start time
-		uint64(newTimestamp.Add(MinimumStakingDuration).Unix()), // end time
+		uint64(newTimestamp.Add(DefaultStakingParameters.MinimumStakingDuration).Unix()), // end time
 		defaultKey.PublicKey().Address(),                        // node ID
 		defaultKey.PublicKey().Address(),                        // destination
 		testNetworkID,                                           // network ID
@@ -348,9 +348,6 @@ func TestAddDefaultSubnetDelegatorTxSemanticVerify(t *testing.T) {
 	}
 
 	// Case 7: Account that pays tx fee doesn't have enough $AVA to pay tx fee
-	txFeeSaved := txFee
-	txFee = 1 // Do this so test works even when txFee is 0
-
 	// Create new key whose account has no $AVA
 	factory := crypto.FactorySECP256K1R{}
 	newAcctKey, err := factory.NewPrivateKey()
@@ -375,5 +372,4 @@ func TestAddDefaultSubnetDelegatorTxSemanticVerify(t *testing.T) {
 	if err == nil {
 		t.Fatal("should have failed verification because payer account has no $AVA to pay fee")
 	}
-	txFee = txFeeSaved // Reset tx fee
 }
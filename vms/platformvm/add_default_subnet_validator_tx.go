@@ -77,18 +77,12 @@ func (tx *addDefaultSubnetValidatorTx) SyntacticVerify() error {
 		return errInvalidID
 	case tx.Destination.IsZero():
 		return errInvalidID
-	case tx.Wght < MinimumStakeAmount: // Ensure validator is staking at least the minimum amount
+	case tx.Wght == 0: // Ensure validator is staking something
 		return errWeightTooSmall
 	case tx.Shares > NumberOfShares: // Ensure delegators shares are in the allowed amount
 		return errTooManyShares
-	}
-
-	// Ensure staking length is not too short or long
-	stakingDuration := tx.Duration()
-	if stakingDuration < MinimumStakingDuration {
+	case tx.End <= tx.Start: // Ensure validator stakes for some time
 		return errStakeTooShort
-	} else if stakingDuration > MaximumStakingDuration {
-		return errStakeTooLong
 	}
 
 	// Byte representation of the unsigned transaction
@@ -125,6 +119,15 @@ func (tx *addDefaultSubnetValidatorTx) SemanticVerify(db database.Database) (*ve
 			startTime)
 	}
 
+	// Ensure the validator stakes enough, for neither too short nor too long
+	params, err := tx.vm.getStakingParameters(db)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if err := params.verifyStake(tx.Wght, tx.Duration()); err != nil {
+		return nil, nil, nil, nil, err
+	}
+
 	// Get the account that is paying the transaction fee and, if the proposal is to add a validator
 	// to the default subnet, providing the staked $AVA.
 	// The ID of this account is the address associated with the public key that signed this tx
@@ -140,7 +143,7 @@ func (tx *addDefaultSubnetValidatorTx) SemanticVerify(db database.Database) (*ve
 
 	// The account if this block's proposal is committed and the validator is added
 	// to the pending validator set. (Increase the account's nonce; decrease its balance.)
-	newAccount, err := account.Remove(amount, tx.Nonce)
+	newAccount, err := account.Remove(amount, tx.vm.txFee, tx.Nonce)
 	if err != nil {
 		return nil, nil, nil, nil, err
 	}
@@ -180,6 +183,9 @@ func (tx *addDefaultSubnetValidatorTx) SemanticVerify(db database.Database) (*ve
 	if err := tx.vm.putAccount(onCommitDB, newAccount); err != nil {
 		return nil, nil, nil, nil, err
 	}
+	if err := tx.vm.burn(onCommitDB, tx.vm.txFee); err != nil {
+		return nil, nil, nil, nil, err
+	}
 
 	// If this proposal is aborted, chain state doesn't change
 	onAbortDB := versiondb.New(db)
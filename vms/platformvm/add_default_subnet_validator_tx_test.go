@@ -101,7 +101,7 @@ func TestAddDefaultSubnetValidatorTxSyntacticVerify(t *testing.T) {
 	// Case 6: Stake amount too small
 	tx, err = vm.newAddDefaultSubnetValidatorTx(
 		defaultNonce+1,
-		MinimumStakeAmount-1,
+		DefaultStakingParameters.MinimumStakeAmount-1,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateEndTime.Unix()),
 		defaultKey.PublicKey().Address(),
@@ -113,7 +113,7 @@ func TestAddDefaultSubnetValidatorTxSyntacticVerify(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if err := tx.SyntacticVerify(); err == nil {
+	if _, _, _, _, err := tx.SemanticVerify(vm.DB); err == nil {
 		t.Fatal("should have errored because stake amount too small")
 	}
 
@@ -141,7 +141,7 @@ func TestAddDefaultSubnetValidatorTxSyntacticVerify(t *testing.T) {
 		defaultNonce+1,
 		defaultStakeAmount,
 		uint64(defaultValidateStartTime.Unix()),
-		uint64(defaultValidateStartTime.Add(MinimumStakingDuration).Unix())-1,
+		uint64(defaultValidateStartTime.Add(DefaultStakingParameters.MinimumStakingDuration).Unix())-1,
 		defaultKey.PublicKey().Address(),
 		defaultKey.PublicKey().Address(),
 		NumberOfShares,
@@ -151,7 +151,7 @@ func TestAddDefaultSubnetValidatorTxSyntacticVerify(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if err := tx.SyntacticVerify(); err == nil {
+	if _, _, _, _, err := tx.SemanticVerify(vm.DB); err == nil {
 		t.Fatal("should have errored because validation length too short")
 	}
 
@@ -179,7 +179,7 @@ func TestAddDefaultSubnetValidatorTxSyntacticVerify(t *testing.T) {
 		defaultNonce+1,
 		defaultStakeAmount,
 		uint64(defaultValidateStartTime.Unix()),
-		uint64(defaultValidateStartTime.Add(MaximumStakingDuration).Unix())+1,
+		uint64(defaultValidateStartTime.Add(DefaultStakingParameters.MaximumStakingDuration).Unix())+1,
 		defaultKey.PublicKey().Address(),
 		defaultKey.PublicKey().Address(),
 		NumberOfShares,
@@ -189,7 +189,7 @@ func TestAddDefaultSubnetValidatorTxSyntacticVerify(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if err := tx.SyntacticVerify(); err == nil {
+	if _, _, _, _, err := tx.SemanticVerify(vm.DB); err == nil {
 		t.Fatal("should have errored because validation length too long")
 	}
 
@@ -239,7 +239,7 @@ func TestAddDefaultSubnetValidatorTxSemanticVerify(t *testing.T) {
 	// Case 2: Validator doesn't have enough $AVA to cover stake amount
 	tx, err = vm.newAddDefaultSubnetValidatorTx(
 		defaultNonce+1,
-		defaultBalance-txFee+1,
+		defaultBalance-defaultTxFee+1,
 		uint64(defaultValidateStartTime.Unix()),
 		uint64(defaultValidateEndTime.Unix()),
 		defaultKey.PublicKey().Address(),
@@ -284,7 +284,7 @@ func TestAddDefaultSubnetValidatorTxSemanticVerify(t *testing.T) {
 		defaultNonce+1,           // nonce
 		defaultStakeAmount,       // stake amount
 		uint64(startTime.Unix()), // start time
-		uint64(startTime.Add(MinimumStakingDuration).Unix()), // end time
+		uint64(startTime.Add(DefaultStakingParameters.MinimumStakingDuration).Unix()), // end time
 		key.PublicKey().Address(),                            // node ID
 		defaultKey.PublicKey().Address(),                     // destination
 		NumberOfShares,                                       // shares
@@ -93,18 +93,12 @@ func (tx *addNonDefaultSubnetValidatorTx) SyntacticVerify() error {
 		return errInvalidID
 	case tx.Wght == 0: // Ensure the validator has some weight
 		return errWeightTooSmall
+	case tx.End <= tx.Start: // Ensure the validator validates for some time
+		return errStakeTooShort
 	case !crypto.IsSortedAndUniqueSECP2561RSigs(tx.ControlSigs):
 		return errSigsNotSorted
 	}
 
-	// Ensure staking length is not too short or long
-	stakingDuration := tx.Duration()
-	if stakingDuration < MinimumStakingDuration {
-		return errStakeTooShort
-	} else if stakingDuration > MaximumStakingDuration {
-		return errStakeTooLong
-	}
-
 	// Byte representation of the unsigned transaction
 	unsignedIntf := interface{}(&tx.UnsignedAddNonDefaultSubnetValidatorTx)
 	unsignedBytes, err := Codec.Marshal(&unsignedIntf)
@@ -155,6 +149,15 @@ func (tx *addNonDefaultSubnetValidatorTx) SemanticVerify(db database.Database) (
 		return nil, nil, nil, nil, err
 	}
 
+	// Ensure staking length is not too short or long
+	params, err := tx.vm.getStakingParameters(db)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if err := params.verifyDuration(tx.Duration()); err != nil {
+		return nil, nil, nil, nil, err
+	}
+
 	// Get info about the subnet we're adding a validator to
 	subnets, err := tx.vm.getSubnets(db)
 	if err != nil {
@@ -244,7 +247,7 @@ func (tx *addNonDefaultSubnetValidatorTx) SemanticVerify(db database.Database) (
 
 	// The account if this block's proposal is committed and the validator is added
 	// to the pending validator set. (Increase the account's nonce; decrease its balance.)
-	newAccount, err := account.Remove(0, tx.Nonce) // Remove also removes the fee
+	newAccount, err := account.Remove(0, tx.vm.txFee, tx.Nonce) // Remove also removes the fee
 	if err != nil {
 		return nil, nil, nil, nil, err
 	}
@@ -288,6 +291,9 @@ func (tx *addNonDefaultSubnetValidatorTx) SemanticVerify(db database.Database) (
 	if err := tx.vm.putAccount(onCommitDB, newAccount); err != nil {
 		return nil, nil, nil, nil, fmt.Errorf("couldn't put account: %v", err)
 	}
+	if err := tx.vm.burn(onCommitDB, tx.vm.txFee); err != nil {
+		return nil, nil, nil, nil, err
+	}
 
 	// If this proposal is aborted, chain state doesn't change
 	onAbortDB := versiondb.New(db)
@@ -145,7 +145,7 @@ func TestAddNonDefaultSubnetValidatorTxSyntacticVerify(t *testing.T) {
 		defaultNonce+1,
 		defaultWeight,
 		uint64(defaultValidateStartTime.Unix()),
-		uint64(defaultValidateStartTime.Add(MinimumStakingDuration).Unix())-1,
+		uint64(defaultValidateStartTime.Add(DefaultStakingParameters.MinimumStakingDuration).Unix())-1,
 		defaultKey.PublicKey().Address(),
 		testSubnet1.ID,
 		testNetworkID,
@@ -155,7 +155,7 @@ func TestAddNonDefaultSubnetValidatorTxSyntacticVerify(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	err = tx.SyntacticVerify()
+	_, _, _, _, err = tx.SemanticVerify(vm.DB)
 	if err == nil {
 		t.Fatal("should have errored because validation length too short")
 	}
@@ -165,7 +165,7 @@ func TestAddNonDefaultSubnetValidatorTxSyntacticVerify(t *testing.T) {
 		defaultNonce+1,
 		defaultWeight,
 		uint64(defaultValidateStartTime.Unix()),
-		uint64(defaultValidateStartTime.Add(MaximumStakingDuration).Unix())+1,
+		uint64(defaultValidateStartTime.Add(DefaultStakingParameters.MaximumStakingDuration).Unix())+1,
 		defaultKey.PublicKey().Address(),
 		testSubnet1.ID,
 		testNetworkID,
@@ -175,7 +175,7 @@ func TestAddNonDefaultSubnetValidatorTxSyntacticVerify(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	err = tx.SyntacticVerify()
+	_, _, _, _, err = tx.SemanticVerify(vm.DB)
 	if err == nil {
 		t.Fatal("should have errored because validation length too long")
 	}
@@ -266,7 +266,7 @@ func TestAddNonDefaultSubnetValidatorTxSemanticVerify(t *testing.T) {
 
 	// starts validating default subnet 10 seconds after genesis
 	DSStartTime := defaultGenesisTime.Add(10 * time.Second)
-	DSEndTime := DSStartTime.Add(5 * MinimumStakingDuration)
+	DSEndTime := DSStartTime.Add(5 * DefaultStakingParameters.MinimumStakingDuration)
 
 	addDSTx, err := vm.newAddDefaultSubnetValidatorTx(
 		defaultNonce+1,                   // nonce
@@ -392,7 +392,7 @@ func TestAddNonDefaultSubnetValidatorTxSemanticVerify(t *testing.T) {
 		defaultNonce+1,              // nonce
 		defaultWeight,               // weight
 		uint64(newTimestamp.Unix()), // start time
-		uint64(newTimestamp.Add(MinimumStakingDuration).Unix()), // end time
+		uint64(newTimestamp.Add(DefaultStakingParameters.MinimumStakingDuration).Unix()), // end time
 		defaultKey.PublicKey().Address(),                        // node ID
 		testSubnet1.ID,                                          // subnet ID
 		testNetworkID,                                           // network ID
@@ -413,9 +413,6 @@ func TestAddNonDefaultSubnetValidatorTxSemanticVerify(t *testing.T) {
 	}
 
 	// Case 7: Account that pays tx fee doesn't have enough $AVA to pay tx fee
-	txFeeSaved := txFee
-	txFee = 1 // Do this so test works even when txFee is 0
-
 	// Create new key whose account has no $AVA
 	factory := crypto.FactorySECP256K1R{}
 	newAcctKey, err := factory.NewPrivateKey()
@@ -441,7 +438,6 @@ func TestAddNonDefaultSubnetValidatorTxSemanticVerify(t *testing.T) {
 	if err == nil {
 		t.Fatal("should have failed verification because payer account has no $AVA to pay fee")
 	}
-	txFee = txFeeSaved // Reset tx fee
 
 	// Case 8: Proposed validator already validating the non-default subnet
 	// First, add validator as validator of non-default subnet
@@ -502,7 +498,7 @@ func TestAddNonDefaultSubnetValidatorTxSemanticVerify(t *testing.T) {
 		defaultNonce+1,                    // nonce
 		defaultWeight,                     // weight
 		uint64(defaultGenesisTime.Unix()), // start time
-		uint64(defaultGenesisTime.Add(MinimumStakingDuration).Unix())+1, // end time
+		uint64(defaultGenesisTime.Add(DefaultStakingParameters.MinimumStakingDuration).Unix())+1, // end time
 		keys[0].PublicKey().Address(),                                   // node ID
 		testSubnet1.ID,                                                  // subnet ID
 		testNetworkID,                                                   // network ID
@@ -523,7 +519,7 @@ func TestAddNonDefaultSubnetValidatorTxSemanticVerify(t *testing.T) {
 		defaultNonce+1,                    // nonce
 		defaultWeight,                     // weight
 		uint64(defaultGenesisTime.Unix()), // start time
-		uint64(defaultGenesisTime.Add(MinimumStakingDuration).Unix()), // end time
+		uint64(defaultGenesisTime.Add(DefaultStakingParameters.MinimumStakingDuration).Unix()), // end time
 		keys[0].PublicKey().Address(),                                 // node ID
 		testSubnet1.ID,                                                // subnet ID
 		testNetworkID,                                                 // network ID
@@ -544,7 +540,7 @@ func TestAddNonDefaultSubnetValidatorTxSemanticVerify(t *testing.T) {
 		defaultNonce+1,                    // nonce
 		defaultWeight,                     // weight
 		uint64(defaultGenesisTime.Unix()), // start time
-		uint64(defaultGenesisTime.Add(MinimumStakingDuration).Unix()), // end time
+		uint64(defaultGenesisTime.Add(DefaultStakingParameters.MinimumStakingDuration).Unix()), // end time
 		keys[0].PublicKey().Address(),                                 // node ID
 		testSubnet1.ID,                                                // subnet ID
 		testNetworkID,                                                 // network ID
@@ -566,7 +562,7 @@ func TestAddNonDefaultSubnetValidatorTxSemanticVerify(t *testing.T) {
 		defaultNonce+1,                      // nonce
 		defaultWeight,                       // weight
 		uint64(defaultGenesisTime.Unix())+1, // start time
-		uint64(defaultGenesisTime.Add(MinimumStakingDuration).Unix())+1, // end time
+		uint64(defaultGenesisTime.Add(DefaultStakingParameters.MinimumStakingDuration).Unix())+1, // end time
 		defaultKey.PublicKey().Address(),                                // node ID
 		testSubnet1.ID,                                                  // subnet ID
 		testNetworkID,                                                   // network ID
@@ -37,8 +37,6 @@ func (tx *advanceTimeTx) SyntacticVerify() error {
 	switch {
 	case tx == nil:
 		return errNilTx
-	case tx.vm.clock.Time().Add(Delta).Before(tx.Timestamp()):
-		return errTimeTooAdvanced
 	default:
 		return nil
 	}
@@ -60,6 +58,15 @@ func (tx *advanceTimeTx) SemanticVerify(db database.Database) (*versiondb.Databa
 			currentTimestamp)
 	}
 
+	// Only allow timestamp to move as far forward as the synchrony bound allows
+	params, err := tx.vm.getStakingParameters(db)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if tx.vm.clock.Time().Add(params.Delta).Before(tx.Timestamp()) {
+		return nil, nil, nil, nil, errTimeTooAdvanced
+	}
+
 	// Only allow timestamp to move forward as far as the next validator's end time
 	nextValidatorEndTime := tx.vm.nextValidatorChangeTime(db, false)
 	if tx.Time > uint64(nextValidatorEndTime.Unix()) {
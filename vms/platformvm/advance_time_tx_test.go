@@ -15,22 +15,34 @@ func TestAdvanceTimeTxSyntacticVerify(t *testing.T) {
 		t.Fatal("should have failed verification because tx is nil")
 	}
 
-	// Case 2: Timestamp is ahead of synchrony bound
+	// Case 2: Valid
 	vm := defaultVM()
 	tx = &advanceTimeTx{
-		Time: uint64(defaultGenesisTime.Add(Delta).Add(1 * time.Second).Unix()),
+		Time: uint64(defaultGenesisTime.Add(DefaultStakingParameters.Delta).Add(1 * time.Second).Unix()),
 		vm:   vm,
 	}
+	if err := tx.SyntacticVerify(); err != nil {
+		t.Fatalf("should've passed verification but got: %v", err)
+	}
+}
 
-	err := tx.SyntacticVerify()
-	if err == nil {
+// Ensure semantic verification fails when proposed timestamp is ahead of the
+// synchrony bound
+func TestAdvanceTimeTxTimestampAheadOfSynchronyBound(t *testing.T) {
+	vm := defaultVM()
+
+	// Case 1: Timestamp is ahead of synchrony bound
+	tx := &advanceTimeTx{
+		Time: uint64(defaultGenesisTime.Add(DefaultStakingParameters.Delta).Add(1 * time.Second).Unix()),
+		vm:   vm,
+	}
+	if _, _, _, _, err := tx.SemanticVerify(vm.DB); err == nil {
 		t.Fatal("should've failed verification because timestamp is ahead of synchrony bound")
 	}
 
-	// Case 3: Valid
-	tx.Time = uint64(defaultGenesisTime.Add(Delta).Unix())
-	err = tx.SyntacticVerify()
-	if err != nil {
+	// Case 2: Valid
+	tx.Time = uint64(defaultGenesisTime.Add(DefaultStakingParameters.Delta).Unix())
+	if _, _, _, _, err := tx.SemanticVerify(vm.DB); err != nil {
 		t.Fatalf("should've passed verification but got: %v", err)
 	}
 }
@@ -56,7 +68,7 @@ func TestAdvanceTimeTxTimestampTooLate(t *testing.T) {
 	// Case 1: Timestamp is after next validator start time
 	// Add a pending validator
 	pendingValidatorStartTime := defaultGenesisTime.Add(1 * time.Second)
-	pendingValidatorEndTime := pendingValidatorStartTime.Add(MinimumStakingDuration)
+	pendingValidatorEndTime := pendingValidatorStartTime.Add(DefaultStakingParameters.MinimumStakingDuration)
 	nodeIDKey, _ := vm.factory.NewPrivateKey()
 	nodeID := nodeIDKey.PublicKey().Address()
 	addPendingValidatorTx, err := vm.newAddDefaultSubnetValidatorTx(
@@ -121,7 +133,7 @@ func TestAdvanceTimeTxUpdateValidators(t *testing.T) {
 	// Case 1: Timestamp is after next validator start time
 	// Add a pending validator
 	pendingValidatorStartTime := defaultGenesisTime.Add(1 * time.Second)
-	pendingValidatorEndTime := pendingValidatorStartTime.Add(MinimumStakingDuration)
+	pendingValidatorEndTime := pendingValidatorStartTime.Add(DefaultStakingParameters.MinimumStakingDuration)
 	nodeIDKey, _ := vm.factory.NewPrivateKey()
 	nodeID := nodeIDKey.PublicKey().Address()
 	addPendingValidatorTx, err := vm.newAddDefaultSubnetValidatorTx(
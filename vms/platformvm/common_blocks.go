@@ -182,6 +182,10 @@ type CommonDecisionBlock struct {
 
 	// to be executed if this block is accepted
 	onAcceptFunc func()
+
+	// txs whose changes to the memory shared with the AVM chain are written
+	// along with this block's state, if this block is accepted
+	atomicTxs []atomicTx
 }
 
 // initialize this block
@@ -218,7 +222,7 @@ func (cdb *CommonDecisionBlock) Accept() {
 	if err := cdb.onAcceptDB.Commit(); err != nil {
 		cdb.vm.Ctx.Log.Warn("unable to commit onAcceptDB")
 	}
-	if err := cdb.vm.DB.Commit(); err != nil {
+	if err := cdb.vm.commit(cdb.atomicTxs); err != nil {
 		cdb.vm.Ctx.Log.Warn("unable to commit vm's DB")
 	}
 
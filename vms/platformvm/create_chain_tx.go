@@ -17,6 +17,9 @@ import (
 var (
 	errInvalidVMID             = errors.New("invalid VM ID")
 	errFxIDsNotSortedAndUnique = errors.New("feature extensions IDs must be sorted and unique")
+	errNotSubnetControlKey     = errors.New("tx has a control signature from a key not in the subnet's control keys")
+	errDSCantCreateChain       = errors.New("chains validated by the default subnet can only be created at genesis")
+	errSignedGenesisChain      = errors.New("chains created at genesis can't be signed")
 )
 
 // UnsignedCreateChainTx is an unsigned CreateChainTx
@@ -25,9 +28,11 @@ type UnsignedCreateChainTx struct {
 	NetworkID uint32 `serialize:"true"`
 
 	// Next unused nonce of account paying the transaction fee for this transaction.
-	// Currently unused, as there are no tx fees.
 	Nonce uint64 `serialize:"true"`
 
+	// ID of the subnet that validates the new chain
+	SubnetID ids.ID `serialize:"true"`
+
 	// A human readable name for the chain; need not be unique
 	ChainName string `serialize:"true"`
 
@@ -41,52 +46,91 @@ type UnsignedCreateChainTx struct {
 	GenesisData []byte `serialize:"true"`
 }
 
-// CreateChainTx is a proposal to create a chain
+// CreateChainTx is a proposal to create a chain.
+// It must be signed by a threshold of the control keys of the subnet that
+// validates the new chain.
 type CreateChainTx struct {
 	UnsignedCreateChainTx `serialize:"true"`
 
-	Sig [crypto.SECP256K1RSigLen]byte `serialize:"true"`
+	// Signatures from the subnet's control keys
+	ControlSigs [][crypto.SECP256K1RSigLen]byte `serialize:"true"`
+
+	// PayerSig is the signature of the public key whose corresponding account
+	// pays the tx fee for this tx
+	PayerSig [crypto.SECP256K1RSigLen]byte `serialize:"true"`
 
-	vm    *VM
-	id    ids.ID
-	key   crypto.PublicKey // public key of transaction signer
-	bytes []byte
+	vm         *VM
+	id         ids.ID
+	controlIDs []ids.ShortID
+	senderID   ids.ShortID
+	bytes      []byte
 }
 
 func (tx *CreateChainTx) initialize(vm *VM) error {
 	tx.vm = vm
 	txBytes, err := Codec.Marshal(tx) // byte repr. of the signed tx
+	if err != nil {
+		return err
+	}
 	tx.bytes = txBytes
-	tx.id = ids.NewID(hashing.ComputeHash256Array(txBytes))
-	return err
+	if !tx.SubnetID.Equals(DefaultSubnetID) {
+		tx.id = ids.NewID(hashing.ComputeHash256Array(txBytes))
+		return nil
+	}
+
+	// The chains of the default subnet are only created at genesis, so their
+	// IDs are the hash of the format they're created in
+	legacyBytes, err := Codec.Marshal(tx.legacy())
+	if err != nil {
+		return err
+	}
+	tx.id = ids.NewID(hashing.ComputeHash256Array(legacyBytes))
+	return nil
+}
+
+// legacy returns the legacy format of this tx, which creates a chain of the
+// default subnet
+func (tx *CreateChainTx) legacy() *legacyCreateChainTx {
+	return &legacyCreateChainTx{
+		NetworkID:   tx.NetworkID,
+		Nonce:       tx.Nonce,
+		ChainName:   tx.ChainName,
+		VMID:        tx.VMID,
+		FxIDs:       tx.FxIDs,
+		GenesisData: tx.GenesisData,
+	}
 }
 
 // ID of this transaction
 func (tx *CreateChainTx) ID() ids.ID { return tx.id }
 
-// Key returns the public key of the signer of this transaction
-// Precondition: tx.Verify() has been called and returned nil
-func (tx *CreateChainTx) Key() crypto.PublicKey { return tx.key }
+// PayerAddress returns the address of the account that pays the tx fee
+// Precondition: tx.SyntacticVerify() has been called and returned nil
+func (tx *CreateChainTx) PayerAddress() ids.ShortID { return tx.senderID }
 
 // Bytes returns the byte representation of a CreateChainTx
 func (tx *CreateChainTx) Bytes() []byte { return tx.bytes }
 
 // SyntacticVerify this transaction is well-formed
-// Also populates [tx.Key] with the public key that signed this transaction
+// Also populates [tx.controlIDs] and [tx.senderID]
 func (tx *CreateChainTx) SyntacticVerify() error {
 	switch {
 	case tx == nil:
 		return errNilTx
-	case tx.key != nil:
+	case !tx.senderID.IsZero():
 		return nil // Only verify the transaction once
 	case tx.NetworkID != tx.vm.Ctx.NetworkID: // verify the transaction is on this network
 		return errWrongNetworkID
 	case tx.id.IsZero():
 		return errInvalidID
+	case tx.SubnetID.Equals(DefaultSubnetID):
+		return errDSCantCreateChain
 	case tx.VMID.IsZero():
 		return errInvalidVMID
 	case !ids.IsSortedAndUniqueIDs(tx.FxIDs):
 		return errFxIDsNotSortedAndUnique
+	case !crypto.IsSortedAndUniqueSECP2561RSigs(tx.ControlSigs):
+		return errSigsNotSorted
 	}
 
 	unsignedIntf := interface{}(&tx.UnsignedCreateChainTx)
@@ -95,12 +139,23 @@ func (tx *CreateChainTx) SyntacticVerify() error {
 		return err
 	}
 
-	key, err := tx.vm.factory.RecoverPublicKey(unsignedBytes, tx.Sig[:])
+	unsignedBytesHash := hashing.ComputeHash256(unsignedBytes)
+
+	controlIDs := make([]ids.ShortID, len(tx.ControlSigs))
+	for i, sig := range tx.ControlSigs {
+		key, err := tx.vm.factory.RecoverHashPublicKey(unsignedBytesHash, sig[:])
+		if err != nil {
+			return err
+		}
+		controlIDs[i] = key.Address()
+	}
+
+	key, err := tx.vm.factory.RecoverHashPublicKey(unsignedBytesHash, tx.PayerSig[:])
 	if err != nil {
 		return err
 	}
-	tx.key = key
-
+	tx.controlIDs = controlIDs
+	tx.senderID = key.Address()
 	return nil
 }
 
@@ -110,6 +165,27 @@ func (tx *CreateChainTx) SemanticVerify(db database.Database) (func(), error) {
 		return nil, err
 	}
 
+	// Ensure the tx is signed by a threshold of the subnet's control keys
+	subnet, err := tx.vm.getSubnet(db, tx.SubnetID)
+	if err != nil {
+		return nil, err
+	}
+	if len(tx.controlIDs) != int(subnet.Threshold) {
+		return nil, fmt.Errorf("expected tx to have %d control sigs but has %d", subnet.Threshold, len(tx.controlIDs))
+	}
+	controlKeys := ids.ShortSet{}
+	controlKeys.Add(subnet.ControlKeys...)
+	signers := ids.ShortSet{}
+	for _, controlID := range tx.controlIDs {
+		if !controlKeys.Contains(controlID) {
+			return nil, errNotSubnetControlKey
+		}
+		if signers.Contains(controlID) {
+			return nil, errDuplicateControlSig
+		}
+		signers.Add(controlID)
+	}
+
 	currentChains, err := tx.vm.getChains(db) // chains that currently exist
 	if err != nil {
 		return nil, errDBChains
@@ -125,22 +201,26 @@ func (tx *CreateChainTx) SemanticVerify(db database.Database) (func(), error) {
 	}
 
 	// Deduct tx fee from payer's account
-	account, err := tx.vm.getAccount(db, tx.Key().Address())
+	account, err := tx.vm.getAccount(db, tx.senderID)
 	if err != nil {
 		return nil, err
 	}
-	account, err = account.Remove(0, tx.Nonce)
+	account, err = account.Remove(0, tx.vm.txFee, tx.Nonce)
 	if err != nil {
 		return nil, err
 	}
 	if err := tx.vm.putAccount(db, account); err != nil {
 		return nil, err
 	}
+	if err := tx.vm.burn(db, tx.vm.txFee); err != nil {
+		return nil, err
+	}
 
 	// If this proposal is committed, create the new blockchain using the chain manager
 	onAccept := func() {
 		chainParams := chains.ChainParameters{
 			ID:          tx.ID(),
+			SubnetID:    tx.SubnetID,
 			GenesisData: tx.GenesisData,
 			VMAlias:     tx.VMID.String(),
 		}
@@ -156,6 +236,42 @@ func (tx *CreateChainTx) SemanticVerify(db database.Database) (func(), error) {
 	return onAccept, nil
 }
 
+// legacyCreateChainTx is the format CreateChainTx had before chains could be
+// validated by subnets other than the default subnet. The chains created at
+// genesis are still in this format, so that the genesis of the existing
+// networks, and the IDs of the chains it creates, don't change.
+type legacyCreateChainTx struct {
+	NetworkID   uint32                        `serialize:"true"`
+	Nonce       uint64                        `serialize:"true"`
+	ChainName   string                        `serialize:"true"`
+	VMID        ids.ID                        `serialize:"true"`
+	FxIDs       []ids.ID                      `serialize:"true"`
+	GenesisData []byte                        `serialize:"true"`
+	Sig         [crypto.SECP256K1RSigLen]byte `serialize:"true"`
+}
+
+// chain returns the tx that creates the same chain as [tx], which is
+// validated by the default subnet
+func (tx *legacyCreateChainTx) chain() (*CreateChainTx, error) {
+	// Chains created at genesis aren't signed. If this one were, its ID
+	// wouldn't match the ID of the chain it's converted to.
+	if tx.Sig != [crypto.SECP256K1RSigLen]byte{} {
+		return nil, errSignedGenesisChain
+	}
+	chain := &CreateChainTx{
+		UnsignedCreateChainTx: UnsignedCreateChainTx{
+			NetworkID:   tx.NetworkID,
+			Nonce:       tx.Nonce,
+			SubnetID:    DefaultSubnetID,
+			ChainName:   tx.ChainName,
+			VMID:        tx.VMID,
+			FxIDs:       tx.FxIDs,
+			GenesisData: tx.GenesisData,
+		},
+	}
+	return chain, chain.initialize(nil)
+}
+
 // We use this type so we can serialize a list of *CreateChainTx
 // by defining a Bytes method on it
 type createChainList []*CreateChainTx
@@ -166,11 +282,22 @@ func (chains createChainList) Bytes() []byte {
 	return bytes
 }
 
-func (vm *VM) newCreateChainTx(nonce uint64, genesisData []byte, vmID ids.ID, fxIDs []ids.ID, chainName string, networkID uint32, key *crypto.PrivateKeySECP256K1R) (*CreateChainTx, error) {
+func (vm *VM) newCreateChainTx(
+	nonce uint64,
+	subnetID ids.ID,
+	genesisData []byte,
+	vmID ids.ID,
+	fxIDs []ids.ID,
+	chainName string,
+	networkID uint32,
+	controlKeys []*crypto.PrivateKeySECP256K1R,
+	payerKey *crypto.PrivateKeySECP256K1R,
+) (*CreateChainTx, error) {
 	tx := &CreateChainTx{
 		UnsignedCreateChainTx: UnsignedCreateChainTx{
 			NetworkID:   networkID,
 			Nonce:       nonce,
+			SubnetID:    subnetID,
 			GenesisData: genesisData,
 			VMID:        vmID,
 			FxIDs:       fxIDs,
@@ -183,12 +310,25 @@ func (vm *VM) newCreateChainTx(nonce uint64, genesisData []byte, vmID ids.ID, fx
 	if err != nil {
 		return nil, err
 	}
+	unsignedHash := hashing.ComputeHash256(unsignedBytes)
+
+	// Sign this tx with each control key
+	tx.ControlSigs = make([][crypto.SECP256K1RSigLen]byte, len(controlKeys))
+	for i, key := range controlKeys {
+		sig, err := key.SignHash(unsignedHash)
+		if err != nil {
+			return nil, err
+		}
+		copy(tx.ControlSigs[i][:], sig)
+	}
+	crypto.SortSECP2561RSigs(tx.ControlSigs)
 
-	sig, err := key.Sign(unsignedBytes)
+	// Sign this tx with the key of the tx fee payer
+	sig, err := payerKey.SignHash(unsignedHash)
 	if err != nil {
 		return nil, err
 	}
-	copy(tx.Sig[:], sig)
+	copy(tx.PayerSig[:], sig)
 
 	return tx, tx.initialize(vm)
 }
@@ -6,8 +6,11 @@ package platformvm
 import (
 	"testing"
 
+	"github.com/ava-labs/gecko/database/memdb"
 	"github.com/ava-labs/gecko/database/versiondb"
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/hashing"
 	"github.com/ava-labs/gecko/vms/avm"
 )
 
@@ -24,11 +27,13 @@ func TestCreateChainTxSyntacticVerify(t *testing.T) {
 	// Case 2: network ID is wrong
 	tx, err := vm.newCreateChainTx(
 		defaultNonce+1,
+		testSubnet1.ID,
 		nil,
 		avm.ID,
 		nil,
 		"chain name",
 		testNetworkID+1,
+		testSubnet1ControlKeys[:2],
 		defaultKey,
 	)
 	if err != nil {
@@ -43,11 +48,13 @@ func TestCreateChainTxSyntacticVerify(t *testing.T) {
 	// case 3: tx ID is empty
 	tx, err = vm.newCreateChainTx(
 		defaultNonce+1,
+		testSubnet1.ID,
 		nil,
 		avm.ID,
 		nil,
 		"chain name",
 		testNetworkID,
+		testSubnet1ControlKeys[:2],
 		defaultKey,
 	)
 	if err != nil {
@@ -61,11 +68,13 @@ func TestCreateChainTxSyntacticVerify(t *testing.T) {
 	// Case 4: vm ID is empty
 	tx, err = vm.newCreateChainTx(
 		defaultNonce+1,
+		testSubnet1.ID,
 		nil,
 		avm.ID,
 		nil,
 		"chain name",
 		testNetworkID,
+		testSubnet1ControlKeys[:2],
 		defaultKey,
 	)
 	if err != nil {
@@ -75,6 +84,48 @@ func TestCreateChainTxSyntacticVerify(t *testing.T) {
 	if err := tx.SyntacticVerify(); err == nil {
 		t.Fatal("should've errored because tx ID is empty")
 	}
+
+	// Case 5: chain is validated by the default subnet
+	tx, err = vm.newCreateChainTx(
+		defaultNonce+1,
+		DefaultSubnetID,
+		nil,
+		avm.ID,
+		nil,
+		"chain name",
+		testNetworkID,
+		nil,
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err != errDSCantCreateChain {
+		t.Fatalf("should've errored with %s but errored with %v", errDSCantCreateChain, err)
+	}
+
+	// Case 6: control signatures aren't sorted
+	tx, err = vm.newCreateChainTx(
+		defaultNonce+1,
+		testSubnet1.ID,
+		nil,
+		avm.ID,
+		nil,
+		"chain name",
+		testNetworkID,
+		testSubnet1ControlKeys[:2],
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.ControlSigs[0], tx.ControlSigs[1] = tx.ControlSigs[1], tx.ControlSigs[0]
+	if err := tx.initialize(vm); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err != errSigsNotSorted {
+		t.Fatalf("should've errored with %s but errored with %v", errSigsNotSorted, err)
+	}
 }
 
 func TestSemanticVerify(t *testing.T) {
@@ -83,11 +134,13 @@ func TestSemanticVerify(t *testing.T) {
 	// create a tx
 	tx, err := vm.newCreateChainTx(
 		defaultNonce+1,
+		testSubnet1.ID,
 		nil,
 		avm.ID,
 		nil,
 		"chain name",
 		testNetworkID,
+		testSubnet1ControlKeys[:2],
 		defaultKey,
 	)
 	if err != nil {
@@ -113,17 +166,60 @@ func TestSemanticVerify(t *testing.T) {
 	t.Fatalf("Should have added the chain to the set of chains")
 }
 
+// Ensure a chain can only be created with the signatures of a threshold of
+// its subnet's control keys
+func TestCreateChainTxSemanticVerifyControlSigs(t *testing.T) {
+	vm := defaultVM()
+
+	tests := []struct {
+		name        string
+		subnetID    ids.ID
+		controlKeys []*crypto.PrivateKeySECP256K1R
+		shouldErr   bool
+	}{
+		{"subnet doesn't exist", ids.NewID([32]byte{1}), testSubnet1ControlKeys[:2], true},
+		{"too few control signatures", testSubnet1.ID, testSubnet1ControlKeys[:1], true},
+		{"too many control signatures", testSubnet1.ID, testSubnet1ControlKeys, true},
+		{"signed by a key that isn't a control key", testSubnet1.ID, keys[2:4], true},
+		{"signed twice by the same key", testSubnet1.ID, []*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[1], testSubnet1ControlKeys[1]}, true},
+		{"valid", testSubnet1.ID, testSubnet1ControlKeys[1:], false},
+	}
+	for _, test := range tests {
+		tx, err := vm.newCreateChainTx(
+			defaultNonce+1,
+			test.subnetID,
+			nil,
+			avm.ID,
+			nil,
+			"chain name",
+			testNetworkID,
+			test.controlKeys,
+			defaultKey,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tx.SemanticVerify(versiondb.New(vm.DB)); err == nil && test.shouldErr {
+			t.Fatalf("Should have errored because %s", test.name)
+		} else if err != nil && !test.shouldErr {
+			t.Fatalf("%s shouldn't have errored but errored with %s", test.name, err)
+		}
+	}
+}
+
 func TestSemanticVerifyAlreadyExisting(t *testing.T) {
 	vm := defaultVM()
 
 	// create a tx
 	tx, err := vm.newCreateChainTx(
 		defaultNonce+1,
+		testSubnet1.ID,
 		nil,
 		avm.ID,
 		nil,
 		"chain name",
 		testNetworkID,
+		testSubnet1ControlKeys[:2],
 		defaultKey,
 	)
 	if err != nil {
@@ -140,3 +236,63 @@ func TestSemanticVerifyAlreadyExisting(t *testing.T) {
 		t.Fatalf("should have failed because there is already a chain with ID %s", tx.id)
 	}
 }
+
+// legacyChainList is a list of chains in the legacy format, as it was stored
+// in the database
+type legacyChainList []*legacyCreateChainTx
+
+// Bytes returns the byte representation of the list
+func (chains legacyChainList) Bytes() []byte {
+	bytes, _ := Codec.Marshal(chains)
+	return bytes
+}
+
+// Ensure the chains created at genesis keep the IDs they had before chains
+// were scoped to subnets, including after the list of chains is migrated
+func TestLegacyCreateChainTxID(t *testing.T) {
+	vm := defaultVM()
+
+	legacyChain := &legacyCreateChainTx{
+		NetworkID:   testNetworkID,
+		ChainName:   "chain name",
+		VMID:        avm.ID,
+		GenesisData: []byte{1, 2, 3},
+	}
+	legacyBytes, err := Codec.Marshal(legacyChain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedID := ids.NewID(hashing.ComputeHash256Array(legacyBytes))
+
+	chain, err := legacyChain.chain()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !chain.ID().Equals(expectedID) {
+		t.Fatalf("Wrong chain ID. Expected: %s ; Returned: %s", expectedID, chain.ID())
+	}
+
+	// A database with the chains in the legacy format is migrated
+	vm.DB = versiondb.New(memdb.New())
+	if err := vm.State.Put(vm.DB, legacyChainsTypeID, chainsKey, legacyChainList{legacyChain}); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.migrateChains(); err != nil {
+		t.Fatal(err)
+	}
+	chains, err := vm.getChains(vm.DB)
+	switch {
+	case err != nil:
+		t.Fatal(err)
+	case len(chains) != 1:
+		t.Fatalf("Wrong number of chains. Expected: %d ; Returned: %d", 1, len(chains))
+	case !chains[0].ID().Equals(expectedID):
+		t.Fatalf("Wrong chain ID. Expected: %s ; Returned: %s", expectedID, chains[0].ID())
+	}
+
+	// Chains created at genesis aren't signed
+	legacyChain.Sig[0] = 1
+	if _, err := legacyChain.chain(); err == nil {
+		t.Fatalf("Should have errored because the chain is signed")
+	}
+}
@@ -120,13 +120,16 @@ func (tx *CreateSubnetTx) SemanticVerify(db database.Database) (func(), error) {
 	if err != nil {
 		return nil, err
 	}
-	account, err = account.Remove(0, tx.Nonce)
+	account, err = account.Remove(0, tx.vm.txFee, tx.Nonce)
 	if err != nil {
 		return nil, err
 	}
 	if err := tx.vm.putAccount(db, account); err != nil {
 		return nil, err
 	}
+	if err := tx.vm.burn(db, tx.vm.txFee); err != nil {
+		return nil, err
+	}
 
 	return nil, nil
 }
@@ -80,6 +80,18 @@ func (h *EventHeap) Peek() TimedTx { return h.Txs[0] }
 // Remove ...
 func (h *EventHeap) Remove() TimedTx { return heap.Pop(h).(TimedTx) }
 
+// removeValidator removes the tx that adds the validator with ID [nodeID]
+// from the heap. Returns false if there is no such tx.
+func (h *EventHeap) removeValidator(nodeID ids.ShortID) bool {
+	for i, tx := range h.Txs {
+		if tx.Vdr().ID().Equals(nodeID) {
+			heap.Remove(h, i)
+			return true
+		}
+	}
+	return false
+}
+
 // Push implements the heap interface
 func (h *EventHeap) Push(x interface{}) { h.Txs = append(h.Txs, x.(TimedTx)) }
 
@@ -0,0 +1,177 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/vms/components/shared"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+var (
+	errNoExportAmount  = errors.New("exported amount must be positive")
+	errEmptyExportAddr = errors.New("exported AVA must be sent to an address")
+	errNoAVMChain      = errors.New("this chain doesn't know the IDs of the AVM chain and the AVA asset")
+)
+
+// UnsignedExportTx is an unsigned ExportTx
+type UnsignedExportTx struct {
+	// ID of the network this blockchain exists on
+	NetworkID uint32 `serialize:"true"`
+
+	// Next unused nonce of the account paying the transaction fee and sending
+	// the exported AVA
+	Nonce uint64 `serialize:"true"`
+
+	// Amount of AVA to send to the AVM chain
+	Amount uint64 `serialize:"true"`
+
+	// Address on the AVM chain that the exported AVA is sent to
+	To ids.ShortID `serialize:"true"`
+}
+
+// ExportTx removes AVA from an account and sends it to the AVM chain, where
+// it can be imported to an address
+type ExportTx struct {
+	UnsignedExportTx `serialize:"true"`
+
+	Sig [crypto.SECP256K1RSigLen]byte `serialize:"true"`
+
+	vm    *VM
+	id    ids.ID
+	key   crypto.PublicKey // public key of transaction signer
+	bytes []byte
+}
+
+func (tx *ExportTx) initialize(vm *VM) error {
+	tx.vm = vm
+	txBytes, err := Codec.Marshal(tx) // byte repr. of the signed tx
+	tx.bytes = txBytes
+	tx.id = ids.NewID(hashing.ComputeHash256Array(txBytes))
+	return err
+}
+
+// ID of this transaction
+func (tx *ExportTx) ID() ids.ID { return tx.id }
+
+// Key returns the public key of the signer of this transaction
+// Precondition: tx.Verify() has been called and returned nil
+func (tx *ExportTx) Key() crypto.PublicKey { return tx.key }
+
+// Bytes returns the byte representation of an ExportTx
+func (tx *ExportTx) Bytes() []byte { return tx.bytes }
+
+// SyntacticVerify this transaction is well-formed
+// Also populates [tx.Key] with the public key that signed this transaction
+func (tx *ExportTx) SyntacticVerify() error {
+	switch {
+	case tx == nil:
+		return errNilTx
+	case tx.key != nil:
+		return nil // Only verify the transaction once
+	case tx.NetworkID != tx.vm.Ctx.NetworkID: // verify the transaction is on this network
+		return errWrongNetworkID
+	case tx.id.IsZero():
+		return errInvalidID
+	case tx.Amount == 0:
+		return errNoExportAmount
+	case tx.To.IsZero() || tx.To.Equals(ids.ShortEmpty):
+		return errEmptyExportAddr
+	}
+
+	unsignedIntf := interface{}(&tx.UnsignedExportTx)
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf) // byte repr of unsigned tx
+	if err != nil {
+		return err
+	}
+
+	key, err := tx.vm.factory.RecoverPublicKey(unsignedBytes, tx.Sig[:])
+	if err != nil {
+		return err
+	}
+	tx.key = key
+
+	return nil
+}
+
+// SemanticVerify this transaction is valid.
+func (tx *ExportTx) SemanticVerify(db database.Database) (func(), error) {
+	if err := tx.SyntacticVerify(); err != nil {
+		return nil, err
+	}
+	if tx.vm.avm.IsZero() || tx.vm.ava.IsZero() {
+		return nil, errNoAVMChain
+	}
+
+	// Remove the exported AVA and the tx fee from the payer's account
+	account, err := tx.vm.getAccount(db, tx.Key().Address())
+	if err != nil {
+		return nil, err
+	}
+	account, err = account.Remove(tx.Amount, tx.vm.txFee, tx.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.vm.putAccount(db, account); err != nil {
+		return nil, err
+	}
+	if err := tx.vm.burn(db, tx.vm.txFee); err != nil {
+		return nil, err
+	}
+
+	// The AVA is sent to the AVM chain when this tx's block is accepted. See
+	// writeSharedMemory.
+	return nil, nil
+}
+
+// writeSharedMemory sends the exported AVA to the AVM chain, through [smDB],
+// the memory shared with it
+func (tx *ExportTx) writeSharedMemory(smDB database.Database) {
+	state := shared.NewState(smDB, tx.vm.avm)
+	utxo := &shared.UTXO{
+		TxID:        tx.ID(),
+		OutputIndex: 0,
+		AssetID:     tx.vm.ava,
+		Out: secp256k1fx.TransferOutput{
+			Amt: tx.Amount,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{tx.To},
+			},
+		},
+	}
+	if err := state.FundUTXO(utxo); err != nil {
+		tx.vm.Ctx.Log.Error("couldn't export AVA in tx %s due to %s", tx.ID(), err)
+	}
+}
+
+func (vm *VM) newExportTx(nonce uint64, amount uint64, to ids.ShortID, networkID uint32, key *crypto.PrivateKeySECP256K1R) (*ExportTx, error) {
+	tx := &ExportTx{
+		UnsignedExportTx: UnsignedExportTx{
+			NetworkID: networkID,
+			Nonce:     nonce,
+			Amount:    amount,
+			To:        to,
+		},
+	}
+
+	unsignedIntf := interface{}(&tx.UnsignedExportTx)
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf) // Byte repr. of unsigned transaction
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := key.Sign(unsignedBytes)
+	if err != nil {
+		return nil, err
+	}
+	copy(tx.Sig[:], sig)
+
+	return tx, tx.initialize(vm)
+}
@@ -0,0 +1,260 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/gecko/chains/atomic"
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/database/versiondb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/vms/components/shared"
+)
+
+var (
+	avmChainID = ids.Empty.Prefix(0)
+	avaAssetID = ids.Empty.Prefix(1)
+
+	errWriteFailed = errors.New("write failed")
+)
+
+// failingDB is a database that, once [limitWrites] is set, fails to write any
+// batch after [writesLeft] more batches have been written, as a node crashing
+// would
+type failingDB struct {
+	*memdb.Database
+	limitWrites bool
+	writesLeft  int
+}
+
+func (db *failingDB) NewBatch() database.Batch {
+	return &failingBatch{Batch: db.Database.NewBatch(), db: db}
+}
+
+type failingBatch struct {
+	database.Batch
+	db *failingDB
+}
+
+func (b *failingBatch) Write() error {
+	if b.db.limitWrites {
+		if b.db.writesLeft == 0 {
+			return errWriteFailed
+		}
+		b.db.writesLeft--
+	}
+	return b.Batch.Write()
+}
+
+func (b *failingBatch) Inner() database.Batch { return b }
+
+// atomicVM returns a default VM that shares memory with the AVM chain
+func atomicVM() (*VM, *atomic.Memory) { return atomicVMWithDB(memdb.New()) }
+
+// atomicVMWithDB returns an atomicVM whose state and shared memory are both
+// stored in [baseDB], as they are on a node
+func atomicVMWithDB(baseDB database.Database) (*VM, *atomic.Memory) {
+	vm := defaultVMWithDB(prefixdb.New([]byte("platform"), baseDB))
+
+	sm := &atomic.Memory{}
+	sm.Initialize(logging.NoLog{}, prefixdb.New([]byte("shared memory"), baseDB))
+	vm.Ctx.SharedMemory = sm.NewBlockchainMemory(vm.Ctx.ChainID)
+	vm.avm = avmChainID
+	vm.ava = avaAssetID
+	return vm, sm
+}
+
+func TestExportTxSyntacticVerify(t *testing.T) {
+	vm, _ := atomicVM()
+	to := keys[1].PublicKey().Address()
+
+	// Case 1: tx is nil
+	var tx *ExportTx
+	if err := tx.SyntacticVerify(); err == nil {
+		t.Fatal("should have failed because tx is nil")
+	}
+
+	// Case 2: network ID is wrong
+	tx, err := vm.newExportTx(defaultNonce+1, 1, to, testNetworkID+1, defaultKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err == nil {
+		t.Fatal("should have failed because network ID is wrong")
+	}
+
+	// Case 3: nothing is exported
+	tx, err = vm.newExportTx(defaultNonce+1, 0, to, testNetworkID, defaultKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err == nil {
+		t.Fatal("should have failed because no AVA is exported")
+	}
+
+	// Case 4: recipient is empty
+	tx, err = vm.newExportTx(defaultNonce+1, 1, ids.ShortEmpty, testNetworkID, defaultKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err == nil {
+		t.Fatal("should have failed because the recipient is empty")
+	}
+
+	// Case 5: valid
+	tx, err = vm.newExportTx(defaultNonce+1, 1, to, testNetworkID, defaultKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExportTxSemanticVerify(t *testing.T) {
+	vm, sm := atomicVM()
+	to := keys[1].PublicKey().Address()
+
+	// Case 1: more AVA is exported than the account holds
+	tx, err := vm.newExportTx(defaultNonce+1, defaultBalance+1, to, testNetworkID, defaultKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.SemanticVerify(versiondb.New(vm.DB)); err == nil {
+		t.Fatal("should have failed because the account doesn't hold enough AVA")
+	}
+
+	// Case 2: valid
+	tx, err = vm.newExportTx(defaultNonce+1, 1000, to, testNetworkID, defaultKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := versiondb.New(vm.DB)
+	supply, err := vm.getSupply(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.SemanticVerify(db); err != nil {
+		t.Fatal(err)
+	}
+
+	// The exported AVA still exists, but the fee is burnt
+	if newSupply, err := vm.getSupply(db); err != nil {
+		t.Fatal(err)
+	} else if newSupply != supply-defaultTxFee {
+		t.Fatalf("Wrong supply. Expected: %d ; Returned: %d", supply-defaultTxFee, newSupply)
+	}
+
+	account, err := vm.getAccount(db, defaultKey.PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Balance != defaultBalance-1000-defaultTxFee {
+		t.Fatalf("Wrong balance. Expected: %d ; Returned: %d", defaultBalance-1000-defaultTxFee, account.Balance)
+	}
+
+	// Accept the tx
+	if err := db.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.commit([]atomicTx{tx}); err != nil {
+		t.Fatal(err)
+	}
+
+	avmMemory := sm.NewBlockchainMemory(avmChainID)
+	smDB := avmMemory.GetDatabase(vm.Ctx.ChainID)
+	defer avmMemory.ReleaseDatabase(vm.Ctx.ChainID)
+
+	state := shared.NewState(smDB, avmChainID)
+	utxoIDs, err := state.Funds(to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxoIDs) != 1 {
+		t.Fatalf("Should have exported %d UTXO(s) but exported %d", 1, len(utxoIDs))
+	}
+	utxo, err := state.UTXO(utxoIDs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !utxo.AssetID.Equals(avaAssetID) {
+		t.Fatalf("Should have exported AVA")
+	}
+	if utxo.Out.Amt != 1000 {
+		t.Fatalf("Wrong amount exported. Expected: %d ; Returned: %d", 1000, utxo.Out.Amt)
+	}
+}
+
+func TestExportTxWriteFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		// The number of batches written before the node crashes
+		writes int
+		// Whether the accepted export is expected to have been written
+		written bool
+	}{
+		{name: "no write", writes: 0, written: false},
+		{name: "one write", writes: 1, written: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			baseDB := &failingDB{Database: memdb.New()}
+			vm, sm := atomicVMWithDB(baseDB)
+			to := keys[1].PublicKey().Address()
+
+			tx, err := vm.newExportTx(defaultNonce+1, 1000, to, testNetworkID, defaultKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := tx.SemanticVerify(vm.DB); err != nil {
+				t.Fatal(err)
+			}
+
+			baseDB.limitWrites = true
+			baseDB.writesLeft = test.writes
+			if err := vm.commit([]atomicTx{tx}); (err == nil) != test.written {
+				t.Fatalf("Wrong error. Expected an error: %v ; Returned: %v", !test.written, err)
+			}
+			baseDB.limitWrites = false
+
+			// Read what was written, as the node would after restarting
+			account, err := vm.getAccount(vm.DB.GetDatabase(), defaultKey.PublicKey().Address())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if debited := account.Balance != defaultBalance; debited != test.written {
+				t.Fatalf("The account should have been debited if, and only if, the export was written")
+			}
+
+			avmMemory := sm.NewBlockchainMemory(avmChainID)
+			smDB := avmMemory.GetDatabase(vm.Ctx.ChainID)
+			defer avmMemory.ReleaseDatabase(vm.Ctx.ChainID)
+
+			utxoIDs, err := shared.NewState(smDB, avmChainID).Funds(to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if exported := len(utxoIDs) == 1; exported != test.written {
+				t.Fatalf("The AVA should have been exported if, and only if, the export was written")
+			}
+		})
+	}
+}
+
+func TestExportTxNoAVMChain(t *testing.T) {
+	vm := defaultVM()
+
+	tx, err := vm.newExportTx(defaultNonce+1, 1000, keys[1].PublicKey().Address(), testNetworkID, defaultKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.SemanticVerify(versiondb.New(vm.DB)); err == nil {
+		t.Fatal("should have failed because there is no AVM chain to export to")
+	}
+}
@@ -18,12 +18,29 @@ var (
 type Factory struct {
 	ChainManager chains.Manager
 	Validators   validators.Manager
+
+	// ID of the AVM chain that AVA can be exported to and imported from
+	AVM ids.ID
+
+	// ID of the AVA asset on the AVM chain
+	AVA ids.ID
+
+	// Amount of AVA burnt by every transaction that has a payer
+	TxFee uint64
+
+	// Fraction of its staking period a staker must have been connected to
+	// this node for this node to initially prefer rewarding it
+	UptimeRequirement float64
 }
 
 // New returns a new instance of the Platform Chain
 func (f *Factory) New() interface{} {
 	return &VM{
-		ChainManager: f.ChainManager,
-		Validators:   f.Validators,
+		ChainManager:      f.ChainManager,
+		Validators:        f.Validators,
+		avm:               f.AVM,
+		ava:               f.AVA,
+		txFee:             f.TxFee,
+		uptimeRequirement: f.UptimeRequirement,
 	}
 }
@@ -0,0 +1,232 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/math"
+	"github.com/ava-labs/gecko/vms/components/shared"
+)
+
+var (
+	errNoImportedUTXOs           = errors.New("no UTXOs to import")
+	errUTXOIDsNotSortedAndUnique = errors.New("imported UTXO IDs must be sorted and unique")
+	errAlreadyImported           = errors.New("UTXO was already imported")
+	errUTXONotAVA                = errors.New("only AVA can be imported")
+	errUTXOLocked                = errors.New("imported UTXO is still locked")
+	errUnspendableUTXO           = errors.New("imported UTXO can't be spent by the signer of this tx")
+	errImportOverflow            = errors.New("imported amount overflowed uint64")
+)
+
+// UnsignedImportTx is an unsigned ImportTx
+type UnsignedImportTx struct {
+	// ID of the network this blockchain exists on
+	NetworkID uint32 `serialize:"true"`
+
+	// Next unused nonce of the account paying the transaction fee and receiving
+	// the imported AVA
+	Nonce uint64 `serialize:"true"`
+
+	// IDs of the UTXOs, exported by the AVM chain, that this tx imports
+	UTXOIDs []ids.ID `serialize:"true"`
+}
+
+// ImportTx consumes AVA that the AVM chain exported, and adds it to the
+// account of the signer of this transaction
+type ImportTx struct {
+	UnsignedImportTx `serialize:"true"`
+
+	Sig [crypto.SECP256K1RSigLen]byte `serialize:"true"`
+
+	vm    *VM
+	id    ids.ID
+	key   crypto.PublicKey // public key of transaction signer
+	bytes []byte
+}
+
+func (tx *ImportTx) initialize(vm *VM) error {
+	tx.vm = vm
+	txBytes, err := Codec.Marshal(tx) // byte repr. of the signed tx
+	tx.bytes = txBytes
+	tx.id = ids.NewID(hashing.ComputeHash256Array(txBytes))
+	return err
+}
+
+// ID of this transaction
+func (tx *ImportTx) ID() ids.ID { return tx.id }
+
+// Key returns the public key of the signer of this transaction
+// Precondition: tx.Verify() has been called and returned nil
+func (tx *ImportTx) Key() crypto.PublicKey { return tx.key }
+
+// Bytes returns the byte representation of an ImportTx
+func (tx *ImportTx) Bytes() []byte { return tx.bytes }
+
+// SyntacticVerify this transaction is well-formed
+// Also populates [tx.Key] with the public key that signed this transaction
+func (tx *ImportTx) SyntacticVerify() error {
+	switch {
+	case tx == nil:
+		return errNilTx
+	case tx.key != nil:
+		return nil // Only verify the transaction once
+	case tx.NetworkID != tx.vm.Ctx.NetworkID: // verify the transaction is on this network
+		return errWrongNetworkID
+	case tx.id.IsZero():
+		return errInvalidID
+	case len(tx.UTXOIDs) == 0:
+		return errNoImportedUTXOs
+	case !ids.IsSortedAndUniqueIDs(tx.UTXOIDs):
+		return errUTXOIDsNotSortedAndUnique
+	}
+
+	unsignedIntf := interface{}(&tx.UnsignedImportTx)
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf) // byte repr of unsigned tx
+	if err != nil {
+		return err
+	}
+
+	key, err := tx.vm.factory.RecoverPublicKey(unsignedBytes, tx.Sig[:])
+	if err != nil {
+		return err
+	}
+	tx.key = key
+
+	return nil
+}
+
+// SemanticVerify this transaction is valid.
+func (tx *ImportTx) SemanticVerify(db database.Database) (func(), error) {
+	if err := tx.SyntacticVerify(); err != nil {
+		return nil, err
+	}
+	if tx.vm.avm.IsZero() || tx.vm.ava.IsZero() {
+		return nil, errNoAVMChain
+	}
+
+	timestamp, err := tx.vm.getTimestamp(db)
+	if err != nil {
+		return nil, err
+	}
+	addr := tx.Key().Address()
+
+	smDB := tx.vm.Ctx.SharedMemory.GetDatabase(tx.vm.avm)
+	defer tx.vm.Ctx.SharedMemory.ReleaseDatabase(tx.vm.avm)
+
+	state := shared.NewState(smDB, tx.vm.Ctx.ChainID)
+
+	amount := uint64(0)
+	for _, utxoID := range tx.UTXOIDs {
+		// The UTXOs stay in the shared memory until this tx is accepted, so
+		// blocks that are still processing must not import them again
+		imported, err := tx.vm.importedUTXO(db, utxoID)
+		if err != nil {
+			return nil, err
+		}
+		if imported {
+			return nil, errAlreadyImported
+		}
+
+		utxo, err := state.UTXO(utxoID)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't find UTXO %s: %w", utxoID, err)
+		}
+
+		switch {
+		case !utxo.AssetID.Equals(tx.vm.ava):
+			return nil, errUTXONotAVA
+		case utxo.Out.Locktime > uint64(timestamp.Unix()):
+			return nil, errUTXOLocked
+		case utxo.Out.Threshold != 1 || !containsAddr(utxo.Out.Addrs, addr):
+			return nil, errUnspendableUTXO
+		}
+
+		amount, err = math.Add64(amount, utxo.Out.Amt)
+		if err != nil {
+			return nil, errImportOverflow
+		}
+
+		if err := tx.vm.putImportedUTXO(db, utxoID, tx.ID()); err != nil {
+			return nil, err
+		}
+	}
+
+	// Add the imported AVA to the payer's account before paying the tx fee, so
+	// that an account with no AVA can import AVA
+	account, err := tx.vm.getAccount(db, addr)
+	if err != nil {
+		return nil, err
+	}
+	account, err = account.Add(amount)
+	if err != nil {
+		return nil, err
+	}
+	account, err = account.Remove(0, tx.vm.txFee, tx.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.vm.putAccount(db, account); err != nil {
+		return nil, err
+	}
+	if err := tx.vm.burn(db, tx.vm.txFee); err != nil {
+		return nil, err
+	}
+
+	// The imported UTXOs are removed from the memory shared with the AVM chain
+	// when this tx's block is accepted. See writeSharedMemory.
+	return nil, nil
+}
+
+// writeSharedMemory removes the imported UTXOs from [smDB], the memory shared
+// with the AVM chain. The UTXOs were marked as imported in this chain's state,
+// so they can't be imported again even if this fails.
+func (tx *ImportTx) writeSharedMemory(smDB database.Database) {
+	state := shared.NewState(smDB, tx.vm.Ctx.ChainID)
+	for _, utxoID := range tx.UTXOIDs {
+		if err := state.SpendUTXO(utxoID); err != nil {
+			tx.vm.Ctx.Log.Error("couldn't remove UTXO %s imported in tx %s due to %s", utxoID, tx.ID(), err)
+		}
+	}
+}
+
+func (vm *VM) newImportTx(nonce uint64, utxoIDs []ids.ID, networkID uint32, key *crypto.PrivateKeySECP256K1R) (*ImportTx, error) {
+	ids.SortIDs(utxoIDs)
+	tx := &ImportTx{
+		UnsignedImportTx: UnsignedImportTx{
+			NetworkID: networkID,
+			Nonce:     nonce,
+			UTXOIDs:   utxoIDs,
+		},
+	}
+
+	unsignedIntf := interface{}(&tx.UnsignedImportTx)
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf) // Byte repr. of unsigned transaction
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := key.Sign(unsignedBytes)
+	if err != nil {
+		return nil, err
+	}
+	copy(tx.Sig[:], sig)
+
+	return tx, tx.initialize(vm)
+}
+
+// containsAddr returns true if [addr] is in [addrs]
+func containsAddr(addrs []ids.ShortID, addr ids.ShortID) bool {
+	for _, a := range addrs {
+		if a.Equals(addr) {
+			return true
+		}
+	}
+	return false
+}
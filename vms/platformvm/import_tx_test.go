@@ -0,0 +1,181 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/chains/atomic"
+	"github.com/ava-labs/gecko/database/versiondb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/vms/components/shared"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+// exportToPlatform adds a UTXO, exported by the AVM chain to [vm]'s chain, to
+// the shared memory
+func exportToPlatform(t *testing.T, vm *VM, sm *atomic.Memory, utxo *shared.UTXO) {
+	avmMemory := sm.NewBlockchainMemory(avmChainID)
+	smDB := avmMemory.GetDatabase(vm.Ctx.ChainID)
+	defer avmMemory.ReleaseDatabase(vm.Ctx.ChainID)
+
+	if err := shared.NewState(smDB, vm.Ctx.ChainID).FundUTXO(utxo); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func avaUTXO(txID ids.ID, amount uint64, owner ids.ShortID) *shared.UTXO {
+	return &shared.UTXO{
+		TxID:    txID,
+		AssetID: avaAssetID,
+		Out: secp256k1fx.TransferOutput{
+			Amt: amount,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{owner},
+			},
+		},
+	}
+}
+
+func TestImportTxSyntacticVerify(t *testing.T) {
+	vm, _ := atomicVM()
+
+	// Case 1: tx is nil
+	var tx *ImportTx
+	if err := tx.SyntacticVerify(); err == nil {
+		t.Fatal("should have failed because tx is nil")
+	}
+
+	// Case 2: nothing is imported
+	tx, err := vm.newImportTx(defaultNonce+1, nil, testNetworkID, defaultKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err == nil {
+		t.Fatal("should have failed because no UTXOs are imported")
+	}
+
+	// Case 3: a UTXO is imported twice
+	utxoID := ids.Empty.Prefix(2)
+	tx, err = vm.newImportTx(defaultNonce+1, []ids.ID{utxoID, utxoID}, testNetworkID, defaultKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err == nil {
+		t.Fatal("should have failed because a UTXO is imported twice")
+	}
+
+	// Case 4: valid
+	tx, err = vm.newImportTx(defaultNonce+1, []ids.ID{utxoID}, testNetworkID, defaultKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestImportTxSemanticVerify(t *testing.T) {
+	vm, sm := atomicVM()
+	addr := defaultKey.PublicKey().Address()
+
+	utxo := avaUTXO(ids.Empty.Prefix(2), 1000, addr)
+	exportToPlatform(t, vm, sm, utxo)
+	utxoID := utxo.InputID()
+
+	// Case 1: UTXO isn't owned by the payer
+	tx, err := vm.newImportTx(defaultNonce+1, []ids.ID{utxoID}, testNetworkID, keys[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.SemanticVerify(versiondb.New(vm.DB)); err == nil {
+		t.Fatal("should have failed because the payer doesn't own the UTXO")
+	}
+
+	// Case 2: UTXO wasn't exported
+	tx, err = vm.newImportTx(defaultNonce+1, []ids.ID{ids.Empty.Prefix(3)}, testNetworkID, defaultKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.SemanticVerify(versiondb.New(vm.DB)); err == nil {
+		t.Fatal("should have failed because the UTXO wasn't exported")
+	}
+
+	// Case 3: valid
+	tx, err = vm.newImportTx(defaultNonce+1, []ids.ID{utxoID}, testNetworkID, defaultKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := versiondb.New(vm.DB)
+	if _, err := tx.SemanticVerify(db); err != nil {
+		t.Fatal(err)
+	}
+
+	account, err := vm.getAccount(db, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Balance != defaultBalance+1000-defaultTxFee {
+		t.Fatalf("Wrong balance. Expected: %d ; Returned: %d", defaultBalance+1000-defaultTxFee, account.Balance)
+	}
+
+	// Case 4: the UTXO was already imported in this state
+	tx, err = vm.newImportTx(defaultNonce+2, []ids.ID{utxoID}, testNetworkID, defaultKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.SemanticVerify(versiondb.New(db)); err == nil {
+		t.Fatal("should have failed because the UTXO was already imported")
+	}
+
+	// Accept the tx
+	if err := db.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.commit([]atomicTx{tx}); err != nil {
+		t.Fatal(err)
+	}
+
+	avmMemory := sm.NewBlockchainMemory(avmChainID)
+	smDB := avmMemory.GetDatabase(vm.Ctx.ChainID)
+	defer avmMemory.ReleaseDatabase(vm.Ctx.ChainID)
+	if _, err := shared.NewState(smDB, vm.Ctx.ChainID).UTXO(utxoID); err == nil {
+		t.Fatal("the imported UTXO should have been removed from shared memory")
+	}
+}
+
+func TestImportTxWrongAsset(t *testing.T) {
+	vm, sm := atomicVM()
+	addr := defaultKey.PublicKey().Address()
+
+	utxo := avaUTXO(ids.Empty.Prefix(2), 1000, addr)
+	utxo.AssetID = ids.Empty.Prefix(4)
+	exportToPlatform(t, vm, sm, utxo)
+
+	tx, err := vm.newImportTx(defaultNonce+1, []ids.ID{utxo.InputID()}, testNetworkID, defaultKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.SemanticVerify(versiondb.New(vm.DB)); err == nil {
+		t.Fatal("should have failed because the UTXO isn't AVA")
+	}
+}
+
+func TestImportTxLocked(t *testing.T) {
+	vm, sm := atomicVM()
+	addr := defaultKey.PublicKey().Address()
+
+	utxo := avaUTXO(ids.Empty.Prefix(2), 1000, addr)
+	utxo.Out.Locktime = uint64(defaultGenesisTime.Unix()) + 1
+	exportToPlatform(t, vm, sm, utxo)
+
+	tx, err := vm.newImportTx(defaultNonce+1, []ids.ID{utxo.InputID()}, testNetworkID, defaultKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.SemanticVerify(versiondb.New(vm.DB)); err == nil {
+		t.Fatal("should have failed because the UTXO is locked")
+	}
+}
@@ -0,0 +1,235 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/hashing"
+)
+
+var (
+	errDSCantRemoveValidator = errors.New("validators can't be removed from the default subnet")
+)
+
+// UnsignedRemoveSubnetValidatorTx is an unsigned RemoveSubnetValidatorTx
+type UnsignedRemoveSubnetValidatorTx struct {
+	// ID of the network this blockchain exists on
+	NetworkID uint32 `serialize:"true"`
+
+	// Next unused nonce of the account paying the transaction fee
+	Nonce uint64 `serialize:"true"`
+
+	// ID of the node to remove from the subnet's validators
+	NodeID ids.ShortID `serialize:"true"`
+
+	// ID of the subnet to remove the validator from
+	SubnetID ids.ID `serialize:"true"`
+}
+
+// RemoveSubnetValidatorTx removes a validator from the current or pending
+// validators of a subnet other than the default subnet, before its end time.
+// It must be signed by a threshold of the subnet's control keys.
+type RemoveSubnetValidatorTx struct {
+	UnsignedRemoveSubnetValidatorTx `serialize:"true"`
+
+	// Signatures from the subnet's control keys
+	ControlSigs [][crypto.SECP256K1RSigLen]byte `serialize:"true"`
+
+	// PayerSig is the signature of the public key whose corresponding account
+	// pays the tx fee for this tx
+	PayerSig [crypto.SECP256K1RSigLen]byte `serialize:"true"`
+
+	vm         *VM
+	id         ids.ID
+	controlIDs []ids.ShortID
+	senderID   ids.ShortID
+	bytes      []byte
+}
+
+func (tx *RemoveSubnetValidatorTx) initialize(vm *VM) error {
+	tx.vm = vm
+	txBytes, err := Codec.Marshal(tx) // byte repr. of the signed tx
+	tx.bytes = txBytes
+	tx.id = ids.NewID(hashing.ComputeHash256Array(txBytes))
+	return err
+}
+
+// ID of this transaction
+func (tx *RemoveSubnetValidatorTx) ID() ids.ID { return tx.id }
+
+// Bytes returns the byte representation of a RemoveSubnetValidatorTx
+func (tx *RemoveSubnetValidatorTx) Bytes() []byte { return tx.bytes }
+
+// SyntacticVerify this transaction is well-formed
+// Also populates [tx.controlIDs] and [tx.senderID]
+func (tx *RemoveSubnetValidatorTx) SyntacticVerify() error {
+	switch {
+	case tx == nil:
+		return errNilTx
+	case !tx.senderID.IsZero():
+		return nil // Only verify the transaction once
+	case tx.NetworkID != tx.vm.Ctx.NetworkID: // verify the transaction is on this network
+		return errWrongNetworkID
+	case tx.id.IsZero():
+		return errInvalidID
+	case tx.SubnetID.Equals(DefaultSubnetID):
+		return errDSCantRemoveValidator
+	case !crypto.IsSortedAndUniqueSECP2561RSigs(tx.ControlSigs):
+		return errSigsNotSorted
+	}
+
+	unsignedIntf := interface{}(&tx.UnsignedRemoveSubnetValidatorTx)
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf) // byte repr of unsigned tx
+	if err != nil {
+		return err
+	}
+	unsignedBytesHash := hashing.ComputeHash256(unsignedBytes)
+
+	controlIDs := make([]ids.ShortID, len(tx.ControlSigs))
+	for i, sig := range tx.ControlSigs {
+		key, err := tx.vm.factory.RecoverHashPublicKey(unsignedBytesHash, sig[:])
+		if err != nil {
+			return err
+		}
+		controlIDs[i] = key.Address()
+	}
+
+	key, err := tx.vm.factory.RecoverHashPublicKey(unsignedBytesHash, tx.PayerSig[:])
+	if err != nil {
+		return err
+	}
+	tx.controlIDs = controlIDs
+	tx.senderID = key.Address()
+	return nil
+}
+
+// SemanticVerify this transaction is valid.
+func (tx *RemoveSubnetValidatorTx) SemanticVerify(db database.Database) (func(), error) {
+	if err := tx.SyntacticVerify(); err != nil {
+		return nil, err
+	}
+
+	// Ensure the tx is signed by a threshold of the subnet's control keys
+	subnet, err := tx.vm.getSubnet(db, tx.SubnetID)
+	if err != nil {
+		return nil, err
+	}
+	if len(tx.controlIDs) != int(subnet.Threshold) {
+		return nil, fmt.Errorf("expected tx to have %d control sigs but has %d", subnet.Threshold, len(tx.controlIDs))
+	}
+	controlKeys := ids.ShortSet{}
+	controlKeys.Add(subnet.ControlKeys...)
+	signers := ids.ShortSet{}
+	for _, controlID := range tx.controlIDs {
+		if !controlKeys.Contains(controlID) {
+			return nil, errNotSubnetControlKey
+		}
+		if signers.Contains(controlID) {
+			return nil, errDuplicateControlSig
+		}
+		signers.Add(controlID)
+	}
+
+	// Remove the validator from the subnet's current validators or, if it
+	// isn't validating the subnet yet, from its pending validators
+	currentValidators, err := tx.vm.getCurrentValidators(db, tx.SubnetID)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get current validators of subnet %s: %v", tx.SubnetID, err)
+	}
+	isCurrent := currentValidators.removeValidator(tx.NodeID)
+	if isCurrent {
+		if err := tx.vm.putCurrentValidators(db, currentValidators, tx.SubnetID); err != nil {
+			return nil, err
+		}
+	} else {
+		pendingValidators, err := tx.vm.getPendingValidators(db, tx.SubnetID)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't get pending validators of subnet %s: %v", tx.SubnetID, err)
+		}
+		if !pendingValidators.removeValidator(tx.NodeID) {
+			return nil, fmt.Errorf("%s isn't a current or pending validator of subnet %s", tx.NodeID, tx.SubnetID)
+		}
+		if err := tx.vm.putPendingValidators(db, pendingValidators, tx.SubnetID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Deduct tx fee from payer's account
+	account, err := tx.vm.getAccount(db, tx.senderID)
+	if err != nil {
+		return nil, err
+	}
+	account, err = account.Remove(0, tx.vm.txFee, tx.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.vm.putAccount(db, account); err != nil {
+		return nil, err
+	}
+	if err := tx.vm.burn(db, tx.vm.txFee); err != nil {
+		return nil, err
+	}
+
+	if !isCurrent {
+		return nil, nil
+	}
+
+	// If this tx is accepted, stop sampling the removed validator
+	onAccept := func() {
+		if err := tx.vm.updateValidators(tx.SubnetID); err != nil {
+			tx.vm.Ctx.Log.Error("failed to update validators of subnet %s: %s", tx.SubnetID, err)
+		}
+	}
+	return onAccept, nil
+}
+
+func (vm *VM) newRemoveSubnetValidatorTx(
+	nonce uint64,
+	nodeID ids.ShortID,
+	subnetID ids.ID,
+	networkID uint32,
+	controlKeys []*crypto.PrivateKeySECP256K1R,
+	payerKey *crypto.PrivateKeySECP256K1R,
+) (*RemoveSubnetValidatorTx, error) {
+	tx := &RemoveSubnetValidatorTx{
+		UnsignedRemoveSubnetValidatorTx: UnsignedRemoveSubnetValidatorTx{
+			NetworkID: networkID,
+			Nonce:     nonce,
+			NodeID:    nodeID,
+			SubnetID:  subnetID,
+		},
+	}
+
+	unsignedIntf := interface{}(&tx.UnsignedRemoveSubnetValidatorTx)
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf) // byte repr. of unsigned tx
+	if err != nil {
+		return nil, err
+	}
+	unsignedHash := hashing.ComputeHash256(unsignedBytes)
+
+	// Sign this tx with each control key
+	tx.ControlSigs = make([][crypto.SECP256K1RSigLen]byte, len(controlKeys))
+	for i, key := range controlKeys {
+		sig, err := key.SignHash(unsignedHash)
+		if err != nil {
+			return nil, err
+		}
+		copy(tx.ControlSigs[i][:], sig)
+	}
+	crypto.SortSECP2561RSigs(tx.ControlSigs)
+
+	// Sign this tx with the key of the tx fee payer
+	sig, err := payerKey.SignHash(unsignedHash)
+	if err != nil {
+		return nil, err
+	}
+	copy(tx.PayerSig[:], sig)
+
+	return tx, tx.initialize(vm)
+}
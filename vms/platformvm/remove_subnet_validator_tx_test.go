@@ -0,0 +1,252 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/database/versiondb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/crypto"
+)
+
+// addTestSubnetValidator makes keys[0] a current validator of testSubnet1 if
+// [current], and a pending one otherwise
+func addTestSubnetValidator(vm *VM, current bool) (ids.ShortID, error) {
+	nodeID := keys[0].PublicKey().Address()
+	startTime := defaultGenesisTime.Add(time.Second)
+	tx, err := vm.newAddNonDefaultSubnetValidatorTx(
+		defaultNonce+1,
+		defaultWeight,
+		uint64(startTime.Unix()),
+		uint64(startTime.Add(DefaultStakingParameters.MinimumStakingDuration).Unix()),
+		nodeID,
+		testSubnet1.ID,
+		testNetworkID,
+		testSubnet1ControlKeys[:2],
+		defaultKey,
+	)
+	if err != nil {
+		return ids.ShortID{}, err
+	}
+	if current {
+		err = vm.putCurrentValidators(vm.DB, &EventHeap{Txs: []TimedTx{tx}}, testSubnet1.ID)
+	} else {
+		err = vm.putPendingValidators(vm.DB, &EventHeap{SortByStartTime: true, Txs: []TimedTx{tx}}, testSubnet1.ID)
+	}
+	return nodeID, err
+}
+
+func TestRemoveSubnetValidatorTxSyntacticVerify(t *testing.T) {
+	vm := defaultVM()
+	nodeID := keys[0].PublicKey().Address()
+
+	// Case 1: tx is nil
+	var tx *RemoveSubnetValidatorTx
+	if err := tx.SyntacticVerify(); err == nil {
+		t.Fatal("should have failed because tx is nil")
+	}
+
+	// Case 2: network ID is wrong
+	tx, err := vm.newRemoveSubnetValidatorTx(
+		defaultNonce+1,
+		nodeID,
+		testSubnet1.ID,
+		testNetworkID+1,
+		testSubnet1ControlKeys[:2],
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err == nil {
+		t.Fatal("should have failed because network ID is wrong")
+	}
+
+	// Case 3: subnet is the default subnet
+	tx, err = vm.newRemoveSubnetValidatorTx(
+		defaultNonce+1,
+		nodeID,
+		DefaultSubnetID,
+		testNetworkID,
+		nil,
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err != errDSCantRemoveValidator {
+		t.Fatalf("should have errored with %s but errored with %v", errDSCantRemoveValidator, err)
+	}
+
+	// Case 4: control signatures aren't sorted
+	tx, err = vm.newRemoveSubnetValidatorTx(
+		defaultNonce+1,
+		nodeID,
+		testSubnet1.ID,
+		testNetworkID,
+		testSubnet1ControlKeys[:2],
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.ControlSigs[0], tx.ControlSigs[1] = tx.ControlSigs[1], tx.ControlSigs[0]
+	if err := tx.initialize(vm); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err != errSigsNotSorted {
+		t.Fatalf("should have errored with %s but errored with %v", errSigsNotSorted, err)
+	}
+
+	// Case 5: valid
+	tx, err = vm.newRemoveSubnetValidatorTx(
+		defaultNonce+1,
+		nodeID,
+		testSubnet1.ID,
+		testNetworkID,
+		testSubnet1ControlKeys[:2],
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRemoveSubnetValidatorTxSemanticVerify(t *testing.T) {
+	vm := defaultVM()
+	nodeID, err := addTestSubnetValidator(vm, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		nodeID      ids.ShortID
+		subnetID    ids.ID
+		controlKeys []*crypto.PrivateKeySECP256K1R
+		shouldErr   bool
+	}{
+		{"subnet doesn't exist", nodeID, ids.NewID([32]byte{1}), testSubnet1ControlKeys[:2], true},
+		{"too few control signatures", nodeID, testSubnet1.ID, testSubnet1ControlKeys[:1], true},
+		{"signed by a key that isn't a control key", nodeID, testSubnet1.ID, keys[2:4], true},
+		{"signed twice by the same key", nodeID, testSubnet1.ID, []*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[1], testSubnet1ControlKeys[1]}, true},
+		{"node isn't a validator of the subnet", keys[1].PublicKey().Address(), testSubnet1.ID, testSubnet1ControlKeys[:2], true},
+		{"valid", nodeID, testSubnet1.ID, testSubnet1ControlKeys[1:], false},
+	}
+	for _, test := range tests {
+		tx, err := vm.newRemoveSubnetValidatorTx(
+			defaultNonce+1,
+			test.nodeID,
+			test.subnetID,
+			testNetworkID,
+			test.controlKeys,
+			defaultKey,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tx.SemanticVerify(versiondb.New(vm.DB)); err == nil && test.shouldErr {
+			t.Fatalf("Should have errored because %s", test.name)
+		} else if err != nil && !test.shouldErr {
+			t.Fatalf("%s shouldn't have errored but errored with %s", test.name, err)
+		}
+	}
+}
+
+// Ensure removing a current validator of a subnet removes it from the subnet's
+// validator set once the tx is accepted
+func TestRemoveSubnetValidatorTxCurrent(t *testing.T) {
+	vm := defaultVM()
+	nodeID, err := addTestSubnetValidator(vm, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.updateValidators(testSubnet1.ID); err != nil {
+		t.Fatal(err)
+	}
+	validatorSet, ok := vm.Validators.GetValidatorSet(testSubnet1.ID)
+	if !ok {
+		t.Fatalf("Should have created the validator set of the subnet")
+	}
+	if !validatorSet.Contains(nodeID) {
+		t.Fatalf("Should be validating the subnet")
+	}
+
+	tx, err := vm.newRemoveSubnetValidatorTx(
+		defaultNonce+1,
+		nodeID,
+		testSubnet1.ID,
+		testNetworkID,
+		testSubnet1ControlKeys[:2],
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	onAccept, err := tx.SemanticVerify(vm.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if onAccept == nil {
+		t.Fatalf("Should update the validator set when accepted")
+	}
+	onAccept()
+
+	current, err := vm.getCurrentValidators(vm.DB, testSubnet1.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.Len() != 0 {
+		t.Fatalf("Should have removed the validator from the current validators")
+	}
+	if validatorSet.Contains(nodeID) {
+		t.Fatalf("Should have removed the validator from the validator set")
+	}
+
+	account, err := vm.getAccount(vm.DB, defaultKey.PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Balance != defaultBalance-defaultTxFee {
+		t.Fatalf("Wrong balance. Expected: %d ; Returned: %d", defaultBalance-defaultTxFee, account.Balance)
+	}
+}
+
+// Ensure a validator of a subnet can be removed before it starts validating
+func TestRemoveSubnetValidatorTxPending(t *testing.T) {
+	vm := defaultVM()
+	nodeID, err := addTestSubnetValidator(vm, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := vm.newRemoveSubnetValidatorTx(
+		defaultNonce+1,
+		nodeID,
+		testSubnet1.ID,
+		testNetworkID,
+		testSubnet1ControlKeys[:2],
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := versiondb.New(vm.DB)
+	if _, err := tx.SemanticVerify(db); err != nil {
+		t.Fatal(err)
+	}
+
+	pending, err := vm.getPendingValidators(db, testSubnet1.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending.Len() != 0 {
+		t.Fatalf("Should have removed the validator from the pending validators")
+	}
+}
@@ -4,22 +4,150 @@
 package platformvm
 
 import (
-	"math"
+	"errors"
+	"math/big"
 	"time"
+
+	"github.com/ava-labs/gecko/utils/units"
+)
+
+// Rewards are calculated using only integer arithmetic so that every node,
+// regardless of its architecture, rewards stakers with exactly the same amount.
+//
+// A staker that stakes [amount] nAva for [duration], when [supply] nAva exist,
+// is rewarded with:
+//
+//	remaining * (amount / supply) * (duration / MintingPeriod) * rate(duration)
+//
+// where [remaining] is MaxSupply - [supply], and rate(duration) is the
+// consumption rate, which increases linearly from MinConsumptionRate, for a
+// duration of 0, to MaxConsumptionRate, for a duration of MintingPeriod. That
+// is, staking for longer is rewarded at a higher rate.
+//
+// Since a staker is rewarded with at most [remaining] * ([amount] / [supply]),
+// the supply approaches, but never exceeds, MaxSupply.
+//
+// The reward is rounded down once, after the exact result is calculated.
+const (
+	// RateDenominator is the denominator of consumption rates. That is, a
+	// consumption rate of RateDenominator is 100%.
+	RateDenominator = 1000000
+)
+
+var (
+	errNoMintingPeriod         = errors.New("minting period must be positive")
+	errConsumptionRatesOrder   = errors.New("minimum consumption rate must be at most the maximum consumption rate")
+	errConsumptionRateTooLarge = errors.New("consumption rate must be at most 100%")
+
+	// DefaultRewardConfig is the reward config of a network whose genesis
+	// doesn't specify one
+	DefaultRewardConfig = RewardConfig{
+		MaxSupply:          720 * units.MegaAva,
+		MintingPeriod:      365 * 24 * time.Hour,
+		MinConsumptionRate: RateDenominator / 10,       // 10%
+		MaxConsumptionRate: 12 * RateDenominator / 100, // 12%
+	}
 )
 
-// reward returns the amount of $AVA to reward the staker with
-func reward(duration time.Duration, amount uint64, inflationRate float64) uint64 {
-	// TODO: Can't use floats here. Need to figure out how to do some integer
-	// approximations
+// RewardConfig determines how much stakers are rewarded with
+type RewardConfig struct {
+	// MaxSupply is the amount of nAva that the supply approaches as stakers are
+	// rewarded
+	MaxSupply uint64 `serialize:"true"`
+
+	// MintingPeriod is the duration over which stakers of the entire supply
+	// are rewarded with the consumption rate of the remaining supply
+	MintingPeriod time.Duration `serialize:"true"`
+
+	// MinConsumptionRate is the fraction of the remaining supply, in parts of
+	// RateDenominator, that stakers of the entire supply would be rewarded with
+	// per MintingPeriod if they staked for an arbitrarily short duration
+	MinConsumptionRate uint64 `serialize:"true"`
+
+	// MaxConsumptionRate is the fraction of the remaining supply, in parts of
+	// RateDenominator, that stakers of the entire supply are rewarded with
+	// when staking for MintingPeriod
+	MaxConsumptionRate uint64 `serialize:"true"`
+}
 
-	years := duration.Hours() / (365. * 24.)
+// Verify that this reward config is well formed
+func (c *RewardConfig) Verify() error {
+	switch {
+	case c.MintingPeriod < time.Second:
+		return errNoMintingPeriod
+	case c.MinConsumptionRate > c.MaxConsumptionRate:
+		return errConsumptionRatesOrder
+	case c.MaxConsumptionRate > RateDenominator:
+		return errConsumptionRateTooLarge
+	default:
+		return nil
+	}
+}
+
+// Reward returns the amount of nAva to reward a staker that staked [amount]
+// nAva for [duration] with, given that [supply] nAva exist.
+// Durations are rounded down to the second.
+func (c *RewardConfig) Reward(duration time.Duration, amount, supply uint64) uint64 {
+	if supply >= c.MaxSupply || supply == 0 {
+		return 0
+	}
+	remaining := c.MaxSupply - supply
+
+	// A staker can't stake more than exists, or for longer than the minting
+	// period
+	if amount > supply {
+		amount = supply
+	}
+	period := uint64(c.MintingPeriod / time.Second)
+	if duration > c.MintingPeriod {
+		duration = c.MintingPeriod
+	}
+	seconds := uint64(duration / time.Second)
+
+	// rate(duration) * period * RateDenominator
+	scaledRate := new(big.Int).SetUint64(c.MinConsumptionRate)
+	scaledRate.Mul(scaledRate, new(big.Int).SetUint64(period))
+	rateIncrease := new(big.Int).SetUint64(c.MaxConsumptionRate - c.MinConsumptionRate)
+	rateIncrease.Mul(rateIncrease, new(big.Int).SetUint64(seconds))
+	scaledRate.Add(scaledRate, rateIncrease)
+
+	// remaining * amount * duration * rate(duration) * period * RateDenominator
+	reward := new(big.Int).SetUint64(remaining)
+	reward.Mul(reward, new(big.Int).SetUint64(amount))
+	reward.Mul(reward, new(big.Int).SetUint64(seconds))
+	reward.Mul(reward, scaledRate)
+
+	// supply * period * period * RateDenominator
+	denominator := new(big.Int).SetUint64(supply)
+	denominator.Mul(denominator, new(big.Int).SetUint64(period))
+	denominator.Mul(denominator, new(big.Int).SetUint64(period))
+	denominator.Mul(denominator, big.NewInt(RateDenominator))
+
+	reward.Quo(reward, denominator)
+
+	// The reward is at most [remaining], so this never overflows, but never
+	// exceed the max supply even if that's wrong.
+	if !reward.IsUint64() || reward.Uint64() > remaining {
+		return remaining
+	}
+	return reward.Uint64()
+}
 
-	// Total value of this transaction
-	value := float64(amount) * math.Pow(inflationRate, years)
+// splitReward returns the portions of [reward], which was earned by a
+// delegator, that the delegator and the validator it delegated to receive,
+// given that the validator takes [shares] of NumberOfShares of the reward.
+// The delegator's portion is rounded down.
+func splitReward(reward uint64, shares uint32) (delegatorReward uint64, validatorReward uint64) {
+	// Ensure the validator never takes more than the entire reward
+	if shares > NumberOfShares {
+		shares = NumberOfShares
+	}
 
-	// Amount of the reward in $AVA
-	reward := value - float64(amount)
+	portion := new(big.Int).SetUint64(reward)
+	portion.Mul(portion, big.NewInt(int64(NumberOfShares-shares)))
+	portion.Quo(portion, big.NewInt(NumberOfShares))
 
-	return uint64(reward)
+	// The delegator's portion is at most [reward], so this never underflows
+	delegatorReward = portion.Uint64()
+	return delegatorReward, reward - delegatorReward
 }
@@ -0,0 +1,230 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/utils/units"
+)
+
+// referenceReward calculates the reward with rational numbers, independently of
+// RewardConfig.Reward
+func referenceReward(c *RewardConfig, duration time.Duration, amount, supply uint64) uint64 {
+	if supply >= c.MaxSupply || supply == 0 {
+		return 0
+	}
+	if amount > supply {
+		amount = supply
+	}
+	if duration > c.MintingPeriod {
+		duration = c.MintingPeriod
+	}
+	seconds := new(big.Rat).SetInt64(int64(duration / time.Second))
+	period := new(big.Rat).SetInt64(int64(c.MintingPeriod / time.Second))
+
+	// fraction of the minting period staked for
+	portion := new(big.Rat).Quo(seconds, period)
+
+	rate := new(big.Rat).SetFrac64(int64(c.MaxConsumptionRate-c.MinConsumptionRate), RateDenominator)
+	rate.Mul(rate, portion)
+	rate.Add(rate, new(big.Rat).SetFrac64(int64(c.MinConsumptionRate), RateDenominator))
+
+	stake := new(big.Rat).SetFrac(new(big.Int).SetUint64(amount), new(big.Int).SetUint64(supply))
+
+	reward := new(big.Rat).SetInt(new(big.Int).SetUint64(c.MaxSupply - supply))
+	reward.Mul(reward, stake)
+	reward.Mul(reward, portion)
+	reward.Mul(reward, rate)
+
+	return new(big.Int).Quo(reward.Num(), reward.Denom()).Uint64()
+}
+
+func TestRewardConfigVerify(t *testing.T) {
+	if err := DefaultRewardConfig.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*RewardConfig)
+	}{
+		{"no minting period", func(c *RewardConfig) { c.MintingPeriod = 0 }},
+		{"decreasing rates", func(c *RewardConfig) { c.MinConsumptionRate = c.MaxConsumptionRate + 1 }},
+		{"rate too large", func(c *RewardConfig) { c.MaxConsumptionRate = RateDenominator + 1 }},
+	}
+	for _, test := range tests {
+		config := DefaultRewardConfig
+		test.modify(&config)
+		if err := config.Verify(); err == nil {
+			t.Fatalf("Should have errored due to %s", test.name)
+		}
+	}
+}
+
+// Rewards must be the same on every architecture, so they're checked against
+// values calculated elsewhere
+func TestRewardKnownValues(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		amount   uint64
+		supply   uint64
+		reward   uint64
+	}{
+		{365 * 24 * time.Hour, 2 * units.KiloAva, 360 * units.MegaAva, 240 * units.Ava},
+		{365 * 24 * time.Hour, 360 * units.MegaAva, 360 * units.MegaAva, 43200 * units.KiloAva},
+		{24 * time.Hour, 2 * units.KiloAva, 360 * units.MegaAva, 548245449},
+		{365 * 12 * time.Hour, 10 * units.KiloAva, 400 * units.MegaAva, 440 * units.Ava},
+		{14 * 24 * time.Hour, 123456789012, 543210987654321098, 155294377},
+		{365 * 24 * time.Hour, 1, 1, 86399999999999999},
+		{365 * 24 * time.Hour, units.MilliAva, 720*units.MegaAva - units.KiloAva, 0},
+		{365 * 24 * time.Hour, 2 * units.KiloAva, 720 * units.MegaAva, 0},
+		{365 * 24 * time.Hour, 2 * units.KiloAva, 0, 0},
+		{0, 2 * units.KiloAva, 360 * units.MegaAva, 0},
+		{365 * 24 * time.Hour, 0, 360 * units.MegaAva, 0},
+	}
+	for _, test := range tests {
+		if reward := DefaultRewardConfig.Reward(test.duration, test.amount, test.supply); reward != test.reward {
+			t.Fatalf("Staking %d for %s with a supply of %d should be rewarded with %d but was rewarded with %d",
+				test.amount, test.duration, test.supply, test.reward, reward)
+		}
+	}
+}
+
+// Every reward for small inputs should be exactly the rounded down result
+func TestRewardExhaustive(t *testing.T) {
+	config := RewardConfig{
+		MaxSupply:          100,
+		MintingPeriod:      DefaultStakingParameters.MaximumStakingDuration,
+		MinConsumptionRate: 3 * RateDenominator / 10,
+		MaxConsumptionRate: 7 * RateDenominator / 10,
+	}
+	durations := []time.Duration{
+		0,
+		time.Second,
+		DefaultStakingParameters.MinimumStakingDuration,
+		DefaultStakingParameters.MaximumStakingDuration / 3,
+		DefaultStakingParameters.MaximumStakingDuration - time.Second,
+		DefaultStakingParameters.MaximumStakingDuration,
+		2 * DefaultStakingParameters.MaximumStakingDuration,
+	}
+	for _, duration := range durations {
+		for supply := uint64(0); supply <= config.MaxSupply+1; supply++ {
+			for amount := uint64(0); amount <= supply+1; amount++ {
+				reward := config.Reward(duration, amount, supply)
+				if expected := referenceReward(&config, duration, amount, supply); reward != expected {
+					t.Fatalf("Staking %d for %s with a supply of %d should be rewarded with %d but was rewarded with %d",
+						amount, duration, supply, expected, reward)
+				}
+			}
+		}
+	}
+}
+
+func TestRewardProperties(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+	for i := 0; i < 10000; i++ {
+		config := RewardConfig{
+			MaxSupply:          r.Uint64(),
+			MintingPeriod:      DefaultStakingParameters.MaximumStakingDuration + time.Duration(r.Int63n(int64(DefaultStakingParameters.MaximumStakingDuration))),
+			MinConsumptionRate: uint64(r.Int63n(RateDenominator + 1)),
+		}
+		config.MaxConsumptionRate = config.MinConsumptionRate + uint64(r.Int63n(int64(RateDenominator-config.MinConsumptionRate+1)))
+		if err := config.Verify(); err != nil {
+			t.Fatal(err)
+		}
+
+		supply := uint64(r.Int63n(int64(config.MaxSupply/2 + 1)))
+		amount := uint64(r.Int63n(int64(supply/2 + 1)))
+		duration := time.Duration(r.Int63n(int64(config.MintingPeriod))).Truncate(time.Second)
+
+		reward := config.Reward(duration, amount, supply)
+		if expected := referenceReward(&config, duration, amount, supply); reward != expected {
+			t.Fatalf("Staking %d for %s with a supply of %d should be rewarded with %d but was rewarded with %d",
+				amount, duration, supply, expected, reward)
+		}
+		if reward != config.Reward(duration, amount, supply) {
+			t.Fatalf("Reward should be deterministic")
+		}
+		if reward > config.MaxSupply-supply {
+			t.Fatalf("Reward %d exceeds the remaining supply %d", reward, config.MaxSupply-supply)
+		}
+
+		// Staking more, or for longer, is never rewarded with less
+		if moreReward := config.Reward(duration, amount*2, supply); moreReward < reward {
+			t.Fatalf("Staking twice as much was rewarded with %d < %d", moreReward, reward)
+		}
+		if longerReward := config.Reward(duration+time.Second, amount, supply); longerReward < reward {
+			t.Fatalf("Staking for longer was rewarded with %d < %d", longerReward, reward)
+		}
+		// Staking a larger portion of a larger supply is never rewarded with
+		// more, since less remains to be minted
+		if largerSupplyReward := config.Reward(duration, amount, supply*2); largerSupplyReward > reward {
+			t.Fatalf("Staking with twice the supply was rewarded with %d > %d", largerSupplyReward, reward)
+		}
+	}
+}
+
+func TestRewardNeverExceedsMaxSupply(t *testing.T) {
+	config := DefaultRewardConfig
+	supply := 360 * units.MegaAva
+	for i := 0; i < 1000; i++ {
+		// The entire supply is staked for as long as possible
+		reward := config.Reward(DefaultStakingParameters.MaximumStakingDuration, supply, supply)
+		supply += reward
+		if supply > config.MaxSupply {
+			t.Fatalf("Supply %d exceeded the max supply %d", supply, config.MaxSupply)
+		}
+	}
+	// Once less than 1/MaxConsumptionRate nAva remain, rewards round down to 0
+	if remaining := config.MaxSupply - supply; remaining*config.MaxConsumptionRate >= RateDenominator {
+		t.Fatalf("Supply should have approached the max supply %d but was %d", config.MaxSupply, supply)
+	}
+}
+
+func TestSplitReward(t *testing.T) {
+	tests := []struct {
+		reward          uint64
+		shares          uint32
+		delegatorReward uint64
+		validatorReward uint64
+	}{
+		{1000, 0, 1000, 0},
+		{1000, NumberOfShares, 0, 1000},
+		{1000, NumberOfShares / 4, 750, 250},
+		{1001, NumberOfShares / 4, 750, 251},
+		{math.MaxUint64, NumberOfShares / 2, math.MaxUint64 / 2, math.MaxUint64/2 + 1},
+		{math.MaxUint64, 1, 18446725626965477905, 18446744073710},
+		{math.MaxUint64, NumberOfShares + 1, 0, math.MaxUint64},
+	}
+	for _, test := range tests {
+		delegatorReward, validatorReward := splitReward(test.reward, test.shares)
+		if delegatorReward != test.delegatorReward || validatorReward != test.validatorReward {
+			t.Fatalf("Splitting %d with %d shares should give (%d, %d) but gave (%d, %d)",
+				test.reward, test.shares, test.delegatorReward, test.validatorReward, delegatorReward, validatorReward)
+		}
+	}
+
+	r := rand.New(rand.NewSource(0))
+	for i := 0; i < 10000; i++ {
+		reward := r.Uint64()
+		shares := uint32(r.Int63n(NumberOfShares + 1))
+		delegatorReward, validatorReward := splitReward(reward, shares)
+		if delegatorReward+validatorReward != reward {
+			t.Fatalf("Splitting %d gave (%d, %d), which doesn't add up", reward, delegatorReward, validatorReward)
+		}
+
+		expected := new(big.Int).SetUint64(reward)
+		expected.Mul(expected, big.NewInt(int64(NumberOfShares-shares)))
+		expected.Quo(expected, big.NewInt(NumberOfShares))
+		if delegatorReward != expected.Uint64() {
+			t.Fatalf("Splitting %d with %d shares should give the delegator %d but gave %d",
+				reward, shares, expected, delegatorReward)
+		}
+	}
+}
@@ -109,11 +109,25 @@ func (tx *rewardValidatorTx) SemanticVerify(db database.Database) (*versiondb.Da
 		return nil, nil, nil, nil, errDBPutCurrentValidators
 	}
 
+	// The reward is based on the amount of nAva that exist before the staker
+	// is rewarded
+	supply, err := tx.vm.getSupply(db)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	// The reward is calculated with the staking parameters in effect when the
+	// staker is rewarded
+	params, err := tx.vm.getStakingParameters(db)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	minted := uint64(0) // The amount of nAva the staker was rewarded with
+
 	switch vdrTx := vdrTx.(type) {
 	case *addDefaultSubnetValidatorTx:
 		duration := vdrTx.Duration()
 		amount := vdrTx.Wght
-		reward := reward(duration, amount, InflationRate)
+		reward := params.Rewards.Reward(duration, amount, supply)
 		amountWithReward, err := math.Add64(amount, reward)
 		if err != nil {
 			amountWithReward = amount
@@ -134,6 +148,7 @@ func (tx *rewardValidatorTx) SemanticVerify(db database.Database) (*versiondb.Da
 		accountNoReward := account   // The state of the account if the validator didn't earn a validating reward
 		if newAccount, err := account.Add(amountWithReward); err == nil {
 			accountWithReward = newAccount
+			minted = amountWithReward - amount
 		} else {
 			tx.vm.Ctx.Log.Error("error while calculating account balance: %v", err)
 		}
@@ -157,19 +172,8 @@ func (tx *rewardValidatorTx) SemanticVerify(db database.Database) (*versiondb.Da
 
 		duration := vdrTx.Duration()
 		amount := vdrTx.Wght
-		reward := reward(duration, amount, InflationRate)
-
-		// Because parentTx.Shares <= NumberOfShares this will never underflow
-		delegatorShares := NumberOfShares - uint64(parentTx.Shares)
-		// Because delegatorShares <= NumberOfShares this will never overflow
-		delegatorReward := delegatorShares * (reward / NumberOfShares)
-		// Delay rounding as long as possible for small numbers
-		if optimisticReward, err := math.Mul64(delegatorShares, reward); err == nil {
-			delegatorReward = optimisticReward / NumberOfShares
-		}
-
-		// Because delegatorReward <= reward this will never underflow
-		validatorReward := reward - delegatorReward
+		reward := params.Rewards.Reward(duration, amount, supply)
+		delegatorReward, validatorReward := splitReward(reward, parentTx.Shares)
 
 		delegatorAmountWithReward, err := math.Add64(amount, delegatorReward)
 		if err != nil {
@@ -191,6 +195,7 @@ func (tx *rewardValidatorTx) SemanticVerify(db database.Database) (*versiondb.Da
 		delegatorAccountNoReward := delegatorAccount   // The state of the account if the validator didn't earn a validating reward
 		if newAccount, err := delegatorAccount.Add(delegatorAmountWithReward); err == nil {
 			delegatorAccountWithReward = newAccount
+			minted = delegatorAmountWithReward - amount
 		} else {
 			tx.vm.Ctx.Log.Error("error while calculating account balance: %v", err)
 		}
@@ -220,6 +225,7 @@ func (tx *rewardValidatorTx) SemanticVerify(db database.Database) (*versiondb.Da
 		validatorAccountWithReward := validatorAccount // The state of the account if the validator earned a validating reward
 		if newAccount, err := validatorAccount.Add(validatorReward); err == nil {
 			validatorAccountWithReward = newAccount
+			minted += validatorReward
 		} else {
 			tx.vm.Ctx.Log.Error("error while calculating account balance: %v", err)
 		}
@@ -231,6 +237,13 @@ func (tx *rewardValidatorTx) SemanticVerify(db database.Database) (*versiondb.Da
 		return nil, nil, nil, nil, errShouldBeDSValidator
 	}
 
+	// If this tx's proposal is committed, the reward is added to the supply.
+	// The reward never takes the supply past the maximum supply, so this never
+	// overflows.
+	if err := tx.vm.putSupply(onCommitDB, supply+minted); err != nil {
+		return nil, nil, nil, nil, errDB
+	}
+
 	// Regardless of whether this tx is committed or aborted, update the
 	// validator set to remove the staker. onAbortDB or onCommitDB should commit
 	// (flush to vm.DB) before this is called
@@ -243,14 +256,19 @@ func (tx *rewardValidatorTx) SemanticVerify(db database.Database) (*versiondb.Da
 	return onCommitDB, onAbortDB, updateValidators, updateValidators, nil
 }
 
-// InitiallyPrefersCommit returns true.
+// InitiallyPrefersCommit returns true if the staker was connected to this node
+// for at least the required fraction of its staking period.
 //
-// Right now, *Commit (that is, remove the validator and reward them) is always
-// preferred over *Abort (remove the validator but don't reward them.)
-//
-// TODO: A validator should receive a reward only if they are sufficiently
-// responsive and correct during the time they are validating.
-func (tx *rewardValidatorTx) InitiallyPrefersCommit() bool { return true }
+// If so, *Commit (that is, remove the validator and reward them) is preferred
+// over *Abort (remove the validator but don't reward them.) The staker is
+// given the benefit of the doubt if its uptime isn't known.
+func (tx *rewardValidatorTx) InitiallyPrefersCommit() bool {
+	up, elapsed, ok := tx.vm.uptimes.uptime(tx.TxID)
+	if !ok || elapsed == 0 {
+		return true
+	}
+	return float64(up) >= tx.vm.uptimeRequirement*float64(elapsed)
+}
 
 // RewardStakerTx creates a new transaction that proposes to remove the staker
 // [validatorID] from the default validator set.
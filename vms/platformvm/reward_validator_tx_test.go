@@ -123,9 +123,27 @@ func TestRewardValidatorTxSemanticVerify(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if account.Balance <= defaultBalance-txFee {
+	if account.Balance <= defaultBalance-defaultTxFee {
 		t.Fatal("expected account balance to have increased due to receiving validator reward")
 	}
+
+	// the reward should have been added to the supply only if the validator
+	// was rewarded
+	supply, err := vm.getSupply(vm.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reward := DefaultStakingParameters.Rewards.Reward(nextToRemove.Duration(), nextToRemove.Wght, supply)
+	if commitSupply, err := vm.getSupply(onCommitDB); err != nil {
+		t.Fatal(err)
+	} else if commitSupply != supply+reward {
+		t.Fatalf("expected supply to be %d was %d", supply+reward, commitSupply)
+	}
+	if abortSupply, err := vm.getSupply(onAbortDB); err != nil {
+		t.Fatal(err)
+	} else if abortSupply != supply {
+		t.Fatalf("expected supply to be %d was %d", supply, abortSupply)
+	}
 }
 
 func TestRewardDelegatorTxSemanticVerify(t *testing.T) {
@@ -184,6 +202,20 @@ func TestRewardDelegatorTxSemanticVerify(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	supply, err := vm.getSupply(vm.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The delegator receives 3/4 of the delegation's reward and the validator
+	// the rest
+	delReward := DefaultStakingParameters.Rewards.Reward(delTx.Duration(), delTx.Wght, supply)
+	delegatorReward, validatorReward := splitReward(delReward, vdrTx.Shares)
+	if delegatorReward != delReward*3/4 {
+		t.Fatalf("expected the delegator to receive 3/4 of the reward")
+	}
+	vdrReward := DefaultStakingParameters.Rewards.Reward(vdrTx.Duration(), vdrTx.Wght, supply+delReward)
+
 	tx, err := vm.newRewardValidatorTx(delTx.ID())
 	if err != nil {
 		t.Fatal(err)
@@ -199,7 +231,7 @@ func TestRewardDelegatorTxSemanticVerify(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if expectedBalance := defaultStakeAmount / 100; account.Balance != expectedBalance {
+	if expectedBalance := validatorReward; account.Balance != expectedBalance {
 		t.Fatalf("expected account balance to be %d was %d", expectedBalance, account.Balance)
 	}
 
@@ -208,7 +240,7 @@ func TestRewardDelegatorTxSemanticVerify(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if expectedBalance := (defaultStakeAmount * 103) / 100; account.Balance != expectedBalance {
+	if expectedBalance := defaultStakeAmount + delegatorReward; account.Balance != expectedBalance {
 		t.Fatalf("expected account balance to be %d was %d", expectedBalance, account.Balance)
 	}
 
@@ -227,7 +259,12 @@ func TestRewardDelegatorTxSemanticVerify(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if expectedBalance := (defaultStakeAmount * 21) / 20; account.Balance != expectedBalance {
+	if expectedBalance := defaultStakeAmount + validatorReward + vdrReward; account.Balance != expectedBalance {
 		t.Fatalf("expected account balance to be %d was %d", expectedBalance, account.Balance)
 	}
+	if newSupply, err := vm.getSupply(onCommitDB); err != nil {
+		t.Fatal(err)
+	} else if expectedSupply := supply + delReward + vdrReward; newSupply != expectedSupply {
+		t.Fatalf("expected supply to be %d was %d", expectedSupply, newSupply)
+	}
 }
@@ -14,9 +14,11 @@ import (
 
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/utils/crypto"
 	"github.com/ava-labs/gecko/utils/formatting"
 	"github.com/ava-labs/gecko/utils/json"
+	"github.com/ava-labs/gecko/vms/components/shared"
 )
 
 var (
@@ -30,24 +32,10 @@ var (
 	errNoDestination        = errors.New("call is missing field 'stakeDestination'")
 	errNoSource             = errors.New("call is missing field 'stakeSource'")
 	errGetStakeSource       = errors.New("couldn't get account specified in 'stakeSource'")
+	errRemoteSigner         = errors.New("the keys of users are held by a remote signer")
+	errNotSigner            = errors.New("user doesn't control the signer's key")
 )
 
-var key *crypto.PrivateKeySECP256K1R
-
-func init() {
-	cb58 := formatting.CB58{}
-	err := cb58.FromString("24jUJ9vZexUM6expyMcT48LBx27k1m7xpraoV62oSQAHdziao5")
-	if err != nil {
-		panic(err)
-	}
-	factory := crypto.FactorySECP256K1R{}
-	pk, err := factory.ToPrivateKey(cb58.Bytes)
-	if err != nil {
-		panic(err)
-	}
-	key = pk.(*crypto.PrivateKeySECP256K1R)
-}
-
 // Service defines the API calls that can be made to the platform chain
 type Service struct{ vm *VM }
 
@@ -267,6 +255,70 @@ func (service *Service) SampleValidators(_ *http.Request, args *SampleValidators
 	return nil
 }
 
+// GetUptimeArgs are the arguments for calling GetUptime
+type GetUptimeArgs struct {
+	// ID of the default subnet validator
+	// If omitted, defaults to this node's ID
+	ID ids.ShortID `json:"id"`
+}
+
+// GetUptimeReply are the results from calling GetUptime
+type GetUptimeReply struct {
+	// ID of the tx that added the validator
+	TxID ids.ID `json:"txID"`
+
+	StartTime json.Uint64 `json:"startTime"`
+	EndTime   json.Uint64 `json:"endTime"`
+
+	// How many seconds of its staking period have passed, and how many of
+	// those the validator was connected to this node for
+	Elapsed    json.Uint64 `json:"elapsed"`
+	UpDuration json.Uint64 `json:"upDuration"`
+
+	// The fraction of the elapsed staking period the validator was connected
+	// to this node for, and the fraction it must have been connected for to be
+	// rewarded
+	Uptime            float64 `json:"uptime"`
+	UptimeRequirement float64 `json:"uptimeRequirement"`
+}
+
+// GetUptime returns how long a validator of the default subnet has been
+// connected to this node during its current staking period
+func (service *Service) GetUptime(_ *http.Request, args *GetUptimeArgs, reply *GetUptimeReply) error {
+	service.vm.Ctx.Log.Debug("platform.getUptime called")
+
+	if args.ID.IsZero() {
+		args.ID = service.vm.Ctx.NodeID
+	}
+
+	validators, err := service.vm.getCurrentValidators(service.vm.DB, DefaultSubnetID)
+	if err != nil {
+		return fmt.Errorf("couldn't get validators of the default subnet: %w", err)
+	}
+	for _, tx := range validators.Txs {
+		tx, ok := tx.(*addDefaultSubnetValidatorTx)
+		if !ok || !tx.NodeID.Equals(args.ID) {
+			continue
+		}
+		up, elapsed, ok := service.vm.uptimes.uptime(tx.ID())
+		if !ok {
+			break
+		}
+		reply.TxID = tx.ID()
+		reply.StartTime = json.Uint64(tx.StartTime().Unix())
+		reply.EndTime = json.Uint64(tx.EndTime().Unix())
+		reply.Elapsed = json.Uint64(elapsed)
+		reply.UpDuration = json.Uint64(up)
+		reply.Uptime = 1
+		if elapsed != 0 {
+			reply.Uptime = float64(up) / float64(elapsed)
+		}
+		reply.UptimeRequirement = service.vm.uptimeRequirement
+		return nil
+	}
+	return fmt.Errorf("%s isn't validating the default subnet", args.ID)
+}
+
 /*
  ******************************************************
  *************** Get/Create Accounts ******************
@@ -301,6 +353,24 @@ func (service *Service) GetAccount(_ *http.Request, args *GetAccountArgs, reply
 	return nil
 }
 
+// GetTxFeeArgs are the arguments for calling GetTxFee
+type GetTxFeeArgs struct{}
+
+// GetTxFeeReply is the response from calling GetTxFee
+type GetTxFeeReply struct {
+	// Amount of AVA burnt by every transaction that has a payer, in addition
+	// to the AVA the transaction spends
+	TxFee json.Uint64 `json:"txFee"`
+}
+
+// GetTxFee returns the transaction fee
+func (service *Service) GetTxFee(_ *http.Request, _ *GetTxFeeArgs, reply *GetTxFeeReply) error {
+	service.vm.Ctx.Log.Debug("platform.getTxFee called")
+
+	reply.TxFee = json.Uint64(service.vm.txFee)
+	return nil
+}
+
 // ListAccountsArgs are the arguments to ListAccounts
 type ListAccountsArgs struct {
 	// List all of the accounts controlled by this user
@@ -317,19 +387,14 @@ type ListAccountsReply struct {
 func (service *Service) ListAccounts(_ *http.Request, args *ListAccountsArgs, reply *ListAccountsReply) error {
 	service.vm.Ctx.Log.Debug("platform.listAccounts called for user '%s'", args.Username)
 
-	// db holds the user's info that pertains to the Platform Chain
-	userDB, err := service.vm.Ctx.Keystore.GetDatabase(args.Username, args.Password)
+	// The user
+	signer, err := service.signer(args.Username, args.Password)
 	if err != nil {
 		return errGetUser
 	}
 
-	// The user
-	user := user{
-		db: userDB,
-	}
-
 	// IDs of accounts controlled by this user
-	accountIDs, err := user.getAccountIDs()
+	accountIDs, err := signer.Addresses()
 	if err != nil {
 		return errGetAccounts
 	}
@@ -380,6 +445,10 @@ type CreateAccountReply struct {
 func (service *Service) CreateAccount(_ *http.Request, args *CreateAccountArgs, reply *CreateAccountReply) error {
 	service.vm.Ctx.Log.Debug("platform.createAccount called for user '%s'", args.Username)
 
+	if service.vm.Ctx.RemoteSigner != nil {
+		return errRemoteSigner
+	}
+
 	// userDB holds the user's info that pertains to the Platform Chain
 	userDB, err := service.vm.Ctx.Keystore.GetDatabase(args.Username, args.Password)
 	if err != nil {
@@ -583,12 +652,70 @@ func (service *Service) AddNonDefaultSubnetValidator(_ *http.Request, args *AddN
 	return nil
 }
 
+// RemoveSubnetValidatorArgs are the arguments to RemoveSubnetValidator
+type RemoveSubnetValidatorArgs struct {
+	// ID of the node to remove
+	NodeID ids.ShortID `json:"nodeID"`
+
+	// ID of the subnet to remove the node from
+	SubnetID ids.ID `json:"subnetID"`
+
+	// Next unused nonce of the account the tx fee is paid from
+	PayerNonce json.Uint64 `json:"payerNonce"`
+}
+
+// RemoveSubnetValidatorResponse is the response from a call to RemoveSubnetValidator
+type RemoveSubnetValidatorResponse struct {
+	// The unsigned transaction
+	UnsignedTx formatting.CB58 `json:"unsignedTx"`
+}
+
+// RemoveSubnetValidator removes a validator from a subnet other than the default subnet before its end time
+// Returns the unsigned transaction, which must be signed by a threshold of the subnet's control keys using Sign
+func (service *Service) RemoveSubnetValidator(_ *http.Request, args *RemoveSubnetValidatorArgs, response *RemoveSubnetValidatorResponse) error {
+	service.vm.Ctx.Log.Debug("platform.removeSubnetValidator called")
+
+	if args.SubnetID.Equals(DefaultSubnetID) {
+		return errDSCantRemoveValidator
+	}
+
+	tx := RemoveSubnetValidatorTx{
+		UnsignedRemoveSubnetValidatorTx: UnsignedRemoveSubnetValidatorTx{
+			NetworkID: service.vm.Ctx.NetworkID,
+			Nonce:     uint64(args.PayerNonce),
+			NodeID:    args.NodeID,
+			SubnetID:  args.SubnetID,
+		},
+	}
+
+	txBytes, err := Codec.Marshal(genericTx{Tx: &tx})
+	if err != nil {
+		return errCreatingTransaction
+	}
+
+	response.UnsignedTx.Bytes = txBytes
+	return nil
+}
+
 /*
  ******************************************************
  **************** Sign/Issue Txs **********************
  ******************************************************
  */
 
+// signer returns the signer of the user [username]. The user's keys are held
+// by the remote signer if the node has one, and in the keystore otherwise.
+func (service *Service) signer(username, password string) (snow.Signer, error) {
+	if remote := service.vm.Ctx.RemoteSigner; remote != nil {
+		return remote.GetSigner(username, password)
+	}
+	db, err := service.vm.Ctx.Keystore.GetDatabase(username, password)
+	if err != nil {
+		return nil, err
+	}
+	return &user{db: db}, nil
+}
+
 // SignArgs are the arguments to Sign
 type SignArgs struct {
 	// The bytes to sign
@@ -614,19 +741,20 @@ func (service *Service) Sign(_ *http.Request, args *SignArgs, reply *SignRespons
 	service.vm.Ctx.Log.Debug("platform.sign called")
 
 	// Get the key of the Signer
-	db, err := service.vm.Ctx.Keystore.GetDatabase(args.Username, args.Password)
+	signer, err := service.signer(args.Username, args.Password)
 	if err != nil {
 		return fmt.Errorf("couldn't get data for user '%s'. Does user exist?", args.Username)
 	}
-	user := user{db: db}
-
-	key, err := user.getKey(args.Signer) // Key of [args.Signer]
+	accountIDs, err := signer.Addresses()
 	if err != nil {
-		return errDB
+		return errGetAccounts
 	}
-	if !bytes.Equal(key.PublicKey().Address().Bytes(), args.Signer.Bytes()) { // sanity check
-		return errors.New("got unexpected key from database")
+	controlled := ids.ShortSet{}
+	controlled.Add(accountIDs...)
+	if !controlled.Contains(args.Signer) {
+		return errNotSigner
 	}
+	key := &signerKey{signer: signer, addr: args.Signer}
 
 	genTx := genericTx{}
 	if err := Codec.Unmarshal(args.Tx.Bytes, &genTx); err != nil {
@@ -642,8 +770,18 @@ func (service *Service) Sign(_ *http.Request, args *SignArgs, reply *SignRespons
 		genTx.Tx, err = service.signAddNonDefaultSubnetValidatorTx(tx, key)
 	case *CreateSubnetTx:
 		genTx.Tx, err = service.signCreateSubnetTx(tx, key)
+	case *ExportTx:
+		genTx.Tx, err = service.signExportTx(tx, key)
+	case *ImportTx:
+		genTx.Tx, err = service.signImportTx(tx, key)
+	case *SetStakingParametersTx:
+		genTx.Tx, err = service.signSetStakingParametersTx(tx, key)
+	case *CreateChainTx:
+		genTx.Tx, err = service.signCreateChainTx(tx, key)
+	case *RemoveSubnetValidatorTx:
+		genTx.Tx, err = service.signRemoveSubnetValidatorTx(tx, key)
 	default:
-		err = errors.New("Could not parse given tx. Must be one of: addDefaultSubnetValidatorTx, addNonDefaultSubnetValidatorTx, createSubnetTx")
+		err = errors.New("Could not parse given tx. Must be one of: addDefaultSubnetValidatorTx, addNonDefaultSubnetValidatorTx, createSubnetTx, exportTx, importTx, setStakingParametersTx, createChainTx, removeSubnetValidatorTx")
 	}
 	if err != nil {
 		return err
@@ -654,7 +792,7 @@ func (service *Service) Sign(_ *http.Request, args *SignArgs, reply *SignRespons
 }
 
 // Sign [unsigned] with [key]
-func (service *Service) signAddDefaultSubnetValidatorTx(tx *addDefaultSubnetValidatorTx, key *crypto.PrivateKeySECP256K1R) (*addDefaultSubnetValidatorTx, error) {
+func (service *Service) signAddDefaultSubnetValidatorTx(tx *addDefaultSubnetValidatorTx, key signingKey) (*addDefaultSubnetValidatorTx, error) {
 	service.vm.Ctx.Log.Debug("platform.signAddDefaultSubnetValidatorTx called")
 
 	// TODO: Should we check if tx is already signed?
@@ -677,7 +815,7 @@ func (service *Service) signAddDefaultSubnetValidatorTx(tx *addDefaultSubnetVali
 }
 
 // Sign [unsigned] with [key]
-func (service *Service) signAddDefaultSubnetDelegatorTx(tx *addDefaultSubnetDelegatorTx, key *crypto.PrivateKeySECP256K1R) (*addDefaultSubnetDelegatorTx, error) {
+func (service *Service) signAddDefaultSubnetDelegatorTx(tx *addDefaultSubnetDelegatorTx, key signingKey) (*addDefaultSubnetDelegatorTx, error) {
 	service.vm.Ctx.Log.Debug("platform.signAddDefaultSubnetValidatorTx called")
 
 	// TODO: Should we check if tx is already signed?
@@ -700,7 +838,7 @@ func (service *Service) signAddDefaultSubnetDelegatorTx(tx *addDefaultSubnetDele
 }
 
 // Sign [xt] with [key]
-func (service *Service) signCreateSubnetTx(tx *CreateSubnetTx, key *crypto.PrivateKeySECP256K1R) (*CreateSubnetTx, error) {
+func (service *Service) signCreateSubnetTx(tx *CreateSubnetTx, key signingKey) (*CreateSubnetTx, error) {
 	service.vm.Ctx.Log.Debug("platform.signAddDefaultSubnetValidatorTx called")
 
 	// TODO: Should we check if tx is already signed?
@@ -722,13 +860,57 @@ func (service *Service) signCreateSubnetTx(tx *CreateSubnetTx, key *crypto.Priva
 	return tx, nil
 }
 
+// Sign [tx] with [key]
+func (service *Service) signExportTx(tx *ExportTx, key signingKey) (*ExportTx, error) {
+	service.vm.Ctx.Log.Debug("platform.signExportTx called")
+
+	unsignedIntf := interface{}(&tx.UnsignedExportTx)
+	unsignedTxBytes, err := Codec.Marshal(&unsignedIntf)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing unsigned tx: %v", err)
+	}
+
+	sig, err := key.Sign(unsignedTxBytes)
+	if err != nil {
+		return nil, errors.New("error while signing")
+	}
+	if len(sig) != crypto.SECP256K1RSigLen {
+		return nil, fmt.Errorf("expected signature to be length %d but was length %d", crypto.SECP256K1RSigLen, len(sig))
+	}
+	copy(tx.Sig[:], sig)
+
+	return tx, nil
+}
+
+// Sign [tx] with [key]
+func (service *Service) signImportTx(tx *ImportTx, key signingKey) (*ImportTx, error) {
+	service.vm.Ctx.Log.Debug("platform.signImportTx called")
+
+	unsignedIntf := interface{}(&tx.UnsignedImportTx)
+	unsignedTxBytes, err := Codec.Marshal(&unsignedIntf)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing unsigned tx: %v", err)
+	}
+
+	sig, err := key.Sign(unsignedTxBytes)
+	if err != nil {
+		return nil, errors.New("error while signing")
+	}
+	if len(sig) != crypto.SECP256K1RSigLen {
+		return nil, fmt.Errorf("expected signature to be length %d but was length %d", crypto.SECP256K1RSigLen, len(sig))
+	}
+	copy(tx.Sig[:], sig)
+
+	return tx, nil
+}
+
 // Signs an unsigned or partially signed addNonDefaultSubnetValidatorTx with [key]
 // If [key] is a control key for the subnet and there is an empty spot in tx.ControlSigs, signs there
 // If [key] is a control key for the subnet and there is no empty spot in tx.ControlSigs, signs as payer
 // If [key] is not a control key, sign as payer (account controlled by [key] pays the tx fee)
 // Sorts tx.ControlSigs before returning
 // Assumes each element of tx.ControlSigs is actually a signature, not just empty bytes
-func (service *Service) signAddNonDefaultSubnetValidatorTx(tx *addNonDefaultSubnetValidatorTx, key *crypto.PrivateKeySECP256K1R) (*addNonDefaultSubnetValidatorTx, error) {
+func (service *Service) signAddNonDefaultSubnetValidatorTx(tx *addNonDefaultSubnetValidatorTx, key signingKey) (*addNonDefaultSubnetValidatorTx, error) {
 	service.vm.Ctx.Log.Debug("platform.signAddNonDefaultSubnetValidatorTx called")
 
 	// Compute the byte repr. of the unsigned tx and the signature of [key] over it
@@ -757,13 +939,148 @@ func (service *Service) signAddNonDefaultSubnetValidatorTx(tx *addNonDefaultSubn
 	// If [key] is not a control key, sign as payer (account controlled by [key] pays the tx fee)
 	controlKeySet := ids.ShortSet{}
 	controlKeySet.Add(subnet.ControlKeys...)
-	isControlKey := controlKeySet.Contains(key.PublicKey().Address())
+	isControlKey := controlKeySet.Contains(key.Address())
+
+	payerSigEmpty := tx.PayerSig == [crypto.SECP256K1RSigLen]byte{} // true if no key has signed to pay the tx fee
+
+	if isControlKey && len(tx.ControlSigs) != int(subnet.Threshold) { // Sign as controlSig
+		tx.ControlSigs = append(tx.ControlSigs, [crypto.SECP256K1RSigLen]byte{})
+		copy(tx.ControlSigs[len(tx.ControlSigs)-1][:], sig)
+	} else if payerSigEmpty { // sign as payer
+		copy(tx.PayerSig[:], sig)
+	} else {
+		return nil, errors.New("no place for key to sign")
+	}
+
+	return tx, nil
+}
+
+// Signs an unsigned or partially signed SetStakingParametersTx with [key]
+// If [key] governs the staking parameters and there is an empty spot in tx.ControlSigs, signs there
+// Otherwise, signs as payer (account controlled by [key] pays the tx fee)
+// Sorts tx.ControlSigs before returning
+func (service *Service) signSetStakingParametersTx(tx *SetStakingParametersTx, key signingKey) (*SetStakingParametersTx, error) {
+	service.vm.Ctx.Log.Debug("platform.signSetStakingParametersTx called")
+
+	// Compute the byte repr. of the unsigned tx and the signature of [key] over it
+	unsignedIntf := interface{}(&tx.UnsignedSetStakingParametersTx)
+	unsignedTxBytes, err := Codec.Marshal(&unsignedIntf)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing unsigned tx: %v", err)
+	}
+	sig, err := key.Sign(unsignedTxBytes)
+	if err != nil {
+		return nil, errors.New("error while signing")
+	}
+	if len(sig) != crypto.SECP256K1RSigLen {
+		return nil, fmt.Errorf("expected signature to be length %d but was length %d", crypto.SECP256K1RSigLen, len(sig))
+	}
+
+	// Get the keys that govern the staking parameters
+	schedule, err := service.vm.getParameterSchedule(service.vm.DB)
+	if err != nil {
+		return nil, fmt.Errorf("problem getting staking parameters: %v", err)
+	}
+	controlKeySet := ids.ShortSet{}
+	controlKeySet.Add(schedule.Governance.ControlKeys...)
+	isControlKey := controlKeySet.Contains(key.Address())
+
+	payerSigEmpty := tx.PayerSig == [crypto.SECP256K1RSigLen]byte{} // true if no key has signed to pay the tx fee
+
+	if isControlKey && len(tx.ControlSigs) != int(schedule.Governance.Threshold) { // Sign as controlSig
+		tx.ControlSigs = append(tx.ControlSigs, [crypto.SECP256K1RSigLen]byte{})
+		copy(tx.ControlSigs[len(tx.ControlSigs)-1][:], sig)
+		crypto.SortSECP2561RSigs(tx.ControlSigs)
+	} else if payerSigEmpty { // sign as payer
+		copy(tx.PayerSig[:], sig)
+	} else {
+		return nil, errors.New("no place for key to sign")
+	}
+
+	return tx, nil
+}
+
+// Signs an unsigned or partially signed CreateChainTx with [key]
+// If [key] is a control key for the subnet and there is an empty spot in tx.ControlSigs, signs there
+// Otherwise, signs as payer (account controlled by [key] pays the tx fee)
+// Sorts tx.ControlSigs before returning
+func (service *Service) signCreateChainTx(tx *CreateChainTx, key signingKey) (*CreateChainTx, error) {
+	service.vm.Ctx.Log.Debug("platform.signCreateChainTx called")
+
+	// Compute the byte repr. of the unsigned tx and the signature of [key] over it
+	unsignedIntf := interface{}(&tx.UnsignedCreateChainTx)
+	unsignedTxBytes, err := Codec.Marshal(&unsignedIntf)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing unsigned tx: %v", err)
+	}
+	sig, err := key.Sign(unsignedTxBytes)
+	if err != nil {
+		return nil, errors.New("error while signing")
+	}
+	if len(sig) != crypto.SECP256K1RSigLen {
+		return nil, fmt.Errorf("expected signature to be length %d but was length %d", crypto.SECP256K1RSigLen, len(sig))
+	}
+
+	// Get information about the subnet
+	subnet, err := service.vm.getSubnet(service.vm.DB, tx.SubnetID)
+	if err != nil {
+		return nil, fmt.Errorf("problem getting subnet information: %v", err)
+	}
+	controlKeySet := ids.ShortSet{}
+	controlKeySet.Add(subnet.ControlKeys...)
+	isControlKey := controlKeySet.Contains(key.Address())
+
+	payerSigEmpty := tx.PayerSig == [crypto.SECP256K1RSigLen]byte{} // true if no key has signed to pay the tx fee
+
+	if isControlKey && len(tx.ControlSigs) != int(subnet.Threshold) { // Sign as controlSig
+		tx.ControlSigs = append(tx.ControlSigs, [crypto.SECP256K1RSigLen]byte{})
+		copy(tx.ControlSigs[len(tx.ControlSigs)-1][:], sig)
+		crypto.SortSECP2561RSigs(tx.ControlSigs)
+	} else if payerSigEmpty { // sign as payer
+		copy(tx.PayerSig[:], sig)
+	} else {
+		return nil, errors.New("no place for key to sign")
+	}
+
+	return tx, nil
+}
+
+// Signs an unsigned or partially signed RemoveSubnetValidatorTx with [key]
+// If [key] is a control key for the subnet and there is an empty spot in tx.ControlSigs, signs there
+// Otherwise, signs as payer (account controlled by [key] pays the tx fee)
+// Sorts tx.ControlSigs before returning
+func (service *Service) signRemoveSubnetValidatorTx(tx *RemoveSubnetValidatorTx, key signingKey) (*RemoveSubnetValidatorTx, error) {
+	service.vm.Ctx.Log.Debug("platform.signRemoveSubnetValidatorTx called")
+
+	// Compute the byte repr. of the unsigned tx and the signature of [key] over it
+	unsignedIntf := interface{}(&tx.UnsignedRemoveSubnetValidatorTx)
+	unsignedTxBytes, err := Codec.Marshal(&unsignedIntf)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing unsigned tx: %v", err)
+	}
+	sig, err := key.Sign(unsignedTxBytes)
+	if err != nil {
+		return nil, errors.New("error while signing")
+	}
+	if len(sig) != crypto.SECP256K1RSigLen {
+		return nil, fmt.Errorf("expected signature to be length %d but was length %d", crypto.SECP256K1RSigLen, len(sig))
+	}
+
+	// Get information about the subnet
+	subnet, err := service.vm.getSubnet(service.vm.DB, tx.SubnetID)
+	if err != nil {
+		return nil, fmt.Errorf("problem getting subnet information: %v", err)
+	}
+	controlKeySet := ids.ShortSet{}
+	controlKeySet.Add(subnet.ControlKeys...)
+	isControlKey := controlKeySet.Contains(key.Address())
 
 	payerSigEmpty := tx.PayerSig == [crypto.SECP256K1RSigLen]byte{} // true if no key has signed to pay the tx fee
 
 	if isControlKey && len(tx.ControlSigs) != int(subnet.Threshold) { // Sign as controlSig
 		tx.ControlSigs = append(tx.ControlSigs, [crypto.SECP256K1RSigLen]byte{})
 		copy(tx.ControlSigs[len(tx.ControlSigs)-1][:], sig)
+		crypto.SortSECP2561RSigs(tx.ControlSigs)
 	} else if payerSigEmpty { // sign as payer
 		copy(tx.PayerSig[:], sig)
 	} else {
@@ -809,8 +1126,48 @@ func (service *Service) IssueTx(_ *http.Request, args *IssueTxArgs, response *Is
 		defer service.vm.resetTimer()
 		response.TxID = tx.ID
 		return nil
+	case *ExportTx:
+		if err := tx.initialize(service.vm); err != nil {
+			return fmt.Errorf("error initializing tx: %s", err)
+		}
+		service.vm.unissuedDecisionTxs = append(service.vm.unissuedDecisionTxs, tx)
+		defer service.vm.resetTimer()
+		response.TxID = tx.ID()
+		return nil
+	case *ImportTx:
+		if err := tx.initialize(service.vm); err != nil {
+			return fmt.Errorf("error initializing tx: %s", err)
+		}
+		service.vm.unissuedDecisionTxs = append(service.vm.unissuedDecisionTxs, tx)
+		defer service.vm.resetTimer()
+		response.TxID = tx.ID()
+		return nil
+	case *SetStakingParametersTx:
+		if err := tx.initialize(service.vm); err != nil {
+			return fmt.Errorf("error initializing tx: %s", err)
+		}
+		service.vm.unissuedDecisionTxs = append(service.vm.unissuedDecisionTxs, tx)
+		defer service.vm.resetTimer()
+		response.TxID = tx.ID()
+		return nil
+	case *CreateChainTx:
+		if err := tx.initialize(service.vm); err != nil {
+			return fmt.Errorf("error initializing tx: %s", err)
+		}
+		service.vm.unissuedDecisionTxs = append(service.vm.unissuedDecisionTxs, tx)
+		defer service.vm.resetTimer()
+		response.TxID = tx.ID()
+		return nil
+	case *RemoveSubnetValidatorTx:
+		if err := tx.initialize(service.vm); err != nil {
+			return fmt.Errorf("error initializing tx: %s", err)
+		}
+		service.vm.unissuedDecisionTxs = append(service.vm.unissuedDecisionTxs, tx)
+		defer service.vm.resetTimer()
+		response.TxID = tx.ID()
+		return nil
 	default:
-		return errors.New("Could not parse given tx. Must be one of: addDefaultSubnetValidatorTx, addDefaultSubnetDelegatorTx, addNonDefaultSubnetValidatorTx, createSubnetTx")
+		return errors.New("Could not parse given tx. Must be one of: addDefaultSubnetValidatorTx, addDefaultSubnetDelegatorTx, addNonDefaultSubnetValidatorTx, createSubnetTx, exportTx, importTx, setStakingParametersTx, createChainTx, removeSubnetValidatorTx")
 	}
 }
 
@@ -863,6 +1220,234 @@ func (service *Service) CreateSubnet(_ *http.Request, args *CreateSubnetArgs, re
 
 }
 
+/*
+ ******************************************************
+ ************** Get/Set Staking Parameters ************
+ ******************************************************
+ */
+
+// GetStakingParametersArgs are the arguments for calling GetStakingParameters
+type GetStakingParametersArgs struct{}
+
+// GetStakingParametersReply is the response from calling GetStakingParameters
+type GetStakingParametersReply struct {
+	// The staking parameters in effect at the last accepted chain timestamp
+	Current APIStakingParameters `json:"current"`
+
+	// The staking parameters that are scheduled to take effect at
+	// [ActivationTime], if any
+	Pending        *APIStakingParameters `json:"pending,omitempty"`
+	ActivationTime json.Uint64           `json:"activationTime"`
+
+	// The keys that can change the staking parameters
+	Governance APIGovernance `json:"governance"`
+}
+
+// GetStakingParameters returns the staking parameters, and the change to them
+// that is scheduled, if any
+func (service *Service) GetStakingParameters(_ *http.Request, _ *GetStakingParametersArgs, reply *GetStakingParametersReply) error {
+	service.vm.Ctx.Log.Debug("platform.getStakingParameters called")
+
+	schedule, err := service.vm.getParameterSchedule(service.vm.DB)
+	if err != nil {
+		return fmt.Errorf("couldn't get staking parameters: %w", err)
+	}
+	timestamp, err := service.vm.getTimestamp(service.vm.DB)
+	if err != nil {
+		return fmt.Errorf("couldn't get timestamp: %w", err)
+	}
+
+	current := schedule.at(timestamp)
+	reply.Current = newAPIStakingParameters(current)
+	if current != &schedule.Pending && schedule.ActivationTime != 0 {
+		pending := newAPIStakingParameters(&schedule.Pending)
+		reply.Pending = &pending
+		reply.ActivationTime = json.Uint64(schedule.ActivationTime)
+	}
+	reply.Governance = APIGovernance{
+		ControlKeys: schedule.Governance.ControlKeys,
+		Threshold:   json.Uint16(schedule.Governance.Threshold),
+	}
+	return nil
+}
+
+// SetStakingParametersArgs are the arguments to SetStakingParameters
+type SetStakingParametersArgs struct {
+	// The staking parameters to change to
+	Parameters APIStakingParameters `json:"parameters"`
+
+	// Unix time at which [Parameters] take effect
+	ActivationTime json.Uint64 `json:"activationTime"`
+
+	// Nonce of the account that pays the transaction fee
+	PayerNonce json.Uint64 `json:"payerNonce"`
+}
+
+// SetStakingParametersResponse is the response from a call to
+// SetStakingParameters
+type SetStakingParametersResponse struct {
+	// Byte representation of the unsigned transaction to change the staking
+	// parameters
+	UnsignedTx formatting.CB58 `json:"unsignedTx"`
+}
+
+// SetStakingParameters returns an unsigned transaction to change the staking
+// parameters at [args.ActivationTime].
+// The unsigned transaction must be signed with a threshold of the keys that
+// govern the staking parameters, and then with the key of the payer.
+func (service *Service) SetStakingParameters(_ *http.Request, args *SetStakingParametersArgs, response *SetStakingParametersResponse) error {
+	service.vm.Ctx.Log.Debug("platform.setStakingParameters called")
+
+	params := args.Parameters.stakingParameters()
+	if err := params.Verify(); err != nil {
+		return fmt.Errorf("invalid staking parameters: %w", err)
+	}
+
+	// Create the transaction
+	tx := SetStakingParametersTx{
+		UnsignedSetStakingParametersTx: UnsignedSetStakingParametersTx{
+			NetworkID:      service.vm.Ctx.NetworkID,
+			Nonce:          uint64(args.PayerNonce),
+			Parameters:     params,
+			ActivationTime: uint64(args.ActivationTime),
+		},
+	}
+
+	txBytes, err := Codec.Marshal(genericTx{Tx: &tx})
+	if err != nil {
+		return errCreatingTransaction
+	}
+
+	response.UnsignedTx.Bytes = txBytes
+	return nil
+}
+
+/*
+ ******************************************************
+ ******** Transfer AVA to/from the AVM chain **********
+ ******************************************************
+ */
+
+// ExportAVAArgs are the arguments to ExportAVA
+type ExportAVAArgs struct {
+	// Amount of AVA to send
+	Amount json.Uint64 `json:"amount"`
+
+	// Address on the AVM chain the AVA is sent to
+	To ids.ShortID `json:"to"`
+
+	// Nonce of the account that pays the transaction fee and sends the AVA
+	PayerNonce json.Uint64 `json:"payerNonce"`
+}
+
+// ExportAVAResponse is the response from a call to ExportAVA
+type ExportAVAResponse struct {
+	// Byte representation of the unsigned transaction to export AVA
+	UnsignedTx formatting.CB58 `json:"unsignedTx"`
+}
+
+// ExportAVA returns an unsigned transaction to send AVA to the AVM chain.
+// The unsigned transaction must be signed with the key of the account the AVA
+// is sent from. The AVA must then be imported on the AVM chain.
+func (service *Service) ExportAVA(_ *http.Request, args *ExportAVAArgs, response *ExportAVAResponse) error {
+	service.vm.Ctx.Log.Debug("platform.exportAVA called")
+
+	// Create the transaction
+	tx := ExportTx{
+		UnsignedExportTx: UnsignedExportTx{
+			NetworkID: service.vm.Ctx.NetworkID,
+			Nonce:     uint64(args.PayerNonce),
+			Amount:    uint64(args.Amount),
+			To:        args.To,
+		},
+	}
+
+	txBytes, err := Codec.Marshal(genericTx{Tx: &tx})
+	if err != nil {
+		return errCreatingTransaction
+	}
+
+	response.UnsignedTx.Bytes = txBytes
+	return nil
+}
+
+// ImportAVAArgs are the arguments to ImportAVA
+type ImportAVAArgs struct {
+	// Account the AVA is imported to
+	To ids.ShortID `json:"to"`
+
+	// Nonce of the account that pays the transaction fee and receives the AVA
+	PayerNonce json.Uint64 `json:"payerNonce"`
+}
+
+// ImportAVAResponse is the response from a call to ImportAVA
+type ImportAVAResponse struct {
+	// Byte representation of the unsigned transaction to import AVA
+	UnsignedTx formatting.CB58 `json:"unsignedTx"`
+}
+
+// ImportAVA returns an unsigned transaction that imports all the AVA the AVM
+// chain sent to [args.To]. The unsigned transaction must be signed with the key
+// of [args.To].
+func (service *Service) ImportAVA(_ *http.Request, args *ImportAVAArgs, response *ImportAVAResponse) error {
+	service.vm.Ctx.Log.Debug("platform.importAVA called")
+
+	if service.vm.avm.IsZero() {
+		return errNoAVMChain
+	}
+
+	utxoIDs, err := service.importableUTXOIDs(args.To)
+	if err != nil {
+		return fmt.Errorf("problem retrieving imported UTXOs: %w", err)
+	}
+	if len(utxoIDs) == 0 {
+		return errNoImportedUTXOs
+	}
+	ids.SortIDs(utxoIDs)
+
+	// Create the transaction
+	tx := ImportTx{
+		UnsignedImportTx: UnsignedImportTx{
+			NetworkID: service.vm.Ctx.NetworkID,
+			Nonce:     uint64(args.PayerNonce),
+			UTXOIDs:   utxoIDs,
+		},
+	}
+
+	txBytes, err := Codec.Marshal(genericTx{Tx: &tx})
+	if err != nil {
+		return errCreatingTransaction
+	}
+
+	response.UnsignedTx.Bytes = txBytes
+	return nil
+}
+
+// importableUTXOIDs returns the IDs of the AVA UTXOs that the AVM chain sent to
+// [addr] and that haven't been imported yet
+func (service *Service) importableUTXOIDs(addr ids.ShortID) ([]ids.ID, error) {
+	smDB := service.vm.Ctx.SharedMemory.GetDatabase(service.vm.avm)
+	defer service.vm.Ctx.SharedMemory.ReleaseDatabase(service.vm.avm)
+
+	state := shared.NewState(smDB, service.vm.Ctx.ChainID)
+	utxoIDs, err := state.Funds(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	importable := []ids.ID(nil)
+	for _, utxoID := range utxoIDs {
+		utxo, err := state.UTXO(utxoID)
+		if err != nil {
+			return nil, err
+		}
+		if utxo.AssetID.Equals(service.vm.ava) && utxo.Out.Threshold == 1 {
+			importable = append(importable, utxoID)
+		}
+	}
+	return importable, nil
+}
+
 /*
  ******************************************************
  ******** Create/get status of a blockchain ***********
@@ -871,6 +1456,9 @@ func (service *Service) CreateSubnet(_ *http.Request, args *CreateSubnetArgs, re
 
 // CreateBlockchainArgs is the arguments for calling CreateBlockchain
 type CreateBlockchainArgs struct {
+	// ID of the subnet that validates the new blockchain
+	SubnetID ids.ID `json:"subnetID"`
+
 	// ID of the VM the new blockchain is running
 	VMID string `json:"vmID"`
 
@@ -886,6 +1474,9 @@ type CreateBlockchainArgs struct {
 	Method      string      `json:"method"`
 	Endpoint    string      `json:"endpoint"`
 	GenesisData interface{} `json:"genesisData"`
+
+	// Next unused nonce of the account the tx fee is paid from
+	PayerNonce json.Uint64 `json:"payerNonce"`
 }
 
 // CreateGenesisReply is the reply from a call to CreateGenesis
@@ -895,11 +1486,21 @@ type CreateGenesisReply struct {
 
 // CreateBlockchainReply is the reply from calling CreateBlockchain
 type CreateBlockchainReply struct {
-	BlockchainID ids.ID `json:"blockchainID"`
+	// The unsigned transaction
+	UnsignedTx formatting.CB58 `json:"unsignedTx"`
 }
 
-// CreateBlockchain issues a transaction to the network to create a new blockchain
+// CreateBlockchain returns an unsigned transaction to create a new blockchain
+// validated by the subnet [args.SubnetID].
+// The transaction must be signed by a threshold of the subnet's control keys
+// using Sign. The ID of the issued transaction is the ID of the new blockchain.
 func (service *Service) CreateBlockchain(_ *http.Request, args *CreateBlockchainArgs, reply *CreateBlockchainReply) error {
+	service.vm.Ctx.Log.Debug("platform.createBlockchain called")
+
+	if args.SubnetID.Equals(DefaultSubnetID) {
+		return errDSCantCreateChain
+	}
+
 	vmID, err := service.vm.ChainManager.LookupVM(args.VMID)
 	if err != nil {
 		return fmt.Errorf("no VM with ID '%s' found", args.VMID)
@@ -942,19 +1543,24 @@ func (service *Service) CreateBlockchain(_ *http.Request, args *CreateBlockchain
 		return errNoMethodWithGenesis
 	}
 
-	// TODO: Should use the key store to sign this transaction.
-	// TODO: Nonce shouldn't always be 0
-	tx, err := service.vm.newCreateChainTx(0, genesisBytes, vmID, fxIDs, args.Name, service.vm.Ctx.NetworkID, key)
-	if err != nil {
-		return fmt.Errorf("problem creating transaction: %w", err)
+	tx := CreateChainTx{
+		UnsignedCreateChainTx: UnsignedCreateChainTx{
+			NetworkID:   service.vm.Ctx.NetworkID,
+			Nonce:       uint64(args.PayerNonce),
+			SubnetID:    args.SubnetID,
+			ChainName:   args.Name,
+			VMID:        vmID,
+			FxIDs:       fxIDs,
+			GenesisData: genesisBytes,
+		},
 	}
 
-	// Add this tx to the set of unissued txs
-	service.vm.unissuedDecisionTxs = append(service.vm.unissuedDecisionTxs, tx)
-	service.vm.resetTimer()
-
-	reply.BlockchainID = tx.ID()
+	txBytes, err := Codec.Marshal(genericTx{Tx: &tx})
+	if err != nil {
+		return errCreatingTransaction
+	}
 
+	reply.UnsignedTx.Bytes = txBytes
 	return nil
 }
 
@@ -6,6 +6,13 @@ package platformvm
 import (
 	"encoding/json"
 	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/database/versiondb"
+	"github.com/ava-labs/gecko/utils/crypto"
+
+	cjson "github.com/ava-labs/gecko/utils/json"
 )
 
 func TestAddDefaultSubnetValidator(t *testing.T) {
@@ -32,3 +39,148 @@ func TestCreateBlockchainArgsParsing(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestGetTxFee(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+
+	reply := GetTxFeeReply{}
+	if err := service.GetTxFee(nil, &GetTxFeeArgs{}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if uint64(reply.TxFee) != defaultTxFee {
+		t.Fatalf("Wrong tx fee returned. Expected: %d ; Returned: %d", defaultTxFee, reply.TxFee)
+	}
+}
+
+func TestGetStakingParameters(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+
+	reply := GetStakingParametersReply{}
+	if err := service.GetStakingParameters(nil, &GetStakingParametersArgs{}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if reply.Current != newAPIStakingParameters(&DefaultStakingParameters) {
+		t.Fatalf("Wrong staking parameters returned: %+v", reply.Current)
+	}
+	if uint64(reply.Current.MinimumStakingDuration) != 24*60*60 {
+		t.Fatalf("Durations should be returned in seconds")
+	}
+	if reply.Pending != nil || reply.ActivationTime != 0 {
+		t.Fatalf("No change should be scheduled")
+	}
+	if reply.Governance.Threshold != 2 || len(reply.Governance.ControlKeys) != len(testGovernanceKeys) {
+		t.Fatalf("Wrong governance returned: %+v", reply.Governance)
+	}
+
+	// Schedule a change
+	schedule, err := vm.getParameterSchedule(vm.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pending := DefaultStakingParameters
+	pending.BatchSize = 1
+	activationTime := uint64(defaultGenesisTime.Add(time.Hour).Unix())
+	schedule.schedule(pending, activationTime, defaultGenesisTime)
+	if err := vm.putParameterSchedule(vm.DB, schedule); err != nil {
+		t.Fatal(err)
+	}
+
+	reply = GetStakingParametersReply{}
+	if err := service.GetStakingParameters(nil, &GetStakingParametersArgs{}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if reply.Pending == nil || reply.Pending.BatchSize != 1 || uint64(reply.ActivationTime) != activationTime {
+		t.Fatalf("The scheduled change should have been returned")
+	}
+}
+
+func TestSetStakingParameters(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+
+	args := SetStakingParametersArgs{
+		Parameters:     newAPIStakingParameters(&DefaultStakingParameters),
+		ActivationTime: cjson.Uint64(defaultGenesisTime.Add(time.Hour).Unix()),
+		PayerNonce:     defaultNonce + 1,
+	}
+	reply := SetStakingParametersResponse{}
+	if err := service.SetStakingParameters(nil, &args, &reply); err != nil {
+		t.Fatal(err)
+	}
+
+	genTx := genericTx{}
+	if err := Codec.Unmarshal(reply.UnsignedTx.Bytes, &genTx); err != nil {
+		t.Fatal(err)
+	}
+	tx, ok := genTx.Tx.(*SetStakingParametersTx)
+	if !ok {
+		t.Fatalf("Should have returned a SetStakingParametersTx")
+	}
+	if tx.Parameters != DefaultStakingParameters {
+		t.Fatalf("Wrong staking parameters in tx: %+v", tx.Parameters)
+	}
+
+	// Sign with a threshold of the governing keys, then the payer. The keys
+	// are held by a user, who signs without handing them out.
+	signer := &user{db: memdb.New()}
+	for _, key := range []*crypto.PrivateKeySECP256K1R{testGovernanceKeys[0], testGovernanceKeys[1], defaultKey} {
+		if err := signer.putAccount(key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, key := range testGovernanceKeys[:2] {
+		key := &signerKey{signer: signer, addr: key.PublicKey().Address()}
+		if _, err := service.signSetStakingParametersTx(tx, key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	payer := &signerKey{signer: signer, addr: defaultKey.PublicKey().Address()}
+	if _, err := service.signSetStakingParametersTx(tx, payer); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.initialize(vm); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.SemanticVerify(versiondb.New(vm.DB)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Invalid staking parameters are rejected
+	args.Parameters.BatchSize = 0
+	if err := service.SetStakingParameters(nil, &args, &reply); err == nil {
+		t.Fatal("Should have errored due to invalid staking parameters")
+	}
+}
+
+func TestRemoveSubnetValidator(t *testing.T) {
+	vm := defaultVM()
+	service := Service{vm: vm}
+
+	args := RemoveSubnetValidatorArgs{
+		NodeID:     keys[0].PublicKey().Address(),
+		SubnetID:   DefaultSubnetID,
+		PayerNonce: cjson.Uint64(defaultNonce + 1),
+	}
+	reply := RemoveSubnetValidatorResponse{}
+	if err := service.RemoveSubnetValidator(nil, &args, &reply); err != errDSCantRemoveValidator {
+		t.Fatalf("Should have errored with %s but errored with %v", errDSCantRemoveValidator, err)
+	}
+
+	args.SubnetID = testSubnet1.ID
+	if err := service.RemoveSubnetValidator(nil, &args, &reply); err != nil {
+		t.Fatal(err)
+	}
+	genTx := genericTx{}
+	if err := Codec.Unmarshal(reply.UnsignedTx.Bytes, &genTx); err != nil {
+		t.Fatal(err)
+	}
+	tx, ok := genTx.Tx.(*RemoveSubnetValidatorTx)
+	if !ok {
+		t.Fatalf("Wrong tx type returned: %T", genTx.Tx)
+	}
+	if !tx.NodeID.Equals(args.NodeID) || !tx.SubnetID.Equals(testSubnet1.ID) || tx.Nonce != defaultNonce+1 {
+		t.Fatalf("Wrong tx returned: %+v", tx.UnsignedRemoveSubnetValidatorTx)
+	}
+}
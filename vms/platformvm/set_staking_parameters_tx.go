@@ -0,0 +1,220 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/hashing"
+)
+
+// UnsignedSetStakingParametersTx is an unsigned SetStakingParametersTx
+type UnsignedSetStakingParametersTx struct {
+	// ID of the network this blockchain exists on
+	NetworkID uint32 `serialize:"true"`
+
+	// Next unused nonce of the account paying the transaction fee
+	Nonce uint64 `serialize:"true"`
+
+	// The staking parameters to change to
+	Parameters StakingParameters `serialize:"true"`
+
+	// Unix time at which [Parameters] take effect. That is, they're the staking
+	// parameters of the first chain timestamp at or after this time.
+	ActivationTime uint64 `serialize:"true"`
+}
+
+// SetStakingParametersTx schedules a change to the staking parameters.
+// It must be signed by a threshold of the keys that govern the staking
+// parameters. If it's accepted before an earlier scheduled change takes
+// effect, it replaces that change.
+type SetStakingParametersTx struct {
+	UnsignedSetStakingParametersTx `serialize:"true"`
+
+	// Signatures of the keys that govern the staking parameters
+	ControlSigs [][crypto.SECP256K1RSigLen]byte `serialize:"true"`
+
+	// PayerSig is the signature of the public key whose corresponding account
+	// pays the tx fee for this tx
+	PayerSig [crypto.SECP256K1RSigLen]byte `serialize:"true"`
+
+	vm         *VM
+	id         ids.ID
+	controlIDs []ids.ShortID
+	senderID   ids.ShortID
+	bytes      []byte
+}
+
+func (tx *SetStakingParametersTx) initialize(vm *VM) error {
+	tx.vm = vm
+	txBytes, err := Codec.Marshal(tx) // byte repr. of the signed tx
+	tx.bytes = txBytes
+	tx.id = ids.NewID(hashing.ComputeHash256Array(txBytes))
+	return err
+}
+
+// ID of this transaction
+func (tx *SetStakingParametersTx) ID() ids.ID { return tx.id }
+
+// Bytes returns the byte representation of a SetStakingParametersTx
+func (tx *SetStakingParametersTx) Bytes() []byte { return tx.bytes }
+
+// SyntacticVerify this transaction is well-formed
+// Also populates [tx.controlIDs] and [tx.senderID]
+func (tx *SetStakingParametersTx) SyntacticVerify() error {
+	switch {
+	case tx == nil:
+		return errNilTx
+	case !tx.senderID.IsZero():
+		return nil // Only verify the transaction once
+	case tx.NetworkID != tx.vm.Ctx.NetworkID: // verify the transaction is on this network
+		return errWrongNetworkID
+	case tx.id.IsZero():
+		return errInvalidID
+	case !crypto.IsSortedAndUniqueSECP2561RSigs(tx.ControlSigs):
+		return errSigsNotSorted
+	}
+	if err := tx.Parameters.Verify(); err != nil {
+		return err
+	}
+
+	unsignedIntf := interface{}(&tx.UnsignedSetStakingParametersTx)
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf) // byte repr of unsigned tx
+	if err != nil {
+		return err
+	}
+	unsignedBytesHash := hashing.ComputeHash256(unsignedBytes)
+
+	controlIDs := make([]ids.ShortID, len(tx.ControlSigs))
+	for i, sig := range tx.ControlSigs {
+		key, err := tx.vm.factory.RecoverHashPublicKey(unsignedBytesHash, sig[:])
+		if err != nil {
+			return err
+		}
+		controlIDs[i] = key.Address()
+	}
+
+	key, err := tx.vm.factory.RecoverHashPublicKey(unsignedBytesHash, tx.PayerSig[:])
+	if err != nil {
+		return err
+	}
+	tx.controlIDs = controlIDs
+	tx.senderID = key.Address()
+	return nil
+}
+
+// SemanticVerify this transaction is valid.
+func (tx *SetStakingParametersTx) SemanticVerify(db database.Database) (func(), error) {
+	if err := tx.SyntacticVerify(); err != nil {
+		return nil, err
+	}
+
+	schedule, err := tx.vm.getParameterSchedule(db)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure the tx is signed by a threshold of the keys that govern the
+	// staking parameters
+	governance := schedule.Governance
+	if governance.Threshold == 0 {
+		return nil, errNotGovernable
+	}
+	if len(tx.controlIDs) != int(governance.Threshold) {
+		return nil, fmt.Errorf("expected tx to have %d control sigs but has %d", governance.Threshold, len(tx.controlIDs))
+	}
+	controlKeys := ids.ShortSet{}
+	controlKeys.Add(governance.ControlKeys...)
+	signers := ids.ShortSet{}
+	for _, controlID := range tx.controlIDs {
+		if !controlKeys.Contains(controlID) {
+			return nil, errUnknownControlKey
+		}
+		if signers.Contains(controlID) {
+			return nil, errDuplicateControlSig
+		}
+		signers.Add(controlID)
+	}
+
+	// Ensure the change takes effect in the future, so that every staker's
+	// transaction is verified with the staking parameters of its block
+	timestamp, err := tx.vm.getTimestamp(db)
+	if err != nil {
+		return nil, err
+	}
+	if tx.ActivationTime <= uint64(timestamp.Unix()) {
+		return nil, errActivationTimeTooEarly
+	}
+
+	schedule.schedule(tx.Parameters, tx.ActivationTime, timestamp)
+	if err := tx.vm.putParameterSchedule(db, schedule); err != nil {
+		return nil, err
+	}
+
+	// Deduct tx fee from payer's account
+	account, err := tx.vm.getAccount(db, tx.senderID)
+	if err != nil {
+		return nil, err
+	}
+	account, err = account.Remove(0, tx.vm.txFee, tx.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.vm.putAccount(db, account); err != nil {
+		return nil, err
+	}
+	if err := tx.vm.burn(db, tx.vm.txFee); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (vm *VM) newSetStakingParametersTx(
+	nonce uint64,
+	params StakingParameters,
+	activationTime uint64,
+	networkID uint32,
+	controlKeys []*crypto.PrivateKeySECP256K1R,
+	payerKey *crypto.PrivateKeySECP256K1R,
+) (*SetStakingParametersTx, error) {
+	tx := &SetStakingParametersTx{
+		UnsignedSetStakingParametersTx: UnsignedSetStakingParametersTx{
+			NetworkID:      networkID,
+			Nonce:          nonce,
+			Parameters:     params,
+			ActivationTime: activationTime,
+		},
+	}
+
+	unsignedIntf := interface{}(&tx.UnsignedSetStakingParametersTx)
+	unsignedBytes, err := Codec.Marshal(&unsignedIntf) // byte repr. of unsigned tx
+	if err != nil {
+		return nil, err
+	}
+	unsignedHash := hashing.ComputeHash256(unsignedBytes)
+
+	// Sign this tx with each control key
+	tx.ControlSigs = make([][crypto.SECP256K1RSigLen]byte, len(controlKeys))
+	for i, key := range controlKeys {
+		sig, err := key.SignHash(unsignedHash)
+		if err != nil {
+			return nil, err
+		}
+		copy(tx.ControlSigs[i][:], sig)
+	}
+	crypto.SortSECP2561RSigs(tx.ControlSigs)
+
+	// Sign this tx with the key of the tx fee payer
+	sig, err := payerKey.SignHash(unsignedHash)
+	if err != nil {
+		return nil, err
+	}
+	copy(tx.PayerSig[:], sig)
+
+	return tx, tx.initialize(vm)
+}
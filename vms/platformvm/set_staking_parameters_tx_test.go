@@ -0,0 +1,230 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/database/versiondb"
+	"github.com/ava-labs/gecko/utils/crypto"
+)
+
+func TestSetStakingParametersTxSyntacticVerify(t *testing.T) {
+	vm := defaultVM()
+	activationTime := uint64(defaultGenesisTime.Add(time.Hour).Unix())
+
+	// Case 1: tx is nil
+	var tx *SetStakingParametersTx
+	if err := tx.SyntacticVerify(); err == nil {
+		t.Fatal("should have failed because tx is nil")
+	}
+
+	// Case 2: network ID is wrong
+	tx, err := vm.newSetStakingParametersTx(
+		defaultNonce+1,
+		DefaultStakingParameters,
+		activationTime,
+		testNetworkID+1,
+		testGovernanceKeys[:2],
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err == nil {
+		t.Fatal("should have failed because network ID is wrong")
+	}
+
+	// Case 3: staking parameters are invalid
+	params := DefaultStakingParameters
+	params.BatchSize = 0
+	tx, err = vm.newSetStakingParametersTx(
+		defaultNonce+1,
+		params,
+		activationTime,
+		testNetworkID,
+		testGovernanceKeys[:2],
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err == nil {
+		t.Fatal("should have failed because the staking parameters are invalid")
+	}
+
+	// Case 4: control signatures aren't sorted
+	tx, err = vm.newSetStakingParametersTx(
+		defaultNonce+1,
+		DefaultStakingParameters,
+		activationTime,
+		testNetworkID,
+		testGovernanceKeys[:2],
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.ControlSigs[0], tx.ControlSigs[1] = tx.ControlSigs[1], tx.ControlSigs[0]
+	if err := tx.initialize(vm); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err == nil {
+		t.Fatal("should have failed because control signatures aren't sorted")
+	}
+
+	// Case 5: valid
+	tx, err = vm.newSetStakingParametersTx(
+		defaultNonce+1,
+		DefaultStakingParameters,
+		activationTime,
+		testNetworkID,
+		testGovernanceKeys[:2],
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SyntacticVerify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSetStakingParametersTxSemanticVerify(t *testing.T) {
+	vm := defaultVM()
+	activationTime := uint64(defaultGenesisTime.Add(time.Hour).Unix())
+	keyIntf, err := vm.factory.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyWithoutAccount := keyIntf.(*crypto.PrivateKeySECP256K1R)
+
+	tests := []struct {
+		name           string
+		activationTime uint64
+		controlKeys    []*crypto.PrivateKeySECP256K1R
+		payerKey       *crypto.PrivateKeySECP256K1R
+		shouldErr      bool
+	}{
+		{"too few control signatures", activationTime, testGovernanceKeys[:1], defaultKey, true},
+		{"too many control signatures", activationTime, testGovernanceKeys, defaultKey, true},
+		{"signed by a key that doesn't govern", activationTime, keys[:2], defaultKey, true},
+		{"signed twice by the same key", activationTime, []*crypto.PrivateKeySECP256K1R{testGovernanceKeys[1], testGovernanceKeys[1]}, defaultKey, true},
+		{"activation time isn't in the future", uint64(defaultGenesisTime.Unix()), testGovernanceKeys[:2], defaultKey, true},
+		{"payer can't pay the fee", activationTime, testGovernanceKeys[:2], keyWithoutAccount, true},
+		{"valid", activationTime, testGovernanceKeys[1:], defaultKey, false},
+	}
+	for _, test := range tests {
+		tx, err := vm.newSetStakingParametersTx(
+			defaultNonce+1,
+			DefaultStakingParameters,
+			test.activationTime,
+			testNetworkID,
+			test.controlKeys,
+			test.payerKey,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tx.SemanticVerify(versiondb.New(vm.DB)); err == nil && test.shouldErr {
+			t.Fatalf("Should have errored because %s", test.name)
+		} else if err != nil && !test.shouldErr {
+			t.Fatalf("%s shouldn't have errored but errored with %s", test.name, err)
+		}
+	}
+}
+
+func TestSetStakingParametersTxNotGovernable(t *testing.T) {
+	vm := defaultVM()
+
+	schedule, err := vm.getParameterSchedule(vm.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schedule.Governance = Governance{}
+	if err := vm.putParameterSchedule(vm.DB, schedule); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := vm.newSetStakingParametersTx(
+		defaultNonce+1,
+		DefaultStakingParameters,
+		uint64(defaultGenesisTime.Add(time.Hour).Unix()),
+		testNetworkID,
+		nil,
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.SemanticVerify(versiondb.New(vm.DB)); err != errNotGovernable {
+		t.Fatalf("Should have errored with %s but errored with %v", errNotGovernable, err)
+	}
+}
+
+// Ensure a change to the staking parameters only takes effect at its
+// activation time, and that stakers are then verified against it
+func TestSetStakingParametersTxActivation(t *testing.T) {
+	vm := defaultVM()
+	activationTime := defaultGenesisTime.Add(time.Hour)
+
+	params := DefaultStakingParameters
+	params.MinimumStakeAmount = 2 * DefaultStakingParameters.MinimumStakeAmount
+	tx, err := vm.newSetStakingParametersTx(
+		defaultNonce+1,
+		params,
+		uint64(activationTime.Unix()),
+		testNetworkID,
+		testGovernanceKeys[:2],
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := versiondb.New(vm.DB)
+	if _, err := tx.SemanticVerify(db); err != nil {
+		t.Fatal(err)
+	}
+
+	account, err := vm.getAccount(db, defaultKey.PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Balance != defaultBalance-defaultTxFee {
+		t.Fatalf("Wrong balance. Expected: %d ; Returned: %d", defaultBalance-defaultTxFee, account.Balance)
+	}
+
+	// A delegator that stakes the current minimum is valid until the change
+	// takes effect
+	startTime := activationTime.Add(time.Second)
+	delegatorTx, err := vm.newAddDefaultSubnetDelegatorTx(
+		defaultNonce+2,
+		DefaultStakingParameters.MinimumStakeAmount,
+		uint64(startTime.Unix()),
+		uint64(startTime.Add(DefaultStakingParameters.MinimumStakingDuration).Unix()),
+		keys[1].PublicKey().Address(),
+		defaultKey.PublicKey().Address(),
+		testNetworkID,
+		defaultKey,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, _, err := delegatorTx.SemanticVerify(db); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := vm.putTimestamp(db, activationTime); err != nil {
+		t.Fatal(err)
+	}
+	if current, err := vm.getStakingParameters(db); err != nil {
+		t.Fatal(err)
+	} else if *current != params {
+		t.Fatalf("The change should have taken effect at its activation time")
+	}
+	if _, _, _, _, err := delegatorTx.SemanticVerify(db); err != errWeightTooSmall {
+		t.Fatalf("Should have errored with %s but errored with %v", errWeightTooSmall, err)
+	}
+}
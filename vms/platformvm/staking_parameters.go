@@ -0,0 +1,265 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/json"
+	"github.com/ava-labs/gecko/utils/units"
+)
+
+var (
+	errNoMinimumStake          = errors.New("minimum stake amount must be positive")
+	errNoMinimumDuration       = errors.New("minimum staking duration must be positive")
+	errStakingDurationsOrder   = errors.New("minimum staking duration must be at most the maximum staking duration")
+	errMintingPeriodTooShort   = errors.New("minting period must be at least the maximum staking duration")
+	errNoBatchSize             = errors.New("batch size must be positive")
+	errNegativeDelta           = errors.New("delta can't be negative")
+	errNoThreshold             = errors.New("threshold must be positive when there are control keys")
+	errDuplicatedControlKey    = errors.New("duplicated control key")
+	errNotGovernable           = errors.New("the staking parameters can't be changed, as there are no control keys")
+	errUnknownControlKey       = errors.New("tx has a control signature from a key that doesn't govern the staking parameters")
+	errDuplicateControlSig     = errors.New("tx has more than one control signature from the same key")
+	errActivationTimeTooEarly  = errors.New("activation time must be after the chain timestamp")
+	errUnexpectedScheduleValue = errors.New("expected to retrieve a parameter schedule from database but got a different type")
+
+	// DefaultStakingParameters are the staking parameters of a network whose
+	// genesis doesn't specify them
+	DefaultStakingParameters = StakingParameters{
+		MinimumStakeAmount:     10 * units.MicroAva,
+		MinimumStakingDuration: 24 * time.Hour,
+		MaximumStakingDuration: 365 * 24 * time.Hour,
+		Rewards:                DefaultRewardConfig,
+		BatchSize:              30,
+		Delta:                  10 * time.Second, // TODO change to longer period (2 minutes?) before release
+	}
+)
+
+// StakingParameters are the parameters of the platform chain that can be
+// changed by governance
+type StakingParameters struct {
+	// MinimumStakeAmount is the minimum amount of nAva one must bond to be a
+	// staker
+	MinimumStakeAmount uint64 `serialize:"true"`
+
+	// MinimumStakingDuration is the shortest amount of time a staker can bond
+	// their funds for
+	MinimumStakingDuration time.Duration `serialize:"true"`
+
+	// MaximumStakingDuration is the longest amount of time a staker can bond
+	// their funds for
+	MaximumStakingDuration time.Duration `serialize:"true"`
+
+	// Rewards determines how much stakers are rewarded with
+	Rewards RewardConfig `serialize:"true"`
+
+	// BatchSize is the number of decision transactions to place into a block
+	BatchSize uint32 `serialize:"true"`
+
+	// Delta is the synchrony bound used for safe decision making
+	Delta time.Duration `serialize:"true"`
+}
+
+// Verify that these staking parameters are well formed
+func (p *StakingParameters) Verify() error {
+	switch {
+	case p.MinimumStakeAmount == 0:
+		return errNoMinimumStake
+	case p.MinimumStakingDuration <= 0:
+		return errNoMinimumDuration
+	case p.MinimumStakingDuration > p.MaximumStakingDuration:
+		return errStakingDurationsOrder
+	case p.Rewards.MintingPeriod < p.MaximumStakingDuration:
+		return errMintingPeriodTooShort
+	case p.BatchSize == 0:
+		return errNoBatchSize
+	case p.Delta < 0:
+		return errNegativeDelta
+	default:
+		return p.Rewards.Verify()
+	}
+}
+
+// verifyDuration returns nil if a staker can bond their funds for [duration]
+func (p *StakingParameters) verifyDuration(duration time.Duration) error {
+	switch {
+	case duration < p.MinimumStakingDuration:
+		return errStakeTooShort
+	case duration > p.MaximumStakingDuration:
+		return errStakeTooLong
+	default:
+		return nil
+	}
+}
+
+// verifyStake returns nil if a staker can bond [amount] nAva for [duration]
+func (p *StakingParameters) verifyStake(amount uint64, duration time.Duration) error {
+	if amount < p.MinimumStakeAmount {
+		return errWeightTooSmall
+	}
+	return p.verifyDuration(duration)
+}
+
+// Governance is the set of keys that govern the staking parameters.
+// A transaction that changes the staking parameters must be signed by
+// [Threshold] of [ControlKeys].
+// If there are no control keys, the staking parameters can't be changed.
+type Governance struct {
+	ControlKeys []ids.ShortID `serialize:"true"`
+	Threshold   uint16        `serialize:"true"`
+}
+
+// Verify that this governance is well formed
+func (g *Governance) Verify() error {
+	switch {
+	case g.Threshold > uint16(len(g.ControlKeys)):
+		return errThresholdExceedsKeysLen
+	case g.Threshold > maxThreshold:
+		return errThresholdTooHigh
+	case len(g.ControlKeys) > 0 && g.Threshold == 0:
+		return errNoThreshold
+	}
+	keys := ids.ShortSet{}
+	for _, key := range g.ControlKeys {
+		if keys.Contains(key) {
+			return errDuplicatedControlKey
+		}
+		keys.Add(key)
+	}
+	return nil
+}
+
+// parameterSchedule is the staking parameters in effect, the change to them
+// that is scheduled, if any, and who governs them
+type parameterSchedule struct {
+	Current StakingParameters `serialize:"true"`
+
+	// Unix time [Pending] takes effect at. 0 if no change is scheduled.
+	ActivationTime uint64            `serialize:"true"`
+	Pending        StakingParameters `serialize:"true"`
+
+	Governance Governance `serialize:"true"`
+}
+
+// Bytes returns the byte representation of this schedule
+func (s *parameterSchedule) Bytes() []byte {
+	bytes, _ := Codec.Marshal(s)
+	return bytes
+}
+
+// at returns the staking parameters in effect at [timestamp]
+func (s *parameterSchedule) at(timestamp time.Time) *StakingParameters {
+	if s.ActivationTime != 0 && uint64(timestamp.Unix()) >= s.ActivationTime {
+		return &s.Pending
+	}
+	return &s.Current
+}
+
+// schedule [params] to take effect at Unix time [activationTime], given that
+// the chain's timestamp is [timestamp]. Replaces the change that was
+// scheduled, if it hasn't taken effect yet.
+func (s *parameterSchedule) schedule(params StakingParameters, activationTime uint64, timestamp time.Time) {
+	s.Current = *s.at(timestamp)
+	s.ActivationTime = activationTime
+	s.Pending = params
+}
+
+// get the staking parameters, and the change to them that is scheduled, in
+// [db]
+func (vm *VM) getParameterSchedule(db database.Database) (*parameterSchedule, error) {
+	scheduleIntf, err := vm.State.Get(db, stakingParametersTypeID, stakingParametersKey)
+	if err != nil {
+		return nil, err
+	}
+	schedule, ok := scheduleIntf.(*parameterSchedule)
+	if !ok {
+		return nil, errUnexpectedScheduleValue
+	}
+	return schedule, nil
+}
+
+// put the staking parameters, and the change to them that is scheduled, in
+// [db]
+func (vm *VM) putParameterSchedule(db database.Database, schedule *parameterSchedule) error {
+	return vm.State.Put(db, stakingParametersTypeID, stakingParametersKey, schedule)
+}
+
+// get the staking parameters in effect at the chain timestamp in [db]
+func (vm *VM) getStakingParameters(db database.Database) (*StakingParameters, error) {
+	schedule, err := vm.getParameterSchedule(db)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get staking parameters: %w", err)
+	}
+	timestamp, err := vm.getTimestamp(db)
+	if err != nil {
+		return nil, err
+	}
+	return schedule.at(timestamp), nil
+}
+
+// APIStakingParameters are the staking parameters of the platform chain.
+// Durations are in seconds and rates are in parts of RateDenominator.
+type APIStakingParameters struct {
+	MinimumStakeAmount     json.Uint64 `json:"minimumStakeAmount"`
+	MinimumStakingDuration json.Uint64 `json:"minimumStakingDuration"`
+	MaximumStakingDuration json.Uint64 `json:"maximumStakingDuration"`
+	MaxSupply              json.Uint64 `json:"maxSupply"`
+	MintingPeriod          json.Uint64 `json:"mintingPeriod"`
+	MinConsumptionRate     json.Uint64 `json:"minConsumptionRate"`
+	MaxConsumptionRate     json.Uint64 `json:"maxConsumptionRate"`
+	BatchSize              json.Uint32 `json:"batchSize"`
+	Delta                  json.Uint64 `json:"delta"`
+}
+
+// newAPIStakingParameters returns the API representation of [p]
+func newAPIStakingParameters(p *StakingParameters) APIStakingParameters {
+	return APIStakingParameters{
+		MinimumStakeAmount:     json.Uint64(p.MinimumStakeAmount),
+		MinimumStakingDuration: json.Uint64(p.MinimumStakingDuration / time.Second),
+		MaximumStakingDuration: json.Uint64(p.MaximumStakingDuration / time.Second),
+		MaxSupply:              json.Uint64(p.Rewards.MaxSupply),
+		MintingPeriod:          json.Uint64(p.Rewards.MintingPeriod / time.Second),
+		MinConsumptionRate:     json.Uint64(p.Rewards.MinConsumptionRate),
+		MaxConsumptionRate:     json.Uint64(p.Rewards.MaxConsumptionRate),
+		BatchSize:              json.Uint32(p.BatchSize),
+		Delta:                  json.Uint64(p.Delta / time.Second),
+	}
+}
+
+// stakingParameters returns the staking parameters [p] represents
+func (p *APIStakingParameters) stakingParameters() StakingParameters {
+	return StakingParameters{
+		MinimumStakeAmount:     uint64(p.MinimumStakeAmount),
+		MinimumStakingDuration: seconds(p.MinimumStakingDuration),
+		MaximumStakingDuration: seconds(p.MaximumStakingDuration),
+		Rewards: RewardConfig{
+			MaxSupply:          uint64(p.MaxSupply),
+			MintingPeriod:      seconds(p.MintingPeriod),
+			MinConsumptionRate: uint64(p.MinConsumptionRate),
+			MaxConsumptionRate: uint64(p.MaxConsumptionRate),
+		},
+		BatchSize: uint32(p.BatchSize),
+		Delta:     seconds(p.Delta),
+	}
+}
+
+// seconds returns the duration of [s] seconds. Durations too long to be
+// represented become negative, and so are rejected by Verify.
+func seconds(s json.Uint64) time.Duration {
+	if uint64(s) > uint64(1<<63-1)/uint64(time.Second) {
+		return -1
+	}
+	return time.Duration(s) * time.Second
+}
+
+// APIGovernance is the set of keys that govern the staking parameters
+type APIGovernance struct {
+	ControlKeys []ids.ShortID `json:"controlKeys"`
+	Threshold   json.Uint16   `json:"threshold"`
+}
@@ -0,0 +1,176 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/json"
+)
+
+func TestStakingParametersVerify(t *testing.T) {
+	if err := DefaultStakingParameters.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*StakingParameters)
+	}{
+		{"no minimum stake", func(p *StakingParameters) { p.MinimumStakeAmount = 0 }},
+		{"no minimum duration", func(p *StakingParameters) { p.MinimumStakingDuration = 0 }},
+		{"decreasing durations", func(p *StakingParameters) { p.MinimumStakingDuration = p.MaximumStakingDuration + time.Second }},
+		{"short minting period", func(p *StakingParameters) { p.Rewards.MintingPeriod = p.MaximumStakingDuration - time.Second }},
+		{"invalid rewards", func(p *StakingParameters) { p.Rewards.MaxConsumptionRate = RateDenominator + 1 }},
+		{"no batch size", func(p *StakingParameters) { p.BatchSize = 0 }},
+		{"negative delta", func(p *StakingParameters) { p.Delta = -time.Second }},
+	}
+	for _, test := range tests {
+		params := DefaultStakingParameters
+		test.modify(&params)
+		if err := params.Verify(); err == nil {
+			t.Fatalf("Should have errored due to %s", test.name)
+		}
+	}
+}
+
+func TestStakingParametersVerifyStake(t *testing.T) {
+	params := DefaultStakingParameters
+	if err := params.verifyStake(params.MinimumStakeAmount, params.MinimumStakingDuration); err != nil {
+		t.Fatal(err)
+	}
+	if err := params.verifyStake(params.MinimumStakeAmount, params.MaximumStakingDuration); err != nil {
+		t.Fatal(err)
+	}
+	if err := params.verifyStake(params.MinimumStakeAmount-1, params.MinimumStakingDuration); err != errWeightTooSmall {
+		t.Fatalf("Should have errored with %s but errored with %v", errWeightTooSmall, err)
+	}
+	if err := params.verifyStake(params.MinimumStakeAmount, params.MinimumStakingDuration-time.Second); err != errStakeTooShort {
+		t.Fatalf("Should have errored with %s but errored with %v", errStakeTooShort, err)
+	}
+	if err := params.verifyStake(params.MinimumStakeAmount, params.MaximumStakingDuration+time.Second); err != errStakeTooLong {
+		t.Fatalf("Should have errored with %s but errored with %v", errStakeTooLong, err)
+	}
+}
+
+func TestGovernanceVerify(t *testing.T) {
+	key0 := keys[0].PublicKey().Address()
+	key1 := keys[1].PublicKey().Address()
+
+	tests := []struct {
+		name       string
+		governance Governance
+		shouldErr  bool
+	}{
+		{"not governable", Governance{}, false},
+		{"valid", Governance{ControlKeys: []ids.ShortID{key0, key1}, Threshold: 2}, false},
+		{"no threshold", Governance{ControlKeys: []ids.ShortID{key0}, Threshold: 0}, true},
+		{"threshold exceeds keys", Governance{ControlKeys: []ids.ShortID{key0}, Threshold: 2}, true},
+		{"duplicated key", Governance{ControlKeys: []ids.ShortID{key0, key0}, Threshold: 1}, true},
+	}
+	for _, test := range tests {
+		if err := test.governance.Verify(); err == nil && test.shouldErr {
+			t.Fatalf("Should have errored due to %s", test.name)
+		} else if err != nil && !test.shouldErr {
+			t.Fatalf("%s shouldn't have errored but errored with %s", test.name, err)
+		}
+	}
+}
+
+func TestParameterScheduleAt(t *testing.T) {
+	pending := DefaultStakingParameters
+	pending.MinimumStakeAmount *= 2
+	schedule := parameterSchedule{Current: DefaultStakingParameters}
+
+	// Nothing is scheduled
+	if params := schedule.at(time.Unix(1<<40, 0)); *params != DefaultStakingParameters {
+		t.Fatalf("Should have returned the current staking parameters")
+	}
+
+	schedule.schedule(pending, 100, time.Unix(50, 0))
+	if params := schedule.at(time.Unix(99, 0)); *params != DefaultStakingParameters {
+		t.Fatalf("Should have returned the current staking parameters before the activation time")
+	}
+	if params := schedule.at(time.Unix(100, 0)); *params != pending {
+		t.Fatalf("Should have returned the pending staking parameters at the activation time")
+	}
+
+	// Scheduling after the pending change took effect makes it current
+	later := pending
+	later.BatchSize++
+	schedule.schedule(later, 300, time.Unix(200, 0))
+	if schedule.Current != pending {
+		t.Fatalf("The change that took effect should have become current")
+	}
+	if params := schedule.at(time.Unix(300, 0)); *params != later {
+		t.Fatalf("Should have returned the newly scheduled staking parameters")
+	}
+
+	// Scheduling before the pending change takes effect replaces it
+	schedule.schedule(DefaultStakingParameters, 400, time.Unix(250, 0))
+	if schedule.Current != pending || schedule.Pending != DefaultStakingParameters {
+		t.Fatalf("The change that didn't take effect should have been replaced")
+	}
+}
+
+func TestParameterScheduleStorage(t *testing.T) {
+	vm := defaultVM()
+
+	params, err := vm.getStakingParameters(vm.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *params != DefaultStakingParameters {
+		t.Fatalf("Genesis staking parameters should be the default staking parameters")
+	}
+
+	schedule, err := vm.getParameterSchedule(vm.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schedule.Governance.Threshold != 2 || len(schedule.Governance.ControlKeys) != len(testGovernanceKeys) {
+		t.Fatalf("Wrong genesis governance: %+v", schedule.Governance)
+	}
+
+	pending := DefaultStakingParameters
+	pending.Delta = time.Minute
+	activationTime := defaultGenesisTime.Add(time.Hour)
+	schedule.schedule(pending, uint64(activationTime.Unix()), defaultGenesisTime)
+	if err := vm.putParameterSchedule(vm.DB, schedule); err != nil {
+		t.Fatal(err)
+	}
+
+	// The change takes effect once the chain timestamp reaches its activation
+	// time
+	if params, err := vm.getStakingParameters(vm.DB); err != nil {
+		t.Fatal(err)
+	} else if *params != DefaultStakingParameters {
+		t.Fatalf("The scheduled change shouldn't have taken effect yet")
+	}
+	if err := vm.putTimestamp(vm.DB, activationTime); err != nil {
+		t.Fatal(err)
+	}
+	if params, err := vm.getStakingParameters(vm.DB); err != nil {
+		t.Fatal(err)
+	} else if *params != pending {
+		t.Fatalf("The scheduled change should have taken effect")
+	}
+}
+
+func TestAPIStakingParameters(t *testing.T) {
+	apiParams := newAPIStakingParameters(&DefaultStakingParameters)
+	if params := apiParams.stakingParameters(); params != DefaultStakingParameters {
+		t.Fatalf("Staking parameters should be the same after converting to and from their API representation")
+	}
+
+	// Durations that don't fit in a time.Duration must not wrap around
+	apiParams.MaximumStakingDuration = json.Uint64(1 << 62)
+	apiParams.MintingPeriod = json.Uint64(1 << 62)
+	params := apiParams.stakingParameters()
+	if err := params.Verify(); err == nil {
+		t.Fatalf("Should have errored due to an overflowing duration")
+	}
+}
@@ -20,6 +20,14 @@ type DecisionTx interface {
 	SemanticVerify(database.Database) (onAccept func(), err error)
 }
 
+// atomicTx is a DecisionTx that moves AVA between this chain and the AVM chain
+type atomicTx interface {
+	// writeSharedMemory writes the changes this tx makes to the memory shared
+	// with the AVM chain to [smDB]. It's called once the tx's block is
+	// accepted, and [smDB] is written along with the block.
+	writeSharedMemory(smDB database.Database)
+}
+
 // StandardBlock being accepted results in the transactions contained in the
 // block to be accepted and committed to the chain.
 type StandardBlock struct {
@@ -57,6 +65,7 @@ func (sb *StandardBlock) Verify() error {
 	pdb := parent.onAccept()
 
 	sb.onAcceptDB = versiondb.New(pdb)
+	sb.atomicTxs = nil
 	funcs := []func(){}
 	for _, tx := range sb.Txs {
 		onAccept, err := tx.SemanticVerify(sb.onAcceptDB)
@@ -66,6 +75,9 @@ func (sb *StandardBlock) Verify() error {
 		if onAccept != nil {
 			funcs = append(funcs, onAccept)
 		}
+		if atomicTx, ok := tx.(atomicTx); ok {
+			sb.atomicTxs = append(sb.atomicTxs, atomicTx)
+		}
 	}
 
 	if numFuncs := len(funcs); numFuncs == 1 {
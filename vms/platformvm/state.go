@@ -11,6 +11,7 @@ import (
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/consensus/snowman"
+	"github.com/ava-labs/gecko/vms/components/state"
 )
 
 // This file contains methods of VM that deal with getting/putting values from database
@@ -23,6 +24,7 @@ var (
 const (
 	currentValidatorsPrefix uint64 = iota
 	pendingValidatorsPrefix
+	importedUTXOsPrefix
 )
 
 // get the validators currently validating the specified subnet
@@ -146,6 +148,18 @@ func (vm *VM) putAccount(db database.Database, account Account) error {
 	return nil
 }
 
+// importedUTXO returns true if the UTXO with ID [utxoID], exported by the AVM
+// chain, was imported to this chain
+func (vm *VM) importedUTXO(db database.Database, utxoID ids.ID) (bool, error) {
+	return vm.State.Has(db, state.IDTypeID, utxoID.Prefix(importedUTXOsPrefix))
+}
+
+// putImportedUTXO records that the UTXO with ID [utxoID] was imported to this
+// chain by the tx with ID [txID]
+func (vm *VM) putImportedUTXO(db database.Database, utxoID, txID ids.ID) error {
+	return vm.State.PutID(db, utxoID.Prefix(importedUTXOsPrefix), txID)
+}
+
 // get the blockchains that exist
 func (vm *VM) getChains(db database.Database) ([]*CreateChainTx, error) {
 	chainsInterface, err := vm.State.Get(db, chainsTypeID, chainsKey)
@@ -160,6 +174,29 @@ func (vm *VM) getChains(db database.Database) ([]*CreateChainTx, error) {
 	return chains, nil
 }
 
+// migrateChains moves the list of blockchains that exist from the legacy
+// format, in which every chain is validated by the default subnet, to the
+// current format. Does nothing if the list is already in the current format.
+func (vm *VM) migrateChains() error {
+	migrated, err := vm.State.Has(vm.DB, chainsTypeID, chainsKey)
+	if err != nil || migrated {
+		return err
+	}
+	chainsInterface, err := vm.State.Get(vm.DB, legacyChainsTypeID, chainsKey)
+	if err != nil {
+		return err
+	}
+	chains, ok := chainsInterface.([]*CreateChainTx)
+	if !ok {
+		vm.Ctx.Log.Warn("expected to retrieve []*CreateChainTx from database but got different type")
+		return errDBChains
+	}
+	if err := vm.putChains(vm.DB, chains); err != nil {
+		return err
+	}
+	return vm.DB.Commit()
+}
+
 // put the list of blockchains that exist to database
 func (vm *VM) putChains(db database.Database, chains createChainList) error {
 	if err := vm.State.Put(db, chainsTypeID, chainsKey, chains); err != nil {
@@ -225,6 +262,52 @@ func (vm *VM) getSubnet(db database.Database, ID ids.ID) (*CreateSubnetTx, error
 	return nil, fmt.Errorf("couldn't find subnet with ID %s", ID)
 }
 
+// supply is the amount of nAva that exist. It's increased by the rewards of
+// stakers and decreased by the fees this chain burns. The fees burnt by other
+// chains, such as the AVM, can't be observed by this chain deterministically,
+// so the supply is an upper bound, and stakers' rewards never take the actual
+// supply past the maximum supply.
+type supply uint64
+
+// Bytes returns the byte representation of this supply
+func (s supply) Bytes() []byte {
+	bytes, _ := Codec.Marshal(uint64(s))
+	return bytes
+}
+
+// get the amount of nAva that exist in [db]
+func (vm *VM) getSupply(db database.Database) (uint64, error) {
+	supplyIntf, err := vm.State.Get(db, supplyTypeID, supplyKey)
+	if err != nil {
+		return 0, err
+	}
+	supply, ok := supplyIntf.(uint64)
+	if !ok {
+		vm.Ctx.Log.Warn("expected to retrieve uint64 from database but got different type")
+		return 0, errDB
+	}
+	return supply, nil
+}
+
+// put the amount of nAva that exist in [db]
+func (vm *VM) putSupply(db database.Database, amount uint64) error {
+	return vm.State.Put(db, supplyTypeID, supplyKey, supply(amount))
+}
+
+// burn [amount] nAva, which are removed from the supply in [db]
+func (vm *VM) burn(db database.Database, amount uint64) error {
+	supply, err := vm.getSupply(db)
+	if err != nil {
+		return err
+	}
+	// Every nAva that's burnt is part of the supply, so this never underflows,
+	// but never go below 0 even if that's wrong.
+	if amount > supply {
+		amount = supply
+	}
+	return vm.putSupply(db, supply-amount)
+}
+
 // register each type that we'll be storing in the database
 // so that [vm.State] knows how to unmarshal these types from bytes
 func (vm *VM) registerDBTypes() {
@@ -271,6 +354,26 @@ func (vm *VM) registerDBTypes() {
 		vm.Ctx.Log.Warn(errRegisteringType.Error())
 	}
 
+	unmarshalLegacyChainsFunc := func(bytes []byte) (interface{}, error) {
+		var legacyChains []*legacyCreateChainTx
+		if err := Codec.Unmarshal(bytes, &legacyChains); err != nil {
+			return nil, err
+		}
+		chains := make([]*CreateChainTx, len(legacyChains))
+		for i, legacyChain := range legacyChains {
+			chain, err := legacyChain.chain()
+			if err != nil {
+				return nil, err
+			}
+			chain.vm = vm
+			chains[i] = chain
+		}
+		return chains, nil
+	}
+	if err := vm.State.RegisterType(legacyChainsTypeID, unmarshalLegacyChainsFunc); err != nil {
+		vm.Ctx.Log.Warn(errRegisteringType.Error())
+	}
+
 	unmarshalSubnetsFunc := func(bytes []byte) (interface{}, error) {
 		var subnets []*CreateSubnetTx
 		if err := Codec.Unmarshal(bytes, &subnets); err != nil {
@@ -286,6 +389,39 @@ func (vm *VM) registerDBTypes() {
 	if err := vm.State.RegisterType(subnetsTypeID, unmarshalSubnetsFunc); err != nil {
 		vm.Ctx.Log.Warn(errRegisteringType.Error())
 	}
+
+	unmarshalSupplyFunc := func(bytes []byte) (interface{}, error) {
+		var supply uint64
+		if err := Codec.Unmarshal(bytes, &supply); err != nil {
+			return nil, err
+		}
+		return supply, nil
+	}
+	if err := vm.State.RegisterType(supplyTypeID, unmarshalSupplyFunc); err != nil {
+		vm.Ctx.Log.Warn(errRegisteringType.Error())
+	}
+
+	unmarshalParameterScheduleFunc := func(bytes []byte) (interface{}, error) {
+		schedule := &parameterSchedule{}
+		if err := Codec.Unmarshal(bytes, schedule); err != nil {
+			return nil, err
+		}
+		return schedule, nil
+	}
+	if err := vm.State.RegisterType(stakingParametersTypeID, unmarshalParameterScheduleFunc); err != nil {
+		vm.Ctx.Log.Warn(errRegisteringType.Error())
+	}
+
+	unmarshalUptimeFunc := func(bytes []byte) (interface{}, error) {
+		up := &uptime{}
+		if err := Codec.Unmarshal(bytes, up); err != nil {
+			return nil, err
+		}
+		return up, nil
+	}
+	if err := vm.State.RegisterType(uptimeTypeID, unmarshalUptimeFunc); err != nil {
+		vm.Ctx.Log.Warn(errRegisteringType.Error())
+	}
 }
 
 // Unmarshal a Block from bytes and initialize it
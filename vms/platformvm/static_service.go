@@ -6,11 +6,14 @@ package platformvm
 import (
 	"container/heap"
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/utils/formatting"
 	"github.com/ava-labs/gecko/utils/json"
+	"github.com/ava-labs/gecko/utils/math"
+	"github.com/ava-labs/gecko/vms/avm"
 )
 
 // Note that since an AVA network has exactly one Platform Chain,
@@ -89,12 +92,18 @@ type APIChain struct {
 // [Validators] are the validators of the default subnet at genesis.
 // [Chains] are the chains that exist at genesis.
 // [Time] is the Platform Chain's time at network genesis.
+// [StakingParameters] are the staking parameters at genesis. If nil, they are
+// DefaultStakingParameters.
+// [Governance] are the keys that can change the staking parameters. If nil,
+// the staking parameters can't be changed.
 type BuildGenesisArgs struct {
-	NetworkID  json.Uint32                 `json:"address"`
-	Accounts   []APIAccount                `json:"accounts"`
-	Validators []APIDefaultSubnetValidator `json:"defaultSubnetValidators"`
-	Chains     []APIChain                  `json:"chains"`
-	Time       json.Uint64                 `json:"time"`
+	NetworkID         json.Uint32                 `json:"address"`
+	Accounts          []APIAccount                `json:"accounts"`
+	Validators        []APIDefaultSubnetValidator `json:"defaultSubnetValidators"`
+	Chains            []APIChain                  `json:"chains"`
+	Time              json.Uint64                 `json:"time"`
+	StakingParameters *APIStakingParameters       `json:"stakingParameters"`
+	Governance        *APIGovernance              `json:"governance"`
 }
 
 // BuildGenesisReply is the reply from BuildGenesis
@@ -104,10 +113,16 @@ type BuildGenesisReply struct {
 
 // Genesis represents a genesis state of the platform chain
 type Genesis struct {
-	Accounts   []Account        `serialize:"true"`
-	Validators *EventHeap       `serialize:"true"`
-	Chains     []*CreateChainTx `serialize:"true"`
-	Timestamp  uint64           `serialize:"true"`
+	Accounts          []Account              `serialize:"true"`
+	Validators        *EventHeap             `serialize:"true"`
+	GenesisChains     []*legacyCreateChainTx `serialize:"true"`
+	Timestamp         uint64                 `serialize:"true"`
+	StakingParameters StakingParameters      `serialize:"true"`
+	Governance        Governance             `serialize:"true"`
+
+	// The chains created at genesis, which are validated by the default
+	// subnet. Set by Initialize.
+	Chains []*CreateChainTx
 }
 
 // Initialize ...
@@ -117,14 +132,50 @@ func (g *Genesis) Initialize() error {
 			return err
 		}
 	}
-	for _, chain := range g.Chains {
-		if err := chain.initialize(nil); err != nil {
+	g.Chains = make([]*CreateChainTx, len(g.GenesisChains))
+	for i, genesisChain := range g.GenesisChains {
+		chain, err := genesisChain.chain()
+		if err != nil {
 			return err
 		}
+		g.Chains[i] = chain
 	}
 	return nil
 }
 
+// Supply returns the amount of nAva that exist at genesis, which is held by the
+// accounts, staked by the validators, and allocated by the genesis of the AVM
+// chain with ID [avmID] as the asset with ID [avaAssetID].
+// Assumes Initialize has been called.
+func (g *Genesis) Supply(avmID, avaAssetID ids.ID) (uint64, error) {
+	supply := uint64(0)
+	for _, chain := range g.Chains {
+		if !chain.ID().Equals(avmID) {
+			continue
+		}
+		allocated, err := avm.GenesisSupply(chain.GenesisData, avaAssetID)
+		if err != nil {
+			return 0, err
+		}
+		supply = allocated
+	}
+	for _, account := range g.Accounts {
+		newSupply, err := math.Add64(supply, account.Balance)
+		if err != nil {
+			return 0, err
+		}
+		supply = newSupply
+	}
+	for _, tx := range g.Validators.Txs {
+		newSupply, err := math.Add64(supply, tx.Vdr().Weight())
+		if err != nil {
+			return 0, err
+		}
+		supply = newSupply
+	}
+	return supply, nil
+}
+
 // BuildGenesis build the genesis state of the Platform Chain (and thereby the AVA network.)
 func (*StaticService) BuildGenesis(_ *http.Request, args *BuildGenesisArgs, reply *BuildGenesisReply) error {
 	// Specify the accounts on the Platform chain that exist at genesis.
@@ -174,34 +225,46 @@ func (*StaticService) BuildGenesis(_ *http.Request, args *BuildGenesisArgs, repl
 	}
 
 	// Specify the chains that exist at genesis.
-	chains := []*CreateChainTx{}
+	chains := []*legacyCreateChainTx{}
 	for _, chain := range args.Chains {
 		// Ordinarily we sign a createChainTx. For genesis, there is no key.
 		// We generate the ID of this tx by hashing the bytes of the unsigned transaction
 		// TODO: Should we just sign this tx with a private key that we share publicly?
-		tx := &CreateChainTx{
-			UnsignedCreateChainTx: UnsignedCreateChainTx{
-				NetworkID:   uint32(args.NetworkID),
-				Nonce:       0,
-				ChainName:   chain.Name,
-				VMID:        chain.VMID,
-				FxIDs:       chain.FxIDs,
-				GenesisData: chain.GenesisData.Bytes,
-			},
-		}
-		if err := tx.initialize(nil); err != nil {
-			return err
-		}
+		chains = append(chains, &legacyCreateChainTx{
+			NetworkID:   uint32(args.NetworkID),
+			Nonce:       0,
+			ChainName:   chain.Name,
+			VMID:        chain.VMID,
+			FxIDs:       chain.FxIDs,
+			GenesisData: chain.GenesisData.Bytes,
+		})
+	}
 
-		chains = append(chains, tx)
+	// Specify the staking parameters at genesis, and who may change them
+	params := DefaultStakingParameters
+	if args.StakingParameters != nil {
+		params = args.StakingParameters.stakingParameters()
+	}
+	if err := params.Verify(); err != nil {
+		return fmt.Errorf("invalid staking parameters: %w", err)
+	}
+	governance := Governance{}
+	if args.Governance != nil {
+		governance.ControlKeys = args.Governance.ControlKeys
+		governance.Threshold = uint16(args.Governance.Threshold)
+	}
+	if err := governance.Verify(); err != nil {
+		return fmt.Errorf("invalid governance: %w", err)
 	}
 
 	// genesis holds the genesis state
 	genesis := Genesis{
-		Accounts:   accounts,
-		Validators: validators,
-		Chains:     chains,
-		Timestamp:  uint64(args.Time),
+		Accounts:          accounts,
+		Validators:        validators,
+		GenesisChains:     chains,
+		Timestamp:         uint64(args.Time),
+		StakingParameters: params,
+		Governance:        governance,
 	}
 	// Marshal genesis to bytes
 	bytes, err := Codec.Marshal(genesis)
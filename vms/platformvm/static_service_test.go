@@ -59,7 +59,17 @@ func TestBuildGenesis(t *testing.T) {
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
-		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x05,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x05, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x27, 0x10, 0x00,
+		0x00, 0x4e, 0x94, 0x91, 0x4f, 0x00, 0x00, 0x00,
+		0x70, 0x09, 0xd3, 0x2d, 0xa3, 0x00, 0x00, 0x09,
+		0xfd, 0xf4, 0x2f, 0x6e, 0x48, 0x00, 0x00, 0x00,
+		0x70, 0x09, 0xd3, 0x2d, 0xa3, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x01, 0x86, 0xa0, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x01, 0xd4, 0xc0, 0x00,
+		0x00, 0x00, 0x1e, 0x00, 0x00, 0x00, 0x02, 0x54,
+		0x0b, 0xe4, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00,
 	}
 
 	addr, _ := ids.ShortFromString("8CrVPQZ4VSqgL8zTdvL14G8HqAfrBr4z")
@@ -0,0 +1,237 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+)
+
+var (
+	errUnexpectedUptimeValue = errors.New("expected to retrieve an uptime from the database but got a different type")
+	errInvalidUptimeRequired = errors.New("the required uptime must be between 0 and 1")
+)
+
+// uptime is how long a staker was connected to this node during its staking
+// period, up to [LastUpdated]
+type uptime struct {
+	UpDuration  uint64 `serialize:"true"` // In seconds
+	LastUpdated uint64 `serialize:"true"` // Unix time
+}
+
+// Bytes returns the byte representation of this uptime
+func (u *uptime) Bytes() []byte {
+	bytes, _ := Codec.Marshal(u)
+	return bytes
+}
+
+// stakingPeriod is the period a node stakes for, and its uptime during it
+type stakingPeriod struct {
+	uptime
+
+	nodeID     ids.ShortID
+	start, end uint64 // Unix times
+}
+
+// update the uptime to [now]. [connected] is whether the node was connected
+// since the uptime was last updated.
+func (p *stakingPeriod) update(now uint64, connected bool) {
+	from := p.LastUpdated
+	if from < p.start {
+		from = p.start
+	}
+	to := now
+	if to > p.end {
+		to = p.end
+	}
+	if to <= from {
+		return
+	}
+	if connected {
+		p.UpDuration += to - from
+	}
+	p.LastUpdated = to
+}
+
+// elapsed returns how much of the staking period has passed at [now]
+func (p *stakingPeriod) elapsed(now uint64) uint64 {
+	switch {
+	case now <= p.start:
+		return 0
+	case now >= p.end:
+		return p.end - p.start
+	default:
+		return now - p.start
+	}
+}
+
+// uptimeTracker measures how long each staker of the default subnet is
+// connected to this node during its staking period.
+//
+// The tracker is notified of connections by the network, which holds its own
+// locks while doing so. The tracker therefore has its own lock, and never
+// acquires the context's lock.
+type uptimeTracker struct {
+	vm *VM
+
+	lock sync.Mutex
+
+	// The nodes this node is connected to, including itself
+	connected ids.ShortSet
+
+	// Key: ID of the tx that added the staker
+	// Value: The staker's staking period
+	periods map[[32]byte]*stakingPeriod
+}
+
+// newUptimeTracker returns a tracker for the stakers of [vm]. This node is
+// always connected to itself.
+func newUptimeTracker(vm *VM) *uptimeTracker {
+	t := &uptimeTracker{
+		vm:      vm,
+		periods: make(map[[32]byte]*stakingPeriod),
+	}
+	t.connected.Add(vm.Ctx.NodeID)
+	return t
+}
+
+// now returns the current Unix time
+func (t *uptimeTracker) now() uint64 { return uint64(t.vm.clock.Time().Unix()) }
+
+// Connected marks [nodeID] as being connected from now on
+func (t *uptimeTracker) Connected(nodeID ids.ShortID) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.updateNode(nodeID)
+	t.connected.Add(nodeID)
+}
+
+// Disconnected marks [nodeID] as being disconnected from now on
+func (t *uptimeTracker) Disconnected(nodeID ids.ShortID) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.updateNode(nodeID)
+	t.connected.Remove(nodeID)
+}
+
+// updateNode updates the uptime of every staking period of [nodeID].
+// Assumes [t.lock] is held.
+func (t *uptimeTracker) updateNode(nodeID ids.ShortID) {
+	now := t.now()
+	connected := t.connected.Contains(nodeID)
+	for _, period := range t.periods {
+		if period.nodeID.Equals(nodeID) {
+			period.update(now, connected)
+		}
+	}
+}
+
+// uptime returns how long the staker added by [txID] was connected during its
+// staking period, and how much of its staking period has passed. Returns false
+// if the staker isn't tracked.
+func (t *uptimeTracker) uptime(txID ids.ID) (uint64, uint64, bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	period, ok := t.periods[txID.Key()]
+	if !ok {
+		return 0, 0, false
+	}
+	now := t.now()
+	period.update(now, t.connected.Contains(period.nodeID))
+	return period.UpDuration, period.elapsed(now), true
+}
+
+// setStakers starts tracking the stakers in [stakers] that aren't tracked yet,
+// and stops tracking the stakers that aren't in [stakers] anymore. The uptimes
+// of stakers that were tracked before this node restarted are loaded from
+// [db]. The time this node wasn't running can't be measured, and counts as
+// time the staker was connected.
+func (t *uptimeTracker) setStakers(db database.Database, stakers []TimedTx) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := t.now()
+	periods := make(map[[32]byte]*stakingPeriod, len(stakers))
+	for _, staker := range stakers {
+		txID := staker.ID()
+		if period, ok := t.periods[txID.Key()]; ok {
+			periods[txID.Key()] = period
+			delete(t.periods, txID.Key())
+			continue
+		}
+
+		period := &stakingPeriod{
+			nodeID: staker.Vdr().ID(),
+			start:  uint64(staker.StartTime().Unix()),
+			end:    uint64(staker.EndTime().Unix()),
+		}
+		up, err := t.vm.getUptime(db, txID)
+		switch {
+		case err == nil:
+			period.uptime = *up
+		case err != database.ErrNotFound:
+			return err
+		}
+		period.update(now, true)
+		periods[txID.Key()] = period
+	}
+
+	// The remaining periods belong to stakers that stopped staking
+	for key := range t.periods {
+		if err := t.vm.putUptime(db, ids.NewID(key), nil); err != nil {
+			return err
+		}
+	}
+	t.periods = periods
+	return t.putUptimes(db)
+}
+
+// persist the uptimes of the tracked stakers as of now to [db]
+func (t *uptimeTracker) persist(db database.Database) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.putUptimes(db)
+}
+
+// putUptimes puts the uptimes of the tracked stakers as of now in [db].
+// Assumes [t.lock] is held.
+func (t *uptimeTracker) putUptimes(db database.Database) error {
+	now := t.now()
+	for key, period := range t.periods {
+		period.update(now, t.connected.Contains(period.nodeID))
+		if err := t.vm.putUptime(db, ids.NewID(key), &period.uptime); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// get the uptime of the staker added by [txID] from [db]
+func (vm *VM) getUptime(db database.Database, txID ids.ID) (*uptime, error) {
+	upIntf, err := vm.State.Get(db, uptimeTypeID, txID)
+	if err != nil {
+		return nil, err
+	}
+	up, ok := upIntf.(*uptime)
+	if !ok {
+		return nil, errUnexpectedUptimeValue
+	}
+	return up, nil
+}
+
+// put the uptime of the staker added by [txID] in [db]. If [up] is nil, the
+// uptime is removed.
+func (vm *VM) putUptime(db database.Database, txID ids.ID, up *uptime) error {
+	if up == nil {
+		return vm.State.Put(db, uptimeTypeID, txID, nil)
+	}
+	return vm.State.Put(db, uptimeTypeID, txID, up)
+}
@@ -0,0 +1,164 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestUptimeInitiallyPrefersCommit(t *testing.T) {
+	vm := defaultVM()
+
+	currentValidators, err := vm.getCurrentValidators(vm.DB, DefaultSubnetID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	staker := currentValidators.Txs[0]
+	nodeID := staker.Vdr().ID()
+	stakingPeriod := staker.EndTime().Sub(staker.StartTime())
+
+	tx, err := vm.newRewardValidatorTx(staker.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The staker connects once a quarter of its staking period has passed, and
+	// stays connected until the end of its staking period
+	vm.clock.Set(staker.StartTime().Add(stakingPeriod / 4))
+	vm.Connected(nodeID)
+	vm.clock.Set(staker.EndTime().Add(time.Hour))
+
+	up, elapsed, ok := vm.uptimes.uptime(staker.ID())
+	if !ok {
+		t.Fatalf("should be tracking the staker's uptime")
+	}
+	if expected := uint64(stakingPeriod / time.Second); elapsed != expected {
+		t.Fatalf("Wrong elapsed time. Expected: %d ; Returned: %d", expected, elapsed)
+	}
+	if expected := uint64(3 * stakingPeriod / 4 / time.Second); up != expected {
+		t.Fatalf("Wrong uptime. Expected: %d ; Returned: %d", expected, up)
+	}
+
+	vm.uptimeRequirement = 0.75
+	if !tx.InitiallyPrefersCommit() {
+		t.Fatalf("should prefer rewarding a staker that meets the uptime requirement")
+	}
+	vm.uptimeRequirement = 0.8
+	if tx.InitiallyPrefersCommit() {
+		t.Fatalf("shouldn't prefer rewarding a staker that doesn't meet the uptime requirement")
+	}
+}
+
+func TestUptimeDisconnected(t *testing.T) {
+	vm := defaultVM()
+
+	currentValidators, err := vm.getCurrentValidators(vm.DB, DefaultSubnetID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	staker := currentValidators.Txs[0]
+	nodeID := staker.Vdr().ID()
+
+	vm.clock.Set(staker.StartTime())
+	vm.Connected(nodeID)
+	vm.clock.Set(staker.StartTime().Add(10 * time.Second))
+	vm.Disconnected(nodeID)
+	vm.clock.Set(staker.StartTime().Add(40 * time.Second))
+
+	up, elapsed, ok := vm.uptimes.uptime(staker.ID())
+	switch {
+	case !ok:
+		t.Fatalf("should be tracking the staker's uptime")
+	case up != 10:
+		t.Fatalf("Wrong uptime. Expected: %d ; Returned: %d", 10, up)
+	case elapsed != 40:
+		t.Fatalf("Wrong elapsed time. Expected: %d ; Returned: %d", 40, elapsed)
+	}
+}
+
+func TestUptimePersisted(t *testing.T) {
+	vm := defaultVM()
+
+	currentValidators, err := vm.getCurrentValidators(vm.DB, DefaultSubnetID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	staker := currentValidators.Txs[0]
+
+	// The staker is disconnected for the first 100 seconds
+	vm.clock.Set(staker.StartTime().Add(100 * time.Second))
+	if err := vm.uptimes.persist(vm.DB); err != nil {
+		t.Fatal(err)
+	}
+
+	// This node is offline for 50 seconds, which counts as uptime
+	vm.clock.Set(staker.StartTime().Add(150 * time.Second))
+	restarted := newUptimeTracker(vm)
+	if err := restarted.setStakers(vm.DB, currentValidators.Txs); err != nil {
+		t.Fatal(err)
+	}
+
+	up, elapsed, ok := restarted.uptime(staker.ID())
+	switch {
+	case !ok:
+		t.Fatalf("should be tracking the staker's uptime")
+	case up != 50:
+		t.Fatalf("Wrong uptime. Expected: %d ; Returned: %d", 50, up)
+	case elapsed != 150:
+		t.Fatalf("Wrong elapsed time. Expected: %d ; Returned: %d", 150, elapsed)
+	}
+
+	// Stakers that stop staking aren't tracked anymore
+	if err := restarted.setStakers(vm.DB, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, ok := restarted.uptime(staker.ID()); ok {
+		t.Fatalf("shouldn't be tracking the uptime of a staker that stopped staking")
+	}
+	if _, err := vm.getUptime(vm.DB, staker.ID()); err == nil {
+		t.Fatalf("should have removed the uptime of a staker that stopped staking")
+	}
+}
+
+func TestServiceGetUptime(t *testing.T) {
+	vm := defaultVM()
+	service := &Service{vm: vm}
+
+	currentValidators, err := vm.getCurrentValidators(vm.DB, DefaultSubnetID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	staker := currentValidators.Txs[0]
+	nodeID := staker.Vdr().ID()
+
+	vm.uptimeRequirement = 0.5
+	vm.clock.Set(staker.StartTime())
+	vm.Connected(nodeID)
+	vm.clock.Set(staker.StartTime().Add(20 * time.Second))
+
+	reply := GetUptimeReply{}
+	if err := service.GetUptime(nil, &GetUptimeArgs{ID: nodeID}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	switch {
+	case !reply.TxID.Equals(staker.ID()):
+		t.Fatalf("Wrong tx ID. Expected: %s ; Returned: %s", staker.ID(), reply.TxID)
+	case reply.UpDuration != 20:
+		t.Fatalf("Wrong uptime. Expected: %d ; Returned: %d", 20, reply.UpDuration)
+	case reply.Elapsed != 20:
+		t.Fatalf("Wrong elapsed time. Expected: %d ; Returned: %d", 20, reply.Elapsed)
+	case reply.Uptime != 1:
+		t.Fatalf("Wrong uptime fraction. Expected: %f ; Returned: %f", 1.0, reply.Uptime)
+	case reply.UptimeRequirement != 0.5:
+		t.Fatalf("Wrong uptime requirement. Expected: %f ; Returned: %f", 0.5, reply.UptimeRequirement)
+	}
+
+	// Nodes that aren't validating have no uptime
+	if err := service.GetUptime(nil, &GetUptimeArgs{ID: ids.NewShortID([20]byte{1, 2, 3})}, &GetUptimeReply{}); err == nil {
+		t.Fatalf("should have failed because the node isn't validating")
+	}
+}
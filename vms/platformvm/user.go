@@ -8,9 +8,13 @@ import (
 
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/hashing"
 )
 
+var errUnexpectedKey = errors.New("got unexpected key from database")
+
 // Key in the database whose corresponding value is the list of
 // account IDs this user controls
 var accountIDsKey = ids.Empty.Bytes()
@@ -119,3 +123,38 @@ func (u *user) getKey(accountID ids.ShortID) (*crypto.PrivateKeySECP256K1R, erro
 	}
 	return nil, errDB
 }
+
+// Addresses implements the snow.Signer interface
+func (u *user) Addresses() ([]ids.ShortID, error) { return u.getAccountIDs() }
+
+// SignHash implements the snow.Signer interface
+func (u *user) SignHash(accountID ids.ShortID, hash []byte) ([]byte, error) {
+	key, err := u.getKey(accountID)
+	if err != nil {
+		return nil, err
+	}
+	if !key.PublicKey().Address().Equals(accountID) { // sanity check
+		return nil, errUnexpectedKey
+	}
+	return key.SignHash(hash)
+}
+
+// signingKey signs with the key that controls an account
+type signingKey interface {
+	Address() ids.ShortID
+	Sign(msg []byte) ([]byte, error)
+}
+
+// signerKey is the key that controls an account, as seen through a signer
+type signerKey struct {
+	signer snow.Signer
+	addr   ids.ShortID
+}
+
+// Address returns the address of the account the key controls
+func (k *signerKey) Address() ids.ShortID { return k.addr }
+
+// Sign returns the signature of the hash of [msg] by the key
+func (k *signerKey) Sign(msg []byte) ([]byte, error) {
+	return k.signer.SignHash(k.addr, hashing.ComputeHash256(msg))
+}
@@ -12,6 +12,7 @@ import (
 	stdmath "math"
 
 	"github.com/ava-labs/gecko/chains"
+	"github.com/ava-labs/gecko/chains/atomic"
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/database/versiondb"
 	"github.com/ava-labs/gecko/ids"
@@ -22,7 +23,6 @@ import (
 	"github.com/ava-labs/gecko/utils/crypto"
 	"github.com/ava-labs/gecko/utils/math"
 	"github.com/ava-labs/gecko/utils/timer"
-	"github.com/ava-labs/gecko/utils/units"
 	"github.com/ava-labs/gecko/utils/wrappers"
 	"github.com/ava-labs/gecko/vms/components/codec"
 	"github.com/ava-labs/gecko/vms/components/core"
@@ -32,31 +32,13 @@ const (
 	// For putting/getting values from state
 	accountTypeID uint64 = iota
 	validatorsTypeID
-	chainsTypeID
+	legacyChainsTypeID
 	blockTypeID
 	subnetsTypeID
-
-	// Delta is the synchrony bound used for safe decision making
-	Delta = 10 * time.Second // TODO change to longer period (2 minutes?) before release
-
-	// InflationRate is the maximum inflation rate of AVA from staking
-	InflationRate = 1.04
-
-	// BatchSize is the number of decision transaction to place into a block
-	BatchSize = 30
-
-	// TODO: Incorporate these constants + turn them into governable parameters
-
-	// MinimumStakeAmount is the minimum amount of $AVA one must bond to be a staker
-	MinimumStakeAmount = 10 * units.MicroAva
-
-	// MinimumStakingDuration is the shortest amount of time a staker can bond
-	// their funds for.
-	MinimumStakingDuration = 24 * time.Hour
-
-	// MaximumStakingDuration is the longest amount of time a staker can bond
-	// their funds for.
-	MaximumStakingDuration = 365 * 24 * time.Hour
+	supplyTypeID
+	stakingParametersTypeID
+	uptimeTypeID
+	chainsTypeID
 
 	// NumberOfShares is the number of shares that a delegator is
 	// rewarded
@@ -75,6 +57,8 @@ var (
 	pendingValidatorsKey = ids.NewID([32]byte{'p', 'e', 'n', 'd', 'i', 'n', 'g'})
 	chainsKey            = ids.NewID([32]byte{'c', 'h', 'a', 'i', 'n', 's'})
 	subnetsKey           = ids.NewID([32]byte{'s', 'u', 'b', 'n', 'e', 't', 's'})
+	supplyKey            = ids.NewID([32]byte{'s', 'u', 'p', 'p', 'l', 'y'})
+	stakingParametersKey = ids.NewID([32]byte{'s', 't', 'a', 'k', 'i', 'n', 'g'})
 )
 
 var (
@@ -126,6 +110,18 @@ func init() {
 
 		Codec.RegisterType(&advanceTimeTx{}),
 		Codec.RegisterType(&rewardValidatorTx{}),
+
+		Codec.RegisterType(&UnsignedExportTx{}),
+		Codec.RegisterType(&ExportTx{}),
+
+		Codec.RegisterType(&UnsignedImportTx{}),
+		Codec.RegisterType(&ImportTx{}),
+
+		Codec.RegisterType(&UnsignedSetStakingParametersTx{}),
+		Codec.RegisterType(&SetStakingParametersTx{}),
+
+		Codec.RegisterType(&UnsignedRemoveSubnetValidatorTx{}),
+		Codec.RegisterType(&RemoveSubnetValidatorTx{}),
 	)
 	if errs.Errored() {
 		panic(errs.Err)
@@ -141,6 +137,23 @@ type VM struct {
 	// The node's chain manager
 	ChainManager chains.Manager
 
+	// ID of the AVM chain that AVA can be exported to and imported from
+	avm ids.ID
+
+	// ID of the AVA asset on the AVM chain
+	ava ids.ID
+
+	// Amount of AVA burnt by every transaction that has a payer
+	txFee uint64
+
+	// Fraction of its staking period a staker must have been connected to
+	// this node for this node to initially prefer rewarding it
+	uptimeRequirement float64
+
+	// Measures how long the stakers of the default subnet are connected to
+	// this node
+	uptimes *uptimeTracker
+
 	// Used to create and use keys.
 	factory crypto.FactorySECP256K1R
 
@@ -174,6 +187,9 @@ func (vm *VM) Initialize(
 	if len(fxs) != 0 {
 		return errUnsupportedFXs
 	}
+	if vm.uptimeRequirement < 0 || vm.uptimeRequirement > 1 {
+		return errInvalidUptimeRequired
+	}
 
 	// Initialize the inner VM, which has a lot of boiler-plate logic
 	vm.SnowmanVM = &core.SnowmanVM{}
@@ -202,6 +218,31 @@ func (vm *VM) Initialize(
 			}
 		}
 
+		// Persist the amount of nAva that exist at genesis, which stakers'
+		// rewards are based on
+		supply, err := genesis.Supply(vm.avm, vm.ava)
+		if err != nil {
+			return err
+		}
+		if err := vm.putSupply(vm.DB, supply); err != nil {
+			return errDB
+		}
+
+		// Persist the staking parameters at genesis, and who may change them
+		if err := genesis.StakingParameters.Verify(); err != nil {
+			return fmt.Errorf("invalid genesis staking parameters: %w", err)
+		}
+		if err := genesis.Governance.Verify(); err != nil {
+			return fmt.Errorf("invalid genesis governance: %w", err)
+		}
+		schedule := &parameterSchedule{
+			Current:    genesis.StakingParameters,
+			Governance: genesis.Governance,
+		}
+		if err := vm.putParameterSchedule(vm.DB, schedule); err != nil {
+			return errDB
+		}
+
 		// Persist default subnet validator set at genesis
 		if err := vm.putCurrentValidators(vm.DB, genesis.Validators, DefaultSubnetID); err != nil {
 			return errDBPutCurrentValidators
@@ -252,6 +293,11 @@ func (vm *VM) Initialize(
 		vm.SetDBInitialized()
 	}
 
+	if err := vm.migrateChains(); err != nil {
+		ctx.Log.Error("failed to migrate the list of chains: %s", err)
+		return err
+	}
+
 	// Transactions from clients that have not yet been put into blocks
 	// and added to consensus
 	vm.unissuedEvents = &EventHeap{SortByStartTime: true}
@@ -265,11 +311,27 @@ func (vm *VM) Initialize(
 	})
 	go ctx.Log.RecoverAndPanic(vm.timer.Dispatch)
 
+	vm.uptimes = newUptimeTracker(vm)
 	if err := vm.updateValidators(DefaultSubnetID); err != nil {
 		ctx.Log.Error("failed to initialize the current validator set: %s", err)
 		return err
 	}
 
+	// The chain manager only creates the chains of the subnets this node
+	// validates, so the validators of each subnet must be known before the
+	// chains are created
+	subnets, err := vm.getSubnets(vm.DB)
+	if err != nil {
+		ctx.Log.Error("failed to get subnets: %s", err)
+		return err
+	}
+	for _, subnet := range subnets {
+		if err := vm.updateValidators(subnet.ID); err != nil {
+			ctx.Log.Error("failed to initialize the current validator set of subnet %s: %s", subnet.ID, err)
+			return err
+		}
+	}
+
 	// Create all of the chains that the database says exist
 	if err := vm.initBlockchains(); err != nil {
 		vm.Ctx.Log.Warn("could not retrieve existing chains from database: %s", err)
@@ -292,6 +354,7 @@ func (vm *VM) initBlockchains() error {
 	for _, chain := range existingChains { // Create each blockchain
 		chainParams := chains.ChainParameters{
 			ID:          chain.ID(),
+			SubnetID:    chain.SubnetID,
 			GenesisData: chain.GenesisData,
 			VMAlias:     chain.VMID.String(),
 		}
@@ -306,6 +369,11 @@ func (vm *VM) initBlockchains() error {
 // Shutdown this blockchain
 func (vm *VM) Shutdown() {
 	vm.timer.Stop()
+	if err := vm.uptimes.persist(vm.DB); err != nil {
+		vm.Ctx.Log.Error("Persisting the uptimes of stakers failed with %s", err)
+	} else if err := vm.DB.Commit(); err != nil {
+		vm.Ctx.Log.Error("Persisting the uptimes of stakers failed with %s", err)
+	}
 	if err := vm.DB.Close(); err != nil {
 		vm.Ctx.Log.Error("Closing the database failed with %s", err)
 	}
@@ -316,9 +384,28 @@ func (vm *VM) BuildBlock() (snowman.Block, error) {
 	vm.Ctx.Log.Debug("in BuildBlock")
 	preferredID := vm.Preferred()
 
+	// Get the preferred block (which we want to build off)
+	preferred, err := vm.getBlock(preferredID)
+	vm.Ctx.Log.AssertNoError(err)
+
+	// The database if the preferred block were to be accepted
+	var db database.Database
+	// The preferred block should always be a decision block
+	if preferred, ok := preferred.(decision); ok {
+		db = preferred.onAccept()
+	} else {
+		return nil, errInvalidBlockType
+	}
+
+	// The staking parameters if the preferred block were to be accepted
+	params, err := vm.getStakingParameters(db)
+	if err != nil {
+		return nil, err
+	}
+
 	// If there are pending decision txs, build a block with a batch of them
 	if len(vm.unissuedDecisionTxs) > 0 {
-		numTxs := BatchSize
+		numTxs := int(params.BatchSize)
 		if numTxs > len(vm.unissuedDecisionTxs) {
 			numTxs = len(vm.unissuedDecisionTxs)
 		}
@@ -338,19 +425,6 @@ func (vm *VM) BuildBlock() (snowman.Block, error) {
 		return blk, vm.DB.Commit()
 	}
 
-	// Get the preferred block (which we want to build off)
-	preferred, err := vm.getBlock(preferredID)
-	vm.Ctx.Log.AssertNoError(err)
-
-	// The database if the preferred block were to be accepted
-	var db database.Database
-	// The preferred block should always be a decision block
-	if preferred, ok := preferred.(decision); ok {
-		db = preferred.onAccept()
-	} else {
-		return nil, errInvalidBlockType
-	}
-
 	// The chain time if the preferred block were to be committed
 	currentChainTimestamp, err := vm.getTimestamp(db)
 	if err != nil {
@@ -414,7 +488,7 @@ func (vm *VM) BuildBlock() (snowman.Block, error) {
 
 	// Propose adding a new validator but only if their start time is in the
 	// future relative to local time (plus Delta)
-	syncTime := localTime.Add(Delta)
+	syncTime := localTime.Add(params.Delta)
 	for vm.unissuedEvents.Len() > 0 {
 		tx := vm.unissuedEvents.Remove()
 		if !syncTime.After(tx.StartTime()) {
@@ -472,6 +546,37 @@ func (vm *VM) getBlock(blkID ids.ID) (Block, error) {
 	return nil, errors.New("block not found")
 }
 
+// commit writes the state of the chain to its database. The changes [txs] make
+// to the memory shared with the AVM chain are written in the same batch, so AVA
+// moved between the chains is never spendable on both of them, or on neither
+// of them.
+func (vm *VM) commit(txs []atomicTx) error {
+	if len(txs) == 0 {
+		return vm.DB.Commit()
+	}
+
+	smDB := vm.Ctx.SharedMemory.GetDatabase(vm.avm)
+	defer vm.Ctx.SharedMemory.ReleaseDatabase(vm.avm)
+
+	vsmDB := versiondb.New(smDB)
+	for _, tx := range txs {
+		tx.writeSharedMemory(vsmDB)
+	}
+
+	batch, err := vm.DB.CommitBatch()
+	if err != nil {
+		return err
+	}
+	smBatch, err := vsmDB.CommitBatch()
+	if err != nil {
+		return err
+	}
+	if err := atomic.WriteAll(batch, smBatch); err != nil {
+		return err
+	}
+	return vm.DB.Abort()
+}
+
 // SetPreference sets the preferred block to be the one with ID [blkID]
 func (vm *VM) SetPreference(blkID ids.ID) {
 	if !blkID.Equals(vm.Preferred()) {
@@ -556,7 +661,12 @@ func (vm *VM) resetTimer() {
 		return
 	}
 
-	syncTime := localTime.Add(Delta)
+	params, err := vm.getStakingParameters(db)
+	if err != nil {
+		vm.Ctx.Log.Error("could not retrieve staking parameters from database")
+		return
+	}
+	syncTime := localTime.Add(params.Delta)
 	for vm.unissuedEvents.Len() > 0 {
 		if !syncTime.After(vm.unissuedEvents.Peek().StartTime()) {
 			vm.SnowmanVM.NotifyBlockReady() // Should issue a ProposeAddValidator
@@ -674,7 +784,8 @@ func (vm *VM) getValidators(validatorEvents *EventHeap) []validators.Validator {
 func (vm *VM) updateValidators(subnetID ids.ID) error {
 	validatorSet, ok := vm.Validators.GetValidatorSet(subnetID)
 	if !ok {
-		return fmt.Errorf("couldn't get the validator sampler of the %s subnet", subnetID)
+		validatorSet = validators.NewSet()
+		vm.Validators.PutValidatorSet(subnetID, validatorSet)
 	}
 
 	currentValidators, err := vm.getCurrentValidators(vm.DB, subnetID)
@@ -684,5 +795,23 @@ func (vm *VM) updateValidators(subnetID ids.ID) error {
 
 	validators := vm.getValidators(currentValidators)
 	validatorSet.Set(validators)
-	return nil
+
+	if !subnetID.Equals(DefaultSubnetID) {
+		// This node may have started validating the subnet, in which case the
+		// subnet's chains can be created now
+		if vm.ChainManager != nil {
+			vm.ChainManager.ValidatorsChanged(subnetID)
+		}
+		return nil
+	}
+	if err := vm.uptimes.setStakers(vm.DB, currentValidators.Txs); err != nil {
+		return err
+	}
+	return vm.DB.Commit()
 }
+
+// Connected implements the networking.Connector interface
+func (vm *VM) Connected(validatorID ids.ShortID) { vm.uptimes.Connected(validatorID) }
+
+// Disconnected implements the networking.Connector interface
+func (vm *VM) Disconnected(validatorID ids.ShortID) { vm.uptimes.Disconnected(validatorID) }
@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/database/memdb"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
@@ -18,6 +19,7 @@ import (
 	"github.com/ava-labs/gecko/snow/validators"
 	"github.com/ava-labs/gecko/utils/crypto"
 	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/units"
 	"github.com/ava-labs/gecko/vms/components/core"
 	"github.com/ava-labs/gecko/vms/timestampvm"
 )
@@ -30,7 +32,7 @@ var (
 	defaultValidateStartTime = defaultGenesisTime.Add(1 * time.Second)
 
 	// time that genesis validators stop validating
-	defaultValidateEndTime = defaultValidateStartTime.Add(10 * MinimumStakingDuration)
+	defaultValidateEndTime = defaultValidateStartTime.Add(10 * DefaultStakingParameters.MinimumStakingDuration)
 
 	// each key corresponds to an account that has $AVA and a genesis validator
 	keys []*crypto.PrivateKeySECP256K1R
@@ -39,7 +41,7 @@ var (
 	defaultStakeAmount uint64
 
 	// balance of accounts that exist at genesis
-	defaultBalance = 100 * MinimumStakeAmount
+	defaultBalance = 100 * DefaultStakingParameters.MinimumStakeAmount
 
 	// At genesis this account has AVA and is validating the default subnet
 	defaultKey *crypto.PrivateKeySECP256K1R
@@ -47,6 +49,10 @@ var (
 	// non-default subnet that exists at genesis in defaultVM
 	testSubnet1            *CreateSubnetTx
 	testSubnet1ControlKeys []*crypto.PrivateKeySECP256K1R
+
+	// keys that govern the staking parameters in defaultVM, with a threshold
+	// of 2
+	testGovernanceKeys []*crypto.PrivateKeySECP256K1R
 )
 
 var (
@@ -60,6 +66,9 @@ const (
 
 	defaultNonce  = 1
 	defaultWeight = 1
+
+	// amount of AVA burnt by every transaction that has a payer
+	defaultTxFee = 10 * units.NanoAva
 )
 
 func init() {
@@ -80,12 +89,14 @@ func init() {
 		keys = append(keys, pk.(*crypto.PrivateKeySECP256K1R))
 	}
 
-	defaultStakeAmount = defaultBalance - txFee
+	defaultStakeAmount = defaultBalance - defaultTxFee
 
 	defaultKey = keys[0]
 
 	testSubnet1ControlKeys = keys[0:3]
 
+	testGovernanceKeys = keys[2:5]
+
 }
 
 func defaultContext() *snow.Context {
@@ -94,16 +105,28 @@ func defaultContext() *snow.Context {
 	return ctx
 }
 
-func defaultVM() *VM {
+func defaultVM() *VM { return defaultVMWithDB(memdb.New()) }
+
+// defaultVMWithDB returns a default VM whose state is stored in [db]
+func defaultVMWithDB(db database.Database) *VM {
 	genesisAccounts := GenesisAccounts()
 	genesisValidators := GenesisCurrentValidators()
-	genesisChains := make([]*CreateChainTx, 0)
+	genesisChains := make([]*legacyCreateChainTx, 0)
 
 	genesisState := Genesis{
-		Accounts:   genesisAccounts,
-		Validators: genesisValidators,
-		Chains:     genesisChains,
-		Timestamp:  uint64(defaultGenesisTime.Unix()),
+		Accounts:          genesisAccounts,
+		Validators:        genesisValidators,
+		GenesisChains:     genesisChains,
+		Timestamp:         uint64(defaultGenesisTime.Unix()),
+		StakingParameters: DefaultStakingParameters,
+		Governance: Governance{
+			ControlKeys: []ids.ShortID{
+				testGovernanceKeys[0].PublicKey().Address(),
+				testGovernanceKeys[1].PublicKey().Address(),
+				testGovernanceKeys[2].PublicKey().Address(),
+			},
+			Threshold: 2,
+		},
 	}
 
 	genesisBytes, err := Codec.Marshal(genesisState)
@@ -113,6 +136,7 @@ func defaultVM() *VM {
 
 	vm := &VM{
 		SnowmanVM: &core.SnowmanVM{},
+		txFee:     defaultTxFee,
 	}
 
 	defaultSubnet := validators.NewSet()
@@ -120,7 +144,6 @@ func defaultVM() *VM {
 	vm.Validators.PutValidatorSet(DefaultSubnetID, defaultSubnet)
 
 	vm.clock.Set(defaultGenesisTime)
-	db := memdb.New()
 	msgChan := make(chan common.Message, 1)
 	ctx := defaultContext()
 	if err := vm.Initialize(ctx, db, genesisBytes, msgChan, nil); err != nil {
@@ -283,8 +306,8 @@ func TestGenesis(t *testing.T) {
 // accept proposal to add validator to default subnet
 func TestAddDefaultSubnetValidatorCommit(t *testing.T) {
 	vm := defaultVM()
-	startTime := defaultGenesisTime.Add(Delta).Add(1 * time.Second)
-	endTime := startTime.Add(MinimumStakingDuration)
+	startTime := defaultGenesisTime.Add(DefaultStakingParameters.Delta).Add(1 * time.Second)
+	endTime := startTime.Add(DefaultStakingParameters.MinimumStakingDuration)
 	key, _ := vm.factory.NewPrivateKey()
 	ID := key.PublicKey().Address()
 
@@ -350,8 +373,8 @@ func TestAddDefaultSubnetValidatorCommit(t *testing.T) {
 // Reject proposal to add validator to default subnet
 func TestAddDefaultSubnetValidatorReject(t *testing.T) {
 	vm := defaultVM()
-	startTime := defaultGenesisTime.Add(Delta).Add(1 * time.Second)
-	endTime := startTime.Add(MinimumStakingDuration)
+	startTime := defaultGenesisTime.Add(DefaultStakingParameters.Delta).Add(1 * time.Second)
+	endTime := startTime.Add(DefaultStakingParameters.MinimumStakingDuration)
 	key, _ := vm.factory.NewPrivateKey()
 	ID := key.PublicKey().Address()
 
@@ -421,8 +444,8 @@ func TestAddDefaultSubnetValidatorReject(t *testing.T) {
 // Accept proposal to add validator to non-default subnet
 func TestAddNonDefaultSubnetValidatorAccept(t *testing.T) {
 	vm := defaultVM()
-	startTime := defaultValidateStartTime.Add(Delta).Add(1 * time.Second)
-	endTime := startTime.Add(MinimumStakingDuration)
+	startTime := defaultValidateStartTime.Add(DefaultStakingParameters.Delta).Add(1 * time.Second)
+	endTime := startTime.Add(DefaultStakingParameters.MinimumStakingDuration)
 
 	// create valid tx
 	// note that [startTime, endTime] is a subset of time that keys[0]
@@ -492,8 +515,8 @@ func TestAddNonDefaultSubnetValidatorAccept(t *testing.T) {
 // Reject proposal to add validator to non-default subnet
 func TestAddNonDefaultSubnetValidatorReject(t *testing.T) {
 	vm := defaultVM()
-	startTime := defaultValidateStartTime.Add(Delta).Add(1 * time.Second)
-	endTime := startTime.Add(MinimumStakingDuration)
+	startTime := defaultValidateStartTime.Add(DefaultStakingParameters.Delta).Add(1 * time.Second)
+	endTime := startTime.Add(DefaultStakingParameters.MinimumStakingDuration)
 	key, _ := vm.factory.NewPrivateKey()
 	ID := key.PublicKey().Address()
 
@@ -761,11 +784,13 @@ func TestCreateChain(t *testing.T) {
 
 	tx, err := vm.newCreateChainTx(
 		defaultNonce+1,
+		testSubnet1.ID,
 		nil,
 		timestampvm.ID,
 		nil,
 		"name ",
 		testNetworkID,
+		testSubnet1ControlKeys[:2],
 		keys[0],
 	)
 	if err != nil {
@@ -802,11 +827,11 @@ func TestCreateChain(t *testing.T) {
 	}
 
 	// Verify tx fee was deducted
-	account, err := vm.getAccount(vm.DB, tx.Key().Address())
+	account, err := vm.getAccount(vm.DB, tx.PayerAddress())
 	if err != nil {
 		t.Fatal(err)
 	}
-	if account.Balance != defaultBalance-txFee {
+	if account.Balance != defaultBalance-defaultTxFee {
 		t.Fatal("should have deducted txFee from balance")
 	}
 }
@@ -867,13 +892,13 @@ func TestCreateSubnet(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if account.Balance != defaultBalance-txFee {
+	if account.Balance != defaultBalance-defaultTxFee {
 		t.Fatal("should have deducted txFee from balance")
 	}
 
 	// Now that we've created a new subnet, add a validator to that subnet
-	startTime := defaultValidateStartTime.Add(Delta).Add(1 * time.Second)
-	endTime := startTime.Add(MinimumStakingDuration)
+	startTime := defaultValidateStartTime.Add(DefaultStakingParameters.Delta).Add(1 * time.Second)
+	endTime := startTime.Add(DefaultStakingParameters.MinimumStakingDuration)
 	// [startTime, endTime] is subset of time keys[0] validates default subent so tx is valid
 	addValidatorTx, err := vm.newAddNonDefaultSubnetValidatorTx(
 		defaultNonce+2,
@@ -0,0 +1,132 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcvm
+
+import (
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/consensus/snowman"
+	"github.com/ava-labs/gecko/vms/components/missing"
+)
+
+// ChainVMClient implements snowman.ChainVM by calling the VM of a plugin
+type ChainVMClient struct{ vmClient }
+
+// NewChainVMClient returns a client of the snowman.ChainVM served by [p]
+func NewChainVMClient(p plugin) *ChainVMClient {
+	return &ChainVMClient{vmClient: vmClient{plugin: p}}
+}
+
+// BuildBlock implements the snowman.ChainVM interface
+func (vm *ChainVMClient) BuildBlock() (snowman.Block, error) {
+	reply := BlockReply{}
+	if err := vm.client.Call("VM.BuildBlock", &struct{}{}, &reply); err != nil {
+		return nil, err
+	}
+	return vm.newBlock(&reply), nil
+}
+
+// ParseBlock implements the snowman.ChainVM interface
+func (vm *ChainVMClient) ParseBlock(b []byte) (snowman.Block, error) {
+	reply := BlockReply{}
+	if err := vm.client.Call("VM.ParseBlock", &BytesArgs{Bytes: b}, &reply); err != nil {
+		return nil, err
+	}
+	return vm.newBlock(&reply), nil
+}
+
+// GetBlock implements the snowman.ChainVM interface
+func (vm *ChainVMClient) GetBlock(id ids.ID) (snowman.Block, error) {
+	reply := BlockReply{}
+	if err := vm.client.Call("VM.GetBlock", &IDArgs{ID: id.Key()}, &reply); err != nil {
+		return nil, err
+	}
+	return vm.newBlock(&reply), nil
+}
+
+// SetPreference implements the snowman.ChainVM interface
+func (vm *ChainVMClient) SetPreference(id ids.ID) {
+	if err := vm.client.Call("VM.SetPreference", &IDArgs{ID: id.Key()}, &struct{}{}); err != nil {
+		vm.ctx.Log.Error("couldn't set the preference of the plugin's vm: %s", err)
+	}
+}
+
+// LastAccepted implements the snowman.ChainVM interface
+func (vm *ChainVMClient) LastAccepted() ids.ID {
+	reply := IDReply{}
+	if err := vm.client.Call("VM.LastAccepted", &struct{}{}, &reply); err != nil {
+		vm.ctx.Log.Error("couldn't get the last accepted block of the plugin's vm: %s", err)
+		return ids.Empty
+	}
+	return ids.NewID(reply.ID)
+}
+
+func (vm *ChainVMClient) newBlock(reply *BlockReply) *block {
+	blk := &block{
+		vm:     vm,
+		id:     ids.NewID(reply.ID),
+		status: choices.Status(reply.Status),
+		bytes:  reply.Bytes,
+	}
+	if reply.HasParent {
+		blk.parentID = ids.NewID(reply.ParentID)
+	}
+	return blk
+}
+
+// block is a block of the plugin's VM
+type block struct {
+	vm *ChainVMClient
+
+	id       ids.ID
+	parentID ids.ID
+	status   choices.Status
+	bytes    []byte
+}
+
+func (b *block) ID() ids.ID { return b.id }
+
+func (b *block) Accept() {
+	b.status = choices.Accepted
+	if err := b.vm.client.Call("VM.BlockAccept", &IDArgs{ID: b.id.Key()}, &struct{}{}); err != nil {
+		b.vm.ctx.Log.Error("couldn't accept block %s: %s", b.id, err)
+	}
+}
+
+func (b *block) Reject() {
+	b.status = choices.Rejected
+	if err := b.vm.client.Call("VM.BlockReject", &IDArgs{ID: b.id.Key()}, &struct{}{}); err != nil {
+		b.vm.ctx.Log.Error("couldn't reject block %s: %s", b.id, err)
+	}
+}
+
+// Status of a block that hasn't been decided may have been changed by the
+// plugin's VM, so it's requested from the plugin
+func (b *block) Status() choices.Status {
+	if b.status.Decided() {
+		return b.status
+	}
+	reply := StatusReply{}
+	if err := b.vm.client.Call("VM.BlockStatus", &IDArgs{ID: b.id.Key()}, &reply); err == nil {
+		b.status = choices.Status(reply.Status)
+	}
+	return b.status
+}
+
+func (b *block) Parent() snowman.Block {
+	if b.parentID.IsZero() {
+		return nil
+	}
+	parent, err := b.vm.GetBlock(b.parentID)
+	if err != nil {
+		return &missing.Block{BlkID: b.parentID}
+	}
+	return parent
+}
+
+func (b *block) Verify() error {
+	return b.vm.client.Call("VM.BlockVerify", &IDArgs{ID: b.id.Key()}, &struct{}{})
+}
+
+func (b *block) Bytes() []byte { return b.bytes }
@@ -0,0 +1,114 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcvm
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/vms/timestampvm"
+)
+
+func TestChainVMClient(t *testing.T) {
+	vm := NewChainVMClient(&testPlugin{vm: &timestampvm.VM{}})
+	msgChan := make(chan common.Message, 1)
+	if err := vm.Initialize(snow.DefaultContextTest(), memdb.New(), []byte{1}, msgChan, nil); err != nil {
+		t.Fatal(err)
+	}
+	defer vm.Shutdown()
+
+	genesisID := vm.LastAccepted()
+	genesis, err := vm.GetBlock(genesisID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := genesis.Status(); status != choices.Accepted {
+		t.Fatalf("Wrong status. Expected: %s ; Returned: %s", choices.Accepted, status)
+	}
+	if status := genesis.Parent().Status(); status != choices.Unknown {
+		t.Fatalf("The genesis block's parent shouldn't exist")
+	}
+
+	// Propose a block through the VM's API, which is proxied to the plugin
+	handlers := vm.CreateHandlers()
+	handler, ok := handlers[""]
+	if !ok {
+		t.Fatalf("Should have proxied the VM's handler")
+	}
+	data := formatting.CB58{Bytes: bytes.Repeat([]byte{2}, 32)}
+	body := `{"jsonrpc":"2.0","method":"timestamp.proposeBlock","params":{"data":"` + data.String() + `"},"id":1}`
+	req := httptest.NewRequest(http.MethodPost, "/ext/bc/timestamp", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Wrong status code. Expected: %d ; Returned: %d", http.StatusOK, w.Code)
+	}
+	if !strings.Contains(w.Body.String(), `"Success":true`) {
+		t.Fatalf("Should have proposed the block, but the response was %s", w.Body.String())
+	}
+
+	// The VM should tell the engine a block is ready
+	select {
+	case msg := <-msgChan:
+		if msg != common.PendingTxs {
+			t.Fatalf("Wrong message. Expected: %s ; Returned: %s", common.PendingTxs, msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Should have notified the engine")
+	}
+
+	vm.SetPreference(genesisID)
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !blk.Parent().ID().Equals(genesisID) {
+		t.Fatalf("The block should be built on the genesis block")
+	}
+	if err := blk.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if status := blk.Status(); status != choices.Processing {
+		t.Fatalf("Wrong status. Expected: %s ; Returned: %s", choices.Processing, status)
+	}
+
+	parsedBlk, err := vm.ParseBlock(blk.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !parsedBlk.ID().Equals(blk.ID()) {
+		t.Fatalf("Wrong block. Expected: %s ; Returned: %s", blk.ID(), parsedBlk.ID())
+	}
+
+	blk.Accept()
+	if lastAccepted := vm.LastAccepted(); !lastAccepted.Equals(blk.ID()) {
+		t.Fatalf("Wrong last accepted block. Expected: %s ; Returned: %s", blk.ID(), lastAccepted)
+	}
+	if status := parsedBlk.Status(); status != choices.Accepted {
+		t.Fatalf("Wrong status. Expected: %s ; Returned: %s", choices.Accepted, status)
+	}
+
+	if _, err := vm.BuildBlock(); err == nil {
+		t.Fatalf("Should have failed because there are no pending blocks")
+	}
+}
+
+func TestChainVMClientFxs(t *testing.T) {
+	vm := NewChainVMClient(&testPlugin{vm: &timestampvm.VM{}})
+	msgChan := make(chan common.Message, 1)
+	err := vm.Initialize(snow.DefaultContextTest(), memdb.New(), nil, msgChan, []*common.Fx{{}})
+	if err != errFxsUnsupported {
+		t.Fatalf("Should have errored with %s but errored with %v", errFxsUnsupported, err)
+	}
+}
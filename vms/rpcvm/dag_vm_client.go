@@ -0,0 +1,145 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcvm
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/consensus/snowstorm"
+)
+
+var (
+	errMissingTx = errors.New("missing tx")
+)
+
+// DAGVMClient implements avalanche.DAGVM by calling the VM of a plugin
+type DAGVMClient struct{ vmClient }
+
+// NewDAGVMClient returns a client of the avalanche.DAGVM served by [p]
+func NewDAGVMClient(p plugin) *DAGVMClient {
+	return &DAGVMClient{vmClient: vmClient{plugin: p}}
+}
+
+// PendingTxs implements the avalanche.DAGVM interface
+func (vm *DAGVMClient) PendingTxs() []snowstorm.Tx {
+	reply := TxsReply{}
+	if err := vm.client.Call("VM.PendingTxs", &struct{}{}, &reply); err != nil {
+		vm.ctx.Log.Error("couldn't get the pending txs of the plugin's vm: %s", err)
+		return nil
+	}
+	txs := make([]snowstorm.Tx, len(reply.Txs))
+	for i := range reply.Txs {
+		txs[i] = vm.newTx(&reply.Txs[i])
+	}
+	return txs
+}
+
+// ParseTx implements the avalanche.DAGVM interface
+func (vm *DAGVMClient) ParseTx(b []byte) (snowstorm.Tx, error) {
+	reply := TxReply{}
+	if err := vm.client.Call("VM.ParseTx", &BytesArgs{Bytes: b}, &reply); err != nil {
+		return nil, err
+	}
+	return vm.newTx(&reply), nil
+}
+
+// GetTx implements the avalanche.DAGVM interface
+func (vm *DAGVMClient) GetTx(id ids.ID) (snowstorm.Tx, error) {
+	reply := TxReply{}
+	if err := vm.client.Call("VM.GetTx", &IDArgs{ID: id.Key()}, &reply); err != nil {
+		return nil, err
+	}
+	return vm.newTx(&reply), nil
+}
+
+func (vm *DAGVMClient) newTx(reply *TxReply) *tx {
+	t := &tx{
+		vm:     vm,
+		id:     ids.NewID(reply.ID),
+		status: choices.Status(reply.Status),
+		bytes:  reply.Bytes,
+	}
+	for _, inputID := range reply.InputIDs {
+		t.inputIDs.Add(ids.NewID(inputID))
+	}
+	for _, depID := range reply.DependencyIDs {
+		t.dependencyIDs = append(t.dependencyIDs, ids.NewID(depID))
+	}
+	return t
+}
+
+// tx is a tx of the plugin's VM
+type tx struct {
+	vm *DAGVMClient
+
+	id            ids.ID
+	status        choices.Status
+	bytes         []byte
+	inputIDs      ids.Set
+	dependencyIDs []ids.ID
+
+	// missing is true if the plugin's VM doesn't know about this tx
+	missing bool
+}
+
+func (t *tx) ID() ids.ID { return t.id }
+
+func (t *tx) Accept() {
+	t.status = choices.Accepted
+	if err := t.vm.client.Call("VM.TxAccept", &IDArgs{ID: t.id.Key()}, &struct{}{}); err != nil {
+		t.vm.ctx.Log.Error("couldn't accept tx %s: %s", t.id, err)
+	}
+}
+
+func (t *tx) Reject() {
+	t.status = choices.Rejected
+	if err := t.vm.client.Call("VM.TxReject", &IDArgs{ID: t.id.Key()}, &struct{}{}); err != nil {
+		t.vm.ctx.Log.Error("couldn't reject tx %s: %s", t.id, err)
+	}
+}
+
+// Status of a tx that hasn't been decided may have been changed by the
+// plugin's VM, so it's requested from the plugin
+func (t *tx) Status() choices.Status {
+	if t.status.Decided() || t.missing {
+		return t.status
+	}
+	reply := StatusReply{}
+	if err := t.vm.client.Call("VM.TxStatus", &IDArgs{ID: t.id.Key()}, &reply); err == nil {
+		t.status = choices.Status(reply.Status)
+	}
+	return t.status
+}
+
+// Dependencies that the plugin's VM doesn't know about are returned with the
+// status Unknown
+func (t *tx) Dependencies() []snowstorm.Tx {
+	deps := make([]snowstorm.Tx, len(t.dependencyIDs))
+	for i, depID := range t.dependencyIDs {
+		dep, err := t.vm.GetTx(depID)
+		if err != nil {
+			dep = &tx{
+				vm:      t.vm,
+				id:      depID,
+				status:  choices.Unknown,
+				missing: true,
+			}
+		}
+		deps[i] = dep
+	}
+	return deps
+}
+
+func (t *tx) InputIDs() ids.Set { return t.inputIDs }
+
+func (t *tx) Verify() error {
+	if t.missing {
+		return errMissingTx
+	}
+	return t.vm.client.Call("VM.TxVerify", &IDArgs{ID: t.id.Key()}, &struct{}{})
+}
+
+func (t *tx) Bytes() []byte { return t.bytes }
@@ -0,0 +1,131 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcvm
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/consensus/snowstorm"
+	"github.com/ava-labs/gecko/snow/engine/common"
+)
+
+var (
+	errUnknownTx = errors.New("unknown tx")
+)
+
+// testDAGVM is an avalanche.DAGVM that serves a fixed set of txs
+type testDAGVM struct {
+	txs     []*snowstorm.TestTx
+	pending []snowstorm.Tx
+}
+
+func (vm *testDAGVM) Initialize(*snow.Context, database.Database, []byte, chan<- common.Message, []*common.Fx) error {
+	return nil
+}
+
+func (vm *testDAGVM) Shutdown() {}
+
+func (vm *testDAGVM) CreateHandlers() map[string]*common.HTTPHandler { return nil }
+
+func (vm *testDAGVM) PendingTxs() []snowstorm.Tx { return vm.pending }
+
+func (vm *testDAGVM) ParseTx(b []byte) (snowstorm.Tx, error) {
+	for _, tx := range vm.txs {
+		if bytes.Equal(tx.Bytes(), b) {
+			return tx, nil
+		}
+	}
+	return nil, errUnknownTx
+}
+
+func (vm *testDAGVM) GetTx(id ids.ID) (snowstorm.Tx, error) {
+	for _, tx := range vm.txs {
+		if tx.ID().Equals(id) {
+			return tx, nil
+		}
+	}
+	return nil, errUnknownTx
+}
+
+func TestDAGVMClient(t *testing.T) {
+	tx0 := &snowstorm.TestTx{
+		Identifier: ids.NewID([32]byte{1}),
+		Stat:       choices.Accepted,
+		Bits:       []byte{1},
+	}
+	missingTx := &snowstorm.TestTx{Identifier: ids.NewID([32]byte{2})}
+	tx1 := &snowstorm.TestTx{
+		Identifier: ids.NewID([32]byte{3}),
+		Deps:       []snowstorm.Tx{tx0, missingTx},
+		Stat:       choices.Processing,
+		Bits:       []byte{3},
+	}
+	tx1.Ins.Add(ids.NewID([32]byte{4}), ids.NewID([32]byte{5}))
+	testVM := &testDAGVM{
+		txs:     []*snowstorm.TestTx{tx0, tx1},
+		pending: []snowstorm.Tx{tx1},
+	}
+
+	vm := NewDAGVMClient(&testPlugin{vm: testVM})
+	msgChan := make(chan common.Message, 1)
+	if err := vm.Initialize(snow.DefaultContextTest(), memdb.New(), nil, msgChan, nil); err != nil {
+		t.Fatal(err)
+	}
+	defer vm.Shutdown()
+
+	pending := vm.PendingTxs()
+	if len(pending) != 1 {
+		t.Fatalf("Wrong number of pending txs. Expected: 1 ; Returned: %d", len(pending))
+	}
+	tx := pending[0]
+	if !tx.ID().Equals(tx1.ID()) {
+		t.Fatalf("Wrong tx. Expected: %s ; Returned: %s", tx1.ID(), tx.ID())
+	}
+	if !bytes.Equal(tx.Bytes(), tx1.Bytes()) {
+		t.Fatalf("Wrong bytes. Expected: 0x%x ; Returned: 0x%x", tx1.Bytes(), tx.Bytes())
+	}
+	if inputIDs := tx.InputIDs(); !inputIDs.Equals(tx1.InputIDs()) {
+		t.Fatalf("Wrong input IDs. Expected: %s ; Returned: %s", tx1.InputIDs(), inputIDs)
+	}
+
+	deps := tx.Dependencies()
+	if len(deps) != 2 {
+		t.Fatalf("Wrong number of dependencies. Expected: 2 ; Returned: %d", len(deps))
+	}
+	if status := deps[0].Status(); status != choices.Accepted {
+		t.Fatalf("Wrong status. Expected: %s ; Returned: %s", choices.Accepted, status)
+	}
+	if status := deps[1].Status(); status != choices.Unknown {
+		t.Fatalf("Wrong status. Expected: %s ; Returned: %s", choices.Unknown, status)
+	}
+	if err := deps[1].Verify(); err != errMissingTx {
+		t.Fatalf("Should have errored with %s but errored with %v", errMissingTx, err)
+	}
+
+	if err := tx.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	tx.Accept()
+	if status := tx1.Status(); status != choices.Accepted {
+		t.Fatalf("Should have accepted the tx in the plugin's VM")
+	}
+
+	parsedTx, err := vm.ParseTx(tx1.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := parsedTx.Status(); status != choices.Accepted {
+		t.Fatalf("Wrong status. Expected: %s ; Returned: %s", choices.Accepted, status)
+	}
+	if _, err := vm.GetTx(missingTx.ID()); err == nil {
+		t.Fatalf("Should have failed because the tx doesn't exist")
+	}
+}
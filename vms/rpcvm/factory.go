@@ -0,0 +1,81 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcvm
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/rpc"
+)
+
+const (
+	// ProtocolVersion is the version of the protocol the node and its plugins
+	// speak. A plugin that speaks another version isn't loaded.
+	ProtocolVersion uint32 = 1
+)
+
+// Kind is the type of consensus the VM of a plugin runs on
+type Kind uint32
+
+// The kinds of VMs a plugin can serve
+const (
+	SnowmanKind Kind = iota + 1
+	AvalancheKind
+)
+
+func (k Kind) String() string {
+	switch k {
+	case SnowmanKind:
+		return "snowman"
+	case AvalancheKind:
+		return "avalanche"
+	default:
+		return "unknown"
+	}
+}
+
+// Factory creates clients of the VM served by the plugin at [path]. Each VM is
+// served by its own instance of the plugin, which is launched when the VM is
+// initialized.
+type Factory struct {
+	path string
+	kind Kind
+}
+
+// NewFactory launches the plugin at [path] to learn the kind of VM it serves
+func NewFactory(path string) (*Factory, error) {
+	p := &process{path: path}
+	client, err := p.start(rpc.NewServer(), ioutil.Discard)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't start plugin %s: %w", path, err)
+	}
+	reply := KindReply{}
+	err = client.Call("VM.Kind", &struct{}{}, &reply)
+	if stopErr := p.stop(); err == nil {
+		err = stopErr
+	}
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("couldn't get the kind of plugin %s: %w", path, err)
+	case reply.ProtocolVersion != ProtocolVersion:
+		return nil, fmt.Errorf("plugin %s speaks protocol version %d but %d is required",
+			path, reply.ProtocolVersion, ProtocolVersion)
+	}
+	return &Factory{path: path, kind: reply.Kind}, nil
+}
+
+// Kind returns the kind of VM the plugin serves
+func (f *Factory) Kind() Kind { return f.kind }
+
+// New returns a client of a new instance of the plugin's VM. The returned
+// value is a *ChainVMClient or a *DAGVMClient, depending on the plugin's kind.
+func (f *Factory) New() interface{} {
+	p := &process{path: f.path}
+	switch f.kind {
+	case AvalancheKind:
+		return NewDAGVMClient(p)
+	default:
+		return NewChainVMClient(p)
+	}
+}
@@ -0,0 +1,161 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcvm
+
+import (
+	"fmt"
+	"net/rpc"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/rpcdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+// The node serves the services in this file to the plugin, so that the
+// plugin's VM can use the parts of its context that belong to the node.
+
+// messenger passes the messages the plugin's VM sends to its engine on to the
+// engine of the chain. It's registered with the name "Messenger".
+type messenger struct{ toEngine chan<- common.Message }
+
+// Notify sends a message to the engine, unless the engine is busy
+func (m *messenger) Notify(args *NotifyArgs, _ *struct{}) error {
+	select {
+	case m.toEngine <- common.Message(args.Message):
+	default:
+	}
+	return nil
+}
+
+// logServer writes the plugin's logs to the chain's log. It's registered with
+// the name "Log".
+type logServer struct{ log logging.Logger }
+
+// Log writes a formatted message at the given level
+func (s *logServer) Log(args *LogArgs, _ *struct{}) error {
+	switch logging.Level(args.Level) {
+	case logging.Fatal:
+		s.log.Fatal("%s", args.Msg)
+	case logging.Error:
+		s.log.Error("%s", args.Msg)
+	case logging.Warn:
+		s.log.Warn("%s", args.Msg)
+	case logging.Info:
+		s.log.Info("%s", args.Msg)
+	case logging.Debug:
+		s.log.Debug("%s", args.Msg)
+	case logging.Verbo:
+		s.log.Verbo("%s", args.Msg)
+	}
+	return nil
+}
+
+// Write writes a pre-formatted message
+func (s *logServer) Write(args *LogArgs, _ *struct{}) error {
+	_, err := s.log.Write([]byte(args.Msg))
+	return err
+}
+
+// lookupServer resolves the aliases of chains. It's registered with the name
+// "Lookup".
+type lookupServer struct{ lookup snow.AliasLookup }
+
+// Lookup returns the ID of the chain with the alias [args.Alias]
+func (s *lookupServer) Lookup(args *LookupArgs, reply *IDReply) error {
+	id, err := s.lookup.Lookup(args.Alias)
+	if err != nil {
+		return err
+	}
+	reply.ID = id.Key()
+	return nil
+}
+
+// PrimaryAlias returns the primary alias of the chain with ID [args.ID]
+func (s *lookupServer) PrimaryAlias(args *PrimaryAliasArgs, reply *PrimaryAliasReply) error {
+	alias, err := s.lookup.PrimaryAlias(ids.NewID(args.ID))
+	reply.Alias = alias
+	return err
+}
+
+// newHost returns the services the node serves to a plugin
+func newHost(ctx *snow.Context, db database.Database, toEngine chan<- common.Message) (*rpc.Server, error) {
+	host := rpc.NewServer()
+	services := map[string]interface{}{
+		"DB":        rpcdb.NewServer(db),
+		"Messenger": &messenger{toEngine: toEngine},
+		"Log":       &logServer{log: ctx.Log},
+		"Lookup":    &lookupServer{lookup: ctx.BCLookup},
+	}
+	for name, service := range services {
+		if err := host.RegisterName(name, service); err != nil {
+			return nil, err
+		}
+	}
+	return host, nil
+}
+
+// forwardingLog is the log of a plugin's VM. It sends its messages to the
+// node, which writes them to the chain's log.
+type forwardingLog struct {
+	logging.NoLog
+	host *rpc.Client
+}
+
+func (l *forwardingLog) log(level logging.Level, format string, args ...interface{}) {
+	l.host.Call("Log.Log", &LogArgs{
+		Level: uint32(level),
+		Msg:   fmt.Sprintf(format, args...),
+	}, &struct{}{})
+}
+
+func (l *forwardingLog) Write(p []byte) (int, error) {
+	if err := l.host.Call("Log.Write", &LogArgs{Msg: string(p)}, &struct{}{}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (l *forwardingLog) Fatal(format string, args ...interface{}) {
+	l.log(logging.Fatal, format, args...)
+}
+
+func (l *forwardingLog) Error(format string, args ...interface{}) {
+	l.log(logging.Error, format, args...)
+}
+
+func (l *forwardingLog) Warn(format string, args ...interface{}) {
+	l.log(logging.Warn, format, args...)
+}
+
+func (l *forwardingLog) Info(format string, args ...interface{}) {
+	l.log(logging.Info, format, args...)
+}
+
+func (l *forwardingLog) Debug(format string, args ...interface{}) {
+	l.log(logging.Debug, format, args...)
+}
+
+func (l *forwardingLog) Verbo(format string, args ...interface{}) {
+	l.log(logging.Verbo, format, args...)
+}
+
+// lookupClient resolves the aliases of chains through the node
+type lookupClient struct{ host *rpc.Client }
+
+func (l *lookupClient) Lookup(alias string) (ids.ID, error) {
+	reply := IDReply{}
+	if err := l.host.Call("Lookup.Lookup", &LookupArgs{Alias: alias}, &reply); err != nil {
+		return ids.ID{}, err
+	}
+	return ids.NewID(reply.ID), nil
+}
+
+func (l *lookupClient) PrimaryAlias(id ids.ID) (string, error) {
+	reply := PrimaryAliasReply{}
+	err := l.host.Call("Lookup.PrimaryAlias", &PrimaryAliasArgs{ID: id.Key()}, &reply)
+	return reply.Alias, err
+}
@@ -0,0 +1,106 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcvm
+
+import (
+	"net/http"
+)
+
+// IDs are sent as arrays because gob can't encode the pointer inside of an
+// ids.ID or an ids.ShortID.
+
+// KindReply is the reply from VM.Kind
+type KindReply struct {
+	Kind            Kind
+	ProtocolVersion uint32
+}
+
+// InitializeArgs are the arguments to VM.Initialize
+type InitializeArgs struct {
+	NetworkID    uint32
+	ChainID      [32]byte
+	NodeID       [20]byte
+	GenesisBytes []byte
+}
+
+// HandlerInfo describes an HTTP handler of the VM
+type HandlerInfo struct {
+	Extension   string
+	LockOptions uint32
+}
+
+// CreateHandlersReply is the reply from VM.CreateHandlers
+type CreateHandlersReply struct{ Handlers []HandlerInfo }
+
+// HTTPArgs are the arguments to VM.ServeHTTP. They describe an HTTP request
+// to the handler with the extension [Extension].
+type HTTPArgs struct {
+	Extension string
+	Method    string
+	URL       string
+	Header    http.Header
+	Body      []byte
+}
+
+// HTTPReply is the reply from VM.ServeHTTP. It describes the HTTP response.
+type HTTPReply struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// IDArgs are the arguments to calls that operate on a block or a tx
+type IDArgs struct{ ID [32]byte }
+
+// IDReply is the reply from calls that return an ID
+type IDReply struct{ ID [32]byte }
+
+// BytesArgs are the arguments to calls that parse a block or a tx
+type BytesArgs struct{ Bytes []byte }
+
+// StatusReply is the reply from calls that return the status of a block or a
+// tx
+type StatusReply struct{ Status uint32 }
+
+// BlockReply describes a block of a snowman.ChainVM
+type BlockReply struct {
+	ID [32]byte
+
+	// HasParent is false if the block's Parent() is nil
+	HasParent bool
+	ParentID  [32]byte
+
+	Status uint32
+	Bytes  []byte
+}
+
+// TxReply describes a tx of an avalanche.DAGVM
+type TxReply struct {
+	ID            [32]byte
+	Status        uint32
+	Bytes         []byte
+	InputIDs      [][32]byte
+	DependencyIDs [][32]byte
+}
+
+// TxsReply is the reply from VM.PendingTxs
+type TxsReply struct{ Txs []TxReply }
+
+// NotifyArgs are the arguments to Messenger.Notify
+type NotifyArgs struct{ Message uint32 }
+
+// LogArgs are the arguments to Log.Log and Log.Write
+type LogArgs struct {
+	Level uint32
+	Msg   string
+}
+
+// LookupArgs are the arguments to Lookup.Lookup
+type LookupArgs struct{ Alias string }
+
+// PrimaryAliasArgs are the arguments to Lookup.PrimaryAlias
+type PrimaryAliasArgs struct{ ID [32]byte }
+
+// PrimaryAliasReply is the reply from Lookup.PrimaryAlias
+type PrimaryAliasReply struct{ Alias string }
@@ -0,0 +1,181 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcvm
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/rpc"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// AddrEnvKey is the environment variable the node passes the address of
+	// its socket to a plugin in
+	AddrEnvKey = "GECKO_PLUGIN_ADDR"
+
+	// startTimeout is how long a plugin has to connect to the node
+	startTimeout = 10 * time.Second
+
+	// stopTimeout is how long a plugin has to exit after the node hangs up,
+	// before it's killed
+	stopTimeout = 5 * time.Second
+)
+
+var (
+	errNotLaunchedByNode = fmt.Errorf("%s isn't set. Plugins must be launched by a node", AddrEnvKey)
+	errNotStarted        = errors.New("the plugin hasn't been started")
+)
+
+// plugin is an instance of a plugin the node can talk to
+type plugin interface {
+	// start the plugin. The plugin can call the services of [host]. The
+	// plugin's output is written to [log]. Returns a client of the plugin's
+	// VM.
+	start(host *rpc.Server, log io.Writer) (*rpc.Client, error)
+
+	// stop the plugin
+	stop() error
+}
+
+// process is a plugin that runs in a subprocess of the node. The plugin
+// connects to a unix socket of the node twice: the node calls the plugin's VM
+// over the first connection, and the plugin calls the node's services over the
+// second one.
+type process struct {
+	path string
+
+	dir      string
+	cmd      *exec.Cmd
+	vmClient *rpc.Client
+	hostConn net.Conn
+
+	// exited is closed when the plugin exits, with the error [exitErr]
+	exited  chan struct{}
+	exitErr error
+}
+
+func (p *process) start(host *rpc.Server, log io.Writer) (*rpc.Client, error) {
+	dir, err := ioutil.TempDir("", "gecko-plugin")
+	if err != nil {
+		return nil, err
+	}
+	p.dir = dir
+
+	addr := filepath.Join(dir, "plugin.sock")
+	listener, err := net.ListenUnix("unix", &net.UnixAddr{Name: addr, Net: "unix"})
+	if err != nil {
+		p.cleanup()
+		return nil, err
+	}
+	defer listener.Close()
+
+	p.cmd = exec.Command(p.path)
+	p.cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%s", AddrEnvKey, addr))
+	p.cmd.Stdout = &logWriter{log: log}
+	p.cmd.Stderr = &logWriter{log: log}
+	if err := p.cmd.Start(); err != nil {
+		p.cleanup()
+		return nil, err
+	}
+	p.exited = make(chan struct{})
+	go func() {
+		p.exitErr = p.cmd.Wait()
+		close(p.exited)
+	}()
+
+	// Stop waiting for the plugin to connect if it exits
+	connected := make(chan struct{})
+	defer close(connected)
+	go func() {
+		select {
+		case <-p.exited:
+			listener.Close()
+		case <-connected:
+		}
+	}()
+
+	if err := listener.SetDeadline(time.Now().Add(startTimeout)); err != nil {
+		p.kill()
+		return nil, err
+	}
+	vmConn, err := listener.Accept()
+	if err != nil {
+		p.kill()
+		return nil, err
+	}
+	hostConn, err := listener.Accept()
+	if err != nil {
+		vmConn.Close()
+		p.kill()
+		return nil, err
+	}
+	go host.ServeConn(hostConn)
+
+	p.vmClient = rpc.NewClient(vmConn)
+	p.hostConn = hostConn
+	return p.vmClient, nil
+}
+
+// stop hangs up on the plugin, which makes it exit. If it doesn't exit in
+// time, it's killed.
+func (p *process) stop() error {
+	if p.cmd == nil {
+		return errNotStarted
+	}
+	p.vmClient.Close()
+	p.hostConn.Close()
+
+	select {
+	case <-p.exited:
+	case <-time.After(stopTimeout):
+		p.cmd.Process.Kill()
+		<-p.exited
+	}
+	p.cleanup()
+	return p.exitErr
+}
+
+func (p *process) kill() {
+	p.cmd.Process.Kill()
+	<-p.exited
+	p.cleanup()
+}
+
+func (p *process) cleanup() { os.RemoveAll(p.dir) }
+
+// logWriter writes the output of a plugin to [log]. Errors are ignored, so
+// that the plugin can't be killed by a log that can't be written to.
+type logWriter struct{ log io.Writer }
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.log.Write(p)
+	return len(p), nil
+}
+
+// Serve [vm] to the node that launched this process. [vm] must be an
+// avalanche.DAGVM or a snowman.ChainVM. Serve returns when the node hangs up,
+// after which the plugin should exit.
+func Serve(vm interface{}) error {
+	addr := os.Getenv(AddrEnvKey)
+	if addr == "" {
+		return errNotLaunchedByNode
+	}
+	vmConn, err := net.Dial("unix", addr)
+	if err != nil {
+		return err
+	}
+	hostConn, err := net.Dial("unix", addr)
+	if err != nil {
+		vmConn.Close()
+		return err
+	}
+	return serve(vm, vmConn, hostConn)
+}
@@ -0,0 +1,96 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcvm
+
+import (
+	"io"
+	"net"
+	"net/rpc"
+	"os"
+	"testing"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/vms/timestampvm"
+)
+
+// When the node launches this test binary as a plugin, it serves a
+// timestampvm rather than running the tests
+func TestMain(m *testing.M) {
+	if os.Getenv(AddrEnvKey) != "" {
+		if err := Serve(&timestampvm.VM{}); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// testPlugin serves [vm] in this process over in-memory connections
+type testPlugin struct {
+	vm interface{}
+
+	vmClient *rpc.Client
+	hostConn net.Conn
+}
+
+func (p *testPlugin) start(host *rpc.Server, _ io.Writer) (*rpc.Client, error) {
+	vmConn, pluginVMConn := net.Pipe()
+	hostConn, pluginHostConn := net.Pipe()
+	go host.ServeConn(hostConn)
+	go serve(p.vm, pluginVMConn, pluginHostConn)
+
+	p.vmClient = rpc.NewClient(vmConn)
+	p.hostConn = hostConn
+	return p.vmClient, nil
+}
+
+func (p *testPlugin) stop() error {
+	p.vmClient.Close()
+	return p.hostConn.Close()
+}
+
+func TestProcess(t *testing.T) {
+	factory, err := NewFactory(os.Args[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kind := factory.Kind(); kind != SnowmanKind {
+		t.Fatalf("Wrong kind. Expected: %s ; Returned: %s", SnowmanKind, kind)
+	}
+
+	vm, ok := factory.New().(*ChainVMClient)
+	if !ok {
+		t.Fatalf("Should have created a snowman.ChainVM")
+	}
+	msgChan := make(chan common.Message, 1)
+	if err := vm.Initialize(snow.DefaultContextTest(), memdb.New(), []byte{1}, msgChan, nil); err != nil {
+		t.Fatal(err)
+	}
+	if lastAccepted := vm.LastAccepted(); lastAccepted.IsZero() {
+		t.Fatalf("Should have accepted the genesis block")
+	}
+	vm.Shutdown()
+}
+
+func TestNewFactoryNotAPlugin(t *testing.T) {
+	if _, err := NewFactory("/bin/true"); err == nil {
+		t.Fatalf("Should have failed because the executable isn't a plugin")
+	}
+}
+
+func TestServeNotLaunchedByNode(t *testing.T) {
+	if err := Serve(&timestampvm.VM{}); err != errNotLaunchedByNode {
+		t.Fatalf("Should have errored with %s but errored with %v", errNotLaunchedByNode, err)
+	}
+}
+
+func TestServeUnknownVMType(t *testing.T) {
+	vm := NewChainVMClient(&testPlugin{vm: struct{}{}})
+	msgChan := make(chan common.Message, 1)
+	if err := vm.Initialize(snow.DefaultContextTest(), memdb.New(), nil, msgChan, nil); err == nil {
+		t.Fatalf("Should have failed because the plugin doesn't serve a VM")
+	}
+}
@@ -0,0 +1,131 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcvm
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/rpc"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/engine/common"
+)
+
+var (
+	errFxsUnsupported = errors.New("plugins don't support feature extensions")
+)
+
+// vmClient is the part of a VM client that's common to every kind of VM. It
+// implements common.VM by calling the VM of a plugin.
+type vmClient struct {
+	plugin plugin
+	client *rpc.Client
+	ctx    *snow.Context
+}
+
+// Initialize launches the plugin and initializes its VM. The plugin's VM
+// accesses [db], the log of [ctx], and [toEngine] through the node.
+func (vm *vmClient) Initialize(
+	ctx *snow.Context,
+	db database.Database,
+	genesisBytes []byte,
+	toEngine chan<- common.Message,
+	fxs []*common.Fx,
+) error {
+	if len(fxs) != 0 {
+		return errFxsUnsupported
+	}
+
+	host, err := newHost(ctx, db, toEngine)
+	if err != nil {
+		return err
+	}
+	client, err := vm.plugin.start(host, ctx.Log)
+	if err != nil {
+		return err
+	}
+	vm.client = client
+	vm.ctx = ctx
+
+	return vm.client.Call("VM.Initialize", &InitializeArgs{
+		NetworkID:    ctx.NetworkID,
+		ChainID:      ctx.ChainID.Key(),
+		NodeID:       ctx.NodeID.Key(),
+		GenesisBytes: genesisBytes,
+	}, &struct{}{})
+}
+
+// Shutdown the plugin's VM, and then stop the plugin
+func (vm *vmClient) Shutdown() {
+	if vm.client == nil {
+		return
+	}
+	if err := vm.client.Call("VM.Shutdown", &struct{}{}, &struct{}{}); err != nil {
+		vm.ctx.Log.Error("error while shutting down the plugin's vm: %s", err)
+	}
+	if err := vm.plugin.stop(); err != nil {
+		vm.ctx.Log.Debug("plugin exited with: %s", err)
+	}
+}
+
+// CreateHandlers returns handlers that proxy HTTP requests to the handlers of
+// the plugin's VM
+func (vm *vmClient) CreateHandlers() map[string]*common.HTTPHandler {
+	reply := CreateHandlersReply{}
+	if err := vm.client.Call("VM.CreateHandlers", &struct{}{}, &reply); err != nil {
+		vm.ctx.Log.Error("couldn't get the http handlers of the plugin's vm: %s", err)
+		return nil
+	}
+	handlers := make(map[string]*common.HTTPHandler, len(reply.Handlers))
+	for _, handler := range reply.Handlers {
+		handlers[handler.Extension] = &common.HTTPHandler{
+			LockOptions: common.LockOption(handler.LockOptions),
+			Handler: &httpProxy{
+				vm:        vm,
+				extension: handler.Extension,
+			},
+		}
+	}
+	return handlers
+}
+
+// httpProxy sends the requests it serves to the handler of the plugin's VM
+// with the extension [extension]
+type httpProxy struct {
+	vm        *vmClient
+	extension string
+}
+
+func (p *httpProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	reply := HTTPReply{}
+	err = p.vm.client.Call("VM.ServeHTTP", &HTTPArgs{
+		Extension: p.extension,
+		Method:    r.Method,
+		URL:       r.URL.String(),
+		Header:    r.Header,
+		Body:      body,
+	}, &reply)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	for key, values := range reply.Header {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+	w.WriteHeader(reply.StatusCode)
+	if _, err := w.Write(reply.Body); err != nil {
+		p.vm.ctx.Log.Debug("couldn't write the response of the plugin's vm: %s", err)
+	}
+}
@@ -0,0 +1,607 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpcvm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/rpc"
+	"sync"
+
+	"github.com/ava-labs/gecko/database/rpcdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/consensus/snowman"
+	"github.com/ava-labs/gecko/snow/consensus/snowstorm"
+	"github.com/ava-labs/gecko/snow/engine/avalanche"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/snow/triggers"
+
+	smeng "github.com/ava-labs/gecko/snow/engine/snowman"
+)
+
+const (
+	// toEngineSize is the number of messages the plugin's VM can send to its
+	// engine before they're passed on to the node
+	toEngineSize = 1
+)
+
+var (
+	errUnknownVMType      = errors.New("the vm should have type avalanche.DAGVM or snowman.ChainVM")
+	errNotInitialized     = errors.New("the vm hasn't been initialized")
+	errUnknownHandler     = errors.New("unknown http handler")
+	errWrongKind          = errors.New("the vm doesn't support this call")
+	errUnknownLockOptions = errors.New("invalid lock options")
+)
+
+// vmServer serves a VM to the node over net/rpc. It's registered with the name
+// "VM". Blocks and txs are referred to by their IDs, so the server remembers
+// the undecided blocks and txs it handed out until they're decided.
+type vmServer struct {
+	vm   interface{}
+	kind Kind
+	host *rpc.Client
+
+	ctx      *snow.Context
+	handlers map[string]*common.HTTPHandler // protected by [ctx.Lock]
+
+	lock sync.Mutex // protects [blks] and [txs]
+	blks map[[32]byte]snowman.Block
+	txs  map[[32]byte]snowstorm.Tx
+}
+
+func newVMServer(vm interface{}, host *rpc.Client) (*vmServer, error) {
+	s := &vmServer{
+		vm:   vm,
+		host: host,
+		blks: make(map[[32]byte]snowman.Block),
+		txs:  make(map[[32]byte]snowstorm.Tx),
+	}
+	switch vm.(type) {
+	case avalanche.DAGVM:
+		s.kind = AvalancheKind
+	case smeng.ChainVM:
+		s.kind = SnowmanKind
+	default:
+		return nil, errUnknownVMType
+	}
+	return s, nil
+}
+
+// Kind returns the type of consensus the VM runs on
+func (s *vmServer) Kind(_ *struct{}, reply *KindReply) error {
+	reply.Kind = s.kind
+	reply.ProtocolVersion = ProtocolVersion
+	return nil
+}
+
+// Initialize the VM. Its database, log, and chain aliases are provided by the
+// node.
+func (s *vmServer) Initialize(args *InitializeArgs, _ *struct{}) error {
+	log := &forwardingLog{host: s.host}
+	decisionEvents := &triggers.EventDispatcher{}
+	decisionEvents.Initialize(log)
+	consensusEvents := &triggers.EventDispatcher{}
+	consensusEvents.Initialize(log)
+
+	ctx := &snow.Context{
+		NetworkID:           args.NetworkID,
+		ChainID:             ids.NewID(args.ChainID),
+		NodeID:              ids.NewShortID(args.NodeID),
+		Log:                 log,
+		DecisionDispatcher:  decisionEvents,
+		ConsensusDispatcher: consensusEvents,
+		BCLookup:            &lookupClient{host: s.host},
+	}
+
+	toEngine := make(chan common.Message, toEngineSize)
+	go s.forward(toEngine)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	db := rpcdb.NewClient(s.host)
+	if err := s.vm.(common.VM).Initialize(ctx, db, args.GenesisBytes, toEngine, nil); err != nil {
+		return err
+	}
+	s.ctx = ctx
+	return nil
+}
+
+// forward the messages the VM sends to its engine to the node until the node
+// hangs up
+func (s *vmServer) forward(toEngine <-chan common.Message) {
+	for msg := range toEngine {
+		err := s.host.Call("Messenger.Notify", &NotifyArgs{Message: uint32(msg)}, &struct{}{})
+		if err == rpc.ErrShutdown {
+			return
+		}
+	}
+}
+
+// Shutdown the VM
+func (s *vmServer) Shutdown(_ *struct{}, _ *struct{}) error {
+	if s.ctx == nil {
+		return errNotInitialized
+	}
+	s.ctx.Lock.Lock()
+	defer s.ctx.Lock.Unlock()
+
+	s.vm.(common.VM).Shutdown()
+	return nil
+}
+
+// CreateHandlers returns the extensions and lock options of the VM's HTTP
+// handlers. Requests to them are made with ServeHTTP.
+func (s *vmServer) CreateHandlers(_ *struct{}, reply *CreateHandlersReply) error {
+	if s.ctx == nil {
+		return errNotInitialized
+	}
+	s.ctx.Lock.Lock()
+	defer s.ctx.Lock.Unlock()
+
+	s.handlers = s.vm.(common.VM).CreateHandlers()
+	for extension, handler := range s.handlers {
+		reply.Handlers = append(reply.Handlers, HandlerInfo{
+			Extension:   extension,
+			LockOptions: uint32(handler.LockOptions),
+		})
+	}
+	return nil
+}
+
+// ServeHTTP serves an HTTP request with the handler that has the extension
+// [args.Extension]
+func (s *vmServer) ServeHTTP(args *HTTPArgs, reply *HTTPReply) error {
+	if s.ctx == nil {
+		return errNotInitialized
+	}
+	// The handler's own lock options only apply once it's found, so that
+	// handlers that take no lock are served without it
+	s.ctx.Lock.RLock()
+	handler, ok := s.handlers[args.Extension]
+	s.ctx.Lock.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %q", errUnknownHandler, args.Extension)
+	}
+
+	req, err := http.NewRequest(args.Method, args.URL, bytes.NewReader(args.Body))
+	if err != nil {
+		return err
+	}
+	req.Header = args.Header
+
+	switch handler.LockOptions {
+	case common.WriteLock:
+		s.ctx.Lock.Lock()
+		defer s.ctx.Lock.Unlock()
+	case common.ReadLock:
+		s.ctx.Lock.RLock()
+		defer s.ctx.Lock.RUnlock()
+	case common.NoLock:
+	default:
+		return errUnknownLockOptions
+	}
+
+	w := &responseWriter{header: make(http.Header)}
+	handler.Handler.ServeHTTP(w, req)
+
+	reply.StatusCode = w.statusCode
+	if reply.StatusCode == 0 {
+		reply.StatusCode = http.StatusOK
+	}
+	reply.Header = w.header
+	reply.Body = w.body.Bytes()
+	return nil
+}
+
+// BuildBlock builds a block to add to consensus
+func (s *vmServer) BuildBlock(_ *struct{}, reply *BlockReply) error {
+	vm, err := s.chainVM()
+	if err != nil {
+		return err
+	}
+	s.ctx.Lock.Lock()
+	defer s.ctx.Lock.Unlock()
+
+	blk, err := vm.BuildBlock()
+	if err != nil {
+		return err
+	}
+	s.putBlock(blk, reply)
+	return nil
+}
+
+// ParseBlock parses a block from its bytes
+func (s *vmServer) ParseBlock(args *BytesArgs, reply *BlockReply) error {
+	vm, err := s.chainVM()
+	if err != nil {
+		return err
+	}
+	s.ctx.Lock.Lock()
+	defer s.ctx.Lock.Unlock()
+
+	blk, err := vm.ParseBlock(args.Bytes)
+	if err != nil {
+		return err
+	}
+	s.putBlock(blk, reply)
+	return nil
+}
+
+// GetBlock returns the block with ID [args.ID]
+func (s *vmServer) GetBlock(args *IDArgs, reply *BlockReply) error {
+	vm, err := s.chainVM()
+	if err != nil {
+		return err
+	}
+	s.ctx.Lock.Lock()
+	defer s.ctx.Lock.Unlock()
+
+	blk, err := s.getBlock(vm, args.ID)
+	if err != nil {
+		return err
+	}
+	s.putBlock(blk, reply)
+	return nil
+}
+
+// SetPreference sets the preferred block
+func (s *vmServer) SetPreference(args *IDArgs, _ *struct{}) error {
+	vm, err := s.chainVM()
+	if err != nil {
+		return err
+	}
+	s.ctx.Lock.Lock()
+	defer s.ctx.Lock.Unlock()
+
+	vm.SetPreference(ids.NewID(args.ID))
+	return nil
+}
+
+// LastAccepted returns the ID of the last accepted block
+func (s *vmServer) LastAccepted(_ *struct{}, reply *IDReply) error {
+	vm, err := s.chainVM()
+	if err != nil {
+		return err
+	}
+	s.ctx.Lock.Lock()
+	defer s.ctx.Lock.Unlock()
+
+	reply.ID = vm.LastAccepted().Key()
+	return nil
+}
+
+// BlockStatus returns the status of the block with ID [args.ID]
+func (s *vmServer) BlockStatus(args *IDArgs, reply *StatusReply) error {
+	vm, err := s.chainVM()
+	if err != nil {
+		return err
+	}
+	s.ctx.Lock.Lock()
+	defer s.ctx.Lock.Unlock()
+
+	blk, err := s.getBlock(vm, args.ID)
+	if err != nil {
+		return err
+	}
+	reply.Status = uint32(blk.Status())
+	return nil
+}
+
+// BlockVerify verifies the block with ID [args.ID]
+func (s *vmServer) BlockVerify(args *IDArgs, _ *struct{}) error {
+	vm, err := s.chainVM()
+	if err != nil {
+		return err
+	}
+	s.ctx.Lock.Lock()
+	defer s.ctx.Lock.Unlock()
+
+	blk, err := s.getBlock(vm, args.ID)
+	if err != nil {
+		return err
+	}
+	return blk.Verify()
+}
+
+// BlockAccept accepts the block with ID [args.ID]
+func (s *vmServer) BlockAccept(args *IDArgs, _ *struct{}) error {
+	vm, err := s.chainVM()
+	if err != nil {
+		return err
+	}
+	s.ctx.Lock.Lock()
+	defer s.ctx.Lock.Unlock()
+
+	blk, err := s.getBlock(vm, args.ID)
+	if err != nil {
+		return err
+	}
+	blk.Accept()
+	s.removeBlock(args.ID)
+	return nil
+}
+
+// BlockReject rejects the block with ID [args.ID]
+func (s *vmServer) BlockReject(args *IDArgs, _ *struct{}) error {
+	vm, err := s.chainVM()
+	if err != nil {
+		return err
+	}
+	s.ctx.Lock.Lock()
+	defer s.ctx.Lock.Unlock()
+
+	blk, err := s.getBlock(vm, args.ID)
+	if err != nil {
+		return err
+	}
+	blk.Reject()
+	s.removeBlock(args.ID)
+	return nil
+}
+
+// PendingTxs returns the txs that haven't been sent to consensus yet
+func (s *vmServer) PendingTxs(_ *struct{}, reply *TxsReply) error {
+	vm, err := s.dagVM()
+	if err != nil {
+		return err
+	}
+	s.ctx.Lock.Lock()
+	defer s.ctx.Lock.Unlock()
+
+	txs := vm.PendingTxs()
+	reply.Txs = make([]TxReply, len(txs))
+	for i, tx := range txs {
+		s.putTx(tx, &reply.Txs[i])
+	}
+	return nil
+}
+
+// ParseTx parses a tx from its bytes
+func (s *vmServer) ParseTx(args *BytesArgs, reply *TxReply) error {
+	vm, err := s.dagVM()
+	if err != nil {
+		return err
+	}
+	s.ctx.Lock.Lock()
+	defer s.ctx.Lock.Unlock()
+
+	tx, err := vm.ParseTx(args.Bytes)
+	if err != nil {
+		return err
+	}
+	s.putTx(tx, reply)
+	return nil
+}
+
+// GetTx returns the tx with ID [args.ID]
+func (s *vmServer) GetTx(args *IDArgs, reply *TxReply) error {
+	vm, err := s.dagVM()
+	if err != nil {
+		return err
+	}
+	s.ctx.Lock.Lock()
+	defer s.ctx.Lock.Unlock()
+
+	tx, err := s.getTx(vm, args.ID)
+	if err != nil {
+		return err
+	}
+	s.putTx(tx, reply)
+	return nil
+}
+
+// TxStatus returns the status of the tx with ID [args.ID]
+func (s *vmServer) TxStatus(args *IDArgs, reply *StatusReply) error {
+	vm, err := s.dagVM()
+	if err != nil {
+		return err
+	}
+	s.ctx.Lock.Lock()
+	defer s.ctx.Lock.Unlock()
+
+	tx, err := s.getTx(vm, args.ID)
+	if err != nil {
+		return err
+	}
+	reply.Status = uint32(tx.Status())
+	return nil
+}
+
+// TxVerify verifies the tx with ID [args.ID]
+func (s *vmServer) TxVerify(args *IDArgs, _ *struct{}) error {
+	vm, err := s.dagVM()
+	if err != nil {
+		return err
+	}
+	s.ctx.Lock.Lock()
+	defer s.ctx.Lock.Unlock()
+
+	tx, err := s.getTx(vm, args.ID)
+	if err != nil {
+		return err
+	}
+	return tx.Verify()
+}
+
+// TxAccept accepts the tx with ID [args.ID]
+func (s *vmServer) TxAccept(args *IDArgs, _ *struct{}) error {
+	vm, err := s.dagVM()
+	if err != nil {
+		return err
+	}
+	s.ctx.Lock.Lock()
+	defer s.ctx.Lock.Unlock()
+
+	tx, err := s.getTx(vm, args.ID)
+	if err != nil {
+		return err
+	}
+	tx.Accept()
+	s.removeTx(args.ID)
+	return nil
+}
+
+// TxReject rejects the tx with ID [args.ID]
+func (s *vmServer) TxReject(args *IDArgs, _ *struct{}) error {
+	vm, err := s.dagVM()
+	if err != nil {
+		return err
+	}
+	s.ctx.Lock.Lock()
+	defer s.ctx.Lock.Unlock()
+
+	tx, err := s.getTx(vm, args.ID)
+	if err != nil {
+		return err
+	}
+	tx.Reject()
+	s.removeTx(args.ID)
+	return nil
+}
+
+func (s *vmServer) chainVM() (smeng.ChainVM, error) {
+	switch {
+	case s.ctx == nil:
+		return nil, errNotInitialized
+	case s.kind != SnowmanKind:
+		return nil, errWrongKind
+	}
+	return s.vm.(smeng.ChainVM), nil
+}
+
+func (s *vmServer) dagVM() (avalanche.DAGVM, error) {
+	switch {
+	case s.ctx == nil:
+		return nil, errNotInitialized
+	case s.kind != AvalancheKind:
+		return nil, errWrongKind
+	}
+	return s.vm.(avalanche.DAGVM), nil
+}
+
+// getBlock returns the block with ID [id]. Undecided blocks that were handed
+// out may not be retrievable from the VM, so they're looked up first.
+func (s *vmServer) getBlock(vm smeng.ChainVM, id [32]byte) (snowman.Block, error) {
+	s.lock.Lock()
+	blk, ok := s.blks[id]
+	s.lock.Unlock()
+	if ok {
+		return blk, nil
+	}
+	return vm.GetBlock(ids.NewID(id))
+}
+
+// putBlock describes [blk] in [reply], and remembers [blk] until it's decided
+func (s *vmServer) putBlock(blk snowman.Block, reply *BlockReply) {
+	id := blk.ID().Key()
+	status := blk.Status()
+
+	reply.ID = id
+	if parent := blk.Parent(); parent != nil {
+		reply.HasParent = true
+		reply.ParentID = parent.ID().Key()
+	}
+	reply.Status = uint32(status)
+	reply.Bytes = blk.Bytes()
+
+	if !status.Decided() {
+		s.lock.Lock()
+		s.blks[id] = blk
+		s.lock.Unlock()
+	}
+}
+
+func (s *vmServer) removeBlock(id [32]byte) {
+	s.lock.Lock()
+	delete(s.blks, id)
+	s.lock.Unlock()
+}
+
+// getTx returns the tx with ID [id]. Undecided txs that were handed out may not
+// be retrievable from the VM, so they're looked up first.
+func (s *vmServer) getTx(vm avalanche.DAGVM, id [32]byte) (snowstorm.Tx, error) {
+	s.lock.Lock()
+	tx, ok := s.txs[id]
+	s.lock.Unlock()
+	if ok {
+		return tx, nil
+	}
+	return vm.GetTx(ids.NewID(id))
+}
+
+// putTx describes [tx] in [reply], and remembers [tx] until it's decided
+func (s *vmServer) putTx(tx snowstorm.Tx, reply *TxReply) {
+	id := tx.ID().Key()
+	status := tx.Status()
+
+	reply.ID = id
+	reply.Status = uint32(status)
+	reply.Bytes = tx.Bytes()
+	for _, inputID := range tx.InputIDs().List() {
+		reply.InputIDs = append(reply.InputIDs, inputID.Key())
+	}
+	for _, dep := range tx.Dependencies() {
+		reply.DependencyIDs = append(reply.DependencyIDs, dep.ID().Key())
+	}
+
+	if !status.Decided() {
+		s.lock.Lock()
+		s.txs[id] = tx
+		s.lock.Unlock()
+	}
+}
+
+func (s *vmServer) removeTx(id [32]byte) {
+	s.lock.Lock()
+	delete(s.txs, id)
+	s.lock.Unlock()
+}
+
+// responseWriter records the response of an HTTP handler, so it can be sent
+// back to the node
+type responseWriter struct {
+	header     http.Header
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (w *responseWriter) Header() http.Header { return w.header }
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+	return w.body.Write(b)
+}
+
+func (w *responseWriter) WriteHeader(statusCode int) {
+	if w.statusCode == 0 {
+		w.statusCode = statusCode
+	}
+}
+
+// serve [vm] over [vmConn] until the node hangs up. The node's services are
+// used through [hostConn].
+func serve(vm interface{}, vmConn, hostConn io.ReadWriteCloser) error {
+	host := rpc.NewClient(hostConn)
+	defer host.Close()
+
+	server, err := newVMServer(vm, host)
+	if err != nil {
+		vmConn.Close()
+		return err
+	}
+	rpcServer := rpc.NewServer()
+	if err := rpcServer.RegisterName("VM", server); err != nil {
+		vmConn.Close()
+		return err
+	}
+	rpcServer.ServeConn(vmConn)
+	return nil
+}
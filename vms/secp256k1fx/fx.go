@@ -8,6 +8,7 @@ import (
 
 	"github.com/ava-labs/gecko/utils/crypto"
 	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/math"
 	"github.com/ava-labs/gecko/vms/components/verify"
 )
 
@@ -24,6 +25,7 @@ var (
 	errWrongNumberOfCredentials = errors.New("wrong number of credentials for an operation")
 
 	errWrongMintCreated = errors.New("wrong mint output created from the operation")
+	errNothingBurnt     = errors.New("operation doesn't burn any value")
 
 	errWrongAmounts                   = errors.New("input is consuming a different amount than expected")
 	errTimelocked                     = errors.New("output is time locked")
@@ -31,6 +33,7 @@ var (
 	errTooFewSigners                  = errors.New("input has less signers than expected")
 	errInputCredentialSignersMismatch = errors.New("input expected a different number of signers than provided in the credential")
 	errWrongSigner                    = errors.New("credential does not produce expected signer")
+	errNotRelocked                    = errors.New("locked value of the vesting output isn't locked again by the transaction")
 )
 
 // Fx ...
@@ -41,29 +44,51 @@ type Fx struct {
 
 // Initialize ...
 func (fx *Fx) Initialize(vmIntf interface{}) error {
-	vm, ok := vmIntf.(VM)
-	if !ok {
-		return errWrongVMType
+	if err := fx.InitializeVM(vmIntf); err != nil {
+		return err
 	}
 
-	c := vm.Codec()
+	c := fx.vm.Codec()
 	c.RegisterType(&MintOutput{})
 	c.RegisterType(&TransferOutput{})
 	c.RegisterType(&MintInput{})
 	c.RegisterType(&TransferInput{})
 	c.RegisterType(&Credential{})
+	c.RegisterType(&VestingOutput{})
+	return nil
+}
 
+// InitializeVM sets the VM this fx is running under, without registering the
+// fx's types. Fxs that build on this one use it to verify signatures.
+func (fx *Fx) InitializeVM(vmIntf interface{}) error {
+	vm, ok := vmIntf.(VM)
+	if !ok {
+		return errWrongVMType
+	}
 	fx.vm = vm
 	return nil
 }
 
-// VerifyOperation ...
+// VerifyOperation verifies either a mint or a burn operation.
+//
+// A mint operation consumes a MintOutput with a MintInput. It must create the
+// same MintOutput again, and a TransferOutput with the minted value.
+//
+// A burn operation consumes TransferOutputs with TransferInputs. It may create
+// TransferOutputs with the change, but they must be worth less than what's
+// consumed. The difference is burnt.
 func (fx *Fx) VerifyOperation(txIntf interface{}, utxosIntf, insIntf, credsIntf, outsIntf []interface{}) error {
 	tx, ok := txIntf.(Tx)
 	if !ok {
 		return errWrongTxType
 	}
 
+	if len(insIntf) > 0 {
+		if _, ok := insIntf[0].(*TransferInput); ok {
+			return fx.verifyBurn(tx, utxosIntf, insIntf, credsIntf, outsIntf)
+		}
+	}
+
 	if len(outsIntf) != 2 {
 		return errWrongNumberOfOutputs
 	}
@@ -107,7 +132,61 @@ func (fx *Fx) verifyOperation(tx Tx, utxo *MintOutput, in *MintInput, cred *Cred
 		return errWrongMintCreated
 	}
 
-	return fx.verifyCredentials(tx, &utxo.OutputOwners, &in.Input, cred)
+	return fx.VerifyCredentials(tx, &utxo.OutputOwners, &in.Input, cred)
+}
+
+func (fx *Fx) verifyBurn(tx Tx, utxosIntf, insIntf, credsIntf, outsIntf []interface{}) error {
+	if len(utxosIntf) != len(insIntf) {
+		return errWrongNumberOfInputs
+	}
+	if len(credsIntf) != len(insIntf) {
+		return errWrongNumberOfCredentials
+	}
+
+	consumed := uint64(0)
+	for i, inIntf := range insIntf {
+		utxo, ok := utxosIntf[i].(*TransferOutput)
+		if !ok {
+			return errWrongUTXOType
+		}
+		in, ok := inIntf.(*TransferInput)
+		if !ok {
+			return errWrongInputType
+		}
+		cred, ok := credsIntf[i].(*Credential)
+		if !ok {
+			return errWrongCredentialType
+		}
+		if err := fx.verifyTransfer(tx, utxo, in, cred); err != nil {
+			return err
+		}
+		var err error
+		consumed, err = math.Add64(consumed, in.Amt)
+		if err != nil {
+			return err
+		}
+	}
+
+	produced := uint64(0)
+	for _, outIntf := range outsIntf {
+		out, ok := outIntf.(*TransferOutput)
+		if !ok {
+			return errWrongOutputType
+		}
+		if err := out.Verify(); err != nil {
+			return err
+		}
+		var err error
+		produced, err = math.Add64(produced, out.Amt)
+		if err != nil {
+			return err
+		}
+	}
+
+	if produced >= consumed {
+		return errNothingBurnt
+	}
+	return nil
 }
 
 // VerifyTransfer ...
@@ -116,10 +195,6 @@ func (fx *Fx) VerifyTransfer(txIntf, utxoIntf, inIntf, credIntf interface{}) err
 	if !ok {
 		return errWrongTxType
 	}
-	utxo, ok := utxoIntf.(*TransferOutput)
-	if !ok {
-		return errWrongUTXOType
-	}
 	in, ok := inIntf.(*TransferInput)
 	if !ok {
 		return errWrongInputType
@@ -128,7 +203,14 @@ func (fx *Fx) VerifyTransfer(txIntf, utxoIntf, inIntf, credIntf interface{}) err
 	if !ok {
 		return errWrongCredentialType
 	}
-	return fx.verifyTransfer(tx, utxo, in, cred)
+	switch utxo := utxoIntf.(type) {
+	case *TransferOutput:
+		return fx.verifyTransfer(tx, utxo, in, cred)
+	case *VestingOutput:
+		return fx.verifyVestingTransfer(tx, utxo, in, cred)
+	default:
+		return errWrongUTXOType
+	}
 }
 
 func (fx *Fx) verifyTransfer(tx Tx, utxo *TransferOutput, in *TransferInput, cred *Credential) error {
@@ -144,10 +226,43 @@ func (fx *Fx) verifyTransfer(tx Tx, utxo *TransferOutput, in *TransferInput, cre
 		return errTimelocked
 	}
 
-	return fx.verifyCredentials(tx, &utxo.OutputOwners, &in.Input, cred)
+	return fx.VerifyCredentials(tx, &utxo.OutputOwners, &in.Input, cred)
+}
+
+// verifyVestingTransfer verifies that [tx] can consume the vesting output
+// [utxo]. The value of [utxo] that's still locked must be sent to an output of
+// [tx] that unlocks it no faster.
+func (fx *Fx) verifyVestingTransfer(tx Tx, utxo *VestingOutput, in *TransferInput, cred *Credential) error {
+	if err := verify.All(utxo, in, cred); err != nil {
+		return err
+	}
+	if utxo.Amt != in.Amt {
+		return errWrongAmounts
+	}
+	if err := fx.VerifyCredentials(tx, &utxo.OutputOwners, &in.Input, cred); err != nil {
+		return err
+	}
+
+	time := fx.vm.Clock().Unix()
+	if utxo.Locked(time) == 0 {
+		return nil
+	}
+	transferTx, ok := tx.(TransferTx)
+	if !ok {
+		return errTimelocked
+	}
+	for i, outIntf := range transferTx.Outputs() {
+		out, ok := outIntf.(*VestingOutput)
+		if ok && utxo.Relocks(out, time) && transferTx.Claim(i) {
+			return nil
+		}
+	}
+	return errNotRelocked
 }
 
-func (fx *Fx) verifyCredentials(tx Tx, out *OutputOwners, in *Input, cred *Credential) error {
+// VerifyCredentials returns nil if [cred] holds the signatures of [out]'s
+// owners that [in] points to, over the bytes of [tx]
+func (fx *Fx) VerifyCredentials(tx Tx, out *OutputOwners, in *Input, cred *Credential) error {
 	numSigs := len(in.SigIndices)
 	switch {
 	case out.Threshold < uint32(numSigs):
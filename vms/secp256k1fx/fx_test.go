@@ -48,6 +48,22 @@ type testTx struct{ bytes []byte }
 
 func (tx *testTx) UnsignedBytes() []byte { return tx.bytes }
 
+type testTransferTx struct {
+	testTx
+	outs    []interface{}
+	claimed map[int]bool
+}
+
+func (tx *testTransferTx) Outputs() []interface{} { return tx.outs }
+
+func (tx *testTransferTx) Claim(index int) bool {
+	if tx.claimed[index] {
+		return false
+	}
+	tx.claimed[index] = true
+	return true
+}
+
 func TestFxInitialize(t *testing.T) {
 	vm := testVM{}
 	fx := Fx{}
@@ -353,6 +369,76 @@ func TestFxVerifyTransferTimelocked(t *testing.T) {
 	}
 }
 
+func TestFxVerifyTransferVesting(t *testing.T) {
+	vm := testVM{}
+	date := time.Date(2019, time.January, 19, 16, 25, 17, 3, time.UTC)
+	vm.clock.Set(date)
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	now := uint64(date.Unix())
+	owners := OutputOwners{
+		Threshold: 1,
+		Addrs: []ids.ShortID{
+			ids.NewShortID(addrBytes),
+		},
+	}
+	// Half of the output is unlocked
+	out := &VestingOutput{
+		Amt:          100,
+		VestingStart: now - 50,
+		VestingEnd:   now + 50,
+		OutputOwners: owners,
+	}
+	in := &TransferInput{
+		Amt: 100,
+		Input: Input{
+			SigIndices: []uint32{0},
+		},
+	}
+	cred := &Credential{
+		Sigs: [][crypto.SECP256K1RSigLen]byte{
+			sigBytes,
+		},
+	}
+
+	if err := fx.VerifyTransfer(&testTx{bytes: txBytes}, out, in, cred); err != errTimelocked {
+		t.Fatalf("Should have errored with %s but errored with %v", errTimelocked, err)
+	}
+
+	tx := &testTransferTx{
+		testTx: testTx{bytes: txBytes},
+		outs: []interface{}{
+			&TransferOutput{Amt: 50, OutputOwners: owners},
+			&VestingOutput{Amt: 49, VestingStart: now, VestingEnd: now + 50, OutputOwners: owners},
+		},
+		claimed: make(map[int]bool),
+	}
+	if err := fx.VerifyTransfer(tx, out, in, cred); err != errNotRelocked {
+		t.Fatalf("Should have errored with %s but errored with %v", errNotRelocked, err)
+	}
+
+	tx.outs = append(tx.outs, &VestingOutput{Amt: 50, VestingStart: now, VestingEnd: now + 50, OutputOwners: owners})
+	if err := fx.VerifyTransfer(tx, out, in, cred); err != nil {
+		t.Fatal(err)
+	}
+	if !tx.claimed[2] {
+		t.Fatalf("Should have claimed the output that locks the funds again")
+	}
+
+	// The output was already claimed by the first spend
+	if err := fx.VerifyTransfer(tx, out, in, cred); err != errNotRelocked {
+		t.Fatalf("Should have errored with %s but errored with %v", errNotRelocked, err)
+	}
+
+	// Once vesting ends, the output can be spent without locking anything again
+	vm.clock.Set(date.Add(50 * time.Second))
+	if err := fx.VerifyTransfer(&testTx{bytes: txBytes}, out, in, cred); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestFxVerifyTransferTooManySigners(t *testing.T) {
 	vm := testVM{}
 	date := time.Date(2019, time.January, 19, 16, 25, 17, 3, time.UTC)
@@ -1213,3 +1299,129 @@ func TestFxVerifyOperationMismatchedMintOutput(t *testing.T) {
 		t.Fatalf("Should have errored due to a mismatched mint output")
 	}
 }
+
+func TestFxVerifyBurnOperation(t *testing.T) {
+	vm := testVM{}
+	date := time.Date(2019, time.January, 19, 16, 25, 17, 3, time.UTC)
+	vm.clock.Set(date)
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	tx := &testTx{
+		bytes: txBytes,
+	}
+	owners := OutputOwners{
+		Threshold: 1,
+		Addrs: []ids.ShortID{
+			ids.NewShortID(addrBytes),
+		},
+	}
+	utxo := &TransferOutput{
+		Amt:          2,
+		OutputOwners: owners,
+	}
+	in := &TransferInput{
+		Amt: 2,
+		Input: Input{
+			SigIndices: []uint32{0},
+		},
+	}
+	cred := &Credential{
+		Sigs: [][crypto.SECP256K1RSigLen]byte{
+			sigBytes,
+		},
+	}
+	change := &TransferOutput{
+		Amt:          1,
+		OutputOwners: owners,
+	}
+
+	utxos := []interface{}{utxo}
+	ins := []interface{}{in}
+	creds := []interface{}{cred}
+	if err := fx.VerifyOperation(tx, utxos, ins, creds, []interface{}{change}); err != nil {
+		t.Fatal(err)
+	}
+	if err := fx.VerifyOperation(tx, utxos, ins, creds, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	change.Amt = 2
+	if err := fx.VerifyOperation(tx, utxos, ins, creds, []interface{}{change}); err != errNothingBurnt {
+		t.Fatalf("Should have errored with %s but errored with %v", errNothingBurnt, err)
+	}
+}
+
+func TestFxVerifyBurnOperationWrongUTXOType(t *testing.T) {
+	vm := testVM{}
+	date := time.Date(2019, time.January, 19, 16, 25, 17, 3, time.UTC)
+	vm.clock.Set(date)
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	tx := &testTx{
+		bytes: txBytes,
+	}
+	utxo := &VestingOutput{
+		Amt:          2,
+		VestingStart: 0,
+		VestingEnd:   1,
+		OutputOwners: OutputOwners{
+			Threshold: 1,
+			Addrs: []ids.ShortID{
+				ids.NewShortID(addrBytes),
+			},
+		},
+	}
+	in := &TransferInput{
+		Amt: 2,
+		Input: Input{
+			SigIndices: []uint32{0},
+		},
+	}
+	cred := &Credential{
+		Sigs: [][crypto.SECP256K1RSigLen]byte{
+			sigBytes,
+		},
+	}
+
+	err := fx.VerifyOperation(tx, []interface{}{utxo}, []interface{}{in}, []interface{}{cred}, nil)
+	if err != errWrongUTXOType {
+		t.Fatalf("Should have errored with %s but errored with %v", errWrongUTXOType, err)
+	}
+}
+
+func TestFxVerifyBurnOperationWrongNumberOfCredentials(t *testing.T) {
+	vm := testVM{}
+	date := time.Date(2019, time.January, 19, 16, 25, 17, 3, time.UTC)
+	vm.clock.Set(date)
+	fx := Fx{}
+	if err := fx.Initialize(&vm); err != nil {
+		t.Fatal(err)
+	}
+	tx := &testTx{
+		bytes: txBytes,
+	}
+	utxo := &TransferOutput{
+		Amt: 2,
+		OutputOwners: OutputOwners{
+			Threshold: 1,
+			Addrs: []ids.ShortID{
+				ids.NewShortID(addrBytes),
+			},
+		},
+	}
+	in := &TransferInput{
+		Amt: 2,
+		Input: Input{
+			SigIndices: []uint32{0},
+		},
+	}
+
+	err := fx.VerifyOperation(tx, []interface{}{utxo}, []interface{}{in}, nil, nil)
+	if err != errWrongNumberOfCredentials {
+		t.Fatalf("Should have errored with %s but errored with %v", errWrongNumberOfCredentials, err)
+	}
+}
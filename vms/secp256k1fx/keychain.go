@@ -73,29 +73,59 @@ func (kc *Keychain) New() (*crypto.PrivateKeySECP256K1R, error) {
 
 // Spend attempts to create an input
 func (kc *Keychain) Spend(out verify.Verifiable, time uint64) (verify.Verifiable, []*crypto.PrivateKeySECP256K1R, error) {
+	keys := []*crypto.PrivateKeySECP256K1R(nil)
+	in, err := Spend(out, time, func(owners *OutputOwners) ([]uint32, bool) {
+		sigIndices, matched, able := kc.Match(owners)
+		keys = matched
+		return sigIndices, able
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return in, keys, nil
+}
+
+// Spend attempts to create an input that consumes [out] at [time]. [match]
+// returns the indices of the owners' addresses that will sign the input, and
+// whether they meet the owners' threshold.
+func Spend(out verify.Verifiable, time uint64, match func(*OutputOwners) ([]uint32, bool)) (verify.Verifiable, error) {
 	switch out := out.(type) {
 	case *MintOutput:
-		if sigIndices, keys, able := kc.Match(&out.OutputOwners); able {
+		if sigIndices, able := match(&out.OutputOwners); able {
 			return &MintInput{
 				Input: Input{
 					SigIndices: sigIndices,
 				},
-			}, keys, nil
+			}, nil
 		}
 	case *TransferOutput:
 		if time < out.Locktime {
-			return nil, nil, errLockedFunds
+			return nil, errLockedFunds
+		}
+		if sigIndices, able := match(&out.OutputOwners); able {
+			return &TransferInput{
+				Amt: out.Amt,
+				Input: Input{
+					SigIndices: sigIndices,
+				},
+			}, nil
+		}
+	case *VestingOutput:
+		// The output is consumed in full, so it's only worth spending once some
+		// of it is unlocked
+		if out.Unlocked(time) == 0 {
+			return nil, errLockedFunds
 		}
-		if sigIndices, keys, able := kc.Match(&out.OutputOwners); able {
+		if sigIndices, able := match(&out.OutputOwners); able {
 			return &TransferInput{
 				Amt: out.Amt,
 				Input: Input{
 					SigIndices: sigIndices,
 				},
-			}, keys, nil
+			}, nil
 		}
 	}
-	return nil, nil, errCantSpend
+	return nil, errCantSpend
 }
 
 // Match attempts to match a list of addresses up to the provided threshold
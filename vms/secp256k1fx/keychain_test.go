@@ -298,6 +298,42 @@ func TestKeychainSpendTransfer(t *testing.T) {
 	}
 }
 
+func TestKeychainSpendVesting(t *testing.T) {
+	kc := NewKeychain()
+	sk, err := kc.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vesting := VestingOutput{
+		Amt:          12345,
+		VestingStart: 100,
+		VestingEnd:   200,
+		OutputOwners: OutputOwners{
+			Threshold: 1,
+			Addrs: []ids.ShortID{
+				sk.PublicKey().Address(),
+			},
+		},
+	}
+	if err := vesting.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := kc.Spend(&vesting, 100); err == nil {
+		t.Fatalf("Shouldn't have been able to spend funds that haven't started vesting")
+	}
+
+	// The whole output is consumed, even though only part of it is unlocked
+	if input, _, err := kc.Spend(&vesting, 150); err != nil {
+		t.Fatal(err)
+	} else if input, ok := input.(*TransferInput); !ok {
+		t.Fatalf("Wrong input type returned")
+	} else if amt := input.Amount(); amt != 12345 {
+		t.Fatalf("Wrong amount returned from input")
+	}
+}
+
 func TestKeychainString(t *testing.T) {
 	kc := NewKeychain()
 
@@ -22,6 +22,14 @@ type TransferOutput struct {
 // Amount returns the quantity of the asset this output consumes
 func (out *TransferOutput) Amount() uint64 { return out.Amt }
 
+// Locked returns how much of the value is still locked at [time]
+func (out *TransferOutput) Locked(time uint64) uint64 {
+	if time < out.Locktime {
+		return out.Amt
+	}
+	return 0
+}
+
 // Verify ...
 func (out *TransferOutput) Verify() error {
 	switch {
@@ -7,3 +7,19 @@ package secp256k1fx
 type Tx interface {
 	UnsignedBytes() []byte
 }
+
+// TransferTx is a Tx that exposes the outputs it creates of the asset being
+// transferred. The locked part of a VestingOutput can only be spent by a
+// TransferTx that sends it to a new VestingOutput.
+type TransferTx interface {
+	Tx
+
+	// Outputs returns the outputs the transaction creates of the asset
+	Outputs() []interface{}
+
+	// Claim the output at [index] as the one that locks again what was still
+	// locked of a consumed VestingOutput. Returns false if the output was
+	// already claimed, so that it can't lock again the value of several
+	// consumed outputs.
+	Claim(index int) bool
+}
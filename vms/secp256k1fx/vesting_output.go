@@ -0,0 +1,82 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package secp256k1fx
+
+import (
+	"errors"
+	"math/bits"
+)
+
+var (
+	errInvalidVestingPeriod = errors.New("vesting period must end after it starts")
+)
+
+// VestingOutput is an output whose value unlocks linearly over time. None of
+// the value is unlocked until [VestingStart], and all of it is unlocked from
+// [VestingEnd].
+//
+// The output is always consumed in full. The part of the value that's still
+// locked when the output is consumed must be sent to a new VestingOutput that
+// unlocks no faster than this one.
+type VestingOutput struct {
+	Amt          uint64 `serialize:"true"`
+	VestingStart uint64 `serialize:"true"`
+	VestingEnd   uint64 `serialize:"true"`
+	OutputOwners `serialize:"true"`
+}
+
+// Amount returns the quantity of the asset this output consumes
+func (out *VestingOutput) Amount() uint64 { return out.Amt }
+
+// Locked returns how much of the value is still locked at [time]
+func (out *VestingOutput) Locked(time uint64) uint64 {
+	switch {
+	case time <= out.VestingStart:
+		return out.Amt
+	case time >= out.VestingEnd:
+		return 0
+	}
+	// Amt * elapsed / period is less than Amt, so the quotient fits in 64 bits
+	hi, lo := bits.Mul64(out.Amt, time-out.VestingStart)
+	unlocked, _ := bits.Div64(hi, lo, out.VestingEnd-out.VestingStart)
+	return out.Amt - unlocked
+}
+
+// Unlocked returns how much of the value is unlocked at [time]
+func (out *VestingOutput) Unlocked(time uint64) uint64 { return out.Amt - out.Locked(time) }
+
+// Relocks returns true if [other] locks at least [out.Locked(time)] at [time],
+// and at no later time unlocks value faster than [out] would have
+func (out *VestingOutput) Relocks(other *VestingOutput, time uint64) bool {
+	switch {
+	case !out.OutputOwners.Equals(&other.OutputOwners):
+		return false
+	case out.VestingEnd != other.VestingEnd:
+		return false
+	case other.Amt < out.Locked(time):
+		return false
+	case other.VestingStart >= other.VestingEnd:
+		return false
+	}
+	// Both outputs are fully unlocked at VestingEnd, so once vesting starts,
+	// [other] has at least as much locked as [out] if:
+	// other.Amt / (end - other.VestingStart) >= out.Amt / (end - out.VestingStart)
+	otherHi, otherLo := bits.Mul64(other.Amt, out.VestingEnd-out.VestingStart)
+	outHi, outLo := bits.Mul64(out.Amt, out.VestingEnd-other.VestingStart)
+	return otherHi > outHi || (otherHi == outHi && otherLo >= outLo)
+}
+
+// Verify ...
+func (out *VestingOutput) Verify() error {
+	switch {
+	case out == nil:
+		return errNilOutput
+	case out.Amt == 0:
+		return errNoValueOutput
+	case out.VestingStart >= out.VestingEnd:
+		return errInvalidVestingPeriod
+	default:
+		return out.OutputOwners.Verify()
+	}
+}
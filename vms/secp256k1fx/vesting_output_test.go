@@ -0,0 +1,140 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package secp256k1fx
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestVestingOutputLocked(t *testing.T) {
+	out := VestingOutput{
+		Amt:          1000,
+		VestingStart: 100,
+		VestingEnd:   200,
+		OutputOwners: OutputOwners{
+			Threshold: 1,
+			Addrs: []ids.ShortID{
+				ids.ShortEmpty,
+			},
+		},
+	}
+	if err := out.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	for time, expected := range map[uint64]uint64{
+		0:   1000,
+		100: 1000,
+		101: 990,
+		150: 500,
+		199: 10,
+		200: 0,
+		300: 0,
+	} {
+		if locked := out.Locked(time); locked != expected {
+			t.Fatalf("Wrong amount locked at %d. Expected: %d ; Returned: %d", time, expected, locked)
+		}
+		if unlocked := out.Unlocked(time); unlocked != out.Amt-expected {
+			t.Fatalf("Wrong amount unlocked at %d. Expected: %d ; Returned: %d", time, out.Amt-expected, unlocked)
+		}
+	}
+}
+
+func TestVestingOutputLockedOverflow(t *testing.T) {
+	out := VestingOutput{
+		Amt:          ^uint64(0),
+		VestingStart: 0,
+		VestingEnd:   ^uint64(0),
+	}
+	if locked := out.Locked(1); locked != out.Amt-1 {
+		t.Fatalf("Wrong amount locked. Expected: %d ; Returned: %d", out.Amt-1, locked)
+	}
+}
+
+func TestVestingOutputVerifyInvalidPeriod(t *testing.T) {
+	out := VestingOutput{
+		Amt:          1,
+		VestingStart: 2,
+		VestingEnd:   2,
+		OutputOwners: OutputOwners{
+			Threshold: 1,
+			Addrs: []ids.ShortID{
+				ids.ShortEmpty,
+			},
+		},
+	}
+	if err := out.Verify(); err != errInvalidVestingPeriod {
+		t.Fatalf("Should have errored with %s but errored with %v", errInvalidVestingPeriod, err)
+	}
+}
+
+func TestVestingOutputVerifyNil(t *testing.T) {
+	out := (*VestingOutput)(nil)
+	if err := out.Verify(); err == nil {
+		t.Fatalf("Should have errored with a nil output")
+	}
+}
+
+func TestVestingOutputRelocks(t *testing.T) {
+	owners := OutputOwners{
+		Threshold: 1,
+		Addrs: []ids.ShortID{
+			ids.ShortEmpty,
+		},
+	}
+	out := &VestingOutput{
+		Amt:          1000,
+		VestingStart: 100,
+		VestingEnd:   200,
+		OutputOwners: owners,
+	}
+
+	tests := []struct {
+		description string
+		relock      *VestingOutput
+		relocks     bool
+	}{
+		{
+			description: "what's locked, at the same rate",
+			relock:      &VestingOutput{Amt: 500, VestingStart: 150, VestingEnd: 200, OutputOwners: owners},
+			relocks:     true,
+		},
+		{
+			description: "less than what's locked",
+			relock:      &VestingOutput{Amt: 499, VestingStart: 150, VestingEnd: 200, OutputOwners: owners},
+			relocks:     false,
+		},
+		{
+			description: "what's locked, at a faster rate",
+			relock:      &VestingOutput{Amt: 500, VestingStart: 100, VestingEnd: 200, OutputOwners: owners},
+			relocks:     false,
+		},
+		{
+			description: "what's locked, until later",
+			relock:      &VestingOutput{Amt: 500, VestingStart: 150, VestingEnd: 201, OutputOwners: owners},
+			relocks:     false,
+		},
+		{
+			description: "what's locked, to other owners",
+			relock:      &VestingOutput{Amt: 500, VestingStart: 150, VestingEnd: 200},
+			relocks:     false,
+		},
+		{
+			description: "what's locked, starting later",
+			relock:      &VestingOutput{Amt: 500, VestingStart: 160, VestingEnd: 200, OutputOwners: owners},
+			relocks:     true,
+		},
+		{
+			description: "everything, starting earlier",
+			relock:      &VestingOutput{Amt: 2000, VestingStart: 0, VestingEnd: 200, OutputOwners: owners},
+			relocks:     true,
+		},
+	}
+	for _, test := range tests {
+		if relocks := out.Relocks(test.relock, 150); relocks != test.relocks {
+			t.Fatalf("Relocking %s should have returned %v", test.description, test.relocks)
+		}
+	}
+}
@@ -37,8 +37,8 @@ func (a Account) Balance() uint64 { return a.balance }
 func (a Account) Nonce() uint64 { return a.nonce }
 
 // CreateTx creates a transaction from this account
-// that sends [amount] to the address [destination]
-func (a Account) CreateTx(amount uint64, destination ids.ShortID, ctx *snow.Context, key *crypto.PrivateKeySECP256K1R) (*Tx, Account, error) {
+// that sends [amount] to the address [destination] and burns [txFee]
+func (a Account) CreateTx(amount, txFee uint64, destination ids.ShortID, ctx *snow.Context, key *crypto.PrivateKeySECP256K1R) (*Tx, Account, error) {
 	builder := Builder{
 		NetworkID: ctx.NetworkID,
 		ChainID:   ctx.ChainID,
@@ -48,32 +48,35 @@ func (a Account) CreateTx(amount uint64, destination ids.ShortID, ctx *snow.Cont
 	if err != nil {
 		return nil, a, err
 	}
-	newAccount, err := a.Send(tx, ctx)
+	newAccount, err := a.Send(tx, txFee, ctx)
 	return tx, newAccount, err
 }
 
-// Send generates a new account state from sending the transaction
-func (a Account) Send(tx *Tx, ctx *snow.Context) (Account, error) {
-	return a.send(tx, ctx, &crypto.FactorySECP256K1R{})
+// Send generates a new account state from sending the transaction and burning
+// [txFee]
+func (a Account) Send(tx *Tx, txFee uint64, ctx *snow.Context) (Account, error) {
+	return a.send(tx, txFee, ctx, &crypto.FactorySECP256K1R{})
 }
 
-// send generates the new account state from sending the transaction
-func (a Account) send(tx *Tx, ctx *snow.Context, factory *crypto.FactorySECP256K1R) (Account, error) {
+// send generates the new account state from sending the transaction and
+// burning [txFee]
+func (a Account) send(tx *Tx, txFee uint64, ctx *snow.Context, factory *crypto.FactorySECP256K1R) (Account, error) {
 	return Account{
 		id: a.id,
 		// guaranteed not to overflow due to VerifySend
 		nonce: a.nonce + 1,
 		// guaranteed not to underflow due to VerifySend
-		balance: a.balance - tx.amount,
-	}, a.verifySend(tx, ctx, factory)
+		balance: a.balance - tx.amount - txFee,
+	}, a.verifySend(tx, txFee, ctx, factory)
 }
 
-// VerifySend returns if the provided transaction can send this transaction
-func (a Account) VerifySend(tx *Tx, ctx *snow.Context) error {
-	return a.verifySend(tx, ctx, &crypto.FactorySECP256K1R{})
+// VerifySend returns if the provided transaction can send this transaction,
+// and burn [txFee]
+func (a Account) VerifySend(tx *Tx, txFee uint64, ctx *snow.Context) error {
+	return a.verifySend(tx, txFee, ctx, &crypto.FactorySECP256K1R{})
 }
 
-func (a Account) verifySend(tx *Tx, ctx *snow.Context, factory *crypto.FactorySECP256K1R) error {
+func (a Account) verifySend(tx *Tx, txFee uint64, ctx *snow.Context, factory *crypto.FactorySECP256K1R) error {
 	// Verify the account is in a valid state and the transaction is valid
 	if err := a.Verify(); err != nil {
 		return err
@@ -88,9 +91,11 @@ func (a Account) verifySend(tx *Tx, ctx *snow.Context, factory *crypto.FactorySE
 		return errOutOfSpends
 	case a.nonce+1 != tx.nonce:
 		return fmt.Errorf("wrong tx nonce used, %d != %d", a.nonce+1, tx.nonce)
-	case a.balance < tx.amount:
-		return fmt.Errorf("%s %d < %d", errInsufficientFunds, a.balance, tx.amount)
-	case a.nonce+1 == math.MaxUint64 && a.balance != tx.amount:
+	case tx.amount > math.MaxUint64-txFee:
+		return errOverflow
+	case a.balance < tx.amount+txFee:
+		return fmt.Errorf("%s %d < %d + %d", errInsufficientFunds, a.balance, tx.amount, txFee)
+	case a.nonce+1 == math.MaxUint64 && a.balance != tx.amount+txFee:
 		return errOutOfSpends
 	case !a.id.Equals(tx.key(ctx, factory).Address()):
 		return errInvalidAddress
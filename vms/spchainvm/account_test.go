@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
 )
 
 func TestAccountSerialization(t *testing.T) {
@@ -34,3 +35,24 @@ func TestAccountSerialization(t *testing.T) {
 		t.Fatalf("Expected %s got %s", account, newAccount)
 	}
 }
+
+func TestAccountSendFee(t *testing.T) {
+	ctx := snow.DefaultContextTest()
+	builder := Builder{
+		NetworkID: ctx.NetworkID,
+		ChainID:   ctx.ChainID,
+	}
+	account := builder.NewAccount(keys[0].PublicKey().Address(), 0, 25)
+
+	_, newAccount, err := account.CreateTx(20, 5, keys[1].PublicKey().Address(), ctx, keys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance := newAccount.Balance(); balance != 0 {
+		t.Fatalf("Expected the amount and the fee to be deducted. Expected balance: %d ; Returned: %d", 0, balance)
+	}
+
+	if _, _, err := account.CreateTx(20, 6, keys[1].PublicKey().Address(), ctx, keys[0]); err == nil {
+		t.Fatalf("Should have errored due to being unable to pay the fee")
+	}
+}
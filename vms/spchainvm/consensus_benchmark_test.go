@@ -86,7 +86,7 @@ func ConsensusLeader(numBlocks, numTxsPerBlock int, b *testing.B) {
 					Context:    ctx,
 					Validators: vdrs,
 					Beacons:    beacons,
-					Alpha:      (beacons.Len() + 1) / 2,
+					Alpha:      0.5,
 					Sender:     &sender,
 				},
 				Blocked: blocked,
@@ -104,7 +104,7 @@ func ConsensusLeader(numBlocks, numTxsPerBlock int, b *testing.B) {
 
 		// Asynchronously passes messages from the network to the consensus engine
 		handler := &handler.Handler{}
-		handler.Initialize(&engine, msgChan, 1000)
+		handler.Initialize(&engine, vdrs, msgChan, 1000, 1000, "", prometheus.NewRegistry())
 
 		// Allow incoming messages to be routed to the new chain
 		router.AddChain(handler)
@@ -217,7 +217,7 @@ func ConsensusFollower(numBlocks, numTxsPerBlock int, b *testing.B) {
 					Context:    ctx,
 					Validators: vdrs,
 					Beacons:    beacons,
-					Alpha:      (beacons.Len() + 1) / 2,
+					Alpha:      0.5,
 					Sender:     &sender,
 				},
 				Blocked: blocked,
@@ -235,7 +235,7 @@ func ConsensusFollower(numBlocks, numTxsPerBlock int, b *testing.B) {
 
 		// Asynchronously passes messages from the network to the consensus engine
 		handler := &handler.Handler{}
-		handler.Initialize(&engine, msgChan, 1000)
+		handler.Initialize(&engine, vdrs, msgChan, 1000, 1000, "", prometheus.NewRegistry())
 
 		// Allow incoming messages to be routed to the new chain
 		router.AddChain(handler)
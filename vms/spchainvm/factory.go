@@ -13,7 +13,9 @@ var (
 )
 
 // Factory ...
-type Factory struct{}
+type Factory struct{ TxFee uint64 }
 
 // New ...
-func (f *Factory) New() interface{} { return &VM{} }
+func (f *Factory) New() interface{} {
+	return &VM{TxFee: f.TxFee} // Use the tx fee from the config
+}
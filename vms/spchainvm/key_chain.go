@@ -69,8 +69,8 @@ func (kc *KeyChain) Get(id ids.ShortID) (*crypto.PrivateKeySECP256K1R, bool) {
 // Addresses returns a list of addresses this keychain manages
 func (kc *KeyChain) Addresses() ids.ShortSet { return kc.Addrs }
 
-// Spend attempts to create a new transaction
-func (kc *KeyChain) Spend(account Account, amount uint64, destination ids.ShortID) (*Tx, Account, error) {
+// Spend attempts to create a new transaction that burns [txFee]
+func (kc *KeyChain) Spend(account Account, amount, txFee uint64, destination ids.ShortID) (*Tx, Account, error) {
 	key, exists := kc.Get(account.ID())
 	if !exists {
 		return nil, Account{}, errUnknownAccount
@@ -78,7 +78,7 @@ func (kc *KeyChain) Spend(account Account, amount uint64, destination ids.ShortI
 	ctx := snow.DefaultContextTest()
 	ctx.NetworkID = kc.networkID
 	ctx.ChainID = kc.chainID
-	return account.CreateTx(amount, destination, ctx, key)
+	return account.CreateTx(amount, txFee, destination, ctx, key)
 }
 
 // PrefixedString returns a string representation of this keychain with each
@@ -195,7 +195,7 @@ func (lb *LiveBlock) VerifyState() error {
 	for _, tx := range lb.block.txs {
 		from := tx.key(lb.vm.ctx, &lb.vm.factory).Address()
 		fromAccount := lb.vm.GetAccount(lb.db, from)
-		newFromAccount, err := fromAccount.send(tx, lb.vm.ctx, &lb.vm.factory)
+		newFromAccount, err := fromAccount.send(tx, lb.vm.TxFee, lb.vm.ctx, &lb.vm.factory)
 		if err != nil {
 			lb.validity = err
 			break
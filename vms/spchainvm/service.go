@@ -40,6 +40,22 @@ func (service *Service) IssueTx(_ *http.Request, args *IssueTxArgs, reply *Issue
 	return nil
 }
 
+// GetTxFeeArgs is the arguments for calling GetTxFee
+type GetTxFeeArgs struct{}
+
+// GetTxFeeReply is the reply from calling GetTxFee
+// [TxFee] is the amount the sender of every transaction burns, in addition to
+// the amount sent.
+type GetTxFeeReply struct {
+	TxFee json.Uint64 `json:"txFee"`
+}
+
+// GetTxFee gets the transaction fee
+func (service *Service) GetTxFee(_ *http.Request, _ *GetTxFeeArgs, reply *GetTxFeeReply) error {
+	reply.TxFee = json.Uint64(service.vm.TxFee)
+	return nil
+}
+
 // GetAccountArgs is the arguments for calling GetAccount
 // [Address] is the string repr. of the address we want to know the nonce and balance of
 type GetAccountArgs struct {
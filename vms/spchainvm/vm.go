@@ -63,6 +63,9 @@ type VM struct {
 	currentBlocks map[[32]byte]*LiveBlock
 
 	onAccept func(ids.ID)
+
+	// The transaction fee, which the sender pays. The fee is burned.
+	TxFee uint64
 }
 
 /*
@@ -103,7 +103,7 @@ func TestPayments(t *testing.T) {
 
 	account := vm.GetAccount(vm.baseDB, keys[0].PublicKey().Address())
 
-	tx, _, err := account.CreateTx(200, keys[1].PublicKey().Address(), ctx, keys[0])
+	tx, _, err := account.CreateTx(200, 0, keys[1].PublicKey().Address(), ctx, keys[0])
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -4,7 +4,10 @@
 package spdagvm
 
 import (
+	"math"
+
 	"github.com/ava-labs/gecko/cache"
+	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/utils/wrappers"
@@ -16,6 +19,7 @@ const (
 	txStatusID
 	fundsID
 	dbInitializedID
+	fundsIndexID
 )
 
 var (
@@ -75,15 +79,45 @@ func (s *prefixedState) SetDBInitialized(status choices.Status) error {
 	return s.state.SetStatus(dbInitialized, status)
 }
 
-// Funds returns the IDs of unspent UTXOs that reference address [addr]
+// Funds returns the IDs of unspent UTXOs that reference address [addr].
+// Returns database.ErrNotFound if there are none.
 func (s *prefixedState) Funds(addr ids.ID) ([]ids.ID, error) {
-	return s.state.IDs(s.uniqueID(addr, fundsID, s.funds))
+	utxoIDs, err := s.FundsPage(addr, ids.ID{}, math.MaxInt32)
+	if err == nil && len(utxoIDs) == 0 {
+		err = database.ErrNotFound
+	}
+	return utxoIDs, err
 }
 
-// SetFunds saves the mapping from address [addr] to the IDs of unspent UTXOs
-// that reference [addr]
-func (s *prefixedState) SetFunds(addr ids.ID, idSlice []ids.ID) error {
-	return s.state.SetIDs(s.uniqueID(addr, fundsID, s.funds), idSlice)
+// FundsPage returns up to [limit] IDs of unspent UTXOs that reference address
+// [addr]. The IDs are sorted, and start after [start]. If [start] is empty,
+// they start with the first ID.
+func (s *prefixedState) FundsPage(addr, start ids.ID, limit int) ([]ids.ID, error) {
+	if err := s.migrateFunds(addr); err != nil {
+		return nil, err
+	}
+	return s.state.IDsPage(s.uniqueID(addr, fundsIndexID, s.funds), start, limit)
+}
+
+// migrateFunds moves the IDs of the unspent UTXOs that reference address
+// [addr] out of the list they were kept in before each of them had its own key
+func (s *prefixedState) migrateFunds(addr ids.ID) error {
+	legacyID := addr.Prefix(fundsID)
+	utxoIDs, err := s.state.IDs(legacyID)
+	if err == database.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	indexID := s.uniqueID(addr, fundsIndexID, s.funds)
+	for _, utxoID := range utxoIDs {
+		if err := s.state.AddID(indexID, utxoID); err != nil {
+			return err
+		}
+	}
+	return s.state.SetIDs(legacyID, nil)
 }
 
 // Make [id] unique by prefixing [prefix] to it
@@ -126,12 +160,10 @@ func (s *prefixedState) SpendUTXO(utxoID ids.ID) error {
 func (s *prefixedState) removeUTXO(addrs []ids.ShortID, utxoID ids.ID) error {
 	for _, addr := range addrs {
 		addrID := addr.LongID()
-		utxos := ids.Set{} // IDs of unspent UTXOs referencing [addr]
-		if funds, err := s.Funds(addrID); err == nil {
-			utxos.Add(funds...)
+		if err := s.migrateFunds(addrID); err != nil {
+			return err
 		}
-		utxos.Remove(utxoID)                                     // Remove [utxoID] from this set
-		if err := s.SetFunds(addrID, utxos.List()); err != nil { // Persist
+		if err := s.state.RemoveID(s.uniqueID(addrID, fundsIndexID, s.funds), utxoID); err != nil {
 			return err
 		}
 	}
@@ -162,16 +194,10 @@ func (s *prefixedState) FundUTXO(utxo *UTXO) error {
 func (s *prefixedState) addUTXO(addrs []ids.ShortID, utxoID ids.ID) error {
 	for _, addr := range addrs {
 		addrID := addr.LongID()
-		utxos := ids.Set{}
-		// Get the set of UTXO IDs such that [addr] is referenced in
-		// the UTXO
-		if funds, err := s.Funds(addrID); err == nil {
-			utxos.Add(funds...)
+		if err := s.migrateFunds(addrID); err != nil {
+			return err
 		}
-		// Add [utxoID] to that set
-		utxos.Add(utxoID)
-		// Persist the new set
-		if err := s.SetFunds(addrID, utxos.List()); err != nil {
+		if err := s.state.AddID(s.uniqueID(addrID, fundsIndexID, s.funds), utxoID); err != nil {
 			return err
 		}
 	}
@@ -10,12 +10,18 @@ import (
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/json"
 )
 
 var (
 	errNilID = errors.New("nil ID is not valid")
 )
 
+const (
+	// maxUTXOsToFetch is the most UTXOs that are returned in one page
+	maxUTXOsToFetch = 1024
+)
+
 // Service defines the API services exposed by the ava vm
 type Service struct{ vm *VM }
 
@@ -43,6 +49,23 @@ func (service *Service) IssueTx(r *http.Request, args *IssueTxArgs, reply *Issue
 	return nil
 }
 
+// GetTxFeeArgs are arguments for GetTxFee
+type GetTxFeeArgs struct{}
+
+// GetTxFeeReply is the reply from GetTxFee
+type GetTxFeeReply struct {
+	// Amount every transaction burns, in addition to the amount it sends
+	TxFee json.Uint64 `json:"txFee"`
+}
+
+// GetTxFee returns the transaction fee
+func (service *Service) GetTxFee(r *http.Request, args *GetTxFeeArgs, reply *GetTxFeeReply) error {
+	service.vm.ctx.Log.Verbo("GetTxFee called")
+
+	reply.TxFee = json.Uint64(service.vm.TxFee)
+	return nil
+}
+
 // GetTxStatusArgs are arguments for GetTxStatus
 type GetTxStatusArgs struct {
 	TxID ids.ID `json:"txID"`
@@ -71,32 +94,61 @@ func (service *Service) GetTxStatus(r *http.Request, args *GetTxStatusArgs, repl
 	return nil
 }
 
+// Index is a position in the UTXOs of a list of addresses
+type Index struct {
+	Address ids.ShortID `json:"address"`
+	UTXO    ids.ID      `json:"utxo"`
+}
+
 // GetUTXOsArgs are arguments for GetUTXOs
 type GetUTXOsArgs struct {
 	Addresses []ids.ShortID `json:"addresses"`
+
+	// The maximum number of UTXOs to return. If 0, or more than
+	// maxUTXOsToFetch, maxUTXOsToFetch UTXOs are returned.
+	Limit json.Uint32 `json:"limit"`
+
+	// The UTXOs are returned starting after this index. If empty, they start
+	// with the first UTXO of the first address.
+	StartIndex Index `json:"startIndex"`
 }
 
 // GetUTXOsReply is the reply from GetUTXOs
 type GetUTXOsReply struct {
+	NumFetched json.Uint64 `json:"numFetched"`
+
 	// Each element is the string repr. of an unspent UTXO that
 	// references an address in the arguments
 	UTXOs []formatting.CB58 `json:"utxos"`
+
+	// The index to pass in to get the next page. If fewer than the limit UTXOs
+	// were returned, there are no more UTXOs.
+	EndIndex Index `json:"endIndex"`
 }
 
-// GetUTXOs returns the UTXOs such that at least one address in [args.Addresses]
-// is referenced in the UTXO.
+// GetUTXOs returns a page of the UTXOs such that at least one address in
+// [args.Addresses] is referenced in the UTXO.
 func (service *Service) GetUTXOs(r *http.Request, args *GetUTXOsArgs, reply *GetUTXOsReply) error {
 	service.vm.ctx.Log.Verbo("GetUTXOs called with %s", args.Addresses)
 
+	addrs := []ids.ShortID{}
 	addrSet := ids.ShortSet{}
 	for _, addr := range args.Addresses {
 		if addr.IsZero() {
 			return errNilID
 		}
+		if !addrSet.Contains(addr) {
+			addrSet.Add(addr)
+			addrs = append(addrs, addr)
+		}
+	}
+
+	limit := int(args.Limit)
+	if limit <= 0 || limit > maxUTXOsToFetch {
+		limit = maxUTXOsToFetch
 	}
-	addrSet.Add(args.Addresses...)
 
-	utxos, err := service.vm.GetUTXOs(addrSet)
+	utxos, endAddr, endUTXO, err := service.vm.GetPaginatedUTXOs(addrs, args.StartIndex.Address, args.StartIndex.UTXO, limit)
 	if err != nil {
 		return err
 	}
@@ -105,5 +157,27 @@ func (service *Service) GetUTXOs(r *http.Request, args *GetUTXOsArgs, reply *Get
 	for _, utxo := range utxos {
 		reply.UTXOs = append(reply.UTXOs, formatting.CB58{Bytes: utxo.Bytes()})
 	}
+	reply.NumFetched = json.Uint64(len(utxos))
+	reply.EndIndex.Address = endAddr
+	reply.EndIndex.UTXO = endUTXO
 	return nil
 }
+
+// APITx is the JSON representation of a transaction
+type APITx struct {
+	ID        ids.ID      `json:"id"`
+	NetworkID json.Uint32 `json:"networkID"`
+	ChainID   ids.ID      `json:"chainID"`
+	Inputs    []APIInput  `json:"inputs"`
+	Outputs   []APIOutput `json:"outputs"`
+}
+
+// APIInput is the JSON representation of an input
+type APIInput struct {
+	// The transaction that produced the UTXO this input consumes, and the
+	// UTXO's index in that transaction
+	TxID        ids.ID      `json:"txID"`
+	OutputIndex json.Uint32 `json:"outputIndex"`
+
+	Amount json.Uint64 `json:"amount"`
+}
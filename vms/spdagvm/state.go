@@ -205,3 +205,48 @@ func (s *state) SetIDs(id ids.ID, idSlice []ids.ID) error {
 	}
 	return s.vm.db.Put(id.Bytes(), p.Bytes)
 }
+
+// AddID adds [val] to the set of IDs kept under [id]. Each ID of the set is
+// kept under its own key, so the set can be paged through without loading all
+// of it.
+func (s *state) AddID(id ids.ID, val ids.ID) error {
+	return s.vm.db.Put(setKey(id, val), nil)
+}
+
+// RemoveID removes [val] from the set of IDs kept under [id]
+func (s *state) RemoveID(id ids.ID, val ids.ID) error {
+	return s.vm.db.Delete(setKey(id, val))
+}
+
+// IDsPage returns up to [limit] IDs of the set kept under [id], in order. The
+// IDs start after [start], or with the first ID of the set if [start] is
+// empty.
+func (s *state) IDsPage(id ids.ID, start ids.ID, limit int) ([]ids.ID, error) {
+	prefix := id.Bytes()
+	startKey := prefix
+	if !start.IsZero() {
+		startKey = setKey(id, start)
+	}
+
+	it := s.vm.db.NewIteratorWithStartAndPrefix(startKey, prefix)
+	defer it.Release()
+
+	idSlice := []ids.ID(nil)
+	for len(idSlice) < limit && it.Next() {
+		val, err := ids.ToID(it.Key()[len(prefix):])
+		if err != nil {
+			return nil, err
+		}
+		if val.Equals(start) {
+			continue
+		}
+		idSlice = append(idSlice, val)
+	}
+	return idSlice, it.Error()
+}
+
+func setKey(id ids.ID, val ids.ID) []byte {
+	key := make([]byte, 0, 2*hashing.HashLen)
+	key = append(key, id.Bytes()...)
+	return append(key, val.Bytes()...)
+}
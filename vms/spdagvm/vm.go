@@ -41,6 +41,8 @@ var (
 	errAsset           = errors.New("assetID must be blank")
 	errAmountOverflow  = errors.New("the amount of this transaction plus the transaction fee overflows")
 	errUnsupportedFXs  = errors.New("unsupported feature extensions")
+
+	errUnknownStartAddress = errors.New("the start index's address isn't one of the addresses")
 )
 
 // VM implements the avalanche.DAGVM interface
@@ -245,21 +247,36 @@ func (vm *VM) GetBalance(address, assetID string) (uint64, error) {
 		return 0, err
 	}
 
-	addrSet := ids.ShortSet{addr.Key(): true} // Note this set contains only [addr]
-	utxos, err := vm.GetUTXOs(addrSet)        // The UTXOs that reference [addr]
-	if err != nil {
-		return 0, err
-	}
-
-	// Go through each UTXO that references [addr].
+	// Go through each UTXO that references [addr], a page at a time so that
+	// they aren't all in memory at once.
 	// If the private key that controls [addr] may spend the UTXO,
 	// add its amount to [balance]
 	balance := uint64(0)
 	currentTime := vm.clock.Unix()
+	addrs := []ids.ShortID{addr}
+	startUTXO := ids.ID{}
+	for {
+		utxos, _, endUTXO, err := vm.GetPaginatedUTXOs(addrs, addr, startUTXO, maxUTXOsToFetch)
+		if err != nil {
+			return 0, err
+		}
+		if balance, err = vm.spendableBalance(addr, utxos, currentTime, balance); err != nil {
+			return 0, err
+		}
+		if len(utxos) < maxUTXOsToFetch {
+			return balance, nil
+		}
+		startUTXO = endUTXO
+	}
+}
+
+// spendableBalance returns [balance] plus the amount of the UTXOs in [utxos]
+// that the private key that controls [addr] may spend at time [currentTime]
+func (vm *VM) spendableBalance(addr ids.ShortID, utxos []*UTXO, currentTime, balance uint64) (uint64, error) {
 	for _, utxo := range utxos {
 		switch out := utxo.Out().(type) {
 		case *OutputPayment:
-			// Because [addrSet] has size 1, we know [addr] is
+			// Because [utxos] all reference [addr], we know [addr] is
 			// referenced in [out]
 			if currentTime > out.Locktime() && out.Threshold() == 1 {
 				amount, err := math.Add64(balance, out.Amount())
@@ -438,6 +455,140 @@ func (vm *VM) GetUTXOs(addrs ids.ShortSet) ([]*UTXO, error) {
 	return utxos, nil
 }
 
+// GetPaginatedUTXOs returns up to [limit] UTXOs such that at least one address
+// in [addrs] is referenced in the UTXO. The UTXOs of each address are returned
+// in the order of their IDs, and the addresses are paged through in the order
+// of [addrs]. Paging starts after the UTXO [startUTXO] of the address
+// [startAddr], or at the first UTXO of the first address if [startAddr] is
+// empty. Returns the address and UTXO the next page should start after.
+//
+// A UTXO that references several addresses in [addrs] is only returned once
+// per page.
+func (vm *VM) GetPaginatedUTXOs(addrs []ids.ShortID, startAddr ids.ShortID, startUTXO ids.ID, limit int) ([]*UTXO, ids.ShortID, ids.ID, error) {
+	first := 0
+	if !startAddr.IsZero() {
+		for first < len(addrs) && !addrs[first].Equals(startAddr) {
+			first++
+		}
+		if first == len(addrs) {
+			return nil, ids.ShortID{}, ids.ID{}, errUnknownStartAddress
+		}
+	}
+
+	utxos := []*UTXO{}
+	seen := ids.Set{}
+	lastAddr, lastUTXO := startAddr, startUTXO
+	for _, addr := range addrs[first:] {
+		start := ids.ID{}
+		if addr.Equals(startAddr) {
+			start = startUTXO
+		}
+		for len(utxos) < limit {
+			utxoIDs, err := vm.state.FundsPage(addr.LongID(), start, limit-len(utxos))
+			if err != nil {
+				return nil, ids.ShortID{}, ids.ID{}, err
+			}
+			if len(utxoIDs) == 0 {
+				break
+			}
+			for _, utxoID := range utxoIDs {
+				start = utxoID
+				lastAddr, lastUTXO = addr, utxoID
+				if seen.Contains(utxoID) {
+					continue
+				}
+				seen.Add(utxoID)
+
+				utxo, err := vm.state.UTXO(utxoID)
+				if err != nil {
+					return nil, ids.ShortID{}, ids.ID{}, err
+				}
+				utxos = append(utxos, utxo)
+			}
+		}
+		if len(utxos) == limit {
+			break
+		}
+	}
+	return utxos, lastAddr, lastUTXO, nil
+}
+
+// DecodeContainer implements the events.Decoder interface. The tx is described
+// by an APITx, and the addresses are the ones that can spend the UTXOs it
+// creates.
+func (vm *VM) DecodeContainer(container []byte) (interface{}, []string, error) {
+	c := Codec{}
+	tx, err := c.UnmarshalTx(container)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	apiTx := &APITx{
+		ID:        tx.ID(),
+		NetworkID: jsoncodec.Uint32(tx.networkID),
+		ChainID:   tx.chainID,
+		Inputs:    []APIInput{},
+		Outputs:   []APIOutput{},
+	}
+	for _, in := range tx.ins {
+		switch in := in.(type) {
+		case *InputPayment:
+			apiTx.Inputs = append(apiTx.Inputs, APIInput{
+				TxID:        in.sourceID,
+				OutputIndex: jsoncodec.Uint32(in.sourceIndex),
+				Amount:      jsoncodec.Uint64(in.amount),
+			})
+		default:
+			return nil, nil, errUnknownInputType
+		}
+	}
+
+	addrs := ids.ShortSet{}
+	for _, out := range tx.outs {
+		switch out := out.(type) {
+		case *OutputPayment:
+			apiTx.Outputs = append(apiTx.Outputs, APIOutput{
+				Amount:    jsoncodec.Uint64(out.amount),
+				Locktime:  jsoncodec.Uint64(out.locktime),
+				Threshold: jsoncodec.Uint32(out.threshold),
+				Addresses: out.addresses,
+			})
+			addrs.Add(out.addresses...)
+		case *OutputTakeOrLeave:
+			apiTx.Outputs = append(apiTx.Outputs, APIOutput{
+				Amount:     jsoncodec.Uint64(out.amount),
+				Locktime:   jsoncodec.Uint64(out.locktime1),
+				Threshold:  jsoncodec.Uint32(out.threshold1),
+				Addresses:  out.addresses1,
+				Locktime2:  jsoncodec.Uint64(out.locktime2),
+				Threshold2: jsoncodec.Uint32(out.threshold2),
+				Addresses2: out.addresses2,
+			})
+			addrs.Add(out.addresses1...)
+			addrs.Add(out.addresses2...)
+		default:
+			return nil, nil, errUnknownOutputType
+		}
+	}
+
+	addrList := addrs.List()
+	ids.SortShortIDs(addrList)
+	addrStrs := []string{}
+	for _, addr := range addrList {
+		addrStrs = append(addrStrs, addr.String())
+	}
+	return apiTx, addrStrs, nil
+}
+
+// NormalizeAddress implements the events.Decoder interface
+func (vm *VM) NormalizeAddress(addr string) (string, error) {
+	id, err := ids.ShortFromString(addr)
+	if err != nil {
+		return "", err
+	}
+	return id.String(), nil
+}
+
 /*
  ******************************************************************************
  ********************************** Timer API *********************************
@@ -7,6 +7,7 @@ import (
 	"math"
 	"testing"
 
+	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/database/memdb"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
@@ -808,3 +809,190 @@ func TestTxOutputOverflow(t *testing.T) {
 	}
 	ctx.Lock.Unlock()
 }
+
+func TestDecodeContainer(t *testing.T) {
+	genesisTx := GenesisTx(defaultInitBalances)
+
+	builder := Builder{
+		NetworkID: 0,
+		ChainID:   avaChainID,
+	}
+	addr1 := keys[1].PublicKey().Address()
+	addr2 := keys[2].PublicKey().Address()
+	tx, err := builder.NewTx(
+		/*ins=*/ []Input{
+			builder.NewInputPayment(
+				/*txID=*/ genesisTx.ID(),
+				/*txIndex=*/ 0,
+				/*amount=*/ 5*units.Ava,
+				/*sigs=*/ []*Sig{builder.NewSig(0 /*=index*/)},
+			),
+		},
+		/*outs=*/ []Output{
+			builder.NewOutputPayment(
+				/*amount=*/ 3*units.Ava,
+				/*locktime=*/ 0,
+				/*threshold=*/ 1,
+				/*addresses=*/ []ids.ShortID{addr1},
+			),
+			builder.NewOutputTakeOrLeave(
+				/*amount=*/ 2*units.Ava,
+				/*locktime1=*/ 0,
+				/*threshold1=*/ 1,
+				/*addresses1=*/ []ids.ShortID{addr1},
+				/*locktime2=*/ 1,
+				/*threshold2=*/ 1,
+				/*addresses2=*/ []ids.ShortID{addr2},
+			),
+		},
+		/*signers=*/ []*InputSigner{
+			&InputSigner{Keys: []*crypto.PrivateKeySECP256K1R{
+				keys[0],
+			}},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vm := &VM{}
+	body, addrs, err := vm.DecodeContainer(tx.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	apiTx, ok := body.(*APITx)
+	if !ok {
+		t.Fatalf("Should have described the tx")
+	}
+	if !apiTx.ID.Equals(tx.ID()) {
+		t.Fatalf("Wrong tx ID. Expected: %s ; Returned: %s", tx.ID(), apiTx.ID)
+	}
+	if len(apiTx.Inputs) != 1 || !apiTx.Inputs[0].TxID.Equals(genesisTx.ID()) {
+		t.Fatalf("Wrong inputs: %v", apiTx.Inputs)
+	}
+	if len(apiTx.Outputs) != 2 {
+		t.Fatalf("Wrong number of outputs. Expected: 2 ; Returned: %d", len(apiTx.Outputs))
+	}
+
+	expectedAddrs := ids.ShortSet{}
+	expectedAddrs.Add(addr1, addr2)
+	if len(addrs) != expectedAddrs.Len() {
+		t.Fatalf("Wrong addresses. Expected: %s ; Returned: %v", expectedAddrs, addrs)
+	}
+	for _, addrStr := range addrs {
+		addr, err := ids.ShortFromString(addrStr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !expectedAddrs.Contains(addr) {
+			t.Fatalf("Wrong addresses. Expected: %s ; Returned: %v", expectedAddrs, addrs)
+		}
+	}
+
+	if _, _, err := vm.DecodeContainer([]byte{1, 2, 3}); err == nil {
+		t.Fatalf("Should have failed to decode an invalid tx")
+	}
+
+	if normalized, err := vm.NormalizeAddress(addr1.String()); err != nil {
+		t.Fatal(err)
+	} else if normalized != addr1.String() {
+		t.Fatalf("Wrong address. Expected: %s ; Returned: %s", addr1, normalized)
+	}
+	if _, err := vm.NormalizeAddress("X-" + addr1.String()); err == nil {
+		t.Fatalf("Shouldn't have accepted a chain prefix")
+	}
+}
+
+func TestGetPaginatedUTXOs(t *testing.T) {
+	genesisTx := GenesisTx(defaultInitBalances)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm := &VM{}
+	if err := vm.Initialize(ctx, memdb.New(), genesisTx.Bytes(), make(chan common.Message, 1), nil); err != nil {
+		t.Fatal(err)
+	}
+	defer vm.Shutdown()
+
+	// Each address is referenced by one genesis UTXO
+	addrs := []ids.ShortID{}
+	for _, key := range keys {
+		addrs = append(addrs, key.PublicKey().Address())
+	}
+
+	utxos, endAddr, endUTXO, err := vm.GetPaginatedUTXOs(addrs, ids.ShortID{}, ids.ID{}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxos) != 2 {
+		t.Fatalf("Wrong number of UTXOs. Expected: 2 ; Returned: %d", len(utxos))
+	}
+	if !endAddr.Equals(addrs[1]) || !endUTXO.Equals(utxos[1].ID()) {
+		t.Fatalf("The page should end at the second address's UTXO")
+	}
+
+	utxos, endAddr, endUTXO, err = vm.GetPaginatedUTXOs(addrs, endAddr, endUTXO, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxos) != 1 {
+		t.Fatalf("Wrong number of UTXOs. Expected: 1 ; Returned: %d", len(utxos))
+	}
+	if !endAddr.Equals(addrs[2]) || !endUTXO.Equals(utxos[0].ID()) {
+		t.Fatalf("The page should end at the third address's UTXO")
+	}
+
+	utxos, _, _, err = vm.GetPaginatedUTXOs(addrs, endAddr, endUTXO, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxos) != 0 {
+		t.Fatalf("Should have returned an empty last page")
+	}
+
+	if _, _, _, err := vm.GetPaginatedUTXOs(addrs[:1], addrs[2], ids.ID{}, 2); err != errUnknownStartAddress {
+		t.Fatalf("Should have errored with %s but errored with %v", errUnknownStartAddress, err)
+	}
+}
+
+func TestFundsMigration(t *testing.T) {
+	genesisTx := GenesisTx(defaultInitBalances)
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm := &VM{}
+	if err := vm.Initialize(ctx, memdb.New(), genesisTx.Bytes(), make(chan common.Message, 1), nil); err != nil {
+		t.Fatal(err)
+	}
+	defer vm.Shutdown()
+
+	addr := ids.NewID([32]byte{1})
+	utxoIDs := []ids.ID{ids.NewID([32]byte{4}), ids.NewID([32]byte{2}), ids.NewID([32]byte{3})}
+	// UTXOs indexed as a single unsorted list must still be paged through in
+	// order
+	if err := vm.state.state.SetIDs(addr.Prefix(fundsID), utxoIDs); err != nil {
+		t.Fatal(err)
+	}
+
+	page, err := vm.state.FundsPage(addr, ids.ID{}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 2 || !page[0].Equals(utxoIDs[1]) || !page[1].Equals(utxoIDs[2]) {
+		t.Fatalf("Wrong page. Expected: [%s %s] ; Returned: %s", utxoIDs[1], utxoIDs[2], page)
+	}
+
+	page, err = vm.state.FundsPage(addr, page[1], 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 1 || !page[0].Equals(utxoIDs[0]) {
+		t.Fatalf("Wrong page. Expected: [%s] ; Returned: %s", utxoIDs[0], page)
+	}
+
+	if _, err := vm.state.state.IDs(addr.Prefix(fundsID)); err != database.ErrNotFound {
+		t.Fatalf("The list of UTXOs should have been migrated")
+	}
+}
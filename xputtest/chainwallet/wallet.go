@@ -23,6 +23,7 @@ const (
 type Wallet struct {
 	networkID  uint32
 	chainID    ids.ID
+	txFee      uint64
 	keyChain   *spchainvm.KeyChain            // Mapping from public address to the SigningKeys
 	accountSet map[[20]byte]spchainvm.Account // Mapping from addresses to accounts
 	balance    uint64
@@ -31,10 +32,11 @@ type Wallet struct {
 }
 
 // NewWallet ...
-func NewWallet(networkID uint32, chainID ids.ID) Wallet {
+func NewWallet(networkID uint32, chainID ids.ID, txFee uint64) Wallet {
 	return Wallet{
 		networkID:  networkID,
 		chainID:    chainID,
+		txFee:      txFee,
 		keyChain:   spchainvm.NewKeyChain(networkID, chainID),
 		accountSet: make(map[[20]byte]spchainvm.Account),
 	}
@@ -74,7 +76,7 @@ func (w *Wallet) GenerateTxs() {
 			accountID := account.ID()
 			if key, exists := w.keyChain.Get(accountID); exists {
 				amount := uint64(1)
-				if tx, sendAccount, err := account.CreateTx(amount, accountID, ctx, key); err == nil {
+				if tx, sendAccount, err := account.CreateTx(amount, w.txFee, accountID, ctx, key); err == nil {
 					newAccount, err := sendAccount.Receive(tx, ctx)
 					if err != nil {
 						panic("shouldn't error")
@@ -103,7 +105,7 @@ func (w *Wallet) Send() *spchainvm.Tx {
 		accountID := account.ID()
 		if key, exists := w.keyChain.Get(accountID); exists {
 			amount := uint64(1)
-			if tx, sendAccount, err := account.CreateTx(amount, accountID, ctx, key); err == nil {
+			if tx, sendAccount, err := account.CreateTx(amount, w.txFee, accountID, ctx, key); err == nil {
 				newAccount, err := sendAccount.Receive(tx, ctx)
 				if err == nil {
 					w.accountSet[accountID.Key()] = newAccount
@@ -1,6 +1,9 @@
 // (c) 2019-2020, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
+//go:build cgo
+// +build cgo
+
 package main
 
 // #include "salticidae/network.h"
@@ -151,9 +154,10 @@ func main() {
 }
 
 func (t *tp) benchmarkAvalanche() {
-	platformGenesisBytes := genesis.Genesis(t.networkID)
+	platformGenesisBytes, err := genesis.Genesis(t.networkID)
+	t.log.AssertNoError(err)
 	genesisState := &platformvm.Genesis{}
-	err := platformvm.Codec.Unmarshal(platformGenesisBytes, genesisState)
+	err = platformvm.Codec.Unmarshal(platformGenesisBytes, genesisState)
 	t.log.AssertNoError(err)
 	t.log.AssertNoError(genesisState.Initialize())
 
@@ -241,9 +245,10 @@ func (t *tp) IssueAvalanche(chainID ids.ID, wallet dagwallet.Wallet) {
 }
 
 func (t *tp) benchmarkSnowman() {
-	platformGenesisBytes := genesis.Genesis(t.networkID)
+	platformGenesisBytes, err := genesis.Genesis(t.networkID)
+	t.log.AssertNoError(err)
 	genesisState := &platformvm.Genesis{}
-	err := platformvm.Codec.Unmarshal(platformGenesisBytes, genesisState)
+	err = platformvm.Codec.Unmarshal(platformGenesisBytes, genesisState)
 	t.log.AssertNoError(err)
 	t.log.AssertNoError(genesisState.Initialize())
 
@@ -253,7 +258,7 @@ func (t *tp) benchmarkSnowman() {
 	}
 	genesisBytes := spchainChain.GenesisData
 
-	wallet := chainwallet.NewWallet(t.networkID, spchainChain.ID())
+	wallet := chainwallet.NewWallet(t.networkID, spchainChain.ID(), config.AvaTxFee)
 
 	codec := spchainvm.Codec{}
 	accounts, err := codec.UnmarshalGenesis(genesisBytes)